@@ -0,0 +1,43 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package v2 defines the politeiad v2 gRPC service. The service mirrors the
+// politeiad v2 JSON API routes that are used to submit and retrieve records.
+// Record file payloads are streamed as raw bytes in chunks instead of being
+// base64 encoded inside of a JSON body.
+//
+// The service is defined in v2.proto. The messages and the client and server
+// stubs in v2.pb.go are generated from it and use the standard protobuf
+// codec, so clients can be generated for any language that gRPC supports.
+package v2
+
+//go:generate protoc --go_out=plugins=grpc,paths=source_relative:. v2.proto
+
+const (
+	// ServiceName is the fully qualified name of the gRPC service.
+	ServiceName = "politeiad.v2.Politeiad"
+
+	// ChunkSize is the maximum size in bytes of the file payload data
+	// that is included in a single FileChunk message.
+	ChunkSize = 1024 * 1024
+
+	// PayloadMax is the maximum combined size in bytes of the file
+	// payloads that can be sent in a single RecordNew or RecordEdit
+	// call.
+	PayloadMax = 32 * ChunkSize
+
+	// Methods
+	MethodRecordNew        = "RecordNew"
+	MethodRecordEdit       = "RecordEdit"
+	MethodRecords          = "Records"
+	MethodRecordTimestamps = "RecordTimestamps"
+	MethodInventory        = "Inventory"
+	MethodPluginWrite      = "PluginWrite"
+	MethodPluginReads      = "PluginReads"
+)
+
+// FullMethod returns the full gRPC method name for the provided method.
+func FullMethod(method string) string {
+	return "/" + ServiceName + "/" + method
+}
@@ -0,0 +1,3383 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.25.0
+// 	protoc        (unknown)
+// source: v2.proto
+
+// The politeiad v2 gRPC service mirrors the politeiad v2 JSON API routes that
+// are used to submit and retrieve records. See the politeiad v2 API for the
+// details of each of the fields. Record file payloads are streamed as raw
+// bytes in chunks instead of being base64 encoded inside of a JSON body.
+
+package v2
+
+import (
+	context "context"
+	proto "github.com/golang/protobuf/proto"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// This is a compile-time assertion that a sufficiently up-to-date version
+// of the legacy proto package is being used.
+const _ = proto.ProtoPackageIsVersion4
+
+// RecordState represents the state of a record. The values match the
+// politeiad v2 API RecordStateT values.
+type RecordState int32
+
+const (
+	RecordState_RECORD_STATE_INVALID  RecordState = 0
+	RecordState_RECORD_STATE_UNVETTED RecordState = 1
+	RecordState_RECORD_STATE_VETTED   RecordState = 2
+)
+
+// Enum value maps for RecordState.
+var (
+	RecordState_name = map[int32]string{
+		0: "RECORD_STATE_INVALID",
+		1: "RECORD_STATE_UNVETTED",
+		2: "RECORD_STATE_VETTED",
+	}
+	RecordState_value = map[string]int32{
+		"RECORD_STATE_INVALID":  0,
+		"RECORD_STATE_UNVETTED": 1,
+		"RECORD_STATE_VETTED":   2,
+	}
+)
+
+func (x RecordState) Enum() *RecordState {
+	p := new(RecordState)
+	*p = x
+	return p
+}
+
+func (x RecordState) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RecordState) Descriptor() protoreflect.EnumDescriptor {
+	return file_v2_proto_enumTypes[0].Descriptor()
+}
+
+func (RecordState) Type() protoreflect.EnumType {
+	return &file_v2_proto_enumTypes[0]
+}
+
+func (x RecordState) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RecordState.Descriptor instead.
+func (RecordState) EnumDescriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{0}
+}
+
+// RecordStatus represents the status of a record. The values match the
+// politeiad v2 API RecordStatusT values.
+type RecordStatus int32
+
+const (
+	RecordStatus_RECORD_STATUS_INVALID    RecordStatus = 0
+	RecordStatus_RECORD_STATUS_UNREVIEWED RecordStatus = 1
+	RecordStatus_RECORD_STATUS_PUBLIC     RecordStatus = 2
+	RecordStatus_RECORD_STATUS_CENSORED   RecordStatus = 3
+	RecordStatus_RECORD_STATUS_ARCHIVED   RecordStatus = 4
+)
+
+// Enum value maps for RecordStatus.
+var (
+	RecordStatus_name = map[int32]string{
+		0: "RECORD_STATUS_INVALID",
+		1: "RECORD_STATUS_UNREVIEWED",
+		2: "RECORD_STATUS_PUBLIC",
+		3: "RECORD_STATUS_CENSORED",
+		4: "RECORD_STATUS_ARCHIVED",
+	}
+	RecordStatus_value = map[string]int32{
+		"RECORD_STATUS_INVALID":    0,
+		"RECORD_STATUS_UNREVIEWED": 1,
+		"RECORD_STATUS_PUBLIC":     2,
+		"RECORD_STATUS_CENSORED":   3,
+		"RECORD_STATUS_ARCHIVED":   4,
+	}
+)
+
+func (x RecordStatus) Enum() *RecordStatus {
+	p := new(RecordStatus)
+	*p = x
+	return p
+}
+
+func (x RecordStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (RecordStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_v2_proto_enumTypes[1].Descriptor()
+}
+
+func (RecordStatus) Type() protoreflect.EnumType {
+	return &file_v2_proto_enumTypes[1]
+}
+
+func (x RecordStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use RecordStatus.Descriptor instead.
+func (RecordStatus) EnumDescriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{1}
+}
+
+// MetadataStream describes a single metadata stream.
+type MetadataStream struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PluginId string `protobuf:"bytes,1,opt,name=plugin_id,json=pluginId,proto3" json:"plugin_id,omitempty"`  // Plugin identity
+	StreamId uint32 `protobuf:"varint,2,opt,name=stream_id,json=streamId,proto3" json:"stream_id,omitempty"` // Stream identity
+	Payload  string `protobuf:"bytes,3,opt,name=payload,proto3" json:"payload,omitempty"`                    // JSON encoded metadata
+}
+
+func (x *MetadataStream) Reset() {
+	*x = MetadataStream{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *MetadataStream) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MetadataStream) ProtoMessage() {}
+
+func (x *MetadataStream) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MetadataStream.ProtoReflect.Descriptor instead.
+func (*MetadataStream) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *MetadataStream) GetPluginId() string {
+	if x != nil {
+		return x.PluginId
+	}
+	return ""
+}
+
+func (x *MetadataStream) GetStreamId() uint32 {
+	if x != nil {
+		return x.StreamId
+	}
+	return 0
+}
+
+func (x *MetadataStream) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+// File represents a record file. The file payload is not included in the
+// messages that describe a file. It is streamed separately using FileChunk
+// messages.
+type File struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name    string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`       // Basename of the file
+	Mime    string `protobuf:"bytes,2,opt,name=mime,proto3" json:"mime,omitempty"`       // MIME type
+	Digest  string `protobuf:"bytes,3,opt,name=digest,proto3" json:"digest,omitempty"`   // SHA256 of the payload
+	Payload []byte `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"` // Raw file payload
+}
+
+func (x *File) Reset() {
+	*x = File{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *File) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*File) ProtoMessage() {}
+
+func (x *File) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use File.ProtoReflect.Descriptor instead.
+func (*File) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *File) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *File) GetMime() string {
+	if x != nil {
+		return x.Mime
+	}
+	return ""
+}
+
+func (x *File) GetDigest() string {
+	if x != nil {
+		return x.Digest
+	}
+	return ""
+}
+
+func (x *File) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+// FileChunk contains a chunk of a file payload. File is the index of the file
+// in the list of files that the chunk belongs to. The chunks of a file are
+// sent in order. A chunk must contain between 1 and ChunkSize bytes of data.
+type FileChunk struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	File uint32 `protobuf:"varint,1,opt,name=file,proto3" json:"file,omitempty"`
+	Data []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *FileChunk) Reset() {
+	*x = FileChunk{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *FileChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileChunk) ProtoMessage() {}
+
+func (x *FileChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileChunk.ProtoReflect.Descriptor instead.
+func (*FileChunk) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *FileChunk) GetFile() uint32 {
+	if x != nil {
+		return x.File
+	}
+	return 0
+}
+
+func (x *FileChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// CensorshipRecord contains cryptographic proof that a record was accepted
+// for review by the server.
+type CensorshipRecord struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Token     string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`         // Censorship token
+	Merkle    string `protobuf:"bytes,2,opt,name=merkle,proto3" json:"merkle,omitempty"`       // Merkle root of all files in the record
+	Signature string `protobuf:"bytes,3,opt,name=signature,proto3" json:"signature,omitempty"` // Server signature of the Merkle+Token
+}
+
+func (x *CensorshipRecord) Reset() {
+	*x = CensorshipRecord{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *CensorshipRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CensorshipRecord) ProtoMessage() {}
+
+func (x *CensorshipRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CensorshipRecord.ProtoReflect.Descriptor instead.
+func (*CensorshipRecord) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *CensorshipRecord) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *CensorshipRecord) GetMerkle() string {
+	if x != nil {
+		return x.Merkle
+	}
+	return ""
+}
+
+func (x *CensorshipRecord) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+// Record is a politeiad record. The file payloads are streamed separately
+// from the record. Files that were not requested are not included.
+type Record struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	State            RecordState       `protobuf:"varint,1,opt,name=state,proto3,enum=politeiad.v2.RecordState" json:"state,omitempty"`
+	Status           RecordStatus      `protobuf:"varint,2,opt,name=status,proto3,enum=politeiad.v2.RecordStatus" json:"status,omitempty"`
+	Version          uint32            `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`
+	Timestamp        int64             `protobuf:"varint,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Metadata         []*MetadataStream `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty"`
+	Files            []*File           `protobuf:"bytes,6,rep,name=files,proto3" json:"files,omitempty"`
+	CensorshipRecord *CensorshipRecord `protobuf:"bytes,7,opt,name=censorship_record,json=censorshipRecord,proto3" json:"censorship_record,omitempty"`
+}
+
+func (x *Record) Reset() {
+	*x = Record{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Record) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Record) ProtoMessage() {}
+
+func (x *Record) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Record.ProtoReflect.Descriptor instead.
+func (*Record) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Record) GetState() RecordState {
+	if x != nil {
+		return x.State
+	}
+	return RecordState_RECORD_STATE_INVALID
+}
+
+func (x *Record) GetStatus() RecordStatus {
+	if x != nil {
+		return x.Status
+	}
+	return RecordStatus_RECORD_STATUS_INVALID
+}
+
+func (x *Record) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Record) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *Record) GetMetadata() []*MetadataStream {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Record) GetFiles() []*File {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *Record) GetCensorshipRecord() *CensorshipRecord {
+	if x != nil {
+		return x.CensorshipRecord
+	}
+	return nil
+}
+
+// RecordNew creates a new record. The file payloads are not included. They
+// are sent in the FileChunk messages that follow the RecordNew message.
+type RecordNew struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Challenge string            `protobuf:"bytes,1,opt,name=challenge,proto3" json:"challenge,omitempty"` // Random challenge
+	Metadata  []*MetadataStream `protobuf:"bytes,2,rep,name=metadata,proto3" json:"metadata,omitempty"`
+	Files     []*File           `protobuf:"bytes,3,rep,name=files,proto3" json:"files,omitempty"`
+}
+
+func (x *RecordNew) Reset() {
+	*x = RecordNew{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RecordNew) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordNew) ProtoMessage() {}
+
+func (x *RecordNew) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordNew.ProtoReflect.Descriptor instead.
+func (*RecordNew) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RecordNew) GetChallenge() string {
+	if x != nil {
+		return x.Challenge
+	}
+	return ""
+}
+
+func (x *RecordNew) GetMetadata() []*MetadataStream {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *RecordNew) GetFiles() []*File {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+// RecordNewMsg is a message of the RecordNew client stream.
+type RecordNewMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RecordNew *RecordNew `protobuf:"bytes,1,opt,name=record_new,json=recordNew,proto3" json:"record_new,omitempty"`
+	Chunk     *FileChunk `protobuf:"bytes,2,opt,name=chunk,proto3" json:"chunk,omitempty"`
+}
+
+func (x *RecordNewMsg) Reset() {
+	*x = RecordNewMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RecordNewMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordNewMsg) ProtoMessage() {}
+
+func (x *RecordNewMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordNewMsg.ProtoReflect.Descriptor instead.
+func (*RecordNewMsg) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RecordNewMsg) GetRecordNew() *RecordNew {
+	if x != nil {
+		return x.RecordNew
+	}
+	return nil
+}
+
+func (x *RecordNewMsg) GetChunk() *FileChunk {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+// RecordNewReply is the reply to the RecordNew command. The record file
+// payloads are not included.
+type RecordNewReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Response string  `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"` // Challenge response
+	Record   *Record `protobuf:"bytes,2,opt,name=record,proto3" json:"record,omitempty"`
+}
+
+func (x *RecordNewReply) Reset() {
+	*x = RecordNewReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RecordNewReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordNewReply) ProtoMessage() {}
+
+func (x *RecordNewReply) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordNewReply.ProtoReflect.Descriptor instead.
+func (*RecordNewReply) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RecordNewReply) GetResponse() string {
+	if x != nil {
+		return x.Response
+	}
+	return ""
+}
+
+func (x *RecordNewReply) GetRecord() *Record {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+// RecordEdit edits an existing record. The file payloads of the added files
+// are not included. They are sent in the FileChunk messages that follow the
+// RecordEdit message.
+type RecordEdit struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Challenge   string            `protobuf:"bytes,1,opt,name=challenge,proto3" json:"challenge,omitempty"` // Random challenge
+	Token       string            `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`         // Censorship token
+	MdAppend    []*MetadataStream `protobuf:"bytes,3,rep,name=md_append,json=mdAppend,proto3" json:"md_append,omitempty"`
+	MdOverwrite []*MetadataStream `protobuf:"bytes,4,rep,name=md_overwrite,json=mdOverwrite,proto3" json:"md_overwrite,omitempty"`
+	FilesAdd    []*File           `protobuf:"bytes,5,rep,name=files_add,json=filesAdd,proto3" json:"files_add,omitempty"`
+	FilesDel    []string          `protobuf:"bytes,6,rep,name=files_del,json=filesDel,proto3" json:"files_del,omitempty"`
+}
+
+func (x *RecordEdit) Reset() {
+	*x = RecordEdit{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RecordEdit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordEdit) ProtoMessage() {}
+
+func (x *RecordEdit) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordEdit.ProtoReflect.Descriptor instead.
+func (*RecordEdit) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *RecordEdit) GetChallenge() string {
+	if x != nil {
+		return x.Challenge
+	}
+	return ""
+}
+
+func (x *RecordEdit) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *RecordEdit) GetMdAppend() []*MetadataStream {
+	if x != nil {
+		return x.MdAppend
+	}
+	return nil
+}
+
+func (x *RecordEdit) GetMdOverwrite() []*MetadataStream {
+	if x != nil {
+		return x.MdOverwrite
+	}
+	return nil
+}
+
+func (x *RecordEdit) GetFilesAdd() []*File {
+	if x != nil {
+		return x.FilesAdd
+	}
+	return nil
+}
+
+func (x *RecordEdit) GetFilesDel() []string {
+	if x != nil {
+		return x.FilesDel
+	}
+	return nil
+}
+
+// RecordEditMsg is a message of the RecordEdit client stream.
+type RecordEditMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RecordEdit *RecordEdit `protobuf:"bytes,1,opt,name=record_edit,json=recordEdit,proto3" json:"record_edit,omitempty"`
+	Chunk      *FileChunk  `protobuf:"bytes,2,opt,name=chunk,proto3" json:"chunk,omitempty"`
+}
+
+func (x *RecordEditMsg) Reset() {
+	*x = RecordEditMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RecordEditMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordEditMsg) ProtoMessage() {}
+
+func (x *RecordEditMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordEditMsg.ProtoReflect.Descriptor instead.
+func (*RecordEditMsg) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *RecordEditMsg) GetRecordEdit() *RecordEdit {
+	if x != nil {
+		return x.RecordEdit
+	}
+	return nil
+}
+
+func (x *RecordEditMsg) GetChunk() *FileChunk {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+// RecordEditReply is the reply to the RecordEdit command. The record file
+// payloads are not included.
+type RecordEditReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Response string  `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"` // Challenge response
+	Record   *Record `protobuf:"bytes,2,opt,name=record,proto3" json:"record,omitempty"`
+}
+
+func (x *RecordEditReply) Reset() {
+	*x = RecordEditReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RecordEditReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordEditReply) ProtoMessage() {}
+
+func (x *RecordEditReply) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordEditReply.ProtoReflect.Descriptor instead.
+func (*RecordEditReply) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RecordEditReply) GetResponse() string {
+	if x != nil {
+		return x.Response
+	}
+	return ""
+}
+
+func (x *RecordEditReply) GetRecord() *Record {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+// RecordRequest is used to request a record. If no version is provided the
+// most recent version is returned.
+type RecordRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Token        string   `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Version      uint32   `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"`
+	Filenames    []string `protobuf:"bytes,3,rep,name=filenames,proto3" json:"filenames,omitempty"`
+	OmitAllFiles bool     `protobuf:"varint,4,opt,name=omit_all_files,json=omitAllFiles,proto3" json:"omit_all_files,omitempty"`
+}
+
+func (x *RecordRequest) Reset() {
+	*x = RecordRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RecordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordRequest) ProtoMessage() {}
+
+func (x *RecordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordRequest.ProtoReflect.Descriptor instead.
+func (*RecordRequest) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RecordRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *RecordRequest) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *RecordRequest) GetFilenames() []string {
+	if x != nil {
+		return x.Filenames
+	}
+	return nil
+}
+
+func (x *RecordRequest) GetOmitAllFiles() bool {
+	if x != nil {
+		return x.OmitAllFiles
+	}
+	return false
+}
+
+// Records retrieves a batch of records.
+type Records struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Challenge string           `protobuf:"bytes,1,opt,name=challenge,proto3" json:"challenge,omitempty"` // Random challenge
+	Requests  []*RecordRequest `protobuf:"bytes,2,rep,name=requests,proto3" json:"requests,omitempty"`
+}
+
+func (x *Records) Reset() {
+	*x = Records{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Records) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Records) ProtoMessage() {}
+
+func (x *Records) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Records.ProtoReflect.Descriptor instead.
+func (*Records) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Records) GetChallenge() string {
+	if x != nil {
+		return x.Challenge
+	}
+	return ""
+}
+
+func (x *Records) GetRequests() []*RecordRequest {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+// RecordsMsg is a message of the Records server stream. The first message
+// contains the challenge response. Each record is then sent in a message that
+// contains the record and the token that it was requested with, followed by
+// the FileChunk messages for the files of that record.
+type RecordsMsg struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Response string     `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"` // Challenge response
+	Token    string     `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`       // Token that the record was requested with
+	Record   *Record    `protobuf:"bytes,3,opt,name=record,proto3" json:"record,omitempty"`
+	Chunk    *FileChunk `protobuf:"bytes,4,opt,name=chunk,proto3" json:"chunk,omitempty"`
+}
+
+func (x *RecordsMsg) Reset() {
+	*x = RecordsMsg{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RecordsMsg) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordsMsg) ProtoMessage() {}
+
+func (x *RecordsMsg) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordsMsg.ProtoReflect.Descriptor instead.
+func (*RecordsMsg) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *RecordsMsg) GetResponse() string {
+	if x != nil {
+		return x.Response
+	}
+	return ""
+}
+
+func (x *RecordsMsg) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *RecordsMsg) GetRecord() *Record {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+func (x *RecordsMsg) GetChunk() *FileChunk {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+// Proof contains an inclusion proof for the digest in the merkle root.
+type Proof struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type       string   `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	Digest     string   `protobuf:"bytes,2,opt,name=digest,proto3" json:"digest,omitempty"`
+	MerkleRoot string   `protobuf:"bytes,3,opt,name=merkle_root,json=merkleRoot,proto3" json:"merkle_root,omitempty"`
+	MerklePath []string `protobuf:"bytes,4,rep,name=merkle_path,json=merklePath,proto3" json:"merkle_path,omitempty"`
+	ExtraData  string   `protobuf:"bytes,5,opt,name=extra_data,json=extraData,proto3" json:"extra_data,omitempty"` // JSON encoded
+}
+
+func (x *Proof) Reset() {
+	*x = Proof{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[14]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Proof) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Proof) ProtoMessage() {}
+
+func (x *Proof) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[14]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Proof.ProtoReflect.Descriptor instead.
+func (*Proof) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Proof) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Proof) GetDigest() string {
+	if x != nil {
+		return x.Digest
+	}
+	return ""
+}
+
+func (x *Proof) GetMerkleRoot() string {
+	if x != nil {
+		return x.MerkleRoot
+	}
+	return ""
+}
+
+func (x *Proof) GetMerklePath() []string {
+	if x != nil {
+		return x.MerklePath
+	}
+	return nil
+}
+
+func (x *Proof) GetExtraData() string {
+	if x != nil {
+		return x.ExtraData
+	}
+	return ""
+}
+
+// Timestamp contains all of the data required to verify that a piece of
+// record content was timestamped onto the decred blockchain.
+type Timestamp struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Data       string   `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"` // JSON encoded
+	Digest     string   `protobuf:"bytes,2,opt,name=digest,proto3" json:"digest,omitempty"`
+	TxId       string   `protobuf:"bytes,3,opt,name=tx_id,json=txId,proto3" json:"tx_id,omitempty"`
+	MerkleRoot string   `protobuf:"bytes,4,opt,name=merkle_root,json=merkleRoot,proto3" json:"merkle_root,omitempty"`
+	Proofs     []*Proof `protobuf:"bytes,5,rep,name=proofs,proto3" json:"proofs,omitempty"`
+}
+
+func (x *Timestamp) Reset() {
+	*x = Timestamp{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Timestamp) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Timestamp) ProtoMessage() {}
+
+func (x *Timestamp) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Timestamp.ProtoReflect.Descriptor instead.
+func (*Timestamp) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *Timestamp) GetData() string {
+	if x != nil {
+		return x.Data
+	}
+	return ""
+}
+
+func (x *Timestamp) GetDigest() string {
+	if x != nil {
+		return x.Digest
+	}
+	return ""
+}
+
+func (x *Timestamp) GetTxId() string {
+	if x != nil {
+		return x.TxId
+	}
+	return ""
+}
+
+func (x *Timestamp) GetMerkleRoot() string {
+	if x != nil {
+		return x.MerkleRoot
+	}
+	return ""
+}
+
+func (x *Timestamp) GetProofs() []*Proof {
+	if x != nil {
+		return x.Proofs
+	}
+	return nil
+}
+
+// StreamTimestamps contains the timestamps of the metadata streams of a
+// plugin.
+type StreamTimestamps struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Streams map[uint32]*Timestamp `protobuf:"bytes,1,rep,name=streams,proto3" json:"streams,omitempty" protobuf_key:"varint,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // [streamID]Timestamp
+}
+
+func (x *StreamTimestamps) Reset() {
+	*x = StreamTimestamps{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[16]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StreamTimestamps) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamTimestamps) ProtoMessage() {}
+
+func (x *StreamTimestamps) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[16]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamTimestamps.ProtoReflect.Descriptor instead.
+func (*StreamTimestamps) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *StreamTimestamps) GetStreams() map[uint32]*Timestamp {
+	if x != nil {
+		return x.Streams
+	}
+	return nil
+}
+
+// RecordTimestamps requests the timestamps for a record. If a version is not
+// included the most recent version will be returned.
+type RecordTimestamps struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Challenge string `protobuf:"bytes,1,opt,name=challenge,proto3" json:"challenge,omitempty"` // Random challenge
+	Token     string `protobuf:"bytes,2,opt,name=token,proto3" json:"token,omitempty"`         // Censorship token
+	Version   uint32 `protobuf:"varint,3,opt,name=version,proto3" json:"version,omitempty"`    // Record version
+}
+
+func (x *RecordTimestamps) Reset() {
+	*x = RecordTimestamps{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[17]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RecordTimestamps) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordTimestamps) ProtoMessage() {}
+
+func (x *RecordTimestamps) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[17]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordTimestamps.ProtoReflect.Descriptor instead.
+func (*RecordTimestamps) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *RecordTimestamps) GetChallenge() string {
+	if x != nil {
+		return x.Challenge
+	}
+	return ""
+}
+
+func (x *RecordTimestamps) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *RecordTimestamps) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+// RecordTimestampsReply is the reply to the RecordTimestamps command.
+type RecordTimestampsReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Response       string                       `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"` // Challenge response
+	RecordMetadata *Timestamp                   `protobuf:"bytes,2,opt,name=record_metadata,json=recordMetadata,proto3" json:"record_metadata,omitempty"`
+	Metadata       map[string]*StreamTimestamps `protobuf:"bytes,3,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"` // [pluginID]StreamTimestamps
+	Files          map[string]*Timestamp        `protobuf:"bytes,4,rep,name=files,proto3" json:"files,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`       // [filename]Timestamp
+}
+
+func (x *RecordTimestampsReply) Reset() {
+	*x = RecordTimestampsReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[18]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RecordTimestampsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordTimestampsReply) ProtoMessage() {}
+
+func (x *RecordTimestampsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[18]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordTimestampsReply.ProtoReflect.Descriptor instead.
+func (*RecordTimestampsReply) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *RecordTimestampsReply) GetResponse() string {
+	if x != nil {
+		return x.Response
+	}
+	return ""
+}
+
+func (x *RecordTimestampsReply) GetRecordMetadata() *Timestamp {
+	if x != nil {
+		return x.RecordMetadata
+	}
+	return nil
+}
+
+func (x *RecordTimestampsReply) GetMetadata() map[string]*StreamTimestamps {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *RecordTimestampsReply) GetFiles() map[string]*Timestamp {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+// Inventory requests the tokens of the records in the inventory. The state,
+// status and page are optional.
+type Inventory struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Challenge string       `protobuf:"bytes,1,opt,name=challenge,proto3" json:"challenge,omitempty"` // Random challenge
+	State     RecordState  `protobuf:"varint,2,opt,name=state,proto3,enum=politeiad.v2.RecordState" json:"state,omitempty"`
+	Status    RecordStatus `protobuf:"varint,3,opt,name=status,proto3,enum=politeiad.v2.RecordStatus" json:"status,omitempty"`
+	Page      uint32       `protobuf:"varint,4,opt,name=page,proto3" json:"page,omitempty"`
+}
+
+func (x *Inventory) Reset() {
+	*x = Inventory{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[19]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Inventory) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Inventory) ProtoMessage() {}
+
+func (x *Inventory) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[19]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Inventory.ProtoReflect.Descriptor instead.
+func (*Inventory) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Inventory) GetChallenge() string {
+	if x != nil {
+		return x.Challenge
+	}
+	return ""
+}
+
+func (x *Inventory) GetState() RecordState {
+	if x != nil {
+		return x.State
+	}
+	return RecordState_RECORD_STATE_INVALID
+}
+
+func (x *Inventory) GetStatus() RecordStatus {
+	if x != nil {
+		return x.Status
+	}
+	return RecordStatus_RECORD_STATUS_INVALID
+}
+
+func (x *Inventory) GetPage() uint32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+// Tokens contains a list of record tokens.
+type Tokens struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Tokens []string `protobuf:"bytes,1,rep,name=tokens,proto3" json:"tokens,omitempty"`
+}
+
+func (x *Tokens) Reset() {
+	*x = Tokens{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[20]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Tokens) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tokens) ProtoMessage() {}
+
+func (x *Tokens) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[20]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tokens.ProtoReflect.Descriptor instead.
+func (*Tokens) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *Tokens) GetTokens() []string {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+// InventoryReply is the reply to the Inventory command. The map keys are the
+// human readable record statuses defined by the politeiad v2 API
+// RecordStatuses array.
+type InventoryReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Response string             `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"` // Challenge response
+	Unvetted map[string]*Tokens `protobuf:"bytes,2,rep,name=unvetted,proto3" json:"unvetted,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+	Vetted   map[string]*Tokens `protobuf:"bytes,3,rep,name=vetted,proto3" json:"vetted,omitempty" protobuf_key:"bytes,1,opt,name=key,proto3" protobuf_val:"bytes,2,opt,name=value,proto3"`
+}
+
+func (x *InventoryReply) Reset() {
+	*x = InventoryReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[21]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *InventoryReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InventoryReply) ProtoMessage() {}
+
+func (x *InventoryReply) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[21]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InventoryReply.ProtoReflect.Descriptor instead.
+func (*InventoryReply) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *InventoryReply) GetResponse() string {
+	if x != nil {
+		return x.Response
+	}
+	return ""
+}
+
+func (x *InventoryReply) GetUnvetted() map[string]*Tokens {
+	if x != nil {
+		return x.Unvetted
+	}
+	return nil
+}
+
+func (x *InventoryReply) GetVetted() map[string]*Tokens {
+	if x != nil {
+		return x.Vetted
+	}
+	return nil
+}
+
+// PluginCmd represents a plugin command.
+type PluginCmd struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Token   string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`     // Censorship token
+	Id      string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`           // Plugin identifier
+	Command string `protobuf:"bytes,3,opt,name=command,proto3" json:"command,omitempty"` // Plugin command
+	Payload string `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"` // Command payload
+}
+
+func (x *PluginCmd) Reset() {
+	*x = PluginCmd{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[22]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PluginCmd) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginCmd) ProtoMessage() {}
+
+func (x *PluginCmd) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[22]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginCmd.ProtoReflect.Descriptor instead.
+func (*PluginCmd) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *PluginCmd) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *PluginCmd) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PluginCmd) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *PluginCmd) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+// PluginWrite executes a plugin command that writes data.
+type PluginWrite struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Challenge string     `protobuf:"bytes,1,opt,name=challenge,proto3" json:"challenge,omitempty"` // Random challenge
+	Cmd       *PluginCmd `protobuf:"bytes,2,opt,name=cmd,proto3" json:"cmd,omitempty"`
+}
+
+func (x *PluginWrite) Reset() {
+	*x = PluginWrite{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[23]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PluginWrite) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginWrite) ProtoMessage() {}
+
+func (x *PluginWrite) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[23]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginWrite.ProtoReflect.Descriptor instead.
+func (*PluginWrite) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *PluginWrite) GetChallenge() string {
+	if x != nil {
+		return x.Challenge
+	}
+	return ""
+}
+
+func (x *PluginWrite) GetCmd() *PluginCmd {
+	if x != nil {
+		return x.Cmd
+	}
+	return nil
+}
+
+// PluginWriteReply is the reply to the PluginWrite command.
+type PluginWriteReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Response string `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"` // Challenge response
+	Payload  string `protobuf:"bytes,2,opt,name=payload,proto3" json:"payload,omitempty"`   // Response payload
+}
+
+func (x *PluginWriteReply) Reset() {
+	*x = PluginWriteReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[24]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PluginWriteReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginWriteReply) ProtoMessage() {}
+
+func (x *PluginWriteReply) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[24]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginWriteReply.ProtoReflect.Descriptor instead.
+func (*PluginWriteReply) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *PluginWriteReply) GetResponse() string {
+	if x != nil {
+		return x.Response
+	}
+	return ""
+}
+
+func (x *PluginWriteReply) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+// PluginReads executes a batch of read only plugin commands.
+type PluginReads struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Challenge string       `protobuf:"bytes,1,opt,name=challenge,proto3" json:"challenge,omitempty"` // Random challenge
+	Cmds      []*PluginCmd `protobuf:"bytes,2,rep,name=cmds,proto3" json:"cmds,omitempty"`
+}
+
+func (x *PluginReads) Reset() {
+	*x = PluginReads{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[25]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PluginReads) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginReads) ProtoMessage() {}
+
+func (x *PluginReads) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[25]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginReads.ProtoReflect.Descriptor instead.
+func (*PluginReads) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *PluginReads) GetChallenge() string {
+	if x != nil {
+		return x.Challenge
+	}
+	return ""
+}
+
+func (x *PluginReads) GetCmds() []*PluginCmd {
+	if x != nil {
+		return x.Cmds
+	}
+	return nil
+}
+
+// UserErrorReply contains a politeiad v2 API user error.
+type UserErrorReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ErrorCode    uint32 `protobuf:"varint,1,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	ErrorContext string `protobuf:"bytes,2,opt,name=error_context,json=errorContext,proto3" json:"error_context,omitempty"`
+}
+
+func (x *UserErrorReply) Reset() {
+	*x = UserErrorReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[26]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *UserErrorReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserErrorReply) ProtoMessage() {}
+
+func (x *UserErrorReply) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[26]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserErrorReply.ProtoReflect.Descriptor instead.
+func (*UserErrorReply) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *UserErrorReply) GetErrorCode() uint32 {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return 0
+}
+
+func (x *UserErrorReply) GetErrorContext() string {
+	if x != nil {
+		return x.ErrorContext
+	}
+	return ""
+}
+
+// PluginErrorReply contains a plugin error.
+type PluginErrorReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	PluginId     string `protobuf:"bytes,1,opt,name=plugin_id,json=pluginId,proto3" json:"plugin_id,omitempty"`
+	ErrorCode    uint32 `protobuf:"varint,2,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	ErrorContext string `protobuf:"bytes,3,opt,name=error_context,json=errorContext,proto3" json:"error_context,omitempty"`
+}
+
+func (x *PluginErrorReply) Reset() {
+	*x = PluginErrorReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[27]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PluginErrorReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginErrorReply) ProtoMessage() {}
+
+func (x *PluginErrorReply) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[27]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginErrorReply.ProtoReflect.Descriptor instead.
+func (*PluginErrorReply) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *PluginErrorReply) GetPluginId() string {
+	if x != nil {
+		return x.PluginId
+	}
+	return ""
+}
+
+func (x *PluginErrorReply) GetErrorCode() uint32 {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return 0
+}
+
+func (x *PluginErrorReply) GetErrorContext() string {
+	if x != nil {
+		return x.ErrorContext
+	}
+	return ""
+}
+
+// PluginCmdReply is the reply to an individual plugin command that is part of
+// a batch of plugin commands. The user error is populated if a user error is
+// encountered prior to plugin command execution. The plugin error is
+// populated if a plugin error occurred during plugin command execution.
+type PluginCmdReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Token       string            `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`     // Censorship token
+	Id          string            `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`           // Plugin identifier
+	Command     string            `protobuf:"bytes,3,opt,name=command,proto3" json:"command,omitempty"` // Plugin command
+	Payload     string            `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"` // Response payload
+	UserError   *UserErrorReply   `protobuf:"bytes,5,opt,name=user_error,json=userError,proto3" json:"user_error,omitempty"`
+	PluginError *PluginErrorReply `protobuf:"bytes,6,opt,name=plugin_error,json=pluginError,proto3" json:"plugin_error,omitempty"`
+}
+
+func (x *PluginCmdReply) Reset() {
+	*x = PluginCmdReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[28]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PluginCmdReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginCmdReply) ProtoMessage() {}
+
+func (x *PluginCmdReply) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[28]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginCmdReply.ProtoReflect.Descriptor instead.
+func (*PluginCmdReply) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *PluginCmdReply) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *PluginCmdReply) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PluginCmdReply) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *PluginCmdReply) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+func (x *PluginCmdReply) GetUserError() *UserErrorReply {
+	if x != nil {
+		return x.UserError
+	}
+	return nil
+}
+
+func (x *PluginCmdReply) GetPluginError() *PluginErrorReply {
+	if x != nil {
+		return x.PluginError
+	}
+	return nil
+}
+
+// PluginReadsReply is the reply to the PluginReads command.
+type PluginReadsReply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Response string            `protobuf:"bytes,1,opt,name=response,proto3" json:"response,omitempty"` // Challenge response
+	Replies  []*PluginCmdReply `protobuf:"bytes,2,rep,name=replies,proto3" json:"replies,omitempty"`
+}
+
+func (x *PluginReadsReply) Reset() {
+	*x = PluginReadsReply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_v2_proto_msgTypes[29]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *PluginReadsReply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PluginReadsReply) ProtoMessage() {}
+
+func (x *PluginReadsReply) ProtoReflect() protoreflect.Message {
+	mi := &file_v2_proto_msgTypes[29]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PluginReadsReply.ProtoReflect.Descriptor instead.
+func (*PluginReadsReply) Descriptor() ([]byte, []int) {
+	return file_v2_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *PluginReadsReply) GetResponse() string {
+	if x != nil {
+		return x.Response
+	}
+	return ""
+}
+
+func (x *PluginReadsReply) GetReplies() []*PluginCmdReply {
+	if x != nil {
+		return x.Replies
+	}
+	return nil
+}
+
+var File_v2_proto protoreflect.FileDescriptor
+
+var file_v2_proto_rawDesc = []byte{
+	0x0a, 0x08, 0x76, 0x32, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0c, 0x70, 0x6f, 0x6c, 0x69,
+	0x74, 0x65, 0x69, 0x61, 0x64, 0x2e, 0x76, 0x32, 0x22, 0x64, 0x0a, 0x0e, 0x4d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x6c,
+	0x75, 0x67, 0x69, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70,
+	0x6c, 0x75, 0x67, 0x69, 0x6e, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x74, 0x72, 0x65, 0x61,
+	0x6d, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x73, 0x74, 0x72, 0x65,
+	0x61, 0x6d, 0x49, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0x60,
+	0x0a, 0x04, 0x46, 0x69, 0x6c, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x6d, 0x69,
+	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6d, 0x69, 0x6d, 0x65, 0x12, 0x16,
+	0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
+	0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61,
+	0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64,
+	0x22, 0x33, 0x0a, 0x09, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x12, 0x12, 0x0a,
+	0x04, 0x66, 0x69, 0x6c, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x66, 0x69, 0x6c,
+	0x65, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52,
+	0x04, 0x64, 0x61, 0x74, 0x61, 0x22, 0x5e, 0x0a, 0x10, 0x43, 0x65, 0x6e, 0x73, 0x6f, 0x72, 0x73,
+	0x68, 0x69, 0x70, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b,
+	0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12,
+	0x16, 0x0a, 0x06, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x06, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x73, 0x69, 0x67, 0x6e, 0x61,
+	0x74, 0x75, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x73, 0x69, 0x67, 0x6e,
+	0x61, 0x74, 0x75, 0x72, 0x65, 0x22, 0xd6, 0x02, 0x0a, 0x06, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64,
+	0x12, 0x2f, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x19, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e, 0x52,
+	0x65, 0x63, 0x6f, 0x72, 0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74,
+	0x65, 0x12, 0x32, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x1a, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e, 0x76, 0x32,
+	0x2e, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12,
+	0x1c, 0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x12, 0x38, 0x0a,
+	0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32,
+	0x1c, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e, 0x4d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x08, 0x6d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x28, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73,
+	0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69,
+	0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65,
+	0x73, 0x12, 0x4b, 0x0a, 0x11, 0x63, 0x65, 0x6e, 0x73, 0x6f, 0x72, 0x73, 0x68, 0x69, 0x70, 0x5f,
+	0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x70,
+	0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e, 0x43, 0x65, 0x6e, 0x73,
+	0x6f, 0x72, 0x73, 0x68, 0x69, 0x70, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x10, 0x63, 0x65,
+	0x6e, 0x73, 0x6f, 0x72, 0x73, 0x68, 0x69, 0x70, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x22, 0x8d,
+	0x01, 0x0a, 0x09, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x4e, 0x65, 0x77, 0x12, 0x1c, 0x0a, 0x09,
+	0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x12, 0x38, 0x0a, 0x08, 0x6d, 0x65,
+	0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x70,
+	0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e, 0x4d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x08, 0x6d, 0x65, 0x74, 0x61,
+	0x64, 0x61, 0x74, 0x61, 0x12, 0x28, 0x0a, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x03, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e,
+	0x76, 0x32, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x22, 0x75,
+	0x0a, 0x0c, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x4e, 0x65, 0x77, 0x4d, 0x73, 0x67, 0x12, 0x36,
+	0x0a, 0x0a, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x5f, 0x6e, 0x65, 0x77, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e, 0x76,
+	0x32, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x4e, 0x65, 0x77, 0x52, 0x09, 0x72, 0x65, 0x63,
+	0x6f, 0x72, 0x64, 0x4e, 0x65, 0x77, 0x12, 0x2d, 0x0a, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61,
+	0x64, 0x2e, 0x76, 0x32, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x68, 0x75, 0x6e, 0x6b, 0x52, 0x05,
+	0x63, 0x68, 0x75, 0x6e, 0x6b, 0x22, 0x5a, 0x0a, 0x0e, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x4e,
+	0x65, 0x77, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x06, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e,
+	0x76, 0x32, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x06, 0x72, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x22, 0x8a, 0x02, 0x0a, 0x0a, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x45, 0x64, 0x69, 0x74,
+	0x12, 0x1c, 0x0a, 0x09, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x12, 0x14,
+	0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74,
+	0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x39, 0x0a, 0x09, 0x6d, 0x64, 0x5f, 0x61, 0x70, 0x70, 0x65, 0x6e,
+	0x64, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65,
+	0x69, 0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x53,
+	0x74, 0x72, 0x65, 0x61, 0x6d, 0x52, 0x08, 0x6d, 0x64, 0x41, 0x70, 0x70, 0x65, 0x6e, 0x64, 0x12,
+	0x3f, 0x0a, 0x0c, 0x6d, 0x64, 0x5f, 0x6f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74, 0x65, 0x18,
+	0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61,
+	0x64, 0x2e, 0x76, 0x32, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x53, 0x74, 0x72,
+	0x65, 0x61, 0x6d, 0x52, 0x0b, 0x6d, 0x64, 0x4f, 0x76, 0x65, 0x72, 0x77, 0x72, 0x69, 0x74, 0x65,
+	0x12, 0x2f, 0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x5f, 0x61, 0x64, 0x64, 0x18, 0x05, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e,
+	0x76, 0x32, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x41, 0x64,
+	0x64, 0x12, 0x1b, 0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x5f, 0x64, 0x65, 0x6c, 0x18, 0x06,
+	0x20, 0x03, 0x28, 0x09, 0x52, 0x08, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x44, 0x65, 0x6c, 0x22, 0x79,
+	0x0a, 0x0d, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x45, 0x64, 0x69, 0x74, 0x4d, 0x73, 0x67, 0x12,
+	0x39, 0x0a, 0x0b, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x5f, 0x65, 0x64, 0x69, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x18, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64,
+	0x2e, 0x76, 0x32, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x45, 0x64, 0x69, 0x74, 0x52, 0x0a,
+	0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x45, 0x64, 0x69, 0x74, 0x12, 0x2d, 0x0a, 0x05, 0x63, 0x68,
+	0x75, 0x6e, 0x6b, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x6f, 0x6c, 0x69,
+	0x74, 0x65, 0x69, 0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e, 0x46, 0x69, 0x6c, 0x65, 0x43, 0x68, 0x75,
+	0x6e, 0x6b, 0x52, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x22, 0x5b, 0x0a, 0x0f, 0x52, 0x65, 0x63,
+	0x6f, 0x72, 0x64, 0x45, 0x64, 0x69, 0x74, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x1a, 0x0a, 0x08,
+	0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08,
+	0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x06, 0x72, 0x65, 0x63, 0x6f,
+	0x72, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74,
+	0x65, 0x69, 0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x06,
+	0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x22, 0x83, 0x01, 0x0a, 0x0d, 0x52, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x18,
+	0x0a, 0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1c, 0x0a, 0x09, 0x66, 0x69, 0x6c, 0x65,
+	0x6e, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x09, 0x66, 0x69, 0x6c,
+	0x65, 0x6e, 0x61, 0x6d, 0x65, 0x73, 0x12, 0x24, 0x0a, 0x0e, 0x6f, 0x6d, 0x69, 0x74, 0x5f, 0x61,
+	0x6c, 0x6c, 0x5f, 0x66, 0x69, 0x6c, 0x65, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c,
+	0x6f, 0x6d, 0x69, 0x74, 0x41, 0x6c, 0x6c, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x22, 0x60, 0x0a, 0x07,
+	0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x68, 0x61, 0x6c, 0x6c,
+	0x65, 0x6e, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6c,
+	0x6c, 0x65, 0x6e, 0x67, 0x65, 0x12, 0x37, 0x0a, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65,
+	0x69, 0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x52, 0x08, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x73, 0x22, 0x9b,
+	0x01, 0x0a, 0x0a, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x4d, 0x73, 0x67, 0x12, 0x1a, 0x0a,
+	0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b,
+	0x65, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12,
+	0x2c, 0x0a, 0x06, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x14, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e, 0x52,
+	0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x06, 0x72, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x2d, 0x0a,
+	0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70,
+	0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e, 0x46, 0x69, 0x6c, 0x65,
+	0x43, 0x68, 0x75, 0x6e, 0x6b, 0x52, 0x05, 0x63, 0x68, 0x75, 0x6e, 0x6b, 0x22, 0x94, 0x01, 0x0a,
+	0x05, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x69,
+	0x67, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65,
+	0x73, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x72, 0x6f, 0x6f,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x52,
+	0x6f, 0x6f, 0x74, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x70, 0x61,
+	0x74, 0x68, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x0a, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65,
+	0x50, 0x61, 0x74, 0x68, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x78, 0x74, 0x72, 0x61, 0x5f, 0x64, 0x61,
+	0x74, 0x61, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x65, 0x78, 0x74, 0x72, 0x61, 0x44,
+	0x61, 0x74, 0x61, 0x22, 0x9a, 0x01, 0x0a, 0x09, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x16, 0x0a, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x64, 0x69, 0x67, 0x65, 0x73, 0x74, 0x12, 0x13, 0x0a,
+	0x05, 0x74, 0x78, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x78,
+	0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x5f, 0x72, 0x6f, 0x6f,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6d, 0x65, 0x72, 0x6b, 0x6c, 0x65, 0x52,
+	0x6f, 0x6f, 0x74, 0x12, 0x2b, 0x0a, 0x06, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x73, 0x18, 0x05, 0x20,
+	0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e,
+	0x76, 0x32, 0x2e, 0x50, 0x72, 0x6f, 0x6f, 0x66, 0x52, 0x06, 0x70, 0x72, 0x6f, 0x6f, 0x66, 0x73,
+	0x22, 0xae, 0x01, 0x0a, 0x10, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x73, 0x12, 0x45, 0x0a, 0x07, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x73,
+	0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2b, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69,
+	0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x73, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x73, 0x45, 0x6e,
+	0x74, 0x72, 0x79, 0x52, 0x07, 0x73, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x73, 0x1a, 0x53, 0x0a, 0x0c,
+	0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03,
+	0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2d,
+	0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e,
+	0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e, 0x54, 0x69, 0x6d,
+	0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38,
+	0x01, 0x22, 0x60, 0x0a, 0x10, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e,
+	0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65,
+	0x6e, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x18, 0x0a, 0x07, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07, 0x76, 0x65, 0x72, 0x73,
+	0x69, 0x6f, 0x6e, 0x22, 0xba, 0x03, 0x0a, 0x15, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x54, 0x69,
+	0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x1a, 0x0a,
+	0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x40, 0x0a, 0x0f, 0x72, 0x65, 0x63,
+	0x6f, 0x72, 0x64, 0x5f, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e, 0x76,
+	0x32, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x0e, 0x72, 0x65, 0x63,
+	0x6f, 0x72, 0x64, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x4d, 0x0a, 0x08, 0x6d,
+	0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x31, 0x2e,
+	0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e, 0x52, 0x65, 0x63,
+	0x6f, 0x72, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x73, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x2e, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72, 0x79,
+	0x52, 0x08, 0x6d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x12, 0x44, 0x0a, 0x05, 0x66, 0x69,
+	0x6c, 0x65, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x70, 0x6f, 0x6c, 0x69,
+	0x74, 0x65, 0x69, 0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x2e, 0x46,
+	0x69, 0x6c, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x05, 0x66, 0x69, 0x6c, 0x65, 0x73,
+	0x1a, 0x5b, 0x0a, 0x0d, 0x4d, 0x65, 0x74, 0x61, 0x64, 0x61, 0x74, 0x61, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x34, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e, 0x76,
+	0x32, 0x2e, 0x53, 0x74, 0x72, 0x65, 0x61, 0x6d, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d,
+	0x70, 0x73, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01, 0x1a, 0x51, 0x0a,
+	0x0a, 0x46, 0x69, 0x6c, 0x65, 0x73, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b,
+	0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03, 0x6b, 0x65, 0x79, 0x12, 0x2d, 0x0a,
+	0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70,
+	0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e, 0x54, 0x69, 0x6d, 0x65,
+	0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a, 0x02, 0x38, 0x01,
+	0x22, 0xa2, 0x01, 0x0a, 0x09, 0x49, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x1c,
+	0x0a, 0x09, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x09, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x12, 0x2f, 0x0a, 0x05,
+	0x73, 0x74, 0x61, 0x74, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x70, 0x6f,
+	0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x73, 0x74, 0x61, 0x74, 0x65, 0x12, 0x32, 0x0a,
+	0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e,
+	0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e, 0x52, 0x65, 0x63,
+	0x6f, 0x72, 0x64, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x12, 0x12, 0x0a, 0x04, 0x70, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x04, 0x70, 0x61, 0x67, 0x65, 0x22, 0x20, 0x0a, 0x06, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x12,
+	0x16, 0x0a, 0x06, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x06, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x22, 0xda, 0x02, 0x0a, 0x0e, 0x49, 0x6e, 0x76, 0x65,
+	0x6e, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65,
+	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x46, 0x0a, 0x08, 0x75, 0x6e, 0x76, 0x65, 0x74, 0x74,
+	0x65, 0x64, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2a, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74,
+	0x65, 0x69, 0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e, 0x49, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72,
+	0x79, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x2e, 0x55, 0x6e, 0x76, 0x65, 0x74, 0x74, 0x65, 0x64, 0x45,
+	0x6e, 0x74, 0x72, 0x79, 0x52, 0x08, 0x75, 0x6e, 0x76, 0x65, 0x74, 0x74, 0x65, 0x64, 0x12, 0x40,
+	0x0a, 0x06, 0x76, 0x65, 0x74, 0x74, 0x65, 0x64, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x28,
+	0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e, 0x49, 0x6e,
+	0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x2e, 0x56, 0x65, 0x74,
+	0x74, 0x65, 0x64, 0x45, 0x6e, 0x74, 0x72, 0x79, 0x52, 0x06, 0x76, 0x65, 0x74, 0x74, 0x65, 0x64,
+	0x1a, 0x51, 0x0a, 0x0d, 0x55, 0x6e, 0x76, 0x65, 0x74, 0x74, 0x65, 0x64, 0x45, 0x6e, 0x74, 0x72,
+	0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x03,
+	0x6b, 0x65, 0x79, 0x12, 0x2a, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e, 0x76,
+	0x32, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x3a,
+	0x02, 0x38, 0x01, 0x1a, 0x4f, 0x0a, 0x0b, 0x56, 0x65, 0x74, 0x74, 0x65, 0x64, 0x45, 0x6e, 0x74,
+	0x72, 0x79, 0x12, 0x10, 0x0a, 0x03, 0x6b, 0x65, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x03, 0x6b, 0x65, 0x79, 0x12, 0x2a, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x14, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e,
+	0x76, 0x32, 0x2e, 0x54, 0x6f, 0x6b, 0x65, 0x6e, 0x73, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x75, 0x65,
+	0x3a, 0x02, 0x38, 0x01, 0x22, 0x65, 0x0a, 0x09, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x43, 0x6d,
+	0x64, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61,
+	0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f, 0x6d, 0x6d, 0x61, 0x6e,
+	0x64, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0x56, 0x0a, 0x0b, 0x50,
+	0x6c, 0x75, 0x67, 0x69, 0x6e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x63, 0x68,
+	0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63,
+	0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x12, 0x29, 0x0a, 0x03, 0x63, 0x6d, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61,
+	0x64, 0x2e, 0x76, 0x32, 0x2e, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x43, 0x6d, 0x64, 0x52, 0x03,
+	0x63, 0x6d, 0x64, 0x22, 0x48, 0x0a, 0x10, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x57, 0x72, 0x69,
+	0x74, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x22, 0x58, 0x0a,
+	0x0b, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x52, 0x65, 0x61, 0x64, 0x73, 0x12, 0x1c, 0x0a, 0x09,
+	0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x09, 0x63, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x12, 0x2b, 0x0a, 0x04, 0x63, 0x6d,
+	0x64, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x17, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74,
+	0x65, 0x69, 0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x43, 0x6d,
+	0x64, 0x52, 0x04, 0x63, 0x6d, 0x64, 0x73, 0x22, 0x54, 0x0a, 0x0e, 0x55, 0x73, 0x65, 0x72, 0x45,
+	0x72, 0x72, 0x6f, 0x72, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x65, 0x72, 0x72,
+	0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x09, 0x65,
+	0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x23, 0x0a, 0x0d, 0x65, 0x72, 0x72, 0x6f,
+	0x72, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x22, 0x73, 0x0a,
+	0x10, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x5f, 0x69, 0x64, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x49, 0x64, 0x12, 0x1d,
+	0x0a, 0x0a, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x0d, 0x52, 0x09, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x64, 0x65, 0x12, 0x23, 0x0a,
+	0x0d, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x5f, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x78, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0c, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x6e, 0x74, 0x65,
+	0x78, 0x74, 0x22, 0xea, 0x01, 0x0a, 0x0e, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x43, 0x6d, 0x64,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x74, 0x6f, 0x6b, 0x65, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69,
+	0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x63,
+	0x6f, 0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x63, 0x6f,
+	0x6d, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x12,
+	0x3b, 0x0a, 0x0a, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e,
+	0x76, 0x32, 0x2e, 0x55, 0x73, 0x65, 0x72, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x52, 0x09, 0x75, 0x73, 0x65, 0x72, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x41, 0x0a, 0x0c,
+	0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x5f, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x06, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e, 0x76,
+	0x32, 0x2e, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x52, 0x0b, 0x70, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x22,
+	0x66, 0x0a, 0x10, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x52, 0x65, 0x61, 0x64, 0x73, 0x52, 0x65,
+	0x70, 0x6c, 0x79, 0x12, 0x1a, 0x0a, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x72, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x36, 0x0a, 0x07, 0x72, 0x65, 0x70, 0x6c, 0x69, 0x65, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x1c, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e,
+	0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x43, 0x6d, 0x64, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x52, 0x07,
+	0x72, 0x65, 0x70, 0x6c, 0x69, 0x65, 0x73, 0x2a, 0x5b, 0x0a, 0x0b, 0x52, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x18, 0x0a, 0x14, 0x52, 0x45, 0x43, 0x4f, 0x52, 0x44,
+	0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x10, 0x00,
+	0x12, 0x19, 0x0a, 0x15, 0x52, 0x45, 0x43, 0x4f, 0x52, 0x44, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45,
+	0x5f, 0x55, 0x4e, 0x56, 0x45, 0x54, 0x54, 0x45, 0x44, 0x10, 0x01, 0x12, 0x17, 0x0a, 0x13, 0x52,
+	0x45, 0x43, 0x4f, 0x52, 0x44, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x45, 0x5f, 0x56, 0x45, 0x54, 0x54,
+	0x45, 0x44, 0x10, 0x02, 0x2a, 0x99, 0x01, 0x0a, 0x0c, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x19, 0x0a, 0x15, 0x52, 0x45, 0x43, 0x4f, 0x52, 0x44, 0x5f,
+	0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x10, 0x00,
+	0x12, 0x1c, 0x0a, 0x18, 0x52, 0x45, 0x43, 0x4f, 0x52, 0x44, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55,
+	0x53, 0x5f, 0x55, 0x4e, 0x52, 0x45, 0x56, 0x49, 0x45, 0x57, 0x45, 0x44, 0x10, 0x01, 0x12, 0x18,
+	0x0a, 0x14, 0x52, 0x45, 0x43, 0x4f, 0x52, 0x44, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f,
+	0x50, 0x55, 0x42, 0x4c, 0x49, 0x43, 0x10, 0x02, 0x12, 0x1a, 0x0a, 0x16, 0x52, 0x45, 0x43, 0x4f,
+	0x52, 0x44, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x43, 0x45, 0x4e, 0x53, 0x4f, 0x52,
+	0x45, 0x44, 0x10, 0x03, 0x12, 0x1a, 0x0a, 0x16, 0x52, 0x45, 0x43, 0x4f, 0x52, 0x44, 0x5f, 0x53,
+	0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x41, 0x52, 0x43, 0x48, 0x49, 0x56, 0x45, 0x44, 0x10, 0x04,
+	0x32, 0x8f, 0x04, 0x0a, 0x09, 0x50, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x12, 0x47,
+	0x0a, 0x09, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x4e, 0x65, 0x77, 0x12, 0x1a, 0x2e, 0x70, 0x6f,
+	0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x4e, 0x65, 0x77, 0x4d, 0x73, 0x67, 0x1a, 0x1c, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65,
+	0x69, 0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x4e, 0x65, 0x77,
+	0x52, 0x65, 0x70, 0x6c, 0x79, 0x28, 0x01, 0x12, 0x4a, 0x0a, 0x0a, 0x52, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x45, 0x64, 0x69, 0x74, 0x12, 0x1b, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61,
+	0x64, 0x2e, 0x76, 0x32, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x45, 0x64, 0x69, 0x74, 0x4d,
+	0x73, 0x67, 0x1a, 0x1d, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e, 0x76,
+	0x32, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x45, 0x64, 0x69, 0x74, 0x52, 0x65, 0x70, 0x6c,
+	0x79, 0x28, 0x01, 0x12, 0x3c, 0x0a, 0x07, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x12, 0x15,
+	0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e, 0x52, 0x65,
+	0x63, 0x6f, 0x72, 0x64, 0x73, 0x1a, 0x18, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61,
+	0x64, 0x2e, 0x76, 0x32, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x73, 0x4d, 0x73, 0x67, 0x30,
+	0x01, 0x12, 0x57, 0x0a, 0x10, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x73, 0x12, 0x1e, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61,
+	0x64, 0x2e, 0x76, 0x32, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x73, 0x1a, 0x23, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61,
+	0x64, 0x2e, 0x76, 0x32, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x54, 0x69, 0x6d, 0x65, 0x73,
+	0x74, 0x61, 0x6d, 0x70, 0x73, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x42, 0x0a, 0x09, 0x49, 0x6e,
+	0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x12, 0x17, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65,
+	0x69, 0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e, 0x49, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79,
+	0x1a, 0x1c, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e,
+	0x49, 0x6e, 0x76, 0x65, 0x6e, 0x74, 0x6f, 0x72, 0x79, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x48,
+	0x0a, 0x0b, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x12, 0x19, 0x2e,
+	0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e, 0x50, 0x6c, 0x75,
+	0x67, 0x69, 0x6e, 0x57, 0x72, 0x69, 0x74, 0x65, 0x1a, 0x1e, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74,
+	0x65, 0x69, 0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x57, 0x72,
+	0x69, 0x74, 0x65, 0x52, 0x65, 0x70, 0x6c, 0x79, 0x12, 0x48, 0x0a, 0x0b, 0x50, 0x6c, 0x75, 0x67,
+	0x69, 0x6e, 0x52, 0x65, 0x61, 0x64, 0x73, 0x12, 0x19, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65,
+	0x69, 0x61, 0x64, 0x2e, 0x76, 0x32, 0x2e, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x52, 0x65, 0x61,
+	0x64, 0x73, 0x1a, 0x1e, 0x2e, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2e, 0x76,
+	0x32, 0x2e, 0x50, 0x6c, 0x75, 0x67, 0x69, 0x6e, 0x52, 0x65, 0x61, 0x64, 0x73, 0x52, 0x65, 0x70,
+	0x6c, 0x79, 0x42, 0x35, 0x5a, 0x33, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d,
+	0x2f, 0x64, 0x65, 0x63, 0x72, 0x65, 0x64, 0x2f, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61,
+	0x2f, 0x70, 0x6f, 0x6c, 0x69, 0x74, 0x65, 0x69, 0x61, 0x64, 0x2f, 0x61, 0x70, 0x69, 0x2f, 0x67,
+	0x72, 0x70, 0x63, 0x2f, 0x76, 0x32, 0x3b, 0x76, 0x32, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_v2_proto_rawDescOnce sync.Once
+	file_v2_proto_rawDescData = file_v2_proto_rawDesc
+)
+
+func file_v2_proto_rawDescGZIP() []byte {
+	file_v2_proto_rawDescOnce.Do(func() {
+		file_v2_proto_rawDescData = protoimpl.X.CompressGZIP(file_v2_proto_rawDescData)
+	})
+	return file_v2_proto_rawDescData
+}
+
+var file_v2_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_v2_proto_msgTypes = make([]protoimpl.MessageInfo, 35)
+var file_v2_proto_goTypes = []interface{}{
+	(RecordState)(0),              // 0: politeiad.v2.RecordState
+	(RecordStatus)(0),             // 1: politeiad.v2.RecordStatus
+	(*MetadataStream)(nil),        // 2: politeiad.v2.MetadataStream
+	(*File)(nil),                  // 3: politeiad.v2.File
+	(*FileChunk)(nil),             // 4: politeiad.v2.FileChunk
+	(*CensorshipRecord)(nil),      // 5: politeiad.v2.CensorshipRecord
+	(*Record)(nil),                // 6: politeiad.v2.Record
+	(*RecordNew)(nil),             // 7: politeiad.v2.RecordNew
+	(*RecordNewMsg)(nil),          // 8: politeiad.v2.RecordNewMsg
+	(*RecordNewReply)(nil),        // 9: politeiad.v2.RecordNewReply
+	(*RecordEdit)(nil),            // 10: politeiad.v2.RecordEdit
+	(*RecordEditMsg)(nil),         // 11: politeiad.v2.RecordEditMsg
+	(*RecordEditReply)(nil),       // 12: politeiad.v2.RecordEditReply
+	(*RecordRequest)(nil),         // 13: politeiad.v2.RecordRequest
+	(*Records)(nil),               // 14: politeiad.v2.Records
+	(*RecordsMsg)(nil),            // 15: politeiad.v2.RecordsMsg
+	(*Proof)(nil),                 // 16: politeiad.v2.Proof
+	(*Timestamp)(nil),             // 17: politeiad.v2.Timestamp
+	(*StreamTimestamps)(nil),      // 18: politeiad.v2.StreamTimestamps
+	(*RecordTimestamps)(nil),      // 19: politeiad.v2.RecordTimestamps
+	(*RecordTimestampsReply)(nil), // 20: politeiad.v2.RecordTimestampsReply
+	(*Inventory)(nil),             // 21: politeiad.v2.Inventory
+	(*Tokens)(nil),                // 22: politeiad.v2.Tokens
+	(*InventoryReply)(nil),        // 23: politeiad.v2.InventoryReply
+	(*PluginCmd)(nil),             // 24: politeiad.v2.PluginCmd
+	(*PluginWrite)(nil),           // 25: politeiad.v2.PluginWrite
+	(*PluginWriteReply)(nil),      // 26: politeiad.v2.PluginWriteReply
+	(*PluginReads)(nil),           // 27: politeiad.v2.PluginReads
+	(*UserErrorReply)(nil),        // 28: politeiad.v2.UserErrorReply
+	(*PluginErrorReply)(nil),      // 29: politeiad.v2.PluginErrorReply
+	(*PluginCmdReply)(nil),        // 30: politeiad.v2.PluginCmdReply
+	(*PluginReadsReply)(nil),      // 31: politeiad.v2.PluginReadsReply
+	nil,                           // 32: politeiad.v2.StreamTimestamps.StreamsEntry
+	nil,                           // 33: politeiad.v2.RecordTimestampsReply.MetadataEntry
+	nil,                           // 34: politeiad.v2.RecordTimestampsReply.FilesEntry
+	nil,                           // 35: politeiad.v2.InventoryReply.UnvettedEntry
+	nil,                           // 36: politeiad.v2.InventoryReply.VettedEntry
+}
+var file_v2_proto_depIdxs = []int32{
+	0,  // 0: politeiad.v2.Record.state:type_name -> politeiad.v2.RecordState
+	1,  // 1: politeiad.v2.Record.status:type_name -> politeiad.v2.RecordStatus
+	2,  // 2: politeiad.v2.Record.metadata:type_name -> politeiad.v2.MetadataStream
+	3,  // 3: politeiad.v2.Record.files:type_name -> politeiad.v2.File
+	5,  // 4: politeiad.v2.Record.censorship_record:type_name -> politeiad.v2.CensorshipRecord
+	2,  // 5: politeiad.v2.RecordNew.metadata:type_name -> politeiad.v2.MetadataStream
+	3,  // 6: politeiad.v2.RecordNew.files:type_name -> politeiad.v2.File
+	7,  // 7: politeiad.v2.RecordNewMsg.record_new:type_name -> politeiad.v2.RecordNew
+	4,  // 8: politeiad.v2.RecordNewMsg.chunk:type_name -> politeiad.v2.FileChunk
+	6,  // 9: politeiad.v2.RecordNewReply.record:type_name -> politeiad.v2.Record
+	2,  // 10: politeiad.v2.RecordEdit.md_append:type_name -> politeiad.v2.MetadataStream
+	2,  // 11: politeiad.v2.RecordEdit.md_overwrite:type_name -> politeiad.v2.MetadataStream
+	3,  // 12: politeiad.v2.RecordEdit.files_add:type_name -> politeiad.v2.File
+	10, // 13: politeiad.v2.RecordEditMsg.record_edit:type_name -> politeiad.v2.RecordEdit
+	4,  // 14: politeiad.v2.RecordEditMsg.chunk:type_name -> politeiad.v2.FileChunk
+	6,  // 15: politeiad.v2.RecordEditReply.record:type_name -> politeiad.v2.Record
+	13, // 16: politeiad.v2.Records.requests:type_name -> politeiad.v2.RecordRequest
+	6,  // 17: politeiad.v2.RecordsMsg.record:type_name -> politeiad.v2.Record
+	4,  // 18: politeiad.v2.RecordsMsg.chunk:type_name -> politeiad.v2.FileChunk
+	16, // 19: politeiad.v2.Timestamp.proofs:type_name -> politeiad.v2.Proof
+	32, // 20: politeiad.v2.StreamTimestamps.streams:type_name -> politeiad.v2.StreamTimestamps.StreamsEntry
+	17, // 21: politeiad.v2.RecordTimestampsReply.record_metadata:type_name -> politeiad.v2.Timestamp
+	33, // 22: politeiad.v2.RecordTimestampsReply.metadata:type_name -> politeiad.v2.RecordTimestampsReply.MetadataEntry
+	34, // 23: politeiad.v2.RecordTimestampsReply.files:type_name -> politeiad.v2.RecordTimestampsReply.FilesEntry
+	0,  // 24: politeiad.v2.Inventory.state:type_name -> politeiad.v2.RecordState
+	1,  // 25: politeiad.v2.Inventory.status:type_name -> politeiad.v2.RecordStatus
+	35, // 26: politeiad.v2.InventoryReply.unvetted:type_name -> politeiad.v2.InventoryReply.UnvettedEntry
+	36, // 27: politeiad.v2.InventoryReply.vetted:type_name -> politeiad.v2.InventoryReply.VettedEntry
+	24, // 28: politeiad.v2.PluginWrite.cmd:type_name -> politeiad.v2.PluginCmd
+	24, // 29: politeiad.v2.PluginReads.cmds:type_name -> politeiad.v2.PluginCmd
+	28, // 30: politeiad.v2.PluginCmdReply.user_error:type_name -> politeiad.v2.UserErrorReply
+	29, // 31: politeiad.v2.PluginCmdReply.plugin_error:type_name -> politeiad.v2.PluginErrorReply
+	30, // 32: politeiad.v2.PluginReadsReply.replies:type_name -> politeiad.v2.PluginCmdReply
+	17, // 33: politeiad.v2.StreamTimestamps.StreamsEntry.value:type_name -> politeiad.v2.Timestamp
+	18, // 34: politeiad.v2.RecordTimestampsReply.MetadataEntry.value:type_name -> politeiad.v2.StreamTimestamps
+	17, // 35: politeiad.v2.RecordTimestampsReply.FilesEntry.value:type_name -> politeiad.v2.Timestamp
+	22, // 36: politeiad.v2.InventoryReply.UnvettedEntry.value:type_name -> politeiad.v2.Tokens
+	22, // 37: politeiad.v2.InventoryReply.VettedEntry.value:type_name -> politeiad.v2.Tokens
+	8,  // 38: politeiad.v2.Politeiad.RecordNew:input_type -> politeiad.v2.RecordNewMsg
+	11, // 39: politeiad.v2.Politeiad.RecordEdit:input_type -> politeiad.v2.RecordEditMsg
+	14, // 40: politeiad.v2.Politeiad.Records:input_type -> politeiad.v2.Records
+	19, // 41: politeiad.v2.Politeiad.RecordTimestamps:input_type -> politeiad.v2.RecordTimestamps
+	21, // 42: politeiad.v2.Politeiad.Inventory:input_type -> politeiad.v2.Inventory
+	25, // 43: politeiad.v2.Politeiad.PluginWrite:input_type -> politeiad.v2.PluginWrite
+	27, // 44: politeiad.v2.Politeiad.PluginReads:input_type -> politeiad.v2.PluginReads
+	9,  // 45: politeiad.v2.Politeiad.RecordNew:output_type -> politeiad.v2.RecordNewReply
+	12, // 46: politeiad.v2.Politeiad.RecordEdit:output_type -> politeiad.v2.RecordEditReply
+	15, // 47: politeiad.v2.Politeiad.Records:output_type -> politeiad.v2.RecordsMsg
+	20, // 48: politeiad.v2.Politeiad.RecordTimestamps:output_type -> politeiad.v2.RecordTimestampsReply
+	23, // 49: politeiad.v2.Politeiad.Inventory:output_type -> politeiad.v2.InventoryReply
+	26, // 50: politeiad.v2.Politeiad.PluginWrite:output_type -> politeiad.v2.PluginWriteReply
+	31, // 51: politeiad.v2.Politeiad.PluginReads:output_type -> politeiad.v2.PluginReadsReply
+	45, // [45:52] is the sub-list for method output_type
+	38, // [38:45] is the sub-list for method input_type
+	38, // [38:38] is the sub-list for extension type_name
+	38, // [38:38] is the sub-list for extension extendee
+	0,  // [0:38] is the sub-list for field type_name
+}
+
+func init() { file_v2_proto_init() }
+func file_v2_proto_init() {
+	if File_v2_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_v2_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*MetadataStream); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v2_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*File); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v2_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*FileChunk); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v2_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*CensorshipRecord); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v2_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Record); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v2_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RecordNew); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v2_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RecordNewMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v2_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RecordNewReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v2_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RecordEdit); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v2_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RecordEditMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v2_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RecordEditReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v2_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RecordRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v2_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Records); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v2_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RecordsMsg); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v2_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Proof); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v2_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Timestamp); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v2_proto_msgTypes[16].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StreamTimestamps); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v2_proto_msgTypes[17].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RecordTimestamps); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v2_proto_msgTypes[18].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RecordTimestampsReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v2_proto_msgTypes[19].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Inventory); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v2_proto_msgTypes[20].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Tokens); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v2_proto_msgTypes[21].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*InventoryReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v2_proto_msgTypes[22].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PluginCmd); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v2_proto_msgTypes[23].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PluginWrite); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v2_proto_msgTypes[24].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PluginWriteReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v2_proto_msgTypes[25].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PluginReads); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v2_proto_msgTypes[26].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*UserErrorReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v2_proto_msgTypes[27].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PluginErrorReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v2_proto_msgTypes[28].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PluginCmdReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_v2_proto_msgTypes[29].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*PluginReadsReply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_v2_proto_rawDesc,
+			NumEnums:      2,
+			NumMessages:   35,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_v2_proto_goTypes,
+		DependencyIndexes: file_v2_proto_depIdxs,
+		EnumInfos:         file_v2_proto_enumTypes,
+		MessageInfos:      file_v2_proto_msgTypes,
+	}.Build()
+	File_v2_proto = out.File
+	file_v2_proto_rawDesc = nil
+	file_v2_proto_goTypes = nil
+	file_v2_proto_depIdxs = nil
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConnInterface
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion6
+
+// PoliteiadClient is the client API for Politeiad service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type PoliteiadClient interface {
+	// RecordNew creates a new record. The first message must contain the
+	// RecordNew request. All subsequent messages must contain a file chunk.
+	RecordNew(ctx context.Context, opts ...grpc.CallOption) (Politeiad_RecordNewClient, error)
+	// RecordEdit edits an existing record. The first message must contain
+	// the RecordEdit request. All subsequent messages must contain a file
+	// chunk.
+	RecordEdit(ctx context.Context, opts ...grpc.CallOption) (Politeiad_RecordEditClient, error)
+	// Records returns a batch of records. See RecordsMsg for the order of
+	// the messages that are returned.
+	Records(ctx context.Context, in *Records, opts ...grpc.CallOption) (Politeiad_RecordsClient, error)
+	// RecordTimestamps returns the timestamps for a record.
+	RecordTimestamps(ctx context.Context, in *RecordTimestamps, opts ...grpc.CallOption) (*RecordTimestampsReply, error)
+	// Inventory returns the tokens of the records in the inventory,
+	// categorized by record state and record status.
+	Inventory(ctx context.Context, in *Inventory, opts ...grpc.CallOption) (*InventoryReply, error)
+	// PluginWrite executes a plugin write command.
+	PluginWrite(ctx context.Context, in *PluginWrite, opts ...grpc.CallOption) (*PluginWriteReply, error)
+	// PluginReads executes a batch of plugin read commands.
+	PluginReads(ctx context.Context, in *PluginReads, opts ...grpc.CallOption) (*PluginReadsReply, error)
+}
+
+type politeiadClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPoliteiadClient(cc grpc.ClientConnInterface) PoliteiadClient {
+	return &politeiadClient{cc}
+}
+
+func (c *politeiadClient) RecordNew(ctx context.Context, opts ...grpc.CallOption) (Politeiad_RecordNewClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Politeiad_serviceDesc.Streams[0], "/politeiad.v2.Politeiad/RecordNew", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &politeiadRecordNewClient{stream}
+	return x, nil
+}
+
+type Politeiad_RecordNewClient interface {
+	Send(*RecordNewMsg) error
+	CloseAndRecv() (*RecordNewReply, error)
+	grpc.ClientStream
+}
+
+type politeiadRecordNewClient struct {
+	grpc.ClientStream
+}
+
+func (x *politeiadRecordNewClient) Send(m *RecordNewMsg) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *politeiadRecordNewClient) CloseAndRecv() (*RecordNewReply, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(RecordNewReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *politeiadClient) RecordEdit(ctx context.Context, opts ...grpc.CallOption) (Politeiad_RecordEditClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Politeiad_serviceDesc.Streams[1], "/politeiad.v2.Politeiad/RecordEdit", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &politeiadRecordEditClient{stream}
+	return x, nil
+}
+
+type Politeiad_RecordEditClient interface {
+	Send(*RecordEditMsg) error
+	CloseAndRecv() (*RecordEditReply, error)
+	grpc.ClientStream
+}
+
+type politeiadRecordEditClient struct {
+	grpc.ClientStream
+}
+
+func (x *politeiadRecordEditClient) Send(m *RecordEditMsg) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *politeiadRecordEditClient) CloseAndRecv() (*RecordEditReply, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(RecordEditReply)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *politeiadClient) Records(ctx context.Context, in *Records, opts ...grpc.CallOption) (Politeiad_RecordsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Politeiad_serviceDesc.Streams[2], "/politeiad.v2.Politeiad/Records", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &politeiadRecordsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Politeiad_RecordsClient interface {
+	Recv() (*RecordsMsg, error)
+	grpc.ClientStream
+}
+
+type politeiadRecordsClient struct {
+	grpc.ClientStream
+}
+
+func (x *politeiadRecordsClient) Recv() (*RecordsMsg, error) {
+	m := new(RecordsMsg)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *politeiadClient) RecordTimestamps(ctx context.Context, in *RecordTimestamps, opts ...grpc.CallOption) (*RecordTimestampsReply, error) {
+	out := new(RecordTimestampsReply)
+	err := c.cc.Invoke(ctx, "/politeiad.v2.Politeiad/RecordTimestamps", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *politeiadClient) Inventory(ctx context.Context, in *Inventory, opts ...grpc.CallOption) (*InventoryReply, error) {
+	out := new(InventoryReply)
+	err := c.cc.Invoke(ctx, "/politeiad.v2.Politeiad/Inventory", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *politeiadClient) PluginWrite(ctx context.Context, in *PluginWrite, opts ...grpc.CallOption) (*PluginWriteReply, error) {
+	out := new(PluginWriteReply)
+	err := c.cc.Invoke(ctx, "/politeiad.v2.Politeiad/PluginWrite", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *politeiadClient) PluginReads(ctx context.Context, in *PluginReads, opts ...grpc.CallOption) (*PluginReadsReply, error) {
+	out := new(PluginReadsReply)
+	err := c.cc.Invoke(ctx, "/politeiad.v2.Politeiad/PluginReads", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PoliteiadServer is the server API for Politeiad service.
+type PoliteiadServer interface {
+	// RecordNew creates a new record. The first message must contain the
+	// RecordNew request. All subsequent messages must contain a file chunk.
+	RecordNew(Politeiad_RecordNewServer) error
+	// RecordEdit edits an existing record. The first message must contain
+	// the RecordEdit request. All subsequent messages must contain a file
+	// chunk.
+	RecordEdit(Politeiad_RecordEditServer) error
+	// Records returns a batch of records. See RecordsMsg for the order of
+	// the messages that are returned.
+	Records(*Records, Politeiad_RecordsServer) error
+	// RecordTimestamps returns the timestamps for a record.
+	RecordTimestamps(context.Context, *RecordTimestamps) (*RecordTimestampsReply, error)
+	// Inventory returns the tokens of the records in the inventory,
+	// categorized by record state and record status.
+	Inventory(context.Context, *Inventory) (*InventoryReply, error)
+	// PluginWrite executes a plugin write command.
+	PluginWrite(context.Context, *PluginWrite) (*PluginWriteReply, error)
+	// PluginReads executes a batch of plugin read commands.
+	PluginReads(context.Context, *PluginReads) (*PluginReadsReply, error)
+}
+
+// UnimplementedPoliteiadServer can be embedded to have forward compatible implementations.
+type UnimplementedPoliteiadServer struct {
+}
+
+func (*UnimplementedPoliteiadServer) RecordNew(Politeiad_RecordNewServer) error {
+	return status.Errorf(codes.Unimplemented, "method RecordNew not implemented")
+}
+func (*UnimplementedPoliteiadServer) RecordEdit(Politeiad_RecordEditServer) error {
+	return status.Errorf(codes.Unimplemented, "method RecordEdit not implemented")
+}
+func (*UnimplementedPoliteiadServer) Records(*Records, Politeiad_RecordsServer) error {
+	return status.Errorf(codes.Unimplemented, "method Records not implemented")
+}
+func (*UnimplementedPoliteiadServer) RecordTimestamps(context.Context, *RecordTimestamps) (*RecordTimestampsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecordTimestamps not implemented")
+}
+func (*UnimplementedPoliteiadServer) Inventory(context.Context, *Inventory) (*InventoryReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Inventory not implemented")
+}
+func (*UnimplementedPoliteiadServer) PluginWrite(context.Context, *PluginWrite) (*PluginWriteReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PluginWrite not implemented")
+}
+func (*UnimplementedPoliteiadServer) PluginReads(context.Context, *PluginReads) (*PluginReadsReply, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PluginReads not implemented")
+}
+
+func RegisterPoliteiadServer(s *grpc.Server, srv PoliteiadServer) {
+	s.RegisterService(&_Politeiad_serviceDesc, srv)
+}
+
+func _Politeiad_RecordNew_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PoliteiadServer).RecordNew(&politeiadRecordNewServer{stream})
+}
+
+type Politeiad_RecordNewServer interface {
+	SendAndClose(*RecordNewReply) error
+	Recv() (*RecordNewMsg, error)
+	grpc.ServerStream
+}
+
+type politeiadRecordNewServer struct {
+	grpc.ServerStream
+}
+
+func (x *politeiadRecordNewServer) SendAndClose(m *RecordNewReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *politeiadRecordNewServer) Recv() (*RecordNewMsg, error) {
+	m := new(RecordNewMsg)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Politeiad_RecordEdit_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PoliteiadServer).RecordEdit(&politeiadRecordEditServer{stream})
+}
+
+type Politeiad_RecordEditServer interface {
+	SendAndClose(*RecordEditReply) error
+	Recv() (*RecordEditMsg, error)
+	grpc.ServerStream
+}
+
+type politeiadRecordEditServer struct {
+	grpc.ServerStream
+}
+
+func (x *politeiadRecordEditServer) SendAndClose(m *RecordEditReply) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *politeiadRecordEditServer) Recv() (*RecordEditMsg, error) {
+	m := new(RecordEditMsg)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _Politeiad_Records_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Records)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PoliteiadServer).Records(m, &politeiadRecordsServer{stream})
+}
+
+type Politeiad_RecordsServer interface {
+	Send(*RecordsMsg) error
+	grpc.ServerStream
+}
+
+type politeiadRecordsServer struct {
+	grpc.ServerStream
+}
+
+func (x *politeiadRecordsServer) Send(m *RecordsMsg) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Politeiad_RecordTimestamps_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecordTimestamps)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PoliteiadServer).RecordTimestamps(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/politeiad.v2.Politeiad/RecordTimestamps",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PoliteiadServer).RecordTimestamps(ctx, req.(*RecordTimestamps))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Politeiad_Inventory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Inventory)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PoliteiadServer).Inventory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/politeiad.v2.Politeiad/Inventory",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PoliteiadServer).Inventory(ctx, req.(*Inventory))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Politeiad_PluginWrite_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PluginWrite)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PoliteiadServer).PluginWrite(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/politeiad.v2.Politeiad/PluginWrite",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PoliteiadServer).PluginWrite(ctx, req.(*PluginWrite))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Politeiad_PluginReads_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PluginReads)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PoliteiadServer).PluginReads(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/politeiad.v2.Politeiad/PluginReads",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PoliteiadServer).PluginReads(ctx, req.(*PluginReads))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+var _Politeiad_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "politeiad.v2.Politeiad",
+	HandlerType: (*PoliteiadServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "RecordTimestamps",
+			Handler:    _Politeiad_RecordTimestamps_Handler,
+		},
+		{
+			MethodName: "Inventory",
+			Handler:    _Politeiad_Inventory_Handler,
+		},
+		{
+			MethodName: "PluginWrite",
+			Handler:    _Politeiad_PluginWrite_Handler,
+		},
+		{
+			MethodName: "PluginReads",
+			Handler:    _Politeiad_PluginReads_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "RecordNew",
+			Handler:       _Politeiad_RecordNew_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "RecordEdit",
+			Handler:       _Politeiad_RecordEdit_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Records",
+			Handler:       _Politeiad_Records_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "v2.proto",
+}
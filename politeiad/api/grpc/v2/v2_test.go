@@ -0,0 +1,94 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC license that can be found in
+// the LICENSE file.
+
+package v2
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+)
+
+func TestRecordsMsgEncoding(t *testing.T) {
+	// Raw payload that is not valid utf8
+	payload := []byte{0x00, 0xff, 0xfe, 0x01}
+
+	var tests = []struct {
+		name string
+		msg  *RecordsMsg
+	}{
+		{
+			"challenge response",
+			&RecordsMsg{
+				Response: "response",
+			},
+		},
+		{
+			"record",
+			&RecordsMsg{
+				Token: "token",
+				Record: &Record{
+					State:  RecordState_RECORD_STATE_VETTED,
+					Status: RecordStatus_RECORD_STATUS_PUBLIC,
+					Files: []*File{
+						{
+							Name:   "index.md",
+							Mime:   "text/plain; charset=utf-8",
+							Digest: "digest",
+						},
+					},
+				},
+			},
+		},
+		{
+			"file chunk",
+			&RecordsMsg{
+				Chunk: &FileChunk{
+					File: 1,
+					Data: payload,
+				},
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			b, err := proto.Marshal(tc.msg)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			var m RecordsMsg
+			err = proto.Unmarshal(b, &m)
+			if err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if !proto.Equal(&m, tc.msg) {
+				t.Fatalf("got %v, want %v", &m, tc.msg)
+			}
+		})
+	}
+}
+
+func TestFullMethod(t *testing.T) {
+	// The full method names must match the names of the generated
+	// service description since they are used to look up the required
+	// permissions of a call.
+	methods := make(map[string]bool)
+	for _, v := range _Politeiad_serviceDesc.Methods {
+		methods[v.MethodName] = true
+	}
+	for _, v := range _Politeiad_serviceDesc.Streams {
+		methods[v.StreamName] = true
+	}
+	for _, v := range []string{MethodRecordNew, MethodRecordEdit,
+		MethodRecords, MethodRecordTimestamps, MethodInventory,
+		MethodPluginWrite, MethodPluginReads} {
+		if !methods[v] {
+			t.Errorf("method %v not found in service %v", v, ServiceName)
+		}
+	}
+	if _Politeiad_serviceDesc.ServiceName != ServiceName {
+		t.Errorf("got service name %v, want %v",
+			_Politeiad_serviceDesc.ServiceName, ServiceName)
+	}
+}
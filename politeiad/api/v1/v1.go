@@ -9,7 +9,9 @@ import (
 	"encoding/base64"
 	"encoding/hex"
 	"errors"
+	"fmt"
 	"regexp"
+	"strconv"
 
 	"github.com/decred/dcrtime/merkle"
 	"github.com/decred/politeia/politeiad/api/v1/identity"
@@ -192,10 +194,104 @@ type Identity struct {
 	Challenge string `json:"challenge"` // Random challenge
 }
 
-// IdentityReply contains the server public identity.
+// IdentityReply contains the server public identity. The history contains
+// all of the keys that have been used by the server, ordered from oldest to
+// newest. The last key of the history is the current public key. The history
+// is empty if the server identity has never been rotated.
 type IdentityReply struct {
-	Response  string `json:"response"`  // Signature of Challenge
-	PublicKey string `json:"publickey"` // Public key
+	Response  string        `json:"response"`          // Signature of Challenge
+	PublicKey string        `json:"publickey"`         // Public key
+	History   []IdentityKey `json:"history,omitempty"` // Key history
+}
+
+// IdentityKey contains a server public key and the time at which the key
+// became the active signing key. A key that replaced a previous key contains
+// a handover signature. The handover signature is the signature of the
+// previous key on the IdentityHandoverMsg of the new key. The first key of a
+// history has an activation time of 0 and no handover signature.
+type IdentityKey struct {
+	PublicKey  string `json:"publickey"`           // Public key
+	Activation int64  `json:"activation"`          // Unix timestamp
+	Signature  string `json:"signature,omitempty"` // Handover signature
+}
+
+// IdentityHandoverMsg returns the message that is signed by the previous
+// server key when the server identity is rotated.
+func IdentityHandoverMsg(publicKey string, activation int64) []byte {
+	return []byte(publicKey + strconv.FormatInt(activation, 10))
+}
+
+// VerifyIdentityHistory verifies that each key of the provided key history
+// was handed over to by the key that precedes it and that the activation
+// times are increasing.
+func VerifyIdentityHistory(history []IdentityKey) error {
+	for i, v := range history {
+		if i == 0 {
+			continue
+		}
+		prev := history[i-1]
+		if v.Activation <= prev.Activation {
+			return fmt.Errorf("key %v activation is not after the "+
+				"activation of the previous key", v.PublicKey)
+		}
+		pk, err := hex.DecodeString(prev.PublicKey)
+		if err != nil {
+			return fmt.Errorf("key %v: %v", prev.PublicKey, err)
+		}
+		id, err := identity.PublicIdentityFromBytes(pk)
+		if err != nil {
+			return fmt.Errorf("key %v: %v", prev.PublicKey, err)
+		}
+		sb, err := hex.DecodeString(v.Signature)
+		if err != nil || len(sb) != identity.SignatureSize {
+			return fmt.Errorf("key %v: invalid handover signature",
+				v.PublicKey)
+		}
+		var sig [identity.SignatureSize]byte
+		copy(sig[:], sb)
+		msg := IdentityHandoverMsg(v.PublicKey, v.Activation)
+		if !id.VerifyMessage(msg, sig) {
+			return fmt.Errorf("key %v: handover signature does not "+
+				"verify against key %v", v.PublicKey, prev.PublicKey)
+		}
+	}
+	return nil
+}
+
+// IdentityKeyAt returns the public key of the provided key history that was
+// the active signing key at the provided unix timestamp.
+func IdentityKeyAt(history []IdentityKey, timestamp int64) (string, error) {
+	if len(history) == 0 {
+		return "", fmt.Errorf("key history is empty")
+	}
+	key := history[0].PublicKey
+	for _, v := range history[1:] {
+		if v.Activation > timestamp {
+			break
+		}
+		key = v.PublicKey
+	}
+	return key, nil
+}
+
+// IdentityKeysSince returns the public keys of the provided key history that
+// have been the active signing key at some point since the provided unix
+// timestamp, i.e. the key that was active at the timestamp and all keys
+// that replaced it. This is used to verify signatures that the server
+// creates on demand, such as censorship record signatures, which are signed
+// by the key that is active when the record is retrieved.
+func IdentityKeysSince(history []IdentityKey, timestamp int64) ([]string, error) {
+	key, err := IdentityKeyAt(history, timestamp)
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(history))
+	for _, v := range history {
+		if v.PublicKey == key || len(keys) > 0 {
+			keys = append(keys, v.PublicKey)
+		}
+	}
+	return keys, nil
 }
 
 // File describes an individual file that is part of the record.  The
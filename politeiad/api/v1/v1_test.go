@@ -5,8 +5,10 @@
 package v1
 
 import (
+	"encoding/hex"
 	"testing"
 
+	"github.com/decred/politeia/politeiad/api/v1/identity"
 	"github.com/decred/politeia/unittest"
 )
 
@@ -20,3 +22,96 @@ func TestMaps(t *testing.T) {
 		t.Fatalf("RecordStatus: %v", err)
 	}
 }
+
+// newIdentityHistory returns a key history that contains the provided number
+// of keys. The keys are activated 100 seconds apart.
+func newIdentityHistory(t *testing.T, keys int) []IdentityKey {
+	t.Helper()
+
+	history := make([]IdentityKey, 0, keys)
+	var prev *identity.FullIdentity
+	for i := 0; i < keys; i++ {
+		id, err := identity.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		k := IdentityKey{
+			PublicKey: id.Public.String(),
+		}
+		if prev != nil {
+			k.Activation = int64(i * 100)
+			sig := prev.SignMessage(IdentityHandoverMsg(k.PublicKey,
+				k.Activation))
+			k.Signature = hex.EncodeToString(sig[:])
+		}
+		history = append(history, k)
+		prev = id
+	}
+	return history
+}
+
+func TestVerifyIdentityHistory(t *testing.T) {
+	history := newIdentityHistory(t, 3)
+
+	// Valid history
+	err := VerifyIdentityHistory(history)
+	if err != nil {
+		t.Fatalf("valid history: %v", err)
+	}
+
+	// Handover signed by the wrong key
+	bad := append([]IdentityKey{}, history...)
+	bad[2].Signature = bad[1].Signature
+	err = VerifyIdentityHistory(bad)
+	if err == nil {
+		t.Fatalf("invalid handover signature: got nil error")
+	}
+
+	// Activation out of order
+	bad = append([]IdentityKey{}, history...)
+	bad[2].Activation = bad[1].Activation
+	err = VerifyIdentityHistory(bad)
+	if err == nil {
+		t.Fatalf("invalid activation: got nil error")
+	}
+}
+
+func TestIdentityKeyAt(t *testing.T) {
+	history := newIdentityHistory(t, 3)
+
+	var tests = []struct {
+		timestamp int64
+		key       string
+		since     int
+	}{
+		{0, history[0].PublicKey, 3},
+		{99, history[0].PublicKey, 3},
+		{100, history[1].PublicKey, 2},
+		{199, history[1].PublicKey, 2},
+		{200, history[2].PublicKey, 1},
+		{1000, history[2].PublicKey, 1},
+	}
+	for _, v := range tests {
+		key, err := IdentityKeyAt(history, v.timestamp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if key != v.key {
+			t.Errorf("IdentityKeyAt %v: got %v, want %v",
+				v.timestamp, key, v.key)
+		}
+		keys, err := IdentityKeysSince(history, v.timestamp)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(keys) != v.since || keys[0] != v.key {
+			t.Errorf("IdentityKeysSince %v: got %v, want %v keys "+
+				"starting with %v", v.timestamp, keys, v.since, v.key)
+		}
+	}
+
+	_, err := IdentityKeyAt(nil, 0)
+	if err == nil {
+		t.Fatalf("empty history: got nil error")
+	}
+}
@@ -16,13 +16,31 @@ const (
 	RouteRecordEditMetadata = "/recordeditmetadata"
 	RouteRecordSetStatus    = "/recordsetstatus"
 	RouteRecordTimestamps   = "/recordtimestamps"
+	RouteRecordDiff         = "/recorddiff"
 	RouteRecords            = "/records"
 	RouteInventory          = "/inventory"
 	RouteInventoryOrdered   = "/inventoryordered"
+	RouteInventoryQuery     = "/inventoryquery"
 	RoutePluginWrite        = "/pluginwrite"
 	RoutePluginReads        = "/pluginreads"
 	RoutePluginInventory    = "/plugininventory"
 
+	// Admin routes
+	RouteFsck                 = "/fsck"
+	RouteEncryptionKeyRotate  = "/encryptionkeyrotate"
+	RouteEncryptionKeyStatus  = "/encryptionkeystatus"
+	RouteBlobCacheStats       = "/blobcachestats"
+	RouteAnchorStatus         = "/anchorstatus"
+	RouteAnchorDrop           = "/anchordrop"
+	RouteRecordExport         = "/recordexport"
+	RouteRecordImport         = "/recordimport"
+	RoutePluginSettings       = "/pluginsettings"
+	RoutePluginSettingsUpdate = "/pluginsettingsupdate"
+	RouteEvents               = "/events"
+	RouteBackup               = "/backup"
+	RouteCacheRebuild         = "/cacherebuild"
+	RouteCacheRebuildStatus   = "/cacherebuildstatus"
+
 	// ChallengeSize is the size of a request challenge token in bytes.
 	ChallengeSize = 32
 )
@@ -53,7 +71,16 @@ const (
 	ErrorCodePageSizeExceeded        ErrorCodeT = 19
 	ErrorCodeRecordStateInvalid      ErrorCodeT = 20
 	ErrorCodeRecordStatusInvalid     ErrorCodeT = 21
-	ErrorCodeLast                    ErrorCodeT = 22
+	ErrorCodeBundleInvalid           ErrorCodeT = 22
+	ErrorCodeInventoryQueryInvalid   ErrorCodeT = 23
+	ErrorCodeAnchorDropInProgress    ErrorCodeT = 24
+	ErrorCodePluginSettingInvalid    ErrorCodeT = 25
+	ErrorCodeEventResumeInvalid      ErrorCodeT = 26
+	ErrorCodeBackupInProgress        ErrorCodeT = 27
+	ErrorCodeCacheRebuildInProgress  ErrorCodeT = 28
+	ErrorCodeAccessDenied            ErrorCodeT = 29
+	ErrorCodeEventResumeExpired      ErrorCodeT = 30
+	ErrorCodeLast                    ErrorCodeT = 31
 )
 
 var (
@@ -81,6 +108,15 @@ var (
 		ErrorCodePageSizeExceeded:        "page size exceeded",
 		ErrorCodeRecordStateInvalid:      "record state invalid",
 		ErrorCodeRecordStatusInvalid:     "record status invalid",
+		ErrorCodeBundleInvalid:           "record bundle invalid",
+		ErrorCodeInventoryQueryInvalid:   "inventory query invalid",
+		ErrorCodeAnchorDropInProgress:    "anchor drop in progress",
+		ErrorCodePluginSettingInvalid:    "plugin setting invalid",
+		ErrorCodeEventResumeInvalid:      "event resume token invalid",
+		ErrorCodeBackupInProgress:        "backup in progress",
+		ErrorCodeCacheRebuildInProgress:  "cache rebuild in progress",
+		ErrorCodeAccessDenied:            "rpc credential scope insufficient",
+		ErrorCodeEventResumeExpired:      "event resume token expired; resync required",
 	}
 )
 
@@ -372,6 +408,117 @@ type RecordTimestampsReply struct {
 	Files map[string]Timestamp `json:"files"`
 }
 
+// DiffT represents the type of change that is described by a record diff.
+type DiffT uint32
+
+const (
+	// DiffInvalid is an invalid diff type.
+	DiffInvalid DiffT = 0
+
+	// DiffUnchanged indicates that a line was not changed. It is only
+	// used for the lines of a diff hunk.
+	DiffUnchanged DiffT = 1
+
+	// DiffAdded indicates that a file, metadata stream, or line was
+	// added.
+	DiffAdded DiffT = 2
+
+	// DiffRemoved indicates that a file, metadata stream, or line was
+	// removed.
+	DiffRemoved DiffT = 3
+
+	// DiffModified indicates that a file or metadata stream was
+	// modified.
+	DiffModified DiffT = 4
+
+	// DiffLast is used for unit test validation of human readable
+	// diff types.
+	DiffLast = 5
+)
+
+var (
+	// Diffs contains the human readable diff types.
+	Diffs = map[DiffT]string{
+		DiffInvalid:   "invalid",
+		DiffUnchanged: "unchanged",
+		DiffAdded:     "added",
+		DiffRemoved:   "removed",
+		DiffModified:  "modified",
+	}
+)
+
+// DiffLine is a single line of a diff hunk.
+type DiffLine struct {
+	Type DiffT  `json:"type"`
+	Text string `json:"text"`
+}
+
+// DiffHunk is a contiguous section of changed lines along with up to three
+// unchanged lines before and after them. FromLine and ToLine are the 1-based
+// line numbers of the first line of the hunk in the old and new text.
+// FromLines and ToLines are the number of lines of the hunk that are part of
+// the old and new text.
+type DiffHunk struct {
+	FromLine  uint32     `json:"fromline"`
+	FromLines uint32     `json:"fromlines"`
+	ToLine    uint32     `json:"toline"`
+	ToLines   uint32     `json:"tolines"`
+	Lines     []DiffLine `json:"lines"`
+}
+
+// FileDiff describes the changes made to a record file. Hunks contains a line
+// level diff of the file content and is only populated for text/plain files.
+// The digest of a file is empty if the file does not exist in that version.
+type FileDiff struct {
+	Name       string     `json:"name"`
+	Type       DiffT      `json:"type"`
+	MIME       string     `json:"mime"`
+	FromDigest string     `json:"fromdigest,omitempty"`
+	ToDigest   string     `json:"todigest,omitempty"`
+	Hunks      []DiffHunk `json:"hunks,omitempty"`
+}
+
+// MetadataStreamDiff describes the changes made to a record metadata stream.
+// Each JSON value in the metadata stream payload is treated as a line of the
+// diff so that the entries of appended metadata streams, such as status
+// changes, are shown as individual lines.
+type MetadataStreamDiff struct {
+	PluginID string     `json:"pluginid"`
+	StreamID uint32     `json:"streamid"`
+	Type     DiffT      `json:"type"`
+	Hunks    []DiffHunk `json:"hunks"`
+}
+
+// DiffVersion contains the state and status of a record version that is part
+// of a record diff.
+type DiffVersion struct {
+	State     RecordStateT  `json:"state"`
+	Status    RecordStatusT `json:"status"`
+	Version   uint32        `json:"version"`
+	Timestamp int64         `json:"timestamp"` // Last update
+}
+
+// RecordDiff requests the changes between two versions of a record. The from
+// version is required. If a to version is not included the most recent
+// version is used.
+type RecordDiff struct {
+	Challenge string `json:"challenge"`    // Random challenge
+	Token     string `json:"token"`        // Censorship token
+	From      uint32 `json:"from"`         // Record version
+	To        uint32 `json:"to,omitempty"` // Record version
+}
+
+// RecordDiffReply is the reply to the RecordDiff command. Only the files and
+// metadata streams that changed are included. The diffs are sorted by file
+// name and by metadata stream plugin ID and stream ID.
+type RecordDiffReply struct {
+	Response string               `json:"response"` // Challenge response
+	From     DiffVersion          `json:"from"`
+	To       DiffVersion          `json:"to"`
+	Files    []FileDiff           `json:"files"`
+	Metadata []MetadataStreamDiff `json:"metadata"`
+}
+
 const (
 	// RecordsPageSize is the maximum number of records that can be
 	// requested using the Records commands.
@@ -421,7 +568,7 @@ const (
 
 // Inventory requests the tokens of the records in the inventory, categorized
 // by record state and record status. The tokens are ordered by the timestamp
-// of their most recent status change, sorted from newest to oldest.
+// of their most recent update, sorted from newest to oldest.
 //
 // The state, status, and page arguments can be provided to request a specific
 // page of record tokens.
@@ -444,8 +591,8 @@ type InventoryReply struct {
 }
 
 // InventoryOrdered requests a page of record tokens ordered by the timestamp
-// of their most recent status change from newest to oldest. The reply will
-// include tokens for all record statuses.
+// of their most recent update from newest to oldest. The reply will include
+// tokens for all record statuses.
 type InventoryOrdered struct {
 	Challenge string       `json:"challenge"` // Random challenge
 	State     RecordStateT `json:"state"`
@@ -458,6 +605,62 @@ type InventoryOrderedReply struct {
 	Tokens   []string `json:"tokens"`
 }
 
+// InventorySortT represents the timestamp that inventory entries are sorted
+// by.
+type InventorySortT uint32
+
+const (
+	// InventorySortUpdated sorts the inventory by the timestamp of the
+	// most recent record update.
+	InventorySortUpdated InventorySortT = 0
+
+	// InventorySortCreated sorts the inventory by the timestamp of the
+	// record creation.
+	InventorySortCreated InventorySortT = 1
+
+	// InventoryQueryPageSize is the maximum number of inventory entries
+	// that can be requested using the InventoryQuery command.
+	InventoryQueryPageSize uint32 = 100
+)
+
+// InventoryQuery requests a page of inventory entries. The state, status, and
+// attribute filters are optional. Attributes are set by plugins and are
+// namespaced using the plugin ID, e.g. "ticketvote.votestatus". An entry must
+// match all of the provided attributes to be returned. Entries are sorted from
+// newest to oldest unless Ascending is set.
+//
+// Cursor should be set to the cursor that was returned in the reply of the
+// previous page. An empty cursor requests the first page. A Limit of zero
+// requests the maximum page size.
+type InventoryQuery struct {
+	Challenge  string            `json:"challenge"` // Random challenge
+	State      RecordStateT      `json:"state,omitempty"`
+	Status     RecordStatusT     `json:"status,omitempty"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+	Sort       InventorySortT    `json:"sort,omitempty"`
+	Ascending  bool              `json:"ascending,omitempty"`
+	Cursor     string            `json:"cursor,omitempty"`
+	Limit      uint32            `json:"limit,omitempty"`
+}
+
+// InventoryEntry represents a record in the inventory.
+type InventoryEntry struct {
+	Token      string            `json:"token"`
+	State      RecordStateT      `json:"state"`
+	Status     RecordStatusT     `json:"status"`
+	Created    int64             `json:"created"` // Unix timestamp
+	Updated    int64             `json:"updated"` // Unix timestamp
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// InventoryQueryReply is the reply to the InventoryQuery command. The cursor
+// will be empty if there are no more entries.
+type InventoryQueryReply struct {
+	Response string           `json:"response"` // Challenge response
+	Entries  []InventoryEntry `json:"entries"`
+	Cursor   string           `json:"cursor,omitempty"`
+}
+
 // PluginCmd represents plugin command and the command payload. A token is
 // required for all plugin writes, but is optional for reads.
 type PluginCmd struct {
@@ -531,3 +734,529 @@ type PluginInventoryReply struct {
 	Response string   `json:"response"` // Challenge response
 	Plugins  []Plugin `json:"plugins"`
 }
+
+// PluginSettingsChange is an entry in the audit trail of the plugin settings
+// updates that were made at runtime. Previous contains the values of the
+// updated settings prior to the update. User is the name of the RPC
+// credential that made the update.
+type PluginSettingsChange struct {
+	Settings  []PluginSetting `json:"settings"`  // Updated settings
+	Previous  []PluginSetting `json:"previous"`  // Prior values
+	Reason    string          `json:"reason"`    // Reason for the update
+	User      string          `json:"user"`      // RPC credential name
+	Timestamp int64           `json:"timestamp"` // Unix timestamp
+}
+
+// PluginSettings returns the settings of a plugin along with the audit trail
+// of the plugin settings updates that were made at runtime. The audit trail
+// is ordered from oldest to newest.
+//
+// This route requires admin privileges.
+type PluginSettings struct {
+	Challenge string `json:"challenge"` // Random challenge
+	PluginID  string `json:"pluginid"`
+}
+
+// PluginSettingsReply is the reply to the PluginSettings command.
+type PluginSettingsReply struct {
+	Response string                 `json:"response"` // Challenge response
+	Plugin   Plugin                 `json:"plugin"`
+	History  []PluginSettingsChange `json:"history"`
+}
+
+// PluginSettingsUpdate updates the settings of a plugin at runtime. Only the
+// provided settings are updated. The plugin validates the new values. An
+// ErrorCodePluginSettingInvalid is returned if any of the settings are
+// invalid or cannot be updated at runtime, in which case none of the settings
+// are updated.
+//
+// The update is recorded in the plugin settings audit trail and takes
+// precedence over the plugin settings that politeiad is started with.
+//
+// This route requires admin privileges.
+type PluginSettingsUpdate struct {
+	Challenge string          `json:"challenge"` // Random challenge
+	PluginID  string          `json:"pluginid"`
+	Settings  []PluginSetting `json:"settings"`
+	Reason    string          `json:"reason"`
+}
+
+// PluginSettingsUpdateReply is the reply to the PluginSettingsUpdate command.
+// It contains the updated plugin settings.
+type PluginSettingsUpdateReply struct {
+	Response string `json:"response"` // Challenge response
+	Plugin   Plugin `json:"plugin"`
+}
+
+const (
+	// EventsQueryChallenge and EventsQueryResume are the URL query
+	// parameters of the Events request.
+	EventsQueryChallenge = "challenge"
+	EventsQueryResume    = "resume"
+)
+
+// Events opens a websocket connection that streams the politeiad backend
+// events. The events are published after a change has been successfully
+// saved and the post plugin hooks have been executed. This includes changes
+// that were not made through the API, such as a ticket vote finishing at the
+// vote end height.
+//
+// The request is a websocket upgrade GET request. The Challenge and Resume
+// fields are provided using the EventsQueryChallenge and EventsQueryResume
+// URL query parameters. The first message that is sent by the server is an
+// EventsReply. Every subsequent message is an Event.
+//
+// Resume is the ID of the last event that the client received. The events
+// that were published after it are sent before any new events. An empty
+// Resume streams new events only. ErrorCodeEventResumeInvalid is returned if
+// Resume is malformed.
+//
+// The events are only kept in memory and only the most recent events are
+// kept, so a stream can only be resumed within the lifetime of a single
+// politeiad process. ErrorCodeEventResumeExpired is returned if the events
+// after Resume are no longer available, e.g. because politeiad has been
+// restarted, in which case the client must resync its state by other means
+// and reconnect without a Resume. This includes the events that were
+// published while politeiad was starting up. The server closes the
+// connection if the client falls too far behind. The client should reconnect
+// using the ID of the last event that it received.
+//
+// This route requires admin privileges.
+type Events struct {
+	Challenge string `json:"challenge"` // Random challenge
+	Resume    string `json:"resume,omitempty"`
+}
+
+// EventsReply is the first message that is sent on the event stream.
+type EventsReply struct {
+	Response string `json:"response"` // Challenge response
+}
+
+// EventTypeT represents a backend event type.
+type EventTypeT uint32
+
+const (
+	// EventTypeInvalid is an invalid event type.
+	EventTypeInvalid EventTypeT = 0
+
+	// EventTypeRecordNew is published when a new record is saved.
+	EventTypeRecordNew EventTypeT = 1
+
+	// EventTypeRecordEdit is published when a record is edited.
+	EventTypeRecordEdit EventTypeT = 2
+
+	// EventTypeRecordEditMetadata is published when the metadata of a
+	// record is edited.
+	EventTypeRecordEditMetadata EventTypeT = 3
+
+	// EventTypeRecordSetStatus is published when the status of a
+	// record is updated.
+	EventTypeRecordSetStatus EventTypeT = 4
+
+	// EventTypeRecordImport is published when a record bundle is
+	// imported as a new record.
+	EventTypeRecordImport EventTypeT = 5
+
+	// EventTypePluginWrite is published when a plugin write command is
+	// executed. The event includes the command payload and reply.
+	EventTypePluginWrite EventTypeT = 6
+
+	// EventTypePlugin is published by a plugin. The event name and
+	// payload are defined by the plugin. Ex, the ticketvote plugin
+	// publishes an event when a vote finishes.
+	EventTypePlugin EventTypeT = 7
+
+	// EventTypePluginSettingsUpdate is published when the settings of
+	// a plugin are updated at runtime. The event includes the plugin
+	// ID. The updated settings are returned by the PluginInventory and
+	// PluginSettings routes.
+	EventTypePluginSettingsUpdate EventTypeT = 8
+
+	// EventTypeLast is used for unit test validation of human readable
+	// event types.
+	EventTypeLast = 9
+)
+
+var (
+	// EventTypes contains the human readable event types.
+	EventTypes = map[EventTypeT]string{
+		EventTypeInvalid:              "invalid",
+		EventTypeRecordNew:            "record new",
+		EventTypeRecordEdit:           "record edit",
+		EventTypeRecordEditMetadata:   "record edit metadata",
+		EventTypeRecordSetStatus:      "record set status",
+		EventTypeRecordImport:         "record import",
+		EventTypePluginWrite:          "plugin write",
+		EventTypePlugin:               "plugin",
+		EventTypePluginSettingsUpdate: "plugin settings update",
+	}
+)
+
+// Event is a politeiad backend event. The ID is the resume token of the
+// event stream.
+//
+// The record events include the state, status, version, and metadata streams
+// of the updated record. The record files are not included. The plugin
+// events include the plugin ID and the plugin command or plugin event name.
+type Event struct {
+	ID        string     `json:"id"`
+	Type      EventTypeT `json:"type"`
+	Timestamp int64      `json:"timestamp"` // Unix timestamp
+	Token     string     `json:"token,omitempty"`
+
+	// Record event fields
+	State    RecordStateT     `json:"state,omitempty"`
+	Status   RecordStatusT    `json:"status,omitempty"`
+	Version  uint32           `json:"version,omitempty"`
+	Metadata []MetadataStream `json:"metadata,omitempty"`
+
+	// Plugin event fields
+	PluginID string `json:"pluginid,omitempty"`
+	Name     string `json:"name,omitempty"` // Plugin command or event name
+	Payload  string `json:"payload,omitempty"`
+	Reply    string `json:"reply,omitempty"`
+}
+
+// FsckIssue describes an issue that was found during a backend filesystem
+// check. Token will not be populated if the issue does not correspond to a
+// specific record.
+type FsckIssue struct {
+	Type        string `json:"type"`            // Human readable issue type
+	Token       string `json:"token,omitempty"` // Censorship token
+	Description string `json:"description"`
+	Repaired    bool   `json:"repaired"`
+}
+
+// Fsck performs a filesystem check on the backend and returns a report of the
+// issues that were found. This route does not repair any issues. Repairs
+// require that the backend not be accepting writes and can only be performed
+// on startup using the politeiad --fsckrepair flag.
+//
+// This route requires admin privileges.
+type Fsck struct {
+	Challenge string `json:"challenge"` // Random challenge
+}
+
+// FsckReply is the reply to the Fsck command.
+type FsckReply struct {
+	Response string      `json:"response"` // Challenge response
+	Records  uint32      `json:"records"`  // Number of records checked
+	Blobs    uint64      `json:"blobs"`    // Number of blobs checked
+	Issues   []FsckIssue `json:"issues"`
+}
+
+// EncryptionKeys describes the state of the keys that are used to encrypt
+// unvetted data at rest. New data is always encrypted using the active key.
+// Prior keys are retained until all data that was encrypted using them has
+// been re-encrypted using the active key.
+type EncryptionKeys struct {
+	ActiveKeyID  uint32   `json:"activekeyid"`  // Key used for new data
+	KeyIDs       []uint32 `json:"keyids"`       // Keys that are not retired
+	Reencrypting bool     `json:"reencrypting"` // Re-encryption in progress
+	Reencrypted  uint64   `json:"reencrypted"`  // Blobs re-encrypted
+}
+
+// EncryptionKeyRotate creates a new key for encrypting unvetted data at rest.
+// The existing data is re-encrypted using the new key in the background while
+// politeiad remains online. Prior keys are retired once they are no longer
+// used. The progress can be checked using the EncryptionKeyStatus command.
+//
+// KeyFile is the path, on the politeiad host, of the file that contains the
+// new key. The file is created using a new random key if it does not exist.
+// politeiad uses a default key file in its data dir if one is not provided.
+// The key file must be kept until the key has been retired.
+//
+// This route requires admin privileges.
+type EncryptionKeyRotate struct {
+	Challenge string `json:"challenge"`         // Random challenge
+	KeyFile   string `json:"keyfile,omitempty"` // New key file path
+}
+
+// EncryptionKeyRotateReply is the reply to the EncryptionKeyRotate command.
+type EncryptionKeyRotateReply struct {
+	Response string         `json:"response"` // Challenge response
+	Keys     EncryptionKeys `json:"keys"`
+}
+
+// EncryptionKeyStatus returns the status of the keys that are used to encrypt
+// unvetted data at rest.
+//
+// This route requires admin privileges.
+type EncryptionKeyStatus struct {
+	Challenge string `json:"challenge"` // Random challenge
+}
+
+// EncryptionKeyStatusReply is the reply to the EncryptionKeyStatus command.
+type EncryptionKeyStatusReply struct {
+	Response string         `json:"response"` // Challenge response
+	Keys     EncryptionKeys `json:"keys"`
+}
+
+// Anchor describes an anchor drop, i.e. the timestamping of the root hashes
+// of all trees that had unanchored leaves at the time of the drop.
+//
+// Status will be one of the following: submitted, sent, confirmed, failed. A
+// drop is sent once the anchor transaction has been broadcast and is
+// confirmed once the transaction has received enough confirmations and the
+// anchor records have been saved. Anchored is the number of trees whose
+// anchor has been confirmed.
+type Anchor struct {
+	Status      string   `json:"status"`
+	Trees       uint32   `json:"trees"`       // Trees included in the drop
+	Anchored    uint32   `json:"anchored"`    // Trees with confirmed anchor
+	MerkleRoots []string `json:"merkleroots"` // Anchor merkle roots
+	TxIDs       []string `json:"txids"`       // Anchor transaction IDs
+	Attempts    uint32   `json:"attempts"`    // Verify attempts
+	MaxAttempts uint32   `json:"maxattempts"` // Attempts before timeout
+	Started     int64    `json:"started"`     // Unix timestamp
+	LastCheck   int64    `json:"lastcheck"`   // Unix timestamp
+	Completed   int64    `json:"completed"`   // Unix timestamp
+	Error       string   `json:"error,omitempty"`
+}
+
+// AnchorTree describes a tree that contains leaves that have not been
+// anchored yet. AnchoredSize and Anchored will be zero if the tree has never
+// been anchored.
+type AnchorTree struct {
+	Token        string `json:"token"`        // Censorship token
+	Leaves       uint64 `json:"leaves"`       // Unanchored leaves
+	AnchoredSize uint64 `json:"anchoredsize"` // Tree size of last anchor
+	Anchored     int64  `json:"anchored"`     // Chain timestamp of last anchor
+}
+
+// Anchors describes the state of the anchoring of the backend data. The anchor
+// drop history is kept in memory by politeiad and does not persist across
+// restarts.
+type Anchors struct {
+	Type      string       `json:"type"`     // Anchor service type
+	Schedule  string       `json:"schedule"` // Anchor drop schedule
+	NextDrop  int64        `json:"nextdrop"` // Unix timestamp
+	Dropping  bool         `json:"dropping"` // Anchor drop in progress
+	LastRun   int64        `json:"lastrun"`  // Unix timestamp
+	LastError string       `json:"lasterror,omitempty"`
+	Pending   []AnchorTree `json:"pending"` // Trees with unanchored leaves
+	Drops     []Anchor     `json:"drops"`   // Most recent first
+}
+
+// BlobCache contains the statistics of the in-memory cache of vetted blobs.
+// Enabled will be false and the stats will be zero if the cache has been
+// disabled. The stats are reset when politeiad is restarted.
+type BlobCache struct {
+	Enabled   bool   `json:"enabled"`   // Cache is enabled
+	Hits      uint64 `json:"hits"`      // Blobs returned from the cache
+	Misses    uint64 `json:"misses"`    // Blobs not found in the cache
+	Evictions uint64 `json:"evictions"` // Blobs evicted to free up space
+	Entries   int    `json:"entries"`   // Blobs currently in the cache
+	Size      int64  `json:"size"`      // Current size in bytes
+	MaxSize   int64  `json:"maxsize"`   // Max size in bytes
+}
+
+// BlobCacheStats returns the statistics of the in-memory cache of vetted
+// blobs.
+//
+// This route requires admin privileges.
+type BlobCacheStats struct {
+	Challenge string `json:"challenge"` // Random challenge
+}
+
+// BlobCacheStatsReply is the reply to the BlobCacheStats command.
+type BlobCacheStatsReply struct {
+	Response string    `json:"response"` // Challenge response
+	Cache    BlobCache `json:"cache"`
+}
+
+// AnchorStatus returns the status of the anchoring of the backend data.
+//
+// This route requires admin privileges.
+type AnchorStatus struct {
+	Challenge string `json:"challenge"` // Random challenge
+}
+
+// AnchorStatusReply is the reply to the AnchorStatus command.
+type AnchorStatusReply struct {
+	Response string  `json:"response"` // Challenge response
+	Anchors  Anchors `json:"anchors"`
+}
+
+// AnchorDrop drops an anchor for all data that has not been anchored yet
+// without waiting for the anchor schedule. The anchor is dropped in the
+// background. The progress can be checked using the AnchorStatus command. An
+// ErrorCodeAnchorDropInProgress is returned if a prior anchor drop has not
+// finished dropping.
+//
+// This route requires admin privileges.
+type AnchorDrop struct {
+	Challenge string `json:"challenge"` // Random challenge
+}
+
+// AnchorDropReply is the reply to the AnchorDrop command.
+type AnchorDropReply struct {
+	Response string  `json:"response"` // Challenge response
+	Anchors  Anchors `json:"anchors"`
+}
+
+const (
+	// RecordBundleVersion is the version of the record bundle format.
+	RecordBundleVersion uint32 = 1
+)
+
+// BundleBlob contains a single blob of a record bundle along with the tlog
+// leaf data that is required to restore it.
+//
+// Digest is the tlog leaf value, which is the SHA256 digest of the decoded
+// blob entry data. State is the record state at the time that the blob was
+// saved. PlainText indicates that an unvetted blob is also saved as plain
+// text because it is part of the public version of the record. Deleted
+// indicates that the blob was deleted from the backend, such as the files of
+// a censored record. DataHint and Data are not populated for deleted blobs.
+type BundleBlob struct {
+	LeafIndex      int64        `json:"leafindex"`
+	Digest         string       `json:"digest"`
+	DataDescriptor string       `json:"datadescriptor"`
+	State          RecordStateT `json:"state,omitempty"`
+	PlainText      bool         `json:"plaintext,omitempty"`
+	Deleted        bool         `json:"deleted,omitempty"`
+	DataHint       string       `json:"datahint,omitempty"` // Base64 encoded
+	Data           string       `json:"data,omitempty"`     // Base64 encoded
+}
+
+// BundleTimestamps contains the timestamps of a single record version.
+type BundleTimestamps struct {
+	RecordMetadata Timestamp `json:"recordmetadata"`
+
+	// map[pluginID]map[streamID]Timestamp
+	Metadata map[string]map[uint32]Timestamp `json:"metadata"`
+
+	// map[filename]Timestamp
+	Files map[string]Timestamp `json:"files"`
+}
+
+// RecordBundle contains the full contents of a record in a single portable
+// archive. This includes every version of the record, the timestamps of every
+// version, and every blob of the record in the order that they were appended
+// to the record's tlog tree. The blobs include the record content, the plugin
+// data, and the dcrtime anchors.
+//
+// ServerPublicKey is the politeiad public key of the server that exported the
+// record. It can be used to verify the censorship record signatures.
+type RecordBundle struct {
+	Version         uint32                      `json:"version"`
+	ServerPublicKey string                      `json:"serverpublickey"`
+	Token           string                      `json:"token"`     // Censorship token
+	Timestamp       int64                       `json:"timestamp"` // Export time
+	Records         []Record                    `json:"records"`   // All versions
+	Timestamps      map[uint32]BundleTimestamps `json:"timestamps"`
+	Blobs           []BundleBlob                `json:"blobs"` // Leaf ordered
+}
+
+// RecordExport exports the full contents of a record as a record bundle.
+//
+// This route requires admin privileges.
+type RecordExport struct {
+	Challenge string `json:"challenge"` // Random challenge
+	Token     string `json:"token"`     // Censorship token
+}
+
+// RecordExportReply is the reply to the RecordExport command.
+type RecordExportReply struct {
+	Response string       `json:"response"` // Challenge response
+	Bundle   RecordBundle `json:"bundle"`
+}
+
+// RecordImport imports a record bundle as a new record. All bundle digests,
+// the record content of every version, and the timestamp inclusion proofs are
+// verified prior to the import. The record tokens are derived by the backend,
+// so the imported record is assigned a new token. The plugin data of the
+// imported record is not modified and may still reference the original token.
+//
+// This route requires admin privileges.
+type RecordImport struct {
+	Challenge string       `json:"challenge"` // Random challenge
+	Bundle    RecordBundle `json:"bundle"`
+}
+
+// RecordImportReply is the reply to the RecordImport command.
+type RecordImportReply struct {
+	Response string `json:"response"` // Challenge response
+	Token    string `json:"token"`    // Censorship token of new record
+}
+
+// Backup saves a point-in-time backup of the backend to the politeiad backup
+// directory. Writes are blocked while the backup is being made. Reads are
+// not affected. The backup includes the tlog trees, the key-value store, and
+// the data dir files, which includes the plugin caches. Unvetted data is
+// encrypted using a key that is derived from the tlog signing key.
+//
+// When trillian is used as the tlog, the trillian database must be backed up
+// using the politeiad backup hook, which is run while writes are blocked.
+//
+// This route requires admin privileges.
+type Backup struct {
+	Challenge string `json:"challenge"` // Random challenge
+}
+
+// BackupReply is the reply to the Backup command. Name is the name of the
+// backup directory inside of the politeiad backup directory.
+type BackupReply struct {
+	Response  string `json:"response"`  // Challenge response
+	Name      string `json:"name"`      // Backup name
+	Timestamp int64  `json:"timestamp"` // Unix time of backup
+	Trees     uint64 `json:"trees"`     // Number of tlog trees
+	Blobs     uint64 `json:"blobs"`     // Number of kv blobs
+	Files     uint64 `json:"files"`     // Number of data dir files
+}
+
+// PluginCacheRebuild describes the rebuild of the caches of a single plugin.
+// Records is the number of records that the plugin must process. Rebuilt is
+// the number of records that have been processed so far. Error will be
+// populated if the rebuild failed.
+type PluginCacheRebuild struct {
+	PluginID string `json:"pluginid"`
+	Records  uint32 `json:"records"`
+	Rebuilt  uint32 `json:"rebuilt"`
+	Done     bool   `json:"done"`
+	Error    string `json:"error,omitempty"`
+}
+
+// CacheRebuildState describes the state of the most recent plugin cache
+// rebuild. All fields will be set to their zero values if a cache rebuild has
+// not been run since politeiad was started.
+type CacheRebuildState struct {
+	InProgress bool                 `json:"inprogress"`
+	Started    int64                `json:"started"`  // Unix time
+	Finished   int64                `json:"finished"` // Unix time
+	Plugins    []PluginCacheRebuild `json:"plugins"`
+}
+
+// CacheRebuild deletes the caches of the provided plugins and rebuilds them
+// from the plugin data that has been saved to the backend. The caches of all
+// plugins are rebuilt if no plugin IDs are provided. The rebuild is run in
+// the background. Writes are blocked until the rebuild has finished. The
+// CacheRebuildStatus command can be used to track the progress of the
+// rebuild.
+//
+// This route requires admin privileges.
+type CacheRebuild struct {
+	Challenge string   `json:"challenge"` // Random challenge
+	PluginIDs []string `json:"pluginids,omitempty"`
+}
+
+// CacheRebuildReply is the reply to the CacheRebuild command.
+type CacheRebuildReply struct {
+	Response string            `json:"response"` // Challenge response
+	Rebuild  CacheRebuildState `json:"rebuild"`
+}
+
+// CacheRebuildStatus returns the state of the most recent plugin cache
+// rebuild.
+//
+// This route requires admin privileges.
+type CacheRebuildStatus struct {
+	Challenge string `json:"challenge"` // Random challenge
+}
+
+// CacheRebuildStatusReply is the reply to the CacheRebuildStatus command.
+type CacheRebuildStatusReply struct {
+	Response string            `json:"response"` // Challenge response
+	Rebuild  CacheRebuildState `json:"rebuild"`
+}
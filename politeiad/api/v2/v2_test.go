@@ -23,4 +23,12 @@ func TestMaps(t *testing.T) {
 	if err != nil {
 		t.Fatalf("RecordStatuses: %v", err)
 	}
+	err = unittest.TestGenericConstMap(Diffs, DiffLast)
+	if err != nil {
+		t.Fatalf("Diffs: %v", err)
+	}
+	err = unittest.TestGenericConstMap(EventTypes, EventTypeLast)
+	if err != nil {
+		t.Fatalf("EventTypes: %v", err)
+	}
 }
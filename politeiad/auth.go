@@ -0,0 +1,277 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	v1 "github.com/decred/politeia/politeiad/api/v1"
+	v2 "github.com/decred/politeia/politeiad/api/v2"
+	"github.com/decred/politeia/util"
+)
+
+const (
+	// RPC credential scopes. A scope grants a credential the permission
+	// to access a set of routes.
+	scopeRead         = "read"
+	scopeRecordWrite  = "write"
+	scopeStatusChange = "status"
+	scopeAdmin        = "admin"
+
+	// scopePluginPrefix is the prefix of a plugin write scope. A plugin
+	// write scope has the format plugin:<pluginID> and grants the
+	// permission to execute the write commands of the plugin. The
+	// plugin ID can be set to scopePluginAll to grant the permission
+	// for all plugins.
+	scopePluginPrefix = "plugin:"
+	scopePluginAll    = "*"
+)
+
+// rpcCredential represents a named politeiad RPC credential and the
+// permissions that have been granted to it.
+type rpcCredential struct {
+	name        string
+	permissions map[permission]struct{}
+	plugins     map[string]struct{} // Plugins with write access
+	pluginsAll  bool                // Write access to all plugins
+}
+
+// allowed returns whether the credential has been granted the provided
+// permission. The plugin write permission only requires that the credential
+// has write access to at least one plugin. The handler must verify that the
+// credential has write access to the plugin that is being written to.
+func (c *rpcCredential) allowed(perm permission) bool {
+	switch perm {
+	case permissionPublic:
+		return true
+	case permissionPluginWrite:
+		return c.pluginsAll || len(c.plugins) > 0
+	}
+	_, ok := c.permissions[perm]
+	return ok
+}
+
+// pluginWriteAllowed returns whether the credential has write access to the
+// provided plugin.
+func (c *rpcCredential) pluginWriteAllowed(pluginID string) bool {
+	if c.pluginsAll {
+		return true
+	}
+	_, ok := c.plugins[pluginID]
+	return ok
+}
+
+// newRPCCredentialFull returns a credential that has been granted all
+// permissions.
+func newRPCCredentialFull(name string) *rpcCredential {
+	return &rpcCredential{
+		name: name,
+		permissions: map[permission]struct{}{
+			permissionAdmin:        {},
+			permissionRead:         {},
+			permissionRecordWrite:  {},
+			permissionStatusChange: {},
+		},
+		plugins:    map[string]struct{}{},
+		pluginsAll: true,
+	}
+}
+
+// newRPCCredentialScoped returns a credential that has been granted the
+// permissions of the provided comma separated list of scopes.
+func newRPCCredentialScoped(name, scopes string) (*rpcCredential, error) {
+	c := rpcCredential{
+		name:        name,
+		permissions: make(map[permission]struct{}),
+		plugins:     make(map[string]struct{}),
+	}
+	for _, v := range strings.Split(scopes, ",") {
+		switch {
+		case v == scopeRead:
+			c.permissions[permissionRead] = struct{}{}
+		case v == scopeRecordWrite:
+			c.permissions[permissionRecordWrite] = struct{}{}
+		case v == scopeStatusChange:
+			c.permissions[permissionStatusChange] = struct{}{}
+		case v == scopeAdmin:
+			c.permissions[permissionAdmin] = struct{}{}
+		case strings.HasPrefix(v, scopePluginPrefix):
+			pluginID := strings.TrimPrefix(v, scopePluginPrefix)
+			switch pluginID {
+			case "":
+				return nil, fmt.Errorf("plugin scope missing plugin id")
+			case scopePluginAll:
+				c.pluginsAll = true
+			default:
+				c.plugins[pluginID] = struct{}{}
+			}
+		default:
+			return nil, fmt.Errorf("invalid scope '%v'", v)
+		}
+	}
+	return &c, nil
+}
+
+// rpcCredentials contains the RPC credentials that are allowed to access the
+// politeiad routes.
+type rpcCredentials struct {
+	users     map[string]*rpcCredential // [user]credential
+	passwords map[string]string         // [user]password
+	certs     map[string]*rpcCredential // [commonName]credential
+}
+
+// newRPCCredentials returns the RPC credentials that are specified in the
+// provided config. The rpcuser and rpcpass credential is granted all
+// permissions.
+func newRPCCredentials(cfg *config) (*rpcCredentials, error) {
+	rc := rpcCredentials{
+		users: map[string]*rpcCredential{
+			cfg.RPCUser: newRPCCredentialFull(cfg.RPCUser),
+		},
+		passwords: map[string]string{
+			cfg.RPCUser: cfg.RPCPass,
+		},
+		certs: make(map[string]*rpcCredential),
+	}
+
+	// Parse the named credentials. The credentials will be in the
+	// format: user:pass:scope[,scope]...
+	for _, v := range cfg.RPCCredentials {
+		s := strings.SplitN(v, ":", 3)
+		if len(s) != 3 || s[0] == "" || s[1] == "" {
+			return nil, fmt.Errorf("failed to parse rpc credential; format " +
+				"should be 'user:pass:scope[,scope]...'")
+		}
+		var (
+			user   = s[0]
+			pass   = s[1]
+			scopes = s[2]
+		)
+		if _, ok := rc.users[user]; ok {
+			return nil, fmt.Errorf("duplicate rpc credential user '%v'", user)
+		}
+		c, err := newRPCCredentialScoped(user, scopes)
+		if err != nil {
+			return nil, fmt.Errorf("rpc credential '%v': %v", user, err)
+		}
+		rc.users[user] = c
+		rc.passwords[user] = pass
+	}
+
+	// Parse the client certificate credentials. The credentials will
+	// be in the format: commonName:scope[,scope]...
+	for _, v := range cfg.RPCClientCerts {
+		s := strings.SplitN(v, ":", 2)
+		if len(s) != 2 || s[0] == "" {
+			return nil, fmt.Errorf("failed to parse rpc client cert; format " +
+				"should be 'commonname:scope[,scope]...'")
+		}
+		var (
+			commonName = s[0]
+			scopes     = s[1]
+		)
+		if _, ok := rc.certs[commonName]; ok {
+			return nil, fmt.Errorf("duplicate rpc client cert '%v'", commonName)
+		}
+		c, err := newRPCCredentialScoped(commonName, scopes)
+		if err != nil {
+			return nil, fmt.Errorf("rpc client cert '%v': %v", commonName, err)
+		}
+		rc.certs[commonName] = c
+	}
+
+	return &rc, nil
+}
+
+// authenticate returns the credential of a caller. A verified client
+// certificate takes precedence over the basic authentication credentials.
+// False is returned if the caller could not be authenticated.
+func (rc *rpcCredentials) authenticate(state *tls.ConnectionState, user, pass string, ok bool) (*rpcCredential, bool) {
+	// The verified chains are only populated when the client
+	// certificate has been verified against the client CA.
+	if state != nil && len(state.VerifiedChains) > 0 &&
+		len(state.VerifiedChains[0]) > 0 {
+		cn := state.VerifiedChains[0][0].Subject.CommonName
+		if c, found := rc.certs[cn]; found {
+			return c, true
+		}
+	}
+	if !ok {
+		return nil, false
+	}
+	c, found := rc.users[user]
+	if !found {
+		return nil, false
+	}
+	p := rc.passwords[user]
+	if subtle.ConstantTimeCompare([]byte(pass), []byte(p)) != 1 {
+		return nil, false
+	}
+	return c, true
+}
+
+// credentialKey is the context key for the RPC credential of a request.
+type credentialKey struct{}
+
+// credentialFromContext returns the RPC credential that is contained in the
+// provided context. A credential without any permissions is returned if the
+// context does not contain a credential.
+func credentialFromContext(ctx context.Context) *rpcCredential {
+	c, ok := ctx.Value(credentialKey{}).(*rpcCredential)
+	if !ok {
+		return &rpcCredential{}
+	}
+	return c
+}
+
+// respondWithForbidden sends a forbidden reply to a request whose credential
+// has not been granted the permission that is required by the route.
+func respondWithForbidden(w http.ResponseWriter, r *http.Request, c *rpcCredential, errContext string) {
+	log.Infof("%v Forbidden access for: %v %v",
+		remoteAddr(r), c.name, r.URL.Path)
+
+	if !strings.HasPrefix(r.URL.Path, v2.APIRoute+"/") {
+		util.RespondWithJSON(w, http.StatusForbidden, v1.UserErrorReply{
+			ErrorCode: v1.ErrorStatusInvalidRPCCredentials,
+		})
+		return
+	}
+	util.RespondWithJSON(w, http.StatusForbidden, v2.UserErrorReply{
+		ErrorCode:    v2.ErrorCodeAccessDenied,
+		ErrorContext: errContext,
+	})
+}
+
+// tlsConfig returns the TLS config that is used by the politeiad listeners.
+// Client certificates are verified against the client CA when client
+// certificate authentication has been enabled.
+func (p *politeia) tlsConfig() (*tls.Config, error) {
+	tlsConfig := &tls.Config{}
+	if p.cfg.RPCClientCA == "" {
+		return tlsConfig, nil
+	}
+	b, err := ioutil.ReadFile(p.cfg.RPCClientCA)
+	if err != nil {
+		return nil, fmt.Errorf("read client ca: %v", err)
+	}
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("no certificates found in %v",
+			p.cfg.RPCClientCA)
+	}
+	tlsConfig.ClientCAs = certPool
+	tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	if p.cfg.RPCRequireClientCert {
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
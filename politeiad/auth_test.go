@@ -0,0 +1,508 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	v2 "github.com/decred/politeia/politeiad/api/v2"
+	"github.com/gorilla/mux"
+)
+
+// permissions contains all permissions that can be granted to a credential.
+var permissions = []permission{
+	permissionPublic,
+	permissionAdmin,
+	permissionRead,
+	permissionRecordWrite,
+	permissionStatusChange,
+	permissionPluginWrite,
+}
+
+// newTestLogRotator initializes the log rotator using a log file in a
+// temporary directory and returns a closure that closes the log rotator and
+// removes the directory when invoked. The politeiad loggers can not be used
+// before the log rotator has been initialized.
+func newTestLogRotator(t *testing.T) func() {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "politeiad.log")
+	if err != nil {
+		t.Fatal(err)
+	}
+	initLogRotator(filepath.Join(dir, "politeiad.test.log"))
+
+	return func() {
+		logRotator.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+// newTestRPCCredentials returns the RPC credentials that are used for testing.
+// The rpcuser is admin, reader has the read scope, commenter has the read and
+// comments plugin scopes, and the writer client cert has the write and status
+// scopes.
+func newTestRPCCredentials(t *testing.T) *rpcCredentials {
+	t.Helper()
+
+	rc, err := newRPCCredentials(&config{
+		RPCUser: "admin",
+		RPCPass: "adminpass",
+		RPCCredentials: []string{
+			"reader:readerpass:read",
+			"commenter:commenterpass:read,plugin:comments",
+		},
+		RPCClientCerts: []string{
+			"writer:write,status",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return rc
+}
+
+// newTestTLSState returns a TLS connection state that contains a client
+// certificate with the provided common name. The certificate is only
+// included in the verified chains if verified is true.
+func newTestTLSState(commonName string, verified bool) *tls.ConnectionState {
+	cert := &x509.Certificate{
+		Subject: pkix.Name{
+			CommonName: commonName,
+		},
+	}
+	state := tls.ConnectionState{
+		PeerCertificates: []*x509.Certificate{cert},
+	}
+	if verified {
+		state.VerifiedChains = [][]*x509.Certificate{{cert}}
+	}
+	return &state
+}
+
+func TestNewRPCCredentialScoped(t *testing.T) {
+	var tests = []struct {
+		name        string
+		scopes      string
+		wantErr     bool
+		wantPerms   []permission
+		wantPlugins []string
+		wantAll     bool
+	}{
+		{"read", "read", false, []permission{permissionRead}, nil, false},
+		{"all record scopes", "read,write,status,admin", false,
+			[]permission{permissionRead, permissionRecordWrite,
+				permissionStatusChange, permissionAdmin}, nil, false},
+		{"plugin scopes", "plugin:comments,plugin:ticketvote", false,
+			nil, []string{"comments", "ticketvote"}, false},
+		{"all plugins scope", "plugin:*", false, nil, nil, true},
+		{"duplicate scope", "read,read", false,
+			[]permission{permissionRead}, nil, false},
+		{"empty", "", true, nil, nil, false},
+		{"empty plugin scope", "plugin:", true, nil, nil, false},
+		{"empty plugin scope with others", "read,plugin:", true,
+			nil, nil, false},
+		{"unknown scope", "superuser", true, nil, nil, false},
+		{"uppercase scope", "READ", true, nil, nil, false},
+		{"trailing comma", "read,", true, nil, nil, false},
+		{"whitespace", "read, write", true, nil, nil, false},
+		{"plugin scope without prefix", "comments", true, nil, nil, false},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			c, err := newRPCCredentialScoped("user", v.scopes)
+			switch {
+			case v.wantErr && err == nil:
+				t.Fatalf("got nil error, want error")
+			case v.wantErr:
+				return
+			case err != nil:
+				t.Fatal(err)
+			}
+
+			wantPerms := make(map[permission]struct{}, len(v.wantPerms))
+			for _, p := range v.wantPerms {
+				wantPerms[p] = struct{}{}
+			}
+			wantPlugins := make(map[string]struct{}, len(v.wantPlugins))
+			for _, p := range v.wantPlugins {
+				wantPlugins[p] = struct{}{}
+			}
+			switch {
+			case c.name != "user":
+				t.Fatalf("got name %v, want user", c.name)
+			case !reflect.DeepEqual(c.permissions, wantPerms):
+				t.Fatalf("got permissions %v, want %v",
+					c.permissions, wantPerms)
+			case !reflect.DeepEqual(c.plugins, wantPlugins):
+				t.Fatalf("got plugins %v, want %v", c.plugins, wantPlugins)
+			case c.pluginsAll != v.wantAll:
+				t.Fatalf("got plugins all %v, want %v",
+					c.pluginsAll, v.wantAll)
+			}
+		})
+	}
+}
+
+func TestNewRPCCredentials(t *testing.T) {
+	var tests = []struct {
+		name      string
+		creds     []string
+		certs     []string
+		wantErr   bool
+		wantUsers map[string]string // [user]password
+		wantCerts []string
+	}{
+		{"none", nil, nil, false, map[string]string{}, nil},
+		{"named credential", []string{"reader:readerpass:read"}, nil,
+			false, map[string]string{"reader": "readerpass"}, nil},
+		{"named credential multiple scopes",
+			[]string{"bot:botpass:read,plugin:comments"}, nil,
+			false, map[string]string{"bot": "botpass"}, nil},
+		{"multiple named credentials",
+			[]string{"reader:readerpass:read", "writer:writerpass:write"},
+			nil, false, map[string]string{
+				"reader": "readerpass",
+				"writer": "writerpass",
+			}, nil},
+		{"client cert", nil, []string{"reader:read"}, false,
+			map[string]string{}, []string{"reader"}},
+		{"client cert and named credential with the same name",
+			[]string{"reader:readerpass:read"}, []string{"reader:read"},
+			false, map[string]string{"reader": "readerpass"},
+			[]string{"reader"}},
+		{"named credential missing scopes", []string{"reader:readerpass"},
+			nil, true, nil, nil},
+		{"named credential empty scopes", []string{"reader:readerpass:"},
+			nil, true, nil, nil},
+		{"named credential empty user", []string{":readerpass:read"},
+			nil, true, nil, nil},
+		{"named credential empty pass", []string{"reader::read"},
+			nil, true, nil, nil},
+		{"named credential invalid scope", []string{"reader:pass:root"},
+			nil, true, nil, nil},
+		{"named credential empty plugin scope",
+			[]string{"reader:pass:plugin:"}, nil, true, nil, nil},
+		{"named credential pass contains separator",
+			[]string{"reader:pa:ss:read"}, nil, true, nil, nil},
+		{"duplicate named credential",
+			[]string{"reader:a:read", "reader:b:write"}, nil,
+			true, nil, nil},
+		{"named credential duplicates rpcuser",
+			[]string{"admin:other:read"}, nil, true, nil, nil},
+		{"client cert missing scopes", nil, []string{"reader"},
+			true, nil, nil},
+		{"client cert empty scopes", nil, []string{"reader:"},
+			true, nil, nil},
+		{"client cert empty common name", nil, []string{":read"},
+			true, nil, nil},
+		{"client cert invalid scope", nil, []string{"reader:read:write"},
+			true, nil, nil},
+		{"duplicate client cert", nil, []string{"reader:read", "reader:write"},
+			true, nil, nil},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			rc, err := newRPCCredentials(&config{
+				RPCUser:        "admin",
+				RPCPass:        "adminpass",
+				RPCCredentials: v.creds,
+				RPCClientCerts: v.certs,
+			})
+			switch {
+			case v.wantErr && err == nil:
+				t.Fatalf("got nil error, want error")
+			case v.wantErr:
+				return
+			case err != nil:
+				t.Fatal(err)
+			}
+
+			// The rpcuser is always granted all permissions
+			admin, ok := rc.users["admin"]
+			if !ok || rc.passwords["admin"] != "adminpass" {
+				t.Fatalf("rpcuser credential not found")
+			}
+			if !reflect.DeepEqual(admin, newRPCCredentialFull("admin")) {
+				t.Fatalf("got rpcuser credential %+v, want full access", admin)
+			}
+
+			// Verify the named credentials
+			if len(rc.users) != len(v.wantUsers)+1 {
+				t.Fatalf("got %v users, want %v",
+					len(rc.users), len(v.wantUsers)+1)
+			}
+			for user, pass := range v.wantUsers {
+				c, ok := rc.users[user]
+				if !ok {
+					t.Fatalf("user %v not found", user)
+				}
+				if c.name != user {
+					t.Fatalf("got name %v, want %v", c.name, user)
+				}
+				if rc.passwords[user] != pass {
+					t.Fatalf("got password %v, want %v",
+						rc.passwords[user], pass)
+				}
+			}
+
+			// Verify the client cert credentials
+			if len(rc.certs) != len(v.wantCerts) {
+				t.Fatalf("got %v certs, want %v",
+					len(rc.certs), len(v.wantCerts))
+			}
+			for _, cn := range v.wantCerts {
+				c, ok := rc.certs[cn]
+				if !ok {
+					t.Fatalf("cert %v not found", cn)
+				}
+				if c.name != cn {
+					t.Fatalf("got name %v, want %v", c.name, cn)
+				}
+			}
+		})
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	rc := newTestRPCCredentials(t)
+
+	var tests = []struct {
+		name     string
+		state    *tls.ConnectionState
+		user     string
+		pass     string
+		ok       bool   // Basic auth credentials provided
+		wantName string // Empty if authentication fails
+	}{
+		{"no credentials", nil, "", "", false, ""},
+		{"rpcuser", nil, "admin", "adminpass", true, "admin"},
+		{"named credential", nil, "reader", "readerpass", true, "reader"},
+		{"wrong password", nil, "reader", "adminpass", true, ""},
+		{"password prefix", nil, "reader", "readerpas", true, ""},
+		{"password with suffix", nil, "reader", "readerpass1", true, ""},
+		{"password case", nil, "reader", "READERPASS", true, ""},
+		{"empty password", nil, "reader", "", true, ""},
+		{"unknown user", nil, "unknown", "readerpass", true, ""},
+		{"user case", nil, "Reader", "readerpass", true, ""},
+		{"basic auth not provided", nil, "reader", "readerpass", false, ""},
+		{"verified cert", newTestTLSState("writer", true),
+			"", "", false, "writer"},
+		{"verified cert takes precedence", newTestTLSState("writer", true),
+			"reader", "readerpass", true, "writer"},
+		{"verified cert with wrong password",
+			newTestTLSState("writer", true), "reader", "wrong", true,
+			"writer"},
+		{"verified cert unknown common name",
+			newTestTLSState("unknown", true), "reader", "readerpass", true,
+			"reader"},
+		{"verified cert unknown common name without basic auth",
+			newTestTLSState("unknown", true), "", "", false, ""},
+		{"verified cert common name of named credential",
+			newTestTLSState("reader", true), "", "", false, ""},
+		{"unverified cert", newTestTLSState("writer", false),
+			"", "", false, ""},
+		{"unverified cert with basic auth",
+			newTestTLSState("writer", false), "reader", "readerpass", true,
+			"reader"},
+		{"empty verified chain", &tls.ConnectionState{
+			VerifiedChains: [][]*x509.Certificate{{}},
+		}, "", "", false, ""},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			c, ok := rc.authenticate(v.state, v.user, v.pass, v.ok)
+			switch {
+			case v.wantName == "" && ok:
+				t.Fatalf("got credential %v, want authentication failure",
+					c.name)
+			case v.wantName == "":
+				return
+			case !ok:
+				t.Fatalf("got authentication failure, want %v", v.wantName)
+			case c.name != v.wantName:
+				t.Fatalf("got credential %v, want %v", c.name, v.wantName)
+			}
+		})
+	}
+}
+
+func TestAllowed(t *testing.T) {
+	scoped := func(scopes string) *rpcCredential {
+		c, err := newRPCCredentialScoped("user", scopes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+
+	var tests = []struct {
+		name        string
+		c           *rpcCredential
+		wantPerms   []permission
+		wantPlugins []string // Plugins with write access
+	}{
+		{"full", newRPCCredentialFull("user"), permissions,
+			[]string{"comments", "ticketvote", "pi"}},
+		{"no permissions", &rpcCredential{},
+			[]permission{permissionPublic}, nil},
+		{"read", scoped("read"),
+			[]permission{permissionPublic, permissionRead}, nil},
+		{"write", scoped("write"),
+			[]permission{permissionPublic, permissionRecordWrite}, nil},
+		{"status", scoped("status"),
+			[]permission{permissionPublic, permissionStatusChange}, nil},
+		{"admin", scoped("admin"),
+			[]permission{permissionPublic, permissionAdmin}, nil},
+		{"plugin", scoped("read,plugin:comments"),
+			[]permission{permissionPublic, permissionRead,
+				permissionPluginWrite},
+			[]string{"comments"}},
+		{"all plugins", scoped("plugin:*"),
+			[]permission{permissionPublic, permissionPluginWrite},
+			[]string{"comments", "ticketvote", "pi"}},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			want := make(map[permission]bool, len(v.wantPerms))
+			for _, p := range v.wantPerms {
+				want[p] = true
+			}
+			for _, p := range permissions {
+				if got := v.c.allowed(p); got != want[p] {
+					t.Fatalf("permission %v: got %v, want %v",
+						p, got, want[p])
+				}
+			}
+
+			wantPlugins := make(map[string]bool, len(v.wantPlugins))
+			for _, p := range v.wantPlugins {
+				wantPlugins[p] = true
+			}
+			for _, p := range []string{"comments", "ticketvote", "pi"} {
+				if got := v.c.pluginWriteAllowed(p); got != wantPlugins[p] {
+					t.Fatalf("plugin %v: got %v, want %v",
+						p, got, wantPlugins[p])
+				}
+			}
+		})
+	}
+}
+
+func TestAuthRoutes(t *testing.T) {
+	cleanup := newTestLogRotator(t)
+	defer cleanup()
+
+	p := &politeia{
+		router: mux.NewRouter(),
+		creds:  newTestRPCCredentials(t),
+	}
+	p.addRouteV2(http.MethodPost, v2.RouteRecordSetStatus,
+		p.handleRecordSetStatus, permissionStatusChange)
+	p.addRouteV2(http.MethodPost, v2.RoutePluginWrite,
+		p.handlePluginWrite, permissionPluginWrite)
+
+	// The plugin write handler must verify the plugin scope of the
+	// credential before the plugin command is executed. A request
+	// that makes it past the credential checks returns a 400 since
+	// the request payloads are invalid.
+	pluginWrite := func(pluginID string) []byte {
+		b, err := json.Marshal(v2.PluginWrite{
+			Challenge: hex.EncodeToString(make([]byte, v2.ChallengeSize)),
+			Cmd: v2.PluginCmd{
+				Token:   "0123456789abcdef",
+				ID:      pluginID,
+				Command: "new",
+				Payload: "{}",
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	var tests = []struct {
+		name       string
+		route      string
+		body       []byte
+		state      *tls.ConnectionState
+		user       string
+		pass       string
+		wantStatus int
+	}{
+		{"set status no credentials", v2.RouteRecordSetStatus, nil,
+			nil, "", "", http.StatusUnauthorized},
+		{"set status wrong password", v2.RouteRecordSetStatus, nil,
+			nil, "reader", "wrong", http.StatusUnauthorized},
+		{"set status read only", v2.RouteRecordSetStatus, nil,
+			nil, "reader", "readerpass", http.StatusForbidden},
+		{"set status unverified cert", v2.RouteRecordSetStatus, nil,
+			newTestTLSState("writer", false), "", "",
+			http.StatusUnauthorized},
+		{"set status verified cert", v2.RouteRecordSetStatus, nil,
+			newTestTLSState("writer", true), "", "", http.StatusBadRequest},
+		{"set status rpcuser", v2.RouteRecordSetStatus, nil,
+			nil, "admin", "adminpass", http.StatusBadRequest},
+		{"plugin write no credentials", v2.RoutePluginWrite,
+			pluginWrite("comments"), nil, "", "", http.StatusUnauthorized},
+		{"plugin write read only", v2.RoutePluginWrite,
+			pluginWrite("comments"), nil, "reader", "readerpass",
+			http.StatusForbidden},
+		{"plugin write verified cert without plugin scope",
+			v2.RoutePluginWrite, pluginWrite("comments"),
+			newTestTLSState("writer", true), "", "", http.StatusForbidden},
+		{"plugin write other plugin", v2.RoutePluginWrite,
+			pluginWrite("ticketvote"), nil, "commenter", "commenterpass",
+			http.StatusForbidden},
+		{"plugin write plugin scope", v2.RoutePluginWrite, []byte("{"),
+			nil, "commenter", "commenterpass", http.StatusBadRequest},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost,
+				v2.APIRoute+v.route, bytes.NewReader(v.body))
+			r.TLS = v.state
+			if v.user != "" {
+				r.SetBasicAuth(v.user, v.pass)
+			}
+			w := httptest.NewRecorder()
+			p.router.ServeHTTP(w, r)
+
+			if w.Code != v.wantStatus {
+				t.Fatalf("got status %v, want %v: %s",
+					w.Code, v.wantStatus, w.Body.Bytes())
+			}
+			switch w.Code {
+			case http.StatusUnauthorized:
+				if w.Header().Get("WWW-Authenticate") == "" {
+					t.Fatalf("WWW-Authenticate header not set")
+				}
+			case http.StatusForbidden:
+				var ue v2.UserErrorReply
+				err := json.Unmarshal(w.Body.Bytes(), &ue)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if ue.ErrorCode != v2.ErrorCodeAccessDenied {
+					t.Fatalf("got error code %v, want %v",
+						ue.ErrorCode, v2.ErrorCodeAccessDenied)
+				}
+			}
+		})
+	}
+}
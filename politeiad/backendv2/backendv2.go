@@ -35,6 +35,35 @@ var (
 	// ErrPluginCmdInvalid is returned when a invalid plugin command is
 	// used.
 	ErrPluginCmdInvalid = errors.New("plugin command invalid")
+
+	// ErrInventoryQueryInvalid is returned when an inventory query
+	// contains an invalid sort or cursor.
+	ErrInventoryQueryInvalid = errors.New("inventory query invalid")
+
+	// ErrAnchorDropInProgress is returned when an anchor drop is
+	// requested while a prior anchor drop has not finished dropping.
+	ErrAnchorDropInProgress = errors.New("anchor drop in progress")
+
+	// ErrEventResumeInvalid is returned when an event stream is
+	// resumed using a resume token that is malformed or that refers to
+	// an event that has not been published.
+	ErrEventResumeInvalid = errors.New("event resume token invalid")
+
+	// ErrEventResumeExpired is returned when an event stream is
+	// resumed using a resume token that refers to events that are no
+	// longer available, e.g. because the backend has been restarted.
+	// The subscriber must resync its state by other means and then
+	// resubscribe without a resume token.
+	ErrEventResumeExpired = errors.New("event resume token expired; " +
+		"resync required")
+
+	// ErrBackupInProgress is returned when a backup is requested while
+	// a prior backup has not finished.
+	ErrBackupInProgress = errors.New("backup in progress")
+
+	// ErrCacheRebuildInProgress is returned when a plugin cache rebuild
+	// is requested while a prior rebuild has not finished.
+	ErrCacheRebuildInProgress = errors.New("cache rebuild in progress")
 )
 
 // StateT represents the state of a record.
@@ -170,6 +199,7 @@ const (
 	ContentErrorFilePayloadInvalid      ContentErrorCodeT = 7
 	ContentErrorFileMIMETypeInvalid     ContentErrorCodeT = 8
 	ContentErrorFileMIMETypeUnsupported ContentErrorCodeT = 9
+	ContentErrorBundleInvalid           ContentErrorCodeT = 10
 )
 
 // ContentError is returned when the content of a record does not pass
@@ -244,12 +274,61 @@ type RecordTimestamps struct {
 
 // Inventory contains the tokens of records in the inventory categorized by
 // record state and record status. Tokens are sorted by the timestamp of the
-// status change from newest to oldest.
+// most recent record update from newest to oldest.
 type Inventory struct {
 	Unvetted map[StatusT][]string
 	Vetted   map[StatusT][]string
 }
 
+// InventorySortT represents the timestamp that inventory entries are sorted
+// by.
+type InventorySortT uint32
+
+const (
+	// InventorySortUpdated sorts the inventory by the timestamp of the
+	// most recent record update.
+	InventorySortUpdated InventorySortT = 0
+
+	// InventorySortCreated sorts the inventory by the timestamp of the
+	// record creation.
+	InventorySortCreated InventorySortT = 1
+)
+
+// InventoryQuery requests a page of inventory entries. The state, status, and
+// attribute filters are optional. Attributes are set by plugins and are
+// namespaced using the plugin ID, e.g. "ticketvote.votestatus". An entry must
+// match all of the provided attributes to be returned. Entries are sorted from
+// newest to oldest unless Ascending is set.
+//
+// The Cursor is the cursor that was returned with the previous page. An empty
+// cursor requests the first page.
+type InventoryQuery struct {
+	State      StateT
+	Status     StatusT
+	Attributes map[string]string
+	Sort       InventorySortT
+	Ascending  bool
+	Cursor     string
+	Limit      uint32
+}
+
+// InventoryEntry represents a record in the inventory.
+type InventoryEntry struct {
+	Token      string // Hex encoded
+	State      StateT
+	Status     StatusT
+	Created    int64             // Unix timestamp of record creation
+	Updated    int64             // Unix timestamp of last record update
+	Attributes map[string]string // Plugin attributes
+}
+
+// InventoryPage contains a page of inventory entries. The Cursor can be used
+// to request the next page. It will be empty if there are no more entries.
+type InventoryPage struct {
+	Entries []InventoryEntry
+	Cursor  string
+}
+
 // PluginSetting represents a configurable plugin setting.
 type PluginSetting struct {
 	Key   string // Name of setting
@@ -265,6 +344,39 @@ type Plugin struct {
 	// create receipts, i.e. signatures of user provided data that
 	// prove the backend received and processed a plugin command.
 	Identity *identity.FullIdentity
+
+	// Path is the path of the plugin executable for plugins that run
+	// in a separate process. It is empty for plugins that are built
+	// into politeiad.
+	Path string
+}
+
+// PluginSettingsChange is an entry in the audit trail of the plugin settings
+// updates that were made at runtime. Previous contains the values of the
+// updated settings prior to the update. A setting that did not have a value
+// prior to the update is not included in Previous. User is the name of the
+// RPC credential that made the update.
+type PluginSettingsChange struct {
+	PluginID  string
+	Settings  []PluginSetting // Updated settings
+	Previous  []PluginSetting // Prior values of the updated settings
+	Reason    string          // Reason for the update
+	User      string          // RPC credential that made the update
+	Timestamp int64           // Unix timestamp of the update
+}
+
+// PluginSettingError is returned when a plugin rejects a plugin setting.
+type PluginSettingError struct {
+	PluginID string
+	Key      string
+	Value    string
+	Reason   string
+}
+
+// Error satisfies the error interface.
+func (e PluginSettingError) Error() string {
+	return fmt.Sprintf("plugin '%v' setting %v '%v' invalid: %v",
+		e.PluginID, e.Key, e.Value, e.Reason)
 }
 
 // PluginError represents an error that occurred during plugin execution that
@@ -281,6 +393,449 @@ func (e PluginError) Error() string {
 		e.PluginID, e.ErrorCode)
 }
 
+// FsckIssueT represents a type of issue that can be found during a backend
+// filesystem check.
+type FsckIssueT uint32
+
+const (
+	// FsckIssueInvalid is an invalid fsck issue type.
+	FsckIssueInvalid FsckIssueT = 0
+
+	// FsckIssueLeafInvalid indicates that a tlog leaf could not be
+	// decoded or that a record index references a leaf that does not
+	// exist in the tree.
+	FsckIssueLeafInvalid FsckIssueT = 1
+
+	// FsckIssueRecordIndex indicates that the record indexes of a tree
+	// could not be parsed.
+	FsckIssueRecordIndex FsckIssueT = 2
+
+	// FsckIssueBlobMissing indicates that a blob referenced by a tlog
+	// leaf does not exist in the key-value store.
+	FsckIssueBlobMissing FsckIssueT = 3
+
+	// FsckIssueBlobCorrupt indicates that a blob exists in the
+	// key-value store but does not match the digest saved to its tlog
+	// leaf.
+	FsckIssueBlobCorrupt FsckIssueT = 4
+
+	// FsckIssueBlobOrphaned indicates that a blob exists in the
+	// key-value store but is not referenced by any tlog leaf.
+	FsckIssueBlobOrphaned FsckIssueT = 5
+
+	// FsckIssueCensoredFiles indicates that the file blobs of a
+	// censored record have not been deleted.
+	FsckIssueCensoredFiles FsckIssueT = 6
+
+	// FsckIssueTreeNotFrozen indicates that a tree has been frozen and
+	// anchored, but the tree status has not been set to frozen in the
+	// tlog backend.
+	FsckIssueTreeNotFrozen FsckIssueT = 7
+
+	// FsckIssueInventory indicates that the inventory cache is not
+	// coherent with the records in the backend.
+	FsckIssueInventory FsckIssueT = 8
+
+	// FsckIssuePlugin indicates that a plugin fsck returned an error.
+	FsckIssuePlugin FsckIssueT = 9
+
+	// FsckIssueLast is used for unit test validation of human readable
+	// errors.
+	FsckIssueLast FsckIssueT = 10
+)
+
+var (
+	// FsckIssues contains the human readable fsck issue types.
+	FsckIssues = map[FsckIssueT]string{
+		FsckIssueInvalid:       "invalid issue",
+		FsckIssueLeafInvalid:   "tlog leaf invalid",
+		FsckIssueRecordIndex:   "record index invalid",
+		FsckIssueBlobMissing:   "blob missing",
+		FsckIssueBlobCorrupt:   "blob corrupt",
+		FsckIssueBlobOrphaned:  "blob orphaned",
+		FsckIssueCensoredFiles: "censored record files not deleted",
+		FsckIssueTreeNotFrozen: "tree not frozen",
+		FsckIssueInventory:     "inventory not coherent",
+		FsckIssuePlugin:        "plugin fsck failed",
+	}
+)
+
+// FsckIssue describes an issue that was found during a backend filesystem
+// check. Token will not be populated if the issue does not correspond to a
+// specific record. Repaired is set to true if the issue was repaired as part
+// of the filesystem check.
+type FsckIssue struct {
+	Type        FsckIssueT
+	Token       string // Hex encoded
+	Description string
+	Repaired    bool
+}
+
+// FsckReport is the report that is returned from a backend filesystem check.
+type FsckReport struct {
+	Repair  bool        // Repair mode was enabled
+	Records uint32      // Number of records checked
+	Blobs   uint64      // Number of blobs checked
+	Issues  []FsckIssue // Issues that were found
+}
+
+// EncryptionKeyStatus describes the state of the keys that are used to encrypt
+// unvetted data at rest. New data is always encrypted using the active key.
+// Prior keys are retained until all data that was encrypted using them has
+// been re-encrypted using the active key.
+type EncryptionKeyStatus struct {
+	ActiveKeyID  uint32   // ID of the key used to encrypt new data
+	KeyIDs       []uint32 // IDs of all keys that have not been retired
+	Reencrypting bool     // Re-encryption is in progress
+	Reencrypted  uint64   // Blobs re-encrypted during the last pass
+}
+
+// BlobCacheStats contains the statistics of the in-memory cache of vetted
+// blobs. Enabled will be false and the stats will be zero if the cache has
+// been disabled. The stats are reset when politeiad is restarted.
+type BlobCacheStats struct {
+	Enabled   bool   // Cache is enabled
+	Hits      uint64 // Blobs returned from the cache
+	Misses    uint64 // Blobs not found in the cache
+	Evictions uint64 // Blobs evicted to free up space
+	Entries   int    // Blobs currently in the cache
+	Size      int64  // Current size of the cache in bytes
+	MaxSize   int64  // Max size of the cache in bytes
+}
+
+// AnchorDropT represents the status of an anchor drop.
+type AnchorDropT uint32
+
+const (
+	// AnchorDropInvalid is an invalid anchor drop status.
+	AnchorDropInvalid AnchorDropT = 0
+
+	// AnchorDropSubmitted indicates that the tree digests have been
+	// submitted to the anchor service and that the anchor transaction
+	// has not been sent yet.
+	AnchorDropSubmitted AnchorDropT = 1
+
+	// AnchorDropSent indicates that the anchor transaction has been
+	// sent and is waiting for confirmations.
+	AnchorDropSent AnchorDropT = 2
+
+	// AnchorDropConfirmed indicates that the anchor transaction has
+	// been confirmed and that the anchor records have been saved to
+	// the anchored trees.
+	AnchorDropConfirmed AnchorDropT = 3
+
+	// AnchorDropFailed indicates that the anchor drop failed. The
+	// anchored trees are included in the next anchor drop.
+	AnchorDropFailed AnchorDropT = 4
+
+	// AnchorDropLast is used for unit test validation of human
+	// readable statuses.
+	AnchorDropLast AnchorDropT = 5
+)
+
+var (
+	// AnchorDrops contains the human readable anchor drop statuses.
+	AnchorDrops = map[AnchorDropT]string{
+		AnchorDropInvalid:   "invalid",
+		AnchorDropSubmitted: "submitted",
+		AnchorDropSent:      "sent",
+		AnchorDropConfirmed: "confirmed",
+		AnchorDropFailed:    "failed",
+	}
+)
+
+// AnchorDrop describes an anchor drop, i.e. the timestamping of the root
+// hashes of all trees that had unanchored leaves at the time of the drop. The
+// anchor service only reports whether the anchor transaction has been sent and
+// whether it has received enough confirmations. Anchored is the number of
+// trees whose anchor has been confirmed.
+type AnchorDrop struct {
+	Status      AnchorDropT
+	Trees       uint32   // Number of trees included in the drop
+	Anchored    uint32   // Number of trees with a confirmed anchor
+	MerkleRoots []string // Anchor merkle roots
+	TxIDs       []string // Anchor transaction IDs
+	Attempts    uint32   // Number of verify attempts
+	MaxAttempts uint32   // Verify attempts before the drop times out
+	Started     int64    // Unix timestamp of the drop submission
+	LastCheck   int64    // Unix timestamp of the last verify attempt
+	Completed   int64    // Unix timestamp of the confirmation or failure
+	Error       string   // Failure reason
+}
+
+// AnchorTree describes a tree that contains leaves that have not been
+// anchored yet.
+type AnchorTree struct {
+	Token        string // Hex encoded
+	Leaves       uint64 // Number of unanchored leaves
+	AnchoredSize uint64 // Tree size of the most recent anchor
+	Anchored     int64  // Chain timestamp of the most recent anchor
+}
+
+// AnchorStatus describes the state of the anchoring of the backend data. The
+// anchor drop history is kept in memory and does not persist across restarts.
+type AnchorStatus struct {
+	Type      string       // Anchor service type
+	Schedule  string       // Anchor drop schedule
+	NextDrop  int64        // Unix timestamp of the next scheduled drop
+	Dropping  bool         // An anchor drop is in progress
+	LastRun   int64        // Unix timestamp of the last anchor run
+	LastError string       // Error of the last anchor run
+	Pending   []AnchorTree // Trees with unanchored leaves
+	Drops     []AnchorDrop // Recent anchor drops, most recent first
+}
+
+const (
+	// RecordBundleVersion is the version of the record bundle format.
+	RecordBundleVersion uint32 = 1
+)
+
+// BundleBlob contains a single blob of a record bundle along with the tlog
+// leaf data that is required to restore it.
+//
+// Digest is the tlog leaf value, which is the SHA256 digest of the decoded
+// blob entry data. State is the record state at the time the blob was saved.
+// Unvetted blobs are encrypted at rest. The unvetted blobs that are part of
+// the public version of a record are also saved as plain text, which is
+// indicated by PlainText.
+//
+// Deleted indicates that the blob was deleted from the backend, such as the
+// files of a censored record. The tlog leaf of a deleted blob still exists.
+// DataHint and Data are not populated for deleted blobs unless a plain text
+// copy of the blob still exists.
+type BundleBlob struct {
+	LeafIndex      int64  `json:"leafindex"`
+	Digest         string `json:"digest"`
+	DataDescriptor string `json:"datadescriptor"`
+	State          StateT `json:"state,omitempty"`
+	PlainText      bool   `json:"plaintext,omitempty"`
+	Deleted        bool   `json:"deleted,omitempty"`
+	DataHint       string `json:"datahint,omitempty"` // Base64 encoded
+	Data           string `json:"data,omitempty"`     // Base64 encoded
+}
+
+// RecordBundle contains the full contents of a record. This includes every
+// version of the record, the timestamps of every version, and every blob of
+// the record in the order that they were appended to the record's tlog tree.
+// The blobs include the record content, the plugin data, and the anchors.
+type RecordBundle struct {
+	Version    uint32                      `json:"version"`    // Bundle version
+	Token      string                      `json:"token"`      // Hex encoded
+	Timestamp  int64                       `json:"timestamp"`  // Export time
+	Records    []Record                    `json:"records"`    // All versions
+	Timestamps map[uint32]RecordTimestamps `json:"timestamps"` // [version]
+	Blobs      []BundleBlob                `json:"blobs"`      // Leaf ordered
+}
+
+// DiffT represents the type of change that is described by a record diff.
+type DiffT uint32
+
+const (
+	// DiffInvalid is an invalid diff type.
+	DiffInvalid DiffT = 0
+
+	// DiffUnchanged indicates that a line was not changed. It is only
+	// used for the lines of a diff hunk.
+	DiffUnchanged DiffT = 1
+
+	// DiffAdded indicates that a file, metadata stream, or line was
+	// added.
+	DiffAdded DiffT = 2
+
+	// DiffRemoved indicates that a file, metadata stream, or line was
+	// removed.
+	DiffRemoved DiffT = 3
+
+	// DiffModified indicates that a file or metadata stream was
+	// modified.
+	DiffModified DiffT = 4
+
+	// DiffLast is used by unit tests to verify that all diff types
+	// have a human readable entry.
+	DiffLast DiffT = 5
+)
+
+var (
+	// Diffs contains the human readable diff types.
+	Diffs = map[DiffT]string{
+		DiffInvalid:   "invalid",
+		DiffUnchanged: "unchanged",
+		DiffAdded:     "added",
+		DiffRemoved:   "removed",
+		DiffModified:  "modified",
+	}
+)
+
+// DiffLine is a single line of a diff hunk.
+type DiffLine struct {
+	Type DiffT  `json:"type"`
+	Text string `json:"text"`
+}
+
+// DiffHunk is a contiguous section of changed lines along with the unchanged
+// lines that surround them. FromLine and ToLine are the 1-based line numbers
+// of the first line of the hunk in the old and new text. FromLines and
+// ToLines are the number of lines of the hunk that are part of the old and
+// new text.
+type DiffHunk struct {
+	FromLine  uint32     `json:"fromline"`
+	FromLines uint32     `json:"fromlines"`
+	ToLine    uint32     `json:"toline"`
+	ToLines   uint32     `json:"tolines"`
+	Lines     []DiffLine `json:"lines"`
+}
+
+// FileDiff describes the changes made to a record file. Hunks contains a line
+// level diff of the file content and is only populated for text/plain files.
+// The digest of a file is empty if the file does not exist in that version.
+type FileDiff struct {
+	Name       string     `json:"name"`
+	Type       DiffT      `json:"type"`
+	MIME       string     `json:"mime"`
+	FromDigest string     `json:"fromdigest,omitempty"`
+	ToDigest   string     `json:"todigest,omitempty"`
+	Hunks      []DiffHunk `json:"hunks,omitempty"`
+}
+
+// MetadataStreamDiff describes the changes made to a record metadata stream.
+// Each JSON value in the metadata stream payload is treated as a line of the
+// diff so that the entries of appended metadata streams, such as status
+// changes, are shown as individual lines.
+type MetadataStreamDiff struct {
+	PluginID string     `json:"pluginid"`
+	StreamID uint32     `json:"streamid"`
+	Type     DiffT      `json:"type"`
+	Hunks    []DiffHunk `json:"hunks"`
+}
+
+// RecordDiff describes the changes between two versions of a record. From
+// and To contain the record metadata of the two versions, which includes the
+// record state and status of each version. Only the files and metadata
+// streams that changed are included.
+type RecordDiff struct {
+	From     RecordMetadata       `json:"from"`
+	To       RecordMetadata       `json:"to"`
+	Files    []FileDiff           `json:"files"`
+	Metadata []MetadataStreamDiff `json:"metadata"`
+}
+
+// EventT represents a backend event type.
+type EventT uint32
+
+const (
+	// EventTypeInvalid is an invalid event type.
+	EventTypeInvalid EventT = 0
+
+	// EventTypeRecordNew is published when a new record is saved.
+	EventTypeRecordNew EventT = 1
+
+	// EventTypeRecordEdit is published when a record is edited.
+	EventTypeRecordEdit EventT = 2
+
+	// EventTypeRecordEditMetadata is published when the metadata of a
+	// record is edited.
+	EventTypeRecordEditMetadata EventT = 3
+
+	// EventTypeRecordSetStatus is published when the status of a
+	// record is updated.
+	EventTypeRecordSetStatus EventT = 4
+
+	// EventTypeRecordImport is published when a record bundle is
+	// imported as a new record.
+	EventTypeRecordImport EventT = 5
+
+	// EventTypePluginWrite is published when a plugin write command is
+	// executed.
+	EventTypePluginWrite EventT = 6
+
+	// EventTypePlugin is published by a plugin. Plugins use it to
+	// announce changes that are not the result of a write command,
+	// such as a ticket vote finishing at the vote end height.
+	EventTypePlugin EventT = 7
+
+	// EventTypePluginSettingsUpdate is published when the settings of
+	// a plugin are updated at runtime.
+	EventTypePluginSettingsUpdate EventT = 8
+
+	// EventTypeLast is used for unit test validation of human readable
+	// event types.
+	EventTypeLast EventT = 9
+)
+
+var (
+	// Events contains the human readable event types.
+	Events = map[EventT]string{
+		EventTypeInvalid:              "invalid",
+		EventTypeRecordNew:            "record new",
+		EventTypeRecordEdit:           "record edit",
+		EventTypeRecordEditMetadata:   "record edit metadata",
+		EventTypeRecordSetStatus:      "record set status",
+		EventTypeRecordImport:         "record import",
+		EventTypePluginWrite:          "plugin write",
+		EventTypePlugin:               "plugin",
+		EventTypePluginSettingsUpdate: "plugin settings update",
+	}
+)
+
+// Event is published by the backend after a change has been successfully
+// saved and the post plugin hooks have been executed. The ID is assigned when
+// the event is published. It is used as the resume token when resubscribing
+// to the event stream.
+//
+// The record events include the record metadata and the metadata streams of
+// the updated record. The record files are not included. The plugin events
+// include the plugin ID and the plugin command or plugin event name. The
+// command payload and reply are included for plugin write events.
+type Event struct {
+	ID        string
+	Type      EventT
+	Timestamp int64  // Unix timestamp
+	Token     string // Record token, if applicable
+
+	// Record event fields
+	RecordMetadata *RecordMetadata
+	Metadata       []MetadataStream
+
+	// Plugin event fields
+	PluginID string
+	Name     string // Plugin command or plugin event name
+	Payload  string
+	Reply    string
+}
+
+// BackupSummary describes a backend backup. A backup is a point-in-time
+// snapshot of the backend. It is saved to its own directory, which is named
+// after the time that the backup was taken.
+type BackupSummary struct {
+	Name      string // Backup name
+	Path      string // Backup directory
+	Timestamp int64  // Unix timestamp
+	Trees     uint64 // Number of tlog trees
+	Blobs     uint64 // Number of key-value store blobs
+	Files     uint64 // Number of data directory files
+}
+
+// PluginCacheRebuild describes the rebuild of the caches of a single plugin.
+// Rebuilt is the number of records that have been processed. Error is
+// populated if the rebuild of the plugin caches failed.
+type PluginCacheRebuild struct {
+	PluginID string
+	Records  uint32 // Number of records
+	Rebuilt  uint32 // Number of records that have been processed
+	Done     bool   // Rebuild has finished
+	Error    string // Rebuild error
+}
+
+// CacheRebuildStatus describes the status of the most recent plugin cache
+// rebuild. The plugins are rebuilt one at a time in the order that they are
+// listed.
+type CacheRebuildStatus struct {
+	InProgress bool
+	Started    int64 // Unix timestamp
+	Finished   int64 // Unix timestamp
+	Plugins    []PluginCacheRebuild
+}
+
 // Backend provides an API for interacting with records in the backend.
 type Backend interface {
 	// RecordNew creates a new record.
@@ -312,10 +867,22 @@ type Backend interface {
 	// not returned.
 	Records(reqs []RecordRequest) (map[string]Record, error)
 
+	// RecordDiff returns the changes between two versions of a
+	// record. A to version of 0 indicates the most recent version.
+	RecordDiff(token []byte, from, to uint32) (*RecordDiff, error)
+
+	// RecordExport returns a bundle that contains the full contents of
+	// a record.
+	RecordExport(token []byte) (*RecordBundle, error)
+
+	// RecordImport restores a record bundle as a new record and returns
+	// the token of the new record.
+	RecordImport(RecordBundle) ([]byte, error)
+
 	// Inventory returns the tokens of records in the inventory
 	// categorized by record state and record status. The tokens are
-	// ordered by the timestamp of their most recent status change,
-	// sorted from newest to oldest.
+	// ordered by the timestamp of their most recent update, sorted
+	// from newest to oldest.
 	//
 	// The state, status, and page arguments can be provided to request
 	// a specific page of record tokens.
@@ -326,10 +893,14 @@ type Backend interface {
 		pageNumber uint32) (*Inventory, error)
 
 	// InventoryOrdered returns a page of record tokens ordered by the
-	// timestamp of their most recent status change from newest to
-	// oldest. The returned tokens will include all record statuses.
+	// timestamp of their most recent update from newest to oldest. The
+	// returned tokens will include all record statuses.
 	InventoryOrdered(s StateT, pageSize, pageNumber uint32) ([]string, error)
 
+	// InventoryQuery returns a page of inventory entries that match
+	// the provided query.
+	InventoryQuery(InventoryQuery) (*InventoryPage, error)
+
 	// PluginRegister registers a plugin.
 	PluginRegister(Plugin) error
 
@@ -347,6 +918,92 @@ type Backend interface {
 	// PluginInventory returns all registered plugins.
 	PluginInventory() []Plugin
 
+	// PluginSettingsUpdate updates the settings of a registered plugin
+	// at runtime. The plugin validates the new setting values. A
+	// PluginSettingError is returned if a setting is rejected by the
+	// plugin. The update is recorded in the plugin settings audit
+	// trail, along with the name of the RPC credential that made it,
+	// and the updated plugin is returned.
+	PluginSettingsUpdate(pluginID string, settings []PluginSetting,
+		reason, user string) (*Plugin, error)
+
+	// PluginSettingsHistory returns the audit trail of the runtime
+	// plugin settings updates of a plugin, ordered from oldest to
+	// newest.
+	PluginSettingsHistory(pluginID string) ([]PluginSettingsChange, error)
+
+	// Fsck performs a filesystem check on the backend. If repair is
+	// set to true then any issues that can be repaired will be.
+	Fsck(repair bool) (*FsckReport, error)
+
+	// RotateEncryptionKey creates a new key for encrypting unvetted
+	// data at rest and starts re-encrypting the existing data using
+	// the new key in the background. Prior keys are retired once they
+	// are no longer used. The new key is loaded from the provided key
+	// file. A default key file is used if one is not provided.
+	RotateEncryptionKey(keyFile string) (*EncryptionKeyStatus, error)
+
+	// EncryptionKeyStatus returns the status of the keys that are used
+	// to encrypt unvetted data at rest.
+	EncryptionKeyStatus() (*EncryptionKeyStatus, error)
+
+	// BlobCacheStats returns the statistics of the in-memory cache of
+	// vetted blobs.
+	BlobCacheStats() (*BlobCacheStats, error)
+
+	// AnchorStatus returns the status of the anchoring of the backend
+	// data.
+	AnchorStatus() (*AnchorStatus, error)
+
+	// AnchorDrop drops an anchor for all data that has not been
+	// anchored yet without waiting for the anchor schedule. An
+	// ErrAnchorDropInProgress is returned if a prior anchor drop has
+	// not finished dropping.
+	AnchorDrop() (*AnchorStatus, error)
+
+	// EventsSubscribe subscribes to the backend event stream. The
+	// events that were published after the event with the provided
+	// resume token, i.e. event ID, are sent before any new events. An
+	// empty resume token subscribes to new events only. An
+	// ErrEventResumeInvalid is returned if the resume token is
+	// malformed. An ErrEventResumeExpired is returned if the events
+	// after the resume token are no longer available. The channel is
+	// closed if the subscriber falls too far behind, in which case it
+	// must resubscribe using the ID of the last event that it
+	// received.
+	EventsSubscribe(resume string) (<-chan Event, error)
+
+	// EventsUnsubscribe cancels an event stream subscription and
+	// closes the channel.
+	EventsUnsubscribe(<-chan Event)
+
+	// Backup saves a point-in-time snapshot of the backend to a new
+	// directory inside of the provided directory. Writes are blocked
+	// while the backup is being taken. Reads are not. The hook, if
+	// provided, is executed with the backup directory while writes are
+	// still blocked so that data that is not managed by the backend,
+	// such as a trillian database, can be snapshotted at the same point
+	// in time. An ErrBackupInProgress is returned if a prior backup has
+	// not finished.
+	Backup(dir string, hook func(backupDir string) error) (*BackupSummary, error)
+
+	// RestoreVerify verifies that the backend matches the backup that
+	// is saved in the provided backup directory. This is done after
+	// the backup has been restored.
+	RestoreVerify(backupDir string) error
+
+	// CacheRebuild rebuilds the caches of the provided plugins from the
+	// plugin data that is saved to the backend. The caches of all
+	// plugins are rebuilt if no plugin IDs are provided. The rebuild
+	// is performed in the background. Writes are blocked until it has
+	// finished. An ErrCacheRebuildInProgress is returned if a prior
+	// rebuild has not finished.
+	CacheRebuild(pluginIDs []string) (*CacheRebuildStatus, error)
+
+	// CacheRebuildStatus returns the status of the most recent plugin
+	// cache rebuild.
+	CacheRebuildStatus() (*CacheRebuildStatus, error)
+
 	// Close performs cleanup of the backend.
 	Close()
 }
@@ -19,4 +19,20 @@ func TestMaps(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Statuses: %v", err)
 	}
+	err = unittest.TestGenericConstMap(FsckIssues, uint64(FsckIssueLast))
+	if err != nil {
+		t.Fatalf("FsckIssues: %v", err)
+	}
+	err = unittest.TestGenericConstMap(Diffs, uint64(DiffLast))
+	if err != nil {
+		t.Fatalf("Diffs: %v", err)
+	}
+	err = unittest.TestGenericConstMap(AnchorDrops, uint64(AnchorDropLast))
+	if err != nil {
+		t.Fatalf("AnchorDrops: %v", err)
+	}
+	err = unittest.TestGenericConstMap(Events, uint64(EventTypeLast))
+	if err != nil {
+		t.Fatalf("Events: %v", err)
+	}
 }
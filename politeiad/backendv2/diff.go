@@ -0,0 +1,450 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package backendv2
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+const (
+	// diffContext is the number of unchanged lines that are included
+	// before and after the changed lines of a diff hunk.
+	diffContext = 3
+
+	// diffMaxEdits is the maximum number of line edits that the diff
+	// algorithm will search for. The memory required by the search
+	// grows quadratically with the number of edits. Texts that require
+	// more edits than this are diffed as a full replacement, where all
+	// of the old lines are removed and all of the new lines are added.
+	diffMaxEdits = 1024
+
+	// mimeTextPlain is the MIME type prefix of the files that are
+	// diffed line by line.
+	mimeTextPlain = "text/plain"
+)
+
+// Diff returns the changes between two versions of a record.
+func Diff(from, to Record) (*RecordDiff, error) {
+	files, err := diffFiles(from.Files, to.Files)
+	if err != nil {
+		return nil, err
+	}
+	return &RecordDiff{
+		From:     from.RecordMetadata,
+		To:       to.RecordMetadata,
+		Files:    files,
+		Metadata: diffMetadataStreams(from.Metadata, to.Metadata),
+	}, nil
+}
+
+// diffFiles returns the file diffs of the files that were added, removed, or
+// modified. The diffs are sorted by file name.
+func diffFiles(from, to []File) ([]FileDiff, error) {
+	var (
+		fromFiles = make(map[string]File, len(from))
+		toFiles   = make(map[string]File, len(to))
+		names     = make([]string, 0, len(from)+len(to))
+	)
+	for _, v := range from {
+		fromFiles[v.Name] = v
+		names = append(names, v.Name)
+	}
+	for _, v := range to {
+		toFiles[v.Name] = v
+		if _, ok := fromFiles[v.Name]; !ok {
+			names = append(names, v.Name)
+		}
+	}
+	sort.Strings(names)
+
+	diffs := make([]FileDiff, 0, len(names))
+	for _, name := range names {
+		f, inFrom := fromFiles[name]
+		t, inTo := toFiles[name]
+		fd := FileDiff{
+			Name:       name,
+			FromDigest: f.Digest,
+			ToDigest:   t.Digest,
+		}
+		switch {
+		case inFrom && inTo:
+			if f.Digest == t.Digest {
+				// File is unchanged
+				continue
+			}
+			fd.Type = DiffModified
+			fd.MIME = t.MIME
+		case inTo:
+			fd.Type = DiffAdded
+			fd.MIME = t.MIME
+		default:
+			fd.Type = DiffRemoved
+			fd.MIME = f.MIME
+		}
+
+		// Line diffs are only provided for plain text files. The file
+		// must be plain text in both versions.
+		if isTextPlain(f, inFrom) && isTextPlain(t, inTo) {
+			a, err := fileLines(f)
+			if err != nil {
+				return nil, err
+			}
+			b, err := fileLines(t)
+			if err != nil {
+				return nil, err
+			}
+			fd.Hunks = diffHunks(a, b)
+		}
+
+		diffs = append(diffs, fd)
+	}
+
+	return diffs, nil
+}
+
+// isTextPlain returns whether the file is a text/plain file. A file that does
+// not exist is considered to be plain text so that the lines of added and
+// removed text files are included in the diff.
+func isTextPlain(f File, exists bool) bool {
+	return !exists || strings.HasPrefix(f.MIME, mimeTextPlain)
+}
+
+// fileLines decodes the file payload and returns the lines of the file.
+func fileLines(f File) ([]string, error) {
+	if f.Payload == "" {
+		return []string{}, nil
+	}
+	b, err := base64.StdEncoding.DecodeString(f.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("decode %v: %v", f.Name, err)
+	}
+	return splitLines(string(b)), nil
+}
+
+// splitLines splits the text into lines. A trailing newline does not start a
+// new line.
+func splitLines(s string) []string {
+	if s == "" {
+		return []string{}
+	}
+	return strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+}
+
+// diffMetadataStreams returns the diffs of the metadata streams that were
+// added, removed, or modified. The diffs are sorted by plugin ID and then by
+// stream ID.
+func diffMetadataStreams(from, to []MetadataStream) []MetadataStreamDiff {
+	type streamKey struct {
+		pluginID string
+		streamID uint32
+	}
+	var (
+		fromStreams = make(map[streamKey]MetadataStream, len(from))
+		toStreams   = make(map[streamKey]MetadataStream, len(to))
+		keys        = make([]streamKey, 0, len(from)+len(to))
+	)
+	for _, v := range from {
+		k := streamKey{v.PluginID, v.StreamID}
+		fromStreams[k] = v
+		keys = append(keys, k)
+	}
+	for _, v := range to {
+		k := streamKey{v.PluginID, v.StreamID}
+		toStreams[k] = v
+		if _, ok := fromStreams[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].pluginID != keys[j].pluginID {
+			return keys[i].pluginID < keys[j].pluginID
+		}
+		return keys[i].streamID < keys[j].streamID
+	})
+
+	diffs := make([]MetadataStreamDiff, 0, len(keys))
+	for _, k := range keys {
+		f, inFrom := fromStreams[k]
+		t, inTo := toStreams[k]
+		var dt DiffT
+		switch {
+		case inFrom && inTo:
+			if f.Payload == t.Payload {
+				// Metadata stream is unchanged
+				continue
+			}
+			dt = DiffModified
+		case inTo:
+			dt = DiffAdded
+		default:
+			dt = DiffRemoved
+		}
+		diffs = append(diffs, MetadataStreamDiff{
+			PluginID: k.pluginID,
+			StreamID: k.streamID,
+			Type:     dt,
+			Hunks: diffHunks(metadataLines(f.Payload),
+				metadataLines(t.Payload)),
+		})
+	}
+
+	return diffs
+}
+
+// metadataLines returns the lines of a metadata stream payload. Metadata
+// stream payloads contain one or more JSON values that may or may not be
+// separated by newlines. Each JSON value is returned as a single line of
+// compact JSON. The payload is split on newlines if it does not contain valid
+// JSON.
+func metadataLines(payload string) []string {
+	lines := make([]string, 0, 16)
+	d := json.NewDecoder(strings.NewReader(payload))
+	for {
+		var v json.RawMessage
+		err := d.Decode(&v)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return splitLines(payload)
+		}
+		var b bytes.Buffer
+		err = json.Compact(&b, v)
+		if err != nil {
+			return splitLines(payload)
+		}
+		lines = append(lines, b.String())
+	}
+	return lines
+}
+
+// diffHunks returns the line diff of the provided texts grouped into hunks.
+func diffHunks(a, b []string) []DiffHunk {
+	return hunks(diffLines(a, b), diffContext)
+}
+
+// diffLines returns the shortest edit script that transforms the lines of a
+// into the lines of b. The returned lines include the unchanged lines. The
+// edit script is found using the Myers diff algorithm.
+func diffLines(a, b []string) []DiffLine {
+	// Strip the common prefix and suffix. These lines are unchanged
+	// and do not need to be searched.
+	var prefix, suffix int
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	for suffix < len(a)-prefix && suffix < len(b)-prefix &&
+		a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := make([]DiffLine, 0, len(a)+len(b))
+	for _, v := range a[:prefix] {
+		lines = append(lines, DiffLine{Type: DiffUnchanged, Text: v})
+	}
+	lines = append(lines, myers(a[prefix:len(a)-suffix],
+		b[prefix:len(b)-suffix])...)
+	for _, v := range a[len(a)-suffix:] {
+		lines = append(lines, DiffLine{Type: DiffUnchanged, Text: v})
+	}
+
+	return lines
+}
+
+// myers returns the shortest edit script that transforms a into b. A full
+// replacement is returned if the edit script requires more than diffMaxEdits
+// edits.
+func myers(a, b []string) []DiffLine {
+	var (
+		n    = len(a)
+		m    = len(b)
+		maxD = n + m
+	)
+	if maxD > diffMaxEdits {
+		maxD = diffMaxEdits
+	}
+
+	// v contains the furthest reaching x position of each diagonal k,
+	// indexed by k+offset. trace contains a snapshot of the diagonals
+	// -d to d of v prior to each round d of the search. The snapshots
+	// are used to backtrack through the edit graph once the end has
+	// been reached.
+	var (
+		offset = maxD + 1
+		v      = make([]int, 2*maxD+3)
+		trace  = make([][]int, 0, 64)
+		found  bool
+	)
+	for d := 0; d <= maxD && !found; d++ {
+		snapshot := make([]int, 2*d+1)
+		copy(snapshot, v[offset-d:offset+d+1])
+		trace = append(trace, snapshot)
+
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				// Move down. This is an insertion.
+				x = v[offset+k+1]
+			} else {
+				// Move right. This is a deletion.
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				found = true
+				break
+			}
+		}
+	}
+	if !found {
+		// The edit script is too long. Remove all of the old lines
+		// and add all of the new lines.
+		lines := make([]DiffLine, 0, n+m)
+		for _, v := range a {
+			lines = append(lines, DiffLine{Type: DiffRemoved, Text: v})
+		}
+		for _, v := range b {
+			lines = append(lines, DiffLine{Type: DiffAdded, Text: v})
+		}
+		return lines
+	}
+
+	// Backtrack through the edit graph. The lines are compiled in
+	// reverse order.
+	var (
+		lines = make([]DiffLine, 0, n+m)
+		x     = n
+		y     = m
+	)
+	for d := len(trace) - 1; d >= 0; d-- {
+		if d == 0 {
+			// The remaining lines are the initial snake
+			for x > 0 && y > 0 {
+				lines = append(lines, DiffLine{Type: DiffUnchanged, Text: a[x-1]})
+				x--
+				y--
+			}
+			break
+		}
+
+		var (
+			vd   = trace[d]
+			k    = x - y
+			prev int
+		)
+		if k == -d || (k != d && vd[k-1+d] < vd[k+1+d]) {
+			prev = k + 1
+		} else {
+			prev = k - 1
+		}
+		prevX := vd[prev+d]
+		prevY := prevX - prev
+
+		for x > prevX && y > prevY {
+			lines = append(lines, DiffLine{Type: DiffUnchanged, Text: a[x-1]})
+			x--
+			y--
+		}
+		if x == prevX {
+			lines = append(lines, DiffLine{Type: DiffAdded, Text: b[y-1]})
+		} else {
+			lines = append(lines, DiffLine{Type: DiffRemoved, Text: a[x-1]})
+		}
+		x, y = prevX, prevY
+	}
+
+	// Reverse the lines
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
+
+	return lines
+}
+
+// hunks groups the changed lines of a line diff into hunks. Each hunk includes
+// up to context unchanged lines before and after the changed lines. Changes
+// that are separated by no more than twice the context are included in the
+// same hunk.
+func hunks(lines []DiffLine, context int) []DiffHunk {
+	// Compile the 0-based position of each line in the old and new
+	// text.
+	var (
+		fromPos = make([]int, len(lines)+1)
+		toPos   = make([]int, len(lines)+1)
+	)
+	for i, v := range lines {
+		fromPos[i+1] = fromPos[i]
+		toPos[i+1] = toPos[i]
+		if v.Type != DiffAdded {
+			fromPos[i+1]++
+		}
+		if v.Type != DiffRemoved {
+			toPos[i+1]++
+		}
+	}
+
+	var (
+		hs      = make([]DiffHunk, 0, 8)
+		prevEnd int
+		i       int
+	)
+	for {
+		// Find the next changed line
+		for i < len(lines) && lines[i].Type == DiffUnchanged {
+			i++
+		}
+		if i >= len(lines) {
+			break
+		}
+		start := i - context
+		if start < prevEnd {
+			start = prevEnd
+		}
+
+		// Find the end of the changes that belong to this hunk
+		end := i
+		for {
+			for end < len(lines) && lines[end].Type != DiffUnchanged {
+				end++
+			}
+			next := end
+			for next < len(lines) && lines[next].Type == DiffUnchanged {
+				next++
+			}
+			if next < len(lines) && next-end <= 2*context {
+				end = next
+				continue
+			}
+			break
+		}
+		end += context
+		if end > len(lines) {
+			end = len(lines)
+		}
+
+		hs = append(hs, DiffHunk{
+			FromLine:  uint32(fromPos[start] + 1),
+			FromLines: uint32(fromPos[end] - fromPos[start]),
+			ToLine:    uint32(toPos[start] + 1),
+			ToLines:   uint32(toPos[end] - toPos[start]),
+			Lines:     lines[start:end],
+		})
+		prevEnd = end
+		i = end
+	}
+
+	return hs
+}
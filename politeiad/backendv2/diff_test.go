@@ -0,0 +1,171 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package backendv2
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+)
+
+func TestDiffLines(t *testing.T) {
+	var (
+		u = func(s string) DiffLine { return DiffLine{DiffUnchanged, s} }
+		a = func(s string) DiffLine { return DiffLine{DiffAdded, s} }
+		r = func(s string) DiffLine { return DiffLine{DiffRemoved, s} }
+	)
+	tests := []struct {
+		name string
+		from []string
+		to   []string
+		want []DiffLine
+	}{
+		{
+			"empty",
+			[]string{},
+			[]string{},
+			[]DiffLine{},
+		},
+		{
+			"unchanged",
+			[]string{"a", "b"},
+			[]string{"a", "b"},
+			[]DiffLine{u("a"), u("b")},
+		},
+		{
+			"added",
+			[]string{},
+			[]string{"a", "b"},
+			[]DiffLine{a("a"), a("b")},
+		},
+		{
+			"removed",
+			[]string{"a", "b"},
+			[]string{},
+			[]DiffLine{r("a"), r("b")},
+		},
+		{
+			"modified",
+			[]string{"a", "b", "c", "d"},
+			[]string{"a", "x", "c", "d", "e"},
+			[]DiffLine{u("a"), r("b"), a("x"), u("c"), u("d"), a("e")},
+		},
+		{
+			"moved",
+			[]string{"a", "b", "c"},
+			[]string{"c", "a", "b"},
+			[]DiffLine{a("c"), u("a"), u("b"), r("c")},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := diffLines(tc.from, tc.to)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestHunks(t *testing.T) {
+	from := []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10",
+		"11", "12", "13", "14", "15"}
+	to := []string{"1", "2", "3", "4", "5", "six", "7", "8", "9", "10",
+		"11", "12", "13", "14", "15", "16"}
+
+	// The two changes are separated by more than twice the context
+	// and must be split into separate hunks.
+	hs := hunks(diffLines(from, to), 3)
+	if len(hs) != 2 {
+		t.Fatalf("got %v hunks, want 2", len(hs))
+	}
+	h := hs[0]
+	if h.FromLine != 3 || h.FromLines != 7 || h.ToLine != 3 || h.ToLines != 7 {
+		t.Errorf("hunk 0: got %v,%v %v,%v; want 3,7 3,7",
+			h.FromLine, h.FromLines, h.ToLine, h.ToLines)
+	}
+	h = hs[1]
+	if h.FromLine != 13 || h.FromLines != 3 || h.ToLine != 13 || h.ToLines != 4 {
+		t.Errorf("hunk 1: got %v,%v %v,%v; want 13,3 13,4",
+			h.FromLine, h.FromLines, h.ToLine, h.ToLines)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	file := func(name, mime, payload string) File {
+		return File{
+			Name:    name,
+			MIME:    mime,
+			Digest:  payload, // Only used for comparison
+			Payload: base64.StdEncoding.EncodeToString([]byte(payload)),
+		}
+	}
+	from := Record{
+		RecordMetadata: RecordMetadata{Version: 1, Status: StatusUnreviewed},
+		Metadata: []MetadataStream{
+			{PluginID: "usermd", StreamID: 2, Payload: `{"status":1}`},
+		},
+		Files: []File{
+			file("index.md", "text/plain; charset=utf-8", "a\nb\n"),
+			file("image.png", "image/png", "png1"),
+			file("removed.md", "text/plain; charset=utf-8", "x\n"),
+		},
+	}
+	to := Record{
+		RecordMetadata: RecordMetadata{Version: 2, Status: StatusPublic},
+		Metadata: []MetadataStream{
+			{PluginID: "usermd", StreamID: 2,
+				Payload: `{"status":1}{"status": 2}`},
+		},
+		Files: []File{
+			file("index.md", "text/plain; charset=utf-8", "a\nc\n"),
+			file("image.png", "image/png", "png2"),
+		},
+	}
+	d, err := Diff(from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Verify files
+	if len(d.Files) != 3 {
+		t.Fatalf("got %v file diffs, want 3", len(d.Files))
+	}
+	wantTypes := map[string]DiffT{
+		"image.png":  DiffModified,
+		"index.md":   DiffModified,
+		"removed.md": DiffRemoved,
+	}
+	for _, v := range d.Files {
+		if v.Type != wantTypes[v.Name] {
+			t.Errorf("%v: got type %v, want %v", v.Name,
+				Diffs[v.Type], Diffs[wantTypes[v.Name]])
+		}
+		switch v.Name {
+		case "image.png":
+			if len(v.Hunks) != 0 {
+				t.Errorf("image.png: got hunks for a binary file")
+			}
+		case "removed.md":
+			if len(v.Hunks) != 1 || v.Hunks[0].FromLines != 1 {
+				t.Errorf("removed.md: unexpected hunks %v", v.Hunks)
+			}
+		}
+	}
+
+	// Verify metadata. The appended status change must be a single
+	// added line.
+	if len(d.Metadata) != 1 {
+		t.Fatalf("got %v metadata diffs, want 1", len(d.Metadata))
+	}
+	want := []DiffLine{
+		{DiffUnchanged, `{"status":1}`},
+		{DiffAdded, `{"status":2}`},
+	}
+	got := d.Metadata[0].Hunks[0].Lines
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("metadata: got %v, want %v", got, want)
+	}
+}
@@ -6,274 +6,162 @@ package tstorebe
 
 import (
 	"encoding/hex"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"path/filepath"
 
 	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
 )
 
 const (
-	// Filenames of the inventory caches.
+	// Filenames of the legacy inventory caches. The inventory used to
+	// be saved to these files. It is now saved to an indexed inventory
+	// in the key-value store. The files are migrated to the indexed
+	// inventory on startup and then deleted.
 	filenameInvUnvetted = "inv-unvetted.json"
 	filenameInvVetted   = "inv-vetted.json"
-)
-
-// entry represents a record entry in the inventory.
-type entry struct {
-	Token  string          `json:"token"`
-	Status backend.StatusT `json:"status"`
-}
 
-// inventory represents the record inventory.
-type inventory struct {
-	Entries []entry `json:"entries"`
-}
+	// invQueryPageSize is the number of entries that are requested
+	// per query when the full inventory is being read.
+	invQueryPageSize uint32 = 1000
+)
 
-// invPathUnvetted returns the file path for the unvetted inventory.
+// invPathUnvetted returns the file path for the legacy unvetted inventory.
 func (t *tstoreBackend) invPathUnvetted() string {
 	return filepath.Join(t.dataDir, filenameInvUnvetted)
 }
 
-// invPathVetted returns the file path for the vetted inventory.
+// invPathVetted returns the file path for the legacy vetted inventory.
 func (t *tstoreBackend) invPathVetted() string {
 	return filepath.Join(t.dataDir, filenameInvVetted)
 }
 
-// invGetLocked retrieves the inventory from disk. A new inventory is returned
-// if one does not exist yet.
-//
-// This function must be called WITH the read lock held.
-func (t *tstoreBackend) invGetLocked(filePath string) (*inventory, error) {
-	b, err := ioutil.ReadFile(filePath)
-	if err != nil {
-		var e *os.PathError
-		if errors.As(err, &e) && !os.IsExist(err) {
-			// File does't exist. Return a new inventory.
-			return &inventory{
-				Entries: make([]entry, 0, 1024),
-			}, nil
-		}
-		return nil, err
-	}
-
-	var inv inventory
-	err = json.Unmarshal(b, &inv)
-	if err != nil {
-		return nil, err
+// invEntryNew returns a new inventory entry for the provided record metadata.
+func invEntryNew(rm backend.RecordMetadata, created int64) store.InvEntry {
+	return store.InvEntry{
+		Token:   rm.Token,
+		State:   uint32(rm.State),
+		Status:  uint32(rm.Status),
+		Created: created,
+		Updated: rm.Timestamp,
 	}
-
-	return &inv, nil
-}
-
-// invGet retrieves the inventory from disk. A new inventory is returned if one
-// does not exist yet.
-//
-// This function must be called WITHOUT the read lock held.
-func (t *tstoreBackend) invGet(filePath string) (*inventory, error) {
-	t.RLock()
-	defer t.RUnlock()
-
-	return t.invGetLocked(filePath)
 }
 
-// invSaveLocked writes the inventory to disk.
-//
-// This function must be called WITH the read/write lock held.
-func (t *tstoreBackend) invSaveLocked(filePath string, inv inventory) error {
-	b, err := json.Marshal(inv)
+// invAdd adds a new record to the inventory. The record metadata timestamp is
+// used as the created timestamp.
+func (t *tstoreBackend) invAdd(rm backend.RecordMetadata) error {
+	err := t.tstore.InvPut([]store.InvEntry{invEntryNew(rm, rm.Timestamp)})
 	if err != nil {
 		return err
 	}
-	return ioutil.WriteFile(filePath, b, 0664)
-}
 
-// invAdd adds a new record to the inventory.
-//
-// This function must be called WITHOUT the read/write lock held.
-func (t *tstoreBackend) invAdd(state backend.StateT, token []byte, s backend.StatusT) error {
-	// Get inventory file path
-	var fp string
-	switch state {
-	case backend.StateUnvetted:
-		fp = t.invPathUnvetted()
-	case backend.StateVetted:
-		fp = t.invPathVetted()
-	default:
-		return fmt.Errorf("invalid state %v", state)
-	}
-
-	t.Lock()
-	defer t.Unlock()
-
-	// Get inventory
-	inv, err := t.invGetLocked(fp)
-	if err != nil {
-		return err
-	}
-
-	// Prepend token
-	e := entry{
-		Token:  hex.EncodeToString(token),
-		Status: s,
-	}
-	inv.Entries = append([]entry{e}, inv.Entries...)
-
-	// Save inventory
-	err = t.invSaveLocked(fp, *inv)
-	if err != nil {
-		return err
-	}
-
-	log.Debugf("Inv add %v %x %v",
-		backend.States[state], token, backend.Statuses[s])
+	log.Debugf("Inv add %v %v %v", rm.Token,
+		backend.States[rm.State], backend.Statuses[rm.Status])
 
 	return nil
 }
 
-// invUpdate updates the status of a record in the inventory. The record state
-// must remain the same.
+// invUpdate updates the inventory entry of a record using the provided record
+// metadata. This includes any changes to the record state, record status, and
+// updated timestamp. The created timestamp and the plugin attributes of the
+// entry are preserved.
 //
-// This function must be called WITHOUT the read/write lock held.
-func (t *tstoreBackend) invUpdate(state backend.StateT, token []byte, s backend.StatusT) error {
-	// Get inventory file path
-	var fp string
-	switch state {
-	case backend.StateUnvetted:
-		fp = t.invPathUnvetted()
-	case backend.StateVetted:
-		fp = t.invPathVetted()
-	default:
-		return fmt.Errorf("invalid state %v", state)
-	}
-
-	t.Lock()
-	defer t.Unlock()
-
-	// Get inventory
-	inv, err := t.invGetLocked(fp)
+// This function must be called WITH the record lock held.
+func (t *tstoreBackend) invUpdate(rm backend.RecordMetadata) error {
+	entries, err := t.tstore.InvGet([]string{rm.Token})
 	if err != nil {
 		return err
 	}
-
-	// Del entry
-	entries, err := entryDel(inv.Entries, token)
-	if err != nil {
-		return fmt.Errorf("%v entry del: %v", state, err)
+	prev, ok := entries[rm.Token]
+	if !ok {
+		return fmt.Errorf("inventory entry not found")
 	}
-
-	// Prepend new entry to inventory
-	e := entry{
-		Token:  hex.EncodeToString(token),
-		Status: s,
-	}
-	inv.Entries = append([]entry{e}, entries...)
-
-	// Save inventory
-	err = t.invSaveLocked(fp, *inv)
+	err = t.tstore.InvPut([]store.InvEntry{invEntryNew(rm, prev.Created)})
 	if err != nil {
 		return err
 	}
 
-	log.Debugf("Inv update %v %x to %v",
-		backend.States[state], token, backend.Statuses[s])
-
-	return nil
-}
-
-// invMoveToVetted deletes a record from the unvetted inventory then adds it
-// to the vetted inventory.
-//
-// This function must be called WITHOUT the read/write lock held.
-func (t *tstoreBackend) invMoveToVetted(token []byte, s backend.StatusT) error {
-	var (
-		upath = t.invPathUnvetted()
-		vpath = t.invPathVetted()
-	)
-
-	t.Lock()
-	defer t.Unlock()
-
-	// Get unvetted inventory
-	u, err := t.invGetLocked(upath)
-	if err != nil {
-		return fmt.Errorf("unvetted invGetLocked: %v", err)
-	}
-
-	// Del entry
-	u.Entries, err = entryDel(u.Entries, token)
-	if err != nil {
-		return fmt.Errorf("entryDel: %v", err)
-	}
-
-	// Save unvetted inventory
-	err = t.invSaveLocked(upath, *u)
-	if err != nil {
-		return fmt.Errorf("unvetted invSaveLocked: %v", err)
-	}
-
-	// Get vetted inventory
-	v, err := t.invGetLocked(vpath)
-	if err != nil {
-		return fmt.Errorf("vetted invGetLocked: %v", err)
-	}
-
-	// Prepend new entry to inventory
-	e := entry{
-		Token:  hex.EncodeToString(token),
-		Status: s,
-	}
-	v.Entries = append([]entry{e}, v.Entries...)
-
-	// Save vetted inventory
-	err = t.invSaveLocked(vpath, *v)
-	if err != nil {
-		return fmt.Errorf("vetted invSaveLocked: %v", err)
-	}
-
-	log.Debugf("Inv move to vetted %x %v", token, backend.Statuses[s])
+	log.Debugf("Inv update %v %v %v", rm.Token,
+		backend.States[rm.State], backend.Statuses[rm.Status])
 
 	return nil
 }
 
 // inventoryAdd is a wrapper around the invAdd method that allows us to decide
 // how errors should be handled. For now we just panic. If an error occurs the
-// cache is no longer coherent and the only way to fix it is to rebuild it.
-func (t *tstoreBackend) inventoryAdd(state backend.StateT, token []byte, s backend.StatusT) {
-	err := t.invAdd(state, token, s)
+// inventory is no longer coherent and the only way to fix it is to run a fsck
+// repair.
+func (t *tstoreBackend) inventoryAdd(rm backend.RecordMetadata) {
+	err := t.invAdd(rm)
 	if err != nil {
-		panic(fmt.Sprintf("invAdd %v %x %v: %v", state, token, s, err))
+		panic(fmt.Sprintf("invAdd %v %v %v: %v",
+			rm.Token, rm.State, rm.Status, err))
 	}
 }
 
 // inventoryUpdate is a wrapper around the invUpdate method that allows us to
-// decide how disk read/write errors should be handled. For now we just panic.
-// If an error occurs the cache is no longer coherent and the only way to fix
-// it is to rebuild it.
-func (t *tstoreBackend) inventoryUpdate(state backend.StateT, token []byte, s backend.StatusT) {
-	err := t.invUpdate(state, token, s)
+// decide how errors should be handled. For now we just panic. If an error
+// occurs the inventory is no longer coherent and the only way to fix it is to
+// run a fsck repair.
+func (t *tstoreBackend) inventoryUpdate(rm backend.RecordMetadata) {
+	err := t.invUpdate(rm)
 	if err != nil {
-		panic(fmt.Sprintf("invUpdate %v %x %v: %v", state, token, s, err))
+		panic(fmt.Sprintf("invUpdate %v %v %v: %v",
+			rm.Token, rm.State, rm.Status, err))
 	}
 }
 
-// inventoryMoveToVetted is a wrapper around the invMoveToVetted method that
-// allows us to decide how disk read/write errors should be handled. For now we
-// just panic. If an error occurs the cache is no longer coherent and the only
-// way to fix it is to rebuild it.
-func (t *tstoreBackend) inventoryMoveToVetted(token []byte, s backend.StatusT) {
-	err := t.invMoveToVetted(token, s)
+// invTokens returns the tokens of the provided inventory entries.
+func invTokens(entries []store.InvEntry) []string {
+	tokens := make([]string, 0, len(entries))
+	for _, v := range entries {
+		tokens = append(tokens, v.Token)
+	}
+	return tokens
+}
+
+// invPage returns the tokens for the requested page of the inventory. Pages
+// are numbered starting at 1 and contain the inventory entries sorted by the
+// updated timestamp from newest to oldest.
+//
+// The page number based inventory routes predate the cursor based inventory
+// queries. The entries of prior pages are skipped by requesting them using a
+// single query and then using the returned cursor to request the page.
+func (t *tstoreBackend) invPage(state backend.StateT, s backend.StatusT, pageSize, page uint32) ([]string, error) {
+	if pageSize == 0 || page == 0 {
+		return []string{}, nil
+	}
+	q := store.InvQuery{
+		State:  uint32(state),
+		Status: uint32(s),
+		Sort:   store.InvSortUpdated,
+		Limit:  pageSize,
+	}
+	if page > 1 {
+		skip := q
+		skip.Limit = (page - 1) * pageSize
+		p, err := t.tstore.InvQuery(skip)
+		if err != nil {
+			return nil, err
+		}
+		if p.Cursor == "" {
+			// The inventory does not contain the requested page
+			return []string{}, nil
+		}
+		q.Cursor = p.Cursor
+	}
+	p, err := t.tstore.InvQuery(q)
 	if err != nil {
-		panic(fmt.Sprintf("invMoveToVetted %x %v: %v", token, s, err))
+		return nil, err
 	}
+	return invTokens(p.Entries), nil
 }
 
 // invByStatus contains the inventory categorized by record state and record
 // status. Each list contains a page of tokens that are sorted by the timestamp
-// of the status change from newest to oldest.
+// of the most recent record update from newest to oldest.
 type invByStatus struct {
 	Unvetted map[backend.StatusT][]string
 	Vetted   map[backend.StatusT][]string
@@ -281,63 +169,45 @@ type invByStatus struct {
 
 // invByStatusAll returns a page of tokens for all record states and statuses.
 func (t *tstoreBackend) invByStatusAll(pageSize uint32) (*invByStatus, error) {
-	// Get unvetted inventory
-	u, err := t.invGet(t.invPathUnvetted())
-	if err != nil {
-		return nil, err
-	}
-
-	// Prepare unvetted inventory reply
 	var (
-		unvetted = tokensParse(u.Entries, backend.StatusUnreviewed, pageSize, 1)
-		censored = tokensParse(u.Entries, backend.StatusCensored, pageSize, 1)
-		archived = tokensParse(u.Entries, backend.StatusArchived, pageSize, 1)
-
-		unvettedInv = make(map[backend.StatusT][]string, 16)
-	)
-	if len(unvetted) != 0 {
-		unvettedInv[backend.StatusUnreviewed] = unvetted
-	}
-	if len(censored) != 0 {
-		unvettedInv[backend.StatusCensored] = censored
-	}
-	if len(archived) != 0 {
-		unvettedInv[backend.StatusArchived] = archived
-	}
-
-	// Get vetted inventory
-	v, err := t.invGet(t.invPathVetted())
-	if err != nil {
-		return nil, err
-	}
-
-	// Prepare vetted inventory reply
-	var (
-		vetted    = tokensParse(v.Entries, backend.StatusPublic, pageSize, 1)
-		vcensored = tokensParse(v.Entries, backend.StatusCensored, pageSize, 1)
-		varchived = tokensParse(v.Entries, backend.StatusArchived, pageSize, 1)
-
-		vettedInv = make(map[backend.StatusT][]string, 16)
+		statuses = map[backend.StateT][]backend.StatusT{
+			backend.StateUnvetted: {
+				backend.StatusUnreviewed,
+				backend.StatusCensored,
+				backend.StatusArchived,
+			},
+			backend.StateVetted: {
+				backend.StatusPublic,
+				backend.StatusCensored,
+				backend.StatusArchived,
+			},
+		}
+		inv = map[backend.StateT]map[backend.StatusT][]string{
+			backend.StateUnvetted: make(map[backend.StatusT][]string, 16),
+			backend.StateVetted:   make(map[backend.StatusT][]string, 16),
+		}
 	)
-	if len(vetted) != 0 {
-		vettedInv[backend.StatusPublic] = vetted
-	}
-	if len(vcensored) != 0 {
-		vettedInv[backend.StatusCensored] = vcensored
-	}
-	if len(varchived) != 0 {
-		vettedInv[backend.StatusArchived] = varchived
+	for state, ss := range statuses {
+		for _, s := range ss {
+			tokens, err := t.invPage(state, s, pageSize, 1)
+			if err != nil {
+				return nil, err
+			}
+			if len(tokens) != 0 {
+				inv[state][s] = tokens
+			}
+		}
 	}
 
 	return &invByStatus{
-		Unvetted: unvettedInv,
-		Vetted:   vettedInv,
+		Unvetted: inv[backend.StateUnvetted],
+		Vetted:   inv[backend.StateVetted],
 	}, nil
 }
 
 // invByStatus returns the tokens of records in the inventory categorized by
 // record state and record status. The tokens are ordered by the timestamp of
-// their most recent status change, sorted from newest to oldest.
+// their most recent update, sorted from newest to oldest.
 //
 // The state, status, and page arguments can be provided to request a specific
 // page of record tokens.
@@ -351,26 +221,19 @@ func (t *tstoreBackend) invByStatus(state backend.StateT, s backend.StatusT, pag
 		return t.invByStatusAll(pageSize)
 	}
 
-	// Get inventory file path
-	var fp string
 	switch state {
-	case backend.StateUnvetted:
-		fp = t.invPathUnvetted()
-	case backend.StateVetted:
-		fp = t.invPathVetted()
+	case backend.StateUnvetted, backend.StateVetted:
+		// Allowed; continue
 	default:
 		return nil, fmt.Errorf("unknown state '%v'", state)
 	}
 
-	// Get inventory
-	inv, err := t.invGet(fp)
+	// Get the page of tokens
+	tokens, err := t.invPage(state, s, pageSize, page)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get the page of tokens
-	tokens := tokensParse(inv.Entries, s, pageSize, page)
-
 	// Prepare reply
 	var ibs invByStatus
 	switch state {
@@ -394,96 +257,285 @@ func (t *tstoreBackend) invByStatus(state backend.StateT, s backend.StatusT, pag
 }
 
 // invOrdered returns a page of record tokens ordered by the timestamp of their
-// most recent status change. The returned tokens will include tokens for all
-// record statuses.
+// most recent update. The returned tokens will include tokens for all record
+// statuses.
 func (t *tstoreBackend) invOrdered(state backend.StateT, pageSize, pageNumber uint32) ([]string, error) {
-	// Get inventory file path
-	var fp string
 	switch state {
-	case backend.StateUnvetted:
-		fp = t.invPathUnvetted()
-	case backend.StateVetted:
-		fp = t.invPathVetted()
+	case backend.StateUnvetted, backend.StateVetted:
+		// Allowed; continue
 	default:
 		return nil, fmt.Errorf("unknown state '%v'", state)
 	}
 
-	// Get inventory
-	inv, err := t.invGet(fp)
+	return t.invPage(state, backend.StatusInvalid, pageSize, pageNumber)
+}
+
+// invQuery returns a page of inventory entries that match the provided query.
+func (t *tstoreBackend) invQuery(q backend.InventoryQuery) (*backend.InventoryPage, error) {
+	var s store.InvSortT
+	switch q.Sort {
+	case backend.InventorySortUpdated:
+		s = store.InvSortUpdated
+	case backend.InventorySortCreated:
+		s = store.InvSortCreated
+	default:
+		return nil, backend.ErrInventoryQueryInvalid
+	}
+	p, err := t.tstore.InvQuery(store.InvQuery{
+		State:      uint32(q.State),
+		Status:     uint32(q.Status),
+		Attributes: q.Attributes,
+		Sort:       s,
+		Ascending:  q.Ascending,
+		Cursor:     q.Cursor,
+		Limit:      q.Limit,
+	})
 	if err != nil {
+		if errors.Is(err, store.ErrInvCursorInvalid) {
+			return nil, backend.ErrInventoryQueryInvalid
+		}
 		return nil, err
 	}
 
-	// Return specified page of tokens
+	entries := make([]backend.InventoryEntry, 0, len(p.Entries))
+	for _, v := range p.Entries {
+		entries = append(entries, backend.InventoryEntry{
+			Token:      v.Token,
+			State:      backend.StateT(v.State),
+			Status:     backend.StatusT(v.Status),
+			Created:    v.Created,
+			Updated:    v.Updated,
+			Attributes: v.Attributes,
+		})
+	}
+
+	return &backend.InventoryPage{
+		Entries: entries,
+		Cursor:  p.Cursor,
+	}, nil
+}
+
+// invAll returns all entries in the inventory.
+func (t *tstoreBackend) invAll() (map[string]store.InvEntry, error) {
 	var (
-		startIdx = int((pageNumber - 1) * pageSize)
-		endIdx   = startIdx + int(pageSize)
-		tokens   = make([]string, 0, pageSize)
+		entries = make(map[string]store.InvEntry, 1024)
+		q       = store.InvQuery{
+			Sort:  store.InvSortCreated,
+			Limit: invQueryPageSize,
+		}
 	)
-	for i := startIdx; i < endIdx; i++ {
-		if i >= len(inv.Entries) {
-			// We've reached the end of the inventory. We're done.
+	for {
+		p, err := t.tstore.InvQuery(q)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range p.Entries {
+			entries[v.Token] = v
+		}
+		if p.Cursor == "" {
 			break
 		}
+		q.Cursor = p.Cursor
+	}
+	return entries, nil
+}
 
-		tokens = append(tokens, inv.Entries[i].Token)
+// invRecords returns the record metadata of all records in the tstore.
+func (t *tstoreBackend) invRecords() (map[string]backend.RecordMetadata, error) {
+	tokens, err := t.tstore.Inventory()
+	if err != nil {
+		return nil, fmt.Errorf("tstore Inventory: %v", err)
+	}
+	records := make(map[string]backend.RecordMetadata, len(tokens))
+	for _, v := range tokens {
+		r, err := t.tstore.RecordPartial(v, 0, nil, true)
+		if errors.Is(err, backend.ErrRecordNotFound) {
+			// The tree exists but does not contain a record. This can
+			// happen if an unexpected error occurred during record
+			// creation.
+			continue
+		} else if err != nil {
+			// The tstore fsck is responsible for reporting any issues
+			// with the record content. Skip the record.
+			log.Errorf("invRecords RecordPartial %x: %v", v, err)
+			continue
+		}
+		records[hex.EncodeToString(v)] = r.RecordMetadata
 	}
+	return records, nil
+}
 
-	return tokens, nil
+// invEntryBuild returns an inventory entry for the provided record metadata.
+// The created timestamp is looked up in the tstore.
+func (t *tstoreBackend) invEntryBuild(rm backend.RecordMetadata) (*store.InvEntry, error) {
+	token, err := hex.DecodeString(rm.Token)
+	if err != nil {
+		return nil, err
+	}
+	created, err := t.tstore.RecordCreated(token)
+	if err != nil {
+		return nil, fmt.Errorf("RecordCreated %v: %v", rm.Token, err)
+	}
+	e := invEntryNew(rm, created)
+	return &e, nil
 }
 
-// entryDel removes the entry for the token and returns the updated slice.
-func entryDel(entries []entry, token []byte) ([]entry, error) {
-	// Find token in entries
-	var i int
-	var found bool
-	htoken := hex.EncodeToString(token)
-	for k, v := range entries {
-		if v.Token == htoken {
-			i = k
-			found = true
-			break
+// invMigrate builds the indexed inventory from the records in the tstore if
+// the legacy inventory files exist. The legacy files are deleted once the
+// indexed inventory has been built.
+func (t *tstoreBackend) invMigrate() error {
+	paths := make([]string, 0, 2)
+	for _, fp := range []string{t.invPathUnvetted(), t.invPathVetted()} {
+		if _, err := os.Stat(fp); err == nil {
+			paths = append(paths, fp)
 		}
 	}
-	if !found {
-		return nil, fmt.Errorf("token not found %x", token)
+	if len(paths) == 0 {
+		// Nothing to migrate
+		return nil
 	}
 
-	// Del token from entries (linear time)
-	copy(entries[i:], entries[i+1:])   // Shift entries[i+1:] left one index
-	entries[len(entries)-1] = entry{}  // Del last element (write zero value)
-	entries = entries[:len(entries)-1] // Truncate slice
+	log.Infof("Migrating the inventory to the indexed inventory")
 
-	return entries, nil
+	records, err := t.invRecords()
+	if err != nil {
+		return err
+	}
+	entries := make([]store.InvEntry, 0, invQueryPageSize)
+	for _, rm := range records {
+		e, err := t.invEntryBuild(rm)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, *e)
+		if uint32(len(entries)) < invQueryPageSize {
+			continue
+		}
+		err = t.tstore.InvPut(entries)
+		if err != nil {
+			return fmt.Errorf("InvPut: %v", err)
+		}
+		entries = entries[:0]
+	}
+	if len(entries) > 0 {
+		err = t.tstore.InvPut(entries)
+		if err != nil {
+			return fmt.Errorf("InvPut: %v", err)
+		}
+	}
+	for _, fp := range paths {
+		err = os.Remove(fp)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Infof("%v records added to the indexed inventory", len(records))
+
+	return nil
 }
 
-// tokensParse parses a page of tokens from the provided entries that meet the
-// provided criteria.
-func tokensParse(entries []entry, s backend.StatusT, countPerPage, page uint32) []string {
-	tokens := make([]string, 0, countPerPage)
-	if countPerPage == 0 || page == 0 {
-		return tokens
+// invFsck verifies that the inventory is coherent with the records in the
+// tstore. Every record in the tstore must have an inventory entry with a
+// record state, record status, and updated timestamp that match its latest
+// record metadata. If repair is set to true then the inventory will be updated
+// to match the tstore. The plugin attributes of existing entries are
+// preserved.
+func (t *tstoreBackend) invFsck(repair bool) ([]backend.FsckIssue, error) {
+	// Compile the records that exist in the tstore
+	records, err := t.invRecords()
+	if err != nil {
+		return nil, err
+	}
+
+	// Compile the inventory entries
+	entries, err := t.invAll()
+	if err != nil {
+		return nil, err
 	}
 
-	startAt := (page - 1) * countPerPage
-	var foundCount uint32
-	for _, v := range entries {
-		if v.Status != s {
-			// Status does not match
+	var (
+		issues = make([]backend.FsckIssue, 0, 16)
+		put    = make([]store.InvEntry, 0, 16)
+		del    = make([]string, 0, 16)
+	)
+	for token, e := range entries {
+		rm, ok := records[token]
+		var desc string
+		switch {
+		case !ok:
+			desc = "inventory entry does not correspond to a record"
+			del = append(del, token)
+		case uint32(rm.State) != e.State:
+			desc = fmt.Sprintf("inventory state is %v, want %v",
+				backend.States[backend.StateT(e.State)],
+				backend.States[rm.State])
+		case uint32(rm.Status) != e.Status:
+			desc = fmt.Sprintf("inventory status is %v, want %v",
+				backend.Statuses[backend.StatusT(e.Status)],
+				backend.Statuses[rm.Status])
+		case rm.Timestamp != e.Updated:
+			desc = fmt.Sprintf("inventory updated timestamp is %v, want %v",
+				e.Updated, rm.Timestamp)
+		default:
+			// Entry is coherent
 			continue
 		}
+		if ok {
+			put = append(put, invEntryNew(rm, e.Created))
+		}
+		issues = append(issues, backend.FsckIssue{
+			Type:        backend.FsckIssueInventory,
+			Token:       token,
+			Description: desc,
+		})
+	}
 
-		// Matching status found
-		if foundCount >= startAt {
-			tokens = append(tokens, v.Token)
-			if len(tokens) == int(countPerPage) {
-				// We have a full page. We're done.
-				return tokens
-			}
+	// Find the records that are missing from the inventory
+	for token, rm := range records {
+		if _, ok := entries[token]; ok {
+			continue
 		}
+		issues = append(issues, backend.FsckIssue{
+			Type:  backend.FsckIssueInventory,
+			Token: token,
+			Description: fmt.Sprintf("%v %v record not found in inventory",
+				backend.States[rm.State], backend.Statuses[rm.Status]),
+		})
+		if !repair {
+			continue
+		}
+		e, err := t.invEntryBuild(rm)
+		if err != nil {
+			return nil, err
+		}
+		put = append(put, *e)
+	}
+	if !repair || len(issues) == 0 {
+		return issues, nil
+	}
 
-		foundCount++
+	// Repair the inventory
+	if len(put) > 0 {
+		err = t.tstore.InvPut(put)
+		if err != nil {
+			return nil, fmt.Errorf("InvPut: %v", err)
+		}
+	}
+	if len(del) > 0 {
+		err = t.tstore.InvDel(del)
+		if err != nil {
+			return nil, fmt.Errorf("InvDel: %v", err)
+		}
 	}
 
-	return tokens
+	// The issues are only marked as repaired once the inventory has
+	// been saved.
+	for k := range issues {
+		issues[k].Repaired = true
+	}
+
+	log.Infof("Inventory repaired: %v issues", len(issues))
+
+	return issues, nil
 }
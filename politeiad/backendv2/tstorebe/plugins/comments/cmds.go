@@ -450,12 +450,13 @@ func (p *commentsPlugin) cmdNew(token []byte, payload string) (string, error) {
 	}
 
 	// Verify comment
-	if len(n.Comment) > int(p.commentLengthMax) {
+	lengthMax := p.settingsGet().commentLengthMax
+	if len(n.Comment) > int(lengthMax) {
 		return "", backend.PluginError{
 			PluginID:  comments.PluginID,
 			ErrorCode: uint32(comments.ErrorCodeMaxLengthExceeded),
 			ErrorContext: fmt.Sprintf("max length is %v characters",
-				p.commentLengthMax),
+				lengthMax),
 		}
 	}
 
@@ -569,12 +570,13 @@ func (p *commentsPlugin) cmdEdit(token []byte, payload string) (string, error) {
 	}
 
 	// Verify comment
-	if len(e.Comment) > int(p.commentLengthMax) {
+	lengthMax := p.settingsGet().commentLengthMax
+	if len(e.Comment) > int(lengthMax) {
 		return "", backend.PluginError{
 			PluginID:  comments.PluginID,
 			ErrorCode: uint32(comments.ErrorCodeMaxLengthExceeded),
 			ErrorContext: fmt.Sprintf("max length is %v characters",
-				p.commentLengthMax),
+				lengthMax),
 		}
 	}
 
@@ -872,7 +874,7 @@ func (p *commentsPlugin) cmdVote(token []byte, payload string) (string, error) {
 	}
 
 	// Verify user has not exceeded max allowed vote changes
-	if len(cidx.Votes[v.UserID]) > int(p.voteChangesMax) {
+	if len(cidx.Votes[v.UserID]) > int(p.settingsGet().voteChangesMax) {
 		return "", backend.PluginError{
 			PluginID:  comments.PluginID,
 			ErrorCode: uint32(comments.ErrorCodeVoteChangesMaxExceeded),
@@ -39,9 +39,10 @@ type commentsPlugin struct {
 	// prove the backend received and processed a plugin command.
 	identity *identity.FullIdentity
 
-	// Plugin settings
-	commentLengthMax uint32
-	voteChangesMax   uint32
+	// Plugin settings. The settings can be updated at runtime and are
+	// protected by the settings mutex.
+	mtxSettings sync.RWMutex
+	settings    pluginSettings
 }
 
 // Setup performs any plugin setup that is required.
@@ -89,7 +90,12 @@ func (p *commentsPlugin) Cmd(token []byte, cmd, payload string) (string, error)
 //
 // This function satisfies the plugins PluginClient interface.
 func (p *commentsPlugin) Hook(h plugins.HookT, payload string) error {
-	log.Tracef("comments Hook: %x %v", plugins.Hooks[h])
+	log.Tracef("comments Hook: %v", plugins.Hooks[h])
+
+	switch h {
+	case plugins.HookTypeRecordImportPost:
+		return p.hookRecordImportPost(payload)
+	}
 
 	return nil
 }
@@ -106,24 +112,75 @@ func (p *commentsPlugin) Fsck() error {
 	return nil
 }
 
+// CacheRebuild deletes the cached record indexes and rebuilds them from the
+// comment blobs that have been saved to tstore.
+//
+// This function satisfies the plugins PluginClient interface.
+func (p *commentsPlugin) CacheRebuild(tokens [][]byte, progress func()) error {
+	log.Tracef("comments CacheRebuild: %v records", len(tokens))
+
+	err := p.recordIndexesDel()
+	if err != nil {
+		return fmt.Errorf("recordIndexesDel: %v", err)
+	}
+	for _, token := range tokens {
+		state, err := p.tstore.RecordState(token)
+		if err != nil {
+			return fmt.Errorf("RecordState %x: %v", token, err)
+		}
+		ridx, err := p.recordIndexBuild(token)
+		if err != nil {
+			return fmt.Errorf("recordIndexBuild %x: %v", token, err)
+		}
+		if len(ridx.Comments) > 0 {
+			err = p._recordIndexSave(token, state, *ridx)
+			if err != nil {
+				return err
+			}
+		}
+		progress()
+	}
+
+	return nil
+}
+
 // Settings returns the plugin settings.
 //
 // This function satisfies the plugins PluginClient interface.
 func (p *commentsPlugin) Settings() []backend.PluginSetting {
 	log.Tracef("comments Settings")
 
+	s := p.settingsGet()
 	return []backend.PluginSetting{
 		{
 			Key:   comments.SettingKeyCommentLengthMax,
-			Value: strconv.FormatUint(uint64(p.commentLengthMax), 10),
+			Value: strconv.FormatUint(uint64(s.commentLengthMax), 10),
 		},
 		{
 			Key:   comments.SettingKeyVoteChangesMax,
-			Value: strconv.FormatUint(uint64(p.voteChangesMax), 10),
+			Value: strconv.FormatUint(uint64(s.voteChangesMax), 10),
 		},
 	}
 }
 
+// SettingsUpdate updates the plugin settings at runtime.
+//
+// This function satisfies the plugins PluginClient interface.
+func (p *commentsPlugin) SettingsUpdate(settings []backend.PluginSetting) error {
+	log.Tracef("comments SettingsUpdate: %v", settings)
+
+	p.mtxSettings.Lock()
+	defer p.mtxSettings.Unlock()
+
+	s, err := settingsParse(p.settings, settings)
+	if err != nil {
+		return err
+	}
+	p.settings = s
+
+	return nil
+}
+
 // New returns a new comments plugin.
 func New(tstore plugins.TstoreClient, settings []backend.PluginSetting, dataDir string, id *identity.FullIdentity) (*commentsPlugin, error) {
 	// Setup comments plugin data dir
@@ -133,39 +190,19 @@ func New(tstore plugins.TstoreClient, settings []backend.PluginSetting, dataDir
 		return nil, err
 	}
 
-	// Default plugin settings
-	var (
-		commentLengthMax = comments.SettingCommentLengthMax
-		voteChangesMax   = comments.SettingVoteChangesMax
-	)
-
-	// Override defaults with any passed in settings
-	for _, v := range settings {
-		switch v.Key {
-		case comments.SettingKeyCommentLengthMax:
-			u, err := strconv.ParseUint(v.Value, 10, 64)
-			if err != nil {
-				return nil, fmt.Errorf("invalid plugin setting %v '%v': %v",
-					v.Key, v.Value, err)
-			}
-			commentLengthMax = uint32(u)
-		case comments.SettingKeyVoteChangesMax:
-			u, err := strconv.ParseUint(v.Value, 10, 64)
-			if err != nil {
-				return nil, fmt.Errorf("invalid plugin setting %v '%v': %v",
-					v.Key, v.Value, err)
-			}
-			voteChangesMax = uint32(u)
-		default:
-			return nil, fmt.Errorf("invalid comments plugin setting '%v'", v.Key)
-		}
+	// Override the default settings with any passed in settings
+	s, err := settingsParse(pluginSettings{
+		commentLengthMax: comments.SettingCommentLengthMax,
+		voteChangesMax:   comments.SettingVoteChangesMax,
+	}, settings)
+	if err != nil {
+		return nil, err
 	}
 
 	return &commentsPlugin{
-		tstore:           tstore,
-		identity:         id,
-		dataDir:          dataDir,
-		commentLengthMax: commentLengthMax,
-		voteChangesMax:   voteChangesMax,
+		tstore:   tstore,
+		identity: id,
+		dataDir:  dataDir,
+		settings: s,
 	}, nil
 }
@@ -0,0 +1,37 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package comments
+
+import (
+	"encoding/json"
+
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
+)
+
+// hookRecordImportPost builds the cached record index of a record that has
+// been imported from the comment blobs that were imported with the record.
+func (p *commentsPlugin) hookRecordImportPost(payload string) error {
+	var ri plugins.HookRecordImportPost
+	err := json.Unmarshal([]byte(payload), &ri)
+	if err != nil {
+		return err
+	}
+	rm := ri.Record.RecordMetadata
+
+	token, err := tokenDecode(rm.Token)
+	if err != nil {
+		return err
+	}
+	ridx, err := p.recordIndexBuild(token)
+	if err != nil {
+		return err
+	}
+	if len(ridx.Comments) == 0 {
+		// Nothing to cache
+		return nil
+	}
+
+	return p._recordIndexSave(token, rm.State, *ridx)
+}
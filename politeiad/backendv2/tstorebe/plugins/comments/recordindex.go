@@ -5,6 +5,7 @@
 package comments
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -14,6 +15,7 @@ import (
 	"strings"
 
 	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
 	"github.com/decred/politeia/politeiad/plugins/comments"
 	"github.com/decred/politeia/util"
 )
@@ -143,3 +145,134 @@ func (p *commentsPlugin) recordIndexSave(token []byte, s backend.StateT, ridx re
 		panic(err)
 	}
 }
+
+// recordIndexBuild builds the record index of a record from the comment blobs
+// that have been saved to tstore. The blobs are replayed in the order that
+// they were saved. The comment add blobs of a deleted comment no longer exist,
+// so only the comment del digest is indexed for deleted comments.
+func (p *commentsPlugin) recordIndexBuild(token []byte) (*recordIndex, error) {
+	ridx := recordIndex{
+		Comments: make(map[uint32]commentIndex),
+	}
+	commentIndexGet := func(commentID uint32) commentIndex {
+		cidx, ok := ridx.Comments[commentID]
+		if !ok {
+			cidx = commentIndex{
+				Adds:  make(map[uint32][]byte),
+				Votes: make(map[string][]voteIndex),
+			}
+		}
+		return cidx
+	}
+
+	// Replay the comment adds
+	adds, err := p.blobsByDataDesc(token, dataDescriptorCommentAdd)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range adds {
+		ca, err := convertCommentAddFromBlobEntry(v)
+		if err != nil {
+			return nil, err
+		}
+		digest, err := hex.DecodeString(v.Digest)
+		if err != nil {
+			return nil, err
+		}
+		cidx := commentIndexGet(ca.CommentID)
+		cidx.Adds[ca.Version] = digest
+		ridx.Comments[ca.CommentID] = cidx
+	}
+
+	// Replay the comment dels
+	dels, err := p.blobsByDataDesc(token, dataDescriptorCommentDel)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range dels {
+		cd, err := convertCommentDelFromBlobEntry(v)
+		if err != nil {
+			return nil, err
+		}
+		digest, err := hex.DecodeString(v.Digest)
+		if err != nil {
+			return nil, err
+		}
+		cidx := commentIndexGet(cd.CommentID)
+		cidx.Del = digest
+		ridx.Comments[cd.CommentID] = cidx
+	}
+
+	// Replay the comment votes. The votes must be replayed in the
+	// order that they were cast since the effect of a vote depends on
+	// the prior votes of the user.
+	votes, err := p.blobsByDataDesc(token, dataDescriptorCommentVote)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range votes {
+		cv, err := convertCommentVoteFromBlobEntry(v)
+		if err != nil {
+			return nil, err
+		}
+		digest, err := hex.DecodeString(v.Digest)
+		if err != nil {
+			return nil, err
+		}
+		cidx := commentIndexGet(cv.CommentID)
+		cidx.Votes[cv.UserID] = append(cidx.Votes[cv.UserID], voteIndex{
+			Vote:   cv.Vote,
+			Digest: digest,
+		})
+		ridx.Comments[cv.CommentID] = cidx
+	}
+
+	return &ridx, nil
+}
+
+// blobsByDataDesc returns the blobs of a record that match the provided data
+// descriptor, ordered from oldest to newest. Blobs that have been deleted are
+// not included.
+func (p *commentsPlugin) blobsByDataDesc(token []byte, dataDesc string) ([]store.BlobEntry, error) {
+	digests, err := p.tstore.DigestsByDataDesc(token, []string{dataDesc})
+	if err != nil {
+		return nil, err
+	}
+	blobs, err := p.tstore.Blobs(token, digests)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]store.BlobEntry, 0, len(blobs))
+	for _, v := range digests {
+		be, ok := blobs[hex.EncodeToString(v)]
+		if !ok {
+			// Blob has been deleted
+			continue
+		}
+		entries = append(entries, be)
+	}
+	return entries, nil
+}
+
+// recordIndexesDel deletes all cached record indexes from the comments plugin
+// data dir.
+func (p *commentsPlugin) recordIndexesDel() error {
+	p.Lock()
+	defer p.Unlock()
+
+	for _, fn := range []string{fnRecordIndexUnvetted, fnRecordIndexVetted} {
+		pattern := strings.Replace(fn, "{shorttoken}", "*", 1)
+		files, err := filepath.Glob(filepath.Join(p.dataDir, pattern))
+		if err != nil {
+			return err
+		}
+		for _, v := range files {
+			err = os.Remove(v)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,290 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package comments
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/decred/politeia/politeiad/api/v1/identity"
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
+	"github.com/decred/politeia/politeiad/plugins/comments"
+	"github.com/decred/politeia/util"
+)
+
+// newTestCommentsPlugin returns a commentsPlugin that has been setup for
+// testing and a closure that cleans up the test data when invoked.
+func newTestCommentsPlugin(t *testing.T) (*commentsPlugin, *plugins.TestTstore, func()) {
+	t.Helper()
+
+	dataDir, err := ioutil.TempDir("", comments.PluginID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := identity.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tstore := plugins.NewTestTstore()
+	p, err := New(tstore, nil, dataDir, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return p, tstore, func() {
+		os.RemoveAll(dataDir)
+	}
+}
+
+// recordIndexNormalize returns a copy of the record index that can be compared
+// to a record index that was built using recordIndexBuild. The add digests of
+// a deleted comment remain in the index that is updated by the del command,
+// but the add blobs have been deleted so they can't be rebuilt. Empty maps are
+// replaced with nil maps.
+func recordIndexNormalize(ridx recordIndex) recordIndex {
+	n := recordIndex{
+		Comments: make(map[uint32]commentIndex, len(ridx.Comments)),
+	}
+	for k, v := range ridx.Comments {
+		if v.Del != nil || len(v.Adds) == 0 {
+			v.Adds = nil
+		}
+		if len(v.Votes) == 0 {
+			v.Votes = nil
+		}
+		n.Comments[k] = v
+	}
+	return n
+}
+
+func TestCacheRebuild(t *testing.T) {
+	p, tstore, cleanup := newTestCommentsPlugin(t)
+	defer cleanup()
+
+	// Setup the records. A vetted record with comments, an unvetted
+	// record with comments, and a vetted record without comments.
+	newRecord := func(i byte, s backend.StateT) []byte {
+		token := util.Digest([]byte{i})[:8]
+		status := backend.StatusPublic
+		if s == backend.StateUnvetted {
+			status = backend.StatusUnreviewed
+		}
+		tstore.RecordSave(backend.Record{
+			RecordMetadata: backend.RecordMetadata{
+				Token:     hex.EncodeToString(token),
+				Version:   1,
+				Iteration: 1,
+				State:     s,
+				Status:    status,
+			},
+		})
+		return token
+	}
+	var (
+		vetted   = newRecord(1, backend.StateVetted)
+		unvetted = newRecord(2, backend.StateUnvetted)
+		empty    = newRecord(3, backend.StateVetted)
+		tokens   = [][]byte{vetted, unvetted, empty}
+	)
+
+	// Setup the users
+	type user struct {
+		id       string
+		identity *identity.FullIdentity
+	}
+	newUser := func(id string) user {
+		fid, err := identity.New()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return user{
+			id:       id,
+			identity: fid,
+		}
+	}
+	var (
+		user1 = newUser("0b9a1e3c-4d1b-4a63-9b1b-6f1a2f0c2e11")
+		user2 = newUser("7d5f4c1e-2a8b-4e0e-8f3c-1c2d3e4f5a6b")
+	)
+
+	// sign returns the public key and signature of the message. exec
+	// executes a plugin command.
+	sign := func(u user, msg string) (string, string) {
+		sig := u.identity.SignMessage([]byte(msg))
+		return u.identity.Public.String(), hex.EncodeToString(sig[:])
+	}
+	exec := func(token []byte, c string, payload interface{}) {
+		t.Helper()
+		b, err := json.Marshal(payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = p.Cmd(token, c, string(b))
+		if err != nil {
+			t.Fatalf("%v: %v", c, err)
+		}
+	}
+	newComment := func(token []byte, s comments.RecordStateT, u user, parentID uint32, comment string) {
+		t.Helper()
+		n := comments.New{
+			UserID:   u.id,
+			State:    s,
+			Token:    hex.EncodeToString(token),
+			ParentID: parentID,
+			Comment:  comment,
+		}
+		n.PublicKey, n.Signature = sign(u, strconv.FormatUint(uint64(n.State), 10)+
+			n.Token+strconv.FormatUint(uint64(n.ParentID), 10)+n.Comment)
+		exec(token, comments.CmdNew, n)
+	}
+	editComment := func(token []byte, s comments.RecordStateT, u user, commentID, parentID uint32, comment string) {
+		t.Helper()
+		e := comments.Edit{
+			UserID:    u.id,
+			State:     s,
+			Token:     hex.EncodeToString(token),
+			ParentID:  parentID,
+			CommentID: commentID,
+			Comment:   comment,
+		}
+		e.PublicKey, e.Signature = sign(u, strconv.FormatUint(uint64(e.State), 10)+
+			e.Token+strconv.FormatUint(uint64(e.ParentID), 10)+e.Comment)
+		exec(token, comments.CmdEdit, e)
+	}
+	delComment := func(token []byte, s comments.RecordStateT, u user, commentID uint32, reason string) {
+		t.Helper()
+		d := comments.Del{
+			State:     s,
+			Token:     hex.EncodeToString(token),
+			CommentID: commentID,
+			Reason:    reason,
+		}
+		d.PublicKey, d.Signature = sign(u, strconv.FormatUint(uint64(d.State), 10)+
+			d.Token+strconv.FormatUint(uint64(d.CommentID), 10)+d.Reason)
+		exec(token, comments.CmdDel, d)
+	}
+	vote := func(token []byte, s comments.RecordStateT, u user, commentID uint32, v comments.VoteT) {
+		t.Helper()
+		cv := comments.Vote{
+			UserID:    u.id,
+			State:     s,
+			Token:     hex.EncodeToString(token),
+			CommentID: commentID,
+			Vote:      v,
+		}
+		cv.PublicKey, cv.Signature = sign(u, strconv.FormatUint(uint64(cv.State), 10)+
+			cv.Token+strconv.FormatUint(uint64(cv.CommentID), 10)+
+			strconv.FormatInt(int64(cv.Vote), 10))
+		exec(token, comments.CmdVote, cv)
+	}
+
+	// Setup the comments using the plugin commands
+	var (
+		sv = comments.RecordStateVetted
+		su = comments.RecordStateUnvetted
+	)
+	newComment(vetted, sv, user1, 0, "comment 1")
+	newComment(vetted, sv, user2, 1, "reply to comment 1")
+	newComment(vetted, sv, user1, 0, "comment 3")
+	editComment(vetted, sv, user1, 1, 0, "comment 1 edited")
+	vote(vetted, sv, user2, 1, comments.VoteUpvote)
+	vote(vetted, sv, user2, 1, comments.VoteDownvote)
+	vote(vetted, sv, user1, 2, comments.VoteUpvote)
+	vote(vetted, sv, user2, 3, comments.VoteUpvote)
+	delComment(vetted, sv, user1, 3, "spam")
+	newComment(unvetted, su, user1, 0, "unvetted comment")
+
+	// Save the record indexes and the comments that were built by the
+	// plugin commands.
+	type cache struct {
+		state backend.StateT
+		ridx  recordIndex
+		all   string
+	}
+	caches := func() map[string]cache {
+		t.Helper()
+		c := make(map[string]cache, len(tokens))
+		for _, token := range tokens {
+			state, err := tstore.RecordState(token)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ridx, err := p.recordIndex(token, state)
+			if err != nil {
+				t.Fatal(err)
+			}
+			all, err := p.cmdGetAll(token)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c[hex.EncodeToString(token)] = cache{
+				state: state,
+				ridx:  recordIndexNormalize(*ridx),
+				all:   all,
+			}
+		}
+		return c
+	}
+	want := caches()
+	if n := len(want[hex.EncodeToString(vetted)].ridx.Comments); n != 3 {
+		t.Fatalf("got %v vetted comments, want 3", n)
+	}
+
+	// Delete the cache and add a stale record index for a record that
+	// has comments in a different state.
+	err := p.recordIndexesDel()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p._recordIndexSave(unvetted, backend.StateVetted, recordIndex{
+		Comments: map[uint32]commentIndex{1: {}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rebuild the cache and verify that it matches the cache that was
+	// built by the plugin commands.
+	var progress int
+	err = p.CacheRebuild(tokens, func() { progress++ })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if progress != len(tokens) {
+		t.Fatalf("got progress %v, want %v", progress, len(tokens))
+	}
+	got := caches()
+	for token, w := range want {
+		g := got[token]
+		if !reflect.DeepEqual(g.ridx, w.ridx) {
+			t.Fatalf("%v: got record index %+v, want %+v", token, g.ridx, w.ridx)
+		}
+		if g.all != w.all {
+			t.Fatalf("%v: got comments %v, want %v", token, g.all, w.all)
+		}
+	}
+	for _, s := range []backend.StateT{backend.StateUnvetted, backend.StateVetted} {
+		for _, token := range tokens {
+			fp, err := p.recordIndexPath(token, s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = os.Stat(fp)
+			exists := err == nil
+			wantExists := s == want[hex.EncodeToString(token)].state &&
+				len(want[hex.EncodeToString(token)].ridx.Comments) > 0
+			if exists != wantExists {
+				t.Fatalf("%x %v: got record index exists %v, want %v",
+					token, s, exists, wantExists)
+			}
+		}
+	}
+}
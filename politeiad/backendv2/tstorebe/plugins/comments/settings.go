@@ -0,0 +1,69 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package comments
+
+import (
+	"strconv"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/comments"
+)
+
+// pluginSettings contains the comments plugin settings.
+type pluginSettings struct {
+	commentLengthMax uint32
+	voteChangesMax   uint32
+}
+
+// settingsGet returns the plugin settings. The settings can be updated at
+// runtime so they must be read using this function.
+func (p *commentsPlugin) settingsGet() pluginSettings {
+	p.mtxSettings.RLock()
+	defer p.mtxSettings.RUnlock()
+
+	return p.settings
+}
+
+// settingsParse applies the provided plugin settings to the existing settings
+// and returns the result. A backend PluginSettingError is returned if any of
+// the settings are invalid.
+func settingsParse(s pluginSettings, ss []backend.PluginSetting) (pluginSettings, error) {
+	for _, v := range ss {
+		switch v.Key {
+		case comments.SettingKeyCommentLengthMax:
+			u, err := strconv.ParseUint(v.Value, 10, 32)
+			if err != nil {
+				return s, settingError(v, err.Error())
+			}
+			if u == 0 {
+				return s, settingError(v, "must be greater than zero")
+			}
+			s.commentLengthMax = uint32(u)
+		case comments.SettingKeyVoteChangesMax:
+			u, err := strconv.ParseUint(v.Value, 10, 32)
+			if err != nil {
+				return s, settingError(v, err.Error())
+			}
+			if u == 0 {
+				return s, settingError(v, "must be greater than zero")
+			}
+			s.voteChangesMax = uint32(u)
+		default:
+			return s, settingError(v, "unknown setting")
+		}
+	}
+
+	return s, nil
+}
+
+// settingError returns a backend PluginSettingError for the provided setting.
+func settingError(s backend.PluginSetting, reason string) error {
+	return backend.PluginSettingError{
+		PluginID: comments.PluginID,
+		Key:      s.Key,
+		Value:    s.Value,
+		Reason:   reason,
+	}
+}
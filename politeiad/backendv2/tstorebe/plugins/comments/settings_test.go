@@ -0,0 +1,122 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package comments
+
+import (
+	"errors"
+	"testing"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/comments"
+)
+
+func TestSettingsUpdate(t *testing.T) {
+	s := pluginSettings{
+		commentLengthMax: comments.SettingCommentLengthMax,
+		voteChangesMax:   comments.SettingVoteChangesMax,
+	}
+	setting := func(key, value string) backend.PluginSetting {
+		return backend.PluginSetting{
+			Key:   key,
+			Value: value,
+		}
+	}
+
+	// Setup the rejected settings. The key of the setting that the
+	// error is expected to reference is included with each test.
+	var tests = []struct {
+		name     string
+		settings []backend.PluginSetting
+		wantKey  string
+	}{
+		{
+			"unknown setting",
+			[]backend.PluginSetting{
+				setting("unknown", "1"),
+			},
+			"unknown",
+		},
+		{
+			"comment length max not a number",
+			[]backend.PluginSetting{
+				setting(comments.SettingKeyCommentLengthMax, "8k"),
+			},
+			comments.SettingKeyCommentLengthMax,
+		},
+		{
+			"comment length max zero",
+			[]backend.PluginSetting{
+				setting(comments.SettingKeyCommentLengthMax, "0"),
+			},
+			comments.SettingKeyCommentLengthMax,
+		},
+		{
+			"comment length max negative",
+			[]backend.PluginSetting{
+				setting(comments.SettingKeyCommentLengthMax, "-1"),
+			},
+			comments.SettingKeyCommentLengthMax,
+		},
+		{
+			"vote changes max overflows uint32",
+			[]backend.PluginSetting{
+				setting(comments.SettingKeyVoteChangesMax, "4294967296"),
+			},
+			comments.SettingKeyVoteChangesMax,
+		},
+		{
+			"vote changes max empty",
+			[]backend.PluginSetting{
+				setting(comments.SettingKeyVoteChangesMax, ""),
+			},
+			comments.SettingKeyVoteChangesMax,
+		},
+		{
+			"valid setting followed by an invalid setting",
+			[]backend.PluginSetting{
+				setting(comments.SettingKeyCommentLengthMax, "100"),
+				setting(comments.SettingKeyVoteChangesMax, "0"),
+			},
+			comments.SettingKeyVoteChangesMax,
+		},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			p := commentsPlugin{
+				settings: s,
+			}
+			err := p.SettingsUpdate(v.settings)
+			var e backend.PluginSettingError
+			if !errors.As(err, &e) {
+				t.Fatalf("got error %v, want PluginSettingError", err)
+			}
+			if e.PluginID != comments.PluginID || e.Key != v.wantKey {
+				t.Fatalf("got plugin %v key %v, want %v %v",
+					e.PluginID, e.Key, comments.PluginID, v.wantKey)
+			}
+
+			// None of the settings are updated
+			if p.settingsGet() != s {
+				t.Fatalf("got settings %+v, want %+v", p.settingsGet(), s)
+			}
+		})
+	}
+
+	// Verify that a valid update is applied
+	p := commentsPlugin{
+		settings: s,
+	}
+	err := p.SettingsUpdate([]backend.PluginSetting{
+		setting(comments.SettingKeyCommentLengthMax, "100"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := s
+	want.commentLengthMax = 100
+	if p.settingsGet() != want {
+		t.Fatalf("got settings %+v, want %+v", p.settingsGet(), want)
+	}
+}
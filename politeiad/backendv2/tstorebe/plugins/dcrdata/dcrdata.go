@@ -229,6 +229,16 @@ func (p *dcrdataPlugin) Fsck() error {
 	return nil
 }
 
+// CacheRebuild rebuilds the plugin caches. The dcrdata plugin does not keep any
+// caches that are derived from tstore data.
+//
+// This function satisfies the plugins PluginClient interface.
+func (p *dcrdataPlugin) CacheRebuild(tokens [][]byte, progress func()) error {
+	log.Tracef("dcrdata CacheRebuild")
+
+	return nil
+}
+
 // Settings returns the plugin's settings.
 //
 // This function satisfies the plugins PluginClient interface.
@@ -238,6 +248,30 @@ func (p *dcrdataPlugin) Settings() []backend.PluginSetting {
 	return nil
 }
 
+// SettingsUpdate updates the plugin settings at runtime. The dcrdata hosts
+// cannot be updated at runtime since the websocket connection would need to
+// be re-established.
+//
+// This function satisfies the plugins PluginClient interface.
+func (p *dcrdataPlugin) SettingsUpdate(settings []backend.PluginSetting) error {
+	log.Tracef("dcrdata SettingsUpdate: %v", settings)
+
+	if len(settings) == 0 {
+		return nil
+	}
+	reason := "unknown setting"
+	switch settings[0].Key {
+	case dcrdata.SettingKeyHostHTTP, dcrdata.SettingKeyHostWS:
+		reason = "setting cannot be updated at runtime"
+	}
+	return backend.PluginSettingError{
+		PluginID: dcrdata.PluginID,
+		Key:      settings[0].Key,
+		Value:    settings[0].Value,
+		Reason:   reason,
+	}
+}
+
 func New(settings []backend.PluginSetting, activeNetParams *chaincfg.Params) (*dcrdataPlugin, error) {
 	// Plugin setting
 	var (
@@ -0,0 +1,620 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package external
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"time"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
+)
+
+const (
+	// tstoreSocketName is the filename of the unix socket that serves
+	// the Tstore methods to the plugin process. The socket is created
+	// in a temporary directory that is only accessible by the
+	// politeiad user.
+	tstoreSocketName = "tstore.sock"
+
+	// callTimeout is the maximum amount of time that politeiad waits
+	// for a plugin command or hook to complete. Setup and fsck are not
+	// subject to a timeout since they may need to process all records.
+	callTimeout = time.Minute
+
+	// restartDelayMin and restartDelayMax are the minimum and maximum
+	// delays between plugin process restarts. The delay is doubled
+	// each time the process exits shortly after being started.
+	restartDelayMin = time.Second
+	restartDelayMax = time.Minute
+
+	// stopTimeout is the amount of time that a plugin process is given
+	// to exit once its connection has been closed before the process
+	// is killed.
+	stopTimeout = 5 * time.Second
+)
+
+var (
+	_ plugins.PluginClient = (*externalPlugin)(nil)
+
+	// errNotRunning is returned when a plugin method is called while
+	// the plugin process is not running.
+	errNotRunning = errors.New("plugin process is not running")
+)
+
+// process contains a running plugin process.
+type process struct {
+	cmd    *exec.Cmd
+	client *rpc.Client
+	exited chan struct{} // Closed when the process exits
+}
+
+// externalPlugin is the politeiad side of an external plugin. It launches the
+// plugin executable, supervises it, and forwards the plugins.PluginClient
+// calls to it. The plugin process is restarted if it exits unexpectedly.
+//
+// externalPlugin satisfies the plugins PluginClient interface.
+type externalPlugin struct {
+	sync.Mutex
+	id      string
+	path    string
+	dataDir string
+
+	// socketDir is the temporary directory that contains the tstore
+	// unix socket.
+	socketDir string
+	listener  net.Listener
+
+	// The following fields are protected by the mutex. The settings
+	// are the settings that the plugin process is initialized with.
+	// They include any settings that were updated at runtime so that
+	// the updates persist across plugin restarts.
+	proc     *process
+	settings []backend.PluginSetting
+	setup    bool                    // Setup has completed
+	reported []backend.PluginSetting // Settings reported by the plugin
+	shutdown bool
+}
+
+// Setup performs any plugin setup that is required.
+//
+// This function satisfies the plugins PluginClient interface.
+func (p *externalPlugin) Setup() error {
+	log.Tracef("%v Setup", p.id)
+
+	proc, err := p.process()
+	if err != nil {
+		return err
+	}
+	err = p.setupProcess(proc)
+	if err != nil {
+		return err
+	}
+
+	p.Lock()
+	p.setup = true
+	p.Unlock()
+
+	return nil
+}
+
+// Cmd executes a plugin command.
+//
+// This function satisfies the plugins PluginClient interface.
+func (p *externalPlugin) Cmd(token []byte, cmd, payload string) (string, error) {
+	log.Tracef("%v Cmd: %x %v %v", p.id, token, cmd, payload)
+
+	var reply CmdReply
+	err := p.call("Cmd", CmdArgs{
+		Token:   token,
+		Cmd:     cmd,
+		Payload: payload,
+	}, &reply, callTimeout)
+	if err != nil {
+		return "", err
+	}
+	if reply.Error != nil {
+		return "", decodeError(reply.Error)
+	}
+
+	return reply.Payload, nil
+}
+
+// Hook executes a plugin hook.
+//
+// This function satisfies the plugins PluginClient interface.
+func (p *externalPlugin) Hook(h plugins.HookT, payload string) error {
+	log.Tracef("%v Hook: %v", p.id, plugins.Hooks[h])
+
+	var reply ErrorReply
+	err := p.call("Hook", HookArgs{
+		Hook:    h,
+		Payload: payload,
+	}, &reply, callTimeout)
+	if err != nil {
+		return err
+	}
+
+	return decodeError(reply.Error)
+}
+
+// Fsck performs a plugin filesystem check.
+//
+// This function satisfies the plugins PluginClient interface.
+func (p *externalPlugin) Fsck() error {
+	log.Tracef("%v Fsck", p.id)
+
+	var reply ErrorReply
+	err := p.call("Fsck", FsckArgs{}, &reply, 0)
+	if err != nil {
+		return err
+	}
+
+	return decodeError(reply.Error)
+}
+
+// CacheRebuild rebuilds the plugin caches. Progress is not reported by the
+// plugin process while the rebuild is being performed. The progress function
+// is called for every record once the plugin process has finished.
+//
+// This function satisfies the plugins PluginClient interface.
+func (p *externalPlugin) CacheRebuild(tokens [][]byte, progress func()) error {
+	log.Tracef("%v CacheRebuild: %v records", p.id, len(tokens))
+
+	var reply ErrorReply
+	err := p.call("CacheRebuild", CacheRebuildArgs{
+		Tokens: tokens,
+	}, &reply, 0)
+	if err != nil {
+		return err
+	}
+	err = decodeError(reply.Error)
+	if err != nil {
+		return err
+	}
+	for range tokens {
+		progress()
+	}
+
+	return nil
+}
+
+// Settings returns the plugin's settings. The settings that were reported by
+// the plugin during setup are returned. The settings that were provided to
+// politeiad are returned if the plugin has not been setup yet.
+//
+// This function satisfies the plugins PluginClient interface.
+func (p *externalPlugin) Settings() []backend.PluginSetting {
+	log.Tracef("%v Settings", p.id)
+
+	p.Lock()
+	defer p.Unlock()
+
+	if p.reported != nil {
+		return p.reported
+	}
+	return p.settings
+}
+
+// SettingsUpdate updates the plugin settings at runtime. The plugin process
+// validates the new settings. The settings that are reported by the plugin are
+// refreshed once the update has been applied.
+//
+// This function satisfies the plugins PluginClient interface.
+func (p *externalPlugin) SettingsUpdate(settings []backend.PluginSetting) error {
+	log.Tracef("%v SettingsUpdate: %v", p.id, settings)
+
+	proc, err := p.process()
+	if err != nil {
+		return err
+	}
+	var reply ErrorReply
+	err = callProcess(proc, "SettingsUpdate", SettingsUpdateArgs{
+		Settings: settings,
+	}, &reply, callTimeout)
+	if err != nil {
+		return err
+	}
+	if reply.Error != nil {
+		return decodeError(reply.Error)
+	}
+
+	// Save the updated settings so that they are provided to the
+	// plugin process if it is restarted.
+	p.Lock()
+	p.settings = settingsMerge(p.settings, settings)
+	p.Unlock()
+
+	// Refresh the settings that are reported by the plugin
+	var sr SettingsReply
+	err = callProcess(proc, "Settings", SettingsArgs{}, &sr, callTimeout)
+	if err != nil {
+		return err
+	}
+	if sr.Error != nil {
+		return decodeError(sr.Error)
+	}
+
+	p.Lock()
+	p.reported = sr.Settings
+	p.Unlock()
+
+	return nil
+}
+
+// settingsMerge returns the settings with the updates applied. The value of an
+// existing setting is replaced. New settings are appended.
+func settingsMerge(settings, updates []backend.PluginSetting) []backend.PluginSetting {
+	merged := make([]backend.PluginSetting, 0, len(settings)+len(updates))
+	merged = append(merged, settings...)
+	for _, u := range updates {
+		var found bool
+		for i, v := range merged {
+			if v.Key == u.Key {
+				merged[i].Value = u.Value
+				found = true
+			}
+		}
+		if !found {
+			merged = append(merged, u)
+		}
+	}
+	return merged
+}
+
+// Close stops the plugin process and the tstore socket.
+func (p *externalPlugin) Close() {
+	log.Tracef("%v Close", p.id)
+
+	p.Lock()
+	p.shutdown = true
+	proc := p.proc
+	p.proc = nil
+	p.Unlock()
+
+	if proc != nil {
+		stop(proc)
+	}
+	p.listener.Close()
+	os.RemoveAll(p.socketDir)
+}
+
+// process returns the running plugin process.
+func (p *externalPlugin) process() (*process, error) {
+	p.Lock()
+	defer p.Unlock()
+
+	if p.proc == nil {
+		return nil, errNotRunning
+	}
+	return p.proc, nil
+}
+
+// call executes an RPC method on the plugin process. A timeout of 0 waits
+// until the call completes or the process exits.
+func (p *externalPlugin) call(method string, args, reply interface{}, timeout time.Duration) error {
+	proc, err := p.process()
+	if err != nil {
+		return err
+	}
+	return callProcess(proc, method, args, reply, timeout)
+}
+
+// callProcess executes an RPC method on the provided plugin process.
+func callProcess(proc *process, method string, args, reply interface{}, timeout time.Duration) error {
+	var expired <-chan time.Time
+	if timeout > 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		expired = t.C
+	}
+
+	c := proc.client.Go(pluginService+"."+method, args, reply, nil)
+	select {
+	case <-c.Done:
+		if c.Error == rpc.ErrShutdown {
+			return errNotRunning
+		}
+		return c.Error
+	case <-proc.exited:
+		return errNotRunning
+	case <-expired:
+		return fmt.Errorf("plugin %v timed out after %v", method, timeout)
+	}
+}
+
+// setupProcess runs the plugin setup on a plugin process and saves the
+// settings that are reported by the plugin.
+func (p *externalPlugin) setupProcess(proc *process) error {
+	var reply ErrorReply
+	err := callProcess(proc, "Setup", SetupArgs{}, &reply, 0)
+	if err != nil {
+		return err
+	}
+	if reply.Error != nil {
+		return decodeError(reply.Error)
+	}
+
+	var sr SettingsReply
+	err = callProcess(proc, "Settings", SettingsArgs{}, &sr, callTimeout)
+	if err != nil {
+		return err
+	}
+	if sr.Error != nil {
+		return decodeError(sr.Error)
+	}
+
+	p.Lock()
+	p.reported = sr.Settings
+	p.Unlock()
+
+	return nil
+}
+
+// start launches the plugin executable and initializes the plugin.
+func (p *externalPlugin) start() (*process, error) {
+	log.Debugf("Starting plugin %v: %v", p.id, p.path)
+
+	// Setup the stdio pipes. The pipes are created manually instead
+	// of using the exec.Cmd pipe methods so that waiting on the
+	// process does not close the ends that are used by politeiad.
+	stdinR, stdinW, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	stdoutR, stdoutW, err := os.Pipe()
+	if err != nil {
+		stdinR.Close()
+		stdinW.Close()
+		return nil, err
+	}
+	stderrR, stderrW, err := os.Pipe()
+	if err != nil {
+		stdinR.Close()
+		stdinW.Close()
+		stdoutR.Close()
+		stdoutW.Close()
+		return nil, err
+	}
+
+	cmd := exec.Command(p.path)
+	cmd.Dir = p.dataDir
+	cmd.Stdin = stdinR
+	cmd.Stdout = stdoutW
+	cmd.Stderr = stderrW
+	err = cmd.Start()
+
+	// The child ends of the pipes are no longer needed by politeiad
+	// regardless of whether the process was started.
+	stdinR.Close()
+	stdoutW.Close()
+	stderrW.Close()
+	if err != nil {
+		stdinW.Close()
+		stdoutR.Close()
+		stderrR.Close()
+		return nil, err
+	}
+
+	// Log the plugin stderr output
+	go func() {
+		s := bufio.NewScanner(stderrR)
+		for s.Scan() {
+			log.Infof("%v: %s", p.id, s.Bytes())
+		}
+		stderrR.Close()
+	}()
+
+	proc := &process{
+		cmd:    cmd,
+		client: rpc.NewClientWithCodec(jsonrpc.NewClientCodec(stdioConn{stdoutR, stdinW})),
+		exited: make(chan struct{}),
+	}
+	go func() {
+		err := cmd.Wait()
+		if err != nil {
+			log.Debugf("Plugin %v exited: %v", p.id, err)
+		}
+		close(proc.exited)
+		proc.client.Close()
+	}()
+
+	// Initialize the plugin
+	p.Lock()
+	settings := p.settings
+	p.Unlock()
+
+	var reply ErrorReply
+	err = callProcess(proc, "Init", InitArgs{
+		PluginID:   p.id,
+		Settings:   settings,
+		DataDir:    p.dataDir,
+		TstoreAddr: filepath.Join(p.socketDir, tstoreSocketName),
+	}, &reply, callTimeout)
+	if err == nil {
+		err = decodeError(reply.Error)
+	}
+	if err != nil {
+		stop(proc)
+		return nil, fmt.Errorf("init: %v", err)
+	}
+
+	return proc, nil
+}
+
+// stop closes the connection to a plugin process and waits for it to exit.
+// The process is killed if it does not exit within the stop timeout.
+func stop(proc *process) {
+	proc.client.Close()
+	select {
+	case <-proc.exited:
+	case <-time.After(stopTimeout):
+		proc.cmd.Process.Kill()
+		<-proc.exited
+	}
+}
+
+// supervise restarts the plugin process whenever it exits. It returns once
+// the plugin has been closed.
+func (p *externalPlugin) supervise(proc *process) {
+	delay := restartDelayMin
+	for {
+		started := time.Now()
+		<-proc.exited
+
+		p.Lock()
+		shutdown := p.shutdown
+		p.proc = nil
+		p.Unlock()
+		if shutdown {
+			return
+		}
+
+		log.Errorf("Plugin %v exited unexpectedly; restarting in %v",
+			p.id, delay)
+
+		// Reset the delay if the process ran for a while. Otherwise
+		// back off so that a plugin that fails on startup does not
+		// spin.
+		if time.Since(started) > restartDelayMax {
+			delay = restartDelayMin
+		}
+		for {
+			time.Sleep(delay)
+			if delay < restartDelayMax {
+				delay *= 2
+			}
+
+			p.Lock()
+			shutdown = p.shutdown
+			isSetup := p.setup
+			p.Unlock()
+			if shutdown {
+				return
+			}
+
+			var err error
+			proc, err = p.start()
+			if err == nil && isSetup {
+				// The plugin setup must be run again since the
+				// plugin may keep state in memory.
+				err = p.setupProcess(proc)
+				if err != nil {
+					stop(proc)
+				}
+			}
+			if err != nil {
+				log.Errorf("Plugin %v restart failed: %v", p.id, err)
+				continue
+			}
+			break
+		}
+
+		p.Lock()
+		if p.shutdown {
+			p.Unlock()
+			stop(proc)
+			return
+		}
+		p.proc = proc
+		p.Unlock()
+
+		log.Infof("Plugin %v restarted", p.id)
+	}
+}
+
+// serveTstore serves the Tstore methods on the tstore socket.
+func (p *externalPlugin) serveTstore(s *rpc.Server) {
+	for {
+		conn, err := p.listener.Accept()
+		if err != nil {
+			p.Lock()
+			shutdown := p.shutdown
+			p.Unlock()
+			if !shutdown {
+				log.Errorf("Plugin %v tstore socket: %v", p.id, err)
+			}
+			return
+		}
+		go s.ServeCodec(jsonrpc.NewServerCodec(conn))
+	}
+}
+
+// New launches an external plugin executable and returns the plugin client
+// that is used by tstore to interact with it.
+func New(tstore plugins.TstoreClient, id, path string, settings []backend.PluginSetting, dataDir string) (*externalPlugin, error) {
+	// Verify the plugin executable exists
+	path, err := exec.LookPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create plugin data directory
+	dataDir = filepath.Join(dataDir, id)
+	err = os.MkdirAll(dataDir, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	// Setup the tstore socket. The socket is created in a temporary
+	// directory since the path of a unix socket is limited in length.
+	socketDir, err := ioutil.TempDir("", "politeiad-"+id+"-")
+	if err != nil {
+		return nil, err
+	}
+	err = os.Chmod(socketDir, 0700)
+	if err != nil {
+		os.RemoveAll(socketDir)
+		return nil, err
+	}
+	l, err := net.Listen("unix", filepath.Join(socketDir, tstoreSocketName))
+	if err != nil {
+		os.RemoveAll(socketDir)
+		return nil, err
+	}
+	s := rpc.NewServer()
+	err = s.RegisterName(tstoreService, &tstoreServer{
+		pluginID: id,
+		tstore:   tstore,
+	})
+	if err != nil {
+		l.Close()
+		os.RemoveAll(socketDir)
+		return nil, err
+	}
+
+	p := &externalPlugin{
+		id:        id,
+		path:      path,
+		settings:  settings,
+		dataDir:   dataDir,
+		socketDir: socketDir,
+		listener:  l,
+	}
+	go p.serveTstore(s)
+
+	// Launch the plugin process
+	proc, err := p.start()
+	if err != nil {
+		p.Close()
+		return nil, fmt.Errorf("start plugin %v: %v", id, err)
+	}
+	p.proc = proc
+	go p.supervise(proc)
+
+	log.Infof("Plugin %v started: %v", id, path)
+
+	return p, nil
+}
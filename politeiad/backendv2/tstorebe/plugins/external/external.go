@@ -0,0 +1,313 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package external provides support for tstore plugins that run in a separate
+// process from politeiad.
+//
+// An external plugin is an executable that is launched and supervised by
+// politeiad. politeiad communicates with the plugin using JSON-RPC. The plugin
+// serves the plugins.PluginClient methods over its stdin and stdout. politeiad
+// serves the plugins.TstoreClient methods over a unix socket so that the
+// plugin is able to save and retrieve blobs. Plugin log output must be written
+// to stderr since stdout is used by the protocol. politeiad logs each line
+// that is written to stderr.
+//
+// Plugin authors only need to implement the plugins.PluginClient interface and
+// call Serve from the main function of the plugin executable with a NewFunc
+// that returns the plugin. The plugin is provided with a plugins.TstoreClient
+// that forwards its calls to politeiad.
+//
+//	func main() {
+//		err := external.Serve(newMyPlugin)
+//		if err != nil {
+//			fmt.Fprintln(os.Stderr, err)
+//			os.Exit(1)
+//		}
+//	}
+//
+// External plugins are registered using the politeiad plugin config option,
+// formatted as pluginID,path. The plugin settings are provided using the
+// pluginsetting config option, the same as compiled in plugins.
+package external
+
+import (
+	"errors"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+)
+
+const (
+	// pluginService is the name of the RPC service that is served by
+	// the plugin process. It provides the plugins.PluginClient methods.
+	pluginService = "Plugin"
+
+	// tstoreService is the name of the RPC service that is served by
+	// politeiad. It provides the plugins.TstoreClient methods.
+	tstoreService = "Tstore"
+)
+
+// Error is an error that is returned over the plugin protocol. The errors
+// that politeiad handles in a specific way, such as plugin user errors and
+// the backend errors, are described by their fields so that they can be
+// re-created on the other side of the connection.
+type Error struct {
+	Message string `json:"message"`
+
+	// Sentinel contains the name of a sentinel error. See the
+	// sentinelErrors map.
+	Sentinel string `json:"sentinel,omitempty"`
+
+	// Plugin user error fields. These are only populated for
+	// backend.PluginError errors.
+	PluginID     string `json:"pluginid,omitempty"`
+	ErrorCode    uint32 `json:"errorcode,omitempty"`
+	ErrorContext string `json:"errorcontext,omitempty"`
+
+	// Plugin setting error fields. These are only populated for
+	// backend.PluginSettingError errors. The plugin ID is returned
+	// in the PluginID field.
+	SettingKey    string `json:"settingkey,omitempty"`
+	SettingValue  string `json:"settingvalue,omitempty"`
+	SettingReason string `json:"settingreason,omitempty"`
+}
+
+var (
+	// sentinelErrors contains the sentinel errors that are preserved
+	// when an error is sent over the plugin protocol.
+	sentinelErrors = map[string]error{
+		"shutdown":         backend.ErrShutdown,
+		"tokeninvalid":     backend.ErrTokenInvalid,
+		"recordnotfound":   backend.ErrRecordNotFound,
+		"recordlocked":     backend.ErrRecordLocked,
+		"norecordchanges":  backend.ErrNoRecordChanges,
+		"pluginidinvalid":  backend.ErrPluginIDInvalid,
+		"plugincmdinvalid": backend.ErrPluginCmdInvalid,
+		"duplicateblob":    plugins.ErrDuplicateBlob,
+	}
+)
+
+// encodeError converts an error into an Error that can be sent over the
+// plugin protocol. A nil error returns a nil Error.
+func encodeError(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	var (
+		pe  backend.PluginError
+		pse backend.PluginSettingError
+	)
+	if errors.As(err, &pe) {
+		return &Error{
+			Message:      err.Error(),
+			PluginID:     pe.PluginID,
+			ErrorCode:    pe.ErrorCode,
+			ErrorContext: pe.ErrorContext,
+		}
+	}
+	if errors.As(err, &pse) {
+		return &Error{
+			Message:       err.Error(),
+			PluginID:      pse.PluginID,
+			SettingKey:    pse.Key,
+			SettingValue:  pse.Value,
+			SettingReason: pse.Reason,
+		}
+	}
+	for k, v := range sentinelErrors {
+		if errors.Is(err, v) {
+			return &Error{
+				Message:  err.Error(),
+				Sentinel: k,
+			}
+		}
+	}
+	return &Error{
+		Message: err.Error(),
+	}
+}
+
+// decodeError converts an Error that was received over the plugin protocol
+// back into an error. A nil Error returns a nil error.
+func decodeError(e *Error) error {
+	if e == nil {
+		return nil
+	}
+	if e.SettingKey != "" {
+		return backend.PluginSettingError{
+			PluginID: e.PluginID,
+			Key:      e.SettingKey,
+			Value:    e.SettingValue,
+			Reason:   e.SettingReason,
+		}
+	}
+	if e.PluginID != "" {
+		return backend.PluginError{
+			PluginID:     e.PluginID,
+			ErrorCode:    e.ErrorCode,
+			ErrorContext: e.ErrorContext,
+		}
+	}
+	if err, ok := sentinelErrors[e.Sentinel]; ok {
+		return err
+	}
+	return errors.New(e.Message)
+}
+
+// ErrorReply is the reply to the RPC methods that only return an error.
+type ErrorReply struct {
+	Error *Error `json:"error,omitempty"`
+}
+
+// InitArgs contains the arguments of the Plugin.Init method. Init is the first
+// method that politeiad calls after the plugin process is started. TstoreAddr
+// is the address of the unix socket that serves the Tstore methods.
+type InitArgs struct {
+	PluginID   string                  `json:"pluginid"`
+	Settings   []backend.PluginSetting `json:"settings"`
+	DataDir    string                  `json:"datadir"`
+	TstoreAddr string                  `json:"tstoreaddr"`
+}
+
+// SetupArgs contains the arguments of the Plugin.Setup method.
+type SetupArgs struct{}
+
+// CmdArgs contains the arguments of the Plugin.Cmd method.
+type CmdArgs struct {
+	Token   []byte `json:"token"`
+	Cmd     string `json:"cmd"`
+	Payload string `json:"payload"`
+}
+
+// CmdReply is the reply to the Plugin.Cmd method.
+type CmdReply struct {
+	Payload string `json:"payload"`
+	Error   *Error `json:"error,omitempty"`
+}
+
+// HookArgs contains the arguments of the Plugin.Hook method.
+type HookArgs struct {
+	Hook    plugins.HookT `json:"hook"`
+	Payload string        `json:"payload"`
+}
+
+// FsckArgs contains the arguments of the Plugin.Fsck method.
+type FsckArgs struct{}
+
+// CacheRebuildArgs contains the arguments of the Plugin.CacheRebuild method.
+type CacheRebuildArgs struct {
+	Tokens [][]byte `json:"tokens"`
+}
+
+// SettingsArgs contains the arguments of the Plugin.Settings method.
+type SettingsArgs struct{}
+
+// SettingsReply is the reply to the Plugin.Settings method.
+type SettingsReply struct {
+	Settings []backend.PluginSetting `json:"settings"`
+	Error    *Error                  `json:"error,omitempty"`
+}
+
+// SettingsUpdateArgs contains the arguments of the Plugin.SettingsUpdate
+// method.
+type SettingsUpdateArgs struct {
+	Settings []backend.PluginSetting `json:"settings"`
+}
+
+// BlobSaveArgs contains the arguments of the Tstore.BlobSave method.
+type BlobSaveArgs struct {
+	Token     []byte          `json:"token"`
+	BlobEntry store.BlobEntry `json:"blobentry"`
+}
+
+// DigestsArgs contains the arguments of the Tstore.BlobsDel and Tstore.Blobs
+// methods.
+type DigestsArgs struct {
+	Token   []byte   `json:"token"`
+	Digests [][]byte `json:"digests"`
+}
+
+// BlobsReply is the reply to the Tstore.Blobs method.
+type BlobsReply struct {
+	Blobs map[string]store.BlobEntry `json:"blobs"` // [digest]BlobEntry
+	Error *Error                     `json:"error,omitempty"`
+}
+
+// DataDescArgs contains the arguments of the Tstore.BlobsByDataDesc and
+// Tstore.DigestsByDataDesc methods.
+type DataDescArgs struct {
+	Token    []byte   `json:"token"`
+	DataDesc []string `json:"datadesc"`
+}
+
+// BlobsByDataDescReply is the reply to the Tstore.BlobsByDataDesc method.
+type BlobsByDataDescReply struct {
+	Blobs []store.BlobEntry `json:"blobs"`
+	Error *Error            `json:"error,omitempty"`
+}
+
+// DigestsReply is the reply to the Tstore.DigestsByDataDesc method.
+type DigestsReply struct {
+	Digests [][]byte `json:"digests"`
+	Error   *Error   `json:"error,omitempty"`
+}
+
+// TimestampArgs contains the arguments of the Tstore.Timestamp method.
+type TimestampArgs struct {
+	Token  []byte `json:"token"`
+	Digest []byte `json:"digest"`
+}
+
+// TimestampReply is the reply to the Tstore.Timestamp method.
+type TimestampReply struct {
+	Timestamp *backend.Timestamp `json:"timestamp"`
+	Error     *Error             `json:"error,omitempty"`
+}
+
+// RecordPartialArgs contains the arguments of the Tstore.RecordPartial
+// method. The Record and RecordLatest methods of the remote TstoreClient are
+// also implemented using this method.
+type RecordPartialArgs struct {
+	Token        []byte   `json:"token"`
+	Version      uint32   `json:"version"`
+	Filenames    []string `json:"filenames"`
+	OmitAllFiles bool     `json:"omitallfiles"`
+}
+
+// RecordReply is the reply to the Tstore.RecordPartial method.
+type RecordReply struct {
+	Record *backend.Record `json:"record"`
+	Error  *Error          `json:"error,omitempty"`
+}
+
+// RecordStateArgs contains the arguments of the Tstore.RecordState method.
+type RecordStateArgs struct {
+	Token []byte `json:"token"`
+}
+
+// RecordStateReply is the reply to the Tstore.RecordState method.
+type RecordStateReply struct {
+	State backend.StateT `json:"state"`
+	Error *Error         `json:"error,omitempty"`
+}
+
+// InventoryAttributesSetArgs contains the arguments of the
+// Tstore.InventoryAttributesSet method. External plugins are only allowed to
+// set the attributes of their own plugin ID.
+type InventoryAttributesSetArgs struct {
+	Token      []byte            `json:"token"`
+	PluginID   string            `json:"pluginid"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+// PluginEventPublishArgs contains the arguments of the
+// Tstore.PluginEventPublish method. External plugins are only allowed to
+// publish events for their own plugin ID.
+type PluginEventPublishArgs struct {
+	Token    []byte `json:"token"`
+	PluginID string `json:"pluginid"`
+	Name     string `json:"name"`
+	Payload  string `json:"payload"`
+}
@@ -0,0 +1,215 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package external
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"path/filepath"
+	"testing"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
+)
+
+func TestErrorEncoding(t *testing.T) {
+	pe := backend.PluginError{
+		PluginID:     "test",
+		ErrorCode:    3,
+		ErrorContext: "context",
+	}
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"nil", nil, nil},
+		{"plugin error", pe, pe},
+		{"wrapped plugin error", fmt.Errorf("wrap: %w", pe), pe},
+		{"sentinel", backend.ErrRecordNotFound, backend.ErrRecordNotFound},
+		{"wrapped sentinel", fmt.Errorf("wrap: %w", plugins.ErrDuplicateBlob),
+			plugins.ErrDuplicateBlob},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := decodeError(encodeError(tc.err))
+			if got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	// Errors that are not recognized are returned using their message
+	err := decodeError(encodeError(errors.New("some error")))
+	if err == nil || err.Error() != "some error" {
+		t.Errorf("got %v, want some error", err)
+	}
+}
+
+// testTstore is a plugins.TstoreClient that only implements RecordState.
+type testTstore struct {
+	plugins.TstoreClient
+}
+
+func (testTstore) RecordState(token []byte) (backend.StateT, error) {
+	if len(token) == 0 {
+		return backend.StateInvalid, backend.ErrTokenInvalid
+	}
+	return backend.StateVetted, nil
+}
+
+// testPlugin is a plugins.PluginClient that returns the state of the record
+// from the tstore client.
+type testPlugin struct {
+	plugins.PluginClient
+	tstore plugins.TstoreClient
+}
+
+func (p *testPlugin) Cmd(token []byte, cmd, payload string) (string, error) {
+	if cmd != "state" {
+		return "", backend.PluginError{
+			PluginID:  "test",
+			ErrorCode: 1,
+		}
+	}
+	s, err := p.tstore.RecordState(token)
+	if err != nil {
+		return "", err
+	}
+	return backend.States[s], nil
+}
+
+func (p *testPlugin) SettingsUpdate(settings []backend.PluginSetting) error {
+	for _, v := range settings {
+		return backend.PluginSettingError{
+			PluginID: "test",
+			Key:      v.Key,
+			Value:    v.Value,
+			Reason:   "unknown setting",
+		}
+	}
+	return nil
+}
+
+func TestProtocol(t *testing.T) {
+	// Serve the tstore methods on a unix socket
+	dir, err := ioutil.TempDir("", "external")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	addr := filepath.Join(dir, tstoreSocketName)
+	l, err := net.Listen("unix", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	s := rpc.NewServer()
+	err = s.RegisterName(tstoreService, &tstoreServer{
+		pluginID: "test",
+		tstore:   testTstore{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go s.ServeCodec(jsonrpc.NewServerCodec(conn))
+		}
+	}()
+
+	// Serve the plugin over an in memory connection
+	c1, c2 := net.Pipe()
+	go serveConn(c2, func(cfg Config, tstore plugins.TstoreClient) (plugins.PluginClient, error) {
+		return &testPlugin{tstore: tstore}, nil
+	})
+	client := rpc.NewClientWithCodec(jsonrpc.NewClientCodec(c1))
+	defer client.Close()
+
+	// Commands fail prior to initialization
+	var cr CmdReply
+	err = client.Call(pluginService+".Cmd", CmdArgs{Cmd: "state"}, &cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decodeError(cr.Error) == nil {
+		t.Fatalf("got nil error before init")
+	}
+
+	// Initialize the plugin
+	var er ErrorReply
+	err = client.Call(pluginService+".Init", InitArgs{
+		PluginID:   "test",
+		TstoreAddr: addr,
+	}, &er)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := decodeError(er.Error); err != nil {
+		t.Fatal(err)
+	}
+
+	// Execute a command that calls back into tstore
+	cr = CmdReply{}
+	err = client.Call(pluginService+".Cmd", CmdArgs{
+		Token: []byte{0x01},
+		Cmd:   "state",
+	}, &cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := decodeError(cr.Error); err != nil {
+		t.Fatal(err)
+	}
+	if cr.Payload != backend.States[backend.StateVetted] {
+		t.Errorf("got payload %v, want %v", cr.Payload,
+			backend.States[backend.StateVetted])
+	}
+
+	// Tstore errors are passed through to the plugin and back
+	cr = CmdReply{}
+	err = client.Call(pluginService+".Cmd", CmdArgs{Cmd: "state"}, &cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := decodeError(cr.Error); err != backend.ErrTokenInvalid {
+		t.Errorf("got error %v, want %v", err, backend.ErrTokenInvalid)
+	}
+
+	// Plugin user errors are preserved
+	cr = CmdReply{}
+	err = client.Call(pluginService+".Cmd", CmdArgs{Cmd: "invalid"}, &cr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pe backend.PluginError
+	if !errors.As(decodeError(cr.Error), &pe) || pe.ErrorCode != 1 {
+		t.Errorf("got error %v, want plugin error", decodeError(cr.Error))
+	}
+
+	// Plugin setting errors are preserved
+	er = ErrorReply{}
+	err = client.Call(pluginService+".SettingsUpdate", SettingsUpdateArgs{
+		Settings: []backend.PluginSetting{{Key: "key", Value: "value"}},
+	}, &er)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var pse backend.PluginSettingError
+	if !errors.As(decodeError(er.Error), &pse) || pse.Key != "key" ||
+		pse.Value != "value" || pse.PluginID != "test" {
+		t.Errorf("got error %v, want plugin setting error",
+			decodeError(er.Error))
+	}
+}
@@ -0,0 +1,242 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package external
+
+import (
+	"errors"
+	"io"
+	"net"
+	"net/rpc"
+	"net/rpc/jsonrpc"
+	"os"
+	"sync"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
+)
+
+var (
+	// errNotInitialized is returned when a plugin method is called
+	// prior to the plugin being initialized by politeiad.
+	errNotInitialized = errors.New("plugin not initialized")
+)
+
+// Config contains the plugin configuration that politeiad provides to an
+// external plugin on startup.
+type Config struct {
+	// PluginID is the plugin ID that the plugin was registered with.
+	PluginID string
+
+	// Settings contains the plugin settings that were provided to
+	// politeiad using the pluginsetting config option.
+	Settings []backend.PluginSetting
+
+	// DataDir is the plugin data directory. The plugin is allowed to
+	// store cached data here. The directory is created by politeiad.
+	DataDir string
+}
+
+// NewFunc returns a new plugin. It is called by Serve once the plugin process
+// has been initialized by politeiad. The provided TstoreClient forwards its
+// calls to politeiad.
+type NewFunc func(cfg Config, tstore plugins.TstoreClient) (plugins.PluginClient, error)
+
+// Serve serves a plugin to politeiad over stdin and stdout. It must be called
+// by the main function of the plugin executable and blocks until politeiad
+// closes the connection.
+func Serve(newPlugin NewFunc) error {
+	return serveConn(stdioConn{os.Stdin, os.Stdout}, newPlugin)
+}
+
+// serveConn serves a plugin over the provided connection.
+func serveConn(conn io.ReadWriteCloser, newPlugin NewFunc) error {
+	p := &pluginServer{
+		newPlugin: newPlugin,
+	}
+	defer p.close()
+
+	s := rpc.NewServer()
+	err := s.RegisterName(pluginService, p)
+	if err != nil {
+		return err
+	}
+	s.ServeCodec(jsonrpc.NewServerCodec(conn))
+
+	return nil
+}
+
+// stdioConn is an io.ReadWriteCloser that uses stdin and stdout.
+type stdioConn struct {
+	in  io.ReadCloser
+	out io.WriteCloser
+}
+
+// Read reads from stdin.
+func (c stdioConn) Read(p []byte) (int, error) {
+	return c.in.Read(p)
+}
+
+// Write writes to stdout.
+func (c stdioConn) Write(p []byte) (int, error) {
+	return c.out.Write(p)
+}
+
+// Close closes stdin and stdout.
+func (c stdioConn) Close() error {
+	err := c.in.Close()
+	if err2 := c.out.Close(); err == nil {
+		err = err2
+	}
+	return err
+}
+
+// pluginServer serves the plugins.PluginClient methods of an external plugin
+// to politeiad. It runs in the plugin process.
+//
+// The RPC methods always return a nil error. Errors are returned in the reply
+// so that they can be re-created by politeiad.
+type pluginServer struct {
+	newPlugin NewFunc
+
+	// The mutex is not embedded since net/rpc would attempt to
+	// register its exported methods as RPC methods.
+	mtx    sync.RWMutex
+	plugin plugins.PluginClient
+	tstore *rpc.Client
+}
+
+// client returns the plugin client.
+func (p *pluginServer) client() (plugins.PluginClient, error) {
+	p.mtx.RLock()
+	defer p.mtx.RUnlock()
+
+	if p.plugin == nil {
+		return nil, errNotInitialized
+	}
+	return p.plugin, nil
+}
+
+// close closes the tstore connection.
+func (p *pluginServer) close() {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if p.tstore != nil {
+		p.tstore.Close()
+	}
+}
+
+// Init connects to the tstore socket and creates the plugin.
+func (p *pluginServer) Init(args *InitArgs, reply *ErrorReply) error {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if p.plugin != nil {
+		reply.Error = encodeError(errors.New("plugin already initialized"))
+		return nil
+	}
+
+	conn, err := net.Dial("unix", args.TstoreAddr)
+	if err != nil {
+		reply.Error = encodeError(err)
+		return nil
+	}
+	c := rpc.NewClientWithCodec(jsonrpc.NewClientCodec(conn))
+	cfg := Config{
+		PluginID: args.PluginID,
+		Settings: args.Settings,
+		DataDir:  args.DataDir,
+	}
+	plugin, err := p.newPlugin(cfg, &tstoreClient{client: c})
+	if err != nil {
+		c.Close()
+		reply.Error = encodeError(err)
+		return nil
+	}
+
+	p.plugin = plugin
+	p.tstore = c
+
+	return nil
+}
+
+// Setup performs any required plugin setup.
+func (p *pluginServer) Setup(args *SetupArgs, reply *ErrorReply) error {
+	c, err := p.client()
+	if err != nil {
+		reply.Error = encodeError(err)
+		return nil
+	}
+	reply.Error = encodeError(c.Setup())
+	return nil
+}
+
+// Cmd executes a plugin command.
+func (p *pluginServer) Cmd(args *CmdArgs, reply *CmdReply) error {
+	c, err := p.client()
+	if err != nil {
+		reply.Error = encodeError(err)
+		return nil
+	}
+	payload, err := c.Cmd(args.Token, args.Cmd, args.Payload)
+	reply.Payload = payload
+	reply.Error = encodeError(err)
+	return nil
+}
+
+// Hook executes a plugin hook.
+func (p *pluginServer) Hook(args *HookArgs, reply *ErrorReply) error {
+	c, err := p.client()
+	if err != nil {
+		reply.Error = encodeError(err)
+		return nil
+	}
+	reply.Error = encodeError(c.Hook(args.Hook, args.Payload))
+	return nil
+}
+
+// Fsck performs a plugin file system check.
+func (p *pluginServer) Fsck(args *FsckArgs, reply *ErrorReply) error {
+	c, err := p.client()
+	if err != nil {
+		reply.Error = encodeError(err)
+		return nil
+	}
+	reply.Error = encodeError(c.Fsck())
+	return nil
+}
+
+// CacheRebuild rebuilds the plugin caches.
+func (p *pluginServer) CacheRebuild(args *CacheRebuildArgs, reply *ErrorReply) error {
+	c, err := p.client()
+	if err != nil {
+		reply.Error = encodeError(err)
+		return nil
+	}
+	reply.Error = encodeError(c.CacheRebuild(args.Tokens, func() {}))
+	return nil
+}
+
+// Settings returns the plugin settings.
+func (p *pluginServer) Settings(args *SettingsArgs, reply *SettingsReply) error {
+	c, err := p.client()
+	if err != nil {
+		reply.Error = encodeError(err)
+		return nil
+	}
+	reply.Settings = c.Settings()
+	return nil
+}
+
+// SettingsUpdate updates the plugin settings.
+func (p *pluginServer) SettingsUpdate(args *SettingsUpdateArgs, reply *ErrorReply) error {
+	c, err := p.client()
+	if err != nil {
+		reply.Error = encodeError(err)
+		return nil
+	}
+	reply.Error = encodeError(c.SettingsUpdate(args.Settings))
+	return nil
+}
@@ -0,0 +1,347 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package external
+
+import (
+	"fmt"
+	"net/rpc"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+)
+
+// tstoreServer serves the plugins.TstoreClient methods to an external plugin.
+// It runs in politeiad and forwards the calls to the tstore instance.
+//
+// The RPC methods always return a nil error. Errors are returned in the reply
+// so that they can be re-created by the plugin.
+type tstoreServer struct {
+	pluginID string
+	tstore   plugins.TstoreClient
+}
+
+// BlobSave saves a blob to tstore.
+func (s *tstoreServer) BlobSave(args *BlobSaveArgs, reply *ErrorReply) error {
+	log.Tracef("%v tstore BlobSave: %x", s.pluginID, args.Token)
+
+	reply.Error = encodeError(s.tstore.BlobSave(args.Token, args.BlobEntry))
+	return nil
+}
+
+// BlobsDel deletes blobs from tstore.
+func (s *tstoreServer) BlobsDel(args *DigestsArgs, reply *ErrorReply) error {
+	log.Tracef("%v tstore BlobsDel: %x", s.pluginID, args.Token)
+
+	reply.Error = encodeError(s.tstore.BlobsDel(args.Token, args.Digests))
+	return nil
+}
+
+// Blobs returns the blobs that correspond to the provided digests.
+func (s *tstoreServer) Blobs(args *DigestsArgs, reply *BlobsReply) error {
+	log.Tracef("%v tstore Blobs: %x", s.pluginID, args.Token)
+
+	blobs, err := s.tstore.Blobs(args.Token, args.Digests)
+	reply.Blobs = blobs
+	reply.Error = encodeError(err)
+	return nil
+}
+
+// BlobsByDataDesc returns the blobs that match the provided data descriptors.
+func (s *tstoreServer) BlobsByDataDesc(args *DataDescArgs, reply *BlobsByDataDescReply) error {
+	log.Tracef("%v tstore BlobsByDataDesc: %x %v",
+		s.pluginID, args.Token, args.DataDesc)
+
+	blobs, err := s.tstore.BlobsByDataDesc(args.Token, args.DataDesc)
+	reply.Blobs = blobs
+	reply.Error = encodeError(err)
+	return nil
+}
+
+// DigestsByDataDesc returns the digests of the blobs that match the provided
+// data descriptors.
+func (s *tstoreServer) DigestsByDataDesc(args *DataDescArgs, reply *DigestsReply) error {
+	log.Tracef("%v tstore DigestsByDataDesc: %x %v",
+		s.pluginID, args.Token, args.DataDesc)
+
+	digests, err := s.tstore.DigestsByDataDesc(args.Token, args.DataDesc)
+	reply.Digests = digests
+	reply.Error = encodeError(err)
+	return nil
+}
+
+// Timestamp returns the timestamp of a blob.
+func (s *tstoreServer) Timestamp(args *TimestampArgs, reply *TimestampReply) error {
+	log.Tracef("%v tstore Timestamp: %x %x", s.pluginID, args.Token, args.Digest)
+
+	ts, err := s.tstore.Timestamp(args.Token, args.Digest)
+	reply.Timestamp = ts
+	reply.Error = encodeError(err)
+	return nil
+}
+
+// RecordPartial returns a partial record.
+func (s *tstoreServer) RecordPartial(args *RecordPartialArgs, reply *RecordReply) error {
+	log.Tracef("%v tstore RecordPartial: %x %v",
+		s.pluginID, args.Token, args.Version)
+
+	r, err := s.tstore.RecordPartial(args.Token, args.Version,
+		args.Filenames, args.OmitAllFiles)
+	reply.Record = r
+	reply.Error = encodeError(err)
+	return nil
+}
+
+// RecordState returns the state of a record.
+func (s *tstoreServer) RecordState(args *RecordStateArgs, reply *RecordStateReply) error {
+	log.Tracef("%v tstore RecordState: %x", s.pluginID, args.Token)
+
+	state, err := s.tstore.RecordState(args.Token)
+	reply.State = state
+	reply.Error = encodeError(err)
+	return nil
+}
+
+// InventoryAttributesSet sets the inventory attributes of a record. A plugin
+// is only allowed to set the attributes of its own plugin ID.
+func (s *tstoreServer) InventoryAttributesSet(args *InventoryAttributesSetArgs, reply *ErrorReply) error {
+	log.Tracef("%v tstore InventoryAttributesSet: %x", s.pluginID, args.Token)
+
+	if args.PluginID != s.pluginID {
+		reply.Error = encodeError(fmt.Errorf("plugin %v is not allowed "+
+			"to set the attributes of plugin %v", s.pluginID, args.PluginID))
+		return nil
+	}
+
+	reply.Error = encodeError(s.tstore.InventoryAttributesSet(args.Token,
+		args.PluginID, args.Attributes))
+	return nil
+}
+
+// PluginEventPublish publishes a plugin event to the backend event stream. A
+// plugin is only allowed to publish events for its own plugin ID.
+func (s *tstoreServer) PluginEventPublish(args *PluginEventPublishArgs, reply *ErrorReply) error {
+	log.Tracef("%v tstore PluginEventPublish: %x %v",
+		s.pluginID, args.Token, args.Name)
+
+	if args.PluginID != s.pluginID {
+		reply.Error = encodeError(fmt.Errorf("plugin %v is not allowed "+
+			"to publish the events of plugin %v", s.pluginID, args.PluginID))
+		return nil
+	}
+
+	reply.Error = encodeError(s.tstore.PluginEventPublish(args.Token,
+		args.PluginID, args.Name, args.Payload))
+	return nil
+}
+
+var (
+	_ plugins.TstoreClient = (*tstoreClient)(nil)
+)
+
+// tstoreClient is the plugins.TstoreClient that is provided to an external
+// plugin. It runs in the plugin process and forwards the calls to the
+// tstoreServer that is running in politeiad.
+//
+// tstoreClient satisfies the plugins TstoreClient interface.
+type tstoreClient struct {
+	client *rpc.Client
+}
+
+// call executes a Tstore RPC method.
+func (c *tstoreClient) call(method string, args, reply interface{}) error {
+	return c.client.Call(tstoreService+"."+method, args, reply)
+}
+
+// BlobSave saves a BlobEntry to the tstore instance.
+//
+// This function satisfies the plugins TstoreClient interface.
+func (c *tstoreClient) BlobSave(token []byte, be store.BlobEntry) error {
+	var reply ErrorReply
+	err := c.call("BlobSave", BlobSaveArgs{
+		Token:     token,
+		BlobEntry: be,
+	}, &reply)
+	if err != nil {
+		return err
+	}
+	return decodeError(reply.Error)
+}
+
+// BlobsDel deletes the blobs that correspond to the provided digests.
+//
+// This function satisfies the plugins TstoreClient interface.
+func (c *tstoreClient) BlobsDel(token []byte, digests [][]byte) error {
+	var reply ErrorReply
+	err := c.call("BlobsDel", DigestsArgs{
+		Token:   token,
+		Digests: digests,
+	}, &reply)
+	if err != nil {
+		return err
+	}
+	return decodeError(reply.Error)
+}
+
+// Blobs returns the blobs that correspond to the provided digests.
+//
+// This function satisfies the plugins TstoreClient interface.
+func (c *tstoreClient) Blobs(token []byte, digests [][]byte) (map[string]store.BlobEntry, error) {
+	var reply BlobsReply
+	err := c.call("Blobs", DigestsArgs{
+		Token:   token,
+		Digests: digests,
+	}, &reply)
+	if err != nil {
+		return nil, err
+	}
+	if reply.Error != nil {
+		return nil, decodeError(reply.Error)
+	}
+	if reply.Blobs == nil {
+		reply.Blobs = make(map[string]store.BlobEntry)
+	}
+	return reply.Blobs, nil
+}
+
+// BlobsByDataDesc returns all blobs that match the provided data descriptor.
+//
+// This function satisfies the plugins TstoreClient interface.
+func (c *tstoreClient) BlobsByDataDesc(token []byte, dataDesc []string) ([]store.BlobEntry, error) {
+	var reply BlobsByDataDescReply
+	err := c.call("BlobsByDataDesc", DataDescArgs{
+		Token:    token,
+		DataDesc: dataDesc,
+	}, &reply)
+	if err != nil {
+		return nil, err
+	}
+	if reply.Error != nil {
+		return nil, decodeError(reply.Error)
+	}
+	return reply.Blobs, nil
+}
+
+// DigestsByDataDesc returns the digests of all blobs that match the provided
+// data descriptor.
+//
+// This function satisfies the plugins TstoreClient interface.
+func (c *tstoreClient) DigestsByDataDesc(token []byte, dataDesc []string) ([][]byte, error) {
+	var reply DigestsReply
+	err := c.call("DigestsByDataDesc", DataDescArgs{
+		Token:    token,
+		DataDesc: dataDesc,
+	}, &reply)
+	if err != nil {
+		return nil, err
+	}
+	if reply.Error != nil {
+		return nil, decodeError(reply.Error)
+	}
+	return reply.Digests, nil
+}
+
+// Timestamp returns the timestamp for the blob that correpsonds to the
+// digest.
+//
+// This function satisfies the plugins TstoreClient interface.
+func (c *tstoreClient) Timestamp(token []byte, digest []byte) (*backend.Timestamp, error) {
+	var reply TimestampReply
+	err := c.call("Timestamp", TimestampArgs{
+		Token:  token,
+		Digest: digest,
+	}, &reply)
+	if err != nil {
+		return nil, err
+	}
+	if reply.Error != nil {
+		return nil, decodeError(reply.Error)
+	}
+	return reply.Timestamp, nil
+}
+
+// Record returns a version of a record.
+//
+// This function satisfies the plugins TstoreClient interface.
+func (c *tstoreClient) Record(token []byte, version uint32) (*backend.Record, error) {
+	return c.RecordPartial(token, version, nil, false)
+}
+
+// RecordLatest returns the most recent version of a record.
+//
+// This function satisfies the plugins TstoreClient interface.
+func (c *tstoreClient) RecordLatest(token []byte) (*backend.Record, error) {
+	return c.RecordPartial(token, 0, nil, false)
+}
+
+// RecordPartial returns a partial record.
+//
+// This function satisfies the plugins TstoreClient interface.
+func (c *tstoreClient) RecordPartial(token []byte, version uint32, filenames []string, omitAllFiles bool) (*backend.Record, error) {
+	var reply RecordReply
+	err := c.call("RecordPartial", RecordPartialArgs{
+		Token:        token,
+		Version:      version,
+		Filenames:    filenames,
+		OmitAllFiles: omitAllFiles,
+	}, &reply)
+	if err != nil {
+		return nil, err
+	}
+	if reply.Error != nil {
+		return nil, decodeError(reply.Error)
+	}
+	return reply.Record, nil
+}
+
+// RecordState returns whether the record is unvetted or vetted.
+//
+// This function satisfies the plugins TstoreClient interface.
+func (c *tstoreClient) RecordState(token []byte) (backend.StateT, error) {
+	var reply RecordStateReply
+	err := c.call("RecordState", RecordStateArgs{
+		Token: token,
+	}, &reply)
+	if err != nil {
+		return backend.StateInvalid, err
+	}
+	if reply.Error != nil {
+		return backend.StateInvalid, decodeError(reply.Error)
+	}
+	return reply.State, nil
+}
+
+// InventoryAttributesSet sets inventory attributes for a record.
+//
+// This function satisfies the plugins TstoreClient interface.
+func (c *tstoreClient) InventoryAttributesSet(token []byte, pluginID string, attrs map[string]string) error {
+	var reply ErrorReply
+	err := c.call("InventoryAttributesSet", InventoryAttributesSetArgs{
+		Token:      token,
+		PluginID:   pluginID,
+		Attributes: attrs,
+	}, &reply)
+	if err != nil {
+		return err
+	}
+	return decodeError(reply.Error)
+}
+
+// PluginEventPublish publishes a plugin event to the backend event stream.
+//
+// This function satisfies the plugins TstoreClient interface.
+func (c *tstoreClient) PluginEventPublish(token []byte, pluginID, name, payload string) error {
+	var reply ErrorReply
+	err := c.call("PluginEventPublish", PluginEventPublishArgs{
+		Token:    token,
+		PluginID: pluginID,
+		Name:     name,
+		Payload:  payload,
+	}, &reply)
+	if err != nil {
+		return err
+	}
+	return decodeError(reply.Error)
+}
@@ -135,7 +135,7 @@ func (p *piPlugin) hookPluginPre(payload string) error {
 // proposalNameIsValid returns whether the provided name is a valid proposal
 // name.
 func (p *piPlugin) proposalNameIsValid(name string) bool {
-	return p.proposalNameRegexp.MatchString(name)
+	return p.settingsGet().proposalNameRegexp.MatchString(name)
 }
 
 // proposalFilesVerify verifies the files adhere to all pi plugin setting
@@ -144,6 +144,8 @@ func (p *piPlugin) proposalNameIsValid(name string) bool {
 // name, a valid base64 payload, and that the file digest and MIME type are
 // correct.
 func (p *piPlugin) proposalFilesVerify(files []backend.File) error {
+	s := p.settingsGet()
+
 	var imagesCount uint32
 	for _, v := range files {
 		payload, err := base64.StdEncoding.DecodeString(v.Payload)
@@ -165,14 +167,14 @@ func (p *piPlugin) proposalFilesVerify(files []backend.File) error {
 			}
 
 			// Verify text file size
-			if len(payload) > int(p.textFileSizeMax) {
+			if len(payload) > int(s.textFileSizeMax) {
 				return backend.PluginError{
 					PluginID:  pi.PluginID,
 					ErrorCode: uint32(pi.ErrorCodeTextFileSizeInvalid),
 					ErrorContext: fmt.Sprintf("file %v "+
 						"size %v exceeds max size %v",
 						v.Name, len(payload),
-						p.textFileSizeMax),
+						s.textFileSizeMax),
 				}
 			}
 
@@ -180,14 +182,14 @@ func (p *piPlugin) proposalFilesVerify(files []backend.File) error {
 			imagesCount++
 
 			// Verify image file size
-			if len(payload) > int(p.imageFileSizeMax) {
+			if len(payload) > int(s.imageFileSizeMax) {
 				return backend.PluginError{
 					PluginID:  pi.PluginID,
 					ErrorCode: uint32(pi.ErrorCodeImageFileSizeInvalid),
 					ErrorContext: fmt.Sprintf("image %v "+
 						"size %v exceeds max size %v",
 						v.Name, len(payload),
-						p.imageFileSizeMax),
+						s.imageFileSizeMax),
 				}
 			}
 
@@ -213,12 +215,12 @@ func (p *piPlugin) proposalFilesVerify(files []backend.File) error {
 	}
 
 	// Verify image file count is acceptable
-	if imagesCount > p.imageFileCountMax {
+	if imagesCount > s.imageFileCountMax {
 		return backend.PluginError{
 			PluginID:  pi.PluginID,
 			ErrorCode: uint32(pi.ErrorCodeImageFileCountInvalid),
 			ErrorContext: fmt.Sprintf("got %v image files, max "+
-				"is %v", imagesCount, p.imageFileCountMax),
+				"is %v", imagesCount, s.imageFileCountMax),
 		}
 	}
 
@@ -236,11 +238,11 @@ func (p *piPlugin) proposalFilesVerify(files []backend.File) error {
 	}
 
 	// Verify proposal name
-	if !p.proposalNameIsValid(pm.Name) {
+	if !s.proposalNameRegexp.MatchString(pm.Name) {
 		return backend.PluginError{
 			PluginID:     pi.PluginID,
 			ErrorCode:    uint32(pi.ErrorCodeProposalNameInvalid),
-			ErrorContext: p.proposalNameRegexp.String(),
+			ErrorContext: s.proposalNameRegexp.String(),
 		}
 	}
 
@@ -266,7 +268,7 @@ func (p *piPlugin) voteSummary(token []byte) (*ticketvote.SummaryReply, error) {
 // commentWritesAllowed verifies that a proposal has a vote status that allows
 // comment writes to be made to the proposal. This includes both comments and
 // comment votes. Comment writes are allowed up until the proposal has finished
-// voting.
+// voting or the vote has been cancelled by an admin.
 func (p *piPlugin) commentWritesAllowed(token []byte) error {
 	vs, err := p.voteSummary(token)
 	if err != nil {
@@ -277,6 +279,14 @@ func (p *piPlugin) commentWritesAllowed(token []byte) error {
 		ticketvote.VoteStatusStarted:
 		// Comment writes are allowed on these vote statuses
 		return nil
+	case ticketvote.VoteStatusCancelled:
+		// The vote was cancelled by an admin. The proposal is locked
+		// the same way it would have been had the vote finished.
+		return backend.PluginError{
+			PluginID:     pi.PluginID,
+			ErrorCode:    uint32(pi.ErrorCodeVoteStatusInvalid),
+			ErrorContext: "vote has been cancelled; proposal is locked",
+		}
 	default:
 		// Vote status does not allow writes
 		return backend.PluginError{
@@ -6,16 +6,14 @@ package pi
 
 import (
 	"encoding/json"
-	"fmt"
 	"os"
 	"path/filepath"
-	"regexp"
 	"strconv"
+	"sync"
 
 	backend "github.com/decred/politeia/politeiad/backendv2"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
 	"github.com/decred/politeia/politeiad/plugins/pi"
-	"github.com/decred/politeia/util"
 )
 
 var (
@@ -35,15 +33,10 @@ type piPlugin struct {
 	// by walking the trillian trees.
 	dataDir string
 
-	// Plugin settings
-	textFileCountMax           uint32
-	textFileSizeMax            uint32 // In bytes
-	imageFileCountMax          uint32
-	imageFileSizeMax           uint32 // In bytes
-	proposalNameSupportedChars string // JSON encoded []string
-	proposalNameLengthMin      uint32 // In characters
-	proposalNameLengthMax      uint32 // In characters
-	proposalNameRegexp         *regexp.Regexp
+	// Plugin settings. The settings can be updated at runtime and are
+	// protected by the settings mutex.
+	mtxSettings sync.RWMutex
+	settings    pluginSettings
 }
 
 // Setup performs any plugin setup that is required.
@@ -91,44 +84,74 @@ func (p *piPlugin) Fsck() error {
 	return nil
 }
 
+// CacheRebuild rebuilds the plugin caches. The pi plugin does not keep any
+// caches that are derived from tstore data.
+//
+// This function satisfies the plugins PluginClient interface.
+func (p *piPlugin) CacheRebuild(tokens [][]byte, progress func()) error {
+	log.Tracef("pi CacheRebuild")
+
+	return nil
+}
+
 // Settings returns the plugin's settings.
 //
 // This function satisfies the plugins PluginClient interface.
 func (p *piPlugin) Settings() []backend.PluginSetting {
 	log.Tracef("pi Settings")
 
+	s := p.settingsGet()
+
+	// The supported chars are returned JSON encoded. The setting was
+	// verified when it was parsed so the encoding will not fail.
+	b, _ := json.Marshal(s.proposalNameSupportedChars)
+
 	return []backend.PluginSetting{
 		{
 			Key:   pi.SettingKeyTextFileSizeMax,
-			Value: strconv.FormatUint(uint64(p.textFileSizeMax), 10),
-		},
-		{
-			Key:   pi.SettingKeyImageFileCountMax,
-			Value: strconv.FormatUint(uint64(p.imageFileCountMax), 10),
+			Value: strconv.FormatUint(uint64(s.textFileSizeMax), 10),
 		},
 		{
 			Key:   pi.SettingKeyImageFileCountMax,
-			Value: strconv.FormatUint(uint64(p.imageFileCountMax), 10),
+			Value: strconv.FormatUint(uint64(s.imageFileCountMax), 10),
 		},
 		{
 			Key:   pi.SettingKeyImageFileSizeMax,
-			Value: strconv.FormatUint(uint64(p.imageFileSizeMax), 10),
+			Value: strconv.FormatUint(uint64(s.imageFileSizeMax), 10),
 		},
 		{
 			Key:   pi.SettingKeyProposalNameLengthMin,
-			Value: strconv.FormatUint(uint64(p.proposalNameLengthMin), 10),
+			Value: strconv.FormatUint(uint64(s.proposalNameLengthMin), 10),
 		},
 		{
 			Key:   pi.SettingKeyProposalNameLengthMax,
-			Value: strconv.FormatUint(uint64(p.proposalNameLengthMax), 10),
+			Value: strconv.FormatUint(uint64(s.proposalNameLengthMax), 10),
 		},
 		{
 			Key:   pi.SettingKeyProposalNameSupportedChars,
-			Value: p.proposalNameSupportedChars,
+			Value: string(b),
 		},
 	}
 }
 
+// SettingsUpdate updates the plugin settings at runtime.
+//
+// This function satisfies the plugins PluginClient interface.
+func (p *piPlugin) SettingsUpdate(settings []backend.PluginSetting) error {
+	log.Tracef("pi SettingsUpdate: %v", settings)
+
+	p.mtxSettings.Lock()
+	defer p.mtxSettings.Unlock()
+
+	s, err := settingsParse(p.settings, settings)
+	if err != nil {
+		return err
+	}
+	p.settings = s
+
+	return nil
+}
+
 // New returns a new piPlugin.
 func New(backend backend.Backend, settings []backend.PluginSetting, dataDir string) (*piPlugin, error) {
 	// Create plugin data directory
@@ -138,91 +161,15 @@ func New(backend backend.Backend, settings []backend.PluginSetting, dataDir stri
 		return nil, err
 	}
 
-	// Setup plugin setting default values
-	var (
-		textFileSizeMax    = pi.SettingTextFileSizeMax
-		imageFileCountMax  = pi.SettingImageFileCountMax
-		imageFileSizeMax   = pi.SettingImageFileSizeMax
-		nameLengthMin      = pi.SettingProposalNameLengthMin
-		nameLengthMax      = pi.SettingProposalNameLengthMax
-		nameSupportedChars = pi.SettingProposalNameSupportedChars
-	)
-
-	// Override defaults with any passed in settings
-	for _, v := range settings {
-		switch v.Key {
-		case pi.SettingKeyTextFileSizeMax:
-			u, err := strconv.ParseUint(v.Value, 10, 64)
-			if err != nil {
-				return nil, fmt.Errorf("invalid plugin setting %v '%v': %v",
-					v.Key, v.Value, err)
-			}
-			textFileSizeMax = uint32(u)
-		case pi.SettingKeyImageFileCountMax:
-			u, err := strconv.ParseUint(v.Value, 10, 64)
-			if err != nil {
-				return nil, fmt.Errorf("invalid plugin setting %v '%v': %v",
-					v.Key, v.Value, err)
-			}
-			imageFileCountMax = uint32(u)
-		case pi.SettingKeyImageFileSizeMax:
-			u, err := strconv.ParseUint(v.Value, 10, 64)
-			if err != nil {
-				return nil, fmt.Errorf("invalid plugin setting %v '%v': %v",
-					v.Key, v.Value, err)
-			}
-			imageFileSizeMax = uint32(u)
-		case pi.SettingKeyProposalNameLengthMin:
-			u, err := strconv.ParseUint(v.Value, 10, 64)
-			if err != nil {
-				return nil, fmt.Errorf("invalid plugin setting %v '%v': %v",
-					v.Key, v.Value, err)
-			}
-			nameLengthMin = uint32(u)
-		case pi.SettingKeyProposalNameLengthMax:
-			u, err := strconv.ParseUint(v.Value, 10, 64)
-			if err != nil {
-				return nil, fmt.Errorf("invalid plugin setting %v '%v': %v",
-					v.Key, v.Value, err)
-			}
-			nameLengthMax = uint32(u)
-		case pi.SettingKeyProposalNameSupportedChars:
-			var sc []string
-			err := json.Unmarshal([]byte(v.Value), &sc)
-			if err != nil {
-				return nil, fmt.Errorf("invalid plugin setting %v '%v': %v",
-					v.Key, v.Value, err)
-			}
-			nameSupportedChars = sc
-		default:
-			return nil, fmt.Errorf("invalid plugin setting: %v", v.Key)
-		}
-	}
-
-	// Setup proposal name regex
-	rexp, err := util.Regexp(nameSupportedChars, uint64(nameLengthMin),
-		uint64(nameLengthMax))
-	if err != nil {
-		return nil, fmt.Errorf("proposal name regexp: %v", err)
-	}
-
-	// Encode the supported chars so that they can be returned as a
-	// plugin setting string.
-	b, err := json.Marshal(nameSupportedChars)
+	// Override the default settings with any passed in settings
+	s, err := settingsParse(settingsDefault(), settings)
 	if err != nil {
 		return nil, err
 	}
-	nameSupportedCharsString := string(b)
 
 	return &piPlugin{
-		dataDir:                    dataDir,
-		backend:                    backend,
-		textFileSizeMax:            textFileSizeMax,
-		imageFileCountMax:          imageFileCountMax,
-		imageFileSizeMax:           imageFileSizeMax,
-		proposalNameLengthMin:      nameLengthMin,
-		proposalNameLengthMax:      nameLengthMax,
-		proposalNameSupportedChars: nameSupportedCharsString,
-		proposalNameRegexp:         rexp,
+		dataDir:  dataDir,
+		backend:  backend,
+		settings: s,
 	}, nil
 }
@@ -0,0 +1,152 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package pi
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/pi"
+	"github.com/decred/politeia/util"
+)
+
+// pluginSettings contains the pi plugin settings.
+type pluginSettings struct {
+	textFileSizeMax            uint32 // In bytes
+	imageFileCountMax          uint32
+	imageFileSizeMax           uint32 // In bytes
+	proposalNameSupportedChars []string
+	proposalNameLengthMin      uint32 // In characters
+	proposalNameLengthMax      uint32 // In characters
+
+	// proposalNameRegexp is created from the proposal name settings.
+	proposalNameRegexp *regexp.Regexp
+}
+
+// settingsDefault returns the default plugin settings. The proposal name
+// regexp is not set. It is created by settingsParse.
+func settingsDefault() pluginSettings {
+	return pluginSettings{
+		textFileSizeMax:            pi.SettingTextFileSizeMax,
+		imageFileCountMax:          pi.SettingImageFileCountMax,
+		imageFileSizeMax:           pi.SettingImageFileSizeMax,
+		proposalNameSupportedChars: pi.SettingProposalNameSupportedChars,
+		proposalNameLengthMin:      pi.SettingProposalNameLengthMin,
+		proposalNameLengthMax:      pi.SettingProposalNameLengthMax,
+	}
+}
+
+// settingsGet returns the plugin settings. The settings can be updated at
+// runtime so they must be read using this function.
+func (p *piPlugin) settingsGet() pluginSettings {
+	p.mtxSettings.RLock()
+	defer p.mtxSettings.RUnlock()
+
+	return p.settings
+}
+
+// settingsParse applies the provided plugin settings to the existing settings
+// and returns the result. The proposal name regexp is re-created using the
+// resulting settings. A backend PluginSettingError is returned if any of the
+// settings are invalid.
+func settingsParse(s pluginSettings, ss []backend.PluginSetting) (pluginSettings, error) {
+	// nameSetting is the last proposal name setting that was applied.
+	// It is used as the error context if the proposal name regexp
+	// cannot be created. The supported chars were verified when they
+	// were parsed so the encoding will not fail.
+	b, _ := json.Marshal(s.proposalNameSupportedChars)
+	nameSetting := backend.PluginSetting{
+		Key:   pi.SettingKeyProposalNameSupportedChars,
+		Value: string(b),
+	}
+	for _, v := range ss {
+		switch v.Key {
+		case pi.SettingKeyTextFileSizeMax:
+			u, err := settingParseUint(v)
+			if err != nil {
+				return s, err
+			}
+			s.textFileSizeMax = u
+		case pi.SettingKeyImageFileCountMax:
+			u, err := settingParseUint(v)
+			if err != nil {
+				return s, err
+			}
+			s.imageFileCountMax = u
+		case pi.SettingKeyImageFileSizeMax:
+			u, err := settingParseUint(v)
+			if err != nil {
+				return s, err
+			}
+			s.imageFileSizeMax = u
+		case pi.SettingKeyProposalNameLengthMin:
+			u, err := settingParseUint(v)
+			if err != nil {
+				return s, err
+			}
+			s.proposalNameLengthMin = u
+			nameSetting = v
+		case pi.SettingKeyProposalNameLengthMax:
+			u, err := settingParseUint(v)
+			if err != nil {
+				return s, err
+			}
+			s.proposalNameLengthMax = u
+			nameSetting = v
+		case pi.SettingKeyProposalNameSupportedChars:
+			var sc []string
+			err := json.Unmarshal([]byte(v.Value), &sc)
+			if err != nil {
+				return s, settingError(v, err.Error())
+			}
+			s.proposalNameSupportedChars = sc
+			nameSetting = v
+		default:
+			return s, settingError(v, "unknown setting")
+		}
+	}
+
+	// Setup the proposal name regexp
+	if s.proposalNameLengthMin > s.proposalNameLengthMax {
+		return s, settingError(nameSetting, fmt.Sprintf("proposal name "+
+			"length min %v exceeds length max %v", s.proposalNameLengthMin,
+			s.proposalNameLengthMax))
+	}
+	rexp, err := util.Regexp(s.proposalNameSupportedChars,
+		uint64(s.proposalNameLengthMin), uint64(s.proposalNameLengthMax))
+	if err != nil {
+		return s, settingError(nameSetting,
+			fmt.Sprintf("proposal name regexp: %v", err))
+	}
+	s.proposalNameRegexp = rexp
+
+	return s, nil
+}
+
+// settingParseUint parses a uint32 plugin setting. The setting must be greater
+// than zero.
+func settingParseUint(s backend.PluginSetting) (uint32, error) {
+	u, err := strconv.ParseUint(s.Value, 10, 32)
+	if err != nil {
+		return 0, settingError(s, err.Error())
+	}
+	if u == 0 {
+		return 0, settingError(s, "must be greater than zero")
+	}
+	return uint32(u), nil
+}
+
+// settingError returns a backend PluginSettingError for the provided setting.
+func settingError(s backend.PluginSetting, reason string) error {
+	return backend.PluginSettingError{
+		PluginID: pi.PluginID,
+		Key:      s.Key,
+		Value:    s.Value,
+		Reason:   reason,
+	}
+}
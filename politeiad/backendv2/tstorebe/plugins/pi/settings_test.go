@@ -0,0 +1,152 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package pi
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/pi"
+)
+
+func TestSettingsUpdate(t *testing.T) {
+	setting := func(key, value string) backend.PluginSetting {
+		return backend.PluginSetting{
+			Key:   key,
+			Value: value,
+		}
+	}
+
+	// Setup the rejected settings. The key of the setting that the
+	// error is expected to reference is included with each test.
+	var tests = []struct {
+		name     string
+		settings []backend.PluginSetting
+		wantKey  string
+	}{
+		{
+			"unknown setting",
+			[]backend.PluginSetting{
+				setting("unknown", "1"),
+			},
+			"unknown",
+		},
+		{
+			"text file size max not a number",
+			[]backend.PluginSetting{
+				setting(pi.SettingKeyTextFileSizeMax, "512kb"),
+			},
+			pi.SettingKeyTextFileSizeMax,
+		},
+		{
+			"image file count max zero",
+			[]backend.PluginSetting{
+				setting(pi.SettingKeyImageFileCountMax, "0"),
+			},
+			pi.SettingKeyImageFileCountMax,
+		},
+		{
+			"image file size max overflows uint32",
+			[]backend.PluginSetting{
+				setting(pi.SettingKeyImageFileSizeMax, "4294967296"),
+			},
+			pi.SettingKeyImageFileSizeMax,
+		},
+		{
+			"proposal name length min negative",
+			[]backend.PluginSetting{
+				setting(pi.SettingKeyProposalNameLengthMin, "-8"),
+			},
+			pi.SettingKeyProposalNameLengthMin,
+		},
+		{
+			"proposal name length min exceeds max",
+			[]backend.PluginSetting{
+				setting(pi.SettingKeyProposalNameLengthMin, "81"),
+			},
+			pi.SettingKeyProposalNameLengthMin,
+		},
+		{
+			"proposal name length max below min",
+			[]backend.PluginSetting{
+				setting(pi.SettingKeyProposalNameLengthMax, "7"),
+			},
+			pi.SettingKeyProposalNameLengthMax,
+		},
+		{
+			"proposal name length max exceeds regexp limit",
+			[]backend.PluginSetting{
+				setting(pi.SettingKeyProposalNameLengthMax, "1001"),
+			},
+			pi.SettingKeyProposalNameLengthMax,
+		},
+		{
+			"proposal name supported chars not json",
+			[]backend.PluginSetting{
+				setting(pi.SettingKeyProposalNameSupportedChars, "A-z"),
+			},
+			pi.SettingKeyProposalNameSupportedChars,
+		},
+		{
+			"proposal name supported chars invalid range",
+			[]backend.PluginSetting{
+				setting(pi.SettingKeyProposalNameSupportedChars, `["z-a"]`),
+			},
+			pi.SettingKeyProposalNameSupportedChars,
+		},
+		{
+			"valid setting followed by an invalid setting",
+			[]backend.PluginSetting{
+				setting(pi.SettingKeyTextFileSizeMax, "1024"),
+				setting(pi.SettingKeyImageFileCountMax, "-1"),
+			},
+			pi.SettingKeyImageFileCountMax,
+		},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			p, cleanup := newTestPiPlugin(t)
+			defer cleanup()
+			s := p.settingsGet()
+
+			err := p.SettingsUpdate(v.settings)
+			var e backend.PluginSettingError
+			if !errors.As(err, &e) {
+				t.Fatalf("got error %v, want PluginSettingError", err)
+			}
+			if e.PluginID != pi.PluginID || e.Key != v.wantKey {
+				t.Fatalf("got plugin %v key %v, want %v %v",
+					e.PluginID, e.Key, pi.PluginID, v.wantKey)
+			}
+
+			// None of the settings are updated
+			if !reflect.DeepEqual(p.settingsGet(), s) {
+				t.Fatalf("got settings %+v, want %+v", p.settingsGet(), s)
+			}
+		})
+	}
+
+	// Verify that a valid update is applied and that the proposal
+	// name regexp is re-created using the updated settings.
+	p, cleanup := newTestPiPlugin(t)
+	defer cleanup()
+	err := p.SettingsUpdate([]backend.PluginSetting{
+		setting(pi.SettingKeyProposalNameLengthMin, "4"),
+		setting(pi.SettingKeyProposalNameSupportedChars, `["a-z"]`),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := p.settingsGet()
+	if s.proposalNameLengthMin != 4 ||
+		!reflect.DeepEqual(s.proposalNameSupportedChars, []string{"a-z"}) {
+		t.Fatalf("got settings %+v", s)
+	}
+	if !p.proposalNameIsValid("abcd") || p.proposalNameIsValid("ABCD") {
+		t.Fatalf("proposal name regexp was not updated")
+	}
+}
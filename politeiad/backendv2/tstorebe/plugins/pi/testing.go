@@ -5,13 +5,11 @@
 package pi
 
 import (
-	"encoding/json"
 	"io/ioutil"
 	"os"
 	"testing"
 
 	"github.com/decred/politeia/politeiad/plugins/pi"
-	"github.com/decred/politeia/util"
 )
 
 // newTestPiPlugin returns a piPlugin that has been setup for testing.
@@ -22,36 +20,16 @@ func newTestPiPlugin(t *testing.T) (*piPlugin, func()) {
 		t.Fatal(err)
 	}
 
-	// Setup proposal name regex
-	var (
-		nameSupportedChars = pi.SettingProposalNameSupportedChars
-		nameLengthMin      = pi.SettingProposalNameLengthMin
-		nameLengthMax      = pi.SettingProposalNameLengthMax
-	)
-	rexp, err := util.Regexp(nameSupportedChars, uint64(nameLengthMin),
-		uint64(nameLengthMax))
+	// Setup the default plugin settings
+	s, err := settingsParse(settingsDefault(), nil)
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	// Encode the supported chars. This is done so that they can be
-	// returned as a plugin setting string.
-	b, err := json.Marshal(nameSupportedChars)
-	if err != nil {
-		t.Fatal(err)
-	}
-	nameSupportedCharsString := string(b)
-
 	// Setup plugin context
 	p := piPlugin{
-		dataDir:                    dataDir,
-		textFileSizeMax:            pi.SettingTextFileSizeMax,
-		imageFileCountMax:          pi.SettingImageFileCountMax,
-		imageFileSizeMax:           pi.SettingImageFileSizeMax,
-		proposalNameLengthMin:      nameLengthMin,
-		proposalNameLengthMax:      nameLengthMax,
-		proposalNameSupportedChars: nameSupportedCharsString,
-		proposalNameRegexp:         rexp,
+		dataDir:  dataDir,
+		settings: s,
 	}
 
 	return &p, func() {
@@ -62,8 +62,12 @@ const (
 	// HookTypePluginPost is called after a plugin command is executed.
 	HookTypePluginPost HookT = 10
 
+	// HookTypeRecordImportPost is called after a record bundle has
+	// been imported as a new record.
+	HookTypeRecordImportPost HookT = 11
+
 	// HookTypeLast unit test only
-	HookTypeLast HookT = 11
+	HookTypeLast HookT = 12
 )
 
 var (
@@ -80,6 +84,7 @@ var (
 		HookTypeSetRecordStatusPost: "set record status post",
 		HookTypePluginPre:           "plugin pre",
 		HookTypePluginPost:          "plugin post",
+		HookTypeRecordImportPost:    "record import post",
 	}
 )
 
@@ -141,6 +146,15 @@ type HookPluginPost struct {
 	Reply    string `json:"reply"`
 }
 
+// HookRecordImportPost is the payload for the post record import hook. The
+// record is the most recent version of the imported record and its record
+// metadata contains the token of the new record. The plugin data of the
+// record is imported unmodified, so it may still reference the token of the
+// record that was exported.
+type HookRecordImportPost struct {
+	Record backend.Record `json:"record"`
+}
+
 // PluginClient provides an API for a tstore instance to use when interacting
 // with a plugin. All tstore plugins must implement the PluginClient interface.
 type PluginClient interface {
@@ -156,8 +170,23 @@ type PluginClient interface {
 	// Fsck performs a plugin file system check.
 	Fsck() error
 
+	// CacheRebuild deletes the plugin caches and rebuilds them from
+	// the plugin data that has been saved to tstore. The provided
+	// tokens are the tokens of all records in the tstore instance.
+	// The progress function is called each time the plugin has
+	// finished processing a record. Tstore writes are blocked for the
+	// duration of the rebuild.
+	CacheRebuild(tokens [][]byte, progress func()) error
+
 	// Settings returns the plugin settings.
 	Settings() []backend.PluginSetting
+
+	// SettingsUpdate updates the plugin settings at runtime. Only the
+	// provided settings are updated. The plugin must validate the new
+	// values and return a backend PluginSettingError if any of them
+	// are invalid or cannot be changed at runtime, in which case none
+	// of the settings are updated.
+	SettingsUpdate(settings []backend.PluginSetting) error
 }
 
 // TstoreClient provides an API for plugins to interact with a tstore instance.
@@ -222,4 +251,18 @@ type TstoreClient interface {
 
 	// RecordState returns whether the record is unvetted or vetted.
 	RecordState(token []byte) (backend.StateT, error)
+
+	// InventoryAttributesSet sets inventory attributes for a record.
+	// The attributes can be used to filter the backend inventory. The
+	// attribute keys are namespaced using the plugin ID. An empty
+	// attribute value deletes the attribute.
+	InventoryAttributesSet(token []byte, pluginID string,
+		attrs map[string]string) error
+
+	// PluginEventPublish publishes a plugin event to the backend event
+	// stream. Plugins use this to announce changes that are not the
+	// result of a plugin write command. The token is optional. The
+	// event name and payload are defined by the plugin.
+	PluginEventPublish(token []byte, pluginID, name,
+		payload string) error
 }
@@ -0,0 +1,89 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	"encoding/json"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/search"
+)
+
+// cmdSearch returns a page of records that match the provided search query,
+// ranked by relevance.
+func (p *searchPlugin) cmdSearch(payload string) (string, error) {
+	// Decode payload
+	var s search.Search
+	err := json.Unmarshal([]byte(payload), &s)
+	if err != nil {
+		return "", err
+	}
+
+	// Parse the query terms. Duplicate terms are removed.
+	var (
+		qt   = terms(s.Query)
+		seen = make(map[string]struct{}, len(qt))
+		uniq = make([]string, 0, len(qt))
+	)
+	for _, v := range qt {
+		if _, ok := seen[v]; ok {
+			continue
+		}
+		seen[v] = struct{}{}
+		uniq = append(uniq, v)
+	}
+	if len(uniq) == 0 {
+		return "", backend.PluginError{
+			PluginID:     search.PluginID,
+			ErrorCode:    uint32(search.ErrorCodeQueryInvalid),
+			ErrorContext: "query does not contain any searchable terms",
+		}
+	}
+
+	// Rank the records
+	state := backend.StateVetted
+	if s.Unvetted {
+		state = backend.StateUnvetted
+	}
+	results := p.rank(state, uniq)
+
+	// Compile the requested page
+	page := s.Page
+	if page == 0 {
+		page = 1
+	}
+	var (
+		pageSize = uint64(p.pageSizeGet())
+		start    = uint64(page-1) * pageSize
+		end      = start + pageSize
+		total    = uint64(len(results))
+	)
+	if start > total {
+		start = total
+	}
+	if end > total {
+		end = total
+	}
+	sr := make([]search.SearchResult, 0, end-start)
+	for _, v := range results[start:end] {
+		sr = append(sr, search.SearchResult{
+			Token:     v.doc.Token,
+			Name:      v.doc.Name,
+			Score:     v.score,
+			Timestamp: v.doc.Timestamp,
+		})
+	}
+
+	// Prepare reply
+	reply, err := json.Marshal(search.SearchReply{
+		Results: sr,
+		Total:   uint32(total),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	"encoding/json"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
+)
+
+// hookNewRecordPost adds a new record to the search index.
+func (p *searchPlugin) hookNewRecordPost(payload string) error {
+	var nr plugins.HookNewRecordPost
+	err := json.Unmarshal([]byte(payload), &nr)
+	if err != nil {
+		return err
+	}
+
+	d, err := p.docNew(nr.RecordMetadata, nr.Files)
+	if err != nil {
+		return err
+	}
+	return p.docSave(*d)
+}
+
+// hookEditRecordPost re-indexes a record using the updated record files.
+func (p *searchPlugin) hookEditRecordPost(payload string) error {
+	var er plugins.HookEditRecord
+	err := json.Unmarshal([]byte(payload), &er)
+	if err != nil {
+		return err
+	}
+
+	d, err := p.docNew(er.RecordMetadata, er.Files)
+	if err != nil {
+		return err
+	}
+	return p.docSave(*d)
+}
+
+// hookSetRecordStatusPost updates the state and status of a record in the
+// search index. The record is removed from the index when it is censored
+// since the record content is deleted. The saved document of a record that
+// is unvetted after the status change is deleted from the plugin data dir by
+// docSave.
+func (p *searchPlugin) hookSetRecordStatusPost(payload string) error {
+	var srs plugins.HookSetRecordStatus
+	err := json.Unmarshal([]byte(payload), &srs)
+	if err != nil {
+		return err
+	}
+	rm := srs.RecordMetadata
+
+	if rm.Status == backend.StatusCensored {
+		return p.docDel(rm.Token)
+	}
+
+	d, err := p.docNew(rm, srs.Record.Files)
+	if err != nil {
+		return err
+	}
+	return p.docSave(*d)
+}
+
+// hookRecordImportPost indexes a record that has been imported. Censored
+// records are not indexed since the record content has been deleted.
+func (p *searchPlugin) hookRecordImportPost(payload string) error {
+	var ri plugins.HookRecordImportPost
+	err := json.Unmarshal([]byte(payload), &ri)
+	if err != nil {
+		return err
+	}
+	rm := ri.Record.RecordMetadata
+
+	if rm.Status == backend.StatusCensored {
+		return nil
+	}
+
+	d, err := p.docNew(rm, ri.Record.Files)
+	if err != nil {
+		return err
+	}
+	return p.docSave(*d)
+}
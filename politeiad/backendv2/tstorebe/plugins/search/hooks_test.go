@@ -0,0 +1,255 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
+	"github.com/decred/politeia/politeiad/plugins/search"
+)
+
+// newTestSearchPlugin returns a searchPlugin that has been setup for testing
+// and a closure that cleans up the test data when invoked.
+func newTestSearchPlugin(t *testing.T) (*searchPlugin, func()) {
+	t.Helper()
+
+	dataDir, err := ioutil.TempDir("", search.PluginID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := New(nil, plugins.NewTestTstore(), nil, dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return p, func() {
+		os.RemoveAll(dataDir)
+	}
+}
+
+func TestHooksVisibility(t *testing.T) {
+	p, cleanup := newTestSearchPlugin(t)
+	defer cleanup()
+
+	// hook executes a plugin hook. files returns the record files for
+	// the provided name and text.
+	hook := func(h plugins.HookT, payload interface{}) {
+		t.Helper()
+		b, err := json.Marshal(payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = p.Hook(h, string(b))
+		if err != nil {
+			t.Fatalf("%v: %v", plugins.Hooks[h], err)
+		}
+	}
+	files := func(name, text string) []backend.File {
+		md := `{"name":"` + name + `"}`
+		return []backend.File{
+			{
+				Name:    "proposalmetadata.json",
+				Payload: base64.StdEncoding.EncodeToString([]byte(md)),
+			},
+			{
+				Name:    "index.md",
+				Payload: base64.StdEncoding.EncodeToString([]byte(text)),
+			},
+		}
+	}
+
+	// query returns the tokens that are returned by the search command
+	// for a vetted and an unvetted search.
+	query := func(q string) ([]string, []string) {
+		t.Helper()
+		tokens := func(unvetted bool) []string {
+			b, err := json.Marshal(search.Search{
+				Query:    q,
+				Unvetted: unvetted,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			reply, err := p.Cmd(nil, search.CmdSearch, string(b))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var sr search.SearchReply
+			err = json.Unmarshal([]byte(reply), &sr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tokens := make([]string, 0, len(sr.Results))
+			for _, v := range sr.Results {
+				tokens = append(tokens, v.Token)
+			}
+			return tokens
+		}
+		return tokens(false), tokens(true)
+	}
+	check := func(step, q string, wantVetted, wantUnvetted []string) {
+		t.Helper()
+		vetted, unvetted := query(q)
+		if !reflect.DeepEqual(vetted, wantVetted) {
+			t.Fatalf("%v: vetted %q: got %v, want %v",
+				step, q, vetted, wantVetted)
+		}
+		if !reflect.DeepEqual(unvetted, wantUnvetted) {
+			t.Fatalf("%v: unvetted %q: got %v, want %v",
+				step, q, unvetted, wantUnvetted)
+		}
+	}
+	none := []string{}
+
+	// saved verifies whether the document of a record has been saved to
+	// the plugin data dir. Only the documents of vetted records are
+	// saved since the unvetted record content is encrypted in tstore.
+	saved := func(step, token string, want bool) {
+		t.Helper()
+		_, err := os.Stat(p.docPath(token))
+		if got := err == nil; got != want {
+			t.Fatalf("%v: got document saved %v, want %v", step, got, want)
+		}
+	}
+
+	// Submit a new record. It can only be found by unvetted searches.
+	r := backend.Record{
+		RecordMetadata: backend.RecordMetadata{
+			Token:     "a1",
+			Version:   1,
+			Iteration: 1,
+			State:     backend.StateUnvetted,
+			Status:    backend.StatusUnreviewed,
+			Timestamp: 1,
+		},
+		Files: files("Treasury audit", "An audit of the treasury."),
+	}
+	hook(plugins.HookTypeNewRecordPost, plugins.HookNewRecordPost{
+		RecordMetadata: r.RecordMetadata,
+		Files:          r.Files,
+	})
+	check("new", "treasury", none, []string{"a1"})
+	saved("new", "a1", false)
+
+	// Edit the unvetted record. The index must be updated using the
+	// edited files.
+	rm := r.RecordMetadata
+	rm.Iteration = 2
+	rm.Timestamp = 2
+	f := files("Marketing audit", "An audit of the marketing spend.")
+	hook(plugins.HookTypeEditRecordPost, plugins.HookEditRecord{
+		Record:         r,
+		RecordMetadata: rm,
+		Files:          f,
+	})
+	r.RecordMetadata, r.Files = rm, f
+	check("unvetted edit", "treasury", none, none)
+	check("unvetted edit", "marketing", none, []string{"a1"})
+
+	// Make the record public. It can only be found by vetted searches.
+	rm = r.RecordMetadata
+	rm.State = backend.StateVetted
+	rm.Status = backend.StatusPublic
+	rm.Iteration = 3
+	rm.Timestamp = 3
+	hook(plugins.HookTypeSetRecordStatusPost, plugins.HookSetRecordStatus{
+		Record:         r,
+		RecordMetadata: rm,
+	})
+	r.RecordMetadata = rm
+	check("public", "marketing", []string{"a1"}, none)
+	saved("public", "a1", true)
+
+	// Edit the public record
+	rm = r.RecordMetadata
+	rm.Version = 2
+	rm.Iteration = 4
+	rm.Timestamp = 4
+	f = files("Marketing audit", "An audit of the decred marketing spend.")
+	hook(plugins.HookTypeEditRecordPost, plugins.HookEditRecord{
+		Record:         r,
+		RecordMetadata: rm,
+		Files:          f,
+	})
+	r.RecordMetadata, r.Files = rm, f
+	check("vetted edit", "decred", []string{"a1"}, none)
+
+	// Censor the record. It must not be returned by any search.
+	rm = r.RecordMetadata
+	rm.Status = backend.StatusCensored
+	rm.Iteration = 5
+	rm.Timestamp = 5
+	hook(plugins.HookTypeSetRecordStatusPost, plugins.HookSetRecordStatus{
+		Record:         r,
+		RecordMetadata: rm,
+	})
+	check("censored", "marketing", none, none)
+	check("censored", "decred", none, none)
+	saved("censored", "a1", false)
+
+	// The censored record must not be returned once the index has been
+	// reloaded from the data dir.
+	p.docs = make(map[string]*document)
+	err := p.docsLoad()
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("reload", "marketing", none, none)
+}
+
+func TestDocsLoadUnvetted(t *testing.T) {
+	p, cleanup := newTestSearchPlugin(t)
+	defer cleanup()
+
+	// Save the documents of an unvetted and a vetted record to the
+	// plugin data dir the way that a previous version of the plugin
+	// did.
+	docs := []document{
+		{
+			Token:  "a1",
+			State:  backend.StateUnvetted,
+			Status: backend.StatusUnreviewed,
+		},
+		{
+			Token:  "b2",
+			State:  backend.StateVetted,
+			Status: backend.StatusPublic,
+		},
+	}
+	for _, d := range docs {
+		b, err := json.Marshal(d)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = ioutil.WriteFile(p.docPath(d.Token), b, 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Only the vetted document must be loaded. The unvetted document
+	// must be deleted from the plugin data dir.
+	err := p.docsLoad()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := p.docs["a1"]; ok {
+		t.Fatalf("unvetted document was loaded")
+	}
+	if _, ok := p.docs["b2"]; !ok {
+		t.Fatalf("vetted document was not loaded")
+	}
+	_, err = os.Stat(p.docPath("a1"))
+	if !os.IsNotExist(err) {
+		t.Fatalf("unvetted document was not deleted: %v", err)
+	}
+}
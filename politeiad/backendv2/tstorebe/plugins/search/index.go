@@ -0,0 +1,492 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+)
+
+const (
+	// docsDirname is the name of the directory in the plugin data dir
+	// that contains the cached documents of vetted records. Each
+	// document is saved to its own file, using the record token as the
+	// filename.
+	docsDirname = "docs"
+
+	// nameWeight is the number of times that a term in the record name
+	// is counted. This ranks records that contain a term in their name
+	// above records that only contain the term in their text.
+	nameWeight = 5
+
+	// termLengthMin and termLengthMax are the minimum and maximum
+	// number of characters that a term can contain in order to be
+	// indexed.
+	termLengthMin = 2
+	termLengthMax = 64
+
+	// BM25 ranking parameters. k1 controls the term frequency
+	// saturation. b controls the document length normalization.
+	bm25K1 = 1.2
+	bm25B  = 0.75
+
+	// inventoryPageSize is the number of entries that are requested
+	// per backend inventory query.
+	inventoryPageSize uint32 = 100
+)
+
+var (
+	// stopWords contains the common words that are not indexed.
+	stopWords = map[string]struct{}{
+		"an": {}, "and": {}, "are": {}, "as": {}, "at": {}, "be": {},
+		"by": {}, "for": {}, "from": {}, "has": {}, "in": {}, "is": {},
+		"it": {}, "its": {}, "of": {}, "on": {}, "or": {}, "that": {},
+		"the": {}, "this": {}, "to": {}, "was": {}, "will": {}, "with": {},
+	}
+)
+
+// document is the indexed representation of a record. The documents of
+// vetted records are saved to the plugin data dir so that the index does not
+// need to be rebuilt on startup. The documents of unvetted records are only
+// kept in memory since they contain the plaintext record content, which is
+// encrypted in tstore. They are rebuilt from tstore on startup.
+type document struct {
+	Token     string            `json:"token"`
+	Name      string            `json:"name"`
+	State     backend.StateT    `json:"state"`
+	Status    backend.StatusT   `json:"status"`
+	Version   uint32            `json:"version"`
+	Timestamp int64             `json:"timestamp"` // Record timestamp
+	Length    uint32            `json:"length"`    // Total term count
+	Terms     map[string]uint32 `json:"terms"`     // [term]frequency
+}
+
+// terms splits the provided text into lowercase index terms. Stop words and
+// terms that are too short or too long are not included.
+func terms(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	t := make([]string, 0, len(words))
+	for _, v := range words {
+		l := utf8.RuneCountInString(v)
+		if l < termLengthMin || l > termLengthMax {
+			continue
+		}
+		if _, ok := stopWords[v]; ok {
+			continue
+		}
+		t = append(t, v)
+	}
+	return t
+}
+
+// docNew returns a new document for the provided record.
+func (p *searchPlugin) docNew(rm backend.RecordMetadata, files []backend.File) (*document, error) {
+	d := document{
+		Token:     rm.Token,
+		State:     rm.State,
+		Status:    rm.Status,
+		Version:   rm.Version,
+		Timestamp: rm.Timestamp,
+		Terms:     make(map[string]uint32, 256),
+	}
+	add := func(text string, weight uint32) {
+		for _, v := range terms(text) {
+			d.Terms[v] += weight
+			d.Length += weight
+		}
+	}
+	textFiles := make(map[string]struct{}, len(p.textFiles))
+	for _, v := range p.textFiles {
+		textFiles[v] = struct{}{}
+	}
+	for _, v := range files {
+		_, isText := textFiles[v.Name]
+		if !isText && v.Name != p.nameFile {
+			continue
+		}
+		b, err := base64.StdEncoding.DecodeString(v.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("decode %v: %v", v.Name, err)
+		}
+		if isText {
+			add(string(b), 1)
+		}
+		if v.Name != p.nameFile {
+			continue
+		}
+		var fields map[string]interface{}
+		err = json.Unmarshal(b, &fields)
+		if err != nil {
+			return nil, fmt.Errorf("unmarshal %v: %v", v.Name, err)
+		}
+		if name, ok := fields[p.nameField].(string); ok {
+			d.Name = name
+			add(name, nameWeight)
+		}
+	}
+	return &d, nil
+}
+
+// docPath returns the file path of the cached document for a record.
+func (p *searchPlugin) docPath(token string) string {
+	return filepath.Join(p.dataDir, docsDirname, token+".json")
+}
+
+// docSaveLocked adds a document to the index. The document is saved to the
+// plugin data dir if the record is vetted. The document of an unvetted record
+// is only kept in memory and any previously saved document of the record is
+// deleted from the plugin data dir.
+//
+// This function must be called WITH the lock held.
+func (p *searchPlugin) docSaveLocked(d document) error {
+	if d.State == backend.StateVetted {
+		b, err := json.Marshal(d)
+		if err != nil {
+			return err
+		}
+		err = ioutil.WriteFile(p.docPath(d.Token), b, 0600)
+		if err != nil {
+			return err
+		}
+	} else {
+		err := p.docFileDel(d.Token)
+		if err != nil {
+			return err
+		}
+	}
+	p.docs[d.Token] = &d
+
+	log.Debugf("Search index %v %v %v", d.Token,
+		backend.States[d.State], backend.Statuses[d.Status])
+
+	return nil
+}
+
+// docSave adds a document to the index. See docSaveLocked.
+//
+// This function must be called WITHOUT the lock held.
+func (p *searchPlugin) docSave(d document) error {
+	p.Lock()
+	defer p.Unlock()
+
+	return p.docSaveLocked(d)
+}
+
+// docFileDel deletes the saved document of a record from the plugin data dir.
+// No error is returned if the document has not been saved.
+func (p *searchPlugin) docFileDel(token string) error {
+	err := os.Remove(p.docPath(token))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// docDelLocked deletes the document of a record from the plugin data dir and
+// from the index.
+//
+// This function must be called WITH the lock held.
+func (p *searchPlugin) docDelLocked(token string) error {
+	err := p.docFileDel(token)
+	if err != nil {
+		return err
+	}
+	delete(p.docs, token)
+
+	log.Debugf("Search index del %v", token)
+
+	return nil
+}
+
+// docDel deletes the document of a record from the plugin data dir and from
+// the index.
+//
+// This function must be called WITHOUT the lock held.
+func (p *searchPlugin) docDel(token string) error {
+	p.Lock()
+	defer p.Unlock()
+
+	return p.docDelLocked(token)
+}
+
+// docsLoad loads the cached documents from the plugin data dir. Documents of
+// unvetted records that were saved by a previous version of the plugin are
+// deleted. They are re-indexed in memory by sync.
+func (p *searchPlugin) docsLoad() error {
+	p.Lock()
+	defer p.Unlock()
+
+	files, err := ioutil.ReadDir(filepath.Join(p.dataDir, docsDirname))
+	if err != nil {
+		return err
+	}
+	for _, v := range files {
+		fp := filepath.Join(p.dataDir, docsDirname, v.Name())
+		b, err := ioutil.ReadFile(fp)
+		if err != nil {
+			return err
+		}
+		var d document
+		err = json.Unmarshal(b, &d)
+		if err != nil {
+			return fmt.Errorf("unmarshal %v: %v", fp, err)
+		}
+		if d.State != backend.StateVetted {
+			err = os.Remove(fp)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		p.docs[d.Token] = &d
+	}
+
+	return nil
+}
+
+// docIsStale returns whether the document does not reflect the latest version
+// of the record. A nil document is considered stale.
+//
+// The record timestamp is updated on every record write, including metadata
+// only edits that do not trigger a re-index. These documents are re-indexed
+// once so that their timestamp matches the inventory again.
+func docIsStale(d *document, e backend.InventoryEntry) bool {
+	return d == nil || d.State != e.State || d.Status != e.Status ||
+		d.Timestamp != e.Updated
+}
+
+// sync compares the index against the backend inventory. Records that are
+// missing from the index or that have been updated since they were indexed
+// are re-indexed. Documents of records that have been censored or that no
+// longer exist are deleted. The number of documents that were updated is
+// returned.
+func (p *searchPlugin) sync() (int, error) {
+	var (
+		q = backend.InventoryQuery{
+			Sort:  backend.InventorySortCreated,
+			Limit: inventoryPageSize,
+		}
+		found   = make(map[string]struct{}, 1024)
+		updated int
+	)
+	for {
+		page, err := p.backend.InventoryQuery(q)
+		if err != nil {
+			return 0, fmt.Errorf("InventoryQuery: %v", err)
+		}
+		for _, e := range page.Entries {
+			found[e.Token] = struct{}{}
+
+			p.RLock()
+			d := p.docs[e.Token]
+			p.RUnlock()
+
+			switch {
+			case e.Status == backend.StatusCensored:
+				// Censored records are not indexed
+				if d == nil {
+					continue
+				}
+				err = p.docDel(e.Token)
+			case docIsStale(d, e):
+				err = p.index(e.Token)
+			default:
+				// Document is up to date
+				continue
+			}
+			if err != nil {
+				return 0, err
+			}
+			updated++
+		}
+		if page.Cursor == "" {
+			break
+		}
+		q.Cursor = page.Cursor
+	}
+
+	// Delete the documents of records that no longer exist
+	p.Lock()
+	defer p.Unlock()
+
+	for token := range p.docs {
+		if _, ok := found[token]; ok {
+			continue
+		}
+		t, err := hex.DecodeString(token)
+		if err == nil && p.backend.RecordExists(t) {
+			// The record was created after the inventory was read
+			continue
+		}
+		err = p.docDelLocked(token)
+		if err != nil {
+			return 0, err
+		}
+		updated++
+	}
+
+	return updated, nil
+}
+
+// index indexes the latest version of a record.
+func (p *searchPlugin) index(token string) error {
+	t, err := hex.DecodeString(token)
+	if err != nil {
+		return err
+	}
+	filenames := append([]string{p.nameFile}, p.textFiles...)
+	r, err := p.tstore.RecordPartial(t, 0, filenames, false)
+	if err != nil {
+		return fmt.Errorf("RecordPartial %v: %v", token, err)
+	}
+	d, err := p.docNew(r.RecordMetadata, r.Files)
+	if err != nil {
+		return err
+	}
+	return p.docSave(*d)
+}
+
+// rebuild deletes all cached documents and re-indexes the provided records.
+// Censored records are not indexed.
+func (p *searchPlugin) rebuild(tokens [][]byte, progress func()) error {
+	// Delete the cached documents
+	p.Lock()
+	err := os.RemoveAll(filepath.Join(p.dataDir, docsDirname))
+	if err == nil {
+		err = os.MkdirAll(filepath.Join(p.dataDir, docsDirname), 0700)
+	}
+	p.docs = make(map[string]*document, len(tokens))
+	p.Unlock()
+	if err != nil {
+		return err
+	}
+
+	// Index the records
+	filenames := append([]string{p.nameFile}, p.textFiles...)
+	for _, token := range tokens {
+		r, err := p.tstore.RecordPartial(token, 0, filenames, false)
+		if err != nil {
+			return fmt.Errorf("RecordPartial %x: %v", token, err)
+		}
+		if r.RecordMetadata.Status != backend.StatusCensored {
+			d, err := p.docNew(r.RecordMetadata, r.Files)
+			if err != nil {
+				return err
+			}
+			err = p.docSave(*d)
+			if err != nil {
+				return err
+			}
+		}
+		progress()
+	}
+
+	return nil
+}
+
+// result is a document that matched a search query.
+type result struct {
+	doc   *document
+	score float64
+}
+
+// rank returns the documents in the provided state that contain any of the
+// provided terms, sorted by their BM25 score from highest to lowest. Censored
+// records are never returned.
+func (p *searchPlugin) rank(state backend.StateT, queryTerms []string) []result {
+	p.RLock()
+	defer p.RUnlock()
+
+	// Compile the documents that can be searched. The corpus
+	// statistics are only calculated using these documents so that
+	// the scores do not leak information about other documents.
+	var (
+		docs   = make([]*document, 0, len(p.docs))
+		length uint64
+	)
+	for _, v := range p.docs {
+		if v.State != state || v.Status == backend.StatusCensored {
+			continue
+		}
+		docs = append(docs, v)
+		length += uint64(v.Length)
+	}
+	if len(docs) == 0 {
+		return []result{}
+	}
+	var (
+		n     = float64(len(docs))
+		avgdl = float64(length) / n
+	)
+	if avgdl == 0 {
+		avgdl = 1
+	}
+
+	// Calculate the inverse document frequency of each term
+	idf := make(map[string]float64, len(queryTerms))
+	for _, t := range queryTerms {
+		var count float64
+		for _, d := range docs {
+			if _, ok := d.Terms[t]; ok {
+				count++
+			}
+		}
+		idf[t] = math.Log(1 + (n-count+0.5)/(count+0.5))
+	}
+
+	// Score the documents
+	results := make([]result, 0, len(docs))
+	for _, d := range docs {
+		var score float64
+		for _, t := range queryTerms {
+			tf, ok := d.Terms[t]
+			if !ok {
+				continue
+			}
+			f := float64(tf)
+			norm := bm25K1 * (1 - bm25B + bm25B*float64(d.Length)/avgdl)
+			score += idf[t] * f * (bm25K1 + 1) / (f + norm)
+		}
+		if score == 0 {
+			continue
+		}
+		results = append(results, result{
+			doc:   d,
+			score: score,
+		})
+	}
+
+	// Sort by score. Ties are sorted by the record timestamp from
+	// newest to oldest, then by token so that the order is stable
+	// across pages.
+	sort.Slice(results, func(i, j int) bool {
+		ri, rj := results[i], results[j]
+		switch {
+		case ri.score != rj.score:
+			return ri.score > rj.score
+		case ri.doc.Timestamp != rj.doc.Timestamp:
+			return ri.doc.Timestamp > rj.doc.Timestamp
+		default:
+			return ri.doc.Token < rj.doc.Token
+		}
+	})
+
+	return results
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/search"
+)
+
+func TestTerms(t *testing.T) {
+	got := terms("The *Decred* treasury: a 2021 proposal, for the DAO!")
+	want := []string{"decred", "treasury", "2021", "proposal", "dao"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestRank(t *testing.T) {
+	p := searchPlugin{
+		docs:      make(map[string]*document),
+		textFiles: search.SettingTextFiles,
+		nameFile:  "proposalmetadata.json",
+		nameField: "name",
+	}
+	file := func(name, payload string) backend.File {
+		return backend.File{
+			Name:    name,
+			Payload: base64.StdEncoding.EncodeToString([]byte(payload)),
+		}
+	}
+	records := []struct {
+		rm    backend.RecordMetadata
+		name  string
+		index string
+	}{
+		{
+			backend.RecordMetadata{Token: "a", State: backend.StateVetted,
+				Status: backend.StatusPublic, Timestamp: 1},
+			"Marketing",
+			"A marketing proposal that mentions the treasury once.",
+		},
+		{
+			backend.RecordMetadata{Token: "b", State: backend.StateVetted,
+				Status: backend.StatusPublic, Timestamp: 2},
+			"Treasury report",
+			"A report on the treasury spending.",
+		},
+		{
+			backend.RecordMetadata{Token: "c", State: backend.StateVetted,
+				Status: backend.StatusPublic, Timestamp: 3},
+			"Development",
+			"Development work unrelated to the query.",
+		},
+		{
+			backend.RecordMetadata{Token: "d", State: backend.StateUnvetted,
+				Status: backend.StatusUnreviewed, Timestamp: 4},
+			"Treasury",
+			"An unvetted treasury proposal.",
+		},
+	}
+	for _, v := range records {
+		d, err := p.docNew(v.rm, []backend.File{
+			file("proposalmetadata.json", `{"name":"`+v.name+`"}`),
+			file("index.md", v.index),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		p.docs[d.Token] = d
+	}
+
+	// The record that contains the term in its name must be ranked
+	// first. Unvetted records must not be returned.
+	results := p.rank(backend.StateVetted, []string{"treasury"})
+	got := make([]string, 0, len(results))
+	for _, v := range results {
+		got = append(got, v.doc.Token)
+	}
+	want := []string{"b", "a"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("vetted: got %v, want %v", got, want)
+	}
+	if results[0].doc.Name != "Treasury report" {
+		t.Errorf("got name %v, want Treasury report", results[0].doc.Name)
+	}
+
+	// Only unvetted records are returned for unvetted searches
+	results = p.rank(backend.StateUnvetted, []string{"treasury"})
+	if len(results) != 1 || results[0].doc.Token != "d" {
+		t.Errorf("unvetted: got %v results, want d", len(results))
+	}
+}
@@ -0,0 +1,297 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package search
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
+	"github.com/decred/politeia/politeiad/plugins/search"
+)
+
+var (
+	_ plugins.PluginClient = (*searchPlugin)(nil)
+)
+
+// searchPlugin is the tstore backend implementation of the search plugin. The
+// search plugin indexes the text content of records and allows the records to
+// be searched by keyword.
+//
+// searchPlugin satisfies the plugins PluginClient interface.
+type searchPlugin struct {
+	sync.RWMutex
+	backend backend.Backend
+	tstore  plugins.TstoreClient
+
+	// dataDir is the search plugin data directory. The only data that
+	// is stored here is cached data that can be re-created at any time
+	// by walking the trillian trees.
+	dataDir string
+
+	// docs contains the indexed documents of all records. It is loaded
+	// from the data dir on startup. The docs map is protected by the
+	// plugin mutex.
+	docs map[string]*document // [token]document
+
+	// Plugin settings. The page size can be updated at runtime and is
+	// protected by the plugin mutex.
+	textFiles []string
+	nameFile  string
+	nameField string
+	pageSize  uint32
+}
+
+// pageSizeGet returns the page size plugin setting.
+func (p *searchPlugin) pageSizeGet() uint32 {
+	p.RLock()
+	defer p.RUnlock()
+
+	return p.pageSize
+}
+
+// Setup performs any plugin setup that is required.
+//
+// This function satisfies the plugins PluginClient interface.
+func (p *searchPlugin) Setup() error {
+	log.Tracef("search Setup")
+
+	// Load the cached documents
+	err := p.docsLoad()
+	if err != nil {
+		return err
+	}
+
+	// Index any records that are missing from the cache or that have
+	// been updated since they were indexed.
+	log.Infof("Updating search index")
+
+	n, err := p.sync()
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Search index: %v records, %v updated", len(p.docs), n)
+
+	return nil
+}
+
+// Cmd executes a plugin command.
+//
+// This function satisfies the plugins PluginClient interface.
+func (p *searchPlugin) Cmd(token []byte, cmd, payload string) (string, error) {
+	log.Tracef("search Cmd: %x %v %v", token, cmd, payload)
+
+	switch cmd {
+	case search.CmdSearch:
+		return p.cmdSearch(payload)
+	}
+
+	return "", backend.ErrPluginCmdInvalid
+}
+
+// Hook executes a plugin hook.
+//
+// This function satisfies the plugins PluginClient interface.
+func (p *searchPlugin) Hook(h plugins.HookT, payload string) error {
+	log.Tracef("search Hook: %v", plugins.Hooks[h])
+
+	switch h {
+	case plugins.HookTypeNewRecordPost:
+		return p.hookNewRecordPost(payload)
+	case plugins.HookTypeEditRecordPost:
+		return p.hookEditRecordPost(payload)
+	case plugins.HookTypeSetRecordStatusPost:
+		return p.hookSetRecordStatusPost(payload)
+	case plugins.HookTypeRecordImportPost:
+		return p.hookRecordImportPost(payload)
+	}
+
+	return nil
+}
+
+// Fsck performs a plugin filesystem check. The search index is verified
+// against the backend inventory and any stale documents are re-indexed.
+//
+// This function satisfies the plugins PluginClient interface.
+func (p *searchPlugin) Fsck() error {
+	log.Tracef("search Fsck")
+
+	n, err := p.sync()
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		log.Infof("Search index: %v records updated", n)
+	}
+
+	return nil
+}
+
+// CacheRebuild deletes the search index and rebuilds it from the latest
+// version of all records.
+//
+// This function satisfies the plugins PluginClient interface.
+func (p *searchPlugin) CacheRebuild(tokens [][]byte, progress func()) error {
+	log.Tracef("search CacheRebuild: %v records", len(tokens))
+
+	err := p.rebuild(tokens, progress)
+	if err != nil {
+		return err
+	}
+
+	p.RLock()
+	n := len(p.docs)
+	p.RUnlock()
+
+	log.Infof("Search index rebuilt: %v records", n)
+
+	return nil
+}
+
+// Settings returns the plugin's settings.
+//
+// This function satisfies the plugins PluginClient interface.
+func (p *searchPlugin) Settings() []backend.PluginSetting {
+	log.Tracef("search Settings")
+
+	// The text files are returned JSON encoded. The setting was
+	// verified on startup so the encoding will not fail.
+	b, _ := json.Marshal(p.textFiles)
+
+	return []backend.PluginSetting{
+		{
+			Key:   search.SettingKeyTextFiles,
+			Value: string(b),
+		},
+		{
+			Key:   search.SettingKeyNameField,
+			Value: p.nameFile + ":" + p.nameField,
+		},
+		{
+			Key:   search.SettingKeyPageSize,
+			Value: strconv.FormatUint(uint64(p.pageSizeGet()), 10),
+		},
+	}
+}
+
+// SettingsUpdate updates the plugin settings at runtime. Only the page size
+// can be updated. The indexed files cannot be changed at runtime since the
+// index would need to be rebuilt.
+//
+// This function satisfies the plugins PluginClient interface.
+func (p *searchPlugin) SettingsUpdate(settings []backend.PluginSetting) error {
+	log.Tracef("search SettingsUpdate: %v", settings)
+
+	var pageSize uint32
+	for _, v := range settings {
+		switch v.Key {
+		case search.SettingKeyPageSize:
+			u, err := strconv.ParseUint(v.Value, 10, 32)
+			if err != nil {
+				return settingError(v, err.Error())
+			}
+			if u == 0 {
+				return settingError(v, "must be greater than zero")
+			}
+			pageSize = uint32(u)
+		case search.SettingKeyTextFiles, search.SettingKeyNameField:
+			return settingError(v, "setting cannot be updated at runtime")
+		default:
+			return settingError(v, "unknown setting")
+		}
+	}
+	if pageSize == 0 {
+		// Nothing to update
+		return nil
+	}
+
+	p.Lock()
+	p.pageSize = pageSize
+	p.Unlock()
+
+	return nil
+}
+
+// settingError returns a backend PluginSettingError for the provided setting.
+func settingError(s backend.PluginSetting, reason string) error {
+	return backend.PluginSettingError{
+		PluginID: search.PluginID,
+		Key:      s.Key,
+		Value:    s.Value,
+		Reason:   reason,
+	}
+}
+
+// New returns a new searchPlugin.
+func New(backend backend.Backend, tstore plugins.TstoreClient, settings []backend.PluginSetting, dataDir string) (*searchPlugin, error) {
+	// Create plugin data directory
+	dataDir = filepath.Join(dataDir, search.PluginID)
+	err := os.MkdirAll(filepath.Join(dataDir, docsDirname), 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	// Setup plugin setting default values
+	var (
+		textFiles = search.SettingTextFiles
+		nameField = search.SettingNameField
+		pageSize  = search.SettingPageSize
+	)
+
+	// Override defaults with any passed in settings
+	for _, v := range settings {
+		switch v.Key {
+		case search.SettingKeyTextFiles:
+			var tf []string
+			err := json.Unmarshal([]byte(v.Value), &tf)
+			if err != nil {
+				return nil, fmt.Errorf("invalid plugin setting %v '%v': %v",
+					v.Key, v.Value, err)
+			}
+			textFiles = tf
+		case search.SettingKeyNameField:
+			nameField = v.Value
+		case search.SettingKeyPageSize:
+			u, err := strconv.ParseUint(v.Value, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid plugin setting %v '%v': %v",
+					v.Key, v.Value, err)
+			}
+			pageSize = uint32(u)
+		default:
+			return nil, fmt.Errorf("invalid plugin setting: %v", v.Key)
+		}
+	}
+
+	// Parse the name field setting
+	s := strings.SplitN(nameField, ":", 2)
+	if len(s) != 2 || s[0] == "" || s[1] == "" {
+		return nil, fmt.Errorf("invalid plugin setting %v '%v': must be "+
+			"formatted as <filename>:<jsonfield>", search.SettingKeyNameField,
+			nameField)
+	}
+	if pageSize == 0 {
+		return nil, fmt.Errorf("invalid plugin setting %v '%v': must be "+
+			"greater than zero", search.SettingKeyPageSize, pageSize)
+	}
+
+	return &searchPlugin{
+		backend:   backend,
+		tstore:    tstore,
+		dataDir:   dataDir,
+		docs:      make(map[string]*document),
+		textFiles: textFiles,
+		nameFile:  s[0],
+		nameField: s[1],
+		pageSize:  pageSize,
+	}, nil
+}
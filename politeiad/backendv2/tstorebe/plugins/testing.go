@@ -0,0 +1,384 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package plugins
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"sync"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+)
+
+var (
+	// Verify that TestTstore satisfies the TstoreClient interface.
+	_ TstoreClient = (*TestTstore)(nil)
+)
+
+// testBlob is a blob that has been saved to a TestTstore record.
+type testBlob struct {
+	entry      store.BlobEntry
+	descriptor string
+	state      backend.StateT // Record state when the blob was saved
+}
+
+// testRecord is a record that has been saved to a TestTstore.
+type testRecord struct {
+	record backend.Record
+	blobs  []testBlob // Ordered from oldest to newest
+}
+
+// TestTstore is an in memory TstoreClient that is used to test plugins
+// without a tstore instance. Records are added using RecordSave. Only the
+// most recent version of a record is kept. The same as tstore, only the
+// vetted blobs of a vetted record are returned.
+type TestTstore struct {
+	sync.Mutex
+	records map[string]*testRecord       // [token]record
+	attrs   map[string]map[string]string // [token][pluginID.key]value
+	events  []backend.Event
+}
+
+// NewTestTstore returns a new TestTstore.
+func NewTestTstore() *TestTstore {
+	return &TestTstore{
+		records: make(map[string]*testRecord),
+		attrs:   make(map[string]map[string]string),
+	}
+}
+
+// RecordSave saves a record to the TestTstore. An existing record with the
+// same token is replaced. The plugin blobs of the record are kept.
+func (t *TestTstore) RecordSave(r backend.Record) {
+	t.Lock()
+	defer t.Unlock()
+
+	tr, ok := t.records[r.RecordMetadata.Token]
+	if !ok {
+		tr = &testRecord{}
+		t.records[r.RecordMetadata.Token] = tr
+	}
+	tr.record = r
+}
+
+// InventoryAttributes returns the inventory attributes of a record.
+func (t *TestTstore) InventoryAttributes(token []byte) map[string]string {
+	t.Lock()
+	defer t.Unlock()
+
+	attrs := make(map[string]string, len(t.attrs[hex.EncodeToString(token)]))
+	for k, v := range t.attrs[hex.EncodeToString(token)] {
+		attrs[k] = v
+	}
+	return attrs
+}
+
+// Events returns the plugin events that have been published.
+func (t *TestTstore) Events() []backend.Event {
+	t.Lock()
+	defer t.Unlock()
+
+	events := make([]backend.Event, len(t.events))
+	copy(events, t.events)
+	return events
+}
+
+// record returns the record for the provided token.
+//
+// This function must be called WITH the lock held.
+func (t *TestTstore) record(token []byte) (*testRecord, error) {
+	tr, ok := t.records[hex.EncodeToString(token)]
+	if !ok {
+		return nil, backend.ErrRecordNotFound
+	}
+	return tr, nil
+}
+
+// blobs returns the blobs of the record that match the provided function.
+// Only the vetted blobs are returned if the record is vetted.
+//
+// This function must be called WITH the lock held.
+func (t *TestTstore) blobs(token []byte, match func(testBlob) bool) ([]store.BlobEntry, error) {
+	tr, err := t.record(token)
+	if err != nil {
+		return nil, err
+	}
+	vetted := tr.record.RecordMetadata.State == backend.StateVetted
+	entries := make([]store.BlobEntry, 0, len(tr.blobs))
+	for _, v := range tr.blobs {
+		if vetted && v.state != backend.StateVetted {
+			continue
+		}
+		if match(v) {
+			entries = append(entries, v.entry)
+		}
+	}
+	return entries, nil
+}
+
+// BlobSave saves a BlobEntry to a record.
+//
+// This function satisfies the TstoreClient interface.
+func (t *TestTstore) BlobSave(token []byte, be store.BlobEntry) error {
+	t.Lock()
+	defer t.Unlock()
+
+	tr, err := t.record(token)
+	if err != nil {
+		return err
+	}
+	b, err := base64.StdEncoding.DecodeString(be.DataHint)
+	if err != nil {
+		return err
+	}
+	var dd store.DataDescriptor
+	err = json.Unmarshal(b, &dd)
+	if err != nil {
+		return err
+	}
+	for _, v := range tr.blobs {
+		if v.entry.Digest == be.Digest {
+			return ErrDuplicateBlob
+		}
+	}
+	tr.blobs = append(tr.blobs, testBlob{
+		entry:      be,
+		descriptor: dd.Descriptor,
+		state:      tr.record.RecordMetadata.State,
+	})
+
+	return nil
+}
+
+// BlobsDel deletes the blobs that correspond to the provided digests.
+//
+// This function satisfies the TstoreClient interface.
+func (t *TestTstore) BlobsDel(token []byte, digests [][]byte) error {
+	t.Lock()
+	defer t.Unlock()
+
+	tr, err := t.record(token)
+	if err != nil {
+		return err
+	}
+	del := make(map[string]struct{}, len(digests))
+	for _, v := range digests {
+		del[hex.EncodeToString(v)] = struct{}{}
+	}
+	blobs := make([]testBlob, 0, len(tr.blobs))
+	for _, v := range tr.blobs {
+		if _, ok := del[v.entry.Digest]; ok {
+			continue
+		}
+		blobs = append(blobs, v)
+	}
+	tr.blobs = blobs
+
+	return nil
+}
+
+// Blobs returns the blobs that correspond to the provided digests.
+//
+// This function satisfies the TstoreClient interface.
+func (t *TestTstore) Blobs(token []byte, digests [][]byte) (map[string]store.BlobEntry, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	want := make(map[string]struct{}, len(digests))
+	for _, v := range digests {
+		want[hex.EncodeToString(v)] = struct{}{}
+	}
+	entries, err := t.blobs(token, func(b testBlob) bool {
+		_, ok := want[b.entry.Digest]
+		return ok
+	})
+	if err != nil {
+		return nil, err
+	}
+	blobs := make(map[string]store.BlobEntry, len(entries))
+	for _, v := range entries {
+		blobs[v.Digest] = v
+	}
+	return blobs, nil
+}
+
+// BlobsByDataDesc returns all blobs that match the provided data descriptors.
+//
+// This function satisfies the TstoreClient interface.
+func (t *TestTstore) BlobsByDataDesc(token []byte, dataDesc []string) ([]store.BlobEntry, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	return t.blobs(token, func(b testBlob) bool {
+		for _, v := range dataDesc {
+			if b.descriptor == v {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// DigestsByDataDesc returns the digests of all blobs that match the provided
+// data descriptors.
+//
+// This function satisfies the TstoreClient interface.
+func (t *TestTstore) DigestsByDataDesc(token []byte, dataDesc []string) ([][]byte, error) {
+	entries, err := t.BlobsByDataDesc(token, dataDesc)
+	if err != nil {
+		return nil, err
+	}
+	digests := make([][]byte, 0, len(entries))
+	for _, v := range entries {
+		d, err := hex.DecodeString(v.Digest)
+		if err != nil {
+			return nil, err
+		}
+		digests = append(digests, d)
+	}
+	return digests, nil
+}
+
+// Timestamp returns the timestamp for the blob that correpsonds to the
+// digest. The TestTstore does not anchor blobs so the timestamp does not
+// contain any proofs.
+//
+// This function satisfies the TstoreClient interface.
+func (t *TestTstore) Timestamp(token []byte, digest []byte) (*backend.Timestamp, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	d := hex.EncodeToString(digest)
+	entries, err := t.blobs(token, func(b testBlob) bool {
+		return b.entry.Digest == d
+	})
+	if err != nil {
+		return nil, err
+	}
+	ts := backend.Timestamp{
+		Digest: d,
+		Proofs: []backend.Proof{},
+	}
+	if len(entries) > 0 {
+		ts.Data = entries[0].Data
+	}
+	return &ts, nil
+}
+
+// Record returns a version of a record.
+//
+// This function satisfies the TstoreClient interface.
+func (t *TestTstore) Record(token []byte, version uint32) (*backend.Record, error) {
+	return t.RecordPartial(token, version, nil, false)
+}
+
+// RecordLatest returns the most recent version of a record.
+//
+// This function satisfies the TstoreClient interface.
+func (t *TestTstore) RecordLatest(token []byte) (*backend.Record, error) {
+	return t.RecordPartial(token, 0, nil, false)
+}
+
+// RecordPartial returns a partial record.
+//
+// This function satisfies the TstoreClient interface.
+func (t *TestTstore) RecordPartial(token []byte, version uint32, filenames []string, omitAllFiles bool) (*backend.Record, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	tr, err := t.record(token)
+	if err != nil {
+		return nil, err
+	}
+	r := tr.record
+	if version != 0 && version != r.RecordMetadata.Version {
+		return nil, backend.ErrRecordNotFound
+	}
+	r.Metadata = append([]backend.MetadataStream{}, r.Metadata...)
+	files := make([]backend.File, 0, len(r.Files))
+	for _, v := range r.Files {
+		switch {
+		case omitAllFiles:
+			continue
+		case len(filenames) > 0:
+			for _, fn := range filenames {
+				if v.Name == fn {
+					files = append(files, v)
+				}
+			}
+		default:
+			files = append(files, v)
+		}
+	}
+	r.Files = files
+
+	return &r, nil
+}
+
+// RecordState returns whether the record is unvetted or vetted.
+//
+// This function satisfies the TstoreClient interface.
+func (t *TestTstore) RecordState(token []byte) (backend.StateT, error) {
+	t.Lock()
+	defer t.Unlock()
+
+	tr, err := t.record(token)
+	if err != nil {
+		return backend.StateInvalid, err
+	}
+	return tr.record.RecordMetadata.State, nil
+}
+
+// InventoryAttributesSet sets inventory attributes for a record. An empty
+// attribute value deletes the attribute.
+//
+// This function satisfies the TstoreClient interface.
+func (t *TestTstore) InventoryAttributesSet(token []byte, pluginID string, attrs map[string]string) error {
+	t.Lock()
+	defer t.Unlock()
+
+	if _, err := t.record(token); err != nil {
+		return err
+	}
+	a, ok := t.attrs[hex.EncodeToString(token)]
+	if !ok {
+		a = make(map[string]string, len(attrs))
+		t.attrs[hex.EncodeToString(token)] = a
+	}
+	for k, v := range attrs {
+		key := pluginID + "." + k
+		if v == "" {
+			delete(a, key)
+			continue
+		}
+		a[key] = v
+	}
+
+	return nil
+}
+
+// PluginEventPublish records a plugin event.
+//
+// This function satisfies the TstoreClient interface.
+func (t *TestTstore) PluginEventPublish(token []byte, pluginID, name, payload string) error {
+	t.Lock()
+	defer t.Unlock()
+
+	var tokenStr string
+	if len(token) > 0 {
+		tokenStr = hex.EncodeToString(token)
+	}
+	t.events = append(t.events, backend.Event{
+		Type:     backend.EventTypePlugin,
+		Token:    tokenStr,
+		PluginID: pluginID,
+		Name:     name,
+		Payload:  payload,
+	})
+
+	return nil
+}
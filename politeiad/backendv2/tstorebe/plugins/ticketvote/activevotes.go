@@ -39,6 +39,11 @@ type activeVote struct {
 	Details   *ticketvote.VoteDetails
 	CastVotes map[string]string // [ticket]voteBit
 
+	// Commitments contains the commitment of each ticket that has cast
+	// a secret ballot vote. The vote bit of a secret ballot vote is an
+	// empty string in the CastVotes map until it has been revealed.
+	Commitments map[string]string // [ticket]commitment
+
 	// Addrs contains the largest commitment address for each eligble
 	// ticket. The vote must be signed with the key from this address.
 	//
@@ -88,6 +93,7 @@ func (a *activeVotes) VoteDetails(token []byte) *ticketvote.VoteDetails {
 			PassPercentage:   av.Details.Params.PassPercentage,
 			Options:          options,
 			Parent:           av.Details.Params.Parent,
+			RevealDuration:   av.Details.Params.RevealDuration,
 		},
 		PublicKey:        av.Details.PublicKey,
 		Signature:        av.Details.Signature,
@@ -95,6 +101,8 @@ func (a *activeVotes) VoteDetails(token []byte) *ticketvote.VoteDetails {
 		StartBlockHash:   av.Details.StartBlockHash,
 		EndBlockHeight:   av.Details.EndBlockHeight,
 		EligibleTickets:  eligible,
+
+		RevealEndBlockHeight: av.Details.RevealEndBlockHeight,
 	}
 }
 
@@ -208,6 +216,37 @@ func (a *activeVotes) AddCastVote(token, ticket, votebit string) {
 	av.CastVotes[ticket] = votebit
 }
 
+// AddCommitment adds the commitment of a secret ballot vote to the active
+// votes cache.
+func (a *activeVotes) AddCommitment(token, ticket, commitment string) {
+	a.Lock()
+	defer a.Unlock()
+
+	av, ok := a.activeVotes[token]
+	if !ok {
+		// Vote does not exist. See AddCastVote for more details.
+		log.Warnf("AddCommitment: vote not found %v", token)
+		return
+	}
+
+	av.Commitments[ticket] = commitment
+}
+
+// Commitment returns the commitment of a secret ballot vote and whether the
+// vote bit has been revealed. An empty commitment is returned if the ticket
+// has not cast a commitment or if the token does not correspond to a record
+// in the active votes cache.
+func (a *activeVotes) Commitment(token, ticket string) (string, bool) {
+	a.RLock()
+	defer a.RUnlock()
+
+	av, ok := a.activeVotes[token]
+	if !ok {
+		return "", false
+	}
+	return av.Commitments[ticket], av.CastVotes[ticket] != ""
+}
+
 // AddCommitmentAddrs adds commitment addresses to the cache for a record.
 func (a *activeVotes) AddCommitmentAddrs(token string, addrs map[string]commitmentAddr) {
 	a.Lock()
@@ -248,11 +287,18 @@ func (a *activeVotes) Del(token string) {
 func (a *activeVotes) Add(vd ticketvote.VoteDetails) {
 	token := vd.Params.Token
 
+	// Only secret ballot votes have commitments
+	var commitmentsSize int
+	if vd.Params.RevealDuration != 0 {
+		commitmentsSize = 40960 // Ticket pool size
+	}
+
 	a.Lock()
 	a.activeVotes[token] = activeVote{
-		Details:   &vd,
-		CastVotes: make(map[string]string, 40960), // Ticket pool size
-		Addrs:     make(map[string]string, 40960), // Ticket pool size
+		Details:     &vd,
+		CastVotes:   make(map[string]string, 40960), // Ticket pool size
+		Commitments: make(map[string]string, commitmentsSize),
+		Addrs:       make(map[string]string, 40960), // Ticket pool size
 	}
 	a.Unlock()
 
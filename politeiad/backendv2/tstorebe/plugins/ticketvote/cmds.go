@@ -6,6 +6,7 @@ package ticketvote
 
 import (
 	"bytes"
+	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
@@ -34,7 +35,9 @@ const (
 	dataDescriptorVoteDetails     = pluginID + "-vote-v1"
 	dataDescriptorCastVoteDetails = pluginID + "-castvote-v1"
 	dataDescriptorVoteCollider    = pluginID + "-vcollider-v1"
+	dataDescriptorVoteReveal      = pluginID + "-vreveal-v1"
 	dataDescriptorStartRunoff     = pluginID + "-startrunoff-v1"
+	dataDescriptorCancelDetails   = pluginID + "-cancel-v1"
 )
 
 // cmdAuthorize authorizes a ticket vote or revokes a previous authorization.
@@ -214,6 +217,8 @@ func voteParamsVerify(vote ticketvote.VoteParams, voteDurationMin, voteDurationM
 		// This is allowed
 	case ticketvote.VoteTypeRunoff:
 		// This is allowed
+	case ticketvote.VoteTypeMultipleChoice:
+		// This is allowed
 	default:
 		return backend.PluginError{
 			PluginID:  ticketvote.PluginID,
@@ -253,6 +258,33 @@ func voteParamsVerify(vote ticketvote.VoteParams, voteDurationMin, voteDurationM
 		}
 	}
 
+	// Verify the reveal duration of a secret ballot vote. The reveal
+	// window is subject to the same limits as the voting period.
+	switch {
+	case vote.RevealDuration == 0:
+		// Not a secret ballot vote
+	case vote.Type == ticketvote.VoteTypeRunoff:
+		return backend.PluginError{
+			PluginID:     ticketvote.PluginID,
+			ErrorCode:    uint32(ticketvote.ErrorCodeVoteDurationInvalid),
+			ErrorContext: "runoff votes cannot be secret ballot votes",
+		}
+	case vote.RevealDuration > voteDurationMax:
+		return backend.PluginError{
+			PluginID:  ticketvote.PluginID,
+			ErrorCode: uint32(ticketvote.ErrorCodeVoteDurationInvalid),
+			ErrorContext: fmt.Sprintf("reveal duration %v exceeds max "+
+				"duration %v", vote.RevealDuration, voteDurationMax),
+		}
+	case vote.RevealDuration < voteDurationMin:
+		return backend.PluginError{
+			PluginID:  ticketvote.PluginID,
+			ErrorCode: uint32(ticketvote.ErrorCodeVoteDurationInvalid),
+			ErrorContext: fmt.Sprintf("reveal duration %v under min "+
+				"duration %v", vote.RevealDuration, voteDurationMin),
+		}
+	}
+
 	// Verify vote options. Different vote types have different
 	// requirements.
 	if len(vote.Options) == 0 {
@@ -305,6 +337,52 @@ func voteParamsVerify(vote ticketvote.VoteParams, voteDurationMin, voteDurationM
 					strings.Join(missing, ",")),
 			}
 		}
+
+	case ticketvote.VoteTypeMultipleChoice:
+		// Multiple choice votes allow for custom vote options. Ensure
+		// that the number of options is within the allowed range and
+		// that the option IDs and bits are unique.
+		if len(vote.Options) < 2 ||
+			len(vote.Options) > ticketvote.VoteOptionsMax {
+			return backend.PluginError{
+				PluginID:  ticketvote.PluginID,
+				ErrorCode: uint32(ticketvote.ErrorCodeVoteOptionsInvalid),
+				ErrorContext: fmt.Sprintf("vote options "+
+					"count got %v, want 2 to %v",
+					len(vote.Options), ticketvote.VoteOptionsMax),
+			}
+		}
+		var (
+			ids  = make(map[string]struct{}, len(vote.Options))
+			bits = make(map[uint64]struct{}, len(vote.Options))
+		)
+		for _, v := range vote.Options {
+			if v.ID == "" {
+				return backend.PluginError{
+					PluginID:     ticketvote.PluginID,
+					ErrorCode:    uint32(ticketvote.ErrorCodeVoteOptionsInvalid),
+					ErrorContext: "vote option ID is empty",
+				}
+			}
+			if _, ok := ids[v.ID]; ok {
+				return backend.PluginError{
+					PluginID:  ticketvote.PluginID,
+					ErrorCode: uint32(ticketvote.ErrorCodeVoteOptionsInvalid),
+					ErrorContext: fmt.Sprintf("duplicate vote "+
+						"option ID %v", v.ID),
+				}
+			}
+			if _, ok := bits[v.Bit]; ok {
+				return backend.PluginError{
+					PluginID:  ticketvote.PluginID,
+					ErrorCode: uint32(ticketvote.ErrorCodeVoteBitsInvalid),
+					ErrorContext: fmt.Sprintf("duplicate vote "+
+						"option bit 0x%x", v.Bit),
+				}
+			}
+			ids[v.ID] = struct{}{}
+			bits[v.Bit] = struct{}{}
+		}
 	}
 
 	// Verify vote bits are somewhat sane
@@ -328,6 +406,13 @@ func voteParamsVerify(vote ticketvote.VoteParams, voteDurationMin, voteDurationM
 			ErrorContext: "parent token should not be provided " +
 				"for a standard vote",
 		}
+	case vote.Type == ticketvote.VoteTypeMultipleChoice && vote.Parent != "":
+		return backend.PluginError{
+			PluginID:  ticketvote.PluginID,
+			ErrorCode: uint32(ticketvote.ErrorCodeVoteParentInvalid),
+			ErrorContext: "parent token should not be provided " +
+				"for a multiple choice vote",
+		}
 	case vote.Type == ticketvote.VoteTypeRunoff:
 		_, err := tokenDecode(vote.Parent)
 		if err != nil {
@@ -428,7 +513,9 @@ func (p *ticketVotePlugin) voteChainParams(duration uint32) (*voteChainParams, e
 	}, nil
 }
 
-// startStandard starts a standard vote.
+// startStandard starts a standard vote. Multiple choice votes are started
+// using this function as well since they are also single record votes that
+// require an authorization.
 func (p *ticketVotePlugin) startStandard(token []byte, s ticketvote.Start) (*ticketvote.StartReply, error) {
 	// Verify there is only one start details
 	if len(s.Starts) != 1 {
@@ -436,7 +523,7 @@ func (p *ticketVotePlugin) startStandard(token []byte, s ticketvote.Start) (*tic
 			PluginID:  ticketvote.PluginID,
 			ErrorCode: uint32(ticketvote.ErrorCodeStartDetailsInvalid),
 			ErrorContext: "more than one start details found for " +
-				"standard vote",
+				"a single record vote",
 		}
 	}
 	sd := s.Starts[0]
@@ -459,7 +546,9 @@ func (p *ticketVotePlugin) startStandard(token []byte, s ticketvote.Start) (*tic
 	}
 
 	// Verify vote options and params
-	err = voteParamsVerify(sd.Params, p.voteDurationMin, p.voteDurationMax)
+	settings := p.settingsGet()
+	err = voteParamsVerify(sd.Params, settings.voteDurationMin,
+		settings.voteDurationMax)
 	if err != nil {
 		return nil, err
 	}
@@ -539,6 +628,12 @@ func (p *ticketVotePlugin) startStandard(token []byte, s ticketvote.Start) (*tic
 		EndBlockHeight:   vcp.EndBlockHeight,
 		EligibleTickets:  vcp.EligibleTickets,
 	}
+	if sd.Params.RevealDuration != 0 {
+		// The reveal window of a secret ballot vote begins once
+		// the voting period has ended.
+		vd.RevealEndBlockHeight = vcp.EndBlockHeight +
+			sd.Params.RevealDuration
+	}
 
 	// Save vote details
 	err = p.voteDetailsSave(token, vd)
@@ -548,7 +643,7 @@ func (p *ticketVotePlugin) startStandard(token []byte, s ticketvote.Start) (*tic
 
 	// Update inventory
 	p.inventoryUpdateToStarted(vd.Params.Token, ticketvote.VoteStatusStarted,
-		vd.EndBlockHeight)
+		voteEndHeight(vd))
 
 	// Update active votes cache
 	p.activeVotesAdd(vd)
@@ -782,7 +877,10 @@ func (p *ticketVotePlugin) startRunoffForParent(token []byte, s ticketvote.Start
 	if err != nil {
 		return nil, err
 	}
-	expected := make(map[string]struct{}, len(lf.Tokens)) // [token]struct{}
+	var (
+		expected = make(map[string]struct{}, len(lf.Tokens)) // [token]struct{}
+		amounts  = make(map[string]uint64, len(lf.Tokens))   // [token]amount
+	)
 	for k := range lf.Tokens {
 		token, err := tokenDecode(k)
 		if err != nil {
@@ -801,6 +899,21 @@ func (p *ticketVotePlugin) startRunoffForParent(token []byte, s ticketvote.Start
 		// This is a public record that is part of the parent record's
 		// submissions list. It is required to be in the runoff vote.
 		expected[k] = struct{}{}
+
+		// Snapshot the amount that was requested by the submission
+		// if the parent record has set a budget.
+		if vm.Budget == 0 {
+			continue
+		}
+		subVM, err := voteMetadataDecode(r.Files)
+		if err != nil {
+			return nil, err
+		}
+		if subVM == nil {
+			return nil, fmt.Errorf("vote metadata does not exist on "+
+				"submission %v", k)
+		}
+		amounts[k] = subVM.Amount
 	}
 
 	// Verify that there are no extra submissions in the runoff vote
@@ -850,6 +963,10 @@ func (p *ticketVotePlugin) startRunoffForParent(token []byte, s ticketvote.Start
 		EndBlockHeight:   vcp.EndBlockHeight,
 		EligibleTickets:  vcp.EligibleTickets,
 	}
+	if vm.Budget != 0 {
+		srr.Budget = vm.Budget
+		srr.Amounts = amounts
+	}
 
 	// Save start runoff record
 	err = p.startRunoffRecordSave(token, *srr)
@@ -881,6 +998,7 @@ func (p *ticketVotePlugin) startRunoff(token []byte, s ticketvote.Start) (*ticke
 		quorum   = s.Starts[0].Params.QuorumPercentage
 		pass     = s.Starts[0].Params.PassPercentage
 		parent   = s.Starts[0].Params.Parent
+		settings = p.settingsGet()
 	)
 	for _, v := range s.Starts {
 		// Verify vote params are the same for all submissions
@@ -968,8 +1086,8 @@ func (p *ticketVotePlugin) startRunoff(token []byte, s ticketvote.Start) (*ticke
 
 		// Verify vote options and params. Vote optoins are required to
 		// be approve and reject.
-		err = voteParamsVerify(v.Params, p.voteDurationMin,
-			p.voteDurationMax)
+		err = voteParamsVerify(v.Params, settings.voteDurationMin,
+			settings.voteDurationMax)
 		if err != nil {
 			return nil, err
 		}
@@ -1069,7 +1187,7 @@ func (p *ticketVotePlugin) cmdStart(token []byte, payload string) (string, error
 	// Start vote
 	var sr *ticketvote.StartReply
 	switch vtype {
-	case ticketvote.VoteTypeStandard:
+	case ticketvote.VoteTypeStandard, ticketvote.VoteTypeMultipleChoice:
 		sr, err = p.startStandard(token, s)
 		if err != nil {
 			return "", err
@@ -1262,7 +1380,7 @@ func (p *ticketVotePlugin) castVoteDetailsSave(token []byte, cv ticketvote.CastV
 // must be created using the largest commitment address from the ticket that is
 // casting a vote.
 func castVoteVerifySignature(cv ticketvote.CastVote, addr string, net *chaincfg.Params) error {
-	msg := cv.Token + cv.Ticket + cv.VoteBit
+	msg := cv.Token + cv.Ticket + cv.VoteBit + cv.Commitment
 
 	// Convert hex signature to base64. This is what the verify
 	// message function expects.
@@ -1325,13 +1443,14 @@ func (p *ticketVotePlugin) ballot(token []byte, votes []ticketvote.CastVote, br
 
 			// Setup cast vote details
 			cvd = ticketvote.CastVoteDetails{
-				Token:     v.Token,
-				Ticket:    v.Ticket,
-				VoteBit:   v.VoteBit,
-				Signature: v.Signature,
-				Address:   addr,
-				Receipt:   hex.EncodeToString(receipt[:]),
-				Timestamp: time.Now().Unix(),
+				Token:      v.Token,
+				Ticket:     v.Ticket,
+				VoteBit:    v.VoteBit,
+				Commitment: v.Commitment,
+				Signature:  v.Signature,
+				Address:    addr,
+				Receipt:    hex.EncodeToString(receipt[:]),
+				Timestamp:  time.Now().Unix(),
 			}
 
 			// Save cast vote details
@@ -1378,6 +1497,10 @@ func (p *ticketVotePlugin) ballot(token []byte, votes []ticketvote.CastVote, br
 
 			// Update cast votes cache
 			p.activeVotes.AddCastVote(v.Token, v.Ticket, v.VoteBit)
+			if v.Commitment != "" {
+				p.activeVotes.AddCommitment(v.Token, v.Ticket,
+					v.Commitment)
+			}
 
 		saveReply:
 			// Save the reply
@@ -1464,24 +1587,47 @@ func (p *ticketVotePlugin) cmdCastBallot(token []byte, payload string) (string,
 			continue
 		}
 
-		// Verify vote bit
-		bit, err := strconv.ParseUint(v.VoteBit, 16, 64)
-		if err != nil {
-			e := ticketvote.VoteErrorVoteBitInvalid
-			receipts[k].Ticket = v.Ticket
-			receipts[k].ErrorCode = e
-			receipts[k].ErrorContext = ticketvote.VoteErrors[e]
-			continue
-		}
-		err = voteBitVerify(voteDetails.Params.Options,
-			voteDetails.Params.Mask, bit)
-		if err != nil {
-			e := ticketvote.VoteErrorVoteBitInvalid
+		// Verify the commitment. Secret ballot votes cast a
+		// commitment instead of a vote bit. The vote bit is
+		// verified once it has been revealed.
+		switch {
+		case voteDetails.Params.RevealDuration != 0:
+			err := commitmentVerify(v)
+			if err != nil {
+				e := ticketvote.VoteErrorCommitmentInvalid
+				receipts[k].Ticket = v.Ticket
+				receipts[k].ErrorCode = e
+				receipts[k].ErrorContext = fmt.Sprintf("%v: %v",
+					ticketvote.VoteErrors[e], err)
+				continue
+			}
+		case v.Commitment != "":
+			e := ticketvote.VoteErrorCommitmentInvalid
 			receipts[k].Ticket = v.Ticket
 			receipts[k].ErrorCode = e
-			receipts[k].ErrorContext = fmt.Sprintf("%v: %v",
-				ticketvote.VoteErrors[e], err)
+			receipts[k].ErrorContext = fmt.Sprintf("%v: not a secret "+
+				"ballot vote", ticketvote.VoteErrors[e])
 			continue
+		default:
+			// Verify vote bit
+			bit, err := strconv.ParseUint(v.VoteBit, 16, 64)
+			if err != nil {
+				e := ticketvote.VoteErrorVoteBitInvalid
+				receipts[k].Ticket = v.Ticket
+				receipts[k].ErrorCode = e
+				receipts[k].ErrorContext = ticketvote.VoteErrors[e]
+				continue
+			}
+			err = voteBitVerify(voteDetails.Params.Options,
+				voteDetails.Params.Mask, bit)
+			if err != nil {
+				e := ticketvote.VoteErrorVoteBitInvalid
+				receipts[k].Ticket = v.Ticket
+				receipts[k].ErrorCode = e
+				receipts[k].ErrorContext = fmt.Sprintf("%v: %v",
+					ticketvote.VoteErrors[e], err)
+				continue
+			}
 		}
 
 		// Verify ticket is eligible to vote
@@ -1714,92 +1860,258 @@ func (p *ticketVotePlugin) cmdCastBallot(token []byte, payload string) (string,
 	return string(reply), nil
 }
 
-// cmdDetails returns the vote details for a record.
-func (p *ticketVotePlugin) cmdDetails(token []byte) (string, error) {
-	// Get vote authorizations
-	auths, err := p.auths(token)
-	if err != nil {
-		return "", fmt.Errorf("auths: %v", err)
-	}
+// voteReveal is saved to the backend when the vote bit of a secret ballot
+// vote is revealed. The vote bit and salt have been verified against the
+// commitment of the ticket before the vote reveal is saved.
+type voteReveal struct {
+	Token     string `json:"token"`     // Record token
+	Ticket    string `json:"ticket"`    // Ticket hash
+	VoteBit   string `json:"votebit"`   // Vote bit, hex encoded
+	Salt      string `json:"salt"`      // Commitment salt, hex encoded
+	Receipt   string `json:"receipt"`   // Server signature
+	Timestamp int64  `json:"timestamp"` // Unix timestamp
+}
 
-	// Get vote details
-	vd, err := p.voteDetails(token)
+// voteRevealSave saves a voteReveal to the backend.
+func (p *ticketVotePlugin) voteRevealSave(token []byte, vr voteReveal) error {
+	// Prepare blob
+	be, err := convertBlobEntryFromVoteReveal(vr)
 	if err != nil {
-		return "", fmt.Errorf("voteDetails: %v", err)
+		return err
 	}
 
-	// Prepare rely
-	dr := ticketvote.DetailsReply{
-		Auths: auths,
-		Vote:  vd,
+	// Save blob
+	return p.tstore.BlobSave(token, *be)
+}
+
+// commitmentMatches returns whether the provided commitment is the commitment
+// to the token, ticket, vote bit and salt. The hex encoding of a commitment is
+// case insensitive. The commitment is saved the way that it was cast, which is
+// also what the cast vote signature covers.
+func commitmentMatches(commitment, token, ticket, voteBit, salt string) bool {
+	return strings.EqualFold(commitment,
+		ticketvote.VoteCommitment(token, ticket, voteBit, salt))
+}
+
+// commitmentVerify verifies that a secret ballot cast vote contains a valid
+// commitment and does not contain a vote bit. The commitment can only be
+// verified against the token and ticket of the cast vote once it has been
+// revealed. See revealVerify.
+func commitmentVerify(cv ticketvote.CastVote) error {
+	if cv.VoteBit != "" {
+		return fmt.Errorf("vote bit must be empty")
 	}
-	reply, err := json.Marshal(dr)
+	b, err := hex.DecodeString(cv.Commitment)
 	if err != nil {
-		return "", err
+		return fmt.Errorf("commitment is not hex")
 	}
-
-	return string(reply), nil
+	if len(b) != sha256.Size {
+		return fmt.Errorf("commitment size got %v, want %v",
+			len(b), sha256.Size)
+	}
+	return nil
 }
 
-// cmdRunoffDetails is an internal plugin command that requests the details of
-// a runoff vote.
-func (p *ticketVotePlugin) cmdRunoffDetails(token []byte) (string, error) {
-	// Get start runoff record
-	srs, err := p.startRunoffRecord(token)
+// revealVerify verifies that the provided vote reveal matches the commitment
+// that was cast by the ticket. The token and ticket of the reveal are part of
+// the commitment.
+func revealVerify(rv ticketvote.RevealVote, options []ticketvote.VoteOption, mask uint64, commitment string) (ticketvote.VoteErrorT, error) {
+	// Verify vote bit
+	bit, err := strconv.ParseUint(rv.VoteBit, 16, 64)
 	if err != nil {
-		return "", err
+		return ticketvote.VoteErrorVoteBitInvalid, fmt.Errorf("not hex")
 	}
-
-	// Prepare reply
-	r := runoffDetailsReply{
-		Runoff: *srs,
+	err = voteBitVerify(options, mask, bit)
+	if err != nil {
+		return ticketvote.VoteErrorVoteBitInvalid, err
 	}
-	reply, err := json.Marshal(r)
+
+	// Verify salt
+	b, err := hex.DecodeString(rv.Salt)
 	if err != nil {
-		return "", err
+		return ticketvote.VoteErrorCommitmentInvalid,
+			fmt.Errorf("salt is not hex")
+	}
+	if len(b) != ticketvote.SaltSize {
+		return ticketvote.VoteErrorCommitmentInvalid,
+			fmt.Errorf("salt size got %v, want %v", len(b),
+				ticketvote.SaltSize)
 	}
 
-	return string(reply), nil
+	// Verify the commitment
+	if !commitmentMatches(commitment, rv.Token, rv.Ticket, rv.VoteBit,
+		rv.Salt) {
+		return ticketvote.VoteErrorCommitmentInvalid,
+			fmt.Errorf("token, ticket, vote bit and salt do not match " +
+				"the commitment")
+	}
+
+	return ticketvote.VoteErrorInvalid, nil
 }
 
-// cmdResults requests the vote objects of all votes that were cast in a ticket
-// vote.
-func (p *ticketVotePlugin) cmdResults(token []byte) (string, error) {
-	// Get vote results
-	votes, err := p.voteResults(token)
+// cmdReveal reveals a ballot of secret ballot votes. This function will not
+// return a user error if one occurs for an individual vote. It will instead
+// return the reveal reply with the error included in the individual reveal
+// vote reply.
+func (p *ticketVotePlugin) cmdReveal(token []byte, payload string) (string, error) {
+	// Decode payload
+	var r ticketvote.Reveal
+	err := json.Unmarshal([]byte(payload), &r)
 	if err != nil {
 		return "", err
 	}
+	votes := r.Votes
 
-	// Prepare reply
-	rr := ticketvote.ResultsReply{
-		Votes: votes,
-	}
-	reply, err := json.Marshal(rr)
+	// Get the data that we need to validate the reveals
+	voteDetails := p.activeVotes.VoteDetails(token)
+	bestBlock, err := p.bestBlock()
 	if err != nil {
 		return "", err
 	}
 
-	return string(reply), nil
-}
+	// Validate the reveals. A ticket can only be revealed once. The
+	// cast votes cache is not updated until the valid reveals have been
+	// saved, so the tickets of the valid reveals in this batch are
+	// tracked separately.
+	var (
+		receipts = make([]ticketvote.RevealVoteReply, len(votes))
+		tickets  = make(map[string]struct{}, len(votes))
+	)
+	for k, v := range votes {
+		receipts[k].Ticket = v.Ticket
 
-// cmdSummary requests the vote summary for a record.
-func (p *ticketVotePlugin) cmdSummary(token []byte) (string, error) {
-	// Get best block. This cmd does not write any data so we do not
-	// have to use the safe best block.
-	bb, err := p.bestBlockUnsafe()
-	if err != nil {
-		return "", fmt.Errorf("bestBlockUnsafe: %v", err)
+		// Verify token is a valid token
+		rt, err := tokenDecode(v.Token)
+		if err != nil {
+			e := ticketvote.VoteErrorTokenInvalid
+			receipts[k].ErrorCode = e
+			receipts[k].ErrorContext = fmt.Sprintf("%v: not hex",
+				ticketvote.VoteErrors[e])
+			continue
+		}
+
+		// Verify vote token and command token are the same
+		if !bytes.Equal(rt, token) {
+			e := ticketvote.VoteErrorMultipleRecordVotes
+			receipts[k].ErrorCode = e
+			receipts[k].ErrorContext = ticketvote.VoteErrors[e]
+			continue
+		}
+
+		// Verify the reveal window is open
+		var windowErr string
+		switch {
+		case voteDetails == nil:
+			windowErr = "vote is not active"
+		case voteDetails.Params.RevealDuration == 0:
+			windowErr = "not a secret ballot vote"
+		case !voteHasEnded(bestBlock, voteDetails.EndBlockHeight):
+			windowErr = "reveal window has not started"
+		case voteHasEnded(bestBlock, voteDetails.RevealEndBlockHeight):
+			windowErr = "reveal window has ended"
+		}
+		if windowErr != "" {
+			e := ticketvote.VoteErrorVoteStatusInvalid
+			receipts[k].ErrorCode = e
+			receipts[k].ErrorContext = fmt.Sprintf("%v: %v",
+				ticketvote.VoteErrors[e], windowErr)
+			continue
+		}
+
+		// Verify the ticket has cast a commitment that has not been
+		// revealed yet.
+		commitment, revealed := p.activeVotes.Commitment(v.Token, v.Ticket)
+		if commitment == "" {
+			e := ticketvote.VoteErrorCommitmentNotFound
+			receipts[k].ErrorCode = e
+			receipts[k].ErrorContext = ticketvote.VoteErrors[e]
+			continue
+		}
+		if revealed {
+			e := ticketvote.VoteErrorTicketAlreadyVoted
+			receipts[k].ErrorCode = e
+			receipts[k].ErrorContext = fmt.Sprintf("%v: vote has "+
+				"already been revealed", ticketvote.VoteErrors[e])
+			continue
+		}
+		if _, ok := tickets[v.Ticket]; ok {
+			e := ticketvote.VoteErrorTicketAlreadyVoted
+			receipts[k].ErrorCode = e
+			receipts[k].ErrorContext = fmt.Sprintf("%v: duplicate "+
+				"ticket in reveal", ticketvote.VoteErrors[e])
+			continue
+		}
+
+		// Verify the reveal matches the commitment
+		e, err := revealVerify(v, voteDetails.Params.Options,
+			voteDetails.Params.Mask, commitment)
+		if err != nil {
+			receipts[k].ErrorCode = e
+			receipts[k].ErrorContext = fmt.Sprintf("%v: %v",
+				ticketvote.VoteErrors[e], err)
+			continue
+		}
+
+		tickets[v.Ticket] = struct{}{}
 	}
 
-	// Get summary
-	sr, err := p.summary(token, bb)
-	if err != nil {
-		return "", fmt.Errorf("summary: %v", err)
+	// Save the valid reveals. The reveals are saved concurrently in
+	// batches for the same reasons that cast votes are. See the
+	// cmdCastBallot function for more details.
+	var (
+		batchSize = 10
+		wg        sync.WaitGroup
+		count     int
+	)
+	for k, v := range votes {
+		if receipts[k].ErrorCode != ticketvote.VoteErrorInvalid {
+			// Reveal has an error. Skip it.
+			continue
+		}
+
+		wg.Add(1)
+		go func(k int, v ticketvote.RevealVote) {
+			defer wg.Done()
+
+			receipt := p.identity.SignMessage([]byte(v.Ticket +
+				v.VoteBit + v.Salt))
+			vr := voteReveal{
+				Token:     v.Token,
+				Ticket:    v.Ticket,
+				VoteBit:   v.VoteBit,
+				Salt:      v.Salt,
+				Receipt:   hex.EncodeToString(receipt[:]),
+				Timestamp: time.Now().Unix(),
+			}
+			err := p.voteRevealSave(token, vr)
+			if err != nil {
+				t := time.Now().Unix()
+				log.Errorf("cmdReveal: voteRevealSave %v: %v", t, err)
+				e := ticketvote.VoteErrorInternalError
+				receipts[k].ErrorCode = e
+				receipts[k].ErrorContext = fmt.Sprintf("%v: %v",
+					ticketvote.VoteErrors[e], t)
+				return
+			}
+			receipts[k].Receipt = vr.Receipt
+
+			// Update cast votes cache
+			p.activeVotes.AddCastVote(v.Token, v.Ticket, v.VoteBit)
+		}(k, v)
+
+		count++
+		if count%batchSize == 0 {
+			// Wait for the batch to be saved
+			wg.Wait()
+		}
 	}
+	wg.Wait()
 
 	// Prepare reply
-	reply, err := json.Marshal(sr)
+	rr := ticketvote.RevealReply{
+		Receipts: receipts,
+	}
+	reply, err := json.Marshal(rr)
 	if err != nil {
 		return "", err
 	}
@@ -1807,41 +2119,269 @@ func (p *ticketVotePlugin) cmdSummary(token []byte) (string, error) {
 	return string(reply), nil
 }
 
-// cmdInventory requests a page of tokens for the provided status. If no status
-// is provided then a page for each status will be returned.
-func (p *ticketVotePlugin) cmdInventory(payload string) (string, error) {
-	var i ticketvote.Inventory
-	err := json.Unmarshal([]byte(payload), &i)
+// cmdCancel cancels an ongoing ticket vote. The vote is removed from the
+// active votes cache so that no further ballots are accepted and the vote
+// summary is updated to the cancelled status.
+func (p *ticketVotePlugin) cmdCancel(token []byte, payload string) (string, error) {
+	// Decode payload
+	var c ticketvote.Cancel
+	err := json.Unmarshal([]byte(payload), &c)
 	if err != nil {
 		return "", err
 	}
 
-	// Get best block. This command does not write any data so we can
-	// use the unsafe best block.
-	bb, err := p.bestBlockUnsafe()
+	// Verify token
+	err = tokenVerify(token, c.Token)
 	if err != nil {
-		return "", fmt.Errorf("bestBlockUnsafe: %v", err)
+		return "", err
 	}
 
-	// Get the inventory
-	ibs, err := p.inventoryByStatus(bb, i.Status, i.Page)
+	// Verify signature
+	msg := c.Token + c.Reason
+	err = util.VerifySignature(c.Signature, c.PublicKey, msg)
 	if err != nil {
-		return "", fmt.Errorf("invByStatus: %v", err)
+		return "", convertSignatureError(err)
 	}
 
-	// Prepare reply
-	tokens := make(map[string][]string, len(ibs.Tokens))
-	for k, v := range ibs.Tokens {
-		vs := ticketvote.VoteStatuses[k]
-		tokens[vs] = v
-	}
-	ir := ticketvote.InventoryReply{
-		Tokens:    tokens,
-		BestBlock: ibs.BestBlock,
-	}
-	reply, err := json.Marshal(ir)
-	if err != nil {
-		return "", err
+	// Verify reason
+	if strings.TrimSpace(c.Reason) == "" {
+		return "", backend.PluginError{
+			PluginID:     ticketvote.PluginID,
+			ErrorCode:    uint32(ticketvote.ErrorCodeCancelReasonInvalid),
+			ErrorContext: "reason is empty",
+		}
+	}
+
+	// Verify record status
+	r, err := p.tstore.RecordPartial(token, 0, nil, true)
+	if err != nil {
+		return "", fmt.Errorf("RecordPartial: %v", err)
+	}
+	if r.RecordMetadata.Status != backend.StatusPublic {
+		return "", backend.PluginError{
+			PluginID:     ticketvote.PluginID,
+			ErrorCode:    uint32(ticketvote.ErrorCodeRecordStatusInvalid),
+			ErrorContext: "record is not public",
+		}
+	}
+
+	// Verify vote type. A runoff vote cannot be cancelled since the
+	// outcome of a runoff vote submission depends on the outcome of
+	// all other submissions.
+	vd, err := p.voteDetails(token)
+	if err != nil {
+		return "", err
+	}
+	if vd != nil && vd.Params.Type == ticketvote.VoteTypeRunoff {
+		return "", backend.PluginError{
+			PluginID:     ticketvote.PluginID,
+			ErrorCode:    uint32(ticketvote.ErrorCodeVoteTypeInvalid),
+			ErrorContext: "runoff votes cannot be cancelled",
+		}
+	}
+
+	// Verify vote status. Only a vote that is still in progress can
+	// be cancelled. This includes secret ballot votes whose reveal
+	// window has not ended yet.
+	bestBlock, err := p.bestBlock()
+	if err != nil {
+		return "", err
+	}
+	sr, err := p.summary(token, bestBlock)
+	if err != nil {
+		return "", err
+	}
+	if sr.Status != ticketvote.VoteStatusStarted {
+		return "", backend.PluginError{
+			PluginID:  ticketvote.PluginID,
+			ErrorCode: uint32(ticketvote.ErrorCodeVoteStatusInvalid),
+			ErrorContext: fmt.Sprintf("vote status must be %v; got %v",
+				ticketvote.VoteStatuses[ticketvote.VoteStatusStarted],
+				ticketvote.VoteStatuses[sr.Status]),
+		}
+	}
+
+	// Prepare cancel details
+	receipt := p.identity.SignMessage([]byte(c.Signature))
+	cd := ticketvote.CancelDetails{
+		Token:     c.Token,
+		Reason:    c.Reason,
+		PublicKey: c.PublicKey,
+		Signature: c.Signature,
+		Timestamp: time.Now().Unix(),
+		Receipt:   hex.EncodeToString(receipt[:]),
+	}
+
+	// Save cancel details
+	err = p.cancelSave(token, cd)
+	if err != nil {
+		return "", err
+	}
+
+	// Remove the record from the active votes cache. Ballots are only
+	// accepted for records that are in the active votes cache.
+	p.activeVotes.Del(c.Token)
+
+	// Update inventory. The cancelled entry does not have an end
+	// height so it is not updated when the vote end height is reached.
+	p.inventoryUpdate(c.Token, ticketvote.VoteStatusCancelled)
+
+	// Cache the cancelled vote summary
+	_, err = p.summary(token, bestBlock)
+	if err != nil {
+		return "", err
+	}
+
+	log.Infof("Vote cancelled %v: %v", c.Token, c.Reason)
+
+	// Prepare reply
+	cr := ticketvote.CancelReply{
+		Timestamp: cd.Timestamp,
+		Receipt:   cd.Receipt,
+	}
+	reply, err := json.Marshal(cr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// cmdDetails returns the vote details for a record.
+func (p *ticketVotePlugin) cmdDetails(token []byte) (string, error) {
+	// Get vote authorizations
+	auths, err := p.auths(token)
+	if err != nil {
+		return "", fmt.Errorf("auths: %v", err)
+	}
+
+	// Get vote details
+	vd, err := p.voteDetails(token)
+	if err != nil {
+		return "", fmt.Errorf("voteDetails: %v", err)
+	}
+
+	// Get vote cancellation
+	cd, err := p.cancelDetails(token)
+	if err != nil {
+		return "", fmt.Errorf("cancelDetails: %v", err)
+	}
+
+	// Prepare rely
+	dr := ticketvote.DetailsReply{
+		Auths:  auths,
+		Vote:   vd,
+		Cancel: cd,
+	}
+	reply, err := json.Marshal(dr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// cmdRunoffDetails is an internal plugin command that requests the details of
+// a runoff vote.
+func (p *ticketVotePlugin) cmdRunoffDetails(token []byte) (string, error) {
+	// Get start runoff record
+	srs, err := p.startRunoffRecord(token)
+	if err != nil {
+		return "", err
+	}
+
+	// Prepare reply
+	r := runoffDetailsReply{
+		Runoff: *srs,
+	}
+	reply, err := json.Marshal(r)
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// cmdResults requests the vote objects of all votes that were cast in a ticket
+// vote.
+func (p *ticketVotePlugin) cmdResults(token []byte) (string, error) {
+	// Get vote results
+	votes, err := p.voteResults(token)
+	if err != nil {
+		return "", err
+	}
+
+	// Prepare reply
+	rr := ticketvote.ResultsReply{
+		Votes: votes,
+	}
+	reply, err := json.Marshal(rr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// cmdSummary requests the vote summary for a record.
+func (p *ticketVotePlugin) cmdSummary(token []byte) (string, error) {
+	// Get best block. This cmd does not write any data so we do not
+	// have to use the safe best block.
+	bb, err := p.bestBlockUnsafe()
+	if err != nil {
+		return "", fmt.Errorf("bestBlockUnsafe: %v", err)
+	}
+
+	// Get summary
+	sr, err := p.summary(token, bb)
+	if err != nil {
+		return "", fmt.Errorf("summary: %v", err)
+	}
+
+	// Prepare reply
+	reply, err := json.Marshal(sr)
+	if err != nil {
+		return "", err
+	}
+
+	return string(reply), nil
+}
+
+// cmdInventory requests a page of tokens for the provided status. If no status
+// is provided then a page for each status will be returned.
+func (p *ticketVotePlugin) cmdInventory(payload string) (string, error) {
+	var i ticketvote.Inventory
+	err := json.Unmarshal([]byte(payload), &i)
+	if err != nil {
+		return "", err
+	}
+
+	// Get best block. This command does not write any data so we can
+	// use the unsafe best block.
+	bb, err := p.bestBlockUnsafe()
+	if err != nil {
+		return "", fmt.Errorf("bestBlockUnsafe: %v", err)
+	}
+
+	// Get the inventory
+	ibs, err := p.inventoryByStatus(bb, i.Status, i.Page)
+	if err != nil {
+		return "", fmt.Errorf("invByStatus: %v", err)
+	}
+
+	// Prepare reply
+	tokens := make(map[string][]string, len(ibs.Tokens))
+	for k, v := range ibs.Tokens {
+		vs := ticketvote.VoteStatuses[k]
+		tokens[vs] = v
+	}
+	ir := ticketvote.InventoryReply{
+		Tokens:    tokens,
+		BestBlock: ibs.BestBlock,
+	}
+	reply, err := json.Marshal(ir)
+	if err != nil {
+		return "", err
 	}
 
 	return string(reply), nil
@@ -1859,6 +2399,7 @@ func (p *ticketVotePlugin) cmdTimestamps(token []byte, payload string) (string,
 	var (
 		auths   = make([]ticketvote.Timestamp, 0, 32)
 		details *ticketvote.Timestamp
+		cancel  *ticketvote.Timestamp
 
 		pageSize = ticketvote.VoteTimestampsPageSize
 		votes    = make([]ticketvote.Timestamp, 0, pageSize)
@@ -1931,12 +2472,34 @@ func (p *ticketVotePlugin) cmdTimestamps(token []byte, payload string) (string,
 			}
 			details = ts
 		}
+
+		// Vote cancellation timestamp
+		digests, err = p.tstore.DigestsByDataDesc(token,
+			[]string{dataDescriptorCancelDetails})
+		if err != nil {
+			return "", fmt.Errorf("DigestsByDataDesc %x %v: %v",
+				token, dataDescriptorCancelDetails, err)
+		}
+		// There should never be more than a one cancel details
+		if len(digests) > 1 {
+			return "", fmt.Errorf("invalid cancel details count: "+
+				"got %v, want 1", len(digests))
+		}
+		for _, v := range digests {
+			ts, err := p.timestamp(token, v)
+			if err != nil {
+				return "", fmt.Errorf("timestamp %x %x: %v",
+					token, v, err)
+			}
+			cancel = ts
+		}
 	}
 
 	// Prepare reply
 	tr := ticketvote.TimestampsReply{
 		Auths:   auths,
 		Details: details,
+		Cancel:  cancel,
 		Votes:   votes,
 	}
 	reply, err := json.Marshal(tr)
@@ -2073,12 +2636,55 @@ func (p *ticketVotePlugin) voteDetailsByToken(token []byte) (*ticketvote.VoteDet
 	return dr.Vote, nil
 }
 
+// cancelSave saves a CancelDetails to the backend.
+func (p *ticketVotePlugin) cancelSave(token []byte, cd ticketvote.CancelDetails) error {
+	// Prepare blob
+	be, err := convertBlobEntryFromCancelDetails(cd)
+	if err != nil {
+		return err
+	}
+
+	// Save blob
+	return p.tstore.BlobSave(token, *be)
+}
+
+// cancelDetails returns the CancelDetails for a record. Nil is returned if a
+// cancel details is not found.
+func (p *ticketVotePlugin) cancelDetails(token []byte) (*ticketvote.CancelDetails, error) {
+	// Retrieve blobs
+	blobs, err := p.tstore.BlobsByDataDesc(token,
+		[]string{dataDescriptorCancelDetails})
+	if err != nil {
+		return nil, err
+	}
+	switch len(blobs) {
+	case 0:
+		// A cancel details does not exist
+		return nil, nil
+	case 1:
+		// A cancel details exists; continue
+	default:
+		// This should not happen. A vote can only be cancelled once.
+		return nil, fmt.Errorf("multiple cancel details found (%v) on %x",
+			len(blobs), token)
+	}
+
+	// Decode blob
+	cd, err := convertCancelDetailsFromBlobEntry(blobs[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return cd, nil
+}
+
 // voteResults returns all votes that were cast in a ticket vote.
 func (p *ticketVotePlugin) voteResults(token []byte) ([]ticketvote.CastVoteDetails, error) {
 	// Retrieve blobs
 	desc := []string{
 		dataDescriptorCastVoteDetails,
 		dataDescriptorVoteCollider,
+		dataDescriptorVoteReveal,
 	}
 	blobs, err := p.tstore.BlobsByDataDesc(token, desc)
 	if err != nil {
@@ -2098,6 +2704,9 @@ func (p *ticketVotePlugin) voteResults(token []byte) ([]ticketvote.CastVoteDetai
 
 		// map[ticket]index
 		colliderIndexes = make(map[string]int, len(blobs))
+
+		// map[ticket]voteReveal
+		reveals = make(map[string]voteReveal, len(blobs))
 	)
 	for i, v := range blobs {
 		// Decode data hint
@@ -2146,6 +2755,20 @@ func (p *ticketVotePlugin) voteResults(token []byte) ([]ticketvote.CastVoteDetai
 			// Save the ticket and index for the collider
 			colliderIndexes[vc.Ticket] = i
 
+		case dataDescriptorVoteReveal:
+			// Decode vote reveal
+			vr, err := convertVoteRevealFromBlobEntry(v)
+			if err != nil {
+				return nil, err
+			}
+
+			// Save the vote reveal. A ticket can only be
+			// revealed once, but keep the first reveal in
+			// case of an unexpected duplicate.
+			if _, ok := reveals[vr.Ticket]; !ok {
+				reveals[vr.Ticket] = *vr
+			}
+
 		default:
 			return nil, fmt.Errorf("invalid data descriptor: %v",
 				dd.Descriptor)
@@ -2201,6 +2824,24 @@ func (p *ticketVotePlugin) voteResults(token []byte) ([]ticketvote.CastVoteDetai
 		votes[cv.Ticket] = *cv
 	}
 
+	// Add the revealed vote bits to the secret ballot votes. The
+	// reveal was verified against the commitment before it was saved.
+	// It is verified again here as a sanity check.
+	for ticket, vr := range reveals {
+		cv, ok := votes[ticket]
+		if !ok || cv.Commitment == "" {
+			continue
+		}
+		if !commitmentMatches(cv.Commitment, vr.Token, vr.Ticket,
+			vr.VoteBit, vr.Salt) {
+			return nil, fmt.Errorf("vote reveal does not match the "+
+				"commitment %v", ticket)
+		}
+		cv.VoteBit = vr.VoteBit
+		cv.Salt = vr.Salt
+		votes[ticket] = cv
+	}
+
 	// Put votes into an array
 	cvotes := make([]ticketvote.CastVoteDetails, 0, len(blobs))
 	for _, v := range votes {
@@ -2216,8 +2857,10 @@ func (p *ticketVotePlugin) voteResults(token []byte) ([]ticketvote.CastVoteDetai
 }
 
 // voteOptionResults tallies the results of a ticket vote and returns a
-// VoteOptionResult for each vote option in the ticket vote.
-func (p *ticketVotePlugin) voteOptionResults(token []byte, options []ticketvote.VoteOption) ([]ticketvote.VoteOptionResult, error) {
+// VoteOptionResult for each vote option in the ticket vote. The number of
+// secret ballot votes that have not been revealed is also returned. These
+// votes are not included in the results of any vote option.
+func (p *ticketVotePlugin) voteOptionResults(token []byte, options []ticketvote.VoteOption) ([]ticketvote.VoteOptionResult, uint64, error) {
 	// Ongoing votes will have the cast votes cached. Calculate the results
 	// using the cached votes if we can since it will be much faster.
 	var (
@@ -2235,12 +2878,12 @@ func (p *ticketVotePlugin) voteOptionResults(token []byte, options []ticketvote.
 		reply, err := p.backend.PluginRead(token, ticketvote.PluginID,
 			ticketvote.CmdResults, "")
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 		var rr ticketvote.ResultsReply
 		err = json.Unmarshal([]byte(reply), &rr)
 		if err != nil {
-			return nil, err
+			return nil, 0, err
 		}
 
 		// Tally the results
@@ -2261,7 +2904,11 @@ func (p *ticketVotePlugin) voteOptionResults(token []byte, options []ticketvote.
 		})
 	}
 
-	return results, nil
+	// Secret ballot votes that have not been revealed do not have a
+	// vote bit.
+	unrevealed := uint64(tally[""])
+
+	return results, unrevealed, nil
 }
 
 // voteSummariesForRunoff calculates and returns the vote summaries of all
@@ -2301,6 +2948,11 @@ func (p *ticketVotePlugin) summariesForRunoff(parentToken string) (map[string]ti
 
 		// Token of the winner
 		winnerToken string
+
+		// Net number of approve votes of the submissions that met
+		// the quorum and pass requirements. This is only used when
+		// the runoff vote has a budget.
+		passed = make(map[string]int, len(subs)) // [token]netApprove
 	)
 	for _, v := range subs {
 		token, err := tokenDecode(v)
@@ -2314,8 +2966,9 @@ func (p *ticketVotePlugin) summariesForRunoff(parentToken string) (map[string]ti
 			return nil, err
 		}
 
-		// Get vote options results
-		results, err := p.voteOptionResults(token, vd.Params.Options)
+		// Get vote options results. Runoff votes cannot be secret
+		// ballot votes so there are no unrevealed votes.
+		results, _, err := p.voteOptionResults(token, vd.Params.Options)
 		if err != nil {
 			return nil, err
 		}
@@ -2338,7 +2991,7 @@ func (p *ticketVotePlugin) summariesForRunoff(parentToken string) (map[string]ti
 		// We now check if this record has the most net yes votes.
 
 		// Verify the vote met quorum and pass requirements
-		approved := voteIsApproved(*vd, results)
+		approved := voteIsApproved(*vd, results, 0)
 		if !approved {
 			// Vote did not meet quorum and pass requirements.
 			// Nothing else to do. Record vote is not approved.
@@ -2378,7 +3031,28 @@ func (p *ticketVotePlugin) summariesForRunoff(parentToken string) (map[string]ti
 			// vote summary would need to be removed and recreated
 			// using whatever methodology is decided upon.
 		}
+
+		passed[v] = int(votesApprove) - int(votesReject)
 	}
+
+	// A runoff vote with a budget can have multiple winners. The
+	// submissions are funded in order of net approval until the
+	// budget has been exhausted.
+	if rdr.Runoff.Budget != 0 {
+		outcomes := runoffBudgetOutcomes(rdr.Runoff.Budget,
+			rdr.Runoff.Amounts, subs, passed)
+		for token, rb := range outcomes {
+			rb := rb
+			s := summaries[token]
+			if rb.Outcome == ticketvote.BudgetOutcomeFunded {
+				s.Status = ticketvote.VoteStatusApproved
+			}
+			s.Budget = &rb
+			summaries[token] = s
+		}
+		return summaries, nil
+	}
+
 	if winnerToken != "" {
 		// A winner was found. Mark their summary as approved.
 		s := summaries[winnerToken]
@@ -2389,6 +3063,62 @@ func (p *ticketVotePlugin) summariesForRunoff(parentToken string) (map[string]ti
 	return summaries, nil
 }
 
+// runoffBudgetOutcomes returns the budget outcomes of the submissions of a
+// runoff vote that has a budget. The passed map contains the net number of
+// approve votes of the submissions that met the quorum and pass requirements.
+//
+// The passed submissions are funded in order of net approval, from highest
+// to lowest, as long as the remaining budget is sufficient to fund their
+// requested amount. A submission that cannot be funded is skipped and the
+// submissions with a lower net approval are still considered. Submissions
+// with the same net approval are ordered by token so that the outcomes are
+// deterministic.
+func runoffBudgetOutcomes(budget uint64, amounts map[string]uint64, subs []string, passed map[string]int) map[string]ticketvote.RunoffBudget {
+	// Sort the passed submissions by net approval
+	sorted := make([]string, 0, len(passed))
+	for token := range passed {
+		sorted = append(sorted, token)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if passed[sorted[i]] != passed[sorted[j]] {
+			return passed[sorted[i]] > passed[sorted[j]]
+		}
+		return sorted[i] < sorted[j]
+	})
+
+	// Fund the passed submissions
+	var (
+		remaining = budget
+		outcomes  = make(map[string]ticketvote.BudgetOutcomeT, len(subs))
+	)
+	for _, token := range sorted {
+		amount := amounts[token]
+		if amount > remaining {
+			outcomes[token] = ticketvote.BudgetOutcomeOverBudget
+			continue
+		}
+		remaining -= amount
+		outcomes[token] = ticketvote.BudgetOutcomeFunded
+	}
+
+	// Compile the budget outcomes of all submissions
+	rbs := make(map[string]ticketvote.RunoffBudget, len(subs))
+	for _, token := range subs {
+		outcome, ok := outcomes[token]
+		if !ok {
+			outcome = ticketvote.BudgetOutcomeNotPassed
+		}
+		rbs[token] = ticketvote.RunoffBudget{
+			Total:     budget,
+			Amount:    amounts[token],
+			Remaining: remaining,
+			Outcome:   outcome,
+		}
+	}
+
+	return rbs
+}
+
 // summary returns the vote summary for a record.
 func (p *ticketVotePlugin) summary(token []byte, bestBlock uint32) (*ticketvote.SummaryReply, error) {
 	// Check if the summary has been cached
@@ -2405,7 +3135,27 @@ func (p *ticketVotePlugin) summary(token []byte, bestBlock uint32) (*ticketvote.
 		return s, nil
 	}
 
-	// Summary has not been cached. Get it manually.
+	// Summary has not been cached. The cancel details are the source
+	// of truth for whether the vote has been cancelled. The inventory
+	// is updated separately and may lag behind if politeiad crashed
+	// in between the two writes.
+	cd, err := p.cancelDetails(token)
+	if err != nil {
+		return nil, fmt.Errorf("cancelDetails: %v", err)
+	}
+
+	return p.summaryBuild(token, bestBlock, cd != nil)
+}
+
+// summaryBuild builds the vote summary for a record from the ticketvote data
+// of the record and caches it if the vote has finished or has been cancelled.
+// The caller must specify whether the vote has been cancelled.
+//
+// The caches are keyed by the provided token and not by the token in the vote
+// details. The vote details of an imported record contain the token of the
+// record that it was exported from.
+func (p *ticketVotePlugin) summaryBuild(token []byte, bestBlock uint32, cancelled bool) (*ticketvote.SummaryReply, error) {
+	t := hex.EncodeToString(token)
 
 	// Assume vote is unauthorized. Only update the status when the
 	// appropriate record has been found that proves otherwise.
@@ -2454,7 +3204,7 @@ func (p *ticketVotePlugin) summary(token []byte, bestBlock uint32) (*ticketvote.
 	status = ticketvote.VoteStatusStarted
 
 	// Tally vote results
-	results, err := p.voteOptionResults(token, vd.Params.Options)
+	results, unrevealed, err := p.voteOptionResults(token, vd.Params.Options)
 	if err != nil {
 		return nil, err
 	}
@@ -2472,10 +3222,31 @@ func (p *ticketVotePlugin) summary(token []byte, bestBlock uint32) (*ticketvote.
 		PassPercentage:   vd.Params.PassPercentage,
 		Results:          results,
 		BestBlock:        bestBlock,
+
+		RevealEndBlockHeight: vd.RevealEndBlockHeight,
+		Unrevealed:           unrevealed,
 	}
 
-	// If the vote has not finished yet then we are done for now.
-	if !voteHasEnded(bestBlock, vd.EndBlockHeight) {
+	// A cancelled vote has no outcome and its summary will not
+	// change, so it can be cached.
+	if cancelled {
+		summary.Status = ticketvote.VoteStatusCancelled
+
+		// Cache summary
+		err = p.summaryCacheSave(t, summary)
+		if err != nil {
+			return nil, err
+		}
+
+		// Remove record from the active votes cache
+		p.activeVotes.Del(t)
+
+		return &summary, nil
+	}
+
+	// If the vote has not finished yet then we are done for now. A
+	// secret ballot vote finishes once the reveal window has ended.
+	if !voteHasEnded(bestBlock, voteEndHeight(*vd)) {
 		return &summary, nil
 	}
 
@@ -2484,20 +3255,20 @@ func (p *ticketVotePlugin) summary(token []byte, bestBlock uint32) (*ticketvote.
 	switch vd.Params.Type {
 	case ticketvote.VoteTypeStandard:
 		// Standard vote uses a simple approve/reject result
-		if voteIsApproved(*vd, results) {
+		if voteIsApproved(*vd, results, unrevealed) {
 			summary.Status = ticketvote.VoteStatusApproved
 		} else {
 			summary.Status = ticketvote.VoteStatusRejected
 		}
 
 		// Cache summary
-		err = p.summaryCacheSave(vd.Params.Token, summary)
+		err = p.summaryCacheSave(t, summary)
 		if err != nil {
 			return nil, err
 		}
 
 		// Remove record from the active votes cache
-		p.activeVotes.Del(vd.Params.Token)
+		p.activeVotes.Del(t)
 
 	case ticketvote.VoteTypeRunoff:
 		// A runoff vote requires that we pull all other runoff vote
@@ -2517,7 +3288,23 @@ func (p *ticketVotePlugin) summary(token []byte, bestBlock uint32) (*ticketvote.
 			p.activeVotes.Del(k)
 		}
 
-		summary = summaries[vd.Params.Token]
+		summary = summaries[t]
+
+	case ticketvote.VoteTypeMultipleChoice:
+		// Multiple choice votes do not have an approved or rejected
+		// outcome. The vote is finished and the winning vote option,
+		// if there is one, is included in the summary.
+		summary.Status = ticketvote.VoteStatusFinished
+		summary.WinningOption = voteWinningOption(*vd, results, unrevealed)
+
+		// Cache summary
+		err = p.summaryCacheSave(t, summary)
+		if err != nil {
+			return nil, err
+		}
+
+		// Remove record from the active votes cache
+		p.activeVotes.Del(t)
 
 	default:
 		return nil, fmt.Errorf("unknown vote type")
@@ -2661,11 +3448,22 @@ func voteHasEnded(bestBlock, endHeight uint32) bool {
 	return bestBlock >= endHeight
 }
 
+// voteEndHeight returns the block height that the vote finishes at. A secret
+// ballot vote finishes once its reveal window has ended.
+func voteEndHeight(vd ticketvote.VoteDetails) uint32 {
+	if vd.Params.RevealDuration != 0 {
+		return vd.RevealEndBlockHeight
+	}
+	return vd.EndBlockHeight
+}
+
 // voteIsApproved returns whether the provided vote option results met the
-// provided quorum and pass percentage requirements. This function can only be
-// called on votes that use VoteOptionIDApprove and VoteOptionIDReject. Any
-// other vote option IDs will cause this function to panic.
-func voteIsApproved(vd ticketvote.VoteDetails, results []ticketvote.VoteOptionResult) bool {
+// provided quorum and pass percentage requirements. The unrevealed secret
+// ballot votes count toward the quorum, but not toward the pass percentage.
+// This function can only be called on votes that use VoteOptionIDApprove and
+// VoteOptionIDReject. Any other vote option IDs will cause this function to
+// panic.
+func voteIsApproved(vd ticketvote.VoteDetails, results []ticketvote.VoteOptionResult, unrevealed uint64) bool {
 	// Tally the total votes
 	var total uint64
 	for _, v := range results {
@@ -2702,12 +3500,12 @@ func voteIsApproved(vd ticketvote.VoteDetails, results []ticketvote.VoteOptionRe
 	// Check tally against thresholds
 	var approved bool
 	switch {
-	case total < quorum:
+	case total+unrevealed < quorum:
 		// Quorum not met
 		approved = false
 
 		log.Debugf("Quorum not met on %v: votes cast %v, quorum %v",
-			vd.Params.Token, total, quorum)
+			vd.Params.Token, total+unrevealed, quorum)
 
 	case approvedVotes < pass:
 		// Pass percentage not met
@@ -2728,6 +3526,73 @@ func voteIsApproved(vd ticketvote.VoteDetails, results []ticketvote.VoteOptionRe
 	return approved
 }
 
+// voteWinningOption returns the ID of the winning vote option of a multiple
+// choice vote. The winning vote option is the option with the most votes. The
+// quorum requirement is calculated using the votes cast for all vote options
+// and the unrevealed secret ballot votes. If the vote has a pass percentage,
+// the winning vote option must also have received at least the pass
+// percentage of the total revealed votes. An empty string is returned if
+// there is no winner, which includes a tie for the most votes.
+func voteWinningOption(vd ticketvote.VoteDetails, results []ticketvote.VoteOptionResult, unrevealed uint64) string {
+	// Tally the total votes and find the vote option with the most
+	// votes.
+	var (
+		total  uint64
+		winner ticketvote.VoteOptionResult
+		tie    bool
+	)
+	for _, v := range results {
+		total += v.Votes
+		switch {
+		case v.Votes > winner.Votes:
+			winner = v
+			tie = false
+		case v.Votes == winner.Votes:
+			tie = true
+		}
+	}
+
+	// Calculate required thresholds
+	var (
+		eligible   = float64(len(vd.EligibleTickets))
+		quorumPerc = float64(vd.Params.QuorumPercentage)
+		passPerc   = float64(vd.Params.PassPercentage)
+		quorum     = uint64(quorumPerc / 100 * eligible)
+		pass       = uint64(passPerc / 100 * float64(total))
+	)
+
+	// Check tally against thresholds
+	switch {
+	case total == 0:
+		// No votes were cast
+		return ""
+
+	case total+unrevealed < quorum:
+		// Quorum not met
+		log.Debugf("Quorum not met on %v: votes cast %v, quorum %v",
+			vd.Params.Token, total+unrevealed, quorum)
+		return ""
+
+	case tie:
+		// There is a tie for the most votes
+		log.Debugf("Multiple choice vote %v tied: %v votes",
+			vd.Params.Token, winner.Votes)
+		return ""
+
+	case winner.Votes < pass:
+		// Pass percentage not met
+		log.Debugf("Pass threshold not met on %v: option %v votes %v, "+
+			"required %v", vd.Params.Token, winner.ID, winner.Votes, pass)
+		return ""
+	}
+
+	log.Debugf("Multiple choice vote %v winner %v: quorum %v, pass %v, "+
+		"total %v, votes %v", vd.Params.Token, winner.ID, quorum, pass,
+		total, winner.Votes)
+
+	return winner.ID
+}
+
 // tokenDecode decodes a record token and only accepts full length tokens.
 func tokenDecode(token string) ([]byte, error) {
 	return util.TokenDecode(util.TokenTypeTstore, token)
@@ -2930,6 +3795,44 @@ func convertVoteColliderFromBlobEntry(be store.BlobEntry) (*voteCollider, error)
 	return &vc, nil
 }
 
+func convertVoteRevealFromBlobEntry(be store.BlobEntry) (*voteReveal, error) {
+	// Decode and validate data hint
+	b, err := base64.StdEncoding.DecodeString(be.DataHint)
+	if err != nil {
+		return nil, fmt.Errorf("decode DataHint: %v", err)
+	}
+	var dd store.DataDescriptor
+	err = json.Unmarshal(b, &dd)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal DataHint: %v", err)
+	}
+	if dd.Descriptor != dataDescriptorVoteReveal {
+		return nil, fmt.Errorf("unexpected data descriptor: got %v, "+
+			"want %v", dd.Descriptor, dataDescriptorVoteReveal)
+	}
+
+	// Decode data
+	b, err = base64.StdEncoding.DecodeString(be.Data)
+	if err != nil {
+		return nil, fmt.Errorf("decode Data: %v", err)
+	}
+	digest, err := hex.DecodeString(be.Digest)
+	if err != nil {
+		return nil, fmt.Errorf("decode digest: %v", err)
+	}
+	if !bytes.Equal(util.Digest(b), digest) {
+		return nil, fmt.Errorf("data is not coherent; got %x, want %x",
+			util.Digest(b), digest)
+	}
+	var vr voteReveal
+	err = json.Unmarshal(b, &vr)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal voteReveal: %v", err)
+	}
+
+	return &vr, nil
+}
+
 func convertStartRunoffFromBlobEntry(be store.BlobEntry) (*startRunoffRecord, error) {
 	// Decode and validate data hint
 	b, err := base64.StdEncoding.DecodeString(be.DataHint)
@@ -2968,6 +3871,44 @@ func convertStartRunoffFromBlobEntry(be store.BlobEntry) (*startRunoffRecord, er
 	return &srr, nil
 }
 
+func convertCancelDetailsFromBlobEntry(be store.BlobEntry) (*ticketvote.CancelDetails, error) {
+	// Decode and validate data hint
+	b, err := base64.StdEncoding.DecodeString(be.DataHint)
+	if err != nil {
+		return nil, fmt.Errorf("decode DataHint: %v", err)
+	}
+	var dd store.DataDescriptor
+	err = json.Unmarshal(b, &dd)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal DataHint: %v", err)
+	}
+	if dd.Descriptor != dataDescriptorCancelDetails {
+		return nil, fmt.Errorf("unexpected data descriptor: got %v, "+
+			"want %v", dd.Descriptor, dataDescriptorCancelDetails)
+	}
+
+	// Decode data
+	b, err = base64.StdEncoding.DecodeString(be.Data)
+	if err != nil {
+		return nil, fmt.Errorf("decode Data: %v", err)
+	}
+	digest, err := hex.DecodeString(be.Digest)
+	if err != nil {
+		return nil, fmt.Errorf("decode digest: %v", err)
+	}
+	if !bytes.Equal(util.Digest(b), digest) {
+		return nil, fmt.Errorf("data is not coherent; got %x, want %x",
+			util.Digest(b), digest)
+	}
+	var cd ticketvote.CancelDetails
+	err = json.Unmarshal(b, &cd)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal CancelDetails: %v", err)
+	}
+
+	return &cd, nil
+}
+
 func convertBlobEntryFromAuthDetails(ad ticketvote.AuthDetails) (*store.BlobEntry, error) {
 	data, err := json.Marshal(ad)
 	if err != nil {
@@ -3036,6 +3977,23 @@ func convertBlobEntryFromVoteCollider(vc voteCollider) (*store.BlobEntry, error)
 	return &be, nil
 }
 
+func convertBlobEntryFromVoteReveal(vr voteReveal) (*store.BlobEntry, error) {
+	data, err := json.Marshal(vr)
+	if err != nil {
+		return nil, err
+	}
+	hint, err := json.Marshal(
+		store.DataDescriptor{
+			Type:       store.DataTypeStructure,
+			Descriptor: dataDescriptorVoteReveal,
+		})
+	if err != nil {
+		return nil, err
+	}
+	be := store.NewBlobEntry(hint, data)
+	return &be, nil
+}
+
 func convertBlobEntryFromStartRunoff(srr startRunoffRecord) (*store.BlobEntry, error) {
 	data, err := json.Marshal(srr)
 	if err != nil {
@@ -3052,3 +4010,20 @@ func convertBlobEntryFromStartRunoff(srr startRunoffRecord) (*store.BlobEntry, e
 	be := store.NewBlobEntry(hint, data)
 	return &be, nil
 }
+
+func convertBlobEntryFromCancelDetails(cd ticketvote.CancelDetails) (*store.BlobEntry, error) {
+	data, err := json.Marshal(cd)
+	if err != nil {
+		return nil, err
+	}
+	hint, err := json.Marshal(
+		store.DataDescriptor{
+			Type:       store.DataTypeStructure,
+			Descriptor: dataDescriptorCancelDetails,
+		})
+	if err != nil {
+		return nil, err
+	}
+	be := store.NewBlobEntry(hint, data)
+	return &be, nil
+}
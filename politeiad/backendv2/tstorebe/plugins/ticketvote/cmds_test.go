@@ -0,0 +1,800 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/dcrec"
+	"github.com/decred/dcrd/dcrec/secp256k1/v3"
+	"github.com/decred/dcrd/dcrec/secp256k1/v3/ecdsa"
+	"github.com/decred/dcrd/dcrutil/v3"
+	"github.com/decred/dcrd/wire"
+	"github.com/decred/politeia/politeiad/api/v1/identity"
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+)
+
+func TestVoteWinningOption(t *testing.T) {
+	// Setup a vote with 100 eligible tickets
+	vd := func(quorum, pass uint32) ticketvote.VoteDetails {
+		return ticketvote.VoteDetails{
+			Params: ticketvote.VoteParams{
+				Type:             ticketvote.VoteTypeMultipleChoice,
+				QuorumPercentage: quorum,
+				PassPercentage:   pass,
+			},
+			EligibleTickets: make([]string, 100),
+		}
+	}
+	results := func(votes ...uint64) []ticketvote.VoteOptionResult {
+		r := make([]ticketvote.VoteOptionResult, 0, len(votes))
+		for i, v := range votes {
+			r = append(r, ticketvote.VoteOptionResult{
+				ID:      string(rune('a' + i)),
+				VoteBit: 1 << uint(i),
+				Votes:   v,
+			})
+		}
+		return r
+	}
+
+	var tests = []struct {
+		name       string
+		vd         ticketvote.VoteDetails
+		results    []ticketvote.VoteOptionResult
+		unrevealed uint64
+		want       string
+	}{
+		{
+			"no votes",
+			vd(0, 0),
+			results(0, 0, 0),
+			0,
+			"",
+		},
+		{
+			"plurality",
+			vd(20, 0),
+			results(10, 12, 8),
+			0,
+			"b",
+		},
+		{
+			"quorum not met",
+			vd(20, 0),
+			results(5, 6, 4),
+			0,
+			"",
+		},
+		{
+			"quorum met with unrevealed votes",
+			vd(20, 0),
+			results(5, 6, 4),
+			5,
+			"b",
+		},
+		{
+			"tie",
+			vd(20, 0),
+			results(10, 10, 8),
+			0,
+			"",
+		},
+		{
+			"tie broken by a later option",
+			vd(20, 0),
+			results(10, 10, 12),
+			0,
+			"c",
+		},
+		{
+			"threshold met",
+			vd(20, 50),
+			results(20, 10, 10),
+			0,
+			"a",
+		},
+		{
+			"threshold met without unrevealed votes",
+			vd(20, 50),
+			results(20, 10, 10),
+			20,
+			"a",
+		},
+		{
+			"threshold not met",
+			vd(20, 50),
+			results(15, 10, 10),
+			0,
+			"",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := voteWinningOption(tc.vd, tc.results, tc.unrevealed)
+			if got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRunoffBudgetOutcomes(t *testing.T) {
+	var (
+		budget  uint64 = 1000
+		subs           = []string{"a", "b", "c", "d", "e"}
+		amounts        = map[string]uint64{
+			"a": 600,
+			"b": 500,
+			"c": 300,
+			"d": 100,
+			"e": 100,
+		}
+	)
+
+	var tests = []struct {
+		name      string
+		passed    map[string]int
+		want      map[string]ticketvote.BudgetOutcomeT
+		remaining uint64
+	}{
+		{
+			"none passed",
+			map[string]int{},
+			map[string]ticketvote.BudgetOutcomeT{
+				"a": ticketvote.BudgetOutcomeNotPassed,
+				"b": ticketvote.BudgetOutcomeNotPassed,
+				"c": ticketvote.BudgetOutcomeNotPassed,
+				"d": ticketvote.BudgetOutcomeNotPassed,
+				"e": ticketvote.BudgetOutcomeNotPassed,
+			},
+			1000,
+		},
+		{
+			"over budget submission is skipped",
+			map[string]int{"a": 50, "b": 40, "c": 30, "d": 20},
+			map[string]ticketvote.BudgetOutcomeT{
+				"a": ticketvote.BudgetOutcomeFunded,
+				"b": ticketvote.BudgetOutcomeOverBudget,
+				"c": ticketvote.BudgetOutcomeFunded,
+				"d": ticketvote.BudgetOutcomeFunded,
+				"e": ticketvote.BudgetOutcomeNotPassed,
+			},
+			0,
+		},
+		{
+			"tie ordered by token",
+			map[string]int{"b": 40, "a": 40},
+			map[string]ticketvote.BudgetOutcomeT{
+				"a": ticketvote.BudgetOutcomeFunded,
+				"b": ticketvote.BudgetOutcomeOverBudget,
+				"c": ticketvote.BudgetOutcomeNotPassed,
+				"d": ticketvote.BudgetOutcomeNotPassed,
+				"e": ticketvote.BudgetOutcomeNotPassed,
+			},
+			400,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := runoffBudgetOutcomes(budget, amounts, subs, tc.passed)
+			if len(got) != len(subs) {
+				t.Fatalf("got %v outcomes, want %v", len(got), len(subs))
+			}
+			for token, want := range tc.want {
+				rb := got[token]
+				if rb.Outcome != want {
+					t.Errorf("%v: got outcome %v, want %v",
+						token, rb.Outcome, want)
+				}
+				if rb.Total != budget || rb.Amount != amounts[token] {
+					t.Errorf("%v: got total %v amount %v, want %v %v",
+						token, rb.Total, rb.Amount, budget, amounts[token])
+				}
+				if rb.Remaining != tc.remaining {
+					t.Errorf("%v: got remaining %v, want %v",
+						token, rb.Remaining, tc.remaining)
+				}
+			}
+		})
+	}
+}
+
+func TestRevealVerify(t *testing.T) {
+	var (
+		options = []ticketvote.VoteOption{
+			{
+				ID:  ticketvote.VoteOptionIDApprove,
+				Bit: 0x01,
+			},
+			{
+				ID:  ticketvote.VoteOptionIDReject,
+				Bit: 0x02,
+			},
+		}
+		mask       uint64 = 0x03
+		token             = strings.Repeat("0a", 8)
+		ticket            = strings.Repeat("1b", 32)
+		salt              = strings.Repeat("ab", ticketvote.SaltSize)
+		commitment        = ticketvote.VoteCommitment(token, ticket, "1", salt)
+	)
+
+	var tests = []struct {
+		name    string
+		token   string
+		ticket  string
+		voteBit string
+		salt    string
+		want    ticketvote.VoteErrorT
+	}{
+		{
+			"valid reveal",
+			token,
+			ticket,
+			"1",
+			salt,
+			ticketvote.VoteErrorInvalid,
+		},
+		{
+			"vote bit not hex",
+			token,
+			ticket,
+			"z",
+			salt,
+			ticketvote.VoteErrorVoteBitInvalid,
+		},
+		{
+			"vote bit not an option",
+			token,
+			ticket,
+			"4",
+			salt,
+			ticketvote.VoteErrorVoteBitInvalid,
+		},
+		{
+			"salt wrong size",
+			token,
+			ticket,
+			"1",
+			"abab",
+			ticketvote.VoteErrorCommitmentInvalid,
+		},
+		{
+			"vote bit does not match commitment",
+			token,
+			ticket,
+			"2",
+			salt,
+			ticketvote.VoteErrorCommitmentInvalid,
+		},
+		{
+			"salt does not match commitment",
+			token,
+			ticket,
+			"1",
+			strings.Repeat("cd", ticketvote.SaltSize),
+			ticketvote.VoteErrorCommitmentInvalid,
+		},
+		{
+			"token does not match commitment",
+			strings.Repeat("0c", 8),
+			ticket,
+			"1",
+			salt,
+			ticketvote.VoteErrorCommitmentInvalid,
+		},
+		{
+			"ticket does not match commitment",
+			token,
+			strings.Repeat("1d", 32),
+			"1",
+			salt,
+			ticketvote.VoteErrorCommitmentInvalid,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rv := ticketvote.RevealVote{
+				Token:   tc.token,
+				Ticket:  tc.ticket,
+				VoteBit: tc.voteBit,
+				Salt:    tc.salt,
+			}
+			got, err := revealVerify(rv, options, mask, commitment)
+			if got != tc.want {
+				t.Errorf("got error %v, want %v: %v",
+					ticketvote.VoteErrors[got],
+					ticketvote.VoteErrors[tc.want], err)
+			}
+			if (err == nil) != (tc.want == ticketvote.VoteErrorInvalid) {
+				t.Errorf("got err %v, want error code %v",
+					err, tc.want)
+			}
+		})
+	}
+
+	// A ticket can only be revealed once in a reveal ballot. A reveal
+	// that fails verification does not count as the reveal of the
+	// ticket.
+	t.Run("duplicate tickets in reveal", func(t *testing.T) {
+		p, tstore, b, cleanup := newTestTicketVotePlugin(t)
+		defer cleanup()
+
+		rtoken := newTestRecord(t, p, tstore, 1, backend.StatusPublic, nil)
+		token := hex.EncodeToString(rtoken)
+		p.activeVotes.Add(ticketvote.VoteDetails{
+			Params: ticketvote.VoteParams{
+				Token:          token,
+				Version:        1,
+				Type:           ticketvote.VoteTypeStandard,
+				Mask:           mask,
+				Duration:       10,
+				Options:        options,
+				RevealDuration: 20,
+			},
+			StartBlockHeight:     80,
+			EndBlockHeight:       90,
+			RevealEndBlockHeight: 110,
+			EligibleTickets:      b.tickets,
+		})
+		reveal := func(ticket, voteBit, salt string) ticketvote.RevealVote {
+			return ticketvote.RevealVote{
+				Token:   token,
+				Ticket:  ticket,
+				VoteBit: voteBit,
+				Salt:    salt,
+			}
+		}
+		var (
+			t1 = b.tickets[0]
+			t2 = b.tickets[1]
+
+			badSalt = strings.Repeat("cd", ticketvote.SaltSize)
+		)
+		p.activeVotes.AddCommitment(token, t1,
+			ticketvote.VoteCommitment(token, t1, "1", salt))
+		p.activeVotes.AddCommitment(token, t2,
+			ticketvote.VoteCommitment(token, t2, "2", salt))
+
+		r := ticketvote.Reveal{
+			Votes: []ticketvote.RevealVote{
+				reveal(t1, "1", salt),
+				reveal(t1, "1", salt),
+				reveal(t2, "2", badSalt),
+				reveal(t2, "2", salt),
+				reveal(t2, "2", salt),
+			},
+		}
+		want := []ticketvote.VoteErrorT{
+			ticketvote.VoteErrorInvalid,
+			ticketvote.VoteErrorTicketAlreadyVoted,
+			ticketvote.VoteErrorCommitmentInvalid,
+			ticketvote.VoteErrorInvalid,
+			ticketvote.VoteErrorTicketAlreadyVoted,
+		}
+		reply, err := testCmd(t, p, rtoken, ticketvote.CmdReveal, r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var rr ticketvote.RevealReply
+		err = json.Unmarshal([]byte(reply), &rr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k, v := range rr.Receipts {
+			if v.ErrorCode != want[k] {
+				t.Fatalf("reveal %v: got error %v, want %v", k,
+					ticketvote.VoteErrors[v.ErrorCode],
+					ticketvote.VoteErrors[want[k]])
+			}
+		}
+
+		// Only a single reveal of each ticket is saved and tallied
+		blobs, err := tstore.BlobsByDataDesc(rtoken,
+			[]string{dataDescriptorVoteReveal})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(blobs) != 2 {
+			t.Fatalf("got %v saved reveals, want 2", len(blobs))
+		}
+		tally := p.activeVotes.Tally(token)
+		if tally["1"] != 1 || tally["2"] != 1 {
+			t.Fatalf("got tally %v, want 1 vote for each option",
+				tally)
+		}
+	})
+
+	// The hex encoding of a commitment is case insensitive. A vote
+	// that was cast using an uppercase commitment must be revealable
+	// and must be included in the vote results.
+	t.Run("uppercase commitment", func(t *testing.T) {
+		p, tstore, b, cleanup := newTestTicketVotePlugin(t)
+		defer cleanup()
+
+		rtoken := newTestRecord(t, p, tstore, 1, backend.StatusPublic, nil)
+		token := hex.EncodeToString(rtoken)
+		ticket := b.tickets[0]
+		p.activeVotes.Add(ticketvote.VoteDetails{
+			Params: ticketvote.VoteParams{
+				Token:          token,
+				Version:        1,
+				Type:           ticketvote.VoteTypeStandard,
+				Mask:           mask,
+				Duration:       10,
+				Options:        options,
+				RevealDuration: 20,
+			},
+			StartBlockHeight:     95,
+			EndBlockHeight:       105,
+			RevealEndBlockHeight: 125,
+			EligibleTickets:      b.tickets,
+		})
+
+		// Sign the cast vote using the commitment address of the
+		// ticket.
+		commitment := strings.ToUpper(ticketvote.VoteCommitment(token, ticket,
+			"1", salt))
+		addr, sig := testCastVoteSign(t, p, token, ticket, commitment)
+		p.activeVotes.AddCommitmentAddrs(token, map[string]commitmentAddr{
+			ticket: {addr: addr},
+		})
+		cv := ticketvote.CastVote{
+			Token:      token,
+			Ticket:     ticket,
+			Commitment: commitment,
+			Signature:  sig,
+		}
+		reply, err := testCmd(t, p, rtoken, ticketvote.CmdCastBallot,
+			ticketvote.CastBallot{Ballot: []ticketvote.CastVote{cv}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var cbr ticketvote.CastBallotReply
+		err = json.Unmarshal([]byte(reply), &cbr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e := cbr.Receipts[0].ErrorCode; e != ticketvote.VoteErrorInvalid {
+			t.Fatalf("cast vote: got error %v, want none: %v",
+				ticketvote.VoteErrors[e], cbr.Receipts[0].ErrorContext)
+		}
+
+		// Reveal the vote once the voting period has ended
+		b.bestBlockSet(110)
+		reply, err = testCmd(t, p, rtoken, ticketvote.CmdReveal,
+			ticketvote.Reveal{
+				Votes: []ticketvote.RevealVote{
+					{
+						Token:   token,
+						Ticket:  ticket,
+						VoteBit: "1",
+						Salt:    salt,
+					},
+				},
+			})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var rr ticketvote.RevealReply
+		err = json.Unmarshal([]byte(reply), &rr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if e := rr.Receipts[0].ErrorCode; e != ticketvote.VoteErrorInvalid {
+			t.Fatalf("reveal: got error %v, want none: %v",
+				ticketvote.VoteErrors[e], rr.Receipts[0].ErrorContext)
+		}
+
+		// The revealed vote bit is included in the results
+		reply, err = testCmd(t, p, rtoken, ticketvote.CmdResults, "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var res ticketvote.ResultsReply
+		err = json.Unmarshal([]byte(reply), &res)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(res.Votes) != 1 || res.Votes[0].VoteBit != "1" {
+			t.Fatalf("got results %+v, want a single vote for bit 1",
+				res.Votes)
+		}
+	})
+}
+
+// testCastVoteSign signs the cast vote message of a secret ballot vote using a
+// new secp256k1 key. It returns the P2PKH address of the key, which must be
+// used as the commitment address of the ticket, and the hex encoded signature.
+func testCastVoteSign(t *testing.T, p *ticketVotePlugin, token, ticket, commitment string) (string, string) {
+	t.Helper()
+
+	key, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkh := dcrutil.Hash160(key.PubKey().SerializeCompressed())
+	addr, err := dcrutil.NewAddressPubKeyHash(pkh, p.activeNetParams,
+		dcrec.STEcdsaSecp256k1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	wire.WriteVarString(&buf, 0, "Decred Signed Message:\n")
+	wire.WriteVarString(&buf, 0, token+ticket+commitment)
+	sig := ecdsa.SignCompact(key, chainhash.HashB(buf.Bytes()), true)
+
+	return addr.Address(), hex.EncodeToString(sig)
+}
+
+func TestCmdCancel(t *testing.T) {
+	p, tstore, b, cleanup := newTestTicketVotePlugin(t)
+	defer cleanup()
+
+	id, err := identity.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Setup the records. A record can only be censored once it has been
+	// made public. The finished vote ends once the best block is moved
+	// forward.
+	var (
+		public = backend.StatusPublic
+
+		unvetted = newTestRecord(t, p, tstore, 1,
+			backend.StatusUnreviewed, nil)
+		censored     = newTestRecord(t, p, tstore, 2, public, nil)
+		runoff       = newTestRecord(t, p, tstore, 3, public, nil)
+		unauthorized = newTestRecord(t, p, tstore, 4, public, nil)
+		authorized   = newTestRecord(t, p, tstore, 5, public, nil)
+		finished     = newTestRecord(t, p, tstore, 6, public, nil)
+		started      = newTestRecord(t, p, tstore, 7, public, nil)
+	)
+	testSetRecordStatus(t, p, tstore, censored, backend.StatusCensored)
+	err = p.voteDetailsSave(runoff, ticketvote.VoteDetails{
+		Params: ticketvote.VoteParams{
+			Token:    hex.EncodeToString(runoff),
+			Version:  1,
+			Type:     ticketvote.VoteTypeRunoff,
+			Duration: 100,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, token := range [][]byte{authorized, finished, started} {
+		err = testAuthorize(t, p, id, token)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = testStart(t, p, id, finished, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = testStart(t, p, id, started, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.bestBlockSet(150)
+
+	var tests = []struct {
+		name    string
+		token   []byte
+		reason  string
+		wantErr ticketvote.ErrorCodeT
+	}{
+		{
+			"empty reason",
+			started,
+			" ",
+			ticketvote.ErrorCodeCancelReasonInvalid,
+		},
+		{
+			"record unvetted",
+			unvetted,
+			"reason",
+			ticketvote.ErrorCodeRecordStatusInvalid,
+		},
+		{
+			"record censored",
+			censored,
+			"reason",
+			ticketvote.ErrorCodeRecordStatusInvalid,
+		},
+		{
+			"runoff vote",
+			runoff,
+			"reason",
+			ticketvote.ErrorCodeVoteTypeInvalid,
+		},
+		{
+			"vote unauthorized",
+			unauthorized,
+			"reason",
+			ticketvote.ErrorCodeVoteStatusInvalid,
+		},
+		{
+			"vote authorized",
+			authorized,
+			"reason",
+			ticketvote.ErrorCodeVoteStatusInvalid,
+		},
+		{
+			"vote finished",
+			finished,
+			"reason",
+			ticketvote.ErrorCodeVoteStatusInvalid,
+		},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			err := testCancel(t, p, id, v.token, v.reason)
+			var e backend.PluginError
+			if !errors.As(err, &e) {
+				t.Fatalf("got error %v, want PluginError", err)
+			}
+			code := ticketvote.ErrorCodeT(e.ErrorCode)
+			if e.PluginID != ticketvote.PluginID || code != v.wantErr {
+				t.Fatalf("got plugin %v error %v, want %v %v",
+					e.PluginID, ticketvote.ErrorCodes[code],
+					ticketvote.PluginID, ticketvote.ErrorCodes[v.wantErr])
+			}
+		})
+	}
+
+	// Cancel the started vote
+	if p.activeVotes.VoteDetails(started) == nil {
+		t.Fatalf("started vote is not active")
+	}
+	err = testCancel(t, p, id, started, "reason")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Verify that the summary reports the cancelled vote
+	reply, err := testCmd(t, p, started, ticketvote.CmdSummary,
+		ticketvote.Summary{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sr ticketvote.SummaryReply
+	err = json.Unmarshal([]byte(reply), &sr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sr.Status != ticketvote.VoteStatusCancelled {
+		t.Fatalf("got vote status %v, want %v",
+			ticketvote.VoteStatuses[sr.Status],
+			ticketvote.VoteStatuses[ticketvote.VoteStatusCancelled])
+	}
+
+	// The cancelled vote summary will not change, so it is cached the
+	// same way that the summaries of finished votes are.
+	cached, err := p.summaryCache(hex.EncodeToString(started))
+	if err != nil {
+		t.Fatalf("cancelled vote summary was not cached: %v", err)
+	}
+	if cached.Status != ticketvote.VoteStatusCancelled {
+		t.Fatalf("got cached vote status %v, want %v",
+			ticketvote.VoteStatuses[cached.Status],
+			ticketvote.VoteStatuses[ticketvote.VoteStatusCancelled])
+	}
+
+	// Verify that a ballot cast after the cancellation is rejected
+	cb := ticketvote.CastBallot{
+		Ballot: []ticketvote.CastVote{
+			{
+				Token:   hex.EncodeToString(started),
+				Ticket:  b.tickets[0],
+				VoteBit: "1",
+			},
+		},
+	}
+	reply, err = testCmd(t, p, started, ticketvote.CmdCastBallot, cb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var cbr ticketvote.CastBallotReply
+	err = json.Unmarshal([]byte(reply), &cbr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantVoteErr := ticketvote.VoteErrorVoteStatusInvalid
+	if len(cbr.Receipts) != 1 || cbr.Receipts[0].ErrorCode != wantVoteErr {
+		t.Fatalf("got receipts %+v, want error %v", cbr.Receipts,
+			ticketvote.VoteErrors[wantVoteErr])
+	}
+
+	// Verify that a cancelled vote can't be cancelled again
+	err = testCancel(t, p, id, started, "reason")
+	var (
+		e       backend.PluginError
+		wantErr = ticketvote.ErrorCodeVoteStatusInvalid
+	)
+	if !errors.As(err, &e) || ticketvote.ErrorCodeT(e.ErrorCode) != wantErr {
+		t.Fatalf("got error %v, want %v", err, ticketvote.ErrorCodes[wantErr])
+	}
+}
+
+func TestCancelRecovery(t *testing.T) {
+	p, tstore, _, cleanup := newTestTicketVotePlugin(t)
+	defer cleanup()
+
+	id, err := identity.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Start a vote
+	token := newTestRecord(t, p, tstore, 1, backend.StatusPublic, nil)
+	err = testAuthorize(t, p, id, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = testStart(t, p, id, token, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Save the cancel details without updating the inventory. This
+	// is the state that politeiad is left in if it goes down in the
+	// middle of a vote cancellation.
+	err = p.cancelSave(token, ticketvote.CancelDetails{
+		Token:  hex.EncodeToString(token),
+		Reason: "reason",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Verify that the summary reports the cancelled vote
+	sr, err := p.summary(token, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sr.Status != ticketvote.VoteStatusCancelled {
+		t.Fatalf("got vote status %v, want %v",
+			ticketvote.VoteStatuses[sr.Status],
+			ticketvote.VoteStatuses[ticketvote.VoteStatusCancelled])
+	}
+
+	// Rebuild the active votes cache the same way it is rebuilt on
+	// startup and verify that the cancelled vote is not added back
+	// and that the inventory has been repaired.
+	p.activeVotes = newActiveVotes()
+	err = p.Setup()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Close()
+
+	if p.activeVotes.VoteDetails(token) != nil {
+		t.Fatalf("cancelled vote was added to the active votes cache")
+	}
+	inv, err := p.invGet()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range inv.Entries {
+		if v.Token != hex.EncodeToString(token) {
+			continue
+		}
+		if v.Status != ticketvote.VoteStatusCancelled {
+			t.Fatalf("got inventory status %v, want %v",
+				ticketvote.VoteStatuses[v.Status],
+				ticketvote.VoteStatuses[ticketvote.VoteStatusCancelled])
+		}
+	}
+}
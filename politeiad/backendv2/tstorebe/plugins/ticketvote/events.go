@@ -0,0 +1,74 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+)
+
+const (
+	// voteFinishedCheckInterval is how often the plugin checks for
+	// votes that have reached their end height. The vote inventory is
+	// otherwise only updated when it is requested, so without this
+	// check a vote finished event would not be published until the
+	// next inventory request.
+	voteFinishedCheckInterval = time.Minute
+)
+
+// eventVoteFinishedPublish publishes a vote finished event to the backend
+// event stream.
+func (p *ticketVotePlugin) eventVoteFinishedPublish(vf ticketvote.VoteFinished) {
+	token, err := tokenDecode(vf.Token)
+	if err != nil {
+		log.Errorf("eventVoteFinishedPublish: %v", err)
+		return
+	}
+	b, err := json.Marshal(vf)
+	if err != nil {
+		log.Errorf("eventVoteFinishedPublish: %v", err)
+		return
+	}
+	err = p.tstore.PluginEventPublish(token, ticketvote.PluginID,
+		ticketvote.EventVoteFinished, string(b))
+	if err != nil {
+		log.Errorf("eventVoteFinishedPublish %v: %v", vf.Token, err)
+		return
+	}
+
+	log.Infof("Vote finished %v %v", vf.Token,
+		ticketvote.VoteStatuses[vf.Summary.Status])
+}
+
+// voteFinishedMonitor periodically updates the vote inventory for the best
+// block so that the vote finished events are published once the vote end
+// height has been reached. This function should be run in a go routine. It
+// returns once the plugin has been closed.
+func (p *ticketVotePlugin) voteFinishedMonitor() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(voteFinishedCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+		}
+
+		bestBlock, err := p.bestBlock()
+		if err != nil {
+			log.Debugf("voteFinishedMonitor: bestBlock: %v", err)
+			continue
+		}
+		_, err = p.Inventory(bestBlock)
+		if err != nil {
+			log.Errorf("voteFinishedMonitor: Inventory: %v", err)
+		}
+	}
+}
@@ -86,6 +86,57 @@ func (p *ticketVotePlugin) hookSetRecordStatusPost(payload string) error {
 		srs.RecordMetadata.State, srs.RecordMetadata.Status, srs.Record.Files)
 }
 
+// hookRecordImportPost builds the ticketvote caches of a record that has been
+// imported. The imported record is not added to the active votes cache. The
+// cast votes of its vote reference the token of the exported record.
+func (p *ticketVotePlugin) hookRecordImportPost(payload string) error {
+	var ri plugins.HookRecordImportPost
+	err := json.Unmarshal([]byte(payload), &ri)
+	if err != nil {
+		return err
+	}
+	rm := ri.Record.RecordMetadata
+
+	// Ticketvote caches only need to be built for vetted records
+	if rm.State != backend.StateVetted {
+		return nil
+	}
+	token, err := tokenDecode(rm.Token)
+	if err != nil {
+		return err
+	}
+
+	// Add the record to the inventory. The vote summary is cached
+	// as a side effect if the vote has finished.
+	bestBlock, err := p.bestBlock()
+	if err != nil {
+		return fmt.Errorf("bestBlock: %v", err)
+	}
+	e, _, err := p.invEntryBuild(token, rm, bestBlock)
+	if err != nil {
+		return err
+	}
+	err = p.invAdd(e.Token, e.Status, e.EndHeight)
+	if err != nil {
+		return fmt.Errorf("invAdd: %v", err)
+	}
+
+	// Add the record to the submissions list of the record that it
+	// links to. The link to token is the token that was used when
+	// the record was submitted.
+	if rm.Status == backend.StatusCensored {
+		return nil
+	}
+	vm, err := voteMetadataDecode(ri.Record.Files)
+	if err != nil {
+		return err
+	}
+	if vm == nil || vm.LinkTo == "" {
+		return nil
+	}
+	return p.submissionsCacheAdd(vm.LinkTo, rm.Token)
+}
+
 // linkByVerify verifies that the provided link by timestamp meets all
 // ticketvote plugin requirements. See the ticketvote VoteMetadata structure
 // for more details on the link by timestamp.
@@ -96,8 +147,9 @@ func (p *ticketVotePlugin) linkByVerify(linkBy int64) error {
 	}
 
 	// Min and max link by periods are a ticketvote plugin setting
-	min := time.Now().Unix() + p.linkByPeriodMin
-	max := time.Now().Unix() + p.linkByPeriodMax
+	settings := p.settingsGet()
+	min := time.Now().Unix() + settings.linkByPeriodMin
+	max := time.Now().Unix() + settings.linkByPeriodMax
 	switch {
 	case linkBy < min:
 		return backend.PluginError{
@@ -276,6 +328,75 @@ func (p *ticketVotePlugin) linkToVerifyOnStatusChange(status backend.StatusT, vm
 	return nil
 }
 
+// budgetVerify verifies that the runoff vote budget fields of the provided
+// vote metadata meet the ticketvote plugin requirements. A budget can only be
+// set on a runoff vote parent record and a requested amount can only be set
+// on a runoff vote submission. A submission must request an amount if, and
+// only if, the parent record has set a budget. The requested amount cannot
+// exceed the budget.
+func (p *ticketVotePlugin) budgetVerify(vm ticketvote.VoteMetadata) error {
+	switch {
+	case vm.Budget != 0 && vm.LinkBy == 0:
+		return backend.PluginError{
+			PluginID:     ticketvote.PluginID,
+			ErrorCode:    uint32(ticketvote.ErrorCodeVoteMetadataInvalid),
+			ErrorContext: "budget can only be set on a runoff vote parent",
+		}
+	case vm.Amount != 0 && vm.LinkTo == "":
+		return backend.PluginError{
+			PluginID:     ticketvote.PluginID,
+			ErrorCode:    uint32(ticketvote.ErrorCodeVoteMetadataInvalid),
+			ErrorContext: "amount can only be set on a runoff vote submission",
+		}
+	case vm.LinkTo == "":
+		// Not a runoff vote submission. Nothing else to do.
+		return nil
+	}
+
+	// Get the parent record budget
+	token, err := tokenDecode(vm.LinkTo)
+	if err != nil {
+		return err
+	}
+	r, err := p.recordAbridged(token)
+	if err != nil {
+		return err
+	}
+	parentVM, err := voteMetadataDecode(r.Files)
+	if err != nil {
+		return err
+	}
+	if parentVM == nil {
+		return fmt.Errorf("vote metadata does not exist on parent record %v",
+			vm.LinkTo)
+	}
+
+	// Verify the requested amount
+	switch {
+	case parentVM.Budget == 0 && vm.Amount != 0:
+		return backend.PluginError{
+			PluginID:     ticketvote.PluginID,
+			ErrorCode:    uint32(ticketvote.ErrorCodeVoteMetadataInvalid),
+			ErrorContext: "parent record has not set a budget",
+		}
+	case parentVM.Budget != 0 && vm.Amount == 0:
+		return backend.PluginError{
+			PluginID:     ticketvote.PluginID,
+			ErrorCode:    uint32(ticketvote.ErrorCodeVoteMetadataInvalid),
+			ErrorContext: "amount is required by the parent record budget",
+		}
+	case vm.Amount > parentVM.Budget:
+		return backend.PluginError{
+			PluginID:  ticketvote.PluginID,
+			ErrorCode: uint32(ticketvote.ErrorCodeVoteMetadataInvalid),
+			ErrorContext: fmt.Sprintf("amount exceeds the parent record "+
+				"budget: got %v, max %v", vm.Amount, parentVM.Budget),
+		}
+	}
+
+	return nil
+}
+
 // voteMetadataVerify decodes the VoteMetadata from the provided files and
 // verifies that it meets the ticketvote plugin requirements. Vote metadata is
 // optional so one may not exist.
@@ -324,7 +445,8 @@ func (p *ticketVotePlugin) voteMetadataVerify(files []backend.File) error {
 		}
 	}
 
-	return nil
+	// Verify the runoff vote budget fields
+	return p.budgetVerify(*vm)
 }
 
 // voteMetadataVerifyOnEdits runs vote metadata validation that is specific to
@@ -354,6 +476,12 @@ func (p *ticketVotePlugin) voteMetadataVerifyOnEdits(r backend.Record, newFiles
 		return err
 	}
 
+	// Verify the runoff vote budget fields
+	err = p.budgetVerify(*vm)
+	if err != nil {
+		return err
+	}
+
 	// The LinkTo does not need to be validated since we have already
 	// confirmed that it has not changed from the previous record
 	// version and it would have already been validated when the record
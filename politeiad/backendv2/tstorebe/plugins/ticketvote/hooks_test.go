@@ -0,0 +1,98 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/decred/politeia/politeiad/api/v1/identity"
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+)
+
+func TestHookRecordImportPost(t *testing.T) {
+	p, tstore, b, cleanup := newTestTicketVotePlugin(t)
+	defer cleanup()
+
+	id, err := identity.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Setup a source record that is a runoff submission and that has
+	// a finished vote. The source record remains in the backend when
+	// it is imported.
+	var (
+		parent = newTestRecord(t, p, tstore, 1, backend.StatusPublic,
+			&ticketvote.VoteMetadata{LinkBy: 1})
+		source = newTestRecord(t, p, tstore, 2, backend.StatusPublic,
+			&ticketvote.VoteMetadata{LinkTo: hex.EncodeToString(parent)})
+	)
+	err = testAuthorize(t, p, id, source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = testStart(t, p, id, source, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.bestBlockSet(150)
+	bestBlock, err := p.bestBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := p.summary(source, bestBlock)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	imported := testRecordImport(t, p, tstore, source, 3)
+
+	// The vote summary of the imported record must be cached using
+	// the token of the imported record.
+	sr, err := p.summaryCache(hex.EncodeToString(imported))
+	if err != nil {
+		t.Fatalf("summaryCache: %v", err)
+	}
+	if !reflect.DeepEqual(sr, want) {
+		t.Fatalf("got summary %+v, want %+v", sr, want)
+	}
+
+	// Both records must be in the inventory
+	inv, err := p.Inventory(bestBlock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statuses := make(map[string]ticketvote.VoteStatusT, len(inv.Entries))
+	for _, v := range inv.Entries {
+		statuses[v.Token] = v.Status
+	}
+	for _, token := range [][]byte{source, imported} {
+		s := statuses[hex.EncodeToString(token)]
+		if s != want.Status {
+			t.Fatalf("%x: got inventory status %v, want %v",
+				token, ticketvote.VoteStatuses[s],
+				ticketvote.VoteStatuses[want.Status])
+		}
+	}
+
+	// Both records must be in the submissions list of the parent
+	reply, err := p.cmdSubmissions(parent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var subs ticketvote.SubmissionsReply
+	err = json.Unmarshal([]byte(reply), &subs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(subs.Submissions) != 2 {
+		t.Fatalf("got submissions %v, want %x and %x",
+			subs.Submissions, source, imported)
+	}
+}
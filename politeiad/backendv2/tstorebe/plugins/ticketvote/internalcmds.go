@@ -36,6 +36,12 @@ type startRunoffRecord struct {
 	StartBlockHash   string   `json:"startblockhash"`
 	EndBlockHeight   uint32   `json:"endblockheight"`
 	EligibleTickets  []string `json:"eligibletickets"`
+
+	// Budget and Amounts are only populated when the runoff vote parent
+	// has set a budget. Amounts contains the amount that was requested
+	// by each submission at the time the runoff vote was started.
+	Budget  uint64            `json:"budget,omitempty"`
+	Amounts map[string]uint64 `json:"amounts,omitempty"` // [token]amount
 }
 
 // startRunoffSubmission is an internal plugin command that is used to start
@@ -13,6 +13,7 @@ import (
 	"path/filepath"
 	"sort"
 
+	backend "github.com/decred/politeia/politeiad/backendv2"
 	"github.com/decred/politeia/politeiad/plugins/ticketvote"
 )
 
@@ -30,15 +31,35 @@ type entry struct {
 }
 
 // inventory contains the ticketvote inventory. The unauthorized, authorized,
-// and started lists are updated in real-time since ticket vote plugin commands
-// or hooks initiate those actions. The finished, approved, and rejected
-// statuses are lazy loaded since those lists depends on external state (DCR
-// block height).
+// started, and cancelled lists are updated in real-time since ticket vote
+// plugin commands or hooks initiate those actions. The finished, approved,
+// and rejected statuses are lazy loaded since those lists depends on external
+// state (DCR block height).
 type inventory struct {
 	Entries   []entry `json:"entries"`
 	BestBlock uint32  `json:"bestblock"`
 }
 
+// invAttributeVoteStatus is the backend inventory attribute that contains the
+// vote status of a record. The backend inventory can be filtered by vote status
+// using the namespaced attribute key, i.e. ticketvote.votestatus. The finished,
+// approved, and rejected statuses are lazy loaded the same way they are for the
+// ticketvote inventory.
+const invAttributeVoteStatus = "votestatus"
+
+// invAttributeSet sets the vote status backend inventory attribute of a
+// record.
+func (p *ticketVotePlugin) invAttributeSet(token string, s ticketvote.VoteStatusT) error {
+	t, err := tokenDecode(token)
+	if err != nil {
+		return err
+	}
+	return p.tstore.InventoryAttributesSet(t, ticketvote.PluginID,
+		map[string]string{
+			invAttributeVoteStatus: ticketvote.VoteStatuses[s],
+		})
+}
+
 // invPath returns the full path for the cached ticket vote inventory.
 func (p *ticketVotePlugin) invPath() string {
 	return filepath.Join(p.dataDir, filenameInventory)
@@ -93,10 +114,11 @@ func (p *ticketVotePlugin) invSaveLocked(inv inventory) error {
 	return ioutil.WriteFile(p.invPath(), b, 0664)
 }
 
-// invAdd adds a token to the ticketvote inventory.
+// invAdd adds a token to the ticketvote inventory. The end height is only
+// required for records with a started vote.
 //
 // This function must be called WITHOUT the mtxInv write lock held.
-func (p *ticketVotePlugin) invAdd(token string, s ticketvote.VoteStatusT) error {
+func (p *ticketVotePlugin) invAdd(token string, s ticketvote.VoteStatusT, endHeight uint32) error {
 	p.mtxInv.Lock()
 	defer p.mtxInv.Unlock()
 
@@ -108,8 +130,9 @@ func (p *ticketVotePlugin) invAdd(token string, s ticketvote.VoteStatusT) error
 
 	// Prepend token
 	e := entry{
-		Token:  token,
-		Status: s,
+		Token:     token,
+		Status:    s,
+		EndHeight: endHeight,
 	}
 	inv.Entries = append([]entry{e}, inv.Entries...)
 
@@ -119,6 +142,12 @@ func (p *ticketVotePlugin) invAdd(token string, s ticketvote.VoteStatusT) error
 		return err
 	}
 
+	// Update the backend inventory attribute
+	err = p.invAttributeSet(token, s)
+	if err != nil {
+		return err
+	}
+
 	log.Debugf("Vote inv add %v %v", token, ticketvote.VoteStatuses[s])
 
 	return nil
@@ -127,7 +156,7 @@ func (p *ticketVotePlugin) invAdd(token string, s ticketvote.VoteStatusT) error
 // inventoryAdd is a wrapper around the invAdd method that allows us to decide
 // how disk read/write errors should be handled. For now we just panic.
 func (p *ticketVotePlugin) inventoryAdd(token string, s ticketvote.VoteStatusT) {
-	err := p.invAdd(token, s)
+	err := p.invAdd(token, s, 0)
 	if err != nil {
 		panic(fmt.Sprintf("invAdd %v %v: %v", token, s, err))
 	}
@@ -165,6 +194,12 @@ func (p *ticketVotePlugin) invUpdateLocked(token string, s ticketvote.VoteStatus
 		return err
 	}
 
+	// Update the backend inventory attribute
+	err = p.invAttributeSet(token, s)
+	if err != nil {
+		return err
+	}
+
 	log.Debugf("Vote inv update %v to %v", token, ticketvote.VoteStatuses[s])
 
 	return nil
@@ -225,19 +260,28 @@ func (p *ticketVotePlugin) invUpdateForBlock(bestBlock uint32) (*inventory, erro
 	})
 
 	// Update the inventory for the ended entries
+	finished := make([]ticketvote.VoteFinished, 0, len(ended))
 	for _, v := range ended {
 		// Get the vote summary
 		token, err := tokenDecode(v.Token)
 		if err != nil {
 			return nil, err
 		}
-		sr, err := p.summaryByToken(token)
+		sr, err := p.summary(token, bestBlock)
 		if err != nil {
 			return nil, err
 		}
 
 		// Update inventory
 		switch sr.Status {
+		case ticketvote.VoteStatusCancelled:
+			// The vote was cancelled but politeiad went down before
+			// the inventory was updated. A cancelled vote does not
+			// publish a vote finished event.
+			err := p.invUpdateLocked(v.Token, sr.Status, 0)
+			if err != nil {
+				return nil, err
+			}
 		case ticketvote.VoteStatusFinished, ticketvote.VoteStatusApproved,
 			ticketvote.VoteStatusRejected:
 			// These statuses are allowed
@@ -245,6 +289,10 @@ func (p *ticketVotePlugin) invUpdateForBlock(bestBlock uint32) (*inventory, erro
 			if err != nil {
 				return nil, err
 			}
+			finished = append(finished, ticketvote.VoteFinished{
+				Token:   v.Token,
+				Summary: *sr,
+			})
 		default:
 			return nil, fmt.Errorf("unexpected vote status %v %v",
 				v.Token, sr.Status)
@@ -266,6 +314,11 @@ func (p *ticketVotePlugin) invUpdateForBlock(bestBlock uint32) (*inventory, erro
 
 	log.Debugf("Vote inv updated for block %v", bestBlock)
 
+	// Publish the finished votes to the backend event stream
+	for _, v := range finished {
+		p.eventVoteFinishedPublish(v)
+	}
+
 	return inv, nil
 }
 
@@ -328,6 +381,8 @@ func (p *ticketVotePlugin) invByStatusAll(bestBlock, pageSize uint32) (*invBySta
 			pageSize, 1)
 		ineligible = tokensParse(i.Entries, ticketvote.VoteStatusIneligible,
 			pageSize, 1)
+		cancelled = tokensParse(i.Entries, ticketvote.VoteStatusCancelled,
+			pageSize, 1)
 
 		tokens = make(map[ticketvote.VoteStatusT][]string, 16)
 	)
@@ -352,6 +407,9 @@ func (p *ticketVotePlugin) invByStatusAll(bestBlock, pageSize uint32) (*invBySta
 	if len(ineligible) != 0 {
 		tokens[ticketvote.VoteStatusIneligible] = ineligible
 	}
+	if len(cancelled) != 0 {
+		tokens[ticketvote.VoteStatusCancelled] = cancelled
+	}
 
 	return &invByStatus{
 		Tokens:    tokens,
@@ -438,3 +496,138 @@ func tokensParse(entries []entry, s ticketvote.VoteStatusT, countPerPage, page u
 
 	return tokens
 }
+
+// invRebuild rebuilds the ticketvote inventory and the vote summaries cache
+// from the ticketvote data of the provided records. The vote status of each
+// vetted record is derived from its vote summary. The vote summaries of
+// finished votes are cached as a side effect of retrieving them.
+//
+// The inventory entries are ordered by the time of their most recent vote
+// status change from newest to oldest. The time is approximated using the
+// record timestamp, the authorization timestamp, the cancellation timestamp,
+// or the vote start and end heights, depending on the vote status. Only the
+// relative order of entries that share a vote status is meaningful.
+//
+// This function must be called WITHOUT the mtxInv write lock held.
+func (p *ticketVotePlugin) invRebuild(tokens [][]byte, progress func()) error {
+	bestBlock, err := p.bestBlock()
+	if err != nil {
+		return fmt.Errorf("bestBlock: %v", err)
+	}
+
+	type invEntry struct {
+		entry
+		changed int64 // Approximate time of the status change
+	}
+	entries := make([]invEntry, 0, len(tokens))
+	for _, token := range tokens {
+		r, err := p.tstore.RecordPartial(token, 0, nil, true)
+		if err != nil {
+			return fmt.Errorf("RecordPartial %x: %v", token, err)
+		}
+		rm := r.RecordMetadata
+		if rm.State != backend.StateVetted {
+			// Only vetted records are part of the inventory
+			progress()
+			continue
+		}
+		e, changed, err := p.invEntryBuild(token, rm, bestBlock)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, invEntry{
+			entry:   *e,
+			changed: changed,
+		})
+
+		progress()
+	}
+
+	// Sort the entries from newest to oldest
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].changed > entries[j].changed
+	})
+	inv := inventory{
+		Entries:   make([]entry, 0, len(entries)),
+		BestBlock: bestBlock,
+	}
+	for _, v := range entries {
+		inv.Entries = append(inv.Entries, v.entry)
+	}
+
+	// Save the inventory and update the backend inventory attributes
+	p.mtxInv.Lock()
+	defer p.mtxInv.Unlock()
+
+	err = p.invSaveLocked(inv)
+	if err != nil {
+		return err
+	}
+	for _, v := range inv.Entries {
+		err = p.invAttributeSet(v.Token, v.Status)
+		if err != nil {
+			return fmt.Errorf("invAttributeSet %v: %v", v.Token, err)
+		}
+	}
+
+	log.Infof("Vote inv rebuilt for block %v: %v records",
+		bestBlock, len(inv.Entries))
+
+	return nil
+}
+
+// invEntryBuild builds the inventory entry of a vetted record from the
+// ticketvote data of the record. The approximate time of the most recent vote
+// status change is also returned. See invRebuild for more details.
+func (p *ticketVotePlugin) invEntryBuild(token []byte, rm backend.RecordMetadata, bestBlock uint32) (*entry, int64, error) {
+	var (
+		e = entry{
+			Token: rm.Token,
+		}
+		changed = rm.Timestamp
+	)
+	switch rm.Status {
+	case backend.StatusCensored, backend.StatusArchived:
+		// These statuses do not allow for a vote
+		e.Status = ticketvote.VoteStatusIneligible
+
+	default:
+		// The inventory entry is being built from scratch, so the
+		// cancel details are used to determine whether the vote has
+		// been cancelled.
+		cd, err := p.cancelDetails(token)
+		if err != nil {
+			return nil, 0, fmt.Errorf("cancelDetails %x: %v", token, err)
+		}
+		sr, err := p.summaryBuild(token, bestBlock, cd != nil)
+		if err != nil {
+			return nil, 0, fmt.Errorf("summary %x: %v", token, err)
+		}
+		e.Status = sr.Status
+		switch sr.Status {
+		case ticketvote.VoteStatusAuthorized:
+			auths, err := p.auths(token)
+			if err != nil {
+				return nil, 0, fmt.Errorf("auths %x: %v", token, err)
+			}
+			if len(auths) > 0 {
+				changed = auths[len(auths)-1].Timestamp
+			}
+		case ticketvote.VoteStatusStarted:
+			e.EndHeight = sr.EndBlockHeight
+			if sr.RevealEndBlockHeight != 0 {
+				// Secret ballot votes finish once the reveal
+				// window has ended.
+				e.EndHeight = sr.RevealEndBlockHeight
+			}
+			changed = int64(sr.StartBlockHeight)
+		case ticketvote.VoteStatusFinished, ticketvote.VoteStatusApproved,
+			ticketvote.VoteStatusRejected:
+			changed = int64(sr.EndBlockHeight)
+		case ticketvote.VoteStatusCancelled:
+			changed = cd.Timestamp
+		}
+	}
+
+	return &e, changed, nil
+}
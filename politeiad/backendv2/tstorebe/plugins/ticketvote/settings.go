@@ -0,0 +1,121 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+)
+
+// pluginSettings contains the ticketvote plugin settings.
+type pluginSettings struct {
+	linkByPeriodMin int64  // In seconds
+	linkByPeriodMax int64  // In seconds
+	voteDurationMin uint32 // In blocks
+	voteDurationMax uint32 // In blocks
+}
+
+// settingsDefault returns the default plugin settings for the provided
+// network.
+func settingsDefault(activeNetParams *chaincfg.Params) (*pluginSettings, error) {
+	switch activeNetParams.Name {
+	case chaincfg.MainNetParams().Name:
+		return &pluginSettings{
+			linkByPeriodMin: ticketvote.SettingMainNetLinkByPeriodMin,
+			linkByPeriodMax: ticketvote.SettingMainNetLinkByPeriodMax,
+			voteDurationMin: ticketvote.SettingMainNetVoteDurationMin,
+			voteDurationMax: ticketvote.SettingMainNetVoteDurationMax,
+		}, nil
+	case chaincfg.TestNet3Params().Name, chaincfg.SimNetParams().Name:
+		// Simnet uses the testnet defaults
+		return &pluginSettings{
+			linkByPeriodMin: ticketvote.SettingTestNetLinkByPeriodMin,
+			linkByPeriodMax: ticketvote.SettingTestNetLinkByPeriodMax,
+			voteDurationMin: ticketvote.SettingTestNetVoteDurationMin,
+			voteDurationMax: ticketvote.SettingTestNetVoteDurationMax,
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown active net: %v", activeNetParams.Name)
+}
+
+// settingsGet returns the plugin settings. The settings can be updated at
+// runtime so they must be read using this function.
+func (p *ticketVotePlugin) settingsGet() pluginSettings {
+	p.mtxSettings.RLock()
+	defer p.mtxSettings.RUnlock()
+
+	return p.settings
+}
+
+// settingsParse applies the provided plugin settings to the existing settings
+// and returns the result. A backend PluginSettingError is returned if any of
+// the settings are invalid.
+func settingsParse(s pluginSettings, ss []backend.PluginSetting) (pluginSettings, error) {
+	var linkBy, duration *backend.PluginSetting
+	for i, v := range ss {
+		switch v.Key {
+		case ticketvote.SettingKeyLinkByPeriodMin,
+			ticketvote.SettingKeyLinkByPeriodMax:
+			i64, err := strconv.ParseInt(v.Value, 10, 64)
+			if err != nil {
+				return s, settingError(v, err.Error())
+			}
+			if i64 <= 0 {
+				return s, settingError(v, "must be greater than zero")
+			}
+			if v.Key == ticketvote.SettingKeyLinkByPeriodMin {
+				s.linkByPeriodMin = i64
+			} else {
+				s.linkByPeriodMax = i64
+			}
+			linkBy = &ss[i]
+
+		case ticketvote.SettingKeyVoteDurationMin,
+			ticketvote.SettingKeyVoteDurationMax:
+			u, err := strconv.ParseUint(v.Value, 10, 32)
+			if err != nil {
+				return s, settingError(v, err.Error())
+			}
+			if u == 0 {
+				return s, settingError(v, "must be greater than zero")
+			}
+			if v.Key == ticketvote.SettingKeyVoteDurationMin {
+				s.voteDurationMin = uint32(u)
+			} else {
+				s.voteDurationMax = uint32(u)
+			}
+			duration = &ss[i]
+
+		default:
+			return s, settingError(v, "unknown setting")
+		}
+	}
+
+	// Verify the min and max settings are coherent
+	if linkBy != nil && s.linkByPeriodMin > s.linkByPeriodMax {
+		return s, settingError(*linkBy, fmt.Sprintf("link by period min %v "+
+			"exceeds max %v", s.linkByPeriodMin, s.linkByPeriodMax))
+	}
+	if duration != nil && s.voteDurationMin > s.voteDurationMax {
+		return s, settingError(*duration, fmt.Sprintf("vote duration min %v "+
+			"exceeds max %v", s.voteDurationMin, s.voteDurationMax))
+	}
+
+	return s, nil
+}
+
+// settingError returns a backend PluginSettingError for the provided setting.
+func settingError(s backend.PluginSetting, reason string) error {
+	return backend.PluginSettingError{
+		PluginID: ticketvote.PluginID,
+		Key:      s.Key,
+		Value:    s.Value,
+		Reason:   reason,
+	}
+}
@@ -0,0 +1,169 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+)
+
+func TestSettingsUpdate(t *testing.T) {
+	s, err := settingsDefault(chaincfg.TestNet3Params())
+	if err != nil {
+		t.Fatal(err)
+	}
+	setting := func(key, value string) backend.PluginSetting {
+		return backend.PluginSetting{
+			Key:   key,
+			Value: value,
+		}
+	}
+
+	// Setup the rejected settings. The key of the setting that the
+	// error is expected to reference is included with each test.
+	var tests = []struct {
+		name     string
+		settings []backend.PluginSetting
+		wantKey  string
+	}{
+		{
+			"unknown setting",
+			[]backend.PluginSetting{
+				setting("unknown", "1"),
+			},
+			"unknown",
+		},
+		{
+			"link by period min not a number",
+			[]backend.PluginSetting{
+				setting(ticketvote.SettingKeyLinkByPeriodMin, "1d"),
+			},
+			ticketvote.SettingKeyLinkByPeriodMin,
+		},
+		{
+			"link by period min zero",
+			[]backend.PluginSetting{
+				setting(ticketvote.SettingKeyLinkByPeriodMin, "0"),
+			},
+			ticketvote.SettingKeyLinkByPeriodMin,
+		},
+		{
+			"link by period max negative",
+			[]backend.PluginSetting{
+				setting(ticketvote.SettingKeyLinkByPeriodMax, "-1"),
+			},
+			ticketvote.SettingKeyLinkByPeriodMax,
+		},
+		{
+			"link by period min exceeds max",
+			[]backend.PluginSetting{
+				setting(ticketvote.SettingKeyLinkByPeriodMin, "7776001"),
+			},
+			ticketvote.SettingKeyLinkByPeriodMin,
+		},
+		{
+			"link by period max below min",
+			[]backend.PluginSetting{
+				setting(ticketvote.SettingKeyLinkByPeriodMin, "100"),
+				setting(ticketvote.SettingKeyLinkByPeriodMax, "99"),
+			},
+			ticketvote.SettingKeyLinkByPeriodMax,
+		},
+		{
+			"vote duration min zero",
+			[]backend.PluginSetting{
+				setting(ticketvote.SettingKeyVoteDurationMin, "0"),
+			},
+			ticketvote.SettingKeyVoteDurationMin,
+		},
+		{
+			"vote duration max not a number",
+			[]backend.PluginSetting{
+				setting(ticketvote.SettingKeyVoteDurationMax, "0x10"),
+			},
+			ticketvote.SettingKeyVoteDurationMax,
+		},
+		{
+			"vote duration max negative",
+			[]backend.PluginSetting{
+				setting(ticketvote.SettingKeyVoteDurationMax, "-1"),
+			},
+			ticketvote.SettingKeyVoteDurationMax,
+		},
+		{
+			"vote duration max overflows uint32",
+			[]backend.PluginSetting{
+				setting(ticketvote.SettingKeyVoteDurationMax, "4294967296"),
+			},
+			ticketvote.SettingKeyVoteDurationMax,
+		},
+		{
+			"vote duration max below min",
+			[]backend.PluginSetting{
+				setting(ticketvote.SettingKeyVoteDurationMin, "100"),
+				setting(ticketvote.SettingKeyVoteDurationMax, "99"),
+			},
+			ticketvote.SettingKeyVoteDurationMax,
+		},
+		{
+			"vote duration min exceeds max",
+			[]backend.PluginSetting{
+				setting(ticketvote.SettingKeyVoteDurationMin, "4033"),
+			},
+			ticketvote.SettingKeyVoteDurationMin,
+		},
+		{
+			"valid setting followed by an invalid setting",
+			[]backend.PluginSetting{
+				setting(ticketvote.SettingKeyVoteDurationMax, "100"),
+				setting(ticketvote.SettingKeyLinkByPeriodMax, "0"),
+			},
+			ticketvote.SettingKeyLinkByPeriodMax,
+		},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			p := ticketVotePlugin{
+				settings: *s,
+			}
+			err := p.SettingsUpdate(v.settings)
+			var e backend.PluginSettingError
+			if !errors.As(err, &e) {
+				t.Fatalf("got error %v, want PluginSettingError", err)
+			}
+			if e.PluginID != ticketvote.PluginID || e.Key != v.wantKey {
+				t.Fatalf("got plugin %v key %v, want %v %v",
+					e.PluginID, e.Key, ticketvote.PluginID, v.wantKey)
+			}
+
+			// None of the settings are updated
+			if p.settingsGet() != *s {
+				t.Fatalf("got settings %+v, want %+v", p.settingsGet(), *s)
+			}
+		})
+	}
+
+	// Verify that a valid update is applied
+	p := ticketVotePlugin{
+		settings: *s,
+	}
+	err = p.SettingsUpdate([]backend.PluginSetting{
+		setting(ticketvote.SettingKeyVoteDurationMin, "10"),
+		setting(ticketvote.SettingKeyVoteDurationMax, "20"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := *s
+	want.voteDurationMin = 10
+	want.voteDurationMax = 20
+	if p.settingsGet() != want {
+		t.Fatalf("got settings %+v, want %+v", p.settingsGet(), want)
+	}
+}
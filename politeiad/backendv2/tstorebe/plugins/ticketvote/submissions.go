@@ -7,11 +7,14 @@ package ticketvote
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
 
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
 	"github.com/decred/politeia/util"
 )
 
@@ -179,3 +182,65 @@ func (p *ticketVotePlugin) submissionsCacheDel(parentToken, childToken string) e
 
 	return nil
 }
+
+// submissionsDel deletes all cached submissions lists from the plugin data
+// dir.
+//
+// This function must be called WITHOUT the mtxSubs lock held.
+func (p *ticketVotePlugin) submissionsDel() error {
+	p.mtxSubs.Lock()
+	defer p.mtxSubs.Unlock()
+
+	pattern := strings.Replace(fnSubmissions, "{shorttoken}", "*", 1)
+	files, err := filepath.Glob(filepath.Join(p.dataDir, pattern))
+	if err != nil {
+		return err
+	}
+	for _, v := range files {
+		err = os.Remove(v)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// submissionsRebuild deletes all cached submissions lists and rebuilds them
+// from the vote metadata of the provided records. A record is included in
+// the submissions list of the record that it links to if it has been made
+// public and has not been censored.
+//
+// This function must be called WITHOUT the mtxSubs lock held.
+func (p *ticketVotePlugin) submissionsRebuild(tokens [][]byte) error {
+	err := p.submissionsDel()
+	if err != nil {
+		return err
+	}
+
+	for _, token := range tokens {
+		r, err := p.tstore.RecordPartial(token, 0,
+			[]string{ticketvote.FileNameVoteMetadata}, false)
+		if err != nil {
+			return fmt.Errorf("RecordPartial %x: %v", token, err)
+		}
+		rm := r.RecordMetadata
+		if rm.State != backend.StateVetted ||
+			rm.Status == backend.StatusCensored {
+			continue
+		}
+		vm, err := voteMetadataDecode(r.Files)
+		if err != nil {
+			return err
+		}
+		if vm == nil || vm.LinkTo == "" {
+			continue
+		}
+		err = p.submissionsCacheAdd(vm.LinkTo, rm.Token)
+		if err != nil {
+			return fmt.Errorf("submissionsCacheAdd: %v", err)
+		}
+	}
+
+	return nil
+}
@@ -96,3 +96,25 @@ func (p *ticketVotePlugin) summaryCacheSave(token string, sr ticketvote.SummaryR
 
 	return nil
 }
+
+// summariesDel deletes all cached vote summaries from the plugin data dir.
+//
+// This function must be called WITHOUT the mtxSummary lock held.
+func (p *ticketVotePlugin) summariesDel() error {
+	p.mtxSummary.Lock()
+	defer p.mtxSummary.Unlock()
+
+	pattern := strings.Replace(filenameSummary, "{shorttoken}", "*", 1)
+	files, err := filepath.Glob(filepath.Join(p.dataDir, pattern))
+	if err != nil {
+		return err
+	}
+	for _, v := range files {
+		err = os.Remove(v)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,351 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/politeia/politeiad/api/v1/identity"
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
+	"github.com/decred/politeia/politeiad/plugins/dcrdata"
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+	"github.com/decred/politeia/util"
+)
+
+// testBackend is a backend that serves the dcrdata plugin commands that are
+// used by the ticketvote plugin and routes ticketvote plugin commands back to
+// the ticketvote plugin. All other backend methods panic.
+type testBackend struct {
+	backend.Backend
+	plugin *ticketVotePlugin
+
+	sync.Mutex
+	bestBlock uint32
+	tickets   []string // Ticket pool
+}
+
+// bestBlockSet sets the best block that is returned by the backend.
+func (b *testBackend) bestBlockSet(height uint32) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.bestBlock = height
+}
+
+// PluginInventory returns the plugins that are registered with the backend.
+//
+// This function satisfies the backend Backend interface.
+func (b *testBackend) PluginInventory() []backend.Plugin {
+	return []backend.Plugin{
+		{
+			ID: ticketvote.PluginID,
+		},
+		{
+			ID: dcrdata.PluginID,
+		},
+	}
+}
+
+// PluginRead executes a read-only plugin command.
+//
+// This function satisfies the backend Backend interface.
+func (b *testBackend) PluginRead(token []byte, pluginID, cmd, payload string) (string, error) {
+	switch pluginID {
+	case ticketvote.PluginID:
+		return b.plugin.Cmd(token, cmd, payload)
+	case dcrdata.PluginID:
+		// Handled below
+	default:
+		return "", backend.ErrPluginIDInvalid
+	}
+
+	b.Lock()
+	defer b.Unlock()
+
+	var reply interface{}
+	switch cmd {
+	case dcrdata.CmdBestBlock:
+		reply = dcrdata.BestBlockReply{
+			Status: dcrdata.StatusConnected,
+			Height: b.bestBlock,
+		}
+	case dcrdata.CmdBlockDetails:
+		var bd dcrdata.BlockDetails
+		err := json.Unmarshal([]byte(payload), &bd)
+		if err != nil {
+			return "", err
+		}
+		hash := util.Digest([]byte(strconv.FormatUint(uint64(bd.Height), 10)))
+		reply = dcrdata.BlockDetailsReply{
+			Block: dcrdata.BlockDataBasic{
+				Height: bd.Height,
+				Hash:   hex.EncodeToString(hash),
+			},
+		}
+	case dcrdata.CmdTicketPool:
+		reply = dcrdata.TicketPoolReply{
+			Tickets: b.tickets,
+		}
+	case dcrdata.CmdTxsTrimmed:
+		// The commitment addresses of the tickets are not used
+		reply = dcrdata.TxsTrimmedReply{
+			Txs: []dcrdata.TrimmedTx{},
+		}
+	default:
+		return "", backend.ErrPluginCmdInvalid
+	}
+	r, err := json.Marshal(reply)
+	if err != nil {
+		return "", err
+	}
+	return string(r), nil
+}
+
+// newTestTicketVotePlugin returns a ticketVotePlugin that has been setup for
+// testing and a closure that cleans up the test data when invoked. The best
+// block of the test backend is set to 100 and the ticket pool contains 10
+// tickets.
+func newTestTicketVotePlugin(t *testing.T) (*ticketVotePlugin, *plugins.TestTstore, *testBackend, func()) {
+	t.Helper()
+
+	dataDir, err := ioutil.TempDir("", ticketvote.PluginID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, err := identity.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &testBackend{
+		bestBlock: 100,
+		tickets:   make([]string, 0, 10),
+	}
+	for i := 0; i < 10; i++ {
+		ticket := util.Digest([]byte("ticket" + strconv.Itoa(i)))
+		b.tickets = append(b.tickets, hex.EncodeToString(ticket))
+	}
+	tstore := plugins.NewTestTstore()
+	p, err := New(b, tstore, nil, dataDir, id, chaincfg.TestNet3Params())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.plugin = p
+
+	return p, tstore, b, func() {
+		os.RemoveAll(dataDir)
+	}
+}
+
+// newTestRecord saves a new record with the provided status to the test
+// tstore. The set record status post hook is executed for vetted records. The
+// vote metadata is added to the record files if it is not nil.
+func newTestRecord(t *testing.T, p *ticketVotePlugin, tstore *plugins.TestTstore, timestamp int64, status backend.StatusT, vm *ticketvote.VoteMetadata) []byte {
+	t.Helper()
+
+	state := backend.StateVetted
+	if status == backend.StatusUnreviewed {
+		state = backend.StateUnvetted
+	}
+	token := util.Digest([]byte(strconv.FormatInt(timestamp, 10)))[:8]
+	r := backend.Record{
+		RecordMetadata: backend.RecordMetadata{
+			Token:     hex.EncodeToString(token),
+			Version:   1,
+			Iteration: 1,
+			State:     state,
+			Status:    status,
+			Timestamp: timestamp,
+		},
+		Metadata: []backend.MetadataStream{},
+		Files:    []backend.File{},
+	}
+	if vm != nil {
+		b, err := json.Marshal(vm)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.Files = append(r.Files, backend.File{
+			Name:    ticketvote.FileNameVoteMetadata,
+			MIME:    "text/plain; charset=utf-8",
+			Digest:  hex.EncodeToString(util.Digest(b)),
+			Payload: base64.StdEncoding.EncodeToString(b),
+		})
+	}
+	tstore.RecordSave(r)
+	if state == backend.StateUnvetted {
+		return token
+	}
+
+	testSetRecordStatus(t, p, tstore, token, status)
+
+	return token
+}
+
+// testSetRecordStatus updates the status of a vetted record in the test
+// tstore and executes the set record status post hook.
+func testSetRecordStatus(t *testing.T, p *ticketVotePlugin, tstore *plugins.TestTstore, token []byte, status backend.StatusT) {
+	t.Helper()
+
+	r, err := tstore.RecordLatest(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.RecordMetadata.Status = status
+	tstore.RecordSave(*r)
+	b, err := json.Marshal(plugins.HookSetRecordStatus{
+		Record:         *r,
+		RecordMetadata: r.RecordMetadata,
+		Metadata:       r.Metadata,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.Hook(plugins.HookTypeSetRecordStatusPost, string(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testCmd executes a ticketvote plugin command using the provided payload.
+func testCmd(t *testing.T, p *ticketVotePlugin, token []byte, cmd string, payload interface{}) (string, error) {
+	t.Helper()
+
+	b, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p.Cmd(token, cmd, string(b))
+}
+
+// testAuthorize authorizes the vote of a record.
+func testAuthorize(t *testing.T, p *ticketVotePlugin, id *identity.FullIdentity, token []byte) error {
+	t.Helper()
+
+	a := ticketvote.Authorize{
+		Token:     hex.EncodeToString(token),
+		Version:   1,
+		Action:    ticketvote.AuthActionAuthorize,
+		PublicKey: id.Public.String(),
+	}
+	sig := id.SignMessage([]byte(a.Token + "1" + string(a.Action)))
+	a.Signature = hex.EncodeToString(sig[:])
+	_, err := testCmd(t, p, token, ticketvote.CmdAuthorize, a)
+	return err
+}
+
+// testStart starts a standard vote for a record with the provided duration.
+func testStart(t *testing.T, p *ticketVotePlugin, id *identity.FullIdentity, token []byte, duration uint32) error {
+	t.Helper()
+
+	params := ticketvote.VoteParams{
+		Token:            hex.EncodeToString(token),
+		Version:          1,
+		Type:             ticketvote.VoteTypeStandard,
+		Mask:             0x03,
+		Duration:         duration,
+		QuorumPercentage: 20,
+		PassPercentage:   60,
+		Options: []ticketvote.VoteOption{
+			{
+				ID:          ticketvote.VoteOptionIDApprove,
+				Description: "Approve the record",
+				Bit:         0x01,
+			},
+			{
+				ID:          ticketvote.VoteOptionIDReject,
+				Description: "Reject the record",
+				Bit:         0x02,
+			},
+		},
+	}
+	b, err := json.Marshal(params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig := id.SignMessage([]byte(hex.EncodeToString(util.Digest(b))))
+	s := ticketvote.Start{
+		Starts: []ticketvote.StartDetails{
+			{
+				Params:    params,
+				PublicKey: id.Public.String(),
+				Signature: hex.EncodeToString(sig[:]),
+			},
+		},
+	}
+	_, err = testCmd(t, p, token, ticketvote.CmdStart, s)
+	return err
+}
+
+// testCancel cancels the vote of a record.
+func testCancel(t *testing.T, p *ticketVotePlugin, id *identity.FullIdentity, token []byte, reason string) error {
+	t.Helper()
+
+	c := ticketvote.Cancel{
+		Token:     hex.EncodeToString(token),
+		Reason:    reason,
+		PublicKey: id.Public.String(),
+	}
+	sig := id.SignMessage([]byte(c.Token + c.Reason))
+	c.Signature = hex.EncodeToString(sig[:])
+	_, err := testCmd(t, p, token, ticketvote.CmdCancel, c)
+	return err
+}
+
+// testRecordImport copies a record and its ticketvote blobs to a new record
+// in the test tstore the same way that a record bundle is imported, then
+// executes the record import post hook. The ticketvote blobs of the new
+// record still contain the token of the source record.
+func testRecordImport(t *testing.T, p *ticketVotePlugin, tstore *plugins.TestTstore, source []byte, timestamp int64) []byte {
+	t.Helper()
+
+	r, err := tstore.RecordLatest(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := util.Digest([]byte(strconv.FormatInt(timestamp, 10)))[:8]
+	r.RecordMetadata.Token = hex.EncodeToString(token)
+	tstore.RecordSave(*r)
+
+	blobs, err := tstore.BlobsByDataDesc(source, []string{
+		dataDescriptorAuthDetails,
+		dataDescriptorVoteDetails,
+		dataDescriptorCastVoteDetails,
+		dataDescriptorVoteCollider,
+		dataDescriptorVoteReveal,
+		dataDescriptorStartRunoff,
+		dataDescriptorCancelDetails,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range blobs {
+		err = tstore.BlobSave(token, v)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	b, err := json.Marshal(plugins.HookRecordImportPost{
+		Record: *r,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.Hook(plugins.HookTypeRecordImportPost, string(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
@@ -54,11 +54,16 @@ type ticketVotePlugin struct {
 	mtxSummary sync.Mutex   // Vote summaries cache
 	mtxSubs    sync.Mutex   // Runoff vote submission cache
 
-	// Plugin settings
-	linkByPeriodMin int64  // In seconds
-	linkByPeriodMax int64  // In seconds
-	voteDurationMin uint32 // In blocks
-	voteDurationMax uint32 // In blocks
+	// Plugin settings. The settings can be updated at runtime and are
+	// protected by the settings mutex.
+	mtxSettings sync.RWMutex
+	settings    pluginSettings
+
+	// done is closed when the plugin is closed in order to stop the
+	// background jobs of the plugin. The wait group is used to wait
+	// for the jobs to exit.
+	done chan struct{}
+	wg   sync.WaitGroup
 }
 
 // Setup performs any plugin setup that is required.
@@ -102,12 +107,31 @@ func (p *ticketVotePlugin) Setup() error {
 		}
 	}
 	for _, v := range started {
-		// Get the vote details
 		token, err := tokenDecode(v)
 		if err != nil {
 			return err
 		}
 
+		// Verify that the vote has not been cancelled. The cancel
+		// details are saved before the inventory is updated, so the
+		// inventory will still list a cancelled vote as started if
+		// politeiad went down in between the two writes.
+		cd, err := p.cancelDetails(token)
+		if err != nil {
+			return fmt.Errorf("cancelDetails %x: %v", token, err)
+		}
+		if cd != nil {
+			log.Infof("Updating cancelled vote inventory entry %v", v)
+
+			err = p.invUpdate(v, ticketvote.VoteStatusCancelled, 0)
+			if err != nil {
+				return fmt.Errorf("invUpdate %v: %v", v, err)
+			}
+			continue
+		}
+
+		// Get the vote details
+
 		reply, err := p.backend.PluginRead(token, ticketvote.PluginID,
 			ticketvote.CmdDetails, "")
 		if err != nil {
@@ -143,9 +167,18 @@ func (p *ticketVotePlugin) Setup() error {
 		for _, v := range rr.Votes {
 			// Add cast vote to the active votes cache
 			p.activeVotes.AddCastVote(v.Token, v.Ticket, v.VoteBit)
+			if v.Commitment != "" {
+				p.activeVotes.AddCommitment(v.Token, v.Ticket,
+					v.Commitment)
+			}
 		}
 	}
 
+	// Publish the vote finished events as votes reach their end
+	// height.
+	p.wg.Add(1)
+	go p.voteFinishedMonitor()
+
 	return nil
 }
 
@@ -162,6 +195,10 @@ func (p *ticketVotePlugin) Cmd(token []byte, cmd, payload string) (string, error
 		return p.cmdStart(token, payload)
 	case ticketvote.CmdCastBallot:
 		return p.cmdCastBallot(token, payload)
+	case ticketvote.CmdReveal:
+		return p.cmdReveal(token, payload)
+	case ticketvote.CmdCancel:
+		return p.cmdCancel(token, payload)
 	case ticketvote.CmdDetails:
 		return p.cmdDetails(token)
 	case ticketvote.CmdResults:
@@ -200,6 +237,8 @@ func (p *ticketVotePlugin) Hook(h plugins.HookT, payload string) error {
 		return p.hookSetRecordStatusPre(payload)
 	case plugins.HookTypeSetRecordStatusPost:
 		return p.hookSetRecordStatusPost(payload)
+	case plugins.HookTypeRecordImportPost:
+		return p.hookRecordImportPost(payload)
 	}
 
 	return nil
@@ -220,115 +259,100 @@ func (p *ticketVotePlugin) Fsck() error {
 	return nil
 }
 
+// CacheRebuild deletes the vote summaries, runoff submissions, and vote
+// inventory caches and rebuilds them from the ticketvote data of all records.
+//
+// This function satisfies the plugins PluginClient interface.
+func (p *ticketVotePlugin) CacheRebuild(tokens [][]byte, progress func()) error {
+	log.Tracef("ticketvote CacheRebuild: %v records", len(tokens))
+
+	err := p.summariesDel()
+	if err != nil {
+		return fmt.Errorf("summariesDel: %v", err)
+	}
+
+	// The runoff vote summaries are derived from the submissions
+	// lists, so the submissions lists must be rebuilt first.
+	err = p.submissionsRebuild(tokens)
+	if err != nil {
+		return fmt.Errorf("submissionsRebuild: %v", err)
+	}
+
+	return p.invRebuild(tokens, progress)
+}
+
+// Close stops the background jobs of the plugin and waits for them to exit.
+// The tstore closes the plugin when it is closed.
+func (p *ticketVotePlugin) Close() {
+	log.Tracef("ticketvote Close")
+
+	close(p.done)
+	p.wg.Wait()
+}
+
 // Settings returns the plugin's settings.
 //
 // This function satisfies the plugins PluginClient interface.
 func (p *ticketVotePlugin) Settings() []backend.PluginSetting {
 	log.Tracef("ticketvote Settings")
 
+	s := p.settingsGet()
 	return []backend.PluginSetting{
 		{
 			Key:   ticketvote.SettingKeyLinkByPeriodMin,
-			Value: strconv.FormatInt(p.linkByPeriodMin, 10),
+			Value: strconv.FormatInt(s.linkByPeriodMin, 10),
 		},
 		{
 			Key:   ticketvote.SettingKeyLinkByPeriodMax,
-			Value: strconv.FormatInt(p.linkByPeriodMax, 10),
+			Value: strconv.FormatInt(s.linkByPeriodMax, 10),
 		},
 		{
 			Key:   ticketvote.SettingKeyVoteDurationMin,
-			Value: strconv.FormatUint(uint64(p.voteDurationMin), 10),
+			Value: strconv.FormatUint(uint64(s.voteDurationMin), 10),
 		},
 		{
 			Key:   ticketvote.SettingKeyVoteDurationMax,
-			Value: strconv.FormatUint(uint64(p.voteDurationMax), 10),
+			Value: strconv.FormatUint(uint64(s.voteDurationMax), 10),
 		},
 	}
 }
 
-func New(backend backend.Backend, tstore plugins.TstoreClient, settings []backend.PluginSetting, dataDir string, id *identity.FullIdentity, activeNetParams *chaincfg.Params) (*ticketVotePlugin, error) {
-	// Plugin settings
-	var (
-		linkByPeriodMin int64
-		linkByPeriodMax int64
-		voteDurationMin uint32
-		voteDurationMax uint32
-	)
-
-	// Set plugin settings to defaults. These will be overwritten if
-	// the setting was specified by the user.
-	switch activeNetParams.Name {
-	case chaincfg.MainNetParams().Name:
-		linkByPeriodMin = ticketvote.SettingMainNetLinkByPeriodMin
-		linkByPeriodMax = ticketvote.SettingMainNetLinkByPeriodMax
-		voteDurationMin = ticketvote.SettingMainNetVoteDurationMin
-		voteDurationMax = ticketvote.SettingMainNetVoteDurationMax
-	case chaincfg.TestNet3Params().Name:
-		linkByPeriodMin = ticketvote.SettingTestNetLinkByPeriodMin
-		linkByPeriodMax = ticketvote.SettingTestNetLinkByPeriodMax
-		voteDurationMin = ticketvote.SettingTestNetVoteDurationMin
-		voteDurationMax = ticketvote.SettingTestNetVoteDurationMax
-	case chaincfg.SimNetParams().Name:
-		// Use testnet defaults for simnet
-		linkByPeriodMin = ticketvote.SettingTestNetLinkByPeriodMin
-		linkByPeriodMax = ticketvote.SettingTestNetLinkByPeriodMax
-		voteDurationMin = ticketvote.SettingTestNetVoteDurationMin
-		voteDurationMax = ticketvote.SettingTestNetVoteDurationMax
-	default:
-		return nil, fmt.Errorf("unknown active net: %v", activeNetParams.Name)
-	}
+// SettingsUpdate updates the plugin settings at runtime. The updated settings
+// only apply to votes that are started after the update.
+//
+// This function satisfies the plugins PluginClient interface.
+func (p *ticketVotePlugin) SettingsUpdate(settings []backend.PluginSetting) error {
+	log.Tracef("ticketvote SettingsUpdate: %v", settings)
 
-	// Override defaults with any passed in settings
-	for _, v := range settings {
-		switch v.Key {
-		case ticketvote.SettingKeyLinkByPeriodMin:
-			i, err := strconv.ParseInt(v.Value, 10, 64)
-			if err != nil {
-				return nil, fmt.Errorf("plugin setting '%v': ParseInt(%v): %v",
-					v.Key, v.Value, err)
-			}
-			linkByPeriodMin = i
-			log.Infof("Plugin setting updated: ticketvote %v %v",
-				ticketvote.SettingKeyLinkByPeriodMin, linkByPeriodMin)
+	p.mtxSettings.Lock()
+	defer p.mtxSettings.Unlock()
 
-		case ticketvote.SettingKeyLinkByPeriodMax:
-			i, err := strconv.ParseInt(v.Value, 10, 64)
-			if err != nil {
-				return nil, fmt.Errorf("plugin setting '%v': ParseInt(%v): %v",
-					v.Key, v.Value, err)
-			}
-			linkByPeriodMax = i
-			log.Infof("Plugin setting updated: ticketvote %v %v",
-				ticketvote.SettingKeyLinkByPeriodMax, linkByPeriodMax)
-
-		case ticketvote.SettingKeyVoteDurationMin:
-			u, err := strconv.ParseUint(v.Value, 10, 64)
-			if err != nil {
-				return nil, fmt.Errorf("plugin setting '%v': ParseUint(%v): %v",
-					v.Key, v.Value, err)
-			}
-			voteDurationMin = uint32(u)
-			log.Infof("Plugin setting updated: ticketvote %v %v",
-				ticketvote.SettingKeyVoteDurationMin, voteDurationMin)
+	s, err := settingsParse(p.settings, settings)
+	if err != nil {
+		return err
+	}
+	p.settings = s
 
-		case ticketvote.SettingKeyVoteDurationMax:
-			u, err := strconv.ParseUint(v.Value, 10, 64)
-			if err != nil {
-				return nil, fmt.Errorf("plugin setting '%v': ParseUint(%v): %v",
-					v.Key, v.Value, err)
-			}
-			voteDurationMax = uint32(u)
-			log.Infof("Plugin setting updated: ticketvote %v %v",
-				ticketvote.SettingKeyVoteDurationMax, voteDurationMax)
+	return nil
+}
 
-		default:
-			return nil, fmt.Errorf("invalid plugin setting '%v'", v.Key)
-		}
+func New(backend backend.Backend, tstore plugins.TstoreClient, settings []backend.PluginSetting, dataDir string, id *identity.FullIdentity, activeNetParams *chaincfg.Params) (*ticketVotePlugin, error) {
+	// Override the default settings with any passed in settings
+	s, err := settingsDefault(activeNetParams)
+	if err != nil {
+		return nil, err
+	}
+	*s, err = settingsParse(*s, settings)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range settings {
+		log.Infof("Plugin setting updated: ticketvote %v %v", v.Key, v.Value)
 	}
 
 	// Create the plugin data directory
 	dataDir = filepath.Join(dataDir, ticketvote.PluginID)
-	err := os.MkdirAll(dataDir, 0700)
+	err = os.MkdirAll(dataDir, 0700)
 	if err != nil {
 		return nil, err
 	}
@@ -340,9 +364,7 @@ func New(backend backend.Backend, tstore plugins.TstoreClient, settings []backen
 		dataDir:         dataDir,
 		identity:        id,
 		activeVotes:     newActiveVotes(),
-		linkByPeriodMin: linkByPeriodMin,
-		linkByPeriodMax: linkByPeriodMax,
-		voteDurationMin: voteDurationMin,
-		voteDurationMax: voteDurationMax,
+		settings:        *s,
+		done:            make(chan struct{}),
 	}, nil
 }
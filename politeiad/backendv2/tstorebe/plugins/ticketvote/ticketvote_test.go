@@ -0,0 +1,220 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ticketvote
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/decred/politeia/politeiad/api/v1/identity"
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+)
+
+func TestCacheRebuild(t *testing.T) {
+	p, tstore, b, cleanup := newTestTicketVotePlugin(t)
+	defer cleanup()
+
+	id, err := identity.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Setup records with every vote status using the plugin hooks and
+	// commands. The runoff parent has a submission and a censored
+	// submission.
+	var (
+		unauthorized = newTestRecord(t, p, tstore, 1, backend.StatusPublic, nil)
+		parent       = newTestRecord(t, p, tstore, 2, backend.StatusPublic,
+			&ticketvote.VoteMetadata{LinkBy: 1})
+		sub = newTestRecord(t, p, tstore, 3, backend.StatusPublic,
+			&ticketvote.VoteMetadata{LinkTo: hex.EncodeToString(parent)})
+		censoredSub = newTestRecord(t, p, tstore, 4, backend.StatusPublic,
+			&ticketvote.VoteMetadata{LinkTo: hex.EncodeToString(parent)})
+		authorized = newTestRecord(t, p, tstore, 5, backend.StatusPublic, nil)
+		started    = newTestRecord(t, p, tstore, 6, backend.StatusPublic, nil)
+		finished   = newTestRecord(t, p, tstore, 7, backend.StatusPublic, nil)
+		cancelled  = newTestRecord(t, p, tstore, 8, backend.StatusPublic, nil)
+		archived   = newTestRecord(t, p, tstore, 9, backend.StatusPublic, nil)
+		unvetted   = newTestRecord(t, p, tstore, 10, backend.StatusUnreviewed, nil)
+
+		tokens = [][]byte{unauthorized, parent, sub, censoredSub, authorized,
+			started, finished, cancelled, archived, unvetted}
+	)
+	testSetRecordStatus(t, p, tstore, censoredSub, backend.StatusCensored)
+	testSetRecordStatus(t, p, tstore, archived, backend.StatusArchived)
+	for _, token := range [][]byte{authorized, started, finished, cancelled} {
+		err := testAuthorize(t, p, id, token)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = testStart(t, p, id, finished, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = testStart(t, p, id, cancelled, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = testCancel(t, p, id, cancelled, "cancelled")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = testStart(t, p, id, started, 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.bestBlockSet(150)
+	bestBlock, err := p.bestBlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// caches returns the ticketvote caches. The inventory entries are
+	// grouped by vote status since only the order of the entries that
+	// share a vote status is preserved by a rebuild.
+	type cache struct {
+		inv         map[ticketvote.VoteStatusT][]entry
+		bestBlock   uint32
+		summaries   map[string]ticketvote.SummaryReply
+		submissions string
+		attrs       map[string]map[string]string
+		files       []string
+	}
+	caches := func() cache {
+		t.Helper()
+
+		inv, err := p.Inventory(bestBlock)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := cache{
+			inv:       make(map[ticketvote.VoteStatusT][]entry),
+			bestBlock: inv.BestBlock,
+			summaries: make(map[string]ticketvote.SummaryReply, len(tokens)),
+			attrs:     make(map[string]map[string]string, len(tokens)),
+		}
+		for _, v := range inv.Entries {
+			c.inv[v.Status] = append(c.inv[v.Status], v)
+		}
+		for _, token := range tokens {
+			sr, err := p.summary(token, bestBlock)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.summaries[hex.EncodeToString(token)] = *sr
+			c.attrs[hex.EncodeToString(token)] = tstore.InventoryAttributes(token)
+		}
+		c.submissions, err = p.cmdSubmissions(parent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		files, err := ioutil.ReadDir(p.dataDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, v := range files {
+			c.files = append(c.files, v.Name())
+		}
+		sort.Strings(c.files)
+		return c
+	}
+	want := caches()
+	for _, s := range []ticketvote.VoteStatusT{
+		ticketvote.VoteStatusUnauthorized,
+		ticketvote.VoteStatusAuthorized,
+		ticketvote.VoteStatusStarted,
+		ticketvote.VoteStatusRejected,
+		ticketvote.VoteStatusCancelled,
+		ticketvote.VoteStatusIneligible,
+	} {
+		if len(want.inv[s]) == 0 {
+			t.Fatalf("no %v inventory entries", ticketvote.VoteStatuses[s])
+		}
+	}
+
+	// Delete the caches and add stale caches for records that do not
+	// have a cached summary or a submissions list.
+	files, err := ioutil.ReadDir(p.dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range files {
+		err = os.Remove(filepath.Join(p.dataDir, v.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = p.summaryCacheSave(hex.EncodeToString(unauthorized),
+		ticketvote.SummaryReply{Status: ticketvote.VoteStatusApproved})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = p.submissionsCacheAdd(hex.EncodeToString(authorized),
+		hex.EncodeToString(sub))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rebuild the caches and verify that they match the caches that
+	// were built by the plugin hooks and commands.
+	var progress int
+	err = p.CacheRebuild(tokens, func() { progress++ })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if progress != len(tokens) {
+		t.Fatalf("got progress %v, want %v", progress, len(tokens))
+	}
+	got := caches()
+	if !reflect.DeepEqual(got.inv, want.inv) {
+		t.Fatalf("got inventory %v, want %v", got.inv, want.inv)
+	}
+	if got.bestBlock != want.bestBlock {
+		t.Fatalf("got best block %v, want %v", got.bestBlock, want.bestBlock)
+	}
+	for token, w := range want.summaries {
+		if g := got.summaries[token]; !reflect.DeepEqual(g, w) {
+			t.Fatalf("%v: got summary %+v, want %+v", token, g, w)
+		}
+	}
+	if got.submissions != want.submissions {
+		t.Fatalf("got submissions %v, want %v",
+			got.submissions, want.submissions)
+	}
+	if !reflect.DeepEqual(got.attrs, want.attrs) {
+		t.Fatalf("got inventory attributes %v, want %v", got.attrs, want.attrs)
+	}
+	if !reflect.DeepEqual(got.files, want.files) {
+		t.Fatalf("got cache files %v, want %v", got.files, want.files)
+	}
+}
+
+func TestClose(t *testing.T) {
+	p, _, _, cleanup := newTestTicketVotePlugin(t)
+	defer cleanup()
+
+	// Closing the plugin must stop the vote finished monitor
+	p.wg.Add(1)
+	go p.voteFinishedMonitor()
+
+	closed := make(chan struct{})
+	go func() {
+		p.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("vote finished monitor was not stopped")
+	}
+}
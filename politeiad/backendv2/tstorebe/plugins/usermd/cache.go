@@ -11,6 +11,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	backend "github.com/decred/politeia/politeiad/backendv2"
@@ -221,3 +222,89 @@ func delToken(tokens []string, tokenToDel string) ([]string, error) {
 
 	return tokens, nil
 }
+
+// userCachesDelLocked deletes all user caches from the plugin data dir.
+//
+// This function must be called WITH the lock held.
+func (p *usermdPlugin) userCachesDelLocked() error {
+	pattern := strings.Replace(fnUserCache, "{userid}", "*", 1)
+	files, err := filepath.Glob(filepath.Join(p.dataDir, pattern))
+	if err != nil {
+		return err
+	}
+	for _, v := range files {
+		err = os.Remove(v)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// userCachesRebuild deletes all user caches and rebuilds them from the user
+// metadata of the provided records. The tokens of each user are sorted by the
+// timestamp of the most recent record status change.
+//
+// This function must be called WITHOUT the lock held.
+func (p *usermdPlugin) userCachesRebuild(tokens [][]byte, progress func()) error {
+	p.Lock()
+	defer p.Unlock()
+
+	err := p.userCachesDelLocked()
+	if err != nil {
+		return err
+	}
+
+	// Compile the records of each user
+	type userRecord struct {
+		state     backend.StateT
+		token     string
+		timestamp int64
+	}
+	records := make(map[string][]userRecord, 256) // [userID]records
+	for _, token := range tokens {
+		r, err := p.tstore.RecordPartial(token, 0, nil, true)
+		if err != nil {
+			return fmt.Errorf("RecordPartial %x: %v", token, err)
+		}
+		um, err := userMetadataDecode(r.Metadata)
+		if err != nil {
+			return err
+		}
+		if um != nil {
+			records[um.UserID] = append(records[um.UserID], userRecord{
+				state:     r.RecordMetadata.State,
+				token:     r.RecordMetadata.Token,
+				timestamp: r.RecordMetadata.Timestamp,
+			})
+		}
+		progress()
+	}
+
+	// Save the user caches
+	for userID, urs := range records {
+		sort.SliceStable(urs, func(i, j int) bool {
+			return urs[i].timestamp < urs[j].timestamp
+		})
+		uc := userCache{
+			Unvetted: []string{},
+			Vetted:   []string{},
+		}
+		for _, v := range urs {
+			switch v.state {
+			case backend.StateUnvetted:
+				uc.Unvetted = append(uc.Unvetted, v.token)
+			case backend.StateVetted:
+				uc.Vetted = append(uc.Vetted, v.token)
+			}
+		}
+		err = p.userCacheSaveLocked(userID, uc)
+		if err != nil {
+			return err
+		}
+	}
+
+	log.Infof("User caches rebuilt for %v users", len(records))
+
+	return nil
+}
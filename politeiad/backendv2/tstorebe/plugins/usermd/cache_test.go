@@ -0,0 +1,198 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package usermd
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
+	"github.com/decred/politeia/politeiad/plugins/usermd"
+	"github.com/decred/politeia/util"
+)
+
+// newTestUsermdPlugin returns a usermdPlugin that has been setup for testing
+// and a closure that cleans up the test data when invoked.
+func newTestUsermdPlugin(t *testing.T) (*usermdPlugin, *plugins.TestTstore, func()) {
+	t.Helper()
+
+	dataDir, err := ioutil.TempDir("", usermd.PluginID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tstore := plugins.NewTestTstore()
+	p, err := New(tstore, nil, dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return p, tstore, func() {
+		os.RemoveAll(dataDir)
+	}
+}
+
+// cacheFiles returns the contents of the cache files in the plugin data dir.
+func cacheFiles(t *testing.T, dataDir string) map[string][]byte {
+	t.Helper()
+
+	files, err := ioutil.ReadDir(dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache := make(map[string][]byte, len(files))
+	for _, v := range files {
+		b, err := ioutil.ReadFile(filepath.Join(dataDir, v.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		cache[v.Name()] = b
+	}
+	return cache
+}
+
+func TestCacheRebuild(t *testing.T) {
+	p, tstore, cleanup := newTestUsermdPlugin(t)
+	defer cleanup()
+
+	// newRecord saves a new unvetted record and executes the new
+	// record post hook. The user metadata is not included if the user
+	// ID is empty.
+	var timestamp int64
+	newRecord := func(userID string) backend.Record {
+		timestamp++
+		token := hex.EncodeToString(util.Digest([]byte{byte(timestamp)})[:8])
+		r := backend.Record{
+			RecordMetadata: backend.RecordMetadata{
+				Token:     token,
+				Version:   1,
+				Iteration: 1,
+				State:     backend.StateUnvetted,
+				Status:    backend.StatusUnreviewed,
+				Timestamp: timestamp,
+			},
+			Metadata: []backend.MetadataStream{},
+		}
+		if userID != "" {
+			b, err := json.Marshal(usermd.UserMetadata{
+				UserID: userID,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			r.Metadata = append(r.Metadata, backend.MetadataStream{
+				PluginID: usermd.PluginID,
+				StreamID: usermd.StreamIDUserMetadata,
+				Payload:  string(b),
+			})
+		}
+		tstore.RecordSave(r)
+		if userID == "" {
+			return r
+		}
+		b, err := json.Marshal(plugins.HookNewRecordPost{
+			Metadata:       r.Metadata,
+			RecordMetadata: r.RecordMetadata,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = p.hookNewRecordPost(string(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return r
+	}
+
+	// makePublic makes a record public and executes the set record
+	// status post hook.
+	makePublic := func(r backend.Record) {
+		timestamp++
+		r.RecordMetadata.State = backend.StateVetted
+		r.RecordMetadata.Status = backend.StatusPublic
+		r.RecordMetadata.Timestamp = timestamp
+		tstore.RecordSave(r)
+		b, err := json.Marshal(plugins.HookSetRecordStatus{
+			RecordMetadata: r.RecordMetadata,
+			Metadata:       r.Metadata,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = p.hookSetRecordStatusPost(string(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Setup the user caches using the plugin hooks. The records of a
+	// user are made public in a different order than they were
+	// submitted in.
+	var (
+		user1 = "0b9a1e3c-4d1b-4a63-9b1b-6f1a2f0c2e11"
+		user2 = "7d5f4c1e-2a8b-4e0e-8f3c-1c2d3e4f5a6b"
+
+		r1 = newRecord(user1)
+		r2 = newRecord(user1)
+		r3 = newRecord(user1)
+		r4 = newRecord(user2)
+		r5 = newRecord("")
+	)
+	makePublic(r3)
+	makePublic(r1)
+	makePublic(r4)
+	tokens := make([][]byte, 0, 5)
+	for _, v := range []backend.Record{r1, r2, r3, r4, r5} {
+		token, err := hex.DecodeString(v.RecordMetadata.Token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tokens = append(tokens, token)
+	}
+	want := cacheFiles(t, p.dataDir)
+	if len(want) != 2 {
+		t.Fatalf("got %v user caches, want 2", len(want))
+	}
+
+	// Delete the caches and add a stale cache of a user that does not
+	// have any records.
+	err := os.RemoveAll(p.dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.MkdirAll(p.dataDir, 0700)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(p.userCachePath("stale"), []byte("{}"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rebuild the caches and verify that they match the caches that
+	// were built by the hooks.
+	var progress int
+	err = p.CacheRebuild(tokens, func() { progress++ })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if progress != len(tokens) {
+		t.Fatalf("got progress %v, want %v", progress, len(tokens))
+	}
+	got := cacheFiles(t, p.dataDir)
+	if len(got) != len(want) {
+		t.Fatalf("got %v user caches, want %v", len(got), len(want))
+	}
+	for fn, b := range want {
+		if !bytes.Equal(got[fn], b) {
+			t.Fatalf("%v: got %s, want %s", fn, got[fn], b)
+		}
+	}
+}
@@ -57,6 +57,30 @@ func (p *usermdPlugin) hookNewRecordPost(payload string) error {
 	return nil
 }
 
+// hookRecordImportPost adds the token of a record that has been imported to
+// the user cache of the record author.
+func (p *usermdPlugin) hookRecordImportPost(payload string) error {
+	var ri plugins.HookRecordImportPost
+	err := json.Unmarshal([]byte(payload), &ri)
+	if err != nil {
+		return err
+	}
+
+	// Decode user metadata. The record may not contain user metadata
+	// if it was not submitted by a user.
+	um, err := userMetadataDecode(ri.Record.Metadata)
+	if err != nil {
+		return err
+	}
+	if um == nil {
+		return nil
+	}
+
+	// Add token to the user cache
+	rm := ri.Record.RecordMetadata
+	return p.userCacheAddToken(um.UserID, rm.State, rm.Token)
+}
+
 // hookEditRecordPre adds plugin specific validation onto the tstore backend
 // RecordEdit method.
 func (p *usermdPlugin) hookEditRecordPre(payload string) error {
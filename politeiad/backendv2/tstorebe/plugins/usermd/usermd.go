@@ -76,6 +76,8 @@ func (p *usermdPlugin) Hook(h plugins.HookT, payload string) error {
 		return p.hookSetRecordStatusPre(payload)
 	case plugins.HookTypeSetRecordStatusPost:
 		return p.hookSetRecordStatusPost(payload)
+	case plugins.HookTypeRecordImportPost:
+		return p.hookRecordImportPost(payload)
 	}
 
 	return nil
@@ -90,6 +92,16 @@ func (p *usermdPlugin) Fsck() error {
 	return nil
 }
 
+// CacheRebuild deletes the user caches and rebuilds them from the user
+// metadata of all records.
+//
+// This function satisfies the plugins PluginClient interface.
+func (p *usermdPlugin) CacheRebuild(tokens [][]byte, progress func()) error {
+	log.Tracef("usermd CacheRebuild: %v records", len(tokens))
+
+	return p.userCachesRebuild(tokens, progress)
+}
+
 // Settings returns the plugin's settings.
 //
 // This function satisfies the plugins PluginClient interface.
@@ -99,6 +111,24 @@ func (p *usermdPlugin) Settings() []backend.PluginSetting {
 	return nil
 }
 
+// SettingsUpdate updates the plugin settings at runtime. The usermd plugin
+// does not have any settings.
+//
+// This function satisfies the plugins PluginClient interface.
+func (p *usermdPlugin) SettingsUpdate(settings []backend.PluginSetting) error {
+	log.Tracef("usermd SettingsUpdate: %v", settings)
+
+	if len(settings) == 0 {
+		return nil
+	}
+	return backend.PluginSettingError{
+		PluginID: usermd.PluginID,
+		Key:      settings[0].Key,
+		Value:    settings[0].Value,
+		Reason:   "unknown setting",
+	}
+}
+
 // New returns a new usermdPlugin.
 func New(tstore plugins.TstoreClient, settings []backend.PluginSetting, dataDir string) (*usermdPlugin, error) {
 	// Create plugin data directory
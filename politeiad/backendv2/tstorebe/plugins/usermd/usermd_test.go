@@ -0,0 +1,68 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package usermd
+
+import (
+	"errors"
+	"testing"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/usermd"
+)
+
+func TestSettingsUpdate(t *testing.T) {
+	var tests = []struct {
+		name     string
+		settings []backend.PluginSetting
+		wantErr  bool
+	}{
+		{
+			"no settings",
+			[]backend.PluginSetting{},
+			false,
+		},
+		{
+			"unknown setting",
+			[]backend.PluginSetting{
+				{
+					Key:   "unknown",
+					Value: "1",
+				},
+			},
+			true,
+		},
+		{
+			"empty setting",
+			[]backend.PluginSetting{
+				{},
+			},
+			true,
+		},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			var p usermdPlugin
+			err := p.SettingsUpdate(v.settings)
+			if !v.wantErr {
+				if err != nil {
+					t.Fatalf("got error %v, want nil", err)
+				}
+				return
+			}
+			var e backend.PluginSettingError
+			if !errors.As(err, &e) {
+				t.Fatalf("got error %v, want PluginSettingError", err)
+			}
+			if e.PluginID != usermd.PluginID ||
+				e.Key != v.settings[0].Key {
+				t.Fatalf("got plugin %v key %v, want %v %v", e.PluginID,
+					e.Key, usermd.PluginID, v.settings[0].Key)
+			}
+			if len(p.Settings()) != 0 {
+				t.Fatalf("got settings %v, want none", p.Settings())
+			}
+		})
+	}
+}
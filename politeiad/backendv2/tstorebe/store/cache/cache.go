@@ -0,0 +1,361 @@
+// Copyright (c) 2020-2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"container/list"
+	"sync"
+
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+)
+
+const (
+	// statsLogInterval is the number of cache lookups between the
+	// cache stats being logged.
+	statsLogInterval = 10000
+)
+
+var (
+	_ store.BlobKV = (*BlobCache)(nil)
+)
+
+// Stats contains the cache statistics.
+type Stats struct {
+	Hits      uint64 // Number of blobs returned from the cache
+	Misses    uint64 // Number of blobs not found in the cache
+	Evictions uint64 // Number of blobs evicted to free up space
+	Entries   int    // Number of blobs currently in the cache
+	Size      int64  // Current size of the cache in bytes
+	MaxSize   int64  // Max size of the cache in bytes
+}
+
+// entry is an entry in the LRU list.
+type entry struct {
+	key  string
+	blob []byte
+}
+
+// size returns the number of bytes that the entry counts against the cache
+// size.
+func (e *entry) size() int64 {
+	return int64(len(e.key) + len(e.blob))
+}
+
+// fetch tracks the in flight store lookups for a key that missed the cache.
+// The generation is incremented whenever the key is written to or deleted
+// from the store. A lookup only adds its blob to the cache if the generation
+// has not changed since the lookup began, i.e. the blob is not stale.
+type fetch struct {
+	refs int    // Number of in flight lookups
+	gen  uint64 // Generation of the key
+}
+
+// BlobCache is a store BlobKV decorator that adds a memory bounded, least
+// recently used read-through cache on top of a BlobKV. Blobs are only added to
+// the cache when they are retrieved from the underlying BlobKV. Only blobs
+// whose key passes the cacheable filter are cached. The caller uses this to
+// ensure that only unencrypted blobs that will not change, i.e. vetted blobs,
+// are held in memory.
+//
+// Blobs are removed from the cache when they are deleted or overwritten. The
+// least recently used blobs are evicted once the cache exceeds its max size.
+//
+// BlobCache satisfies the store BlobKV interface.
+type BlobCache struct {
+	sync.Mutex
+	kv        store.BlobKV
+	cacheable func(key string) bool
+	maxSize   int64
+	size      int64
+	lru       *list.List               // Front is most recently used
+	entries   map[string]*list.Element // [key]*entry
+	fetches   map[string]*fetch        // [key]*fetch
+
+	// Stats
+	hits      uint64
+	misses    uint64
+	evictions uint64
+}
+
+// Stats returns the cache statistics.
+func (c *BlobCache) Stats() Stats {
+	c.Lock()
+	defer c.Unlock()
+
+	return c.statsLocked()
+}
+
+// statsLocked returns the cache statistics.
+//
+// This function must be called WITH the lock held.
+func (c *BlobCache) statsLocked() Stats {
+	return Stats{
+		Hits:      c.hits,
+		Misses:    c.misses,
+		Evictions: c.evictions,
+		Entries:   len(c.entries),
+		Size:      c.size,
+		MaxSize:   c.maxSize,
+	}
+}
+
+// logStats logs the provided cache statistics.
+func logStats(s Stats) {
+	var hitRate float64
+	if s.Hits+s.Misses > 0 {
+		hitRate = float64(s.Hits) / float64(s.Hits+s.Misses) * 100
+	}
+	log.Infof("Blob cache: %v hits, %v misses (%.1f%% hit rate), "+
+		"%v evictions, %v entries, %v/%v bytes", s.Hits, s.Misses, hitRate,
+		s.Evictions, s.Entries, s.Size, s.MaxSize)
+}
+
+// delLocked removes a blob from the cache.
+//
+// This function must be called WITH the lock held.
+func (c *BlobCache) delLocked(key string) {
+	el, ok := c.entries[key]
+	if !ok {
+		return
+	}
+	e := c.lru.Remove(el).(*entry)
+	delete(c.entries, key)
+	c.size -= e.size()
+}
+
+// invalidateLocked removes a blob from the cache and increments the
+// generation of any in flight lookups for the blob so that a stale blob is not
+// added back to the cache once the lookup completes.
+//
+// This function must be called WITH the lock held.
+func (c *BlobCache) invalidateLocked(key string) {
+	c.delLocked(key)
+	if f, ok := c.fetches[key]; ok {
+		f.gen++
+	}
+}
+
+// invalidate invalidates the provided keys.
+func (c *BlobCache) invalidate(keys []string) {
+	c.Lock()
+	defer c.Unlock()
+
+	for _, k := range keys {
+		c.invalidateLocked(k)
+	}
+}
+
+// fetchStartLocked registers an in flight lookup for each of the provided
+// keys and returns the current generation of each key.
+//
+// This function must be called WITH the lock held.
+func (c *BlobCache) fetchStartLocked(keys []string) map[string]uint64 {
+	gens := make(map[string]uint64, len(keys))
+	for _, k := range keys {
+		f, ok := c.fetches[k]
+		if !ok {
+			f = &fetch{}
+			c.fetches[k] = f
+		}
+		f.refs++
+		gens[k] = f.gen
+	}
+	return gens
+}
+
+// fetchDoneLocked unregisters the in flight lookups for the provided keys and
+// returns the keys whose generation has changed since the lookup began.
+//
+// This function must be called WITH the lock held.
+func (c *BlobCache) fetchDoneLocked(gens map[string]uint64) map[string]struct{} {
+	stale := make(map[string]struct{}, len(gens))
+	for k, gen := range gens {
+		f := c.fetches[k]
+		if f.gen != gen {
+			stale[k] = struct{}{}
+		}
+		f.refs--
+		if f.refs == 0 {
+			delete(c.fetches, k)
+		}
+	}
+	return stale
+}
+
+// addLocked adds a blob to the cache and evicts the least recently used blobs
+// until the cache is within its size limit.
+//
+// This function must be called WITH the lock held.
+func (c *BlobCache) addLocked(key string, blob []byte) {
+	e := &entry{
+		key:  key,
+		blob: blob,
+	}
+	if e.size() > c.maxSize {
+		// Blob is too large to ever fit in the cache
+		return
+	}
+
+	c.delLocked(key)
+	c.entries[key] = c.lru.PushFront(e)
+	c.size += e.size()
+
+	for c.size > c.maxSize {
+		el := c.lru.Back()
+		if el == nil {
+			break
+		}
+		c.delLocked(el.Value.(*entry).key)
+		c.evictions++
+	}
+}
+
+// Put saves the provided key-value pairs to the underlying store. Any cached
+// versions of the blobs are invalidated.
+//
+// This function satisfies the store BlobKV interface.
+func (c *BlobCache) Put(blobs map[string][]byte, encrypt bool) error {
+	keys := make([]string, 0, len(blobs))
+	for k := range blobs {
+		keys = append(keys, k)
+	}
+
+	// The blobs are invalidated both before and after the store write.
+	// A lookup that reads the previous version of a blob from the store
+	// while the write is in progress will see the generation change and
+	// will not add the stale blob to the cache.
+	c.invalidate(keys)
+	err := c.kv.Put(blobs, encrypt)
+	c.invalidate(keys)
+
+	return err
+}
+
+// Del deletes the provided blobs from the underlying store and from the
+// cache.
+//
+// This function satisfies the store BlobKV interface.
+func (c *BlobCache) Del(keys []string) error {
+	// The blobs are removed from the cache prior to being deleted from
+	// the store so that a blob is never returned from the cache after
+	// it has been deleted. They are invalidated again once the delete
+	// has completed so that a lookup that was in progress during the
+	// delete does not add the deleted blob back to the cache.
+	c.invalidate(keys)
+	err := c.kv.Del(keys)
+	c.invalidate(keys)
+
+	return err
+}
+
+// Get returns blobs from the cache. Any blobs that are not cached are
+// retrieved from the underlying store and cached if they are cacheable.
+//
+// This function satisfies the store BlobKV interface.
+func (c *BlobCache) Get(keys []string) (map[string][]byte, error) {
+	var (
+		blobs  = make(map[string][]byte, len(keys))
+		misses = make([]string, 0, len(keys))
+	)
+
+	// Lookup blobs in the cache
+	c.Lock()
+	for _, k := range keys {
+		el, ok := c.entries[k]
+		if !ok {
+			misses = append(misses, k)
+			continue
+		}
+		c.lru.MoveToFront(el)
+		e := el.Value.(*entry)
+
+		// Return a copy so that the caller cannot modify the cache
+		b := make([]byte, len(e.blob))
+		copy(b, e.blob)
+		blobs[k] = b
+	}
+	c.hits += uint64(len(keys) - len(misses))
+	c.misses += uint64(len(misses))
+	lookups := c.hits + c.misses
+	stats := c.statsLocked()
+	gens := c.fetchStartLocked(misses)
+	c.Unlock()
+
+	if lookups/statsLogInterval !=
+		(lookups-uint64(len(keys)))/statsLogInterval {
+		logStats(stats)
+	}
+
+	if len(misses) == 0 {
+		return blobs, nil
+	}
+
+	// Retrieve the remaining blobs from the store
+	reply, err := c.kv.Get(misses)
+
+	c.Lock()
+	defer c.Unlock()
+
+	stale := c.fetchDoneLocked(gens)
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range reply {
+		blobs[k] = v
+		if !c.cacheable(k) {
+			continue
+		}
+		if _, ok := stale[k]; ok {
+			// The blob was written to or deleted from the store while
+			// it was being retrieved. The retrieved blob may be stale.
+			continue
+		}
+		b := make([]byte, len(v))
+		copy(b, v)
+		c.addLocked(k, b)
+	}
+
+	return blobs, nil
+}
+
+// Keys returns all of the keys in the underlying store.
+//
+// This function satisfies the store BlobKV interface.
+func (c *BlobCache) Keys() ([]string, error) {
+	return c.kv.Keys()
+}
+
+// Close logs the cache statistics, clears the cache, and closes the
+// underlying store.
+//
+// This function satisfies the store BlobKV interface.
+func (c *BlobCache) Close() {
+	log.Tracef("Close")
+
+	c.Lock()
+	logStats(c.statsLocked())
+	c.lru.Init()
+	c.entries = make(map[string]*list.Element)
+	c.size = 0
+	c.Unlock()
+
+	c.kv.Close()
+}
+
+// New returns a new BlobCache that wraps the provided BlobKV. The cache will
+// hold at most maxSize bytes of blobs. Only blobs whose key passes the
+// cacheable filter will be cached.
+func New(kv store.BlobKV, maxSize int64, cacheable func(key string) bool) *BlobCache {
+	log.Infof("Blob cache size: %v bytes", maxSize)
+
+	return &BlobCache{
+		kv:        kv,
+		cacheable: cacheable,
+		maxSize:   maxSize,
+		lru:       list.New(),
+		entries:   make(map[string]*list.Element, 1024),
+		fetches:   make(map[string]*fetch),
+	}
+}
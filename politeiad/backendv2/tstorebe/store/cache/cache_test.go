@@ -0,0 +1,352 @@
+// Copyright (c) 2020-2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+)
+
+var (
+	_ store.BlobKV = (*testKV)(nil)
+)
+
+// testKV is an in-memory store BlobKV that counts the number of blobs that
+// have been retrieved from it. The get hook, if set, is called after the blobs
+// have been read and before they are returned.
+type testKV struct {
+	sync.Mutex
+	blobs   map[string][]byte
+	gets    int
+	getHook func()
+}
+
+func (kv *testKV) Put(blobs map[string][]byte, encrypt bool) error {
+	kv.Lock()
+	defer kv.Unlock()
+
+	for k, v := range blobs {
+		kv.blobs[k] = v
+	}
+	return nil
+}
+
+func (kv *testKV) Del(keys []string) error {
+	kv.Lock()
+	defer kv.Unlock()
+
+	for _, k := range keys {
+		delete(kv.blobs, k)
+	}
+	return nil
+}
+
+func (kv *testKV) Get(keys []string) (map[string][]byte, error) {
+	kv.Lock()
+	reply := make(map[string][]byte, len(keys))
+	for _, k := range keys {
+		v, ok := kv.blobs[k]
+		if !ok {
+			continue
+		}
+		kv.gets++
+		reply[k] = v
+	}
+	hook := kv.getHook
+	kv.Unlock()
+
+	if hook != nil {
+		hook()
+	}
+	return reply, nil
+}
+
+func (kv *testKV) Keys() ([]string, error) {
+	kv.Lock()
+	defer kv.Unlock()
+
+	keys := make([]string, 0, len(kv.blobs))
+	for k := range kv.blobs {
+		keys = append(keys, k)
+	}
+	return keys, nil
+}
+
+func (kv *testKV) Close() {}
+
+func newTestCache(maxSize int64) (*BlobCache, *testKV) {
+	kv := &testKV{
+		blobs: make(map[string][]byte),
+	}
+	c := New(kv, maxSize, func(key string) bool {
+		return !strings.HasPrefix(key, "e_")
+	})
+	return c, kv
+}
+
+func TestGet(t *testing.T) {
+	c, kv := newTestCache(1024)
+
+	blobs := map[string][]byte{
+		"a":   []byte("a"),
+		"e_b": []byte("b"),
+	}
+	err := c.Put(blobs, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Retrieve the blobs twice. The cacheable blob should only be
+	// retrieved from the underlying store once.
+	for i := 0; i < 2; i++ {
+		reply, err := c.Get([]string{"a", "e_b", "notfound"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(reply) != 2 {
+			t.Fatalf("got %v blobs, want 2", len(reply))
+		}
+		for k, v := range reply {
+			if !bytes.Equal(v, blobs[k]) {
+				t.Fatalf("blob %v: got %s, want %s", k, v, blobs[k])
+			}
+		}
+	}
+	if kv.gets != 3 {
+		t.Fatalf("got %v store gets, want 3", kv.gets)
+	}
+
+	s := c.Stats()
+	if s.Hits != 1 || s.Misses != 5 || s.Entries != 1 {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+
+	// Modifying a returned blob should not modify the cache
+	reply, err := c.Get([]string{"a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply["a"][0] = 'z'
+	reply, err = c.Get([]string{"a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(reply["a"], blobs["a"]) {
+		t.Fatalf("cached blob was modified")
+	}
+}
+
+func TestDel(t *testing.T) {
+	c, _ := newTestCache(1024)
+
+	err := c.Put(map[string][]byte{"a": []byte("a")}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.Get([]string{"a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Stats().Entries != 1 {
+		t.Fatalf("blob was not cached")
+	}
+
+	// Deleting the blob should remove it from the cache
+	err = c.Del([]string{"a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply, err := c.Get([]string{"a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reply) != 0 {
+		t.Fatalf("deleted blob was returned")
+	}
+	s := c.Stats()
+	if s.Entries != 0 || s.Size != 0 {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+}
+
+func TestEviction(t *testing.T) {
+	// Each entry is 2 bytes, one for the key and one for the blob. The
+	// cache can hold two entries.
+	c, _ := newTestCache(4)
+
+	err := c.Put(map[string][]byte{
+		"a": []byte("a"),
+		"b": []byte("b"),
+		"c": []byte("c"),
+		"d": []byte("toolarge"),
+	}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Cache a and b, then use a so that b is the least recently used
+	for _, v := range []string{"a", "b", "a"} {
+		_, err = c.Get([]string{v})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Caching c should evict b. Blobs that are larger than the cache
+	// should not be cached.
+	_, err = c.Get([]string{"c", "d"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := c.Stats()
+	if s.Entries != 2 || s.Size != 4 || s.Evictions != 1 {
+		t.Fatalf("unexpected stats %+v", s)
+	}
+	for _, v := range []string{"a", "c"} {
+		if _, ok := c.entries[v]; !ok {
+			t.Fatalf("blob %v not cached", v)
+		}
+	}
+}
+
+func TestStaleWriteBack(t *testing.T) {
+	var tests = []struct {
+		name  string
+		write func(c *BlobCache) error
+		want  []byte // Nil if the blob should not exist
+	}{
+		{
+			"put",
+			func(c *BlobCache) error {
+				return c.Put(map[string][]byte{"a": []byte("new")}, false)
+			},
+			[]byte("new"),
+		},
+		{
+			"del",
+			func(c *BlobCache) error {
+				return c.Del([]string{"a"})
+			},
+			nil,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, kv := newTestCache(1024)
+			err := c.Put(map[string][]byte{"a": []byte("old")}, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Block the lookup once it has read the old blob from
+			// the store.
+			var (
+				read    = make(chan struct{})
+				release = make(chan struct{})
+				once    sync.Once
+			)
+			kv.Lock()
+			kv.getHook = func() {
+				once.Do(func() {
+					close(read)
+					<-release
+				})
+			}
+			kv.Unlock()
+
+			done := make(chan error)
+			go func() {
+				_, err := c.Get([]string{"a"})
+				done <- err
+			}()
+
+			// Write the blob while the lookup is in progress, then let
+			// the lookup complete.
+			<-read
+			err = test.write(c)
+			if err != nil {
+				t.Fatal(err)
+			}
+			close(release)
+			err = <-done
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The stale blob must not have been cached
+			reply, err := c.Get([]string{"a"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			b, ok := reply["a"]
+			switch {
+			case test.want == nil && ok:
+				t.Fatalf("got deleted blob %s", b)
+			case test.want != nil && !bytes.Equal(b, test.want):
+				t.Fatalf("got blob %s, want %s", b, test.want)
+			}
+			if len(c.fetches) != 0 {
+				t.Fatalf("got %v in flight lookups, want 0", len(c.fetches))
+			}
+		})
+	}
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	c, _ := newTestCache(64)
+
+	// Concurrently write and read a set of keys. Every blob that is
+	// written contains the key and a version. Once all of the writes
+	// have completed, the cache must return the last version of each
+	// blob.
+	const (
+		keys     = 8
+		versions = 200
+	)
+	var wg sync.WaitGroup
+	for i := 0; i < keys; i++ {
+		key := fmt.Sprintf("%v", i)
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for v := 0; v < versions; v++ {
+				b := []byte(fmt.Sprintf("%v-%v", key, v))
+				err := c.Put(map[string][]byte{key: b}, false)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for v := 0; v < versions; v++ {
+				_, err := c.Get([]string{key})
+				if err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for i := 0; i < keys; i++ {
+		key := fmt.Sprintf("%v", i)
+		reply, err := c.Get([]string{key})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := fmt.Sprintf("%v-%v", key, versions-1)
+		if string(reply[key]) != want {
+			t.Errorf("got blob %s, want %v", reply[key], want)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package store
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	// ErrInvCursorInvalid is returned when an inventory query contains
+	// a cursor that could not be decoded.
+	ErrInvCursorInvalid = errors.New("inventory cursor invalid")
+)
+
+// InvEntry is an entry in the record inventory index.
+//
+// Created is the unix timestamp of when the record was created. Updated is the
+// unix timestamp of the most recent update to the record. Attributes contains
+// the record attributes that have been supplied by plugins. Attribute keys are
+// namespaced by plugin ID, e.g. ticketvote.votestatus.
+type InvEntry struct {
+	Token      string            `json:"token"` // Hex encoded full length token
+	State      uint32            `json:"state"`
+	Status     uint32            `json:"status"`
+	Created    int64             `json:"created"`
+	Updated    int64             `json:"updated"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// InvSortT represents the timestamp that an inventory query is sorted by.
+type InvSortT uint32
+
+const (
+	// InvSortUpdated sorts the inventory by the timestamp of the most
+	// recent record update.
+	InvSortUpdated InvSortT = 0
+
+	// InvSortCreated sorts the inventory by the record creation
+	// timestamp.
+	InvSortCreated InvSortT = 1
+)
+
+// InvQuery is a query for a page of inventory entries. The State, Status, and
+// Attributes fields are optional filters. A zero value State or Status matches
+// all entries. An entry must match all of the provided attributes.
+//
+// Entries are sorted from newest to oldest unless Ascending is set. Ties are
+// broken using the token. Cursor is the cursor that was returned with the
+// previous page. An empty cursor requests the first page.
+type InvQuery struct {
+	State      uint32
+	Status     uint32
+	Attributes map[string]string
+	Sort       InvSortT
+	Ascending  bool
+	Cursor     string
+	Limit      uint32
+}
+
+// InvPage is a page of inventory entries. Cursor can be used to request the
+// next page. It is empty if there are no more entries.
+type InvPage struct {
+	Entries []InvEntry
+	Cursor  string
+}
+
+// Inventory represents a store that maintains an indexed record inventory.
+// All operations are performed atomically.
+type Inventory interface {
+	// InvPut inserts or updates the provided inventory entries. The
+	// attributes of an existing entry are not modified. Attributes can
+	// only be updated using InvAttributesSet.
+	InvPut(entries []InvEntry) error
+
+	// InvAttributesSet sets attributes of an inventory entry. An empty
+	// attribute value deletes the attribute. Attributes that are not
+	// provided are not modified.
+	InvAttributesSet(token string, attrs map[string]string) error
+
+	// InvDel deletes the inventory entries for the provided tokens.
+	InvDel(tokens []string) error
+
+	// InvGet returns the inventory entries for the provided tokens. An
+	// entry will not exist in the returned map if it is not found.
+	InvGet(tokens []string) (map[string]InvEntry, error)
+
+	// InvQuery returns a page of inventory entries.
+	InvQuery(q InvQuery) (*InvPage, error)
+}
+
+// InvSortTimestamp returns the timestamp of the entry that the provided sort
+// uses.
+func InvSortTimestamp(e InvEntry, s InvSortT) int64 {
+	if s == InvSortCreated {
+		return e.Created
+	}
+	return e.Updated
+}
+
+// InvCursorEncode encodes an inventory cursor. The cursor points to the last
+// entry of a page.
+func InvCursorEncode(timestamp int64, token string) string {
+	c := strconv.FormatInt(timestamp, 10) + "." + token
+	return base64.RawURLEncoding.EncodeToString([]byte(c))
+}
+
+// InvCursorDecode decodes an inventory cursor into the timestamp and token of
+// the entry that it points to.
+func InvCursorDecode(cursor string) (int64, string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, "", ErrInvCursorInvalid
+	}
+	s := strings.SplitN(string(b), ".", 2)
+	if len(s) != 2 || s[1] == "" {
+		return 0, "", ErrInvCursorInvalid
+	}
+	ts, err := strconv.ParseInt(s[0], 10, 64)
+	if err != nil {
+		return 0, "", ErrInvCursorInvalid
+	}
+	return ts, s[1], nil
+}
+
+// InvAttributesMatch returns whether the entry contains all of the provided
+// attributes.
+func InvAttributesMatch(e InvEntry, attrs map[string]string) bool {
+	for k, v := range attrs {
+		if e.Attributes[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+const (
+	// InvAttributeKeyMaxLength is the maximum length of an inventory
+	// attribute key.
+	InvAttributeKeyMaxLength = 128
+
+	// InvAttributeValueMaxLength is the maximum length of an inventory
+	// attribute value.
+	InvAttributeValueMaxLength = 255
+)
+
+// InvAttributesVerify verifies that the provided attributes do not exceed the
+// maximum lengths that are supported by the stores.
+func InvAttributesVerify(attrs map[string]string) error {
+	for k, v := range attrs {
+		if k == "" || len(k) > InvAttributeKeyMaxLength {
+			return fmt.Errorf("invalid attribute key '%v'", k)
+		}
+		if len(v) > InvAttributeValueMaxLength {
+			return fmt.Errorf("attribute %v value exceeds max length", k)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2020-2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package store
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/decred/politeia/util"
+	"github.com/decred/slog"
+	"golang.org/x/crypto/argon2"
+)
+
+// EncryptionKeyParams is saved to the kv store on initial derivation of an
+// encryption key. It contains the params that were used to derive the key and
+// a SHA256 digest of the key. Subsequent derivations will use the existing
+// params to derive the key and will use the digest to verify that the
+// encryption key has not changed.
+//
+// The keys that are created by a key rotation are not derived from the
+// password. They are loaded from a key file instead so that a compromised
+// password does not compromise the rotated keys. The path of the key file is
+// saved in place of the argon2id params.
+type EncryptionKeyParams struct {
+	Digest  []byte            `json:"digest"` // SHA256 digest
+	Params  util.Argon2Params `json:"params"`
+	KeyFile string            `json:"keyfile,omitempty"`
+}
+
+// EncryptionKeyParamsKey returns the kv store key for the params of the
+// encryption key with the provided ID. The prefix is the unversioned kv store
+// key of the BlobKV. The key with ID 0 uses the unversioned kv store key so
+// that stores that were created prior to the addition of key rotation
+// continue to work.
+func EncryptionKeyParamsKey(prefix string, keyID uint32) string {
+	if keyID == 0 {
+		return prefix
+	}
+	return fmt.Sprintf("%v-%v", prefix, keyID)
+}
+
+// ParseEncryptionKeyParamsKey parses the encryption key ID from the provided
+// encryption key params kv store key.
+func ParseEncryptionKeyParamsKey(prefix, key string) (uint32, error) {
+	if key == prefix {
+		return 0, nil
+	}
+	s := strings.TrimPrefix(key, prefix+"-")
+	keyID, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("invalid encryption key params key %v", key)
+	}
+	return uint32(keyID), nil
+}
+
+// Argon2idKey derives an encryption key using the provided parameters and the
+// Argon2id key derivation function.
+func Argon2idKey(password []byte, ap util.Argon2Params) *[32]byte {
+	var key [32]byte
+	k := argon2.IDKey(password, ap.Salt, ap.Time, ap.Memory,
+		ap.Threads, ap.KeyLen)
+	copy(key[:], k)
+	util.Zero(k)
+	return &key
+}
+
+// encryptionKeyParamsSave saves the provided encryption key params to the kv
+// store under the provided key ID.
+func encryptionKeyParamsSave(kv BlobKV, prefix string, keyID uint32, ekp EncryptionKeyParams) error {
+	b, err := json.Marshal(ekp)
+	if err != nil {
+		return err
+	}
+	blobs := map[string][]byte{
+		EncryptionKeyParamsKey(prefix, keyID): b,
+	}
+	err = kv.Put(blobs, false)
+	if err != nil {
+		return fmt.Errorf("put: %v", err)
+	}
+	return nil
+}
+
+// NewEncryptionKey derives a new encryption key from the password using new
+// argon2id params and saves the params to the kv store under the provided key
+// ID.
+func NewEncryptionKey(kv BlobKV, prefix string, password []byte, keyID uint32) (*[32]byte, error) {
+	ekp := EncryptionKeyParams{
+		Params: util.NewArgon2Params(),
+	}
+	key := Argon2idKey(password, ekp.Params)
+	ekp.Digest = util.Digest(key[:])
+	err := encryptionKeyParamsSave(kv, prefix, keyID, ekp)
+	if err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// EncryptionKeyFile returns the default path of the key file of the rotated
+// encryption key with the provided ID. The prefix is the unversioned kv store
+// key of the BlobKV.
+func EncryptionKeyFile(appDir, prefix string, keyID uint32) string {
+	return filepath.Join(appDir, EncryptionKeyParamsKey(prefix, keyID)+".key")
+}
+
+// NewEncryptionKeyFromFile loads a new encryption key from the provided key
+// file and saves the key file path to the kv store under the provided key ID.
+// The key file is created using a new random key if it does not exist. The
+// key must not already be in the key ring.
+func NewEncryptionKeyFromFile(log slog.Logger, kv BlobKV, r *KeyRing, prefix, keyFile string, keyID uint32) (*[32]byte, error) {
+	keyFile, err := filepath.Abs(util.CleanAndExpandPath(keyFile))
+	if err != nil {
+		return nil, err
+	}
+	key, err := util.LoadEncryptionKey(log, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	if id, ok := r.Contains(key); ok {
+		util.Zero(key[:])
+		return nil, fmt.Errorf("key file %v contains encryption key %v; "+
+			"a new key is required", keyFile, id)
+	}
+	ekp := EncryptionKeyParams{
+		Digest:  util.Digest(key[:]),
+		KeyFile: keyFile,
+	}
+	err = encryptionKeyParamsSave(kv, prefix, keyID, ekp)
+	if err != nil {
+		util.Zero(key[:])
+		return nil, err
+	}
+	return key, nil
+}
+
+// DeriveEncryptionKeys derives the 32 byte encryption keys from the provided
+// password using the Aragon2id key derivation function. The params of each
+// key are retrieved from the kv store using the provided params keys, then
+// the saved encryption key digests are used to verify that the keys have not
+// changed. The keys that were created by a key rotation are loaded from their
+// key files instead. The keys are added to the key ring.
+//
+// If no params keys are provided then this is the first time that a key is
+// being derived. A new key is created and its params are saved to the kv
+// store for future use.
+func DeriveEncryptionKeys(log slog.Logger, kv BlobKV, r *KeyRing, prefix string, password []byte, paramsKeys []string) error {
+	if len(paramsKeys) == 0 {
+		key, err := NewEncryptionKey(kv, prefix, password, 0)
+		if err != nil {
+			return err
+		}
+		r.Add(0, key)
+		return nil
+	}
+
+	blobs, err := kv.Get(paramsKeys)
+	if err != nil {
+		return fmt.Errorf("get: %v", err)
+	}
+	for _, v := range paramsKeys {
+		keyID, err := ParseEncryptionKeyParamsKey(prefix, v)
+		if err != nil {
+			return err
+		}
+		b, ok := blobs[v]
+		if !ok {
+			return fmt.Errorf("encryption key params not found %v", v)
+		}
+		var ekp EncryptionKeyParams
+		err = json.Unmarshal(b, &ekp)
+		if err != nil {
+			return err
+		}
+
+		// Derive or load the key and verify that the key has not
+		// changed
+		var key *[32]byte
+		if ekp.KeyFile != "" {
+			if !util.FileExists(ekp.KeyFile) {
+				return fmt.Errorf("encryption key %v file not found: %v",
+					keyID, ekp.KeyFile)
+			}
+			key, err = util.LoadEncryptionKey(log, ekp.KeyFile)
+			if err != nil {
+				return err
+			}
+		} else {
+			key = Argon2idKey(password, ekp.Params)
+		}
+		if !bytes.Equal(ekp.Digest, util.Digest(key[:])) {
+			return fmt.Errorf("attempting to use different encryption key")
+		}
+
+		r.Add(keyID, key)
+	}
+
+	return nil
+}
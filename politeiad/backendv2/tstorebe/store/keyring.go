@@ -0,0 +1,436 @@
+// Copyright (c) 2020-2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package store
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/decred/politeia/util"
+	"github.com/decred/slog"
+	"github.com/marcopeereboom/sbox"
+)
+
+// IsEncrypted returns whether the provided blob has been prefixed with an sbox
+// header, indicating that it is an encrypted blob.
+func IsEncrypted(b []byte) bool {
+	return bytes.HasPrefix(b, []byte("sbox"))
+}
+
+// KeyRing contains the encryption keys of a BlobKV that supports key rotation.
+// Encrypted blobs carry the ID of the key that was used to encrypt them in the
+// sbox header version field. The key with the highest ID is the active key.
+// Blobs are only ever encrypted using the active key. Prior keys are retained
+// until all blobs have been re-encrypted using the active key.
+//
+// The KeyRing does not persist the keys or read the blobs. The BlobKV is
+// responsible for both and provides them to the KeyRing using callbacks.
+//
+// The read lock must be held for the duration of any operation that encrypts
+// or decrypts blobs. The write lock is required to change the active key, so a
+// key rotation waits for any in-flight writes that are using the prior key to
+// complete.
+type KeyRing struct {
+	sync.RWMutex
+	keys  map[uint32]*[32]byte // [keyID]key
+	keyID uint32               // Active key ID
+
+	// rotateMtx serializes key rotations and key retirements.
+	rotateMtx sync.Mutex
+}
+
+// Add adds a key to the key ring. The key becomes the active key if it has the
+// highest ID of all keys in the key ring. This is used to load the existing
+// keys on startup.
+func (r *KeyRing) Add(keyID uint32, key *[32]byte) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.keys[keyID] = key
+	if keyID > r.keyID {
+		r.keyID = keyID
+	}
+}
+
+// ActiveKeyID returns the ID of the active key.
+func (r *KeyRing) ActiveKeyID() uint32 {
+	r.RLock()
+	defer r.RUnlock()
+
+	return r.keyID
+}
+
+// Encrypt encrypts the provided data using the active key and a random nonce.
+// The ID of the active key is used as the sbox header version.
+//
+// This function must be called WITH the read lock held.
+func (r *KeyRing) Encrypt(data []byte) ([]byte, error) {
+	return sbox.Encrypt(r.keyID, r.keys[r.keyID], data)
+}
+
+// EncryptN encrypts the provided data using the active key and the provided
+// nonce. The ID of the active key is used as the sbox header version.
+//
+// This function must be called WITH the read lock held.
+func (r *KeyRing) EncryptN(nonce [24]byte, data []byte) ([]byte, error) {
+	return sbox.EncryptN(r.keyID, r.keys[r.keyID], nonce, data)
+}
+
+// Decrypt decrypts the provided blob and returns the decrypted data along with
+// the ID of the key that was used to encrypt it. Decryption is attempted using
+// the active key first, since the majority of blobs will have been encrypted
+// using it, then using any prior keys.
+//
+// This function must be called WITH the read lock held.
+func (r *KeyRing) Decrypt(data []byte) ([]byte, uint32, error) {
+	b, keyID, err := sbox.Decrypt(r.keys[r.keyID], data)
+	if err == nil {
+		if keyID != r.keyID {
+			return nil, 0, fmt.Errorf("key id mismatch: got %v, want %v",
+				keyID, r.keyID)
+		}
+		return b, keyID, nil
+	}
+	for id, key := range r.keys {
+		if id == r.keyID {
+			continue
+		}
+		b, keyID, err := sbox.Decrypt(key, data)
+		if err != nil {
+			continue
+		}
+		if keyID != id {
+			return nil, 0, fmt.Errorf("key id mismatch: got %v, want %v",
+				keyID, id)
+		}
+		return b, keyID, nil
+	}
+	return nil, 0, fmt.Errorf("unable to decrypt blob: %v", err)
+}
+
+// Reencrypt re-encrypts the provided blob using the active key. The encrypt
+// function is used to encrypt the blob so that the BlobKV can choose how the
+// nonce is created. The returned bool indicates whether the blob was
+// re-encrypted. Blobs that are not encrypted or that are already encrypted
+// using the active key are not re-encrypted.
+//
+// This function must be called WITH the read lock held.
+func (r *KeyRing) Reencrypt(blob []byte, encrypt func([]byte) ([]byte, error)) ([]byte, bool, error) {
+	if !IsEncrypted(blob) {
+		return nil, false, nil
+	}
+	b, keyID, err := r.Decrypt(blob)
+	if err != nil {
+		return nil, false, fmt.Errorf("decrypt: %v", err)
+	}
+	if keyID == r.keyID {
+		// Already encrypted using the active key
+		return nil, false, nil
+	}
+	e, err := encrypt(b)
+	if err != nil {
+		return nil, false, fmt.Errorf("encrypt: %v", err)
+	}
+	return e, true, nil
+}
+
+// KeyIDsInUse adds the IDs of the keys that were used to encrypt the provided
+// blobs to the in use map. Blobs that are not encrypted are ignored.
+//
+// This function must be called WITH the read lock held.
+func (r *KeyRing) KeyIDsInUse(blobs map[string][]byte, inUse map[uint32]struct{}) error {
+	for k, v := range blobs {
+		if !IsEncrypted(v) {
+			continue
+		}
+		_, keyID, err := r.Decrypt(v)
+		if err != nil {
+			return fmt.Errorf("decrypt %v: %v", k, err)
+		}
+		inUse[keyID] = struct{}{}
+	}
+	return nil
+}
+
+// Rotate creates a new key using the provided function and makes it the active
+// key. The new key must be persisted by the function before it returns so
+// that the key can be loaded again on startup. The ID of the new key is
+// returned.
+func (r *KeyRing) Rotate(newKey func(keyID uint32) (*[32]byte, error)) (uint32, error) {
+	r.rotateMtx.Lock()
+	defer r.rotateMtx.Unlock()
+
+	keyID := r.ActiveKeyID() + 1
+	key, err := newKey(keyID)
+	if err != nil {
+		return 0, err
+	}
+
+	// Make the new key the active key. The write lock waits for any
+	// in-flight puts that are using the prior key to complete.
+	r.Lock()
+	r.keys[keyID] = key
+	r.keyID = keyID
+	r.Unlock()
+
+	return keyID, nil
+}
+
+// Retire removes all keys, other than the active key, that are no longer used
+// by any blob. The inUse function returns the IDs of the keys that are used by
+// at least one blob in the BlobKV. The del function deletes the persisted keys
+// and is called WITH the write lock held. The IDs of the retired keys are
+// returned.
+func (r *KeyRing) Retire(inUse func() (map[uint32]struct{}, error), del func(keyIDs []uint32) error) ([]uint32, error) {
+	// The active key cannot change while the rotate mutex is held.
+	// Blobs are only ever encrypted using the active key, so the
+	// number of blobs that use a prior key can only decrease once the
+	// prior keys have been determined.
+	r.rotateMtx.Lock()
+	defer r.rotateMtx.Unlock()
+
+	r.RLock()
+	prior := make([]uint32, 0, len(r.keys))
+	for keyID := range r.keys {
+		if keyID != r.keyID {
+			prior = append(prior, keyID)
+		}
+	}
+	r.RUnlock()
+	if len(prior) == 0 {
+		return []uint32{}, nil
+	}
+
+	// Find the prior keys that are no longer used by any blob
+	used, err := inUse()
+	if err != nil {
+		return nil, err
+	}
+	retire := make([]uint32, 0, len(prior))
+	for _, keyID := range prior {
+		if _, ok := used[keyID]; ok {
+			continue
+		}
+		retire = append(retire, keyID)
+	}
+	if len(retire) == 0 {
+		return []uint32{}, nil
+	}
+	sort.Slice(retire, func(i, j int) bool {
+		return retire[i] < retire[j]
+	})
+
+	// Delete the retired keys. The write lock waits for any in-flight
+	// gets that may have read a blob prior to it being re-encrypted.
+	r.Lock()
+	defer r.Unlock()
+
+	err = del(retire)
+	if err != nil {
+		return nil, err
+	}
+	for _, keyID := range retire {
+		util.Zero(r.keys[keyID][:])
+		delete(r.keys, keyID)
+	}
+
+	return retire, nil
+}
+
+// Contains returns the ID of the provided key if the key is in the key ring.
+func (r *KeyRing) Contains(key *[32]byte) (uint32, bool) {
+	r.RLock()
+	defer r.RUnlock()
+
+	for keyID, v := range r.keys {
+		if bytes.Equal(v[:], key[:]) {
+			return keyID, true
+		}
+	}
+	return 0, false
+}
+
+// KeyIDs returns the IDs of all keys that have not been retired and the ID of
+// the active key.
+func (r *KeyRing) KeyIDs() ([]uint32, uint32) {
+	r.RLock()
+	defer r.RUnlock()
+
+	keyIDs := make([]uint32, 0, len(r.keys))
+	for keyID := range r.keys {
+		keyIDs = append(keyIDs, keyID)
+	}
+	sort.Slice(keyIDs, func(i, j int) bool {
+		return keyIDs[i] < keyIDs[j]
+	})
+
+	return keyIDs, r.keyID
+}
+
+// Zero zeroes all of the keys in the key ring.
+func (r *KeyRing) Zero() {
+	r.Lock()
+	defer r.Unlock()
+
+	for _, v := range r.keys {
+		util.Zero(v[:])
+	}
+}
+
+// NewKeyRing returns a new KeyRing that does not contain any keys.
+func NewKeyRing() *KeyRing {
+	return &KeyRing{
+		keys: make(map[uint32]*[32]byte),
+	}
+}
+
+// RotatorCallbacks contains the BlobKV specific operations that are required
+// by a Rotator.
+type RotatorCallbacks struct {
+	// IsShutdown returns whether the BlobKV has been shut down.
+	IsShutdown func() bool
+
+	// KeyNew creates a new key using the provided key file and persists
+	// it under the provided key ID. An empty key file means that the
+	// BlobKV should use its default key file for the key ID.
+	KeyNew func(keyID uint32, keyFile string) (*[32]byte, error)
+
+	// KeysDel deletes the persisted keys. It is called WITH the key ring
+	// write lock held.
+	KeysDel func(keyIDs []uint32) error
+
+	// Update atomically replaces the provided blobs with the blobs that
+	// are returned by the update function. The BlobKV provides the
+	// function that is used to encrypt the updated blobs so that it can
+	// choose how the nonce is created. The update function returns false
+	// for blobs that do not need to be replaced. Missing blobs are
+	// skipped. The number of blobs that were replaced is returned. It is
+	// called WITH the key ring read lock held.
+	Update func(keys []string, update func(blob []byte, encrypt func([]byte) ([]byte, error)) ([]byte, bool, error)) (uint32, error)
+
+	// Scan calls the provided function with every blob in the BlobKV.
+	// The blobs may be provided in batches. It is called WITHOUT the
+	// key ring lock held.
+	Scan func(fn func(blobs map[string][]byte) error) error
+}
+
+// Rotator implements the KeyRotator interface for a BlobKV that encrypts its
+// blobs using a KeyRing. The BlobKV provides the operations that depend on
+// its storage using the RotatorCallbacks.
+type Rotator struct {
+	log     slog.Logger
+	keyRing *KeyRing
+	cb      RotatorCallbacks
+}
+
+var (
+	_ KeyRotator = (*Rotator)(nil)
+)
+
+// RotateKey creates a new encryption key from the provided key file and makes
+// it the active key. The ID of the new key is returned.
+//
+// This function satisfies the KeyRotator interface.
+func (r *Rotator) RotateKey(keyFile string) (uint32, error) {
+	r.log.Tracef("RotateKey: %v", keyFile)
+
+	if r.cb.IsShutdown() {
+		return 0, ErrShutdown
+	}
+
+	// The new key must be persisted before it is used so that it can
+	// be loaded again on startup.
+	keyID, err := r.keyRing.Rotate(func(keyID uint32) (*[32]byte, error) {
+		return r.cb.KeyNew(keyID, keyFile)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	r.log.Infof("Encryption key rotated; active key ID %v", keyID)
+
+	return keyID, nil
+}
+
+// Reencrypt re-encrypts the provided blobs using the active key. This
+// operation is performed atomically.
+//
+// This function satisfies the KeyRotator interface.
+func (r *Rotator) Reencrypt(keys []string) (uint32, error) {
+	r.log.Tracef("Reencrypt: %v blobs", len(keys))
+
+	if r.cb.IsShutdown() {
+		return 0, ErrShutdown
+	}
+
+	r.keyRing.RLock()
+	defer r.keyRing.RUnlock()
+
+	count, err := r.cb.Update(keys, r.keyRing.Reencrypt)
+	if err != nil {
+		return 0, err
+	}
+
+	r.log.Debugf("Re-encrypted blobs (%v) in store", count)
+
+	return count, nil
+}
+
+// keyIDsInUse returns the IDs of the encryption keys that are used by at
+// least one blob in the BlobKV.
+func (r *Rotator) keyIDsInUse() (map[uint32]struct{}, error) {
+	inUse := make(map[uint32]struct{})
+	err := r.cb.Scan(func(blobs map[string][]byte) error {
+		r.keyRing.RLock()
+		defer r.keyRing.RUnlock()
+
+		return r.keyRing.KeyIDsInUse(blobs, inUse)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return inUse, nil
+}
+
+// RetireKeys deletes all encryption keys, other than the active key, that are
+// no longer used by any blob. The IDs of the retired keys are returned.
+//
+// This function satisfies the KeyRotator interface.
+func (r *Rotator) RetireKeys() ([]uint32, error) {
+	r.log.Tracef("RetireKeys")
+
+	if r.cb.IsShutdown() {
+		return nil, ErrShutdown
+	}
+
+	retired, err := r.keyRing.Retire(r.keyIDsInUse, r.cb.KeysDel)
+	if err != nil {
+		return nil, err
+	}
+	if len(retired) > 0 {
+		r.log.Infof("Encryption keys retired: %v", retired)
+	}
+
+	return retired, nil
+}
+
+// KeyIDs returns the IDs of all encryption keys that have not been retired
+// and the ID of the active key.
+//
+// This function satisfies the KeyRotator interface.
+func (r *Rotator) KeyIDs() ([]uint32, uint32) {
+	return r.keyRing.KeyIDs()
+}
+
+// NewRotator returns a new Rotator.
+func NewRotator(log slog.Logger, r *KeyRing, cb RotatorCallbacks) *Rotator {
+	return &Rotator{
+		log:     log,
+		keyRing: r,
+		cb:      cb,
+	}
+}
@@ -0,0 +1,368 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package localdb
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/iterator"
+	lutil "github.com/syndtr/goleveldb/leveldb/util"
+)
+
+const (
+	// keyPrefixInvEntry is the key prefix of inventory entries. The
+	// full key is the prefix followed by the record token.
+	keyPrefixInvEntry = "inv_e_"
+
+	// keyPrefixInvIndex is the key prefix of the inventory indexes.
+	// Leveldb keys are sorted, so the inventory is indexed by creating
+	// a key for each entry that contains the sort timestamp. Every
+	// entry is indexed under three prefixes for each sort: all
+	// entries, entries of the same state, and entries of the same
+	// state and status. The index values contain the full entry so
+	// that filters can be applied without additional lookups.
+	keyPrefixInvIndex = "inv_i_"
+)
+
+var (
+	_ store.Inventory = (*localdb)(nil)
+)
+
+// invEntryKey returns the key of the inventory entry for a token.
+func invEntryKey(token string) []byte {
+	return []byte(keyPrefixInvEntry + token)
+}
+
+// invIndexPrefix returns the key prefix of an inventory index. A zero value
+// state or status indicates that the index is not filtered by it.
+func invIndexPrefix(s store.InvSortT, state, status uint32) string {
+	return fmt.Sprintf("%v%v_%v_%v_", keyPrefixInvIndex, s, state, status)
+}
+
+// invIndexKey returns the inventory index key for the provided prefix,
+// timestamp, and token. The timestamp is encoded as fixed length hex so that
+// the keys sort by timestamp.
+func invIndexKey(prefix string, timestamp int64, token string) []byte {
+	return []byte(fmt.Sprintf("%v%016x_%v", prefix, uint64(timestamp), token))
+}
+
+// invIndexKeys returns all of the inventory index keys of an entry.
+func invIndexKeys(e store.InvEntry) [][]byte {
+	keys := make([][]byte, 0, 6)
+	for _, s := range []store.InvSortT{
+		store.InvSortUpdated, store.InvSortCreated,
+	} {
+		ts := store.InvSortTimestamp(e, s)
+		keys = append(keys,
+			invIndexKey(invIndexPrefix(s, 0, 0), ts, e.Token),
+			invIndexKey(invIndexPrefix(s, e.State, 0), ts, e.Token),
+			invIndexKey(invIndexPrefix(s, e.State, e.Status), ts, e.Token))
+	}
+	return keys
+}
+
+// invGet returns the inventory entry for a token. Nil is returned if the entry
+// does not exist.
+func (l *localdb) invGet(token string) (*store.InvEntry, error) {
+	b, err := l.db.Get(invEntryKey(token), nil)
+	if err != nil {
+		if errors.Is(err, leveldb.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var e store.InvEntry
+	err = json.Unmarshal(b, &e)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// invBatchPut adds the writes for an inventory entry to the provided batch.
+// The index keys of the prior version of the entry are deleted.
+func invBatchPut(batch *leveldb.Batch, prev *store.InvEntry, e store.InvEntry) error {
+	if prev != nil {
+		for _, k := range invIndexKeys(*prev) {
+			batch.Delete(k)
+		}
+	}
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	batch.Put(invEntryKey(e.Token), b)
+	for _, k := range invIndexKeys(e) {
+		batch.Put(k, b)
+	}
+	return nil
+}
+
+// InvPut inserts or updates the provided inventory entries. The attributes of
+// an existing entry are not modified. This operation is performed atomically.
+//
+// This function satisfies the store Inventory interface.
+func (l *localdb) InvPut(entries []store.InvEntry) error {
+	log.Tracef("InvPut: %v entries", len(entries))
+
+	if l.isShutdown() {
+		return store.ErrShutdown
+	}
+
+	// The inventory mutex is held so that the index keys of the prior
+	// entries cannot be changed by a concurrent write.
+	l.invMtx.Lock()
+	defer l.invMtx.Unlock()
+
+	var (
+		batch   = new(leveldb.Batch)
+		pending = make(map[string]store.InvEntry, len(entries))
+	)
+	for _, e := range entries {
+		prev, err := l.invGet(e.Token)
+		if err != nil {
+			return err
+		}
+		if p, ok := pending[e.Token]; ok {
+			prev = &p
+		}
+		e.Attributes = nil
+		if prev != nil {
+			e.Attributes = prev.Attributes
+		}
+		err = invBatchPut(batch, prev, e)
+		if err != nil {
+			return err
+		}
+		pending[e.Token] = e
+	}
+	err := l.db.Write(batch, nil)
+	if err != nil {
+		return fmt.Errorf("write batch: %v", err)
+	}
+
+	return nil
+}
+
+// InvAttributesSet sets attributes of an inventory entry. An empty attribute
+// value deletes the attribute. This operation is performed atomically.
+//
+// This function satisfies the store Inventory interface.
+func (l *localdb) InvAttributesSet(token string, attrs map[string]string) error {
+	log.Tracef("InvAttributesSet: %v %v", token, attrs)
+
+	if l.isShutdown() {
+		return store.ErrShutdown
+	}
+
+	l.invMtx.Lock()
+	defer l.invMtx.Unlock()
+
+	prev, err := l.invGet(token)
+	if err != nil {
+		return err
+	}
+	if prev == nil {
+		return fmt.Errorf("inventory entry not found: %v", token)
+	}
+	e := *prev
+	e.Attributes = make(map[string]string, len(prev.Attributes)+len(attrs))
+	for k, v := range prev.Attributes {
+		e.Attributes[k] = v
+	}
+	for k, v := range attrs {
+		if v == "" {
+			delete(e.Attributes, k)
+			continue
+		}
+		e.Attributes[k] = v
+	}
+	batch := new(leveldb.Batch)
+	err = invBatchPut(batch, prev, e)
+	if err != nil {
+		return err
+	}
+	err = l.db.Write(batch, nil)
+	if err != nil {
+		return fmt.Errorf("write batch: %v", err)
+	}
+
+	return nil
+}
+
+// InvDel deletes the inventory entries for the provided tokens. This operation
+// is performed atomically.
+//
+// This function satisfies the store Inventory interface.
+func (l *localdb) InvDel(tokens []string) error {
+	log.Tracef("InvDel: %v", tokens)
+
+	if l.isShutdown() {
+		return store.ErrShutdown
+	}
+
+	l.invMtx.Lock()
+	defer l.invMtx.Unlock()
+
+	batch := new(leveldb.Batch)
+	for _, v := range tokens {
+		e, err := l.invGet(v)
+		if err != nil {
+			return err
+		}
+		if e == nil {
+			continue
+		}
+		batch.Delete(invEntryKey(v))
+		for _, k := range invIndexKeys(*e) {
+			batch.Delete(k)
+		}
+	}
+	err := l.db.Write(batch, nil)
+	if err != nil {
+		return fmt.Errorf("write batch: %v", err)
+	}
+
+	return nil
+}
+
+// InvGet returns the inventory entries for the provided tokens.
+//
+// This function satisfies the store Inventory interface.
+func (l *localdb) InvGet(tokens []string) (map[string]store.InvEntry, error) {
+	log.Tracef("InvGet: %v", tokens)
+
+	if l.isShutdown() {
+		return nil, store.ErrShutdown
+	}
+
+	entries := make(map[string]store.InvEntry, len(tokens))
+	for _, v := range tokens {
+		e, err := l.invGet(v)
+		if err != nil {
+			return nil, err
+		}
+		if e == nil {
+			continue
+		}
+		entries[v] = *e
+	}
+
+	return entries, nil
+}
+
+// InvQuery returns a page of inventory entries.
+//
+// This function satisfies the store Inventory interface.
+func (l *localdb) InvQuery(q store.InvQuery) (*store.InvPage, error) {
+	log.Tracef("InvQuery: %+v", q)
+
+	if l.isShutdown() {
+		return nil, store.ErrShutdown
+	}
+
+	// Use the most specific index that is available for the filters.
+	// Entries are only indexed by status in combination with state.
+	var prefix string
+	switch {
+	case q.State != 0 && q.Status != 0:
+		prefix = invIndexPrefix(q.Sort, q.State, q.Status)
+	case q.State != 0:
+		prefix = invIndexPrefix(q.Sort, q.State, 0)
+	default:
+		prefix = invIndexPrefix(q.Sort, 0, 0)
+	}
+	var cursor []byte
+	if q.Cursor != "" {
+		ts, token, err := store.InvCursorDecode(q.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		cursor = invIndexKey(prefix, ts, token)
+	}
+
+	iter := l.db.NewIterator(lutil.BytesPrefix([]byte(prefix)), nil)
+	defer iter.Release()
+
+	// Position the iterator on the first entry of the page. The cursor
+	// points to the last entry of the previous page, which may have
+	// since been updated and no longer exist.
+	var (
+		ok      bool
+		advance func() bool
+	)
+	switch {
+	case q.Ascending && cursor == nil:
+		ok, advance = iter.First(), iter.Next
+	case q.Ascending:
+		ok, advance = iter.Seek(cursor), iter.Next
+		if ok && string(iter.Key()) == string(cursor) {
+			ok = iter.Next()
+		}
+	case cursor == nil:
+		ok, advance = iter.Last(), iter.Prev
+	default:
+		advance = iter.Prev
+		if iter.Seek(cursor) {
+			ok = iter.Prev()
+		} else {
+			ok = iter.Last()
+		}
+	}
+
+	// Compile the page. One additional entry is looked up to determine
+	// whether there are more entries.
+	entries, err := invIterate(iter, ok, advance, q)
+	if err != nil {
+		return nil, err
+	}
+	var next string
+	if uint32(len(entries)) > q.Limit {
+		entries = entries[:q.Limit]
+		last := entries[len(entries)-1]
+		next = store.InvCursorEncode(store.InvSortTimestamp(last, q.Sort),
+			last.Token)
+	}
+
+	return &store.InvPage{
+		Entries: entries,
+		Cursor:  next,
+	}, nil
+}
+
+// invIterate returns up to q.Limit+1 entries that match the query filters,
+// starting at the current iterator position.
+func invIterate(iter iterator.Iterator, ok bool, advance func() bool, q store.InvQuery) ([]store.InvEntry, error) {
+	entries := make([]store.InvEntry, 0, q.Limit+1)
+	if q.Limit == 0 {
+		return entries, nil
+	}
+	for ; ok; ok = advance() {
+		var e store.InvEntry
+		err := json.Unmarshal(iter.Value(), &e)
+		if err != nil {
+			return nil, err
+		}
+		if q.Status != 0 && e.Status != q.Status {
+			continue
+		}
+		if !store.InvAttributesMatch(e, q.Attributes) {
+			continue
+		}
+		entries = append(entries, e)
+		if uint32(len(entries)) > q.Limit {
+			break
+		}
+	}
+	err := iter.Error()
+	if err != nil {
+		return nil, fmt.Errorf("iterator: %v", err)
+	}
+	return entries, nil
+}
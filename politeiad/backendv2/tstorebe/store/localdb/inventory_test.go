@@ -0,0 +1,41 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package localdb
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+)
+
+func newTestLocaldb(t *testing.T) (*localdb, func()) {
+	t.Helper()
+
+	appDir, err := ioutil.TempDir("", "localdb.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	l, err := New(appDir, filepath.Join(appDir, "data"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return l, func() {
+		l.Close()
+		err = os.RemoveAll(appDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestInventory(t *testing.T) {
+	store.InvTest(t, func(t *testing.T) (store.Inventory, func()) {
+		return newTestLocaldb(t)
+	})
+}
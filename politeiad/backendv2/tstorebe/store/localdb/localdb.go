@@ -5,21 +5,24 @@
 package localdb
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
 	"sync/atomic"
 
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
 	"github.com/decred/politeia/util"
-	"github.com/marcopeereboom/sbox"
 	"github.com/syndtr/goleveldb/leveldb"
 )
 
 const (
 	// encryptionKeyFilename is the filename of the encryption key that
-	// is created in the store data directory.
+	// is created in the store data directory. Keys that are created
+	// when the encryption key is rotated include the key ID in the
+	// filename, e.g. leveldb-sbox-1.key.
 	encryptionKeyFilename = "leveldb-sbox.key"
 )
 
@@ -36,19 +39,25 @@ var (
 type localdb struct {
 	shutdown uint64
 	db       *leveldb.DB
-	key      [32]byte
-}
+	appDir   string
+	keyRing  *store.KeyRing
 
-func (l *localdb) isShutdown() bool {
-	return atomic.LoadUint64(&l.shutdown) != 0
-}
+	// Rotator implements the store KeyRotator interface using the
+	// key ring.
+	*store.Rotator
 
-func (l *localdb) encrypt(data []byte) ([]byte, error) {
-	return sbox.Encrypt(0, &l.key, data)
+	// writeMtx prevents a blob that is being re-encrypted from being
+	// deleted or overwritten between the read and the write of the
+	// re-encrypted blob.
+	writeMtx sync.Mutex
+
+	// invMtx serializes inventory writes. Inventory writes must read
+	// the prior entry in order to update its index keys.
+	invMtx sync.Mutex
 }
 
-func (l *localdb) decrypt(data []byte) ([]byte, uint32, error) {
-	return sbox.Decrypt(&l.key, data)
+func (l *localdb) isShutdown() bool {
+	return atomic.LoadUint64(&l.shutdown) != 0
 }
 
 // Put saves the provided key-value pairs to the store. This operation is
@@ -62,10 +71,19 @@ func (l *localdb) Put(blobs map[string][]byte, encrypt bool) error {
 		return store.ErrShutdown
 	}
 
+	// The key ring read lock is held for the duration of the put so
+	// that a key rotation cannot complete while blobs are being
+	// encrypted using the prior key.
+	l.keyRing.RLock()
+	defer l.keyRing.RUnlock()
+
+	l.writeMtx.Lock()
+	defer l.writeMtx.Unlock()
+
 	// Encrypt blobs
 	if encrypt {
 		for k, v := range blobs {
-			e, err := l.encrypt(v)
+			e, err := l.keyRing.Encrypt(v)
 			if err != nil {
 				return fmt.Errorf("encrypt: %v", err)
 			}
@@ -101,6 +119,9 @@ func (l *localdb) Del(keys []string) error {
 		return store.ErrShutdown
 	}
 
+	l.writeMtx.Lock()
+	defer l.writeMtx.Unlock()
+
 	batch := new(leveldb.Batch)
 	for _, v := range keys {
 		batch.Delete([]byte(v))
@@ -115,12 +136,6 @@ func (l *localdb) Del(keys []string) error {
 	return nil
 }
 
-// isEncrypted returns whether the provided blob has been prefixed with an sbox
-// header, indicating that it is an encrypted blob.
-func isEncrypted(b []byte) bool {
-	return bytes.HasPrefix(b, []byte("sbox"))
-}
-
 // Get returns blobs from the store for the provided keys. An entry will not
 // exist in the returned map if for any blobs that are not found. It is the
 // responsibility of the caller to ensure a blob was returned for all provided
@@ -134,6 +149,11 @@ func (l *localdb) Get(keys []string) (map[string][]byte, error) {
 		return nil, store.ErrShutdown
 	}
 
+	// The key ring read lock is held until the blobs have been
+	// decrypted so that a key cannot be retired while in use.
+	l.keyRing.RLock()
+	defer l.keyRing.RUnlock()
+
 	// Lookup blobs
 	blobs := make(map[string][]byte, len(keys))
 	for _, v := range keys {
@@ -150,12 +170,12 @@ func (l *localdb) Get(keys []string) (map[string][]byte, error) {
 
 	// Decrypt blobs
 	for k, v := range blobs {
-		encrypted := isEncrypted(v)
+		encrypted := store.IsEncrypted(v)
 		log.Tracef("Blob is encrypted: %v", encrypted)
 		if !encrypted {
 			continue
 		}
-		b, _, err := l.decrypt(v)
+		b, _, err := l.keyRing.Decrypt(v)
 		if err != nil {
 			return nil, fmt.Errorf("decrypt: %v", err)
 		}
@@ -165,6 +185,30 @@ func (l *localdb) Get(keys []string) (map[string][]byte, error) {
 	return blobs, nil
 }
 
+// Keys returns all of the keys in the store.
+//
+// This function satisfies the store BlobKV interface.
+func (l *localdb) Keys() ([]string, error) {
+	log.Tracef("Keys")
+
+	if l.isShutdown() {
+		return nil, store.ErrShutdown
+	}
+
+	keys := make([]string, 0, 1024)
+	iter := l.db.NewIterator(nil, nil)
+	for iter.Next() {
+		keys = append(keys, string(iter.Key()))
+	}
+	iter.Release()
+	err := iter.Error()
+	if err != nil {
+		return nil, fmt.Errorf("iterator: %v", err)
+	}
+
+	return keys, nil
+}
+
 // Closes closes the store connection.
 //
 // This function satisfies the store BlobKV interface.
@@ -173,22 +217,79 @@ func (l *localdb) Close() {
 
 	atomic.AddUint64(&l.shutdown, 1)
 
-	// Zero the encryption key
-	util.Zero(l.key[:])
+	// Zero the encryption keys
+	l.keyRing.Zero()
 
 	// Close database
 	l.db.Close()
 }
 
-// New returns a new localdb.
-func New(appDir, dataDir string) (*localdb, error) {
-	// Load encryption key.
-	keyFile := filepath.Join(appDir, encryptionKeyFilename)
-	key, err := util.LoadEncryptionKey(log, keyFile)
+// encryptionKeyFile returns the file path of the encryption key with the
+// provided ID.
+func encryptionKeyFile(appDir string, keyID uint32) string {
+	if keyID == 0 {
+		return filepath.Join(appDir, encryptionKeyFilename)
+	}
+	ext := filepath.Ext(encryptionKeyFilename)
+	fn := fmt.Sprintf("%v-%v%v",
+		strings.TrimSuffix(encryptionKeyFilename, ext), keyID, ext)
+	return filepath.Join(appDir, fn)
+}
+
+// encryptionKeyIDs returns the IDs of all encryption key files that exist in
+// the provided directory.
+func encryptionKeyIDs(appDir string) ([]uint32, error) {
+	ext := filepath.Ext(encryptionKeyFilename)
+	prefix := strings.TrimSuffix(encryptionKeyFilename, ext)
+	files, err := filepath.Glob(filepath.Join(appDir, prefix+"*"+ext))
 	if err != nil {
 		return nil, err
 	}
+	keyIDs := make([]uint32, 0, len(files))
+	for _, v := range files {
+		fn := filepath.Base(v)
+		if fn == encryptionKeyFilename {
+			keyIDs = append(keyIDs, 0)
+			continue
+		}
+		s := strings.TrimSuffix(strings.TrimPrefix(fn, prefix+"-"), ext)
+		keyID, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid encryption key file %v", v)
+		}
+		keyIDs = append(keyIDs, uint32(keyID))
+	}
+	return keyIDs, nil
+}
 
+// loadEncryptionKeys loads all encryption keys from the application dir. A
+// new key is created if one does not exist yet. The key with the highest ID
+// is the active key.
+func (l *localdb) loadEncryptionKeys() error {
+	keyIDs, err := encryptionKeyIDs(l.appDir)
+	if err != nil {
+		return err
+	}
+	if len(keyIDs) == 0 {
+		// No keys exist yet. The initial key will be created.
+		keyIDs = []uint32{0}
+	}
+	for _, keyID := range keyIDs {
+		key, err := util.LoadEncryptionKey(log,
+			encryptionKeyFile(l.appDir, keyID))
+		if err != nil {
+			return err
+		}
+		l.keyRing.Add(keyID, key)
+	}
+
+	log.Infof("Active encryption key ID: %v", l.keyRing.ActiveKeyID())
+
+	return nil
+}
+
+// New returns a new localdb.
+func New(appDir, dataDir string) (*localdb, error) {
 	// Open database
 	db, err := leveldb.OpenFile(dataDir, nil)
 	if err != nil {
@@ -197,10 +298,24 @@ func New(appDir, dataDir string) (*localdb, error) {
 
 	// Create context
 	ldb := localdb{
-		db: db,
+		db:      db,
+		appDir:  appDir,
+		keyRing: store.NewKeyRing(),
+	}
+	ldb.Rotator = store.NewRotator(log, ldb.keyRing, store.RotatorCallbacks{
+		IsShutdown: ldb.isShutdown,
+		KeyNew:     ldb.keyNew,
+		KeysDel:    ldb.keysDel,
+		Update:     ldb.update,
+		Scan:       ldb.scan,
+	})
+
+	// Load encryption keys
+	err = ldb.loadEncryptionKeys()
+	if err != nil {
+		db.Close()
+		return nil, err
 	}
-	copy(ldb.key[:], key[:])
-	util.Zero(key[:])
 
 	return &ldb, nil
 }
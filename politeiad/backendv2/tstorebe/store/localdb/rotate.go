@@ -0,0 +1,122 @@
+// Copyright (c) 2020-2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package localdb
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+	"github.com/decred/politeia/util"
+	"github.com/syndtr/goleveldb/leveldb"
+)
+
+var (
+	_ store.KeyRotator = (*localdb)(nil)
+)
+
+// keyNew creates the encryption key file for the provided key ID. A new
+// random key is created if no key file is provided. Otherwise, the key is
+// loaded from the provided key file and copied into the application dir.
+//
+// This function satisfies the store RotatorCallbacks KeyNew function.
+func (l *localdb) keyNew(keyID uint32, keyFile string) (*[32]byte, error) {
+	fp := encryptionKeyFile(l.appDir, keyID)
+	if keyFile == "" {
+		return util.LoadEncryptionKey(log, fp)
+	}
+
+	key, err := util.LoadEncryptionKey(log, util.CleanAndExpandPath(keyFile))
+	if err != nil {
+		return nil, err
+	}
+	if id, ok := l.keyRing.Contains(key); ok {
+		util.Zero(key[:])
+		return nil, fmt.Errorf("key file %v contains encryption key %v; "+
+			"a new key is required", keyFile, id)
+	}
+	err = ioutil.WriteFile(fp, key[:], 0400)
+	if err != nil {
+		util.Zero(key[:])
+		return nil, err
+	}
+
+	log.Infof("Encryption key copied to %v", fp)
+
+	return key, nil
+}
+
+// keysDel deletes the encryption key files of the provided key IDs.
+//
+// This function satisfies the store RotatorCallbacks KeysDel function.
+func (l *localdb) keysDel(keyIDs []uint32) error {
+	for _, keyID := range keyIDs {
+		err := os.Remove(encryptionKeyFile(l.appDir, keyID))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// update replaces the provided blobs with the blobs that are returned by the
+// update function. The blobs are written using a single batch so that the
+// operation is atomic.
+//
+// This function satisfies the store RotatorCallbacks Update function.
+func (l *localdb) update(keys []string, update func([]byte, func([]byte) ([]byte, error)) ([]byte, bool, error)) (uint32, error) {
+	l.writeMtx.Lock()
+	defer l.writeMtx.Unlock()
+
+	batch := new(leveldb.Batch)
+	for _, k := range keys {
+		v, err := l.db.Get([]byte(k), nil)
+		if err != nil {
+			if errors.Is(err, leveldb.ErrNotFound) {
+				// The blob has been deleted. This is ok.
+				continue
+			}
+			return 0, fmt.Errorf("get %v: %v", k, err)
+		}
+		e, ok, err := update(v, l.keyRing.Encrypt)
+		if err != nil {
+			return 0, fmt.Errorf("reencrypt %v: %v", k, err)
+		}
+		if !ok {
+			continue
+		}
+		batch.Put([]byte(k), e)
+	}
+	err := l.db.Write(batch, nil)
+	if err != nil {
+		return 0, fmt.Errorf("write batch: %v", err)
+	}
+
+	return uint32(batch.Len()), nil
+}
+
+// scan calls the provided function with every blob in the store.
+//
+// This function satisfies the store RotatorCallbacks Scan function.
+func (l *localdb) scan(fn func(blobs map[string][]byte) error) error {
+	iter := l.db.NewIterator(nil, nil)
+	defer iter.Release()
+	for iter.Next() {
+		blobs := map[string][]byte{
+			string(iter.Key()): iter.Value(),
+		}
+		err := fn(blobs)
+		if err != nil {
+			return err
+		}
+	}
+	err := iter.Error()
+	if err != nil {
+		return fmt.Errorf("iterator: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package localdb
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestKeyRotation(t *testing.T) {
+	appDir, err := ioutil.TempDir("", "localdb.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(appDir)
+
+	dataDir := filepath.Join(appDir, "data")
+	l, err := New(appDir, dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// verify verifies that all blobs can be retrieved and decrypted
+	blobs := make(map[string][]byte, 2)
+	verify := func(l *localdb) {
+		t.Helper()
+
+		keys := make([]string, 0, len(blobs))
+		for k := range blobs {
+			keys = append(keys, k)
+		}
+		reply, err := l.Get(keys)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k, v := range blobs {
+			if !bytes.Equal(reply[k], v) {
+				t.Fatalf("blob %v: got %s, want %s", k, reply[k], v)
+			}
+		}
+	}
+
+	// Save a blob under the initial key, rotate the key, then save a
+	// blob under the new key.
+	put := func(k, v string) {
+		t.Helper()
+
+		// A new map is used since the blobs are encrypted in place
+		err := l.Put(map[string][]byte{k: []byte(v)}, true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		blobs[k] = []byte(v)
+	}
+	put("a", "key0")
+	keyID, err := l.RotateKey("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyID != 1 {
+		t.Fatalf("got key id %v, want 1", keyID)
+	}
+	put("b", "key1")
+	verify(l)
+
+	// A key file that contains an existing key cannot be used for a
+	// rotation.
+	_, err = l.RotateKey(encryptionKeyFile(appDir, 0))
+	if err == nil {
+		t.Fatalf("got nil error, want key reuse error")
+	}
+
+	// The prior key cannot be retired while it is in use
+	retired, err := l.RetireKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(retired) != 0 {
+		t.Fatalf("got retired keys %v, want none", retired)
+	}
+
+	// Re-encrypt the blobs. Only the blob that was encrypted using
+	// the prior key should be re-encrypted.
+	n, err := l.Reencrypt([]string{"a", "b", "notfound"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Fatalf("got %v re-encrypted blobs, want 1", n)
+	}
+	verify(l)
+
+	// The prior key can now be retired
+	retired, err = l.RetireKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(retired, []uint32{0}) {
+		t.Fatalf("got retired keys %v, want [0]", retired)
+	}
+	_, err = os.Stat(encryptionKeyFile(appDir, 0))
+	if !os.IsNotExist(err) {
+		t.Fatalf("retired key file was not removed: %v", err)
+	}
+	verify(l)
+
+	// Rotate the key using a key file that is provided by the caller.
+	// The key is copied into the app dir.
+	keyFile := filepath.Join(appDir, "new.key")
+	keyID, err = l.RotateKey(keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyID != 2 {
+		t.Fatalf("got key id %v, want 2", keyID)
+	}
+	want, err := ioutil.ReadFile(keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ioutil.ReadFile(encryptionKeyFile(appDir, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("key file was not copied")
+	}
+	put("c", "key2")
+	n, err = l.Reencrypt([]string{"a", "b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Fatalf("got %v re-encrypted blobs, want 2", n)
+	}
+	retired, err = l.RetireKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(retired, []uint32{1}) {
+		t.Fatalf("got retired keys %v, want [1]", retired)
+	}
+	verify(l)
+
+	// The blobs must still be decrypted once the store has been
+	// reopened using only the active key.
+	l.Close()
+	l, err = New(appDir, dataDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	keyIDs, active := l.KeyIDs()
+	if active != 2 || !reflect.DeepEqual(keyIDs, []uint32{2}) {
+		t.Fatalf("got active key %v and keys %v, want 2 and [2]",
+			active, keyIDs)
+	}
+	verify(l)
+}
@@ -0,0 +1,385 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+)
+
+const (
+	// Inventory table names
+	tableNameInventory           = "inventory"
+	tableNameInventoryAttributes = "inventory_attributes"
+)
+
+// tableInventory defines the inventory table. The indexes support the
+// inventory queries for both sort orders, with and without the state and
+// status filters. The token is included in the indexes since it is used as
+// the tie breaker for entries that share the same timestamp.
+const tableInventory = `
+  token VARCHAR(64) NOT NULL PRIMARY KEY,
+  state INT UNSIGNED NOT NULL,
+  status INT UNSIGNED NOT NULL,
+  created BIGINT NOT NULL,
+  updated BIGINT NOT NULL,
+  INDEX idx_updated (updated, token),
+  INDEX idx_created (created, token),
+  INDEX idx_state_status_updated (state, status, updated, token),
+  INDEX idx_state_status_created (state, status, created, token)
+`
+
+// tableInventoryAttributes defines the table that contains the plugin
+// supplied inventory attributes.
+const tableInventoryAttributes = `
+  token VARCHAR(64) NOT NULL,
+  k VARCHAR(128) NOT NULL,
+  v VARCHAR(255) NOT NULL,
+  PRIMARY KEY (token, k),
+  INDEX idx_k_v (k, v)
+`
+
+var (
+	_ store.Inventory = (*mysql)(nil)
+)
+
+// invTx executes the provided function using a database transaction. The
+// transaction is committed if the function does not return an error.
+func (s *mysql) invTx(fn func(context.Context, *sql.Tx) error) error {
+	ctx, cancel := ctxWithTimeout()
+	defer cancel()
+
+	// Start transaction
+	opts := &sql.TxOptions{
+		Isolation: sql.LevelDefault,
+	}
+	tx, err := s.db.BeginTx(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("begin tx: %v", err)
+	}
+
+	err = fn(ctx, tx)
+	if err != nil {
+		// Attempt to roll back the transaction
+		if err2 := tx.Rollback(); err2 != nil {
+			// We're in trouble!
+			e := fmt.Sprintf("inv: %v, unable to rollback: %v", err, err2)
+			panic(e)
+		}
+		return err
+	}
+
+	// Commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("commit tx: %v", err)
+	}
+
+	return nil
+}
+
+// placeholders returns a comma separated list of n query placeholders.
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+// InvPut inserts or updates the provided inventory entries. The attributes of
+// an existing entry are not modified. This operation is performed atomically.
+//
+// This function satisfies the store Inventory interface.
+func (s *mysql) InvPut(entries []store.InvEntry) error {
+	log.Tracef("InvPut: %v entries", len(entries))
+
+	if s.isShutdown() {
+		return store.ErrShutdown
+	}
+
+	return s.invTx(func(ctx context.Context, tx *sql.Tx) error {
+		for _, e := range entries {
+			_, err := tx.ExecContext(ctx,
+				"INSERT INTO inventory (token, state, status, created, updated) "+
+					"VALUES (?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE "+
+					"state = VALUES(state), status = VALUES(status), "+
+					"created = VALUES(created), updated = VALUES(updated);",
+				e.Token, e.State, e.Status, e.Created, e.Updated)
+			if err != nil {
+				return fmt.Errorf("exec put: %v", err)
+			}
+		}
+		return nil
+	})
+}
+
+// InvAttributesSet sets attributes of an inventory entry. An empty attribute
+// value deletes the attribute. This operation is performed atomically.
+//
+// This function satisfies the store Inventory interface.
+func (s *mysql) InvAttributesSet(token string, attrs map[string]string) error {
+	log.Tracef("InvAttributesSet: %v %v", token, attrs)
+
+	if s.isShutdown() {
+		return store.ErrShutdown
+	}
+
+	return s.invTx(func(ctx context.Context, tx *sql.Tx) error {
+		// Lock the inventory entry
+		var t string
+		err := tx.QueryRowContext(ctx,
+			"SELECT token FROM inventory WHERE token = ? FOR UPDATE;",
+			token).Scan(&t)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("inventory entry not found: %v", token)
+		} else if err != nil {
+			return fmt.Errorf("query: %v", err)
+		}
+
+		for k, v := range attrs {
+			if v == "" {
+				_, err = tx.ExecContext(ctx,
+					"DELETE FROM inventory_attributes WHERE token = ? AND k = ?;",
+					token, k)
+			} else {
+				_, err = tx.ExecContext(ctx,
+					"INSERT INTO inventory_attributes (token, k, v) "+
+						"VALUES (?, ?, ?) ON DUPLICATE KEY UPDATE v = VALUES(v);",
+					token, k, v)
+			}
+			if err != nil {
+				return fmt.Errorf("exec attribute %v: %v", k, err)
+			}
+		}
+		return nil
+	})
+}
+
+// InvDel deletes the inventory entries for the provided tokens. This operation
+// is performed atomically.
+//
+// This function satisfies the store Inventory interface.
+func (s *mysql) InvDel(tokens []string) error {
+	log.Tracef("InvDel: %v", tokens)
+
+	if s.isShutdown() {
+		return store.ErrShutdown
+	}
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(tokens))
+	for _, v := range tokens {
+		args = append(args, v)
+	}
+	return s.invTx(func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx,
+			"DELETE FROM inventory_attributes WHERE token IN ("+
+				placeholders(len(tokens))+");", args...)
+		if err != nil {
+			return fmt.Errorf("exec del attributes: %v", err)
+		}
+		_, err = tx.ExecContext(ctx,
+			"DELETE FROM inventory WHERE token IN ("+
+				placeholders(len(tokens))+");", args...)
+		if err != nil {
+			return fmt.Errorf("exec del: %v", err)
+		}
+		return nil
+	})
+}
+
+// invAttributes populates the attributes of the provided entries.
+func (s *mysql) invAttributes(ctx context.Context, entries []store.InvEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	args := make([]interface{}, 0, len(entries))
+	idx := make(map[string]int, len(entries)) // [token]entryIndex
+	for i, v := range entries {
+		args = append(args, v.Token)
+		idx[v.Token] = i
+	}
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT token, k, v FROM inventory_attributes WHERE token IN ("+
+			placeholders(len(args))+");", args...)
+	if err != nil {
+		return fmt.Errorf("query: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var token, k, v string
+		err = rows.Scan(&token, &k, &v)
+		if err != nil {
+			return fmt.Errorf("scan: %v", err)
+		}
+		e := &entries[idx[token]]
+		if e.Attributes == nil {
+			e.Attributes = make(map[string]string)
+		}
+		e.Attributes[k] = v
+	}
+	err = rows.Err()
+	if err != nil {
+		return fmt.Errorf("next: %v", err)
+	}
+
+	return nil
+}
+
+// invEntries executes the provided inventory query and returns the entries,
+// including their attributes.
+func (s *mysql) invEntries(ctx context.Context, query string, args ...interface{}) ([]store.InvEntry, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %v", err)
+	}
+	defer rows.Close()
+
+	entries := make([]store.InvEntry, 0, 256)
+	for rows.Next() {
+		var e store.InvEntry
+		err = rows.Scan(&e.Token, &e.State, &e.Status, &e.Created, &e.Updated)
+		if err != nil {
+			return nil, fmt.Errorf("scan: %v", err)
+		}
+		entries = append(entries, e)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("next: %v", err)
+	}
+
+	err = s.invAttributes(ctx, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// InvGet returns the inventory entries for the provided tokens.
+//
+// This function satisfies the store Inventory interface.
+func (s *mysql) InvGet(tokens []string) (map[string]store.InvEntry, error) {
+	log.Tracef("InvGet: %v", tokens)
+
+	if s.isShutdown() {
+		return nil, store.ErrShutdown
+	}
+	reply := make(map[string]store.InvEntry, len(tokens))
+	if len(tokens) == 0 {
+		return reply, nil
+	}
+
+	ctx, cancel := ctxWithTimeout()
+	defer cancel()
+
+	args := make([]interface{}, 0, len(tokens))
+	for _, v := range tokens {
+		args = append(args, v)
+	}
+	entries, err := s.invEntries(ctx,
+		"SELECT token, state, status, created, updated FROM inventory "+
+			"WHERE token IN ("+placeholders(len(tokens))+");", args...)
+	if err != nil {
+		return nil, err
+	}
+	for _, v := range entries {
+		reply[v.Token] = v
+	}
+
+	return reply, nil
+}
+
+// InvQuery returns a page of inventory entries.
+//
+// This function satisfies the store Inventory interface.
+func (s *mysql) InvQuery(q store.InvQuery) (*store.InvPage, error) {
+	log.Tracef("InvQuery: %+v", q)
+
+	if s.isShutdown() {
+		return nil, store.ErrShutdown
+	}
+	if q.Limit == 0 {
+		return &store.InvPage{
+			Entries: []store.InvEntry{},
+		}, nil
+	}
+
+	// Build the query
+	col := "updated"
+	if q.Sort == store.InvSortCreated {
+		col = "created"
+	}
+	cmp, order := "<", "DESC"
+	if q.Ascending {
+		cmp, order = ">", "ASC"
+	}
+	var (
+		where = make([]string, 0, 8)
+		args  = make([]interface{}, 0, 16)
+	)
+	if q.State != 0 {
+		where = append(where, "state = ?")
+		args = append(args, q.State)
+	}
+	if q.Status != 0 {
+		where = append(where, "status = ?")
+		args = append(args, q.Status)
+	}
+	keys := make([]string, 0, len(q.Attributes))
+	for k := range q.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		where = append(where, "EXISTS (SELECT 1 FROM inventory_attributes a "+
+			"WHERE a.token = inventory.token AND a.k = ? AND a.v = ?)")
+		args = append(args, k, q.Attributes[k])
+	}
+	if q.Cursor != "" {
+		ts, token, err := store.InvCursorDecode(q.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		where = append(where, fmt.Sprintf("(%v %v ? OR (%v = ? AND token %v ?))",
+			col, cmp, col, cmp))
+		args = append(args, ts, ts, token)
+	}
+	query := "SELECT token, state, status, created, updated FROM inventory"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY %v %v, token %v LIMIT ?;", col, order, order)
+
+	// One additional entry is requested to determine whether there
+	// are more entries.
+	args = append(args, q.Limit+1)
+
+	ctx, cancel := ctxWithTimeout()
+	defer cancel()
+
+	entries, err := s.invEntries(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	var next string
+	if uint32(len(entries)) > q.Limit {
+		entries = entries[:q.Limit]
+		last := entries[len(entries)-1]
+		next = store.InvCursorEncode(store.InvSortTimestamp(last, q.Sort),
+			last.Token)
+	}
+
+	return &store.InvPage{
+		Entries: entries,
+		Cursor:  next,
+	}, nil
+}
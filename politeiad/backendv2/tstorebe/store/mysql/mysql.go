@@ -12,7 +12,6 @@ import (
 	"time"
 
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
-	"github.com/decred/politeia/util"
 
 	_ "github.com/go-sql-driver/mysql"
 )
@@ -48,8 +47,13 @@ var (
 type mysql struct {
 	shutdown uint64
 	db       *sql.DB
-	key      [32]byte
 	testing  bool // Only set during unit tests
+	appDir   string
+	keyRing  *store.KeyRing
+
+	// Rotator implements the store KeyRotator interface using the
+	// key ring.
+	*store.Rotator
 }
 
 func ctxWithTimeout() (context.Context, func()) {
@@ -81,10 +85,12 @@ func (s *mysql) put(blobs map[string][]byte, encrypt bool, ctx context.Context,
 		blobs = encrypted
 	}
 
-	// Save blobs
+	// Save blobs. Existing blobs are overwritten, the same as the
+	// other BlobKV implementations.
 	for k, v := range blobs {
 		_, err := tx.ExecContext(ctx,
-			"INSERT INTO kv (k, v) VALUES (?, ?);", k, v)
+			"INSERT INTO kv (k, v) VALUES (?, ?) "+
+				"ON DUPLICATE KEY UPDATE v = VALUES(v);", k, v)
 		if err != nil {
 			return fmt.Errorf("exec put: %v", err)
 		}
@@ -104,6 +110,12 @@ func (s *mysql) Put(blobs map[string][]byte, encrypt bool) error {
 		return store.ErrShutdown
 	}
 
+	// The key ring read lock is held for the duration of the put so
+	// that a key rotation cannot complete while blobs are being
+	// encrypted using the prior key.
+	s.keyRing.RLock()
+	defer s.keyRing.RUnlock()
+
 	ctx, cancel := ctxWithTimeout()
 	defer cancel()
 
@@ -187,22 +199,8 @@ func (s *mysql) Del(keys []string) error {
 	return nil
 }
 
-// Get returns blobs from the store for the provided keys. An entry will not
-// exist in the returned map if for any blobs that are not found. It is the
-// responsibility of the caller to ensure a blob was returned for all provided
-// keys.
-//
-// This function satisfies the store BlobKV interface.
-func (s *mysql) Get(keys []string) (map[string][]byte, error) {
-	log.Tracef("Get: %v", keys)
-
-	if s.isShutdown() {
-		return nil, store.ErrShutdown
-	}
-
-	ctx, cancel := ctxWithTimeout()
-	defer cancel()
-
+// getRaw returns the blobs for the provided keys without decrypting them.
+func (s *mysql) getRaw(ctx context.Context, keys []string) (map[string][]byte, error) {
 	// Build query. A placeholder parameter (?) is required for each
 	// key being requested.
 	//
@@ -248,14 +246,44 @@ func (s *mysql) Get(keys []string) (map[string][]byte, error) {
 		return nil, fmt.Errorf("next: %v", err)
 	}
 
+	return reply, nil
+}
+
+// Get returns blobs from the store for the provided keys. An entry will not
+// exist in the returned map if for any blobs that are not found. It is the
+// responsibility of the caller to ensure a blob was returned for all provided
+// keys.
+//
+// This function satisfies the store BlobKV interface.
+func (s *mysql) Get(keys []string) (map[string][]byte, error) {
+	log.Tracef("Get: %v", keys)
+
+	if s.isShutdown() {
+		return nil, store.ErrShutdown
+	}
+
+	// The key ring read lock is held until the blobs have been
+	// decrypted so that a key cannot be retired while in use.
+	s.keyRing.RLock()
+	defer s.keyRing.RUnlock()
+
+	ctx, cancel := ctxWithTimeout()
+	defer cancel()
+
+	// Get blobs
+	reply, err := s.getRaw(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
 	// Decrypt data blobs
 	for k, v := range reply {
-		encrypted := isEncrypted(v)
+		encrypted := store.IsEncrypted(v)
 		log.Tracef("Blob is encrypted: %v", encrypted)
 		if !encrypted {
 			continue
 		}
-		b, _, err := s.decrypt(v)
+		b, _, err := s.keyRing.Decrypt(v)
 		if err != nil {
 			return nil, fmt.Errorf("decrypt: %v", err)
 		}
@@ -265,14 +293,80 @@ func (s *mysql) Get(keys []string) (map[string][]byte, error) {
 	return reply, nil
 }
 
+// Keys returns all of the keys in the store.
+//
+// This function satisfies the store BlobKV interface.
+func (s *mysql) Keys() ([]string, error) {
+	log.Tracef("Keys")
+
+	if s.isShutdown() {
+		return nil, store.ErrShutdown
+	}
+
+	ctx, cancel := ctxWithTimeout()
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT k FROM kv;")
+	if err != nil {
+		return nil, fmt.Errorf("query: %v", err)
+	}
+	defer rows.Close()
+
+	keys := make([]string, 0, 1024)
+	for rows.Next() {
+		var k string
+		err = rows.Scan(&k)
+		if err != nil {
+			return nil, fmt.Errorf("scan: %v", err)
+		}
+		keys = append(keys, k)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("next: %v", err)
+	}
+
+	return keys, nil
+}
+
+// encryptionKeyParamsKeys returns the kv store keys of all encryption key
+// params.
+func (s *mysql) encryptionKeyParamsKeys() ([]string, error) {
+	ctx, cancel := ctxWithTimeout()
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT k FROM kv WHERE k LIKE ?;", encryptionKeyParamsKey+"%")
+	if err != nil {
+		return nil, fmt.Errorf("query: %v", err)
+	}
+	defer rows.Close()
+
+	keys := make([]string, 0, 8)
+	for rows.Next() {
+		var k string
+		err = rows.Scan(&k)
+		if err != nil {
+			return nil, fmt.Errorf("scan: %v", err)
+		}
+		keys = append(keys, k)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("next: %v", err)
+	}
+
+	return keys, nil
+}
+
 // Closes closes the blob store connection.
 func (s *mysql) Close() {
 	log.Tracef("Close")
 
 	atomic.AddUint64(&s.shutdown, 1)
 
-	// Zero the encryption key
-	util.Zero(s.key[:])
+	// Zero the encryption keys
+	s.keyRing.Zero()
 
 	// Close mysql connection
 	s.db.Close()
@@ -320,15 +414,38 @@ func New(appDir, host, user, password, dbname string) (*mysql, error) {
 		return nil, fmt.Errorf("create nonce table: %v", err)
 	}
 
+	// Setup inventory tables
+	q = fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %v (%v)`,
+		tableNameInventory, tableInventory)
+	_, err = db.Exec(q)
+	if err != nil {
+		return nil, fmt.Errorf("create inventory table: %v", err)
+	}
+	q = fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %v (%v)`,
+		tableNameInventoryAttributes, tableInventoryAttributes)
+	_, err = db.Exec(q)
+	if err != nil {
+		return nil, fmt.Errorf("create inventory attributes table: %v", err)
+	}
+
 	// Setup mysql context
 	s := &mysql{
-		db: db,
+		db:      db,
+		appDir:  appDir,
+		keyRing: store.NewKeyRing(),
 	}
-
-	// Derive encryption key from password. Key is set in argon2idKey
-	err = s.deriveEncryptionKey(password)
+	s.Rotator = store.NewRotator(log, s.keyRing, store.RotatorCallbacks{
+		IsShutdown: s.isShutdown,
+		KeyNew:     s.keyNew,
+		KeysDel:    s.keysDel,
+		Update:     s.update,
+		Scan:       s.scan,
+	})
+
+	// Derive encryption keys from password
+	err = s.deriveEncryptionKeys(password)
 	if err != nil {
-		return nil, fmt.Errorf("deriveEncryptionKey: %v", err)
+		return nil, fmt.Errorf("deriveEncryptionKeys: %v", err)
 	}
 
 	return s, nil
@@ -0,0 +1,118 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+// The tests in this file are run against an actual mysql instance. They are
+// skipped unless the following env variables are set.
+//
+// MYSQL_TEST_HOST: mysql ip:port (ex. localhost:3306)
+// MYSQL_TEST_USER: mysql user (default: politeiad)
+// MYSQL_TEST_PASS: password of the mysql user
+// MYSQL_TEST_DB  : name of the test database (all tables will be dropped)
+const (
+	envTestHost = "MYSQL_TEST_HOST"
+	envTestUser = "MYSQL_TEST_USER"
+	envTestPass = "MYSQL_TEST_PASS"
+	envTestDB   = "MYSQL_TEST_DB"
+)
+
+// newTestMySQL returns a mysql context that is connected to the test database
+// and a closure that closes the connection when invoked. The test database
+// tables are dropped prior to connecting so that every test starts with an
+// empty store.
+func newTestMySQL(t *testing.T) (*mysql, func()) {
+	t.Helper()
+
+	host := os.Getenv(envTestHost)
+	if host == "" {
+		t.Skipf("%v not set; skipping mysql test", envTestHost)
+	}
+	user := os.Getenv(envTestUser)
+	if user == "" {
+		user = "politeiad"
+	}
+	pass := os.Getenv(envTestPass)
+	dbname := os.Getenv(envTestDB)
+	if dbname == "" {
+		t.Fatalf("%v must be set", envTestDB)
+	}
+
+	// Drop the existing tables
+	h := fmt.Sprintf("%v:%v@tcp(%v)/%v", user, pass, host, dbname)
+	db, err := sql.Open("mysql", h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []string{tableNameKeyValue, tableNameNonce,
+		tableNameInventoryAttributes, tableNameInventory} {
+		_, err = db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %v;", v))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	db.Close()
+
+	s, err := New("", host, user, pass, dbname)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return s, func() {
+		s.Close()
+	}
+}
+
+func TestPutOverwrite(t *testing.T) {
+	s, cleanup := newTestMySQL(t)
+	defer cleanup()
+
+	// Existing blobs are overwritten, both plain text and encrypted
+	for _, encrypt := range []bool{false, true} {
+		for _, v := range []string{"a", "b"} {
+			err := s.Put(map[string][]byte{"key": []byte(v)}, encrypt)
+			if err != nil {
+				t.Fatalf("encrypt %v: %v", encrypt, err)
+			}
+		}
+		blobs, err := s.Get([]string{"key"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(blobs["key"], []byte("b")) {
+			t.Fatalf("encrypt %v: got %s, want b", encrypt, blobs["key"])
+		}
+	}
+}
+
+func TestPutIsAtomic(t *testing.T) {
+	s, cleanup := newTestMySQL(t)
+	defer cleanup()
+
+	// Saving a key that exceeds the max key length should fail and
+	// none of the blobs should be saved.
+	err := s.Put(map[string][]byte{
+		"a":                      []byte("a"),
+		strings.Repeat("b", 256): []byte("b"),
+	}, true)
+	if err == nil {
+		t.Fatalf("got nil error, want key length error")
+	}
+	blobs, err := s.Get([]string{"a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blobs) != 0 {
+		t.Fatalf("blob was saved during failed put")
+	}
+}
@@ -0,0 +1,171 @@
+// Copyright (c) 2020-2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+)
+
+const (
+	// scanBatchSize is the number of blobs that are retrieved at a time
+	// when scanning the store for the encryption keys that are in use.
+	scanBatchSize = 500
+)
+
+var (
+	_ store.KeyRotator = (*mysql)(nil)
+)
+
+// keyNew loads a new encryption key from the provided key file and saves the
+// key file path to the kv store. The default key file in the application dir
+// is used if no key file is provided. The key file is created using a new
+// random key if it does not exist.
+//
+// This function satisfies the store RotatorCallbacks KeyNew function.
+func (s *mysql) keyNew(keyID uint32, keyFile string) (*[32]byte, error) {
+	if keyFile == "" {
+		keyFile = store.EncryptionKeyFile(s.appDir,
+			encryptionKeyParamsKey, keyID)
+	}
+	key, err := store.NewEncryptionKeyFromFile(log, s, s.keyRing,
+		encryptionKeyParamsKey, keyFile, keyID)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Encryption key %v params saved to kv store", keyID)
+
+	return key, nil
+}
+
+// keysDel deletes the encryption key params of the provided key IDs from the
+// kv store.
+//
+// This function satisfies the store RotatorCallbacks KeysDel function.
+func (s *mysql) keysDel(keyIDs []uint32) error {
+	paramsKeys := make([]string, 0, len(keyIDs))
+	for _, keyID := range keyIDs {
+		paramsKeys = append(paramsKeys,
+			store.EncryptionKeyParamsKey(encryptionKeyParamsKey, keyID))
+	}
+	err := s.Del(paramsKeys)
+	if err != nil {
+		return fmt.Errorf("del: %v", err)
+	}
+	return nil
+}
+
+// updateTx replaces the provided blobs with the blobs that are returned by the
+// update function. The rows are locked for the duration of the transaction so
+// that a concurrent write cannot be overwritten.
+//
+// This function must be called using a transaction.
+func (s *mysql) updateTx(ctx context.Context, tx *sql.Tx, keys []string, update func([]byte, func([]byte) ([]byte, error)) ([]byte, bool, error)) (uint32, error) {
+	var count uint32
+	for _, k := range keys {
+		var v []byte
+		err := tx.QueryRowContext(ctx,
+			"SELECT v FROM kv WHERE k = ? FOR UPDATE;", k).Scan(&v)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			// The blob has been deleted. This is ok.
+			continue
+		case err != nil:
+			return 0, fmt.Errorf("select %v: %v", k, err)
+		}
+		e, ok, err := update(v, func(b []byte) ([]byte, error) {
+			return s.encrypt(ctx, tx, b)
+		})
+		if err != nil {
+			return 0, fmt.Errorf("reencrypt %v: %v", k, err)
+		}
+		if !ok {
+			continue
+		}
+		_, err = tx.ExecContext(ctx,
+			"UPDATE kv SET v = ? WHERE k = ?;", e, k)
+		if err != nil {
+			return 0, fmt.Errorf("update %v: %v", k, err)
+		}
+
+		count++
+	}
+
+	return count, nil
+}
+
+// update replaces the provided blobs with the blobs that are returned by the
+// update function. This operation is performed atomically.
+//
+// This function satisfies the store RotatorCallbacks Update function.
+func (s *mysql) update(keys []string, update func([]byte, func([]byte) ([]byte, error)) ([]byte, bool, error)) (uint32, error) {
+	ctx, cancel := ctxWithTimeout()
+	defer cancel()
+
+	// Start transaction
+	opts := &sql.TxOptions{
+		Isolation: sql.LevelDefault,
+	}
+	tx, err := s.db.BeginTx(ctx, opts)
+	if err != nil {
+		return 0, fmt.Errorf("begin tx: %v", err)
+	}
+
+	// Update blobs
+	count, err := s.updateTx(ctx, tx, keys, update)
+	if err != nil {
+		// Attempt to roll back the transaction
+		if err2 := tx.Rollback(); err2 != nil {
+			// We're in trouble!
+			e := fmt.Sprintf("update: %v, unable to rollback: %v", err, err2)
+			panic(e)
+		}
+		return 0, err
+	}
+
+	// Commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return 0, fmt.Errorf("commit tx: %v", err)
+	}
+
+	return count, nil
+}
+
+// scan calls the provided function with every blob in the store. The blobs
+// are retrieved in batches.
+//
+// This function satisfies the store RotatorCallbacks Scan function.
+func (s *mysql) scan(fn func(blobs map[string][]byte) error) error {
+	keys, err := s.Keys()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(keys); i += scanBatchSize {
+		end := i + scanBatchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		ctx, cancel := ctxWithTimeout()
+		blobs, err := s.getRaw(ctx, keys[i:end])
+		cancel()
+		if err != nil {
+			return err
+		}
+
+		err = fn(blobs)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
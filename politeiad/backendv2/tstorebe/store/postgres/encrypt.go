@@ -0,0 +1,112 @@
+// Copyright (c) 2020-2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/binary"
+
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+	"github.com/decred/politeia/util"
+	"github.com/marcopeereboom/sbox"
+)
+
+const (
+	// encryptionKeyParamsKey is the kv store key for the encryption
+	// key params that are saved on initial key derivation.
+	encryptionKeyParamsKey = "store-postgres-encryptionkeyparams"
+)
+
+// deriveEncryptionKeys derives the encryption keys from the provided password
+// and adds them to the key ring. A random 16 byte salt is created the first
+// time a key is derived. The salt and the other argon2id params are saved to
+// the kv store and are used to derive the keys on subsequent calls.
+//
+// A store will have multiple encryption keys if the key has been rotated and
+// the blobs that were encrypted using the prior keys have not yet been
+// re-encrypted. The key with the highest ID is the active key. Rotated keys
+// are loaded from their key files instead of being derived from the password.
+// The password is not retained once the keys have been derived.
+func (s *postgres) deriveEncryptionKeys(password string) error {
+	log.Infof("Deriving encryption keys")
+
+	pass := []byte(password)
+	defer util.Zero(pass)
+
+	// Check if the key params already exist in the kv store. Existing
+	// params means that the keys have been derived previously. These
+	// params will be used if found. If no params exist then new ones
+	// will be created and saved to the kv store for future use.
+	paramsKeys, err := s.encryptionKeyParamsKeys()
+	if err != nil {
+		return err
+	}
+	if len(paramsKeys) == 0 {
+		log.Infof("Encryption key params not found; creating new ones")
+	} else {
+		log.Debugf("Encryption key params found in kv store")
+	}
+	err = store.DeriveEncryptionKeys(log, s, s.keyRing,
+		encryptionKeyParamsKey, pass, paramsKeys)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Active encryption key ID: %v", s.keyRing.ActiveKeyID())
+
+	return nil
+}
+
+var emptyNonce = [24]byte{}
+
+func (s *postgres) getDBNonce(ctx context.Context, tx *sql.Tx) ([24]byte, error) {
+	// Get nonce value
+	nonce, err := s.nonce(ctx, tx)
+	if err != nil {
+		return emptyNonce, err
+	}
+
+	log.Tracef("Encrypting with nonce: %v", nonce)
+
+	// Prepare nonce
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint64(b, uint64(nonce))
+	n, err := sbox.NewNonceFromBytes(b)
+	if err != nil {
+		return emptyNonce, err
+	}
+	return n.Current(), nil
+}
+
+func (s *postgres) getTestNonce(ctx context.Context, tx *sql.Tx) ([24]byte, error) {
+	nonce, err := util.Random(8)
+	if err != nil {
+		return emptyNonce, err
+	}
+	n, err := sbox.NewNonceFromBytes(nonce)
+	if err != nil {
+		return emptyNonce, err
+	}
+	return n.Current(), nil
+}
+
+func (s *postgres) getNonce(ctx context.Context, tx *sql.Tx) ([24]byte, error) {
+	if s.testing {
+		return s.getTestNonce(ctx, tx)
+	}
+	return s.getDBNonce(ctx, tx)
+}
+
+// encrypt encrypts the provided data using the active encryption key.
+//
+// This function must be called WITH the key ring read lock held.
+func (s *postgres) encrypt(ctx context.Context, tx *sql.Tx, data []byte) ([]byte, error) {
+	nonce, err := s.getNonce(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	return s.keyRing.EncryptN(nonce, data)
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2020-2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+	"github.com/decred/politeia/util"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	password := "passwordsosikrit"
+	blob := []byte("encryptmeyo")
+
+	// setup fake context
+	s := &postgres{
+		testing: true,
+		keyRing: store.NewKeyRing(),
+	}
+	s.keyRing.Add(0, store.Argon2idKey([]byte(password),
+		util.NewArgon2Params()))
+
+	// Encrypt and make sure cleartext isn't the same as the encypted blob.
+	eb, err := s.encrypt(nil, nil, blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(eb, blob) {
+		t.Fatal("equal")
+	}
+
+	// Decrypt and make sure cleartext is the same as the initial blob.
+	db, _, err := s.keyRing.Decrypt(eb)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(db, blob) {
+		t.Fatal("not equal")
+	}
+
+	// Try to decrypt invalid blob.
+	_, _, err = s.keyRing.Decrypt(blob)
+	if err == nil {
+		t.Fatal("expected invalid sbox header")
+	}
+}
+
+func TestEncryptDecryptKeyRotation(t *testing.T) {
+	password := []byte("passwordsosikrit")
+	blob := []byte("encryptmeyo")
+
+	// setup fake context
+	s := &postgres{
+		testing: true,
+		keyRing: store.NewKeyRing(),
+	}
+	s.keyRing.Add(0, store.Argon2idKey(password, util.NewArgon2Params()))
+
+	// Encrypt a blob using the initial key
+	eb0, err := s.encrypt(nil, nil, blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rotate the key and encrypt a blob using the new key
+	s.keyRing.Add(1, store.Argon2idKey(password, util.NewArgon2Params()))
+	eb1, err := s.encrypt(nil, nil, blob)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Both blobs should be decrypted and report the key that was used
+	// to encrypt them.
+	tests := []struct {
+		name  string
+		blob  []byte
+		keyID uint32
+	}{
+		{"prior key", eb0, 0},
+		{"active key", eb1, 1},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			db, keyID, err := s.keyRing.Decrypt(v.blob)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(db, blob) {
+				t.Fatal("not equal")
+			}
+			if keyID != v.keyID {
+				t.Fatalf("got key id %v, want %v", keyID, v.keyID)
+			}
+		})
+	}
+
+	// A blob should not be able to be decrypted once the key that was
+	// used to encrypt it has been retired.
+	inUse := func() (map[uint32]struct{}, error) {
+		return map[uint32]struct{}{}, nil
+	}
+	_, err = s.keyRing.Retire(inUse, func([]uint32) error { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = s.keyRing.Decrypt(eb0)
+	if err == nil {
+		t.Fatal("expected decryption error")
+	}
+}
@@ -0,0 +1,373 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+	"github.com/lib/pq"
+)
+
+const (
+	// Inventory table names
+	tableNameInventory           = "inventory"
+	tableNameInventoryAttributes = "inventory_attributes"
+)
+
+// tableInventory defines the inventory table.
+const tableInventory = `
+  token VARCHAR(64) NOT NULL PRIMARY KEY,
+  state BIGINT NOT NULL,
+  status BIGINT NOT NULL,
+  created BIGINT NOT NULL,
+  updated BIGINT NOT NULL
+`
+
+// tableInventoryAttributes defines the table that contains the plugin
+// supplied inventory attributes.
+const tableInventoryAttributes = `
+  token VARCHAR(64) NOT NULL REFERENCES inventory (token) ON DELETE CASCADE,
+  k VARCHAR(128) NOT NULL,
+  v VARCHAR(255) NOT NULL,
+  PRIMARY KEY (token, k)
+`
+
+// inventoryIndexes contains the statements that create the inventory indexes.
+// The indexes support the inventory queries for both sort orders, with and
+// without the state and status filters. The token is included in the indexes
+// since it is used as the tie breaker for entries that share the same
+// timestamp.
+var inventoryIndexes = []string{
+	`CREATE INDEX IF NOT EXISTS idx_inventory_updated
+    ON inventory (updated, token)`,
+	`CREATE INDEX IF NOT EXISTS idx_inventory_created
+    ON inventory (created, token)`,
+	`CREATE INDEX IF NOT EXISTS idx_inventory_state_status_updated
+    ON inventory (state, status, updated, token)`,
+	`CREATE INDEX IF NOT EXISTS idx_inventory_state_status_created
+    ON inventory (state, status, created, token)`,
+	`CREATE INDEX IF NOT EXISTS idx_inventory_attributes_k_v
+    ON inventory_attributes (k, v)`,
+}
+
+var (
+	_ store.Inventory = (*postgres)(nil)
+)
+
+// invTx executes the provided function using a database transaction. The
+// transaction is committed if the function does not return an error.
+func (s *postgres) invTx(fn func(context.Context, *sql.Tx) error) error {
+	ctx, cancel := ctxWithTimeout()
+	defer cancel()
+
+	// Start transaction
+	opts := &sql.TxOptions{
+		Isolation: sql.LevelDefault,
+	}
+	tx, err := s.db.BeginTx(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("begin tx: %v", err)
+	}
+
+	err = fn(ctx, tx)
+	if err != nil {
+		// Attempt to roll back the transaction
+		if err2 := tx.Rollback(); err2 != nil {
+			// We're in trouble!
+			e := fmt.Sprintf("inv: %v, unable to rollback: %v", err, err2)
+			panic(e)
+		}
+		return err
+	}
+
+	// Commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("commit tx: %v", err)
+	}
+
+	return nil
+}
+
+// InvPut inserts or updates the provided inventory entries. The attributes of
+// an existing entry are not modified. This operation is performed atomically.
+//
+// This function satisfies the store Inventory interface.
+func (s *postgres) InvPut(entries []store.InvEntry) error {
+	log.Tracef("InvPut: %v entries", len(entries))
+
+	if s.isShutdown() {
+		return store.ErrShutdown
+	}
+
+	return s.invTx(func(ctx context.Context, tx *sql.Tx) error {
+		for _, e := range entries {
+			_, err := tx.ExecContext(ctx,
+				"INSERT INTO inventory (token, state, status, created, updated) "+
+					"VALUES ($1, $2, $3, $4, $5) ON CONFLICT (token) DO UPDATE "+
+					"SET state = EXCLUDED.state, status = EXCLUDED.status, "+
+					"created = EXCLUDED.created, updated = EXCLUDED.updated;",
+				e.Token, e.State, e.Status, e.Created, e.Updated)
+			if err != nil {
+				return fmt.Errorf("exec put: %v", err)
+			}
+		}
+		return nil
+	})
+}
+
+// InvAttributesSet sets attributes of an inventory entry. An empty attribute
+// value deletes the attribute. This operation is performed atomically.
+//
+// This function satisfies the store Inventory interface.
+func (s *postgres) InvAttributesSet(token string, attrs map[string]string) error {
+	log.Tracef("InvAttributesSet: %v %v", token, attrs)
+
+	if s.isShutdown() {
+		return store.ErrShutdown
+	}
+
+	return s.invTx(func(ctx context.Context, tx *sql.Tx) error {
+		// Lock the inventory entry
+		var t string
+		err := tx.QueryRowContext(ctx,
+			"SELECT token FROM inventory WHERE token = $1 FOR UPDATE;",
+			token).Scan(&t)
+		if err == sql.ErrNoRows {
+			return fmt.Errorf("inventory entry not found: %v", token)
+		} else if err != nil {
+			return fmt.Errorf("query: %v", err)
+		}
+
+		for k, v := range attrs {
+			if v == "" {
+				_, err = tx.ExecContext(ctx,
+					"DELETE FROM inventory_attributes "+
+						"WHERE token = $1 AND k = $2;", token, k)
+			} else {
+				_, err = tx.ExecContext(ctx,
+					"INSERT INTO inventory_attributes (token, k, v) "+
+						"VALUES ($1, $2, $3) ON CONFLICT (token, k) "+
+						"DO UPDATE SET v = EXCLUDED.v;", token, k, v)
+			}
+			if err != nil {
+				return fmt.Errorf("exec attribute %v: %v", k, err)
+			}
+		}
+		return nil
+	})
+}
+
+// InvDel deletes the inventory entries for the provided tokens. The entry
+// attributes are deleted by the foreign key cascade. This operation is
+// performed atomically.
+//
+// This function satisfies the store Inventory interface.
+func (s *postgres) InvDel(tokens []string) error {
+	log.Tracef("InvDel: %v", tokens)
+
+	if s.isShutdown() {
+		return store.ErrShutdown
+	}
+
+	return s.invTx(func(ctx context.Context, tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx,
+			"DELETE FROM inventory WHERE token = ANY($1);", pq.Array(tokens))
+		if err != nil {
+			return fmt.Errorf("exec del: %v", err)
+		}
+		return nil
+	})
+}
+
+// invAttributes populates the attributes of the provided entries.
+func (s *postgres) invAttributes(ctx context.Context, entries []store.InvEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	tokens := make([]string, 0, len(entries))
+	idx := make(map[string]int, len(entries)) // [token]entryIndex
+	for i, v := range entries {
+		tokens = append(tokens, v.Token)
+		idx[v.Token] = i
+	}
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT token, k, v FROM inventory_attributes WHERE token = ANY($1);",
+		pq.Array(tokens))
+	if err != nil {
+		return fmt.Errorf("query: %v", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var token, k, v string
+		err = rows.Scan(&token, &k, &v)
+		if err != nil {
+			return fmt.Errorf("scan: %v", err)
+		}
+		e := &entries[idx[token]]
+		if e.Attributes == nil {
+			e.Attributes = make(map[string]string)
+		}
+		e.Attributes[k] = v
+	}
+	err = rows.Err()
+	if err != nil {
+		return fmt.Errorf("next: %v", err)
+	}
+
+	return nil
+}
+
+// invEntries executes the provided inventory query and returns the entries,
+// including their attributes.
+func (s *postgres) invEntries(ctx context.Context, query string, args ...interface{}) ([]store.InvEntry, error) {
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query: %v", err)
+	}
+	defer rows.Close()
+
+	entries := make([]store.InvEntry, 0, 256)
+	for rows.Next() {
+		var e store.InvEntry
+		err = rows.Scan(&e.Token, &e.State, &e.Status, &e.Created, &e.Updated)
+		if err != nil {
+			return nil, fmt.Errorf("scan: %v", err)
+		}
+		entries = append(entries, e)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("next: %v", err)
+	}
+
+	err = s.invAttributes(ctx, entries)
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// InvGet returns the inventory entries for the provided tokens.
+//
+// This function satisfies the store Inventory interface.
+func (s *postgres) InvGet(tokens []string) (map[string]store.InvEntry, error) {
+	log.Tracef("InvGet: %v", tokens)
+
+	if s.isShutdown() {
+		return nil, store.ErrShutdown
+	}
+
+	ctx, cancel := ctxWithTimeout()
+	defer cancel()
+
+	entries, err := s.invEntries(ctx,
+		"SELECT token, state, status, created, updated FROM inventory "+
+			"WHERE token = ANY($1);", pq.Array(tokens))
+	if err != nil {
+		return nil, err
+	}
+	reply := make(map[string]store.InvEntry, len(entries))
+	for _, v := range entries {
+		reply[v.Token] = v
+	}
+
+	return reply, nil
+}
+
+// InvQuery returns a page of inventory entries.
+//
+// This function satisfies the store Inventory interface.
+func (s *postgres) InvQuery(q store.InvQuery) (*store.InvPage, error) {
+	log.Tracef("InvQuery: %+v", q)
+
+	if s.isShutdown() {
+		return nil, store.ErrShutdown
+	}
+	if q.Limit == 0 {
+		return &store.InvPage{
+			Entries: []store.InvEntry{},
+		}, nil
+	}
+
+	// Build the query. The query arguments are numbered in the order
+	// that they are added.
+	col := "updated"
+	if q.Sort == store.InvSortCreated {
+		col = "created"
+	}
+	cmp, order := "<", "DESC"
+	if q.Ascending {
+		cmp, order = ">", "ASC"
+	}
+	var (
+		where = make([]string, 0, 8)
+		args  = make([]interface{}, 0, 16)
+	)
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%v", len(args))
+	}
+	if q.State != 0 {
+		where = append(where, "state = "+arg(q.State))
+	}
+	if q.Status != 0 {
+		where = append(where, "status = "+arg(q.Status))
+	}
+	keys := make([]string, 0, len(q.Attributes))
+	for k := range q.Attributes {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		where = append(where, fmt.Sprintf("EXISTS (SELECT 1 FROM "+
+			"inventory_attributes a WHERE a.token = inventory.token AND "+
+			"a.k = %v AND a.v = %v)", arg(k), arg(q.Attributes[k])))
+	}
+	if q.Cursor != "" {
+		ts, token, err := store.InvCursorDecode(q.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		where = append(where, fmt.Sprintf("(%v, token) %v (%v, %v)",
+			col, cmp, arg(ts), arg(token)))
+	}
+	query := "SELECT token, state, status, created, updated FROM inventory"
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+
+	// One additional entry is requested to determine whether there
+	// are more entries.
+	query += fmt.Sprintf(" ORDER BY %v %v, token %v LIMIT %v;",
+		col, order, order, arg(q.Limit+1))
+
+	ctx, cancel := ctxWithTimeout()
+	defer cancel()
+
+	entries, err := s.invEntries(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	var next string
+	if uint32(len(entries)) > q.Limit {
+		entries = entries[:q.Limit]
+		last := entries[len(entries)-1]
+		next = store.InvCursorEncode(store.InvSortTimestamp(last, q.Sort),
+			last.Token)
+	}
+
+	return &store.InvPage{
+		Entries: entries,
+		Cursor:  next,
+	}, nil
+}
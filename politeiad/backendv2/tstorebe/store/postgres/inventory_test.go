@@ -0,0 +1,17 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"testing"
+
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+)
+
+func TestInventory(t *testing.T) {
+	store.InvTest(t, func(t *testing.T) (store.Inventory, func()) {
+		return newTestPostgres(t)
+	})
+}
@@ -0,0 +1,33 @@
+// Copyright (c) 2020-2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// nonce returns a new nonce value. This function guarantees that the returned
+// nonce will be unique for every invocation.
+//
+// This function must be called using a transaction.
+func (s *postgres) nonce(ctx context.Context, tx *sql.Tx) (int64, error) {
+	// Create and retrieve new nonce value in a single atomic statement.
+	// The BIGSERIAL sequence guarantees that a value is never handed
+	// out twice, even across concurrent transactions and transactions
+	// that are rolled back.
+	var nonce int64
+	err := tx.QueryRowContext(ctx,
+		"INSERT INTO nonce DEFAULT VALUES RETURNING n;").Scan(&nonce)
+	if err != nil {
+		return 0, fmt.Errorf("insert: %v", err)
+	}
+	if nonce == 0 {
+		return 0, fmt.Errorf("invalid 0 nonce")
+	}
+
+	return nonce, nil
+}
@@ -0,0 +1,454 @@
+// Copyright (c) 2020-2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"sync/atomic"
+	"time"
+
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+	"github.com/lib/pq"
+)
+
+const (
+	// Database options
+	connTimeout     = 1 * time.Minute
+	connMaxLifetime = 1 * time.Minute
+	maxOpenConns    = 0 // 0 is unlimited
+	maxIdleConns    = 100
+
+	// Database table names
+	tableNameKeyValue = "kv"
+	tableNameNonce    = "nonce"
+)
+
+// tableKeyValue defines the key-value table.
+const tableKeyValue = `
+  k VARCHAR(255) NOT NULL PRIMARY KEY,
+  v BYTEA NOT NULL
+`
+
+// tableNonce defines the table used to track the encryption nonce.
+const tableNonce = `
+  n BIGSERIAL PRIMARY KEY
+`
+
+var (
+	_ store.BlobKV = (*postgres)(nil)
+)
+
+// postgres implements the store BlobKV interface using a postgres driver.
+type postgres struct {
+	shutdown uint64
+	db       *sql.DB
+	testing  bool // Only set during unit tests
+	appDir   string
+	keyRing  *store.KeyRing
+
+	// Rotator implements the store KeyRotator interface using the
+	// key ring.
+	*store.Rotator
+}
+
+func ctxWithTimeout() (context.Context, func()) {
+	return context.WithTimeout(context.Background(), connTimeout)
+}
+
+func (s *postgres) isShutdown() bool {
+	return atomic.LoadUint64(&s.shutdown) != 0
+}
+
+// put saves the provided blobs to the kv store using the provided transaction.
+func (s *postgres) put(blobs map[string][]byte, encrypt bool, ctx context.Context, tx *sql.Tx) error {
+	// Encrypt blobs
+	if encrypt {
+		encrypted := make(map[string][]byte, len(blobs))
+		for k, v := range blobs {
+			e, err := s.encrypt(ctx, tx, v)
+			if err != nil {
+				return fmt.Errorf("encrypt: %v", err)
+			}
+			encrypted[k] = e
+		}
+
+		// Sanity check
+		if len(encrypted) != len(blobs) {
+			return fmt.Errorf("unexpected number of encrypted blobs")
+		}
+
+		blobs = encrypted
+	}
+
+	// Save blobs. Existing blobs are overwritten, the same as the
+	// other BlobKV implementations.
+	for k, v := range blobs {
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO kv (k, v) VALUES ($1, $2) "+
+				"ON CONFLICT (k) DO UPDATE SET v = EXCLUDED.v;", k, v)
+		if err != nil {
+			return fmt.Errorf("exec put: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// Put saves the provided key-value pairs to the store. This operation is
+// performed atomically.
+//
+// This function satisfies the store BlobKV interface.
+func (s *postgres) Put(blobs map[string][]byte, encrypt bool) error {
+	log.Tracef("Put: %v blobs", len(blobs))
+
+	if s.isShutdown() {
+		return store.ErrShutdown
+	}
+
+	// The key ring read lock is held for the duration of the put so
+	// that a key rotation cannot complete while blobs are being
+	// encrypted using the prior key.
+	s.keyRing.RLock()
+	defer s.keyRing.RUnlock()
+
+	ctx, cancel := ctxWithTimeout()
+	defer cancel()
+
+	// Start transaction
+	opts := &sql.TxOptions{
+		Isolation: sql.LevelDefault,
+	}
+	tx, err := s.db.BeginTx(ctx, opts)
+	if err != nil {
+		return fmt.Errorf("begin tx: %v", err)
+	}
+
+	// Save blobs
+	err = s.put(blobs, encrypt, ctx, tx)
+	if err != nil {
+		// Attempt to roll back the transaction
+		if err2 := tx.Rollback(); err2 != nil {
+			// We're in trouble!
+			e := fmt.Sprintf("put: %v, unable to rollback: %v", err, err2)
+			panic(e)
+		}
+		return err
+	}
+
+	// Commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("commit tx: %v", err)
+	}
+
+	log.Debugf("Saved blobs (%v) to store", len(blobs))
+
+	return nil
+}
+
+// Del deletes the provided blobs from the store. This operation is performed
+// atomically.
+//
+// This function satisfies the store BlobKV interface.
+func (s *postgres) Del(keys []string) error {
+	log.Tracef("Del: %v", keys)
+
+	if s.isShutdown() {
+		return store.ErrShutdown
+	}
+
+	ctx, cancel := ctxWithTimeout()
+	defer cancel()
+
+	// Start transaction
+	opts := &sql.TxOptions{
+		Isolation: sql.LevelDefault,
+	}
+	tx, err := s.db.BeginTx(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	// Delete blobs
+	_, err = tx.ExecContext(ctx,
+		"DELETE FROM kv WHERE k = ANY($1);", pq.Array(keys))
+	if err != nil {
+		// Attempt to roll back the transaction
+		if err2 := tx.Rollback(); err2 != nil {
+			// We're in trouble!
+			e := fmt.Sprintf("del: %v, unable to rollback: %v", err, err2)
+			panic(e)
+		}
+		return err
+	}
+
+	// Commit transaction
+	err = tx.Commit()
+	if err != nil {
+		return fmt.Errorf("commit: %v", err)
+	}
+
+	log.Debugf("Deleted blobs (%v) from store", len(keys))
+
+	return nil
+}
+
+// getRaw returns the blobs for the provided keys without decrypting them.
+func (s *postgres) getRaw(ctx context.Context, keys []string) (map[string][]byte, error) {
+	// Get blobs. The keys are passed in as a single postgres array
+	// parameter.
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT k, v FROM kv WHERE k = ANY($1);", pq.Array(keys))
+	if err != nil {
+		return nil, fmt.Errorf("query: %v", err)
+	}
+	defer rows.Close()
+
+	reply := make(map[string][]byte, len(keys))
+	for rows.Next() {
+		var k string
+		var v []byte
+		err = rows.Scan(&k, &v)
+		if err != nil {
+			return nil, fmt.Errorf("scan: %v", err)
+		}
+		reply[k] = v
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("next: %v", err)
+	}
+
+	return reply, nil
+}
+
+// Get returns blobs from the store for the provided keys. An entry will not
+// exist in the returned map if for any blobs that are not found. It is the
+// responsibility of the caller to ensure a blob was returned for all provided
+// keys.
+//
+// This function satisfies the store BlobKV interface.
+func (s *postgres) Get(keys []string) (map[string][]byte, error) {
+	log.Tracef("Get: %v", keys)
+
+	if s.isShutdown() {
+		return nil, store.ErrShutdown
+	}
+
+	// The key ring read lock is held until the blobs have been
+	// decrypted so that a key cannot be retired while in use.
+	s.keyRing.RLock()
+	defer s.keyRing.RUnlock()
+
+	ctx, cancel := ctxWithTimeout()
+	defer cancel()
+
+	// Get blobs
+	reply, err := s.getRaw(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decrypt data blobs
+	for k, v := range reply {
+		encrypted := store.IsEncrypted(v)
+		log.Tracef("Blob is encrypted: %v", encrypted)
+		if !encrypted {
+			continue
+		}
+		b, _, err := s.keyRing.Decrypt(v)
+		if err != nil {
+			return nil, fmt.Errorf("decrypt: %v", err)
+		}
+		reply[k] = b
+	}
+
+	return reply, nil
+}
+
+// Keys returns all of the keys in the store.
+//
+// This function satisfies the store BlobKV interface.
+func (s *postgres) Keys() ([]string, error) {
+	log.Tracef("Keys")
+
+	if s.isShutdown() {
+		return nil, store.ErrShutdown
+	}
+
+	ctx, cancel := ctxWithTimeout()
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT k FROM kv;")
+	if err != nil {
+		return nil, fmt.Errorf("query: %v", err)
+	}
+	defer rows.Close()
+
+	keys := make([]string, 0, 1024)
+	for rows.Next() {
+		var k string
+		err = rows.Scan(&k)
+		if err != nil {
+			return nil, fmt.Errorf("scan: %v", err)
+		}
+		keys = append(keys, k)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("next: %v", err)
+	}
+
+	return keys, nil
+}
+
+// encryptionKeyParamsKeys returns the kv store keys of all encryption key
+// params.
+func (s *postgres) encryptionKeyParamsKeys() ([]string, error) {
+	ctx, cancel := ctxWithTimeout()
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx,
+		"SELECT k FROM kv WHERE k LIKE $1;", encryptionKeyParamsKey+"%")
+	if err != nil {
+		return nil, fmt.Errorf("query: %v", err)
+	}
+	defer rows.Close()
+
+	keys := make([]string, 0, 8)
+	for rows.Next() {
+		var k string
+		err = rows.Scan(&k)
+		if err != nil {
+			return nil, fmt.Errorf("scan: %v", err)
+		}
+		keys = append(keys, k)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, fmt.Errorf("next: %v", err)
+	}
+
+	return keys, nil
+}
+
+// Closes closes the blob store connection.
+//
+// This function satisfies the store BlobKV interface.
+func (s *postgres) Close() {
+	log.Tracef("Close")
+
+	atomic.AddUint64(&s.shutdown, 1)
+
+	// Zero the encryption keys
+	s.keyRing.Zero()
+
+	// Close postgres connection
+	s.db.Close()
+}
+
+// New returns a new postgres context that satisfies the store BlobKV
+// interface. The connection does not use TLS. The database is expected to be
+// running on the same host as politeiad or on a trusted private network.
+// DSN returns the postgres connection URL for the provided connection params.
+// The user and password are escaped so that they can contain any character.
+func DSN(host, user, password, dbname string) string {
+	u := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(user, password),
+		Host:     host,
+		Path:     dbname,
+		RawQuery: "sslmode=disable",
+	}
+	return u.String()
+}
+
+func New(appDir, host, user, password, dbname string) (*postgres, error) {
+	// The password is required to derive the encryption key
+	if password == "" {
+		return nil, fmt.Errorf("password not provided")
+	}
+
+	// Connect to database
+	log.Infof("Postgres host: postgres://%v:[password]@%v/%v",
+		user, host, dbname)
+
+	db, err := sql.Open("postgres", DSN(host, user, password, dbname))
+	if err != nil {
+		return nil, err
+	}
+
+	// Setup database options
+	db.SetConnMaxLifetime(connMaxLifetime)
+	db.SetMaxOpenConns(maxOpenConns)
+	db.SetMaxIdleConns(maxIdleConns)
+
+	// Verify database connection
+	err = db.Ping()
+	if err != nil {
+		return nil, fmt.Errorf("db ping: %v", err)
+	}
+
+	// Setup key-value table
+	q := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %v (%v)`,
+		tableNameKeyValue, tableKeyValue)
+	_, err = db.Exec(q)
+	if err != nil {
+		return nil, fmt.Errorf("create kv table: %v", err)
+	}
+
+	// Setup nonce table
+	q = fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %v (%v)`,
+		tableNameNonce, tableNonce)
+	_, err = db.Exec(q)
+	if err != nil {
+		return nil, fmt.Errorf("create nonce table: %v", err)
+	}
+
+	// Setup inventory tables
+	q = fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %v (%v)`,
+		tableNameInventory, tableInventory)
+	_, err = db.Exec(q)
+	if err != nil {
+		return nil, fmt.Errorf("create inventory table: %v", err)
+	}
+	q = fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %v (%v)`,
+		tableNameInventoryAttributes, tableInventoryAttributes)
+	_, err = db.Exec(q)
+	if err != nil {
+		return nil, fmt.Errorf("create inventory attributes table: %v", err)
+	}
+	for _, v := range inventoryIndexes {
+		_, err = db.Exec(v)
+		if err != nil {
+			return nil, fmt.Errorf("create inventory index: %v", err)
+		}
+	}
+
+	// Setup postgres context
+	s := &postgres{
+		db:      db,
+		appDir:  appDir,
+		keyRing: store.NewKeyRing(),
+	}
+	s.Rotator = store.NewRotator(log, s.keyRing, store.RotatorCallbacks{
+		IsShutdown: s.isShutdown,
+		KeyNew:     s.keyNew,
+		KeysDel:    s.keysDel,
+		Update:     s.update,
+		Scan:       s.scan,
+	})
+
+	// Derive encryption keys from password
+	err = s.deriveEncryptionKeys(password)
+	if err != nil {
+		return nil, fmt.Errorf("deriveEncryptionKeys: %v", err)
+	}
+
+	return s, nil
+}
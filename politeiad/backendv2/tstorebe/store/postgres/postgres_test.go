@@ -0,0 +1,389 @@
+// Copyright (c) 2020-2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package postgres
+
+import (
+	"bytes"
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+	"github.com/lib/pq"
+)
+
+// The tests in this file are run against an actual postgres instance. They
+// are skipped unless the following env variables are set.
+//
+// POSTGRES_TEST_HOST: postgres ip:port (ex. localhost:5432)
+// POSTGRES_TEST_USER: postgres user (default: politeiad)
+// POSTGRES_TEST_PASS: password of the postgres user
+// POSTGRES_TEST_DB  : name of the test database (all tables will be dropped)
+const (
+	envTestHost = "POSTGRES_TEST_HOST"
+	envTestUser = "POSTGRES_TEST_USER"
+	envTestPass = "POSTGRES_TEST_PASS"
+	envTestDB   = "POSTGRES_TEST_DB"
+)
+
+// newTestPostgres returns a postgres context that is connected to the test
+// database and a closure that closes the connection when invoked. The test
+// database tables are dropped prior to connecting so that every test starts
+// with an empty store.
+func newTestPostgres(t *testing.T) (*postgres, func()) {
+	t.Helper()
+
+	host := os.Getenv(envTestHost)
+	if host == "" {
+		t.Skipf("%v not set; skipping postgres test", envTestHost)
+	}
+	user := os.Getenv(envTestUser)
+	if user == "" {
+		user = "politeiad"
+	}
+	pass := os.Getenv(envTestPass)
+	dbname := os.Getenv(envTestDB)
+	if dbname == "" {
+		t.Fatalf("%v must be set", envTestDB)
+	}
+
+	// Drop the existing tables
+	db, err := sql.Open("postgres", DSN(host, user, pass, dbname))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []string{tableNameKeyValue, tableNameNonce,
+		tableNameInventoryAttributes, tableNameInventory} {
+		_, err = db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %v;", v))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	db.Close()
+
+	// The app dir contains the rotated encryption key files
+	appDir, err := ioutil.TempDir("", "postgres.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := New(appDir, host, user, pass, dbname)
+	if err != nil {
+		os.RemoveAll(appDir)
+		t.Fatal(err)
+	}
+
+	return s, func() {
+		s.Close()
+		os.RemoveAll(appDir)
+	}
+}
+
+func TestDSN(t *testing.T) {
+	var (
+		host   = "localhost:5432"
+		user   = "politeiad"
+		pass   = `p@ss:w/rd?#% &='"\`
+		dbname = "records_testnet"
+	)
+	dsn := DSN(host, user, pass, dbname)
+
+	// The connection params must survive being parsed from the URL
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, _ := u.User.Password()
+	if u.User.Username() != user || p != pass {
+		t.Fatalf("got user %v password %v, want %v %v",
+			u.User.Username(), p, user, pass)
+	}
+	if u.Host != host || u.Path != "/"+dbname {
+		t.Fatalf("got host %v path %v, want %v /%v",
+			u.Host, u.Path, host, dbname)
+	}
+
+	// The postgres driver must be able to parse the URL
+	_, err = pq.ParseURL(dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPutGetDel(t *testing.T) {
+	s, cleanup := newTestPostgres(t)
+	defer cleanup()
+
+	var (
+		keyPlain     = "plain"
+		keyEncrypted = "encrypted"
+		blob         = []byte("blob")
+	)
+
+	// Save a plain text blob and an encrypted blob
+	err := s.Put(map[string][]byte{keyPlain: blob}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = s.Put(map[string][]byte{keyEncrypted: blob}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Verify the encrypted blob was encrypted at rest
+	var v []byte
+	err = s.db.QueryRow("SELECT v FROM kv WHERE k = $1;", keyEncrypted).Scan(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !store.IsEncrypted(v) {
+		t.Fatalf("blob is not encrypted")
+	}
+
+	// Get both blobs plus a blob that does not exist
+	blobs, err := s.Get([]string{keyPlain, keyEncrypted, "notfound"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blobs) != 2 {
+		t.Fatalf("got %v blobs, want 2", len(blobs))
+	}
+	for k, v := range blobs {
+		if !bytes.Equal(v, blob) {
+			t.Fatalf("blob %v: got %s, want %s", k, v, blob)
+		}
+	}
+
+	// Verify keys
+	keys, err := s.Keys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := make(map[string]struct{}, len(keys))
+	for _, v := range keys {
+		found[v] = struct{}{}
+	}
+	for _, v := range []string{keyPlain, keyEncrypted} {
+		if _, ok := found[v]; !ok {
+			t.Fatalf("key not found %v", v)
+		}
+	}
+
+	// Delete both blobs
+	err = s.Del([]string{keyPlain, keyEncrypted})
+	if err != nil {
+		t.Fatal(err)
+	}
+	blobs, err = s.Get([]string{keyPlain, keyEncrypted})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blobs) != 0 {
+		t.Fatalf("got %v blobs, want 0", len(blobs))
+	}
+}
+
+func TestPutOverwrite(t *testing.T) {
+	s, cleanup := newTestPostgres(t)
+	defer cleanup()
+
+	// Existing blobs are overwritten, both plain text and encrypted
+	for _, encrypt := range []bool{false, true} {
+		for _, v := range []string{"a", "b"} {
+			err := s.Put(map[string][]byte{"key": []byte(v)}, encrypt)
+			if err != nil {
+				t.Fatalf("encrypt %v: %v", encrypt, err)
+			}
+		}
+		blobs, err := s.Get([]string{"key"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(blobs["key"], []byte("b")) {
+			t.Fatalf("encrypt %v: got %s, want b", encrypt, blobs["key"])
+		}
+	}
+}
+
+func TestPutIsAtomic(t *testing.T) {
+	s, cleanup := newTestPostgres(t)
+	defer cleanup()
+
+	// Saving a key that exceeds the max key length should fail and
+	// none of the blobs should be saved.
+	err := s.Put(map[string][]byte{
+		"a":                      []byte("a"),
+		strings.Repeat("b", 256): []byte("b"),
+	}, true)
+	if err == nil {
+		t.Fatalf("got nil error, want key length error")
+	}
+	blobs, err := s.Get([]string{"a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blobs) != 0 {
+		t.Fatalf("blob was saved during failed put")
+	}
+}
+
+func TestNonceIsUnique(t *testing.T) {
+	s, cleanup := newTestPostgres(t)
+	defer cleanup()
+
+	var (
+		wg      sync.WaitGroup
+		mtx     sync.Mutex
+		threads = 100
+		nonces  = make(map[int64]struct{}, threads)
+		errs    = make([]error, 0, threads)
+	)
+	for i := 0; i < threads; i++ {
+		wg.Add(1)
+		go func(rollback bool) {
+			defer wg.Done()
+
+			ctx, cancel := ctxWithTimeout()
+			defer cancel()
+
+			tx, err := s.db.BeginTx(ctx, &sql.TxOptions{})
+			if err != nil {
+				mtx.Lock()
+				errs = append(errs, err)
+				mtx.Unlock()
+				return
+			}
+			n, err := s.nonce(ctx, tx)
+
+			// Nonces must not be reused even if the transaction that
+			// retrieved them is rolled back.
+			if rollback {
+				tx.Rollback()
+			} else {
+				tx.Commit()
+			}
+
+			mtx.Lock()
+			defer mtx.Unlock()
+			if err != nil {
+				errs = append(errs, err)
+				return
+			}
+			if _, ok := nonces[n]; ok {
+				errs = append(errs, fmt.Errorf("duplicate nonce %v", n))
+				return
+			}
+			nonces[n] = struct{}{}
+		}(i%2 == 0)
+	}
+	wg.Wait()
+
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	if len(nonces) != threads {
+		t.Fatalf("got %v nonces, want %v", len(nonces), threads)
+	}
+}
+
+func TestEncryptionKeyChange(t *testing.T) {
+	s, cleanup := newTestPostgres(t)
+	defer cleanup()
+
+	// Deriving the key again using the same password should work
+	pass := os.Getenv(envTestPass)
+	err := s.deriveEncryptionKeys(pass)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Deriving the key using a different password should fail
+	err = s.deriveEncryptionKeys(pass + "changed")
+	if err == nil {
+		t.Fatalf("got nil error, want encryption key changed error")
+	}
+}
+
+func TestKeyRotation(t *testing.T) {
+	s, cleanup := newTestPostgres(t)
+	defer cleanup()
+
+	var (
+		key  = "encrypted"
+		blob = []byte("blob")
+	)
+
+	// Save a blob that is encrypted using the initial key
+	err := s.Put(map[string][]byte{key: blob}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rotate the key using a new key file. The prior key should not be
+	// retired until the blob has been re-encrypted.
+	keyFile := filepath.Join(s.appDir, "rotated.key")
+	keyID, err := s.RotateKey(keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyID != 1 {
+		t.Fatalf("got key id %v, want 1", keyID)
+	}
+
+	// The same key file cannot be used for another rotation
+	_, err = s.RotateKey(keyFile)
+	if err == nil {
+		t.Fatalf("got nil error, want key reuse error")
+	}
+
+	// The rotated key must be loaded from the key file, not derived
+	// from the password, when the keys are derived again on startup.
+	err = s.deriveEncryptionKeys(os.Getenv(envTestPass))
+	if err != nil {
+		t.Fatal(err)
+	}
+	retired, err := s.RetireKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(retired) != 0 {
+		t.Fatalf("got retired keys %v, want none", retired)
+	}
+
+	// Re-encrypt the blob and retire the prior key
+	count, err := s.Reencrypt([]string{key, "notfound"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Fatalf("got %v re-encrypted blobs, want 1", count)
+	}
+	retired, err = s.RetireKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(retired) != 1 || retired[0] != 0 {
+		t.Fatalf("got retired keys %v, want [0]", retired)
+	}
+	keyIDs, active := s.KeyIDs()
+	if len(keyIDs) != 1 || active != 1 {
+		t.Fatalf("got key ids %v active %v, want [1] active 1",
+			keyIDs, active)
+	}
+
+	// The blob should still be readable
+	blobs, err := s.Get([]string{key})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(blobs[key], blob) {
+		t.Fatalf("got %s, want %s", blobs[key], blob)
+	}
+}
@@ -84,8 +84,8 @@ func Deblob(blob []byte) (*BlobEntry, error) {
 
 // BlobKV represents a blob key-value store.
 type BlobKV interface {
-	// Put saves the provided key-value pairs to the store. This
-	// operation is performed atomically.
+	// Put saves the provided key-value pairs to the store. Existing
+	// blobs are overwritten. This operation is performed atomically.
 	Put(blobs map[string][]byte, encrypt bool) error
 
 	// Del deletes the provided blobs from the store. This operation
@@ -98,6 +98,43 @@ type BlobKV interface {
 	// was returned for all provided keys.
 	Get(keys []string) (map[string][]byte, error)
 
+	// Keys returns all of the keys in the store. This is an expensive
+	// operation that is only meant to be used by maintenance tasks,
+	// such as a filesystem check.
+	Keys() ([]string, error)
+
 	// Closes closes the store connection.
 	Close()
 }
+
+// KeyRotator represents a BlobKV that supports rotating the key that is used
+// to encrypt blobs. Encrypted blobs carry the ID of the key that was used to
+// encrypt them in the sbox header version field. Prior keys are retained
+// until all blobs have been re-encrypted using the active key.
+type KeyRotator interface {
+	// RotateKey creates a new encryption key and makes it the active
+	// key. All blobs that are encrypted after this call returns will
+	// be encrypted using the new key. The ID of the new key is
+	// returned.
+	//
+	// The new key is loaded from the provided key file. The key file is
+	// created using a new random key if it does not exist. The store
+	// uses a default key file if one is not provided. The key must not
+	// be one of the existing keys.
+	RotateKey(keyFile string) (uint32, error)
+
+	// Reencrypt re-encrypts the provided blobs using the active key.
+	// Blobs that are not encrypted or that are already encrypted using
+	// the active key are not modified. The number of blobs that were
+	// re-encrypted is returned.
+	Reencrypt(keys []string) (uint32, error)
+
+	// RetireKeys deletes all encryption keys, other than the active
+	// key, that are no longer used by any blob. The IDs of the retired
+	// keys are returned.
+	RetireKeys() ([]uint32, error)
+
+	// KeyIDs returns the IDs of all encryption keys that have not been
+	// retired and the ID of the active key.
+	KeyIDs() ([]uint32, uint32)
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package store
+
+import (
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// InvTest runs the inventory tests against the Inventory implementation that
+// is returned by the provided function. Each test is run against a new, empty
+// inventory. The returned closure is invoked to clean up the inventory once
+// the test has completed.
+func InvTest(t *testing.T, newInv func(t *testing.T) (Inventory, func())) {
+	t.Helper()
+
+	var tests = []struct {
+		name string
+		test func(*testing.T, Inventory)
+	}{
+		{"query", invTestQuery},
+		{"put concurrent", invTestPutConcurrent},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			inv, cleanup := newInv(t)
+			defer cleanup()
+
+			tc.test(t, inv)
+		})
+	}
+}
+
+// invQueryAll requests all pages of the provided query and returns the tokens
+// of the returned entries.
+func invQueryAll(t *testing.T, inv Inventory, q InvQuery) []string {
+	t.Helper()
+
+	tokens := make([]string, 0, 16)
+	for {
+		p, err := inv.InvQuery(q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, v := range p.Entries {
+			tokens = append(tokens, v.Token)
+		}
+		if p.Cursor == "" {
+			return tokens
+		}
+		q.Cursor = p.Cursor
+	}
+}
+
+// invTestQuery tests the inventory queries, updates and deletes.
+func invTestQuery(t *testing.T, inv Inventory) {
+
+	// Setup the inventory. The created and updated timestamps are in
+	// reverse order of each other. The last two entries share the same
+	// timestamps so that the token tie breaker is exercised.
+	entries := []InvEntry{
+		{Token: "a0", State: 1, Status: 1, Created: 1, Updated: 50},
+		{Token: "a1", State: 2, Status: 2, Created: 2, Updated: 40},
+		{Token: "a2", State: 2, Status: 3, Created: 3, Updated: 30},
+		{Token: "a3", State: 2, Status: 2, Created: 4, Updated: 20},
+		{Token: "a4", State: 2, Status: 2, Created: 4, Updated: 20},
+	}
+	err := inv.InvPut(entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = inv.InvAttributesSet("a3", map[string]string{"p.k": "v"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		name  string
+		query InvQuery
+		want  []string
+	}{
+		{
+			"updated descending",
+			InvQuery{Sort: InvSortUpdated, Limit: 2},
+			[]string{"a0", "a1", "a2", "a4", "a3"},
+		},
+		{
+			"updated ascending",
+			InvQuery{Sort: InvSortUpdated, Ascending: true,
+				Limit: 2},
+			[]string{"a3", "a4", "a2", "a1", "a0"},
+		},
+		{
+			"created ascending",
+			InvQuery{Sort: InvSortCreated, Ascending: true,
+				Limit: 2},
+			[]string{"a0", "a1", "a2", "a3", "a4"},
+		},
+		{
+			"state filter",
+			InvQuery{State: 2, Limit: 3},
+			[]string{"a1", "a2", "a4", "a3"},
+		},
+		{
+			"state and status filter",
+			InvQuery{State: 2, Status: 2, Sort: InvSortCreated,
+				Limit: 1},
+			[]string{"a4", "a3", "a1"},
+		},
+		{
+			"attribute filter",
+			InvQuery{Attributes: map[string]string{"p.k": "v"},
+				Limit: 1},
+			[]string{"a3"},
+		},
+		{
+			"attribute filter no match",
+			InvQuery{Attributes: map[string]string{"p.k": "x"},
+				Limit: 1},
+			[]string{},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := invQueryAll(t, inv, tc.query)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	// Update an entry and verify that the upsert replaced the entry
+	// fields and preserved its attributes.
+	e := entries[3]
+	e.Status = 3
+	e.Updated = 60
+	err = inv.InvPut([]InvEntry{e})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := invQueryAll(t, inv, InvQuery{State: 2, Status: 2, Limit: 10})
+	want := []string{"a1", "a4"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	ge, err := inv.InvGet([]string{"a3", "missing"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.Attributes = map[string]string{"p.k": "v"}
+	if len(ge) != 1 || !reflect.DeepEqual(ge["a3"], e) {
+		t.Errorf("got %+v, want %+v", ge, e)
+	}
+
+	// Delete the attribute using an empty value
+	err = inv.InvAttributesSet("a3", map[string]string{"p.k": ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = invQueryAll(t, inv, InvQuery{
+		Attributes: map[string]string{"p.k": "v"}, Limit: 10})
+	if len(got) != 0 {
+		t.Errorf("got %v, want no entries", got)
+	}
+
+	// Setting the attributes of an entry that does not exist fails
+	err = inv.InvAttributesSet("missing", map[string]string{"p.k": "v"})
+	if err == nil {
+		t.Errorf("got nil error, want error")
+	}
+
+	// Delete an entry
+	err = inv.InvDel([]string{"a0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = invQueryAll(t, inv, InvQuery{Limit: 10})
+	want = []string{"a3", "a1", "a2", "a4"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+// invTestPutConcurrent tests concurrent inventory updates.
+func invTestPutConcurrent(t *testing.T, inv Inventory) {
+
+	// Update the same entries concurrently. Each writer uses its own
+	// updated timestamp. The inventory must end up with a single entry
+	// per token that contains the fields of one of the writers.
+	var (
+		writers = 8
+		tokens  = 16
+		wg      sync.WaitGroup
+		errs    = make(chan error, writers)
+	)
+	entries := func(updated int64) []InvEntry {
+		e := make([]InvEntry, 0, tokens)
+		for j := 0; j < tokens; j++ {
+			e = append(e, InvEntry{
+				Token:   "t" + strconv.Itoa(j),
+				State:   2,
+				Status:  2,
+				Created: 1,
+				Updated: updated,
+			})
+		}
+		return e
+	}
+	err := inv.InvPut(entries(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs <- inv.InvPut(entries(int64(i + 1)))
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got := invQueryAll(t, inv, InvQuery{Limit: 5})
+	if len(got) != tokens {
+		t.Fatalf("got %v entries, want %v", len(got), tokens)
+	}
+	ge, err := inv.InvGet(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range ge {
+		if v.Updated < 1 || v.Updated > int64(writers) {
+			t.Fatalf("got %v updated %v, want a writer timestamp",
+				v.Token, v.Updated)
+		}
+	}
+}
@@ -16,6 +16,7 @@ import (
 
 	dcrtime "github.com/decred/dcrtime/api/v2"
 	"github.com/decred/dcrtime/merkle"
+	backend "github.com/decred/politeia/politeiad/backendv2"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
 	"github.com/decred/politeia/util"
 	"github.com/google/trillian"
@@ -24,17 +25,52 @@ import (
 )
 
 const (
-	// anchorSchedule determines how often we anchor records. dcrtime
-	// currently drops an anchor on the hour mark so we submit new
-	// anchors a few minutes prior to that.
+	// AnchorTypeDcrtime is a config option that sets the anchor client
+	// to dcrtime. dcrtime timestamps the tree root hashes onto the
+	// decred blockchain.
+	AnchorTypeDcrtime = "dcrtime"
+
+	// AnchorTypeLocal is a config option that sets the anchor client
+	// to a local anchor log. The local anchor log does not timestamp
+	// the tree root hashes onto the decred blockchain. It is intended
+	// for development, testing, and private deployments that do not
+	// have access to dcrtime.
+	AnchorTypeLocal = "local"
+
+	// AnchorScheduleDefault is the default anchor schedule. It
+	// determines how often we anchor records. dcrtime currently drops
+	// an anchor on the hour mark so we submit new anchors a few
+	// minutes prior to that.
 	// Seconds Minutes Hours Days Months DayOfWeek
-	anchorSchedule = "0 56 * * * *" // At minute 56 of every hour
+	AnchorScheduleDefault = "0 56 * * * *" // At minute 56 of every hour
 
 	// anchorID is included in the timestamp and verify requests as a
 	// unique identifier.
 	anchorID = "tstorebe"
+
+	// anchorVerifyTimeout is the maximum amount of time that tstore
+	// waits for an anchor to drop. It is set to 180 minutes to ensure
+	// that enough time is given for a dcrtime anchor transaction to
+	// receive 6 confirmations. This is based on the fact that each
+	// block has a 99.75% chance of being mined within 30 minutes.
+	anchorVerifyTimeout = 180 * time.Minute
 )
 
+// anchorClient provides an interface for timestamping the tlog tree root
+// hashes. The dcrtime API types are used regardless of the implementation so
+// that the anchor records and the timestamp proofs have the same format and
+// can be verified using the same tooling.
+type anchorClient interface {
+	// TimestampBatch submits digests to be anchored.
+	TimestampBatch(id string, digests []string) (*dcrtime.TimestampBatchReply, error)
+
+	// VerifyBatch returns the anchor data of the provided digests. The
+	// ChainTimestamp of a digest is populated once the digest has been
+	// anchored. The merkle path of all successful timestamps must be
+	// verified by the implementation.
+	VerifyBatch(id string, digests []string) (*dcrtime.VerifyBatchReply, error)
+}
+
 // anchor represents an anchor, i.e. timestamp, of a trillian tree at a
 // specific tree size. The LogRootV1.RootHash is the merkle root hash of a
 // trillian tree. This root hash is submitted to dcrtime to be anchored and is
@@ -48,22 +84,28 @@ type anchor struct {
 	VerifyDigest *dcrtime.VerifyDigest `json:"verifydigest"`
 }
 
-// droppingAnchorGet returns the dropping anchor boolean, which is used to
-// prevent reentrant anchor drops.
-func (t *Tstore) droppingAnchorGet() bool {
+// droppingAnchorSet sets the dropping anchor boolean, which is used to prevent
+// reentrant anchor drops.
+func (t *Tstore) droppingAnchorSet(b bool) {
 	t.Lock()
 	defer t.Unlock()
 
-	return t.droppingAnchor
+	t.droppingAnchor = b
 }
 
-// droppingAnchorSet sets the dropping anchor boolean, which is used to prevent
-// reentrant anchor drops.
-func (t *Tstore) droppingAnchorSet(b bool) {
+// droppingAnchorStart sets the dropping anchor boolean if an anchor is not
+// already being dropped. The returned boolean indicates whether the caller is
+// now considered to be dropping the anchor.
+func (t *Tstore) droppingAnchorStart() bool {
 	t.Lock()
 	defer t.Unlock()
 
-	t.droppingAnchor = b
+	if t.droppingAnchor {
+		return false
+	}
+	t.droppingAnchor = true
+
+	return true
 }
 
 var (
@@ -162,6 +204,12 @@ func (t *Tstore) anchorLatest(treeID int64) (*anchor, error) {
 		return nil, err
 	}
 
+	return t.anchorLatestForLeaves(leavesAll)
+}
+
+// anchorLatestForLeaves returns the most recent anchor for the provided tree
+// leaves. A errAnchorNotFound is returned if no anchor is found.
+func (t *Tstore) anchorLatestForLeaves(leavesAll []*trillian.LogLeaf) (*anchor, error) {
 	// Find the most recent anchor leaf
 	var key string
 	for i := len(leavesAll) - 1; i >= 0; i-- {
@@ -270,21 +318,16 @@ func (t *Tstore) anchorSave(a anchor) error {
 }
 
 // anchorWait waits for the anchor to drop. The anchor is not considered
-// dropped until dcrtime returns the ChainTimestamp in the reply. dcrtime does
-// not return the ChainTimestamp until the timestamp transaction has 6
-// confirmations. Once the timestamp has been dropped, the anchor record is
-// saved to the tstore, which means that an anchor leaf will be appended onto
-// all trees that were anchored and the anchor records saved to the kv store.
-func (t *Tstore) anchorWait(anchors []anchor, digests []string) {
-	// Verify we are not reentrant
-	if t.droppingAnchorGet() {
-		log.Errorf("waitForAchor: called reentrantly")
-		return
-	}
-
-	// We are now condsidered to be dropping an anchor
-	t.droppingAnchorSet(true)
-
+// dropped until the anchor client returns the ChainTimestamp in the reply.
+// dcrtime does not return the ChainTimestamp until the timestamp transaction
+// has 6 confirmations. Once the timestamp has been dropped, the anchor record
+// is saved to the tstore, which means that an anchor leaf will be appended
+// onto all trees that were anchored and the anchor records saved to the kv
+// store. The progress of the wait is recorded in the provided anchor drop.
+//
+// The dropping anchor boolean is set by the caller and is cleared once this
+// function returns.
+func (t *Tstore) anchorWait(anchors []anchor, digests []string, d *backend.AnchorDrop) {
 	// Whatever happens in this function we must clear droppingAnchor
 	var exitErr error
 	defer func() {
@@ -292,35 +335,35 @@ func (t *Tstore) anchorWait(anchors []anchor, digests []string) {
 
 		if exitErr != nil {
 			log.Errorf("anchorWait: %v", exitErr)
+			t.anchorDropFailed(d, exitErr)
 		}
 	}()
 
 	// Wait for anchor to drop
 	log.Infof("Waiting for anchor to drop")
 
-	// Continually check with dcrtime if the anchor has been dropped.
-	// The anchor is not considered dropped until the ChainTimestamp
-	// field of the dcrtime reply has been populated. dcrtime only
-	// populates the ChainTimestamp field once the dcr transaction has
-	// 6 confirmations.
+	// Continually check with the anchor client if the anchor has been
+	// dropped. The anchor is not considered dropped until the
+	// ChainTimestamp field of the reply has been populated. dcrtime
+	// only populates the ChainTimestamp field once the dcr transaction
+	// has 6 confirmations.
 	var (
-		// The max retry period is set to 180 minutes to ensure that
-		// enough time is given for the anchor transaction to receive 6
-		// confirmations. This is based on the fact that each block has
-		// a 99.75% chance of being mined within 30 minutes.
-		period  = 5 * time.Minute             // check every 5 minute
-		retries = 180 / int(period.Minutes()) // for up to 180 minutes
+		period  = t.anchorVerifyPeriod
+		retries = int(anchorVerifyTimeout / period)
 		ticker  = time.NewTicker(period)
 	)
 	defer ticker.Stop()
+	t.anchorDropUpdate(d, func(d *backend.AnchorDrop) {
+		d.MaxAttempts = uint32(retries)
+	})
 	for try := 0; try < retries; try++ {
 		<-ticker.C
 
 		log.Debugf("Verify anchor attempt %v/%v", try+1, retries)
 
-		vbr, err := t.dcrtime.verifyBatch(anchorID, digests)
+		vbr, err := t.anchor.VerifyBatch(anchorID, digests)
 		if err != nil {
-			exitErr = fmt.Errorf("verifyBatch: %v", err)
+			exitErr = fmt.Errorf("VerifyBatch: %v", err)
 			return
 		}
 
@@ -341,13 +384,21 @@ func (t *Tstore) anchorWait(anchors []anchor, digests []string) {
 		// valid timestamp for treeA since it was already timestamped
 		// into block 1000. In this situation, the verify loop must also
 		// wait for treeB to be timestamped by dcrtime before continuing.
-		anchored := make(map[string]struct{}, len(digests))
+		var (
+			anchored = make(map[string]struct{}, len(digests))
+			progress = anchorProgress{
+				txIDs:       make(map[string]struct{}, 1),
+				merkleRoots: make(map[string]struct{}, 1),
+			}
+		)
 		for _, v := range vbr.Digests {
 			if v.Result != dcrtime.ResultOK {
 				// Something is wrong. Log the error and retry.
 				log.Errorf("Digest %v: %v (%v)",
 					v.Digest, dcrtime.Result[v.Result], v.Result)
-				break
+				progress.err = fmt.Errorf("digest %v: %v",
+					v.Digest, dcrtime.Result[v.Result])
+				continue
 			}
 
 			// Transaction will be populated once the tx has been sent,
@@ -355,20 +406,24 @@ func (t *Tstore) anchorWait(anchors []anchor, digests []string) {
 			b := make([]byte, sha256.Size)
 			if v.ChainInformation.Transaction == hex.EncodeToString(b) {
 				log.Debugf("Anchor tx not sent yet; retry in %v", period)
-				break
+				continue
 			}
+			progress.txIDs[v.ChainInformation.Transaction] = struct{}{}
+			progress.merkleRoots[v.ChainInformation.MerkleRoot] = struct{}{}
 
 			// ChainTimestamp will be populated once the tx has 6
 			// confirmations.
 			if v.ChainInformation.ChainTimestamp == 0 {
 				log.Debugf("Anchor tx %v not enough confirmations; retry in %v",
 					v.ChainInformation.Transaction, period)
-				break
+				continue
 			}
 
 			// This digest has been anchored
 			anchored[v.Digest] = struct{}{}
 		}
+		progress.anchored = len(anchored)
+		t.anchorDropProgress(d, progress)
 		if len(anchored) != len(digests) {
 			// There are still digests that are waiting to be anchored.
 			// Retry again after the wait period.
@@ -376,6 +431,7 @@ func (t *Tstore) anchorWait(anchors []anchor, digests []string) {
 		}
 
 		// Save anchor records
+		var failed int
 		for k, v := range anchors {
 			var (
 				verifyDigest = vbr.Digests[k]
@@ -388,6 +444,7 @@ func (t *Tstore) anchorWait(anchors []anchor, digests []string) {
 			if digest != hex.EncodeToString(v.LogRoot.RootHash) {
 				log.Errorf("anchorWait: digest mismatch: got %x, want %v",
 					digest, v.LogRoot.RootHash)
+				failed++
 				continue
 			}
 
@@ -395,11 +452,13 @@ func (t *Tstore) anchorWait(anchors []anchor, digests []string) {
 			mk, err := merkle.VerifyAuthPath(&merklePath)
 			if err != nil {
 				log.Errorf("anchorWait: VerifyAuthPath: %v", err)
+				failed++
 				continue
 			}
 			if hex.EncodeToString(mk[:]) != merkleRoot {
 				log.Errorf("anchorWait: merkle root invalid: got %x, want %v",
 					mk[:], merkleRoot)
+				failed++
 				continue
 			}
 
@@ -413,6 +472,7 @@ func (t *Tstore) anchorWait(anchors []anchor, digests []string) {
 			}
 			if !found {
 				log.Errorf("anchorWait: digest %v not found in merkle path", digest)
+				failed++
 				continue
 			}
 
@@ -423,28 +483,38 @@ func (t *Tstore) anchorWait(anchors []anchor, digests []string) {
 			err = t.anchorSave(v)
 			if err != nil {
 				log.Errorf("anchorWait: anchorSave %v: %v", v.TreeID, err)
+				failed++
 				continue
 			}
 		}
+		if failed > 0 {
+			exitErr = fmt.Errorf("%v of %v anchor records could not be saved",
+				failed, len(anchors))
+			return
+		}
 
 		log.Infof("Anchor dropped for %v records", len(vbr.Digests))
+		t.anchorDropConfirmed(d)
 		return
 	}
 
-	log.Errorf("Anchor drop timeout, waited for: %v",
-		int(period.Minutes())*retries)
+	exitErr = fmt.Errorf("anchor drop timeout, waited for: %v",
+		anchorVerifyTimeout)
 }
 
 // anchorTrees drops an anchor for any trees that have unanchored leaves at the
 // time of invocation. A SHA256 digest of the tree's log root at its current
-// height is timestamped onto the decred blockchain using the dcrtime service.
-// The anchor data is saved to the key-value store and the tlog tree is updated
-// with an anchor leaf.
+// height is timestamped using the anchor client, i.e. onto the decred
+// blockchain when using dcrtime. The anchor data is saved to the key-value
+// store and the tlog tree is updated with an anchor leaf.
+//
+// A backend ErrAnchorDropInProgress is returned if a prior anchor has not
+// finished dropping.
 func (t *Tstore) anchorTrees() error {
 	log.Debugf("Start anchor process")
 
 	// Ensure we are not reentrant
-	if t.droppingAnchorGet() {
+	if !t.droppingAnchorStart() {
 		// An anchor is not considered dropped until dcrtime returns the
 		// ChainTimestamp in the VerifyReply. dcrtime does not do this
 		// until the anchor tx has 6 confirmations, therefor, this code
@@ -455,9 +525,19 @@ func (t *Tstore) anchorTrees() error {
 		// anchor period.
 		log.Infof("Attempting to drop an anchor while previous anchor " +
 			"has not finished dropping; skipping current anchor period")
-		return nil
+		return backend.ErrAnchorDropInProgress
 	}
 
+	// We are now considered to be dropping an anchor. The flag is
+	// cleared on return unless the anchor is submitted, in which case
+	// anchorWait clears it once the anchor has dropped.
+	var submitted bool
+	defer func() {
+		if !submitted {
+			t.droppingAnchorSet(false)
+		}
+	}()
+
 	trees, err := t.tlog.TreesAll()
 	if err != nil {
 		return fmt.Errorf("TreesAll: %v", err)
@@ -538,12 +618,15 @@ func (t *Tstore) anchorTrees() error {
 		return nil
 	}
 
-	// Submit dcrtime anchor request
+	// Submit anchor request
 	log.Infof("Anchoring %v trees", len(anchors))
 
-	tbr, err := t.dcrtime.timestampBatch(anchorID, digests)
+	d := t.anchorDropNew(len(anchors))
+	tbr, err := t.anchor.TimestampBatch(anchorID, digests)
 	if err != nil {
-		return fmt.Errorf("timestampBatch: %v", err)
+		err = fmt.Errorf("TimestampBatch: %v", err)
+		t.anchorDropFailed(d, err)
+		return err
 	}
 	var failed bool
 	for i, v := range tbr.Results {
@@ -564,11 +647,14 @@ func (t *Tstore) anchorTrees() error {
 		}
 	}
 	if failed {
-		return fmt.Errorf("dcrtime failed to timestamp digests")
+		err = fmt.Errorf("failed to timestamp digests")
+		t.anchorDropFailed(d, err)
+		return err
 	}
 
-	// Launch go routine that polls dcrtime for the anchor tx
-	go t.anchorWait(anchors, digests)
+	// Launch go routine that polls the anchor client for the anchor tx
+	submitted = true
+	go t.anchorWait(anchors, digests, d)
 
 	return nil
 }
@@ -0,0 +1,226 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tstore
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+)
+
+const (
+	// anchorDropHistory is the number of anchor drops that are kept in
+	// the in memory anchor drop history.
+	anchorDropHistory = 20
+)
+
+// anchorProgress contains the results of a single anchor verify attempt.
+type anchorProgress struct {
+	anchored    int                 // Number of confirmed digests
+	txIDs       map[string]struct{} // Sent anchor transactions
+	merkleRoots map[string]struct{} // Merkle roots of the sent anchors
+	err         error               // Digest error
+}
+
+// anchorDropNew adds a new anchor drop to the anchor drop history and returns
+// it. The oldest drop is removed once the history is full.
+func (t *Tstore) anchorDropNew(trees int) *backend.AnchorDrop {
+	d := &backend.AnchorDrop{
+		Status:  backend.AnchorDropSubmitted,
+		Trees:   uint32(trees),
+		Started: time.Now().Unix(),
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	t.anchorDrops = append(t.anchorDrops, d)
+	if len(t.anchorDrops) > anchorDropHistory {
+		t.anchorDrops = t.anchorDrops[1:]
+	}
+
+	return d
+}
+
+// anchorDropUpdate executes the provided update on an anchor drop while
+// holding the tstore lock. A nil anchor drop is ignored.
+func (t *Tstore) anchorDropUpdate(d *backend.AnchorDrop, update func(*backend.AnchorDrop)) {
+	if d == nil {
+		return
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	update(d)
+}
+
+// anchorDropProgress records the results of an anchor verify attempt.
+func (t *Tstore) anchorDropProgress(d *backend.AnchorDrop, p anchorProgress) {
+	t.anchorDropUpdate(d, func(d *backend.AnchorDrop) {
+		d.Attempts++
+		d.LastCheck = time.Now().Unix()
+		d.Anchored = uint32(p.anchored)
+		d.TxIDs = sortedKeys(p.txIDs)
+		d.MerkleRoots = sortedKeys(p.merkleRoots)
+		if len(d.TxIDs) > 0 {
+			d.Status = backend.AnchorDropSent
+		}
+		d.Error = ""
+		if p.err != nil {
+			d.Error = p.err.Error()
+		}
+	})
+}
+
+// anchorDropConfirmed marks an anchor drop as confirmed.
+func (t *Tstore) anchorDropConfirmed(d *backend.AnchorDrop) {
+	t.anchorDropUpdate(d, func(d *backend.AnchorDrop) {
+		d.Status = backend.AnchorDropConfirmed
+		d.Completed = time.Now().Unix()
+		d.Error = ""
+	})
+}
+
+// anchorDropFailed marks an anchor drop as failed.
+func (t *Tstore) anchorDropFailed(d *backend.AnchorDrop, err error) {
+	t.anchorDropUpdate(d, func(d *backend.AnchorDrop) {
+		d.Status = backend.AnchorDropFailed
+		d.Completed = time.Now().Unix()
+		d.Error = err.Error()
+	})
+}
+
+// anchorRun drops an anchor and records the outcome of the run. A run that is
+// skipped because a prior anchor has not finished dropping is not considered
+// an error.
+func (t *Tstore) anchorRun() error {
+	err := t.anchorTrees()
+
+	t.Lock()
+	t.anchorLastRun = time.Now().Unix()
+	t.anchorLastErr = ""
+	if err != nil && !errors.Is(err, backend.ErrAnchorDropInProgress) {
+		t.anchorLastErr = err.Error()
+	}
+	t.Unlock()
+
+	return err
+}
+
+// anchorPending returns the trees that contain leaves that have not been
+// anchored yet. The anchor leaf of a tree is not counted as an unanchored
+// leaf.
+func (t *Tstore) anchorPending() ([]backend.AnchorTree, error) {
+	trees, err := t.tlog.TreesAll()
+	if err != nil {
+		return nil, fmt.Errorf("TreesAll: %v", err)
+	}
+	pending := make([]backend.AnchorTree, 0, len(trees))
+	for _, v := range trees {
+		leavesAll, err := t.tlog.LeavesAll(v.TreeId)
+		if err != nil {
+			return nil, fmt.Errorf("LeavesAll %v: %v", v.TreeId, err)
+		}
+		if len(leavesAll) == 0 {
+			continue
+		}
+		at := backend.AnchorTree{
+			Token:  hex.EncodeToString(tokenFromTreeID(v.TreeId)),
+			Leaves: uint64(len(leavesAll)),
+		}
+		a, err := t.anchorLatestForLeaves(leavesAll)
+		switch {
+		case errors.Is(err, errAnchorNotFound):
+			// Tree has not been anchored yet
+		case err != nil:
+			return nil, fmt.Errorf("anchorLatest %v: %v", v.TreeId, err)
+		default:
+			at.AnchoredSize = a.LogRoot.TreeSize
+			at.Leaves = 0
+			if uint64(len(leavesAll)) > a.LogRoot.TreeSize+1 {
+				at.Leaves = uint64(len(leavesAll)) - a.LogRoot.TreeSize - 1
+			}
+			if a.VerifyDigest != nil {
+				at.Anchored = a.VerifyDigest.ChainInformation.ChainTimestamp
+			}
+		}
+		if at.Leaves == 0 {
+			continue
+		}
+		pending = append(pending, at)
+	}
+
+	return pending, nil
+}
+
+// AnchorStatus returns the status of the tstore anchoring. This includes the
+// trees that have unanchored leaves and the most recent anchor drops.
+func (t *Tstore) AnchorStatus() (*backend.AnchorStatus, error) {
+	log.Tracef("AnchorStatus")
+
+	pending, err := t.anchorPending()
+	if err != nil {
+		return nil, err
+	}
+
+	var nextDrop int64
+	if t.cron != nil {
+		for _, v := range t.cron.Entries() {
+			if !v.Next.IsZero() {
+				nextDrop = v.Next.Unix()
+			}
+		}
+	}
+
+	t.RLock()
+	defer t.RUnlock()
+
+	// Return the drops from most recent to oldest
+	drops := make([]backend.AnchorDrop, 0, len(t.anchorDrops))
+	for i := len(t.anchorDrops) - 1; i >= 0; i-- {
+		drops = append(drops, *t.anchorDrops[i])
+	}
+
+	return &backend.AnchorStatus{
+		Type:      t.anchorType,
+		Schedule:  t.anchorSchedule,
+		NextDrop:  nextDrop,
+		Dropping:  t.droppingAnchor,
+		LastRun:   t.anchorLastRun,
+		LastError: t.anchorLastErr,
+		Pending:   pending,
+		Drops:     drops,
+	}, nil
+}
+
+// AnchorDrop drops an anchor for all trees that have unanchored leaves
+// without waiting for the anchor schedule. The anchor is dropped in the
+// background. A backend ErrAnchorDropInProgress is returned if a prior anchor
+// has not finished dropping.
+func (t *Tstore) AnchorDrop() (*backend.AnchorStatus, error) {
+	log.Tracef("AnchorDrop")
+
+	err := t.anchorRun()
+	if err != nil {
+		return nil, err
+	}
+
+	return t.AnchorStatus()
+}
+
+// sortedKeys returns the sorted keys of the provided map.
+func sortedKeys(m map[string]struct{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
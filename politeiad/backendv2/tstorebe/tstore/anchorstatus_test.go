@@ -0,0 +1,206 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tstore
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	dcrtime "github.com/decred/dcrtime/api/v2"
+	backend "github.com/decred/politeia/politeiad/backendv2"
+)
+
+// failingAnchorClient is an anchor client that fails every request.
+type failingAnchorClient struct{}
+
+// TimestampBatch satisfies the anchorClient interface.
+func (failingAnchorClient) TimestampBatch(id string, digests []string) (*dcrtime.TimestampBatchReply, error) {
+	return nil, errors.New("timestamp batch failed")
+}
+
+// VerifyBatch satisfies the anchorClient interface.
+func (failingAnchorClient) VerifyBatch(id string, digests []string) (*dcrtime.VerifyBatchReply, error) {
+	return nil, errors.New("verify batch failed")
+}
+
+// waitAnchorDrop waits for the anchor drop that is in progress to complete
+// and returns the anchor status.
+func waitAnchorDrop(t *testing.T, ts *Tstore) *backend.AnchorStatus {
+	t.Helper()
+
+	timeout := time.After(10 * time.Second)
+	for {
+		as, err := ts.AnchorStatus()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !as.Dropping {
+			return as
+		}
+		select {
+		case <-timeout:
+			t.Fatalf("anchor drop did not complete")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestAnchorStatus(t *testing.T) {
+	ts, cleanup := newTestTstoreNative(t)
+	defer cleanup()
+	ts.anchorVerifyPeriod = 10 * time.Millisecond
+
+	// Nothing has been anchored yet
+	as, err := ts.AnchorStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if as.Type != AnchorTypeLocal || as.NextDrop == 0 {
+		t.Errorf("got type %v and next drop %v, want %v and a next drop",
+			as.Type, as.NextDrop, AnchorTypeLocal)
+	}
+	if len(as.Pending) != 0 || len(as.Drops) != 0 || as.Dropping {
+		t.Fatalf("got status %+v, want no pending trees or drops", as)
+	}
+
+	// A new record is pending until an anchor is dropped
+	newTestRecord(t, ts)
+	as, err = ts.AnchorStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(as.Pending) != 1 || as.Pending[0].Leaves == 0 ||
+		as.Pending[0].AnchoredSize != 0 {
+		t.Fatalf("got pending trees %+v, want 1 unanchored tree", as.Pending)
+	}
+
+	// Force an anchor drop and wait for it to be confirmed
+	_, err = ts.AnchorDrop()
+	if err != nil {
+		t.Fatal(err)
+	}
+	as = waitAnchorDrop(t, ts)
+	if len(as.Drops) != 1 {
+		t.Fatalf("got %v anchor drops, want 1", len(as.Drops))
+	}
+	d := as.Drops[0]
+	if d.Status != backend.AnchorDropConfirmed || d.Trees != 1 ||
+		d.Anchored != 1 || d.Completed == 0 || d.Error != "" {
+		t.Fatalf("got anchor drop %+v, want 1 confirmed tree", d)
+	}
+	if len(as.Pending) != 0 {
+		t.Fatalf("got pending trees %+v, want none", as.Pending)
+	}
+	if as.LastRun == 0 || as.LastError != "" {
+		t.Fatalf("got last run %v and last error '%v', want a run and no "+
+			"error", as.LastRun, as.LastError)
+	}
+
+	// A forced drop when there is nothing to anchor does not create a
+	// new anchor drop.
+	as, err = ts.AnchorDrop()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(as.Drops) != 1 || as.Dropping {
+		t.Fatalf("got %v anchor drops and dropping %v, want 1 and false",
+			len(as.Drops), as.Dropping)
+	}
+}
+
+func TestAnchorDropInProgress(t *testing.T) {
+	ts, cleanup := newTestTstoreNative(t)
+	defer cleanup()
+	ts.anchorVerifyPeriod = 10 * time.Millisecond
+
+	newTestRecord(t, ts)
+
+	// A forced drop is rejected while a prior anchor is still being
+	// dropped. The rejected drop must not clear the flag of the drop
+	// that is in progress and is not recorded as an error.
+	ts.droppingAnchorSet(true)
+	_, err := ts.AnchorDrop()
+	if !errors.Is(err, backend.ErrAnchorDropInProgress) {
+		t.Fatalf("got error %v, want %v", err, backend.ErrAnchorDropInProgress)
+	}
+	as, err := ts.AnchorStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !as.Dropping {
+		t.Fatalf("rejected anchor drop cleared the dropping anchor flag")
+	}
+	if len(as.Drops) != 0 || len(as.Pending) != 1 || as.LastError != "" {
+		t.Fatalf("got %v drops, %v pending trees and last error '%v', "+
+			"want 0, 1 and no error", len(as.Drops), len(as.Pending),
+			as.LastError)
+	}
+
+	// The drop succeeds once the prior anchor has dropped
+	ts.droppingAnchorSet(false)
+	_, err = ts.AnchorDrop()
+	if err != nil {
+		t.Fatal(err)
+	}
+	as = waitAnchorDrop(t, ts)
+	if len(as.Drops) != 1 ||
+		as.Drops[0].Status != backend.AnchorDropConfirmed {
+		t.Fatalf("got anchor drops %+v, want 1 confirmed drop", as.Drops)
+	}
+}
+
+func TestAnchorDropFailed(t *testing.T) {
+	ts, cleanup := newTestTstoreNative(t)
+	defer cleanup()
+	ts.anchor = failingAnchorClient{}
+
+	newTestRecord(t, ts)
+
+	// A failed drop is recorded and the dropping anchor flag is cleared
+	// so that the next drop can be attempted.
+	_, err := ts.AnchorDrop()
+	if err == nil {
+		t.Fatalf("anchor drop did not fail")
+	}
+	as, err := ts.AnchorStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if as.Dropping {
+		t.Fatalf("failed anchor drop did not clear the dropping anchor flag")
+	}
+	if len(as.Drops) != 1 || as.Drops[0].Status != backend.AnchorDropFailed ||
+		as.Drops[0].Error == "" {
+		t.Fatalf("got anchor drops %+v, want 1 failed drop", as.Drops)
+	}
+	if as.LastError == "" || len(as.Pending) != 1 {
+		t.Fatalf("got last error '%v' and %v pending trees, want an error "+
+			"and 1", as.LastError, len(as.Pending))
+	}
+}
+
+func TestAnchorDropHistory(t *testing.T) {
+	ts, cleanup := newTestTstoreNative(t)
+	defer cleanup()
+
+	// Only the most recent drops are kept
+	for i := 0; i < anchorDropHistory+5; i++ {
+		ts.anchorDropNew(i + 1)
+	}
+	as, err := ts.AnchorStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(as.Drops) != anchorDropHistory {
+		t.Fatalf("got %v drops, want %v", len(as.Drops), anchorDropHistory)
+	}
+	if as.Drops[0].Trees != anchorDropHistory+5 ||
+		as.Drops[len(as.Drops)-1].Trees != 6 {
+		t.Fatalf("got drops from %v to %v trees, want from %v to 6",
+			as.Drops[0].Trees, as.Drops[len(as.Drops)-1].Trees,
+			anchorDropHistory+5)
+	}
+}
@@ -0,0 +1,701 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tstore
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+	"github.com/google/trillian"
+	"github.com/google/trillian/crypto/keyspb"
+	"github.com/google/trillian/types"
+	"github.com/marcopeereboom/sbox"
+)
+
+const (
+	// backupVersion is the version of the backup format.
+	backupVersion = 1
+
+	// The following are the names of the files and directories that a
+	// backup directory contains. The manifest is written last. A backup
+	// directory that does not contain a manifest is incomplete.
+	backupManifestFilename = "manifest.json"
+	backupBlobsFilename    = "blobs.json.gz"
+	backupFilesDirname     = "files"
+
+	// backupBatchSize is the number of blobs that are read from or
+	// saved to the key-value store in a single call.
+	backupBatchSize = 100
+
+	// backupKeyDomain is used to derive the backup encryption key from
+	// the tlog signing key.
+	backupKeyDomain = "politeiad-backup"
+)
+
+var (
+	// backupKeyPrefixesExcluded contains the prefixes of the key-value
+	// store keys that are not included in a backup. These blobs are
+	// managed by the key-value store implementations, e.g. encryption
+	// key params and inventory index entries, and are recreated by the
+	// key-value store that the backup is restored to. The tlog key
+	// params are included in the backup manifest instead.
+	backupKeyPrefixesExcluded = []string{
+		"store-",
+		"inv_",
+		tlogKeyParamsKey,
+	}
+)
+
+// backupManifest describes the contents of a backup.
+type backupManifest struct {
+	Version       uint32       `json:"version"`
+	Name          string       `json:"name"`
+	Timestamp     int64        `json:"timestamp"`
+	TlogType      string       `json:"tlogtype"`
+	TlogKeyParams []byte       `json:"tlogkeyparams"` // tlogKeyParams blob
+	Trees         []backupTree `json:"trees"`
+	Blobs         uint64       `json:"blobs"`
+	Files         []string     `json:"files"` // Relative to the data dir
+}
+
+// backupTree contains the state of a tlog tree at the time of a backup.
+type backupTree struct {
+	TreeID   int64              `json:"treeid"`
+	State    trillian.TreeState `json:"state"`
+	Size     uint64             `json:"size"`
+	RootHash []byte             `json:"roothash"`
+}
+
+// backupBlob is a key-value store blob that is included in a backup. Blobs
+// with an encrypted key are encrypted using the backup encryption key.
+type backupBlob struct {
+	Key  string `json:"key"`
+	Blob []byte `json:"blob"`
+}
+
+// writeFreezer blocks writes while a backup is being taken or while the plugin
+// caches are being rebuilt. Writers hold the read lock for the duration of a
+// write. A backup or a cache rebuild holds the write lock.
+type writeFreezer struct {
+	sync.RWMutex
+}
+
+// frozenBlobKV is a key-value store decorator that blocks writes while a
+// backup is being taken.
+type frozenBlobKV struct {
+	store.BlobKV
+	freezer *writeFreezer
+}
+
+// Put saves the provided key-value pairs to the store.
+//
+// This function satisfies the store BlobKV interface.
+func (s *frozenBlobKV) Put(blobs map[string][]byte, encrypt bool) error {
+	s.freezer.RLock()
+	defer s.freezer.RUnlock()
+
+	return s.BlobKV.Put(blobs, encrypt)
+}
+
+// Del deletes the provided blobs from the store.
+//
+// This function satisfies the store BlobKV interface.
+func (s *frozenBlobKV) Del(keys []string) error {
+	s.freezer.RLock()
+	defer s.freezer.RUnlock()
+
+	return s.BlobKV.Del(keys)
+}
+
+// frozenTlog is a tlog client decorator that blocks writes while a backup is
+// being taken.
+type frozenTlog struct {
+	tlogClient
+	freezer *writeFreezer
+}
+
+// TreeNew creates a new tree.
+//
+// This function satisfies the tlogClient interface.
+func (t *frozenTlog) TreeNew() (*trillian.Tree, *trillian.SignedLogRoot, error) {
+	t.freezer.RLock()
+	defer t.freezer.RUnlock()
+
+	return t.tlogClient.TreeNew()
+}
+
+// TreeFreeze sets the status of a tree to frozen.
+//
+// This function satisfies the tlogClient interface.
+func (t *frozenTlog) TreeFreeze(treeID int64) (*trillian.Tree, error) {
+	t.freezer.RLock()
+	defer t.freezer.RUnlock()
+
+	return t.tlogClient.TreeFreeze(treeID)
+}
+
+// LeavesAppend appends leaves onto a tree.
+//
+// This function satisfies the tlogClient interface.
+func (t *frozenTlog) LeavesAppend(treeID int64, leaves []*trillian.LogLeaf) ([]queuedLeafProof, *types.LogRootV1, error) {
+	t.freezer.RLock()
+	defer t.freezer.RUnlock()
+
+	return t.tlogClient.LeavesAppend(treeID, leaves)
+}
+
+// backupKeyDerive derives the key that is used to encrypt the unvetted blobs
+// of a backup from the tlog signing key. A backup can only be restored by a
+// politeiad instance that uses the same tlog signing key.
+func backupKeyDerive(tlogKey *keyspb.PrivateKey) *[32]byte {
+	k := sha256.Sum256(append([]byte(backupKeyDomain), tlogKey.Der...))
+	return &k
+}
+
+// backupKeyExcluded returns whether the provided key-value store key is
+// excluded from backups.
+func backupKeyExcluded(key string) bool {
+	for _, v := range backupKeyPrefixesExcluded {
+		if strings.HasPrefix(key, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// backupStart sets the backing up boolean if a backup is not already in
+// progress. The returned boolean indicates whether the caller is now
+// considered to be taking the backup.
+func (t *Tstore) backupStart() bool {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.backingUp {
+		return false
+	}
+	t.backingUp = true
+
+	return true
+}
+
+// backupDone clears the backing up boolean.
+func (t *Tstore) backupDone() {
+	t.Lock()
+	defer t.Unlock()
+
+	t.backingUp = false
+}
+
+// backupTrees returns the state of all tlog trees.
+func (t *Tstore) backupTrees() ([]backupTree, error) {
+	trees, err := t.tlog.TreesAll()
+	if err != nil {
+		return nil, fmt.Errorf("TreesAll: %v", err)
+	}
+	bt := make([]backupTree, 0, len(trees))
+	for _, v := range trees {
+		_, lr, err := t.tlog.SignedLogRoot(v)
+		if err != nil {
+			return nil, fmt.Errorf("SignedLogRoot %v: %v", v.TreeId, err)
+		}
+		bt = append(bt, backupTree{
+			TreeID:   v.TreeId,
+			State:    v.TreeState,
+			Size:     lr.TreeSize,
+			RootHash: lr.RootHash,
+		})
+	}
+	return bt, nil
+}
+
+// backupBlobs writes all key-value store blobs that are not excluded from
+// backups to the provided file. The number of blobs that were written is
+// returned.
+func (t *Tstore) backupBlobs(fp string) (uint64, error) {
+	keys, err := t.store.Keys()
+	if err != nil {
+		return 0, fmt.Errorf("Keys: %v", err)
+	}
+	included := make([]string, 0, len(keys))
+	for _, v := range keys {
+		if backupKeyExcluded(v) {
+			continue
+		}
+		included = append(included, v)
+	}
+
+	f, err := os.OpenFile(fp, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	zw := gzip.NewWriter(f)
+	enc := json.NewEncoder(zw)
+
+	var count uint64
+	for i := 0; i < len(included); i += backupBatchSize {
+		end := i + backupBatchSize
+		if end > len(included) {
+			end = len(included)
+		}
+		blobs, err := t.store.Get(included[i:end])
+		if err != nil {
+			return 0, fmt.Errorf("Get: %v", err)
+		}
+		for _, k := range included[i:end] {
+			b, ok := blobs[k]
+			if !ok {
+				// The blob was deleted prior to the writes being
+				// frozen.
+				continue
+			}
+			if strings.HasPrefix(k, keyPrefixEncrypted) {
+				b, err = sbox.Encrypt(backupVersion, t.backupKey, b)
+				if err != nil {
+					return 0, fmt.Errorf("encrypt %v: %v", k, err)
+				}
+			}
+			err = enc.Encode(backupBlob{
+				Key:  k,
+				Blob: b,
+			})
+			if err != nil {
+				return 0, err
+			}
+			count++
+		}
+	}
+
+	err = zw.Close()
+	if err != nil {
+		return 0, err
+	}
+	err = f.Sync()
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// copyFile copies the src file to dst. The dst file must not exist.
+func copyFile(src, dst string) error {
+	err := os.MkdirAll(filepath.Dir(dst), 0700)
+	if err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(out, in)
+	if err == nil {
+		err = out.Sync()
+	}
+	if err2 := out.Close(); err == nil {
+		err = err2
+	}
+	return err
+}
+
+// backupFiles copies the files of the tstore data directory to the provided
+// directory. This includes the local anchor log and the plugin data. The
+// leveldb key-value store directory is not copied since the key-value store
+// blobs are backed up separately. The relative paths of the copied files are
+// returned.
+func (t *Tstore) backupFiles(backupsDir, dst string) ([]string, error) {
+	skip := []string{
+		filepath.Join(t.dataDir, storeDirname),
+		filepath.Clean(backupsDir),
+	}
+	files := make([]string, 0, 256)
+	err := filepath.Walk(t.dataDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		for _, v := range skip {
+			if path == v {
+				return filepath.SkipDir
+			}
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(t.dataDir, path)
+		if err != nil {
+			return err
+		}
+		err = copyFile(path, filepath.Join(dst, rel))
+		if err != nil {
+			return fmt.Errorf("copy %v: %v", rel, err)
+		}
+		files = append(files, rel)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// Backup saves a point-in-time snapshot of the tstore to a new directory
+// inside of the provided directory. The snapshot contains the state of all
+// tlog trees, the key-value store blobs, and the files of the tstore data
+// directory. The native tlog saves its trees to the key-value store, so the
+// trees are fully restored with the blobs. A trillian database is not managed
+// by tstore and must be snapshotted by the hook, which is executed while the
+// writes are still blocked.
+//
+// The unvetted blobs are encrypted using a key that is derived from the tlog
+// signing key.
+func (t *Tstore) Backup(dir string, hook func(backupDir string) error) (*backend.BackupSummary, error) {
+	log.Tracef("Backup: %v", dir)
+
+	if !t.backupStart() {
+		return nil, backend.ErrBackupInProgress
+	}
+	defer t.backupDone()
+
+	// Setup the backup directory
+	var (
+		now       = time.Now()
+		name      = now.UTC().Format("20060102T150405Z")
+		backupDir = filepath.Join(dir, name)
+	)
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+	err = os.Mkdir(backupDir, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	// Block all writes for the duration of the backup. An incomplete
+	// backup is removed.
+	log.Infof("Starting backup %v; writes are blocked until the backup "+
+		"has completed", backupDir)
+
+	t.freezer.Lock()
+	defer t.freezer.Unlock()
+
+	var success bool
+	defer func() {
+		if !success {
+			os.RemoveAll(backupDir)
+		}
+	}()
+
+	trees, err := t.backupTrees()
+	if err != nil {
+		return nil, err
+	}
+	blobs, err := t.backupBlobs(filepath.Join(backupDir, backupBlobsFilename))
+	if err != nil {
+		return nil, fmt.Errorf("backup blobs: %v", err)
+	}
+	kv, err := t.store.Get([]string{tlogKeyParamsKey})
+	if err != nil {
+		return nil, fmt.Errorf("get tlog key params: %v", err)
+	}
+	files, err := t.backupFiles(dir,
+		filepath.Join(backupDir, backupFilesDirname))
+	if err != nil {
+		return nil, fmt.Errorf("backup files: %v", err)
+	}
+	if hook != nil {
+		err = hook(backupDir)
+		if err != nil {
+			return nil, fmt.Errorf("hook: %v", err)
+		}
+	}
+
+	// Save the manifest
+	m := backupManifest{
+		Version:       backupVersion,
+		Name:          name,
+		Timestamp:     now.Unix(),
+		TlogType:      t.tlogType,
+		TlogKeyParams: kv[tlogKeyParamsKey],
+		Trees:         trees,
+		Blobs:         blobs,
+		Files:         files,
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	err = ioutil.WriteFile(filepath.Join(backupDir, backupManifestFilename),
+		b, 0600)
+	if err != nil {
+		return nil, err
+	}
+	success = true
+
+	log.Infof("Backup complete %v: %v trees, %v blobs, %v files (%v)",
+		name, len(trees), blobs, len(files), time.Since(now))
+
+	return &backend.BackupSummary{
+		Name:      name,
+		Path:      backupDir,
+		Timestamp: m.Timestamp,
+		Trees:     uint64(len(trees)),
+		Blobs:     blobs,
+		Files:     uint64(len(files)),
+	}, nil
+}
+
+// backupManifestLoad loads the manifest of the backup that is saved in the
+// provided directory.
+func backupManifestLoad(backupDir string) (*backupManifest, error) {
+	b, err := ioutil.ReadFile(filepath.Join(backupDir, backupManifestFilename))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("backup manifest not found; the " +
+				"backup is incomplete")
+		}
+		return nil, err
+	}
+	var m backupManifest
+	err = json.Unmarshal(b, &m)
+	if err != nil {
+		return nil, err
+	}
+	if m.Version != backupVersion {
+		return nil, fmt.Errorf("unsupported backup version %v", m.Version)
+	}
+	return &m, nil
+}
+
+// restoreBlobs saves the blobs of a backup to the key-value store.
+func restoreBlobs(kvstore store.BlobKV, fp string, backupKey *[32]byte) (uint64, error) {
+	f, err := os.Open(fp)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return 0, err
+	}
+	dec := json.NewDecoder(zr)
+
+	// Blobs are saved in batches. The encrypted and unencrypted blobs
+	// are saved separately.
+	var (
+		count     uint64
+		plain     = make(map[string][]byte, backupBatchSize)
+		encrypted = make(map[string][]byte, backupBatchSize)
+	)
+	save := func(blobs map[string][]byte, encrypt bool) error {
+		if len(blobs) == 0 {
+			return nil
+		}
+		err := kvstore.Put(blobs, encrypt)
+		if err != nil {
+			return fmt.Errorf("Put: %v", err)
+		}
+		count += uint64(len(blobs))
+		for k := range blobs {
+			delete(blobs, k)
+		}
+		return nil
+	}
+	for {
+		var bb backupBlob
+		err := dec.Decode(&bb)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return 0, fmt.Errorf("decode blob %v: %v", count, err)
+		}
+		if !strings.HasPrefix(bb.Key, keyPrefixEncrypted) {
+			plain[bb.Key] = bb.Blob
+			if len(plain) >= backupBatchSize {
+				if err := save(plain, false); err != nil {
+					return 0, err
+				}
+			}
+			continue
+		}
+		b, _, err := sbox.Decrypt(backupKey, bb.Blob)
+		if err != nil {
+			return 0, fmt.Errorf("decrypt %v: %v", bb.Key, err)
+		}
+		encrypted[bb.Key] = b
+		if len(encrypted) >= backupBatchSize {
+			if err := save(encrypted, true); err != nil {
+				return 0, err
+			}
+		}
+	}
+	if err := save(plain, false); err != nil {
+		return 0, err
+	}
+	if err := save(encrypted, true); err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+// Restore restores the backup that is saved in the provided directory to the
+// key-value store and the data directory of a new tstore instance. This must
+// be done prior to the tstore instance being created. The key-value store
+// must not contain any tstore data and the restored data directory files must
+// not exist yet. The tlog passphrase must be the passphrase of the tstore
+// instance that the backup was taken from.
+//
+// A trillian database is not restored. It must be restored separately using
+// the snapshot that was taken by the backup hook. The restored tstore should
+// be verified using RestoreVerify and a filesystem check once the tstore
+// instance has been created.
+func Restore(backupDir, appDir, dataDir string, anp *chaincfg.Params, tlogPass, dbType, dbHost, dbPass string) error {
+	m, err := backupManifestLoad(backupDir)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Restoring backup %v (%v)", m.Name,
+		time.Unix(m.Timestamp, 0).UTC())
+
+	// Setup the key-value store
+	err = os.MkdirAll(dataDir, 0700)
+	if err != nil {
+		return err
+	}
+	kvstore, err := newBlobKV(appDir, dataDir, anp, dbType, dbHost, dbPass)
+	if err != nil {
+		return err
+	}
+	defer kvstore.Close()
+
+	// Verify that the key-value store is empty
+	keys, err := kvstore.Keys()
+	if err != nil {
+		return fmt.Errorf("Keys: %v", err)
+	}
+	for _, v := range keys {
+		if !backupKeyExcluded(v) {
+			return fmt.Errorf("the key-value store is not empty")
+		}
+	}
+
+	// Restore the tlog key params and derive the backup key. The
+	// tlog key derivation fails if the tlog passphrase is not the
+	// passphrase of the tstore that the backup was taken from.
+	kv, err := kvstore.Get([]string{tlogKeyParamsKey})
+	if err != nil {
+		return fmt.Errorf("get tlog key params: %v", err)
+	}
+	b, ok := kv[tlogKeyParamsKey]
+	switch {
+	case !ok:
+		err = kvstore.Put(map[string][]byte{
+			tlogKeyParamsKey: m.TlogKeyParams,
+		}, false)
+		if err != nil {
+			return fmt.Errorf("put tlog key params: %v", err)
+		}
+	case !bytes.Equal(b, m.TlogKeyParams):
+		return fmt.Errorf("the key-value store contains a different tlog " +
+			"signing key")
+	}
+	tlogKey, err := deriveTlogKey(kvstore, tlogPass)
+	if err != nil {
+		return err
+	}
+	backupKey := backupKeyDerive(tlogKey)
+
+	// Restore the blobs
+	blobs, err := restoreBlobs(kvstore,
+		filepath.Join(backupDir, backupBlobsFilename), backupKey)
+	if err != nil {
+		return fmt.Errorf("restore blobs: %v", err)
+	}
+	if blobs != m.Blobs {
+		return fmt.Errorf("restored %v blobs; manifest contains %v",
+			blobs, m.Blobs)
+	}
+
+	// Restore the data directory files
+	for _, v := range m.Files {
+		err = copyFile(filepath.Join(backupDir, backupFilesDirname, v),
+			filepath.Join(dataDir, v))
+		if err != nil {
+			return fmt.Errorf("restore %v: %v", v, err)
+		}
+	}
+
+	log.Infof("Backup restored: %v blobs, %v files", blobs, len(m.Files))
+
+	return nil
+}
+
+// RestoreVerify verifies that the tlog trees match the state of the trees at
+// the time of the backup that is saved in the provided directory. The tstore
+// must contain the exact same trees, at the exact same heights, as the
+// backup.
+func (t *Tstore) RestoreVerify(backupDir string) error {
+	log.Tracef("RestoreVerify: %v", backupDir)
+
+	m, err := backupManifestLoad(backupDir)
+	if err != nil {
+		return err
+	}
+	if m.TlogType != t.tlogType {
+		return fmt.Errorf("backup tlog type is %v; tstore tlog type is %v",
+			m.TlogType, t.tlogType)
+	}
+	trees, err := t.backupTrees()
+	if err != nil {
+		return err
+	}
+	if len(trees) != len(m.Trees) {
+		return fmt.Errorf("tstore has %v trees; backup has %v trees",
+			len(trees), len(m.Trees))
+	}
+	want := make(map[int64]backupTree, len(m.Trees))
+	for _, v := range m.Trees {
+		want[v.TreeID] = v
+	}
+	for _, v := range trees {
+		w, ok := want[v.TreeID]
+		switch {
+		case !ok:
+			return fmt.Errorf("tree %v is not in the backup", v.TreeID)
+		case v.Size != w.Size:
+			return fmt.Errorf("tree %v size is %v; backup size is %v",
+				v.TreeID, v.Size, w.Size)
+		case !bytes.Equal(v.RootHash, w.RootHash):
+			return fmt.Errorf("tree %v root hash is %x; backup root hash "+
+				"is %x", v.TreeID, v.RootHash, w.RootHash)
+		}
+	}
+
+	log.Infof("Restore verified: %v trees match backup %v",
+		len(trees), m.Name)
+
+	return nil
+}
@@ -0,0 +1,183 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tstore
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	backend "github.com/decred/politeia/politeiad/backendv2"
+)
+
+// testRestore restores the provided backup to a new directory and returns the
+// directory.
+func testRestore(t *testing.T, backupDir, tlogPass string) (string, error) {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "tstore.restore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = Restore(backupDir, dir, filepath.Join(dir, "data"),
+		chaincfg.TestNet3Params(), tlogPass, DBTypeLevelDB, "", "")
+	return dir, err
+}
+
+func TestBackupRestore(t *testing.T) {
+	src, cleanup := newTestTstoreNative(t)
+	defer cleanup()
+	src.anchorVerifyPeriod = 10 * time.Millisecond
+
+	// Create an anchored public record, a public record that has not
+	// been anchored yet, and an unvetted record. The unvetted record
+	// blobs are encrypted.
+	token1, _, _ := newTestRecord(t, src)
+	_, err := src.AnchorDrop()
+	if err != nil {
+		t.Fatal(err)
+	}
+	waitAnchorDrop(t, src)
+	token2, _, _ := newTestRecord(t, src)
+	token3, err := src.RecordNew()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = src.RecordSave(token3, backend.RecordMetadata{
+		Token:     hex.EncodeToString(token3),
+		Version:   1,
+		Iteration: 1,
+		State:     backend.StateUnvetted,
+		Status:    backend.StatusUnreviewed,
+		Timestamp: time.Now().Unix(),
+	}, []backend.MetadataStream{}, []backend.File{
+		newTestFile("index.md", "unvetted"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tokens := [][]byte{token1, token2, token3}
+
+	// Backup the tstore
+	backupsDir, err := ioutil.TempDir("", "tstore.backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(backupsDir)
+	bs, err := src.Backup(backupsDir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bs.Trees != uint64(len(tokens)) || bs.Blobs == 0 || bs.Files == 0 {
+		t.Fatalf("got backup summary %+v", bs)
+	}
+
+	// Restore the backup into an empty directory
+	dir, err := testRestore(t, bs.Path, "testpassphrase")
+	defer os.RemoveAll(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dst := newTestTstoreAt(t, dir)
+	defer dst.Close()
+
+	// The restored trees must match the backup and the restored
+	// tstore must pass a filesystem check.
+	err = dst.RestoreVerify(bs.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := dst.Fsck(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int(r.Records) != len(tokens) || len(r.Issues) != 0 {
+		t.Fatalf("got %v records and issues %+v, want %v records and no "+
+			"issues", r.Records, r.Issues, len(tokens))
+	}
+
+	// The restored records, including the unvetted record that was
+	// encrypted, must match the original records.
+	for _, token := range tokens {
+		want, err := src.RecordLatest(token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := dst.RecordLatest(token)
+		if err != nil {
+			t.Fatalf("%x: %v", token, err)
+		}
+		if got.RecordMetadata != want.RecordMetadata {
+			t.Fatalf("%x: got record metadata %+v, want %+v",
+				token, got.RecordMetadata, want.RecordMetadata)
+		}
+		err = recordContentVerify(*got, *want)
+		if err != nil {
+			t.Fatalf("%x: %v", token, err)
+		}
+	}
+
+	// RestoreVerify fails once the restored trees diverge from the
+	// backup.
+	newTestRecord(t, dst)
+	err = dst.RestoreVerify(bs.Path)
+	if err == nil {
+		t.Fatalf("RestoreVerify passed for a tstore that does not match " +
+			"the backup")
+	}
+}
+
+func TestRestoreInvalid(t *testing.T) {
+	src, cleanup := newTestTstoreNative(t)
+	defer cleanup()
+
+	newTestRecord(t, src)
+	backupsDir, err := ioutil.TempDir("", "tstore.backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(backupsDir)
+	bs, err := src.Backup(backupsDir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A backup can't be restored using a different tlog passphrase
+	dir, err := testRestore(t, bs.Path, "wrongpassphrase")
+	os.RemoveAll(dir)
+	if err == nil {
+		t.Fatalf("backup was restored using the wrong tlog passphrase")
+	}
+
+	// A backup can't be restored into a key-value store that already
+	// contains tstore data.
+	dir, err = testRestore(t, bs.Path, "testpassphrase")
+	defer os.RemoveAll(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = Restore(bs.Path, dir, filepath.Join(dir, "data"),
+		chaincfg.TestNet3Params(), "testpassphrase", DBTypeLevelDB, "", "")
+	if err == nil {
+		t.Fatalf("backup was restored into a key-value store that is " +
+			"not empty")
+	}
+
+	// An incomplete backup, i.e. a backup without a manifest, can't be
+	// restored.
+	err = os.Remove(filepath.Join(bs.Path, backupManifestFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err = testRestore(t, bs.Path, "testpassphrase")
+	os.RemoveAll(dir)
+	if err == nil {
+		t.Fatalf("incomplete backup was restored")
+	}
+}
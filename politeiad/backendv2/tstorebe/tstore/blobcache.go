@@ -0,0 +1,30 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tstore
+
+import (
+	backend "github.com/decred/politeia/politeiad/backendv2"
+)
+
+// BlobCacheStats returns the hit and miss stats of the blob cache. Enabled
+// will be false if the blob cache is disabled.
+func (t *Tstore) BlobCacheStats() *backend.BlobCacheStats {
+	log.Tracef("BlobCacheStats")
+
+	if t.blobCache == nil {
+		return &backend.BlobCacheStats{}
+	}
+
+	s := t.blobCache.Stats()
+	return &backend.BlobCacheStats{
+		Enabled:   true,
+		Hits:      s.Hits,
+		Misses:    s.Misses,
+		Evictions: s.Evictions,
+		Entries:   s.Entries,
+		Size:      s.Size,
+		MaxSize:   s.MaxSize,
+	}
+}
@@ -0,0 +1,61 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tstore
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+)
+
+func TestBlobCacheStats(t *testing.T) {
+	// The stats of a disabled cache are zero
+	ts, cleanup := newTestTstoreNative(t)
+	s := ts.BlobCacheStats()
+	if s.Enabled || s.Hits != 0 || s.Misses != 0 {
+		t.Fatalf("got %+v, want disabled cache", s)
+	}
+	cleanup()
+
+	// Setup a tstore with the blob cache enabled
+	dir, err := ioutil.TempDir("", "tstore.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ts, err = New(dir, filepath.Join(dir, "data"), chaincfg.TestNet3Params(),
+		TlogTypeNative, "", "testpassphrase", DBTypeLevelDB, "", "", "", "",
+		AnchorTypeLocal, "@yearly", 1<<20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ts.Close()
+
+	// The first read of a vetted record misses the cache. The second
+	// read is served from the cache.
+	token, _, _ := newTestRecord(t, ts)
+	_, err = ts.RecordLatest(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := ts.BlobCacheStats()
+	if !first.Enabled || first.Misses == 0 || first.Entries == 0 ||
+		first.MaxSize != 1<<20 {
+		t.Fatalf("got %+v after first read, want misses and entries", first)
+	}
+	_, err = ts.RecordLatest(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second := ts.BlobCacheStats()
+	if second.Hits <= first.Hits || second.Misses != first.Misses {
+		t.Fatalf("got %+v after second read, want more hits than %v and "+
+			"%v misses", second, first.Hits, first.Misses)
+	}
+}
@@ -27,6 +27,25 @@ import (
 func (t *Tstore) BlobSave(token []byte, be store.BlobEntry) error {
 	log.Tracef("BlobSave: %x", token)
 
+	return t.BlobsSave(token, []store.BlobEntry{be})
+}
+
+// BlobsSave saves a batch of BlobEntry to the tstore instance. The blobs are
+// saved to the key-value store in a single call and their log leaves are
+// appended onto the tlog tree in a single call. This allows a large number of
+// blobs to be saved without retrieving the tree leaves for every blob. The
+// same rules that apply to BlobSave apply to every blob in the batch.
+//
+// A plugins ErrDuplicateBlob error is returned if any of the blobs already
+// exist in the tree. Blobs that were not duplicates will have still been
+// saved in this case.
+func (t *Tstore) BlobsSave(token []byte, entries []store.BlobEntry) error {
+	log.Tracef("BlobsSave: %x %v", token, len(entries))
+
+	if len(entries) == 0 {
+		return nil
+	}
+
 	// Verify tree is not frozen
 	treeID := treeIDFromToken(token)
 	leaves, err := t.leavesAll(treeID)
@@ -42,17 +61,6 @@ func (t *Tstore) BlobSave(token []byte, be store.BlobEntry) error {
 		return backend.ErrRecordLocked
 	}
 
-	// Parse the data descriptor
-	b, err := base64.StdEncoding.DecodeString(be.DataHint)
-	if err != nil {
-		return err
-	}
-	var dd store.DataDescriptor
-	err = json.Unmarshal(b, &dd)
-	if err != nil {
-		return err
-	}
-
 	// Only vetted data should be saved plain text
 	var encrypt bool
 	switch idx.State {
@@ -66,52 +74,74 @@ func (t *Tstore) BlobSave(token []byte, be store.BlobEntry) error {
 		panic(fmt.Sprintf("invalid record state %v %v", treeID, idx.State))
 	}
 
-	// Prepare blob and digest
-	digest, err := hex.DecodeString(be.Digest)
-	if err != nil {
-		return err
-	}
-	blob, err := store.Blobify(be)
-	if err != nil {
-		return err
-	}
-	key := storeKeyNew(encrypt)
-	kv := map[string][]byte{key: blob}
+	// Prepare blobs and log leaves
+	var (
+		kv = make(map[string][]byte, len(entries))
+		ll = make([]*trillian.LogLeaf, 0, len(entries))
+	)
+	for _, be := range entries {
+		// Parse the data descriptor
+		b, err := base64.StdEncoding.DecodeString(be.DataHint)
+		if err != nil {
+			return err
+		}
+		var dd store.DataDescriptor
+		err = json.Unmarshal(b, &dd)
+		if err != nil {
+			return err
+		}
 
-	log.Debugf("Saving plugin data blob %v", dd.Descriptor)
+		// Prepare blob and digest
+		digest, err := hex.DecodeString(be.Digest)
+		if err != nil {
+			return err
+		}
+		blob, err := store.Blobify(be)
+		if err != nil {
+			return err
+		}
+		key := storeKeyNew(encrypt)
+		kv[key] = blob
 
-	// Save blob to store
-	err = t.store.Put(kv, encrypt)
-	if err != nil {
-		return fmt.Errorf("store Put: %v", err)
+		log.Debugf("Saving plugin data blob %v", dd.Descriptor)
+
+		// Prepare log leaf
+		extraData, err := extraDataEncode(key, dd.Descriptor, idx.State)
+		if err != nil {
+			return err
+		}
+		ll = append(ll, newLogLeaf(digest, extraData))
 	}
 
-	// Prepare log leaf
-	extraData, err := extraDataEncode(key, dd.Descriptor, idx.State)
+	// Save blobs to store
+	err = t.store.Put(kv, encrypt)
 	if err != nil {
-		return err
-	}
-	leaves = []*trillian.LogLeaf{
-		newLogLeaf(digest, extraData),
+		return fmt.Errorf("store Put: %v", err)
 	}
 
-	// Append log leaf to trillian tree
-	queued, _, err := t.tlog.LeavesAppend(treeID, leaves)
+	// Append log leaves to trillian tree
+	queued, _, err := t.tlog.LeavesAppend(treeID, ll)
 	if err != nil {
 		return fmt.Errorf("LeavesAppend: %v", err)
 	}
-	if len(queued) != 1 {
-		return fmt.Errorf("wrong queued leaves count: got %v, want 1",
-			len(queued))
+	if len(queued) != len(ll) {
+		return fmt.Errorf("wrong queued leaves count: got %v, want %v",
+			len(queued), len(ll))
+	}
+	var duplicate bool
+	for _, v := range queued {
+		c := codes.Code(v.QueuedLeaf.GetStatus().GetCode())
+		switch c {
+		case codes.OK:
+			// This is ok; continue
+		case codes.AlreadyExists:
+			duplicate = true
+		default:
+			return fmt.Errorf("queued leaf error: %v", c)
+		}
 	}
-	c := codes.Code(queued[0].QueuedLeaf.GetStatus().GetCode())
-	switch c {
-	case codes.OK:
-		// This is ok; continue
-	case codes.AlreadyExists:
+	if duplicate {
 		return plugins.ErrDuplicateBlob
-	default:
-		return fmt.Errorf("queued leaf error: %v", c)
 	}
 
 	return nil
@@ -0,0 +1,748 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tstore
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+	"github.com/decred/politeia/util"
+	"github.com/google/trillian"
+	"google.golang.org/grpc/codes"
+)
+
+const (
+	// bundleBatchSize is the maximum number of blobs that are saved to
+	// the tstore in a single batch during a record import.
+	bundleBatchSize = 500
+)
+
+// RecordExport returns a bundle that contains the full contents of a record.
+// The bundle includes every version of the record, the timestamps of every
+// version, and every blob that has been appended to the record's tlog tree,
+// including the plugin data blobs and the anchors. Unvetted blobs are
+// included as plain text.
+func (t *Tstore) RecordExport(token []byte) (*backend.RecordBundle, error) {
+	log.Tracef("RecordExport: %x", token)
+
+	// Read methods are allowed to use short tokens. Lookup the full
+	// length token.
+	var err error
+	token, err = t.fullLengthToken(token)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the latest record index
+	treeID := treeIDFromToken(token)
+	leaves, err := t.leavesAll(treeID)
+	if err != nil {
+		return nil, err
+	}
+	idx, err := t.recordIndexLatest(leaves)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get every version of the record and its timestamps
+	var (
+		records    = make([]backend.Record, 0, idx.Version)
+		timestamps = make(map[uint32]backend.RecordTimestamps, idx.Version)
+	)
+	for v := uint32(1); v <= idx.Version; v++ {
+		r, err := t.record(treeID, v, []string{}, false)
+		if err != nil {
+			return nil, fmt.Errorf("record %v: %v", v, err)
+		}
+		ts, err := t.RecordTimestamps(token, v)
+		if err != nil {
+			return nil, fmt.Errorf("RecordTimestamps %v: %v", v, err)
+		}
+		records = append(records, *r)
+		timestamps[v] = *ts
+	}
+
+	// Get the blobs
+	blobs, err := t.bundleBlobs(leaves)
+	if err != nil {
+		return nil, err
+	}
+
+	return &backend.RecordBundle{
+		Version:    backend.RecordBundleVersion,
+		Token:      hex.EncodeToString(token),
+		Timestamp:  time.Now().Unix(),
+		Records:    records,
+		Timestamps: timestamps,
+		Blobs:      blobs,
+	}, nil
+}
+
+// bundleBlobs returns the bundle blobs for the provided leaves. The returned
+// blobs share the same ordering as the leaves.
+func (t *Tstore) bundleBlobs(leaves []*trillian.LogLeaf) ([]backend.BundleBlob, error) {
+	// Compile the kv store keys. Unvetted blobs may exist as both an
+	// encrypted blob and a plain text blob.
+	var (
+		eds  = make([]*extraData, 0, len(leaves))
+		keys = make([]string, 0, len(leaves))
+	)
+	for _, v := range leaves {
+		ed, err := extraDataDecode(v.ExtraData)
+		if err != nil {
+			return nil, err
+		}
+		eds = append(eds, ed)
+		keys = append(keys, ed.storeKey())
+		if ed.storeKey() != ed.storeKeyNoPrefix() {
+			keys = append(keys, ed.storeKeyNoPrefix())
+		}
+	}
+
+	// Get the blobs from the store
+	kv, err := t.store.Get(keys)
+	if err != nil {
+		return nil, fmt.Errorf("store Get: %v", err)
+	}
+
+	// Prepare the bundle blobs
+	blobs := make([]backend.BundleBlob, 0, len(leaves))
+	for i, v := range leaves {
+		ed := eds[i]
+		bb := backend.BundleBlob{
+			LeafIndex:      v.LeafIndex,
+			Digest:         hex.EncodeToString(v.LeafValue),
+			DataDescriptor: ed.Desc,
+			State:          ed.State,
+		}
+		b, ok := kv[ed.storeKey()]
+		if !ok {
+			// The blob has been deleted
+			bb.Deleted = true
+		}
+		if ed.storeKey() != ed.storeKeyNoPrefix() {
+			pb, ok := kv[ed.storeKeyNoPrefix()]
+			if ok {
+				bb.PlainText = true
+				if b == nil {
+					b = pb
+				}
+			}
+		}
+		if b != nil {
+			be, err := store.Deblob(b)
+			if err != nil {
+				return nil, err
+			}
+			if be.Digest != bb.Digest {
+				return nil, fmt.Errorf("blob digest mismatch for leaf %v: "+
+					"got %v, want %v", v.LeafIndex, be.Digest, bb.Digest)
+			}
+			bb.DataHint = be.DataHint
+			bb.Data = be.Data
+		}
+		blobs = append(blobs, bb)
+	}
+
+	return blobs, nil
+}
+
+// bundleLeaf is a decoded and verified bundle blob.
+type bundleLeaf struct {
+	blob backend.BundleBlob
+	be   *store.BlobEntry // Nil if the blob data is not included
+	data []byte           // Decoded blob entry data
+}
+
+// bundleError returns a backend ContentError for an invalid record bundle.
+func bundleError(format string, args ...interface{}) error {
+	return backend.ContentError{
+		ErrorCode:    backend.ContentErrorBundleInvalid,
+		ErrorContext: fmt.Sprintf(format, args...),
+	}
+}
+
+// bundleDecode decodes the blobs of a record bundle and verifies that they are
+// coherent. The data of every blob must match the digest of its leaf and the
+// leaves must be complete and in order.
+func bundleDecode(b backend.RecordBundle) ([]bundleLeaf, error) {
+	if b.Version != backend.RecordBundleVersion {
+		return nil, bundleError("unsupported bundle version %v", b.Version)
+	}
+	if len(b.Blobs) == 0 {
+		return nil, bundleError("no blobs")
+	}
+
+	var (
+		leaves  = make([]bundleLeaf, 0, len(b.Blobs))
+		digests = make(map[string]struct{}, len(b.Blobs))
+	)
+	for i, v := range b.Blobs {
+		if v.LeafIndex != int64(i) {
+			return nil, bundleError("blob %v: invalid leaf index %v",
+				i, v.LeafIndex)
+		}
+		if _, ok := digests[v.Digest]; ok {
+			return nil, bundleError("blob %v: duplicate digest %v",
+				i, v.Digest)
+		}
+		digests[v.Digest] = struct{}{}
+
+		l := bundleLeaf{
+			blob: v,
+		}
+		if v.Data == "" {
+			if !v.Deleted || v.PlainText {
+				return nil, bundleError("blob %v: data missing", i)
+			}
+			leaves = append(leaves, l)
+			continue
+		}
+
+		// Verify the blob data
+		be := store.BlobEntry{
+			Digest:   v.Digest,
+			DataHint: v.DataHint,
+			Data:     v.Data,
+		}
+		data, err := blobEntryData(be)
+		if err != nil {
+			return nil, bundleError("blob %v: %v", i, err)
+		}
+		hint, err := base64.StdEncoding.DecodeString(v.DataHint)
+		if err != nil {
+			return nil, bundleError("blob %v: decode data hint: %v", i, err)
+		}
+		var dd store.DataDescriptor
+		err = json.Unmarshal(hint, &dd)
+		if err != nil {
+			return nil, bundleError("blob %v: unmarshal data hint: %v",
+				i, err)
+		}
+		if dd.Descriptor != v.DataDescriptor {
+			return nil, bundleError("blob %v: data descriptor mismatch: "+
+				"got %v, want %v", i, dd.Descriptor, v.DataDescriptor)
+		}
+		l.be = &be
+		l.data = data
+		leaves = append(leaves, l)
+	}
+
+	return leaves, nil
+}
+
+// bundleRecordIndexes returns the record indexes of a record bundle sorted by
+// iteration. Only the record indexes of the most recent record state are
+// returned, the same way that they are returned for a tstore record.
+func bundleRecordIndexes(leaves []bundleLeaf) ([]recordIndex, error) {
+	var (
+		unvetted = make([]recordIndex, 0, 64)
+		vetted   = make([]recordIndex, 0, 64)
+	)
+	for _, v := range leaves {
+		if v.blob.DataDescriptor != dataDescriptorRecordIndex {
+			continue
+		}
+		if v.be == nil {
+			return nil, bundleError("record index %v missing",
+				v.blob.LeafIndex)
+		}
+		ri, err := convertRecordIndexFromBlobEntry(*v.be)
+		if err != nil {
+			return nil, bundleError("record index %v: %v",
+				v.blob.LeafIndex, err)
+		}
+		switch ri.State {
+		case backend.StateUnvetted:
+			unvetted = append(unvetted, *ri)
+		case backend.StateVetted:
+			vetted = append(vetted, *ri)
+		default:
+			return nil, bundleError("record index %v: invalid state %v",
+				v.blob.LeafIndex, ri.State)
+		}
+	}
+	indexes := unvetted
+	if len(vetted) > 0 {
+		indexes = vetted
+	}
+	if len(indexes) == 0 {
+		return nil, bundleError("no record indexes")
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		return indexes[i].Iteration < indexes[j].Iteration
+	})
+	return indexes, nil
+}
+
+// bundleRecord returns the specified version of the record that is contained
+// in a record bundle. The blobs of deleted files are not included, the same
+// way they are not included for a tstore record.
+func bundleRecord(merkles map[string]bundleLeaf, indexes []recordIndex, version uint32) (*backend.Record, error) {
+	idx, err := parseRecordIndex(indexes, version)
+	if err != nil {
+		return nil, err
+	}
+
+	// Compile the record content leaves
+	content := make([][]byte, 0, len(idx.Metadata)+len(idx.Files)+1)
+	content = append(content, idx.RecordMetadata)
+	for _, streams := range idx.Metadata {
+		for _, v := range streams {
+			content = append(content, v)
+		}
+	}
+	for _, v := range idx.Files {
+		content = append(content, v)
+	}
+
+	// Decode the record content
+	var (
+		rm       *backend.RecordMetadata
+		metadata = make([]backend.MetadataStream, 0, len(idx.Metadata))
+		files    = make([]backend.File, 0, len(idx.Files))
+	)
+	for _, m := range content {
+		l, ok := merkles[hex.EncodeToString(m)]
+		if !ok {
+			return nil, fmt.Errorf("leaf %x not found", m)
+		}
+		if l.data == nil {
+			// Blob has been deleted
+			continue
+		}
+		switch l.blob.DataDescriptor {
+		case dataDescriptorRecordMetadata:
+			var r backend.RecordMetadata
+			err := json.Unmarshal(l.data, &r)
+			if err != nil {
+				return nil, fmt.Errorf("unmarshal RecordMetadata: %v", err)
+			}
+			rm = &r
+		case dataDescriptorMetadataStream:
+			var ms backend.MetadataStream
+			err := json.Unmarshal(l.data, &ms)
+			if err != nil {
+				return nil, fmt.Errorf("unmarshal MetadataStream: %v", err)
+			}
+			metadata = append(metadata, ms)
+		case dataDescriptorFile:
+			var f backend.File
+			err := json.Unmarshal(l.data, &f)
+			if err != nil {
+				return nil, fmt.Errorf("unmarshal File: %v", err)
+			}
+			files = append(files, f)
+		default:
+			return nil, fmt.Errorf("invalid descriptor %v",
+				l.blob.DataDescriptor)
+		}
+	}
+	if rm == nil {
+		return nil, fmt.Errorf("record metadata not found")
+	}
+
+	return &backend.Record{
+		RecordMetadata: *rm,
+		Metadata:       metadata,
+		Files:          files,
+	}, nil
+}
+
+// recordContentVerify verifies that the content of a record matches the
+// content of the wanted record. The record token is not compared since an
+// imported record is assigned a new token. The record iteration is not
+// compared since it is not part of the politeiad API record and is not
+// included in bundles that are provided by clients. The iteration is verified
+// using the record indexes.
+func recordContentVerify(r, want backend.Record) error {
+	rm, wm := r.RecordMetadata, want.RecordMetadata
+	rm.Token, wm.Token = "", ""
+	rm.Iteration, wm.Iteration = 0, 0
+	if rm != wm {
+		return fmt.Errorf("record metadata mismatch: got %+v, want %+v",
+			rm, wm)
+	}
+
+	// Verify metadata streams
+	md := make(map[string]string, len(r.Metadata)) // [pluginID-streamID]payload
+	for _, v := range r.Metadata {
+		md[fmt.Sprintf("%v-%v", v.PluginID, v.StreamID)] = v.Payload
+	}
+	if len(md) != len(want.Metadata) {
+		return fmt.Errorf("metadata stream count mismatch: got %v, want %v",
+			len(md), len(want.Metadata))
+	}
+	for _, v := range want.Metadata {
+		p, ok := md[fmt.Sprintf("%v-%v", v.PluginID, v.StreamID)]
+		if !ok || p != v.Payload {
+			return fmt.Errorf("metadata stream %v %v mismatch",
+				v.PluginID, v.StreamID)
+		}
+	}
+
+	// Verify files
+	files := make(map[string]backend.File, len(r.Files)) // [name]File
+	for _, v := range r.Files {
+		files[v.Name] = v
+	}
+	if len(files) != len(want.Files) {
+		return fmt.Errorf("file count mismatch: got %v, want %v",
+			len(files), len(want.Files))
+	}
+	for _, v := range want.Files {
+		f, ok := files[v.Name]
+		if !ok || f.Digest != v.Digest || f.MIME != v.MIME {
+			return fmt.Errorf("file %v mismatch", v.Name)
+		}
+		b, err := base64.StdEncoding.DecodeString(v.Payload)
+		if err != nil {
+			return fmt.Errorf("file %v: decode payload: %v", v.Name, err)
+		}
+		if hex.EncodeToString(util.Digest(b)) != v.Digest {
+			return fmt.Errorf("file %v: payload does not match digest",
+				v.Name)
+		}
+	}
+
+	return nil
+}
+
+// bundleVerify verifies that the records and the timestamps of a record bundle
+// are coherent with the bundle blobs. The timestamps were created by the
+// tstore instance that the record was exported from. Timestamps that have
+// been anchored must include valid inclusion proofs.
+func bundleVerify(b backend.RecordBundle, leaves []bundleLeaf) error {
+	merkles := make(map[string]bundleLeaf, len(leaves))
+	for _, v := range leaves {
+		d, err := hex.DecodeString(v.blob.Digest)
+		if err != nil {
+			return bundleError("blob %v: invalid digest", v.blob.LeafIndex)
+		}
+		merkles[hex.EncodeToString(merkleLeafHash(d))] = v
+	}
+	indexes, err := bundleRecordIndexes(leaves)
+	if err != nil {
+		return err
+	}
+
+	// Verify that every record index references leaves that exist
+	for _, idx := range indexes {
+		refs := make([][]byte, 0, len(idx.Metadata)+len(idx.Files)+1)
+		refs = append(refs, idx.RecordMetadata)
+		for _, streams := range idx.Metadata {
+			for _, v := range streams {
+				refs = append(refs, v)
+			}
+		}
+		for _, v := range idx.Files {
+			refs = append(refs, v)
+		}
+		for _, v := range refs {
+			if _, ok := merkles[hex.EncodeToString(v)]; !ok {
+				return bundleError("record index iteration %v references "+
+					"leaf %x that does not exist", idx.Iteration, v)
+			}
+		}
+	}
+
+	// Verify the record metadata token of every record iteration
+	for _, v := range leaves {
+		if v.blob.DataDescriptor != dataDescriptorRecordMetadata {
+			continue
+		}
+		if v.data == nil {
+			return bundleError("record metadata %v missing",
+				v.blob.LeafIndex)
+		}
+		var rm backend.RecordMetadata
+		err := json.Unmarshal(v.data, &rm)
+		if err != nil {
+			return bundleError("record metadata %v: %v",
+				v.blob.LeafIndex, err)
+		}
+		if rm.Token != b.Token {
+			return bundleError("record metadata %v: token mismatch: "+
+				"got %v, want %v", v.blob.LeafIndex, rm.Token, b.Token)
+		}
+	}
+
+	// Verify the records
+	latest := indexes[len(indexes)-1].Version
+	if uint32(len(b.Records)) != latest {
+		return bundleError("record count mismatch: got %v, want %v",
+			len(b.Records), latest)
+	}
+	for i, v := range b.Records {
+		version := uint32(i + 1)
+		r, err := bundleRecord(merkles, indexes, version)
+		if err != nil {
+			return bundleError("record version %v: %v", version, err)
+		}
+		err = recordContentVerify(*r, v)
+		if err != nil {
+			return bundleError("record version %v: %v", version, err)
+		}
+		if v.RecordMetadata.Token != b.Token {
+			return bundleError("record version %v: token mismatch",
+				version)
+		}
+	}
+
+	// Verify the timestamps
+	for version, rt := range b.Timestamps {
+		ts := make([]backend.Timestamp, 0, len(rt.Files)+8)
+		ts = append(ts, rt.RecordMetadata)
+		for _, streams := range rt.Metadata {
+			for _, v := range streams {
+				ts = append(ts, v)
+			}
+		}
+		for _, v := range rt.Files {
+			ts = append(ts, v)
+		}
+		for _, v := range ts {
+			d, err := hex.DecodeString(v.Digest)
+			if err != nil {
+				return bundleError("version %v timestamp: invalid digest",
+					version)
+			}
+			if _, ok := merkles[hex.EncodeToString(merkleLeafHash(d))]; !ok {
+				return bundleError("version %v timestamp: digest %v not "+
+					"found", version, v.Digest)
+			}
+			if v.Data != "" &&
+				hex.EncodeToString(util.Digest([]byte(v.Data))) != v.Digest {
+				return bundleError("version %v timestamp: data does not "+
+					"match digest %v", version, v.Digest)
+			}
+			err = backend.VerifyTimestamp(v)
+			switch err {
+			case nil, backend.ErrNotTimestamped:
+				// This is ok; continue
+			default:
+				return bundleError("version %v timestamp %v: %v",
+					version, v.Digest, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// RecordImport restores a record bundle as a new record and returns the token
+// of the new record.
+//
+// The bundle blobs are appended to a new tlog tree in the same order that they
+// were appended to the tlog tree of the exported record. The record token is
+// derived from the tlog tree ID, so the record metadata and the record index
+// blobs are updated to reference the new token. All other blobs are restored
+// without modification, including the plugin data blobs. The plugin data may
+// still reference the token of the exported record since the client
+// signatures were created using it. Deleted blobs are restored as leaves
+// without a blob. The anchors of the exported record are not restored. The
+// new tree is anchored by this tstore instance.
+func (t *Tstore) RecordImport(b backend.RecordBundle) ([]byte, error) {
+	log.Tracef("RecordImport: %v", b.Token)
+
+	// Decode and verify the bundle
+	leaves, err := bundleDecode(b)
+	if err != nil {
+		return nil, err
+	}
+	err = bundleVerify(b, leaves)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create a new tree
+	token, err := t.RecordNew()
+	if err != nil {
+		return nil, err
+	}
+	treeID := treeIDFromToken(token)
+
+	log.Infof("Importing record %v as %x", b.Token, token)
+
+	var (
+		// rms contains the merkle leaf hashes of the updated record
+		// metadata blobs.
+		rms = make(map[string][]byte, 64) // [oldMerkle]newMerkle
+
+		// The following are the blobs and the leaves of the current
+		// batch. Record indexes are always appended in a batch of
+		// their own so that the record content they reference has
+		// been appended first.
+		encrypted = make(map[string][]byte, bundleBatchSize)
+		plain     = make(map[string][]byte, bundleBatchSize)
+		batch     = make([]*trillian.LogLeaf, 0, bundleBatchSize)
+
+		appended int
+	)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if len(encrypted) > 0 {
+			err := t.store.Put(encrypted, true)
+			if err != nil {
+				return fmt.Errorf("store Put: %v", err)
+			}
+		}
+		if len(plain) > 0 {
+			err := t.store.Put(plain, false)
+			if err != nil {
+				return fmt.Errorf("store Put: %v", err)
+			}
+		}
+		queued, _, err := t.tlog.LeavesAppend(treeID, batch)
+		if err != nil {
+			return fmt.Errorf("LeavesAppend: %v", err)
+		}
+		if len(queued) != len(batch) {
+			return fmt.Errorf("wrong queued leaves count: got %v, want %v",
+				len(queued), len(batch))
+		}
+		for _, v := range queued {
+			c := codes.Code(v.QueuedLeaf.GetStatus().GetCode())
+			if c != codes.OK {
+				return fmt.Errorf("queued leaf error: %v", c)
+			}
+		}
+		appended += len(batch)
+		encrypted = make(map[string][]byte, bundleBatchSize)
+		plain = make(map[string][]byte, bundleBatchSize)
+		batch = make([]*trillian.LogLeaf, 0, bundleBatchSize)
+		return nil
+	}
+
+	for _, v := range leaves {
+		bb := v.blob
+		be := v.be
+		switch bb.DataDescriptor {
+		case dataDescriptorAnchor:
+			// Anchors are specific to the exported tree
+			continue
+
+		case dataDescriptorRecordMetadata:
+			// Update the record token
+			var rm backend.RecordMetadata
+			err := json.Unmarshal(v.data, &rm)
+			if err != nil {
+				return nil, err
+			}
+			rm.Token = hex.EncodeToString(token)
+			be, err = convertBlobEntryFromRecordMetadata(rm)
+			if err != nil {
+				return nil, err
+			}
+			prev, err := merkleLeafHashForBlobEntry(*v.be)
+			if err != nil {
+				return nil, err
+			}
+			m, err := merkleLeafHashForBlobEntry(*be)
+			if err != nil {
+				return nil, err
+			}
+			rms[hex.EncodeToString(prev)] = m
+
+		case dataDescriptorRecordIndex:
+			// Update the record metadata reference
+			ri, err := convertRecordIndexFromBlobEntry(*v.be)
+			if err != nil {
+				return nil, err
+			}
+			m, ok := rms[hex.EncodeToString(ri.RecordMetadata)]
+			if !ok {
+				return nil, fmt.Errorf("record metadata not found for "+
+					"record index %v", bb.LeafIndex)
+			}
+			ri.RecordMetadata = m
+			be, err = convertBlobEntryFromRecordIndex(*ri)
+			if err != nil {
+				return nil, err
+			}
+
+			// The record content must be appended prior to the
+			// record index.
+			err = flush()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		// Prepare the blobs and the leaf
+		encrypt := bb.State == backend.StateUnvetted
+		key := storeKeyNew(encrypt)
+		if be != nil {
+			blob, err := store.Blobify(*be)
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case bb.Deleted:
+				// Only the plain text copy of the blob exists
+			case encrypt:
+				encrypted[key] = blob
+			default:
+				plain[key] = blob
+			}
+			if encrypt && bb.PlainText {
+				plain[storeKeyCleaned(key)] = blob
+			}
+		}
+		digest, err := hex.DecodeString(bb.Digest)
+		if err != nil {
+			return nil, err
+		}
+		if be != nil {
+			digest, err = hex.DecodeString(be.Digest)
+			if err != nil {
+				return nil, err
+			}
+		}
+		extraData, err := extraDataEncode(key, bb.DataDescriptor, bb.State)
+		if err != nil {
+			return nil, err
+		}
+		batch = append(batch, newLogLeaf(digest, extraData))
+
+		if bb.DataDescriptor == dataDescriptorRecordIndex ||
+			len(batch) >= bundleBatchSize {
+			err = flush()
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	err = flush()
+	if err != nil {
+		return nil, err
+	}
+
+	log.Debugf("Imported %v leaves for %x", appended, token)
+
+	// Verify the imported record against the bundle records
+	for _, v := range b.Records {
+		version := v.RecordMetadata.Version
+		r, err := t.record(treeID, version, []string{}, false)
+		if err != nil {
+			return nil, fmt.Errorf("record %v: %v", version, err)
+		}
+		err = recordContentVerify(*r, v)
+		if err != nil {
+			return nil, fmt.Errorf("imported record %x version %v: %v",
+				token, version, err)
+		}
+	}
+
+	return token, nil
+}
@@ -0,0 +1,208 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tstore
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/util"
+)
+
+// newTestBundle creates a public record with two versions and returns the
+// exported record bundle.
+func newTestBundle(t *testing.T, ts *Tstore) *backend.RecordBundle {
+	t.Helper()
+
+	token, rm, files := newTestRecord(t, ts)
+	rm.Version++
+	rm.Iteration++
+	files = append(files, newTestFile("edit.txt", "edit"))
+	err := ts.RecordSave(token, rm, []backend.MetadataStream{}, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := ts.RecordExport(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestRecordExportImport(t *testing.T) {
+	src, cleanup := newTestTstoreNative(t)
+	defer cleanup()
+	dst, cleanupDst := newTestTstoreNative(t)
+	defer cleanupDst()
+
+	b := newTestBundle(t, src)
+	if len(b.Records) != 2 {
+		t.Fatalf("got %v records, want 2", len(b.Records))
+	}
+
+	// Import the bundle into a different tstore and verify that every
+	// version of the imported record matches the exported record.
+	token, err := dst.RecordImport(*b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(token) == b.Token {
+		t.Fatalf("imported record was not assigned a new token")
+	}
+	for _, v := range b.Records {
+		r, err := dst.Record(token, v.RecordMetadata.Version)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.RecordMetadata.Token != hex.EncodeToString(token) {
+			t.Fatalf("version %v: got token %v, want %x",
+				v.RecordMetadata.Version, r.RecordMetadata.Token, token)
+		}
+		err = recordContentVerify(*r, v)
+		if err != nil {
+			t.Fatalf("version %v: %v", v.RecordMetadata.Version, err)
+		}
+	}
+
+	// The imported record must be exportable and must contain the
+	// same blobs, minus the anchors of the exported tree.
+	e, err := dst.RecordExport(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var want int
+	for _, v := range b.Blobs {
+		if v.DataDescriptor != dataDescriptorAnchor {
+			want++
+		}
+	}
+	if len(e.Blobs) != want {
+		t.Fatalf("got %v blobs, want %v", len(e.Blobs), want)
+	}
+}
+
+func TestRecordImportInvalid(t *testing.T) {
+	src, cleanup := newTestTstoreNative(t)
+	defer cleanup()
+	dst, cleanupDst := newTestTstoreNative(t)
+	defer cleanupDst()
+
+	// fileBlob returns the index of the first file blob of the bundle
+	fileBlob := func(b *backend.RecordBundle) int {
+		for i, v := range b.Blobs {
+			if v.DataDescriptor == dataDescriptorFile {
+				return i
+			}
+		}
+		t.Fatalf("no file blobs found")
+		return 0
+	}
+
+	var tests = []struct {
+		name   string
+		tamper func(b *backend.RecordBundle)
+	}{
+		{
+			"unsupported version",
+			func(b *backend.RecordBundle) {
+				b.Version++
+			},
+		},
+		{
+			"no blobs",
+			func(b *backend.RecordBundle) {
+				b.Blobs = nil
+			},
+		},
+		{
+			"digest mismatch",
+			func(b *backend.RecordBundle) {
+				i := fileBlob(b)
+				d := util.Digest([]byte("tampered"))
+				b.Blobs[i].Digest = hex.EncodeToString(d)
+			},
+		},
+		{
+			"data mismatch",
+			func(b *backend.RecordBundle) {
+				i := fileBlob(b)
+				d := base64.StdEncoding.EncodeToString([]byte("tampered"))
+				b.Blobs[i].Data = d
+			},
+		},
+		{
+			"data missing",
+			func(b *backend.RecordBundle) {
+				b.Blobs[fileBlob(b)].Data = ""
+			},
+		},
+		{
+			"leaf index gap",
+			func(b *backend.RecordBundle) {
+				b.Blobs = append(b.Blobs[:1], b.Blobs[2:]...)
+			},
+		},
+		{
+			"duplicate digest",
+			func(b *backend.RecordBundle) {
+				b.Blobs[1].Digest = b.Blobs[0].Digest
+			},
+		},
+		{
+			"data descriptor mismatch",
+			func(b *backend.RecordBundle) {
+				b.Blobs[fileBlob(b)].DataDescriptor = dataDescriptorRecordMetadata
+			},
+		},
+		{
+			"record count mismatch",
+			func(b *backend.RecordBundle) {
+				b.Records = b.Records[:1]
+			},
+		},
+		{
+			"record content mismatch",
+			func(b *backend.RecordBundle) {
+				b.Records[0].Files[0] = newTestFile("index.md", "tampered")
+			},
+		},
+		{
+			"token mismatch",
+			func(b *backend.RecordBundle) {
+				b.Token = hex.EncodeToString(util.Digest([]byte("token"))[:8])
+			},
+		},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			b := newTestBundle(t, src)
+			v.tamper(b)
+
+			trees, err := dst.tlog.TreesAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, err = dst.RecordImport(*b)
+			var ce backend.ContentError
+			if !errors.As(err, &ce) ||
+				ce.ErrorCode != backend.ContentErrorBundleInvalid {
+				t.Fatalf("got error %v, want ContentErrorBundleInvalid", err)
+			}
+
+			// The bundle must be rejected before a tree is created
+			after, err := dst.tlog.TreesAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(after) != len(trees) {
+				t.Fatalf("got %v trees, want %v", len(after), len(trees))
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tstore
+
+import (
+	"fmt"
+	"time"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+)
+
+const (
+	// cacheRebuildLogInterval is the number of records that a plugin
+	// processes in between progress log messages during a cache
+	// rebuild.
+	cacheRebuildLogInterval = 500
+)
+
+// CacheRebuild starts a rebuild of the caches of the provided plugins. The
+// caches of all registered plugins are rebuilt if no plugin IDs are provided.
+// The rebuild is run in the background. Tstore writes are blocked until the
+// rebuild has finished. The initial status of the rebuild is returned. The
+// CacheRebuildStatus method can be used to track its progress.
+func (t *Tstore) CacheRebuild(pluginIDs []string) (*backend.CacheRebuildStatus, error) {
+	log.Tracef("CacheRebuild: %v", pluginIDs)
+
+	// Verify the plugin IDs
+	if len(pluginIDs) == 0 {
+		pluginIDs = t.pluginIDs()
+	}
+	for _, v := range pluginIDs {
+		if _, ok := t.plugin(v); !ok {
+			return nil, backend.ErrPluginIDInvalid
+		}
+	}
+
+	// Start the rebuild
+	s, ok := t.cacheRebuildStart(pluginIDs)
+	if !ok {
+		return nil, backend.ErrCacheRebuildInProgress
+	}
+	go t.cacheRebuild()
+
+	return s, nil
+}
+
+// CacheRebuildStatus returns the status of the most recent plugin cache
+// rebuild. A status with all fields set to their zero values is returned if
+// a cache rebuild has not been run.
+func (t *Tstore) CacheRebuildStatus() *backend.CacheRebuildStatus {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.cacheRebuildStatus == nil {
+		return &backend.CacheRebuildStatus{
+			Plugins: []backend.PluginCacheRebuild{},
+		}
+	}
+	return cacheRebuildStatusCopy(*t.cacheRebuildStatus)
+}
+
+// cacheRebuildStart initializes the status of a new cache rebuild for the
+// provided plugins. A copy of the status is returned. False is returned if a
+// cache rebuild is already in progress.
+func (t *Tstore) cacheRebuildStart(pluginIDs []string) (*backend.CacheRebuildStatus, bool) {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.cacheRebuildStatus != nil && t.cacheRebuildStatus.InProgress {
+		return nil, false
+	}
+	plugins := make([]backend.PluginCacheRebuild, 0, len(pluginIDs))
+	for _, v := range pluginIDs {
+		plugins = append(plugins, backend.PluginCacheRebuild{
+			PluginID: v,
+		})
+	}
+	t.cacheRebuildStatus = &backend.CacheRebuildStatus{
+		InProgress: true,
+		Started:    time.Now().Unix(),
+		Plugins:    plugins,
+	}
+
+	return cacheRebuildStatusCopy(*t.cacheRebuildStatus), true
+}
+
+// cacheRebuildUpdate updates the status of the plugin at the provided index
+// of the cache rebuild plugins list.
+func (t *Tstore) cacheRebuildUpdate(i int, update func(*backend.PluginCacheRebuild)) {
+	t.Lock()
+	defer t.Unlock()
+
+	update(&t.cacheRebuildStatus.Plugins[i])
+}
+
+// cacheRebuildDone marks the cache rebuild as finished.
+func (t *Tstore) cacheRebuildDone() {
+	t.Lock()
+	defer t.Unlock()
+
+	t.cacheRebuildStatus.InProgress = false
+	t.cacheRebuildStatus.Finished = time.Now().Unix()
+}
+
+// cacheRebuild rebuilds the caches of the plugins that are listed in the
+// cache rebuild status. Tstore writes are blocked for the duration of the
+// rebuild. An error that is encountered by a plugin is recorded in the status
+// of that plugin and does not prevent the remaining plugins from being
+// rebuilt.
+//
+// This function must be run in its own goroutine.
+func (t *Tstore) cacheRebuild() {
+	defer t.cacheRebuildDone()
+
+	// Block writes for the duration of the rebuild
+	t.freezer.Lock()
+	defer t.freezer.Unlock()
+
+	t.Lock()
+	pluginIDs := make([]string, 0, len(t.cacheRebuildStatus.Plugins))
+	for _, v := range t.cacheRebuildStatus.Plugins {
+		pluginIDs = append(pluginIDs, v.PluginID)
+	}
+	t.Unlock()
+
+	log.Infof("Rebuilding plugin caches: %v", pluginIDs)
+
+	// Get the tokens of all records
+	tokens, err := t.cacheRebuildTokens()
+	if err != nil {
+		log.Errorf("Cache rebuild failed: %v", err)
+		for i := range pluginIDs {
+			t.cacheRebuildUpdate(i, func(p *backend.PluginCacheRebuild) {
+				p.Error = err.Error()
+			})
+		}
+		return
+	}
+
+	// Rebuild the plugin caches
+	for i, pluginID := range pluginIDs {
+		records := uint32(len(tokens))
+		t.cacheRebuildUpdate(i, func(p *backend.PluginCacheRebuild) {
+			p.Records = records
+		})
+
+		p, ok := t.plugin(pluginID)
+		if !ok {
+			// Should not happen. Plugins are not unregistered.
+			err = fmt.Errorf("plugin not found")
+		} else {
+			var rebuilt uint32
+			progress := func() {
+				rebuilt++
+				t.cacheRebuildUpdate(i, func(p *backend.PluginCacheRebuild) {
+					p.Rebuilt = rebuilt
+				})
+				if rebuilt%cacheRebuildLogInterval == 0 {
+					log.Infof("Cache rebuild %v: %v/%v records",
+						pluginID, rebuilt, records)
+				}
+			}
+			err = p.client.CacheRebuild(tokens, progress)
+		}
+
+		t.cacheRebuildUpdate(i, func(p *backend.PluginCacheRebuild) {
+			p.Done = true
+			if err != nil {
+				p.Error = err.Error()
+				return
+			}
+			// Plugins that do not keep any caches are not
+			// required to report progress.
+			p.Rebuilt = records
+		})
+		if err != nil {
+			log.Errorf("Cache rebuild %v failed: %v", pluginID, err)
+			continue
+		}
+
+		log.Infof("Cache rebuild %v complete: %v records", pluginID, records)
+	}
+}
+
+// cacheRebuildTokens returns the tokens of all records in the tstore
+// instance. The tlog inventory includes trees that do not contain a record,
+// i.e. trees that were created for a new record that failed to be saved.
+// These trees are not included.
+func (t *Tstore) cacheRebuildTokens() ([][]byte, error) {
+	inv, err := t.Inventory()
+	if err != nil {
+		return nil, fmt.Errorf("Inventory: %v", err)
+	}
+	tokens := make([][]byte, 0, len(inv))
+	for _, v := range inv {
+		leaves, err := t.leavesAll(treeIDFromToken(v))
+		if err != nil {
+			return nil, fmt.Errorf("leavesAll %x: %v", v, err)
+		}
+		_, err = t.recordIndexLatest(leaves)
+		switch {
+		case err == backend.ErrRecordNotFound:
+			// The tree does not contain a record
+			continue
+		case err != nil:
+			return nil, fmt.Errorf("recordIndexLatest %x: %v", v, err)
+		}
+		tokens = append(tokens, v)
+	}
+	return tokens, nil
+}
+
+// cacheRebuildStatusCopy returns a deep copy of the provided cache rebuild
+// status.
+func cacheRebuildStatusCopy(s backend.CacheRebuildStatus) *backend.CacheRebuildStatus {
+	plugins := make([]backend.PluginCacheRebuild, len(s.Plugins))
+	copy(plugins, s.Plugins)
+	s.Plugins = plugins
+	return &s
+}
@@ -17,7 +17,13 @@ import (
 	"github.com/decred/politeia/util"
 )
 
+var (
+	_ anchorClient = (*dcrtimeClient)(nil)
+)
+
 // dcrtimeClient is a client for interacting with the dcrtime API.
+//
+// dcrtimeClient satisfies the anchorClient interface.
 type dcrtimeClient struct {
 	host     string
 	certPath string
@@ -70,9 +76,11 @@ func (c *dcrtimeClient) makeReq(method string, route string, v interface{}) ([]b
 	return util.RespBody(r), nil
 }
 
-// timestampBatch posts digests to the dcrtime v2 batch timestamp route.
-func (c *dcrtimeClient) timestampBatch(id string, digests []string) (*dcrtime.TimestampBatchReply, error) {
-	log.Tracef("timestampBatch: %v %v", id, digests)
+// TimestampBatch posts digests to the dcrtime v2 batch timestamp route.
+//
+// This function satisfies the anchorClient interface.
+func (c *dcrtimeClient) TimestampBatch(id string, digests []string) (*dcrtime.TimestampBatchReply, error) {
+	log.Tracef("TimestampBatch: %v %v", id, digests)
 
 	// Setup request
 	for _, v := range digests {
@@ -101,7 +109,7 @@ func (c *dcrtimeClient) timestampBatch(id string, digests []string) (*dcrtime.Ti
 	return &tbr, nil
 }
 
-// verifyBatch returns the data to verify that a digest was included in a
+// VerifyBatch returns the data to verify that a digest was included in a
 // dcrtime timestamp. This function verifies the merkle path and merkle root of
 // all successful timestamps. The caller is responsible for checking the result
 // code and handling digests that failed to be timestamped.
@@ -111,8 +119,10 @@ func (c *dcrtimeClient) timestampBatch(id string, digests []string) (*dcrtime.Ti
 // once the digest has been included in a dcr transaction, except for the
 // ChainTimestamp field. The ChainTimestamp field is only populated once the
 // dcr transaction has 6 confirmations.
-func (c *dcrtimeClient) verifyBatch(id string, digests []string) (*dcrtime.VerifyBatchReply, error) {
-	log.Tracef("verifyBatch: %v %v", id, digests)
+//
+// This function satisfies the anchorClient interface.
+func (c *dcrtimeClient) VerifyBatch(id string, digests []string) (*dcrtime.VerifyBatchReply, error) {
+	log.Tracef("VerifyBatch: %v %v", id, digests)
 
 	// Setup request
 	for _, v := range digests {
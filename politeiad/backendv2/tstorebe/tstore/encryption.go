@@ -0,0 +1,168 @@
+// Copyright (c) 2020-2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tstore
+
+import (
+	"fmt"
+	"strings"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+)
+
+const (
+	// reencryptBatchSize is the number of blobs that are re-encrypted
+	// in a single store call during a re-encryption pass.
+	reencryptBatchSize = 100
+)
+
+// reencryptPass re-encrypts all encrypted blobs in the key-value store using
+// the active encryption key, then retires all prior keys that are no longer
+// used by any blob.
+//
+// Blobs that are saved during the pass are encrypted using the active key so
+// they do not need to be re-encrypted. Blobs that are deleted during the pass
+// are ignored by the store.
+func (t *Tstore) reencryptPass() error {
+	_, activeKeyID := t.keyRotator.KeyIDs()
+	log.Infof("Re-encrypting blobs using encryption key %v", activeKeyID)
+
+	keys, err := t.store.Keys()
+	if err != nil {
+		return fmt.Errorf("keys: %v", err)
+	}
+	encrypted := make([]string, 0, len(keys))
+	for _, v := range keys {
+		if strings.HasPrefix(v, keyPrefixEncrypted) {
+			encrypted = append(encrypted, v)
+		}
+	}
+	for i := 0; i < len(encrypted); i += reencryptBatchSize {
+		end := i + reencryptBatchSize
+		if end > len(encrypted) {
+			end = len(encrypted)
+		}
+		n, err := t.keyRotator.Reencrypt(encrypted[i:end])
+		if err != nil {
+			return fmt.Errorf("reencrypt: %v", err)
+		}
+
+		t.Lock()
+		t.reencrypted += uint64(n)
+		t.Unlock()
+
+		log.Debugf("Re-encrypted blobs %v/%v", end, len(encrypted))
+	}
+
+	t.RLock()
+	reencrypted := t.reencrypted
+	t.RUnlock()
+
+	log.Infof("Re-encryption complete: %v blobs checked, %v blobs "+
+		"re-encrypted", len(encrypted), reencrypted)
+
+	// Retire the prior keys
+	retired, err := t.keyRotator.RetireKeys()
+	if err != nil {
+		return fmt.Errorf("retire keys: %v", err)
+	}
+	if len(retired) > 0 {
+		log.Infof("Retired encryption keys: %v", retired)
+	}
+
+	return nil
+}
+
+// reencryptStart flags a re-encryption pass as in progress. Only a single
+// re-encryption pass is run at a time. If a pass is already in progress, it is
+// flagged to run again once it completes so that any blobs that it already
+// processed are re-encrypted using the newest key. The returned boolean
+// indicates whether the caller must launch the re-encryption.
+func (t *Tstore) reencryptStart() bool {
+	t.Lock()
+	defer t.Unlock()
+
+	if t.reencrypting {
+		t.reencryptPending = true
+		return false
+	}
+	t.reencrypting = true
+	t.reencrypted = 0
+
+	return true
+}
+
+// reencrypt runs re-encryption passes until no further passes have been
+// requested.
+//
+// This function must only be called after reencryptStart returns true and
+// must be called in a goroutine.
+func (t *Tstore) reencrypt() {
+	for {
+		err := t.reencryptPass()
+		if err != nil {
+			log.Errorf("reencryptPass: %v", err)
+		}
+
+		t.Lock()
+		if !t.reencryptPending {
+			t.reencrypting = false
+			t.Unlock()
+			return
+		}
+		t.reencryptPending = false
+		t.reencrypted = 0
+		t.Unlock()
+	}
+}
+
+// RotateEncryptionKey creates a new encryption key for unvetted blobs and
+// launches a re-encryption pass in the background that re-encrypts all
+// existing encrypted blobs using the new key. The prior keys are retired once
+// the pass is complete. The new key is loaded from the provided key file. The
+// key-value store uses a default key file if one is not provided.
+func (t *Tstore) RotateEncryptionKey(keyFile string) (*backend.EncryptionKeyStatus, error) {
+	log.Tracef("RotateEncryptionKey: %v", keyFile)
+
+	if t.keyRotator == nil {
+		return nil, fmt.Errorf("key-value store does not support " +
+			"encryption key rotation")
+	}
+
+	keyID, err := t.keyRotator.RotateKey(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("rotate key: %v", err)
+	}
+
+	log.Infof("Encryption key rotated; new key ID %v", keyID)
+
+	if t.reencryptStart() {
+		go t.reencrypt()
+	}
+
+	return t.EncryptionKeyStatus()
+}
+
+// EncryptionKeyStatus returns the status of the encryption keys that are used
+// to encrypt unvetted blobs.
+func (t *Tstore) EncryptionKeyStatus() (*backend.EncryptionKeyStatus, error) {
+	log.Tracef("EncryptionKeyStatus")
+
+	if t.keyRotator == nil {
+		return nil, fmt.Errorf("key-value store does not support " +
+			"encryption key rotation")
+	}
+
+	keyIDs, activeKeyID := t.keyRotator.KeyIDs()
+
+	t.RLock()
+	defer t.RUnlock()
+
+	return &backend.EncryptionKeyStatus{
+		ActiveKeyID:  activeKeyID,
+		KeyIDs:       keyIDs,
+		Reencrypting: t.reencrypting || t.reencryptPending,
+		Reencrypted:  t.reencrypted,
+	}, nil
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tstore
+
+import (
+	"encoding/hex"
+	"reflect"
+	"testing"
+	"time"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+)
+
+// waitReencrypt waits for the re-encryption pass to complete and returns the
+// encryption key status.
+func waitReencrypt(t *testing.T, ts *Tstore) *backend.EncryptionKeyStatus {
+	t.Helper()
+
+	timeout := time.After(10 * time.Second)
+	for {
+		s, err := ts.EncryptionKeyStatus()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !s.Reencrypting {
+			return s
+		}
+		select {
+		case <-timeout:
+			t.Fatalf("re-encryption did not complete")
+		case <-time.After(10 * time.Millisecond):
+		}
+	}
+}
+
+func TestRotateEncryptionKey(t *testing.T) {
+	ts, cleanup := newTestTstoreNative(t)
+	defer cleanup()
+
+	// Save an unvetted record. The record content is encrypted using
+	// the initial key.
+	newUnvetted := func() []byte {
+		token, err := ts.RecordNew()
+		if err != nil {
+			t.Fatal(err)
+		}
+		rm := backend.RecordMetadata{
+			Token:     hex.EncodeToString(token),
+			Version:   1,
+			Iteration: 1,
+			State:     backend.StateUnvetted,
+			Status:    backend.StatusUnreviewed,
+			Timestamp: time.Now().Unix(),
+		}
+		files := []backend.File{
+			newTestFile("index.md", "unvetted "+rm.Token),
+		}
+		err = ts.RecordSave(token, rm, []backend.MetadataStream{}, files)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	tokens := [][]byte{newUnvetted()}
+
+	// Rotate the key twice. A record is saved under each key so that
+	// the blobs that were written under every key must survive the
+	// following rotations.
+	for i := 1; i <= 2; i++ {
+		_, err := ts.RotateEncryptionKey("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		s := waitReencrypt(t, ts)
+
+		// The prior keys must have been retired once all blobs were
+		// re-encrypted using the new key.
+		wantIDs := []uint32{uint32(i)}
+		if s.ActiveKeyID != uint32(i) || !reflect.DeepEqual(s.KeyIDs, wantIDs) {
+			t.Fatalf("rotation %v: got active key %v and keys %v, want %v",
+				i, s.ActiveKeyID, s.KeyIDs, wantIDs)
+		}
+
+		// All records must still be readable
+		for _, token := range tokens {
+			r, err := ts.RecordLatest(token)
+			if err != nil {
+				t.Fatalf("rotation %v: %x: %v", i, token, err)
+			}
+			want := "unvetted " + hex.EncodeToString(token)
+			if len(r.Files) != 1 || r.Files[0].Digest !=
+				newTestFile("index.md", want).Digest {
+				t.Fatalf("rotation %v: %x: unexpected files %+v",
+					i, token, r.Files)
+			}
+		}
+
+		tokens = append(tokens, newUnvetted())
+	}
+}
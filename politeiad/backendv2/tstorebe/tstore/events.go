@@ -0,0 +1,236 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tstore
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+)
+
+const (
+	// eventsBufferSize is the number of the most recent events that
+	// are kept in memory. A subscriber is able to resume the event
+	// stream as long as the events that it missed are still buffered.
+	eventsBufferSize = 1024
+)
+
+// eventStream is the in memory backend event stream. Events are assigned a
+// sequence number when they are published. The event ID, which is also the
+// resume token, is the stream epoch and the sequence number. The epoch is
+// the time that the stream was created.
+//
+// The events are not persisted. A stream can only be resumed within the
+// lifetime of the politeiad process that published the events. A restart of
+// politeiad creates a new epoch, which expires the resume tokens of the prior
+// epoch. A subscriber that connects without a resume token only receives new
+// events, so the events that were published before it connected, e.g. during
+// plugin setup, must be accounted for by the resync that it performs after
+// its resume token has expired.
+type eventStream struct {
+	sync.Mutex
+	epoch       int64
+	seq         uint64          // Sequence number of the last event
+	events      []backend.Event // Most recent events, oldest first
+	subscribers map[<-chan backend.Event]chan backend.Event
+	closed      bool
+}
+
+// newEventStream returns a new eventStream.
+func newEventStream() *eventStream {
+	return &eventStream{
+		epoch:       time.Now().UnixNano(),
+		events:      make([]backend.Event, 0, eventsBufferSize),
+		subscribers: make(map[<-chan backend.Event]chan backend.Event),
+	}
+}
+
+// eventID returns the event ID for the provided sequence number.
+func (s *eventStream) eventID(seq uint64) string {
+	return fmt.Sprintf("%x-%v", s.epoch, seq)
+}
+
+// resumeSeq returns the sequence number of the event that corresponds to the
+// provided resume token. ErrEventResumeInvalid is returned if the resume token
+// is malformed or refers to an event that has not been published.
+// ErrEventResumeExpired is returned if the resume token is not from the
+// current epoch or if the events that were published after it are no longer
+// buffered.
+//
+// This function must be called WITH the lock held.
+func (s *eventStream) resumeSeq(resume string) (uint64, error) {
+	parts := strings.Split(resume, "-")
+	if len(parts) != 2 {
+		return 0, backend.ErrEventResumeInvalid
+	}
+	epoch, err := strconv.ParseInt(parts[0], 16, 64)
+	if err != nil {
+		return 0, backend.ErrEventResumeInvalid
+	}
+	seq, err := strconv.ParseUint(parts[1], 10, 64)
+	if err != nil {
+		return 0, backend.ErrEventResumeInvalid
+	}
+	if epoch != s.epoch {
+		// Resume token is from a prior epoch
+		return 0, backend.ErrEventResumeExpired
+	}
+
+	// The buffered events contain the sequence numbers from
+	// oldest through s.seq.
+	oldest := s.seq - uint64(len(s.events)) + 1
+	switch {
+	case seq > s.seq:
+		return 0, backend.ErrEventResumeInvalid
+	case seq+1 < oldest:
+		return 0, backend.ErrEventResumeExpired
+	}
+
+	return seq, nil
+}
+
+// publish assigns an ID to the event, buffers it, and sends it to all
+// subscribers. A subscriber that has fallen too far behind is removed and its
+// channel is closed so that the publisher never blocks.
+func (s *eventStream) publish(e backend.Event) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.closed {
+		return
+	}
+
+	s.seq++
+	e.ID = s.eventID(s.seq)
+	e.Timestamp = time.Now().Unix()
+
+	s.events = append(s.events, e)
+	if len(s.events) > eventsBufferSize {
+		s.events = s.events[len(s.events)-eventsBufferSize:]
+	}
+
+	for k, ch := range s.subscribers {
+		select {
+		case ch <- e:
+		default:
+			log.Warnf("Event subscriber has fallen behind; closing " +
+				"subscription")
+			delete(s.subscribers, k)
+			close(ch)
+		}
+	}
+
+	log.Debugf("Event published %v: %v %v", e.ID,
+		backend.Events[e.Type], e.Token)
+}
+
+// subscribe returns a new subscription. The buffered events that were
+// published after the provided resume token are sent on the returned channel
+// prior to any new events.
+func (s *eventStream) subscribe(resume string) (<-chan backend.Event, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.closed {
+		return nil, backend.ErrShutdown
+	}
+
+	var missed []backend.Event
+	if resume != "" {
+		seq, err := s.resumeSeq(resume)
+		if err != nil {
+			return nil, err
+		}
+		missed = s.events[uint64(len(s.events))-(s.seq-seq):]
+	}
+
+	// The channel is large enough to hold all of the buffered events
+	// so that the missed events can be sent without blocking.
+	ch := make(chan backend.Event, eventsBufferSize)
+	for _, v := range missed {
+		ch <- v
+	}
+	s.subscribers[ch] = ch
+
+	return ch, nil
+}
+
+// unsubscribe removes the subscription and closes the channel.
+func (s *eventStream) unsubscribe(c <-chan backend.Event) {
+	s.Lock()
+	defer s.Unlock()
+
+	ch, ok := s.subscribers[c]
+	if !ok {
+		// Subscription has already been removed
+		return
+	}
+	delete(s.subscribers, c)
+	close(ch)
+}
+
+// close closes all subscriptions. No further events are published.
+func (s *eventStream) close() {
+	s.Lock()
+	defer s.Unlock()
+
+	for k, ch := range s.subscribers {
+		delete(s.subscribers, k)
+		close(ch)
+	}
+	s.closed = true
+}
+
+// EventPublish publishes a backend event to the event stream subscribers.
+// The event ID and timestamp are assigned by the event stream.
+func (t *Tstore) EventPublish(e backend.Event) {
+	t.events.publish(e)
+}
+
+// EventsSubscribe subscribes to the backend event stream. See the backendv2
+// Backend interface for details.
+func (t *Tstore) EventsSubscribe(resume string) (<-chan backend.Event, error) {
+	log.Tracef("EventsSubscribe: %v", resume)
+
+	return t.events.subscribe(resume)
+}
+
+// EventsUnsubscribe cancels an event stream subscription.
+func (t *Tstore) EventsUnsubscribe(ch <-chan backend.Event) {
+	log.Tracef("EventsUnsubscribe")
+
+	t.events.unsubscribe(ch)
+}
+
+// PluginEventPublish publishes a plugin event to the backend event stream.
+// The payload is defined by the plugin.
+//
+// This function satisfies the plugins TstoreClient interface.
+func (t *Tstore) PluginEventPublish(token []byte, pluginID, name, payload string) error {
+	log.Tracef("PluginEventPublish: %x %v %v", token, pluginID, name)
+
+	if _, ok := t.plugin(pluginID); !ok {
+		return backend.ErrPluginIDInvalid
+	}
+
+	var tokenStr string
+	if len(token) > 0 {
+		tokenStr = hex.EncodeToString(token)
+	}
+	t.events.publish(backend.Event{
+		Type:     backend.EventTypePlugin,
+		Token:    tokenStr,
+		PluginID: pluginID,
+		Name:     name,
+		Payload:  payload,
+	})
+
+	return nil
+}
@@ -0,0 +1,229 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tstore
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+)
+
+// newTestEventStream returns an event stream that the provided number of
+// events have been published to.
+func newTestEventStream(t *testing.T, events int) *eventStream {
+	t.Helper()
+
+	s := newEventStream()
+	for i := 0; i < events; i++ {
+		s.publish(backend.Event{
+			Type:  backend.EventTypeRecordNew,
+			Token: fmt.Sprintf("%v", i+1),
+		})
+	}
+	return s
+}
+
+// eventsRecv receives all of the events that are currently buffered in the
+// provided channel and returns their sequence numbers.
+func eventsRecv(t *testing.T, s *eventStream, ch <-chan backend.Event) []uint64 {
+	t.Helper()
+
+	seqs := make([]uint64, 0, len(ch))
+	for len(ch) > 0 {
+		e := <-ch
+		var epoch int64
+		var seq uint64
+		_, err := fmt.Sscanf(e.ID, "%x-%d", &epoch, &seq)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if epoch != s.epoch {
+			t.Fatalf("got event epoch %x, want %x", epoch, s.epoch)
+		}
+		seqs = append(seqs, seq)
+	}
+	return seqs
+}
+
+func TestEventStreamResume(t *testing.T) {
+	var tests = []struct {
+		name      string
+		published int    // Number of events published
+		resume    uint64 // Sequence number of the resume token
+		wantErr   error
+		wantFirst uint64 // First missed event, 0 if none were missed
+	}{
+		{"empty buffer", 0, 0, nil, 0},
+		{"empty buffer future event", 0, 1,
+			backend.ErrEventResumeInvalid, 0},
+		{"resume from first event", 5, 1, nil, 2},
+		{"resume from last event", 5, 5, nil, 0},
+		{"resume from future event", 5, 6,
+			backend.ErrEventResumeInvalid, 0},
+		{"full buffer resume from start", eventsBufferSize, 0, nil, 1},
+		{"wrapped buffer oldest resumable", eventsBufferSize + 10, 10,
+			nil, 11},
+		{"wrapped buffer evicted event", eventsBufferSize + 10, 9,
+			backend.ErrEventResumeExpired, 0},
+		{"wrapped buffer last event", eventsBufferSize + 10,
+			eventsBufferSize + 10, nil, 0},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			s := newTestEventStream(t, v.published)
+			if len(s.events) > eventsBufferSize {
+				t.Fatalf("got %v buffered events, want at most %v",
+					len(s.events), eventsBufferSize)
+			}
+
+			ch, err := s.subscribe(s.eventID(v.resume))
+			if v.wantErr != nil {
+				if !errors.Is(err, v.wantErr) {
+					t.Fatalf("got error %v, want %v", err, v.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer s.unsubscribe(ch)
+
+			// The missed events are every event that was published
+			// after the resume token, in order.
+			seqs := eventsRecv(t, s, ch)
+			want := uint64(v.published) - v.resume
+			if uint64(len(seqs)) != want {
+				t.Fatalf("got %v missed events, want %v", len(seqs), want)
+			}
+			for i, seq := range seqs {
+				if seq != v.wantFirst+uint64(i) {
+					t.Fatalf("missed event %v: got seq %v, want %v",
+						i, seq, v.wantFirst+uint64(i))
+				}
+			}
+		})
+	}
+}
+
+func TestEventStreamResumeInvalid(t *testing.T) {
+	s := newTestEventStream(t, 3)
+
+	// A resume token from a prior epoch, i.e. from before a restart,
+	// has expired even when the sequence number is buffered in the
+	// current epoch.
+	prior := newTestEventStream(t, 3)
+	prior.epoch = s.epoch - 1
+
+	var tests = []struct {
+		name    string
+		resume  string
+		wantErr error
+	}{
+		{"prior epoch", prior.eventID(2), backend.ErrEventResumeExpired},
+		{"no separator", fmt.Sprintf("%x", s.epoch),
+			backend.ErrEventResumeInvalid},
+		{"too many parts", s.eventID(1) + "-1",
+			backend.ErrEventResumeInvalid},
+		{"invalid epoch", "z-1", backend.ErrEventResumeInvalid},
+		{"invalid sequence number", fmt.Sprintf("%x-a", s.epoch),
+			backend.ErrEventResumeInvalid},
+		{"negative sequence number", fmt.Sprintf("%x--1", s.epoch),
+			backend.ErrEventResumeInvalid},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			_, err := s.subscribe(v.resume)
+			if !errors.Is(err, v.wantErr) {
+				t.Fatalf("got error %v, want %v", err, v.wantErr)
+			}
+		})
+	}
+}
+
+func TestEventStreamSlowSubscriber(t *testing.T) {
+	s := newTestEventStream(t, 0)
+
+	slow, err := s.subscribe("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fast, err := s.subscribe("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Fill the channel of the slow subscriber while the fast
+	// subscriber keeps up.
+	for i := 0; i < eventsBufferSize; i++ {
+		s.publish(backend.Event{Type: backend.EventTypeRecordNew})
+		<-fast
+	}
+	if len(s.subscribers) != 2 {
+		t.Fatalf("got %v subscribers, want 2", len(s.subscribers))
+	}
+
+	// The next event can't be sent to the slow subscriber. It is
+	// removed and its channel is closed without blocking the publish.
+	s.publish(backend.Event{Type: backend.EventTypeRecordNew})
+	if _, ok := s.subscribers[slow]; ok {
+		t.Fatalf("slow subscriber was not removed")
+	}
+	if _, ok := s.subscribers[fast]; !ok {
+		t.Fatalf("fast subscriber was removed")
+	}
+	if e := <-fast; e.ID != s.eventID(eventsBufferSize+1) {
+		t.Fatalf("got event %v, want %v", e.ID, s.eventID(eventsBufferSize+1))
+	}
+
+	// The slow subscriber receives the events that were buffered in
+	// its channel and then the closed channel.
+	seqs := eventsRecv(t, s, slow)
+	if len(seqs) != eventsBufferSize || seqs[len(seqs)-1] != eventsBufferSize {
+		t.Fatalf("got %v events, want %v", len(seqs), eventsBufferSize)
+	}
+	if _, ok := <-slow; ok {
+		t.Fatalf("slow subscriber channel was not closed")
+	}
+
+	// Unsubscribing an evicted subscriber is a no-op
+	s.unsubscribe(slow)
+
+	// The evicted subscriber is able to resume from the last event it
+	// received.
+	ch, err := s.subscribe(s.eventID(seqs[len(seqs)-1]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	seqs = eventsRecv(t, s, ch)
+	if len(seqs) != 1 || seqs[0] != eventsBufferSize+1 {
+		t.Fatalf("got missed events %v, want %v", seqs, eventsBufferSize+1)
+	}
+}
+
+func TestEventStreamClose(t *testing.T) {
+	s := newTestEventStream(t, 1)
+	ch, err := s.subscribe("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s.close()
+	if _, ok := <-ch; ok {
+		t.Fatalf("subscriber channel was not closed")
+	}
+
+	// Events are not published and new subscriptions are rejected
+	// once the stream is closed.
+	s.publish(backend.Event{Type: backend.EventTypeRecordNew})
+	if s.seq != 1 {
+		t.Fatalf("got seq %v, want 1", s.seq)
+	}
+	_, err = s.subscribe("")
+	if !errors.Is(err, backend.ErrShutdown) {
+		t.Fatalf("got error %v, want %v", err, backend.ErrShutdown)
+	}
+}
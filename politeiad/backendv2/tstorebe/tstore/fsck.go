@@ -0,0 +1,405 @@
+// Copyright (c) 2020-2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tstore
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+	"github.com/decred/politeia/util"
+	"github.com/google/trillian"
+	"github.com/google/uuid"
+)
+
+// fsck is used to aggregate the results of a tstore filesystem check.
+type fsck struct {
+	repair bool
+	report backend.FsckReport
+
+	// keys contains the kv store keys of all blobs that are referenced
+	// by a tlog leaf. Any tstore blob in the kv store that is not in
+	// this list is considered to be orphaned.
+	keys map[string]struct{}
+}
+
+// issueAdd adds an issue to the fsck report.
+func (f *fsck) issueAdd(issue backend.FsckIssueT, token []byte, repaired bool, format string, args ...interface{}) {
+	var t string
+	if token != nil {
+		t = hex.EncodeToString(token)
+	}
+	desc := fmt.Sprintf(format, args...)
+	f.report.Issues = append(f.report.Issues, backend.FsckIssue{
+		Type:        issue,
+		Token:       t,
+		Description: desc,
+		Repaired:    repaired,
+	})
+
+	log.Infof("Fsck %v %v: %v", backend.FsckIssues[issue], t, desc)
+}
+
+// isTstoreKey returns whether the provided kv store key was created by tstore.
+// The kv store is also used to store data that is not part of a tlog tree,
+// such as key derivation params. These blobs are not created using a uuid key.
+func isTstoreKey(key string) bool {
+	key = strings.TrimPrefix(key, keyPrefixEncrypted)
+	if len(key) != 36 {
+		return false
+	}
+	_, err := uuid.Parse(key)
+	return err == nil
+}
+
+// blobEntryData decodes the data of a blob entry and verifies that it matches
+// the blob entry digest.
+func blobEntryData(be store.BlobEntry) ([]byte, error) {
+	b, err := base64.StdEncoding.DecodeString(be.Data)
+	if err != nil {
+		return nil, fmt.Errorf("decode Data: %v", err)
+	}
+	digest, err := hex.DecodeString(be.Digest)
+	if err != nil {
+		return nil, fmt.Errorf("decode Digest: %v", err)
+	}
+	if !bytes.Equal(util.Digest(b), digest) {
+		return nil, fmt.Errorf("data is not coherent; got %x, want %x",
+			util.Digest(b), digest)
+	}
+	return b, nil
+}
+
+// fsckTree performs a filesystem check on a single tlog tree. The following
+// checks are performed.
+//
+//  1. All leaves referenced by the record indexes exist.
+//
+//  2. All record content blobs exist in the kv store and are coherent with the
+//     digest that was saved to their tlog leaf. Plugin data blobs are checked
+//     for coherency, but are not required to exist since plugins are allowed
+//     to delete blobs, e.g. deleting a comment.
+//
+//  3. All file blobs have been deleted for censored records.
+//
+//  4. Frozen trees that have been anchored one last time have their status
+//     set to frozen in trillian.
+func (t *Tstore) fsckTree(tree *trillian.Tree, f *fsck) error {
+	var (
+		treeID = tree.TreeId
+		token  = tokenFromTreeID(treeID)
+	)
+	leaves, err := t.tlog.LeavesAll(treeID)
+	if err != nil {
+		return fmt.Errorf("LeavesAll %v: %v", treeID, err)
+	}
+	if len(leaves) == 0 {
+		// A tree can exist without any leaves if an unexpected error
+		// occurred during record creation. Nothing to check.
+		return nil
+	}
+
+	// Decode the extra data of all leaves and aggregate the kv store
+	// keys. Blobs that are part of a vetted record can exist in the kv
+	// store as both an encrypted blob and a plain text blob, so both
+	// keys are aggregated.
+	var (
+		eds  = make(map[string]*extraData, len(leaves)) // [merkle]extraData
+		keys = make([]string, 0, len(leaves)*2)
+	)
+	for _, v := range leaves {
+		ed, err := extraDataDecode(v.ExtraData)
+		if err != nil {
+			f.issueAdd(backend.FsckIssueLeafInvalid, token, false,
+				"leaf %v extra data: %v", v.LeafIndex, err)
+			continue
+		}
+		eds[hex.EncodeToString(v.MerkleLeafHash)] = ed
+
+		keys = append(keys, ed.storeKey())
+		f.keys[ed.storeKey()] = struct{}{}
+		if ed.storeKey() != ed.storeKeyNoPrefix() {
+			keys = append(keys, ed.storeKeyNoPrefix())
+			f.keys[ed.storeKeyNoPrefix()] = struct{}{}
+		}
+	}
+
+	// Get the record indexes. The tree may not contain a record if an
+	// unexpected error occurred during record creation. The remaining
+	// leaves are still checked for coherency.
+	indexes, err := t.recordIndexes(leaves)
+	switch {
+	case errors.Is(err, backend.ErrRecordNotFound):
+		// No record indexes exist
+	case err != nil:
+		f.issueAdd(backend.FsckIssueRecordIndex, token, false, "%v", err)
+	default:
+		f.report.Records++
+	}
+
+	// Compile the merkle leaf hashes of the record content that is
+	// referenced by the record indexes.
+	var (
+		required = make(map[string]struct{}, len(leaves)) // [merkle]
+		files    = make(map[string]struct{}, len(leaves)) // [merkle]
+	)
+	for _, idx := range indexes {
+		required[hex.EncodeToString(idx.RecordMetadata)] = struct{}{}
+		for _, streams := range idx.Metadata {
+			for _, v := range streams {
+				required[hex.EncodeToString(v)] = struct{}{}
+			}
+		}
+		for _, v := range idx.Files {
+			m := hex.EncodeToString(v)
+			required[m] = struct{}{}
+			files[m] = struct{}{}
+		}
+	}
+	for m := range required {
+		if _, ok := eds[m]; !ok {
+			f.issueAdd(backend.FsckIssueLeafInvalid, token, false,
+				"record index references leaf that does not exist %v", m)
+			delete(required, m)
+		}
+	}
+
+	// Determine the state and status of the record
+	var (
+		state  backend.StateT
+		status backend.StatusT
+		frozen bool
+	)
+	if len(indexes) > 0 {
+		latest := indexes[len(indexes)-1]
+		state = latest.State
+		frozen = latest.Frozen
+	}
+
+	// keyForLeaf returns the key that should be used to retrieve the
+	// blob for the leaf. Vetted record content is always retrieved
+	// using the plain text blob.
+	keyForLeaf := func(merkle string, ed *extraData) string {
+		_, ok := required[merkle]
+		if ok && state == backend.StateVetted {
+			return ed.storeKeyNoPrefix()
+		}
+		return ed.storeKey()
+	}
+
+	// Get the blobs from the kv store
+	blobs, err := t.store.Get(keys)
+	if err != nil {
+		return fmt.Errorf("store Get: %v", err)
+	}
+
+	// Verify blob coherency
+	for _, v := range leaves {
+		m := hex.EncodeToString(v.MerkleLeafHash)
+		ed, ok := eds[m]
+		if !ok {
+			// Extra data was invalid. This has already been reported.
+			continue
+		}
+		_, isRequired := required[m]
+		isRequired = isRequired || ed.Desc == dataDescriptorAnchor
+
+		b, ok := blobs[keyForLeaf(m, ed)]
+		if !ok {
+			_, isFile := files[m]
+			if isRequired && !isFile {
+				f.issueAdd(backend.FsckIssueBlobMissing, token, false,
+					"leaf %v %v blob not found %v",
+					v.LeafIndex, ed.Desc, keyForLeaf(m, ed))
+			}
+			// The missing file blobs are verified once the record
+			// status is known.
+			continue
+		}
+		f.report.Blobs++
+
+		be, err := store.Deblob(b)
+		if err != nil {
+			f.issueAdd(backend.FsckIssueBlobCorrupt, token, false,
+				"leaf %v deblob: %v", v.LeafIndex, err)
+			continue
+		}
+		if be.Digest != hex.EncodeToString(v.LeafValue) {
+			f.issueAdd(backend.FsckIssueBlobCorrupt, token, false,
+				"leaf %v digest mismatch: got %v, want %x",
+				v.LeafIndex, be.Digest, v.LeafValue)
+			continue
+		}
+		data, err := blobEntryData(*be)
+		if err != nil {
+			f.issueAdd(backend.FsckIssueBlobCorrupt, token, false,
+				"leaf %v: %v", v.LeafIndex, err)
+			continue
+		}
+
+		// Pull the record status from the latest record metadata
+		if len(indexes) > 0 &&
+			bytes.Equal(v.MerkleLeafHash,
+				indexes[len(indexes)-1].RecordMetadata) {
+			var rm backend.RecordMetadata
+			err = json.Unmarshal(data, &rm)
+			if err != nil {
+				f.issueAdd(backend.FsckIssueBlobCorrupt, token, false,
+					"leaf %v unmarshal RecordMetadata: %v", v.LeafIndex, err)
+				continue
+			}
+			status = rm.Status
+		}
+	}
+
+	// Verify that the file blobs of censored records have been deleted
+	// and that the file blobs of all other records exist.
+	var filesFound int
+	for m := range files {
+		ed := eds[m]
+		_, ok1 := blobs[ed.storeKey()]
+		_, ok2 := blobs[ed.storeKeyNoPrefix()]
+		switch {
+		case status == backend.StatusCensored:
+			if ok1 || ok2 {
+				filesFound++
+			}
+		case !ok1 && !ok2:
+			f.issueAdd(backend.FsckIssueBlobMissing, token, false,
+				"%v blob not found %v", ed.Desc, keyForLeaf(m, ed))
+		}
+	}
+	if filesFound > 0 {
+		var repaired bool
+		if f.repair {
+			err = t.RecordDel(token)
+			if err != nil {
+				log.Errorf("Fsck RecordDel %x: %v", token, err)
+			} else {
+				repaired = true
+			}
+		}
+		f.issueAdd(backend.FsckIssueCensoredFiles, token, repaired,
+			"%v file blobs found", filesFound)
+	}
+
+	// Set the tree status to frozen if the tree has been frozen and
+	// anchored one last time. The anchor being the last leaf in the
+	// tree means that all record content has been anchored.
+	lastLeaf := eds[hex.EncodeToString(leaves[len(leaves)-1].MerkleLeafHash)]
+	if frozen && lastLeaf != nil && lastLeaf.Desc == dataDescriptorAnchor &&
+		tree.TreeState != trillian.TreeState_FROZEN {
+		var repaired bool
+		if f.repair {
+			_, err = t.tlog.TreeFreeze(treeID)
+			if err != nil {
+				log.Errorf("Fsck TreeFreeze %v: %v", treeID, err)
+			} else {
+				repaired = true
+			}
+		}
+		f.issueAdd(backend.FsckIssueTreeNotFrozen, token, repaired,
+			"tree state %v", tree.TreeState)
+	}
+
+	return nil
+}
+
+// Fsck performs a filesystem check on the tstore. All tlog trees are walked
+// and the record content is verified against the kv store blobs. Any blobs in
+// the kv store that are not referenced by a tlog leaf are reported as
+// orphaned. The fsck of all registered plugins is run once the tstore checks
+// have completed.
+//
+// If repair is set to true then the following issues will be repaired:
+//
+//  1. The file blobs of censored records are deleted.
+//
+//  2. Frozen trees that have been anchored are set to frozen in trillian.
+//
+//  3. Orphaned blobs are deleted from the kv store.
+//
+// Blobs are saved to the kv store prior to being appended onto a tlog tree.
+// Running a repair while the tstore is accepting writes may result in the
+// blobs of an in progress write being deleted. Repairs must only be performed
+// when no writes are being executed, i.e. on startup.
+func (t *Tstore) Fsck(repair bool) (*backend.FsckReport, error) {
+	log.Infof("Starting tstore fsck (repair: %v)", repair)
+
+	f := fsck{
+		repair: repair,
+		report: backend.FsckReport{
+			Repair: repair,
+			Issues: make([]backend.FsckIssue, 0, 64),
+		},
+		keys: make(map[string]struct{}, 4096),
+	}
+
+	// Check all trees
+	trees, err := t.tlog.TreesAll()
+	if err != nil {
+		return nil, fmt.Errorf("TreesAll: %v", err)
+	}
+	for _, v := range trees {
+		err = t.fsckTree(v, &f)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Find orphaned blobs
+	keys, err := t.store.Keys()
+	if err != nil {
+		return nil, fmt.Errorf("store Keys: %v", err)
+	}
+	orphans := make([]string, 0, 256)
+	for _, k := range keys {
+		if !isTstoreKey(k) {
+			continue
+		}
+		if _, ok := f.keys[k]; ok {
+			continue
+		}
+		orphans = append(orphans, k)
+	}
+	if len(orphans) > 0 {
+		var repaired bool
+		if repair {
+			err = t.store.Del(orphans)
+			if err != nil {
+				log.Errorf("Fsck store Del: %v", err)
+			} else {
+				repaired = true
+			}
+		}
+		for _, k := range orphans {
+			f.issueAdd(backend.FsckIssueBlobOrphaned, nil, repaired, "%v", k)
+		}
+	}
+
+	// Run the plugin fscks
+	for _, pluginID := range t.pluginIDs() {
+		p, _ := t.plugin(pluginID)
+
+		log.Infof("Starting %v plugin fsck", pluginID)
+
+		err = p.client.Fsck()
+		if err != nil {
+			f.issueAdd(backend.FsckIssuePlugin, nil, false,
+				"%v: %v", pluginID, err)
+		}
+	}
+
+	log.Infof("Tstore fsck complete: %v records, %v blobs, %v issues",
+		f.report.Records, f.report.Blobs, len(f.report.Issues))
+
+	return &f.report, nil
+}
@@ -0,0 +1,226 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tstore
+
+import (
+	"testing"
+
+	dcrtime "github.com/decred/dcrtime/api/v2"
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/google/trillian"
+)
+
+func TestFsckOrphans(t *testing.T) {
+	ts, cleanup := newTestTstoreNative(t)
+	defer cleanup()
+
+	// A record with valid content must not be reported
+	newTestRecord(t, ts)
+
+	// Seed an orphaned tstore blob and a blob that was not created by
+	// tstore. Only the tstore blob should be reported.
+	orphan := storeKeyNew(false)
+	err := ts.store.Put(map[string][]byte{
+		orphan:       []byte("orphan"),
+		"custom-key": []byte("custom"),
+	}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Verify that the orphan is reported but not deleted when repair
+	// is not set.
+	r, err := ts.Fsck(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Records != 1 {
+		t.Errorf("got %v records, want 1", r.Records)
+	}
+	if len(r.Issues) != 1 {
+		t.Fatalf("got %v issues, want 1: %+v", len(r.Issues), r.Issues)
+	}
+	issue := r.Issues[0]
+	if issue.Type != backend.FsckIssueBlobOrphaned ||
+		issue.Description != orphan || issue.Repaired {
+		t.Fatalf("got issue %+v, want unrepaired orphan %v", issue, orphan)
+	}
+	blobs, err := ts.store.Get([]string{orphan})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := blobs[orphan]; !ok {
+		t.Fatalf("orphan was deleted without repair")
+	}
+
+	// Repair the tstore. The orphan must be deleted and the blob that
+	// was not created by tstore must be left alone.
+	r, err = ts.Fsck(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Issues) != 1 || !r.Issues[0].Repaired {
+		t.Fatalf("got issues %+v, want one repaired orphan", r.Issues)
+	}
+	blobs, err = ts.store.Get([]string{orphan, "custom-key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := blobs[orphan]; ok {
+		t.Errorf("orphan was not deleted")
+	}
+	if _, ok := blobs["custom-key"]; !ok {
+		t.Errorf("non tstore blob was deleted")
+	}
+
+	// Verify the repair
+	r, err = ts.Fsck(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Issues) != 0 {
+		t.Fatalf("got issues after repair: %+v", r.Issues)
+	}
+}
+
+func TestFsckCensoredFiles(t *testing.T) {
+	ts, cleanup := newTestTstoreNative(t)
+	defer cleanup()
+
+	// Censor a record without deleting its files
+	token, rm, files := newTestRecord(t, ts)
+	rm.Status = backend.StatusCensored
+	rm.Iteration++
+	err := ts.RecordFreeze(token, rm, []backend.MetadataStream{}, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Verify the file blobs are reported
+	r, err := ts.Fsck(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Issues) != 1 {
+		t.Fatalf("got %v issues, want 1: %+v", len(r.Issues), r.Issues)
+	}
+	if r.Issues[0].Type != backend.FsckIssueCensoredFiles ||
+		r.Issues[0].Repaired {
+		t.Fatalf("got issue %+v, want unrepaired censored files", r.Issues[0])
+	}
+
+	// Repair the tstore. The file blobs must be deleted.
+	r, err = ts.Fsck(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fsckIssues(r, backend.FsckIssueCensoredFiles) != 1 ||
+		!r.Issues[0].Repaired {
+		t.Fatalf("got issues %+v, want repaired censored files", r.Issues)
+	}
+	rc, err := ts.RecordLatest(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rc.Files) != 0 {
+		t.Errorf("got %v files after repair, want 0", len(rc.Files))
+	}
+
+	// Verify the repair. The missing file blobs of a censored record
+	// are not an issue.
+	r, err = ts.Fsck(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Issues) != 0 {
+		t.Fatalf("got issues after repair: %+v", r.Issues)
+	}
+}
+
+func TestFsckTreeNotFrozen(t *testing.T) {
+	ts, cleanup := newTestTstoreNative(t)
+	defer cleanup()
+
+	// Freeze a record
+	token, rm, files := newTestRecord(t, ts)
+	rm.Status = backend.StatusArchived
+	rm.Iteration++
+	err := ts.RecordFreeze(token, rm, []backend.MetadataStream{}, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A frozen tree that has not been anchored is not an issue
+	r, err := ts.Fsck(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Issues) != 0 {
+		t.Fatalf("got issues before anchor: %+v", r.Issues)
+	}
+
+	// Anchor the tree one last time
+	treeID := treeIDFromToken(token)
+	tree, err := ts.tlog.Tree(treeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, lr, err := ts.tlog.SignedLogRoot(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ts.anchorSave(anchor{
+		TreeID:       treeID,
+		LogRoot:      lr,
+		VerifyDigest: &dcrtime.VerifyDigest{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Verify the tree status is reported but not updated when repair
+	// is not set.
+	r, err = ts.Fsck(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Issues) != 1 ||
+		r.Issues[0].Type != backend.FsckIssueTreeNotFrozen ||
+		r.Issues[0].Repaired {
+		t.Fatalf("got issues %+v, want unrepaired tree not frozen", r.Issues)
+	}
+	tree, err = ts.tlog.Tree(treeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tree.TreeState == trillian.TreeState_FROZEN {
+		t.Fatalf("tree was frozen without repair")
+	}
+
+	// Repair the tstore. The tree must be frozen.
+	r, err = ts.Fsck(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Issues) != 1 || !r.Issues[0].Repaired {
+		t.Fatalf("got issues %+v, want repaired tree not frozen", r.Issues)
+	}
+	tree, err = ts.tlog.Tree(treeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tree.TreeState != trillian.TreeState_FROZEN {
+		t.Fatalf("got tree state %v, want frozen", tree.TreeState)
+	}
+
+	// Verify the repair
+	r, err = ts.Fsck(false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(r.Issues) != 0 {
+		t.Fatalf("got issues after repair: %+v", r.Issues)
+	}
+}
@@ -0,0 +1,140 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tstore
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+)
+
+var (
+	// errInvNotSupported is returned when the key-value store does not
+	// support an inventory index.
+	errInvNotSupported = errors.New("inventory index not supported by the " +
+		"key-value store")
+)
+
+// InvPut inserts or updates the provided inventory index entries.
+func (t *Tstore) InvPut(entries []store.InvEntry) error {
+	log.Tracef("InvPut: %v entries", len(entries))
+
+	if t.inv == nil {
+		return errInvNotSupported
+	}
+	return t.inv.InvPut(entries)
+}
+
+// InvDel deletes the inventory index entries for the provided tokens.
+func (t *Tstore) InvDel(tokens []string) error {
+	log.Tracef("InvDel: %v", tokens)
+
+	if t.inv == nil {
+		return errInvNotSupported
+	}
+	return t.inv.InvDel(tokens)
+}
+
+// InvGet returns the inventory index entries for the provided tokens. An entry
+// will not exist in the returned map if it is not found.
+func (t *Tstore) InvGet(tokens []string) (map[string]store.InvEntry, error) {
+	log.Tracef("InvGet: %v", tokens)
+
+	if t.inv == nil {
+		return nil, errInvNotSupported
+	}
+	return t.inv.InvGet(tokens)
+}
+
+// InvQuery returns a page of inventory index entries.
+func (t *Tstore) InvQuery(q store.InvQuery) (*store.InvPage, error) {
+	log.Tracef("InvQuery: %+v", q)
+
+	if t.inv == nil {
+		return nil, errInvNotSupported
+	}
+	return t.inv.InvQuery(q)
+}
+
+// InventoryAttributesSet sets inventory attributes for a record. The
+// attributes can be used to filter inventory queries. The attribute keys are
+// namespaced using the plugin ID, i.e. the key "votestatus" that is set by the
+// ticketvote plugin is saved as "ticketvote.votestatus". An empty attribute
+// value deletes the attribute.
+//
+// This function satisfies the plugins TstoreClient interface.
+func (t *Tstore) InventoryAttributesSet(token []byte, pluginID string, attrs map[string]string) error {
+	log.Tracef("InventoryAttributesSet: %x %v %v", token, pluginID, attrs)
+
+	if t.inv == nil {
+		return errInvNotSupported
+	}
+	a := make(map[string]string, len(attrs))
+	for k, v := range attrs {
+		a[pluginID+"."+k] = v
+	}
+	err := store.InvAttributesVerify(a)
+	if err != nil {
+		return err
+	}
+	return t.inv.InvAttributesSet(hex.EncodeToString(token), a)
+}
+
+// RecordCreated returns the timestamp of the first record metadata that was
+// saved to the record, i.e. the timestamp of when the record was created.
+func (t *Tstore) RecordCreated(token []byte) (int64, error) {
+	log.Tracef("RecordCreated: %x", token)
+
+	treeID := treeIDFromToken(token)
+	leaves, err := t.leavesAll(treeID)
+	if err != nil {
+		return 0, err
+	}
+	for _, v := range leaves {
+		ed, err := extraDataDecode(v.ExtraData)
+		if err != nil {
+			return 0, err
+		}
+		if ed.Desc != dataDescriptorRecordMetadata {
+			continue
+		}
+
+		// The record metadata of an unvetted record may only exist as
+		// plain text once the record has been made public.
+		keys := []string{ed.storeKey(), ed.storeKeyNoPrefix()}
+		blobs, err := t.store.Get(keys)
+		if err != nil {
+			return 0, fmt.Errorf("store Get: %v", err)
+		}
+		b, ok := blobs[ed.storeKey()]
+		if !ok {
+			b, ok = blobs[ed.storeKeyNoPrefix()]
+		}
+		if !ok {
+			return 0, fmt.Errorf("record metadata blob not found %v",
+				ed.storeKey())
+		}
+		be, err := store.Deblob(b)
+		if err != nil {
+			return 0, err
+		}
+		data, err := blobEntryData(*be)
+		if err != nil {
+			return 0, err
+		}
+		var rm backend.RecordMetadata
+		err = json.Unmarshal(data, &rm)
+		if err != nil {
+			return 0, err
+		}
+		return rm.Timestamp, nil
+	}
+
+	return 0, backend.ErrRecordNotFound
+}
@@ -0,0 +1,405 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tstore
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	dcrtime "github.com/decred/dcrtime/api/v2"
+	"github.com/decred/dcrtime/merkle"
+	"github.com/decred/politeia/politeiad/api/v1/identity"
+	"github.com/decred/politeia/util"
+)
+
+const (
+	// localAnchorDirname is the name of the directory that contains
+	// the local anchor log and identity. It is located in the tstore
+	// data directory.
+	localAnchorDirname = "localanchor"
+
+	// localAnchorLogFilename is the filename of the local anchor log.
+	localAnchorLogFilename = "anchors.log"
+
+	// localAnchorIdentityFilename is the filename of the identity that
+	// is used to sign the local anchor log entries.
+	localAnchorIdentityFilename = "identity.json"
+)
+
+var (
+	_ anchorClient = (*localAnchorClient)(nil)
+)
+
+// localAnchor is an entry in the local anchor log. Each entry anchors a batch
+// of digests by committing to the merkle root of the batch. Entries are
+// chained together by including the digest of the previous entry and are
+// signed using the local anchor identity, making the log tamper evident.
+type localAnchor struct {
+	Height     uint64   `json:"height"`     // Index of entry in the log
+	Previous   string   `json:"previous"`   // Digest of previous entry
+	MerkleRoot string   `json:"merkleroot"` // Merkle root of digests
+	Digests    []string `json:"digests"`    // Anchored digests
+	Timestamp  int64    `json:"timestamp"`  // Unix timestamp of anchor
+	Signature  string   `json:"signature"`  // Signature of entry digest
+}
+
+// digest returns the SHA256 digest of the entry. The digest commits to all
+// entry fields except for the signature. The anchored digests are committed
+// to using the merkle root.
+func (a *localAnchor) digest() []byte {
+	msg := fmt.Sprintf("%v:%v:%v:%v", a.Height, a.Previous,
+		a.MerkleRoot, a.Timestamp)
+	return util.Digest([]byte(msg))
+}
+
+// localAnchorClient is an anchor client that aggregates the tree root hashes
+// into a local, signed, append only anchor log instead of timestamping them
+// onto the decred blockchain. Digests are anchored as soon as they are
+// submitted.
+//
+// The anchor records and timestamp proofs use the dcrtime format. The
+// Transaction of an anchored digest is set to the digest of the log entry and
+// the ChainTimestamp is set to the time that the entry was appended to the
+// log.
+//
+// localAnchorClient satisfies the anchorClient interface.
+type localAnchorClient struct {
+	sync.Mutex
+	path     string // Anchor log filepath
+	identity *identity.FullIdentity
+	anchors  []localAnchor
+	digests  map[string]int // [digest]anchorIndex
+}
+
+// leavesFromDigests decodes the hex encoded digests into merkle leaves.
+func leavesFromDigests(digests []string) ([]*[sha256.Size]byte, error) {
+	leaves := make([]*[sha256.Size]byte, 0, len(digests))
+	for _, v := range digests {
+		b, err := hex.DecodeString(v)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) != sha256.Size {
+			return nil, fmt.Errorf("invalid digest: %v", v)
+		}
+		var d [sha256.Size]byte
+		copy(d[:], b)
+		leaves = append(leaves, &d)
+	}
+	return leaves, nil
+}
+
+// TimestampBatch appends an entry to the anchor log for the provided digests.
+// Digests that have already been anchored are returned with a
+// ResultExistsError.
+//
+// This function satisfies the anchorClient interface.
+func (c *localAnchorClient) TimestampBatch(id string, digests []string) (*dcrtime.TimestampBatchReply, error) {
+	log.Tracef("Local TimestampBatch: %v %v", id, digests)
+
+	for _, v := range digests {
+		if !isDigestSHA256(v) {
+			return nil, fmt.Errorf("invalid digest: %v", v)
+		}
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	// Sort out the digests that have already been anchored
+	var (
+		tbr = dcrtime.TimestampBatchReply{
+			ID:              id,
+			ServerTimestamp: time.Now().Unix(),
+			Digests:         digests,
+		}
+		batch = make([]string, 0, len(digests))
+		seen  = make(map[string]struct{}, len(digests))
+	)
+	for _, v := range digests {
+		_, ok := c.digests[v]
+		if !ok {
+			_, ok = seen[v]
+		}
+		if ok {
+			tbr.Results = append(tbr.Results, dcrtime.ResultExistsError)
+			continue
+		}
+		seen[v] = struct{}{}
+		batch = append(batch, v)
+		tbr.Results = append(tbr.Results, dcrtime.ResultOK)
+	}
+
+	if len(batch) > 0 {
+		err := c.append(batch, tbr.ServerTimestamp)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &tbr, nil
+}
+
+// VerifyBatch returns the anchor data of the provided digests. Digests that
+// have not been anchored are returned with a ResultDoesntExistError.
+//
+// This function satisfies the anchorClient interface.
+func (c *localAnchorClient) VerifyBatch(id string, digests []string) (*dcrtime.VerifyBatchReply, error) {
+	log.Tracef("Local VerifyBatch: %v %v", id, digests)
+
+	for _, v := range digests {
+		if !isDigestSHA256(v) {
+			return nil, fmt.Errorf("invalid digest: %v", v)
+		}
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	vds := make([]dcrtime.VerifyDigest, 0, len(digests))
+	for _, v := range digests {
+		i, ok := c.digests[v]
+		if !ok {
+			vds = append(vds, dcrtime.VerifyDigest{
+				Digest: v,
+				Result: dcrtime.ResultDoesntExistError,
+			})
+			continue
+		}
+		a := c.anchors[i]
+
+		// Build the merkle path of the digest
+		leaves, err := leavesFromDigests(a.Digests)
+		if err != nil {
+			return nil, err
+		}
+		d := leaves[0]
+		for _, l := range leaves {
+			if hex.EncodeToString(l[:]) == v {
+				d = l
+				break
+			}
+		}
+		merkle.Tree(leaves) // Sorts the leaves
+		path := merkle.AuthPath(leaves, d)
+		root, err := merkle.VerifyAuthPath(path)
+		if err != nil {
+			return nil, fmt.Errorf("VerifyAuthPath %v: %v", v, err)
+		}
+		if hex.EncodeToString(root[:]) != a.MerkleRoot {
+			return nil, fmt.Errorf("invalid merkle root %v: got %x, want %v",
+				v, root[:], a.MerkleRoot)
+		}
+
+		vds = append(vds, dcrtime.VerifyDigest{
+			Digest:          v,
+			ServerTimestamp: a.Timestamp,
+			Result:          dcrtime.ResultOK,
+			ChainInformation: dcrtime.ChainInformation{
+				ChainTimestamp: a.Timestamp,
+				Transaction:    hex.EncodeToString(a.digest()),
+				MerkleRoot:     a.MerkleRoot,
+				MerklePath:     *path,
+			},
+		})
+	}
+
+	return &dcrtime.VerifyBatchReply{
+		ID:      id,
+		Digests: vds,
+	}, nil
+}
+
+// append appends a new entry for the provided digests to the anchor log. The
+// entry is written to disk before the in-memory state is updated.
+//
+// This function must be called WITH the lock held.
+func (c *localAnchorClient) append(digests []string, timestamp int64) error {
+	leaves, err := leavesFromDigests(digests)
+	if err != nil {
+		return err
+	}
+	a := localAnchor{
+		Height:     uint64(len(c.anchors)),
+		MerkleRoot: hex.EncodeToString(merkle.Root(leaves)[:]),
+		Digests:    digests,
+		Timestamp:  timestamp,
+	}
+	if len(c.anchors) > 0 {
+		prev := c.anchors[len(c.anchors)-1]
+		a.Previous = hex.EncodeToString(prev.digest())
+	}
+	sig := c.identity.SignMessage(a.digest())
+	a.Signature = hex.EncodeToString(sig[:])
+
+	// Append the entry to the log file
+	b, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(c.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(b, '\n'))
+	if err == nil {
+		err = f.Sync()
+	}
+	if err2 := f.Close(); err == nil {
+		err = err2
+	}
+	if err != nil {
+		return fmt.Errorf("write anchor log: %v", err)
+	}
+
+	// Update the in-memory state
+	c.anchors = append(c.anchors, a)
+	for _, v := range digests {
+		c.digests[v] = len(c.anchors) - 1
+	}
+
+	log.Debugf("Local anchor %v appended for %v digests", a.Height, len(digests))
+
+	return nil
+}
+
+// verifyAnchor verifies that an anchor log entry is coherent and correctly
+// chained onto the previous entry. The previous entry is nil for the first
+// entry in the log.
+func verifyAnchor(id identity.PublicIdentity, a, prev *localAnchor) error {
+	// Verify the chain
+	var (
+		height   uint64
+		previous string
+	)
+	if prev != nil {
+		height = prev.Height + 1
+		previous = hex.EncodeToString(prev.digest())
+	}
+	if a.Height != height {
+		return fmt.Errorf("invalid height: got %v, want %v", a.Height, height)
+	}
+	if a.Previous != previous {
+		return fmt.Errorf("invalid previous digest: got %v, want %v",
+			a.Previous, previous)
+	}
+
+	// Verify the merkle root
+	if len(a.Digests) == 0 {
+		return fmt.Errorf("no digests")
+	}
+	leaves, err := leavesFromDigests(a.Digests)
+	if err != nil {
+		return err
+	}
+	root := merkle.Root(leaves)
+	if hex.EncodeToString(root[:]) != a.MerkleRoot {
+		return fmt.Errorf("invalid merkle root: got %x, want %v",
+			root[:], a.MerkleRoot)
+	}
+
+	// Verify the signature
+	sig, err := identity.SignatureFromString(a.Signature)
+	if err != nil {
+		return err
+	}
+	if !id.VerifyMessage(a.digest(), *sig) {
+		return fmt.Errorf("invalid signature")
+	}
+
+	return nil
+}
+
+// load reads the anchor log from disk and verifies the integrity of all
+// entries. An error is returned if any of the entries are invalid.
+func (c *localAnchorClient) load() error {
+	f, err := os.Open(c.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			// The anchor log has not been created yet
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	d := json.NewDecoder(f)
+	for {
+		var a localAnchor
+		err := d.Decode(&a)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return fmt.Errorf("decode anchor %v: %v", len(c.anchors), err)
+		}
+		var prev *localAnchor
+		if len(c.anchors) > 0 {
+			prev = &c.anchors[len(c.anchors)-1]
+		}
+		err = verifyAnchor(c.identity.Public, &a, prev)
+		if err != nil {
+			return fmt.Errorf("anchor %v: %v", len(c.anchors), err)
+		}
+		c.anchors = append(c.anchors, a)
+		for _, v := range a.Digests {
+			c.digests[v] = len(c.anchors) - 1
+		}
+	}
+
+	return nil
+}
+
+// newLocalAnchorClient returns a new localAnchorClient. The anchor log and the
+// identity that is used to sign the log entries are created in the provided
+// directory if they do not exist yet.
+func newLocalAnchorClient(dir string) (*localAnchorClient, error) {
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+
+	// Load the anchor identity. A new identity is created if one does
+	// not exist yet.
+	fp := filepath.Join(dir, localAnchorIdentityFilename)
+	id, err := identity.LoadFullIdentity(fp)
+	if err != nil {
+		if util.FileExists(fp) {
+			return nil, fmt.Errorf("load identity: %v", err)
+		}
+		id, err = identity.New()
+		if err != nil {
+			return nil, err
+		}
+		err = id.Save(fp)
+		if err != nil {
+			return nil, fmt.Errorf("save identity: %v", err)
+		}
+		log.Infof("Local anchor identity created: %v", fp)
+	}
+
+	c := localAnchorClient{
+		path:     filepath.Join(dir, localAnchorLogFilename),
+		identity: id,
+		anchors:  make([]localAnchor, 0, 1024),
+		digests:  make(map[string]int),
+	}
+	err = c.load()
+	if err != nil {
+		return nil, fmt.Errorf("load anchor log: %v", err)
+	}
+
+	log.Infof("Local anchor log: %v anchors", len(c.anchors))
+	log.Infof("Local anchor public key: %x", id.Public.Key[:])
+
+	return &c, nil
+}
@@ -0,0 +1,218 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tstore
+
+import (
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	dcrtime "github.com/decred/dcrtime/api/v2"
+	"github.com/decred/dcrtime/merkle"
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/util"
+	"github.com/google/trillian/types"
+)
+
+func TestLocalAnchorClient(t *testing.T) {
+	dir, err := ioutil.TempDir("", "localanchor.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	c, err := newLocalAnchorClient(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	digest := func(s string) string {
+		return hex.EncodeToString(util.Digest([]byte(s)))
+	}
+	var (
+		d1 = digest("one")
+		d2 = digest("two")
+		d3 = digest("three")
+		d4 = digest("four")
+	)
+
+	// Anchor a batch of digests
+	tbr, err := c.TimestampBatch(anchorID, []string{d1, d2, d3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range tbr.Results {
+		if v != dcrtime.ResultOK {
+			t.Fatalf("digest %v: got result %v, want ok", i, v)
+		}
+	}
+
+	// Digests that have already been anchored are not anchored again
+	tbr, err = c.TimestampBatch(anchorID, []string{d1, d4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tbr.Results[0] != dcrtime.ResultExistsError ||
+		tbr.Results[1] != dcrtime.ResultOK {
+		t.Fatalf("got results %v, want exists and ok", tbr.Results)
+	}
+
+	// Verify the anchored digests. The merkle path of each digest
+	// must resolve to the merkle root of its anchor.
+	unknown := digest("unknown")
+	vbr, err := c.VerifyBatch(anchorID, []string{d1, d2, d3, d4, unknown})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range vbr.Digests[:4] {
+		if v.Result != dcrtime.ResultOK {
+			t.Fatalf("digest %v: got result %v, want ok", v.Digest, v.Result)
+		}
+		ci := v.ChainInformation
+		if ci.ChainTimestamp == 0 || ci.Transaction == "" {
+			t.Errorf("digest %v: anchor not dropped", v.Digest)
+		}
+		root, err := merkle.VerifyAuthPath(&ci.MerklePath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(root[:]) != ci.MerkleRoot {
+			t.Errorf("digest %v: invalid merkle root", v.Digest)
+		}
+		var found bool
+		for _, h := range ci.MerklePath.Hashes {
+			if hex.EncodeToString(h[:]) == v.Digest {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("digest %v: not found in merkle path", v.Digest)
+		}
+	}
+	if vbr.Digests[0].ChainInformation.Transaction ==
+		vbr.Digests[3].ChainInformation.Transaction {
+		t.Errorf("digests of different batches have the same anchor")
+	}
+	if vbr.Digests[4].Result != dcrtime.ResultDoesntExistError {
+		t.Errorf("got result %v for an unknown digest, want doesn't exist",
+			vbr.Digests[4].Result)
+	}
+
+	// The anchor log is reloaded from disk
+	c, err = newLocalAnchorClient(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.anchors) != 2 || len(c.digests) != 4 {
+		t.Fatalf("got %v anchors and %v digests, want 2 and 4",
+			len(c.anchors), len(c.digests))
+	}
+
+	// A modified anchor log is rejected
+	fp := filepath.Join(dir, localAnchorLogFilename)
+	b, err := ioutil.ReadFile(fp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b = []byte(strings.Replace(string(b), d2, unknown, 1))
+	err = ioutil.WriteFile(fp, b, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = newLocalAnchorClient(dir)
+	if err == nil {
+		t.Fatalf("modified anchor log was loaded")
+	}
+}
+
+func TestAnchorWait(t *testing.T) {
+	dir, err := ioutil.TempDir("", "anchorwait.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ts := NewTestTstore(t, dir)
+	ts.anchor, err = newLocalAnchorClient(filepath.Join(dir,
+		localAnchorDirname))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts.anchorVerifyPeriod = 10 * time.Millisecond
+
+	// Setup the anchors of two trees. The log roots are made up since
+	// the test tlog client does not provide them.
+	var (
+		anchors = make([]anchor, 0, 2)
+		digests = make([]string, 0, 2)
+	)
+	for i := 0; i < 2; i++ {
+		tree, _, err := ts.tlog.TreeNew()
+		if err != nil {
+			t.Fatal(err)
+		}
+		lr := &types.LogRootV1{
+			TreeSize: 1,
+			RootHash: util.Digest([]byte{byte(i)}),
+		}
+		anchors = append(anchors, anchor{
+			TreeID:  tree.TreeId,
+			LogRoot: lr,
+		})
+		digests = append(digests, hex.EncodeToString(lr.RootHash))
+	}
+
+	// Drop the anchor
+	_, err = ts.anchor.TimestampBatch(anchorID, digests)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := ts.anchorDropNew(len(anchors))
+	ts.anchorWait(anchors, digests, d)
+
+	// Verify the anchor drop status
+	as, err := ts.AnchorStatus()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(as.Drops) != 1 {
+		t.Fatalf("got %v anchor drops, want 1", len(as.Drops))
+	}
+	drop := as.Drops[0]
+	if drop.Status != backend.AnchorDropConfirmed {
+		t.Errorf("got drop status %v, want %v",
+			backend.AnchorDrops[drop.Status],
+			backend.AnchorDrops[backend.AnchorDropConfirmed])
+	}
+	if drop.Anchored != 2 || len(drop.TxIDs) != 1 ||
+		len(drop.MerkleRoots) != 1 {
+		t.Errorf("got %v anchored trees, %v txs and %v merkle roots, "+
+			"want 2, 1 and 1", drop.Anchored, len(drop.TxIDs),
+			len(drop.MerkleRoots))
+	}
+	if as.Dropping {
+		t.Errorf("anchor drop still in progress")
+	}
+
+	// Verify the anchor records were saved
+	for _, v := range anchors {
+		a, err := ts.anchorLatest(v.TreeID)
+		if err != nil {
+			t.Fatalf("anchorLatest %v: %v", v.TreeID, err)
+		}
+		vd := a.VerifyDigest
+		if vd == nil || vd.Digest != hex.EncodeToString(v.LogRoot.RootHash) {
+			t.Errorf("tree %v: invalid anchor record", v.TreeID)
+			continue
+		}
+		if vd.ChainInformation.ChainTimestamp == 0 {
+			t.Errorf("tree %v: chain timestamp not set", v.TreeID)
+		}
+	}
+}
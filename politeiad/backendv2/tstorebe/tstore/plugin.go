@@ -14,12 +14,15 @@ import (
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins/comments"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins/dcrdata"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins/external"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins/pi"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins/search"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins/ticketvote"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins/usermd"
 	cmplugin "github.com/decred/politeia/politeiad/plugins/comments"
 	ddplugin "github.com/decred/politeia/politeiad/plugins/dcrdata"
 	piplugin "github.com/decred/politeia/politeiad/plugins/pi"
+	srplugin "github.com/decred/politeia/politeiad/plugins/search"
 	tkplugin "github.com/decred/politeia/politeiad/plugins/ticketvote"
 	umplugin "github.com/decred/politeia/politeiad/plugins/usermd"
 )
@@ -35,6 +38,10 @@ const (
 type plugin struct {
 	id     string
 	client plugins.PluginClient
+
+	// settings contains the settings that the plugin was registered
+	// with, i.e. the plugin settings from the politeiad config.
+	settings []backend.PluginSetting
 }
 
 // plugin returns the specified plugin. Only plugins that have been registered
@@ -76,29 +83,40 @@ func (t *Tstore) PluginRegister(b backend.Backend, p backend.Plugin) error {
 
 		dataDir = filepath.Join(t.dataDir, pluginDataDirname)
 	)
-	switch p.ID {
-	case cmplugin.PluginID:
+	switch {
+	case p.Path != "":
+		// The plugin runs in a separate process
+		client, err = external.New(t, p.ID, p.Path, p.Settings, dataDir)
+		if err != nil {
+			return err
+		}
+	case p.ID == cmplugin.PluginID:
 		client, err = comments.New(t, p.Settings, dataDir, p.Identity)
 		if err != nil {
 			return err
 		}
-	case ddplugin.PluginID:
+	case p.ID == ddplugin.PluginID:
 		client, err = dcrdata.New(p.Settings, t.activeNetParams)
 		if err != nil {
 			return err
 		}
-	case piplugin.PluginID:
+	case p.ID == piplugin.PluginID:
 		client, err = pi.New(b, p.Settings, dataDir)
 		if err != nil {
 			return err
 		}
-	case tkplugin.PluginID:
+	case p.ID == srplugin.PluginID:
+		client, err = search.New(b, t, p.Settings, dataDir)
+		if err != nil {
+			return err
+		}
+	case p.ID == tkplugin.PluginID:
 		client, err = ticketvote.New(b, t, p.Settings, dataDir,
 			p.Identity, t.activeNetParams)
 		if err != nil {
 			return err
 		}
-	case umplugin.PluginID:
+	case p.ID == umplugin.PluginID:
 		client, err = usermd.New(t, p.Settings, dataDir)
 		if err != nil {
 			return err
@@ -107,13 +125,21 @@ func (t *Tstore) PluginRegister(b backend.Backend, p backend.Plugin) error {
 		return backend.ErrPluginIDInvalid
 	}
 
+	// Apply any plugin settings that were updated at runtime
+	pl := plugin{
+		id:       p.ID,
+		client:   client,
+		settings: p.Settings,
+	}
+	err = t.pluginSettingsReplay(pl)
+	if err != nil {
+		return fmt.Errorf("plugin settings replay: %v", err)
+	}
+
 	t.Lock()
 	defer t.Unlock()
 
-	t.plugins[p.ID] = plugin{
-		id:     p.ID,
-		client: client,
-	}
+	t.plugins[p.ID] = pl
 
 	return nil
 }
@@ -0,0 +1,294 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+)
+
+const (
+	// pluginSettingsKeyPrefix is the prefix of the kv store key that
+	// contains the plugin settings audit trail of a plugin. The plugin
+	// ID is appended onto the prefix.
+	pluginSettingsKeyPrefix = "pluginsettings-"
+)
+
+// pluginSettingsChange is the kv store representation of a runtime plugin
+// settings update.
+//
+// Config contains the config values of the updated settings at the time of
+// the update. A setting that was not set in the config is not included. It is
+// used to detect config changes that were made after the update.
+type pluginSettingsChange struct {
+	Settings  []backend.PluginSetting `json:"settings"`
+	Previous  []backend.PluginSetting `json:"previous"`
+	Config    map[string]string       `json:"config"`
+	Reason    string                  `json:"reason"`
+	User      string                  `json:"user"`
+	Timestamp int64                   `json:"timestamp"`
+}
+
+// pluginSettingsKey returns the kv store key for the plugin settings audit
+// trail of a plugin.
+func pluginSettingsKey(pluginID string) string {
+	return pluginSettingsKeyPrefix + pluginID
+}
+
+// pluginSettingsChanges returns the runtime plugin settings updates of a
+// plugin, ordered from oldest to newest.
+func (t *Tstore) pluginSettingsChanges(pluginID string) ([]pluginSettingsChange, error) {
+	key := pluginSettingsKey(pluginID)
+	blobs, err := t.store.Get([]string{key})
+	if err != nil {
+		return nil, fmt.Errorf("get: %v", err)
+	}
+	b, ok := blobs[key]
+	if !ok {
+		// No updates have been made
+		return []pluginSettingsChange{}, nil
+	}
+	var changes []pluginSettingsChange
+	err = json.Unmarshal(b, &changes)
+	if err != nil {
+		return nil, err
+	}
+	return changes, nil
+}
+
+// pluginSettingsSave saves the audit trail of the runtime plugin settings
+// updates of a plugin.
+func (t *Tstore) pluginSettingsSave(pluginID string, changes []pluginSettingsChange) error {
+	b, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+	err = t.store.Put(map[string][]byte{pluginSettingsKey(pluginID): b}, false)
+	if err != nil {
+		return fmt.Errorf("put: %v", err)
+	}
+	return nil
+}
+
+// settingsMap returns the provided plugin settings as a map.
+func settingsMap(settings []backend.PluginSetting) map[string]string {
+	m := make(map[string]string, len(settings))
+	for _, v := range settings {
+		m[v.Key] = v.Value
+	}
+	return m
+}
+
+// pluginSettingsReplay applies the runtime plugin settings updates that were
+// made prior to politeiad being restarted.
+//
+// A runtime update takes precedence over the config value that the plugin was
+// registered with, unless the config value was changed after the runtime
+// update was made. A config change made after the last runtime update of a
+// setting is treated as authoritative and the runtime value of that setting is
+// not applied. Every setting where a runtime value overrides the config value
+// is logged. An update that is no longer accepted by the plugin is skipped.
+func (t *Tstore) pluginSettingsReplay(p plugin) error {
+	changes, err := t.pluginSettingsChanges(p.id)
+	if err != nil {
+		return err
+	}
+
+	// Find the settings whose config value has changed since the last
+	// runtime update of the setting. The config value is used for
+	// these settings.
+	var (
+		config  = settingsMap(p.settings)
+		last    = make(map[string]pluginSettingsChange)
+		skipped = make(map[string]struct{})
+		applied = make(map[string]pluginSettingsChange)
+		count   int
+	)
+	for _, v := range changes {
+		for _, s := range v.Settings {
+			last[s.Key] = v
+		}
+	}
+	for key, v := range last {
+		if v.Config == nil {
+			// The config values were not recorded
+			continue
+		}
+		prior, ok := v.Config[key]
+		current, currentOK := config[key]
+		if ok != currentOK || prior != current {
+			log.Infof("Plugin %v setting %v: config value changed after "+
+				"the runtime update from %v; config value used",
+				p.id, key, time.Unix(v.Timestamp, 0))
+			skipped[key] = struct{}{}
+		}
+	}
+
+	// Apply the updates
+	for _, v := range changes {
+		settings := make([]backend.PluginSetting, 0, len(v.Settings))
+		for _, s := range v.Settings {
+			if _, ok := skipped[s.Key]; ok {
+				continue
+			}
+			settings = append(settings, s)
+		}
+		if len(settings) == 0 {
+			continue
+		}
+
+		err := p.client.SettingsUpdate(settings)
+		if err != nil {
+			log.Errorf("Plugin %v settings update from %v could not be "+
+				"applied: %v", p.id, time.Unix(v.Timestamp, 0), err)
+			continue
+		}
+		for _, s := range settings {
+			applied[s.Key] = v
+		}
+		count++
+	}
+
+	// Log the settings where a runtime value overrides the config
+	values := settingsMap(p.client.Settings())
+	for key, v := range applied {
+		current, ok := config[key]
+		switch {
+		case ok && current == values[key]:
+			// The runtime value matches the config value
+			continue
+		case !ok:
+			current = "(not set)"
+		}
+		log.Infof("Plugin %v setting %v: config value %v overridden by "+
+			"runtime value %v from %v by %v", p.id, key, current,
+			values[key], time.Unix(v.Timestamp, 0), v.User)
+	}
+	if count > 0 {
+		log.Infof("Plugin %v: %v runtime settings updates applied",
+			p.id, count)
+	}
+
+	return nil
+}
+
+// PluginSettingsUpdate updates the settings of a registered plugin at runtime.
+// The plugin validates the new settings. The update is appended onto the
+// plugin settings audit trail, which is saved to the kv store, and is applied
+// again when politeiad is restarted. The user is the name of the RPC
+// credential that made the update.
+//
+// The update is saved to the audit trail before it is applied. The audit
+// trail is restored if the plugin rejects the update. Restoring the audit
+// trail is always possible, where restoring the plugin settings is not, since
+// a plugin cannot unset a setting that did not have a value prior to the
+// update.
+func (t *Tstore) PluginSettingsUpdate(pluginID string, settings []backend.PluginSetting, reason, user string) (*backend.Plugin, error) {
+	log.Tracef("PluginSettingsUpdate: %v %v %v", pluginID, settings, user)
+
+	p, ok := t.plugin(pluginID)
+	if !ok {
+		return nil, backend.ErrPluginIDInvalid
+	}
+
+	// Updates are applied one at a time so that the audit trail
+	// reflects the order that they were applied in.
+	t.mtxPluginSettings.Lock()
+	defer t.mtxPluginSettings.Unlock()
+
+	// Get the existing values of the updated settings
+	current := make(map[string]string)
+	for _, v := range p.client.Settings() {
+		current[v.Key] = v.Value
+	}
+	var (
+		config   = settingsMap(p.settings)
+		previous = make([]backend.PluginSetting, 0, len(settings))
+		configs  = make(map[string]string, len(settings))
+	)
+	for _, v := range settings {
+		if value, ok := config[v.Key]; ok {
+			configs[v.Key] = value
+		}
+		value, ok := current[v.Key]
+		if !ok {
+			continue
+		}
+		previous = append(previous, backend.PluginSetting{
+			Key:   v.Key,
+			Value: value,
+		})
+	}
+
+	// Save the update to the audit trail prior to applying it so that
+	// the applied settings always match the audit trail.
+	changes, err := t.pluginSettingsChanges(pluginID)
+	if err != nil {
+		return nil, err
+	}
+	c := pluginSettingsChange{
+		Settings:  settings,
+		Previous:  previous,
+		Config:    configs,
+		Reason:    reason,
+		User:      user,
+		Timestamp: time.Now().Unix(),
+	}
+	err = t.pluginSettingsSave(pluginID, append(changes, c))
+	if err != nil {
+		return nil, err
+	}
+
+	// Update the plugin settings. The audit trail is restored if the
+	// plugin rejects the update.
+	err = p.client.SettingsUpdate(settings)
+	if err != nil {
+		if err2 := t.pluginSettingsSave(pluginID, changes); err2 != nil {
+			// The update will be retried and rejected again on
+			// startup.
+			log.Criticalf("Plugin %v settings audit trail could not be "+
+				"restored: %v", pluginID, err2)
+		}
+		return nil, err
+	}
+
+	log.Infof("Plugin %v settings updated by %v: %v", pluginID, user, settings)
+
+	return &backend.Plugin{
+		ID:       pluginID,
+		Settings: p.client.Settings(),
+	}, nil
+}
+
+// PluginSettingsHistory returns the audit trail of the runtime plugin settings
+// updates of a plugin, ordered from oldest to newest.
+func (t *Tstore) PluginSettingsHistory(pluginID string) ([]backend.PluginSettingsChange, error) {
+	log.Tracef("PluginSettingsHistory: %v", pluginID)
+
+	if _, ok := t.plugin(pluginID); !ok {
+		return nil, backend.ErrPluginIDInvalid
+	}
+
+	changes, err := t.pluginSettingsChanges(pluginID)
+	if err != nil {
+		return nil, err
+	}
+	history := make([]backend.PluginSettingsChange, 0, len(changes))
+	for _, v := range changes {
+		history = append(history, backend.PluginSettingsChange{
+			PluginID:  pluginID,
+			Settings:  v.Settings,
+			Previous:  v.Previous,
+			Reason:    v.Reason,
+			User:      v.User,
+			Timestamp: v.Timestamp,
+		})
+	}
+
+	return history, nil
+}
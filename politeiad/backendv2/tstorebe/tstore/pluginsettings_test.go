@@ -0,0 +1,347 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tstore
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+	cmplugin "github.com/decred/politeia/politeiad/plugins/comments"
+)
+
+var (
+	_ plugins.PluginClient = (*testSettingsPlugin)(nil)
+)
+
+// testSettingsPlugin is a plugin whose settings do not have default values.
+// A setting only has a value once it has been set. The "invalid" setting is
+// rejected.
+type testSettingsPlugin struct {
+	settings map[string]string
+}
+
+func (p *testSettingsPlugin) Setup() error { return nil }
+
+func (p *testSettingsPlugin) Cmd(token []byte, cmd, payload string) (string, error) {
+	return "", nil
+}
+
+func (p *testSettingsPlugin) Hook(h plugins.HookT, payload string) error { return nil }
+
+func (p *testSettingsPlugin) Fsck() error { return nil }
+
+func (p *testSettingsPlugin) CacheRebuild(tokens [][]byte, progress func()) error {
+	return nil
+}
+
+func (p *testSettingsPlugin) Settings() []backend.PluginSetting {
+	settings := make([]backend.PluginSetting, 0, len(p.settings))
+	for k, v := range p.settings {
+		settings = append(settings, backend.PluginSetting{Key: k, Value: v})
+	}
+	sort.Slice(settings, func(i, j int) bool {
+		return settings[i].Key < settings[j].Key
+	})
+	return settings
+}
+
+func (p *testSettingsPlugin) SettingsUpdate(settings []backend.PluginSetting) error {
+	for _, v := range settings {
+		if v.Key == "invalid" {
+			return backend.PluginSettingError{
+				PluginID: "test",
+				Key:      v.Key,
+				Value:    v.Value,
+				Reason:   "invalid setting",
+			}
+		}
+	}
+	for _, v := range settings {
+		p.settings[v.Key] = v.Value
+	}
+	return nil
+}
+
+// failPutStore is a BlobKV that fails all puts.
+type failPutStore struct {
+	store.BlobKV
+}
+
+func (s *failPutStore) Put(blobs map[string][]byte, encrypt bool) error {
+	return fmt.Errorf("put failed")
+}
+
+// pluginSetting returns the value of the provided plugin setting.
+func pluginSetting(t *testing.T, ts *Tstore, pluginID, key string) string {
+	t.Helper()
+
+	p, ok := ts.plugin(pluginID)
+	if !ok {
+		t.Fatalf("plugin %v not registered", pluginID)
+	}
+	for _, v := range p.client.Settings() {
+		if v.Key == key {
+			return v.Value
+		}
+	}
+	t.Fatalf("plugin %v setting %v not found", pluginID, key)
+	return ""
+}
+
+func TestPluginSettingsUpdate(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tstore.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ts := newTestTstoreAt(t, dir)
+	err = ts.PluginRegister(nil, backend.Plugin{ID: cmplugin.PluginID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Unregistered plugins are rejected
+	_, err = ts.PluginSettingsUpdate("unknown", nil, "", "admin")
+	if !errors.Is(err, backend.ErrPluginIDInvalid) {
+		t.Fatalf("got error %v, want %v", err, backend.ErrPluginIDInvalid)
+	}
+	_, err = ts.PluginSettingsHistory("unknown")
+	if !errors.Is(err, backend.ErrPluginIDInvalid) {
+		t.Fatalf("got error %v, want %v", err, backend.ErrPluginIDInvalid)
+	}
+
+	// No updates have been made
+	history, err := ts.PluginSettingsHistory(cmplugin.PluginID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 0 {
+		t.Fatalf("got %v updates, want 0", len(history))
+	}
+
+	// Apply two updates
+	var (
+		key     = cmplugin.SettingKeyCommentLengthMax
+		initial = strconv.FormatUint(uint64(cmplugin.SettingCommentLengthMax), 10)
+		updates = []backend.PluginSetting{
+			{Key: key, Value: "100"},
+			{Key: key, Value: "200"},
+		}
+	)
+	for i, v := range updates {
+		p, err := ts.PluginSettingsUpdate(cmplugin.PluginID,
+			[]backend.PluginSetting{v}, "reason "+strconv.Itoa(i),
+			"user"+strconv.Itoa(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if p.ID != cmplugin.PluginID {
+			t.Fatalf("got plugin %v, want %v", p.ID, cmplugin.PluginID)
+		}
+	}
+
+	// A rejected update is not applied and is not added to the audit
+	// trail.
+	_, err = ts.PluginSettingsUpdate(cmplugin.PluginID,
+		[]backend.PluginSetting{{Key: key, Value: "0"}}, "rejected",
+		"admin")
+	var e backend.PluginSettingError
+	if !errors.As(err, &e) {
+		t.Fatalf("got error %v, want PluginSettingError", err)
+	}
+	if v := pluginSetting(t, ts, cmplugin.PluginID, key); v != "200" {
+		t.Fatalf("got setting %v, want 200", v)
+	}
+
+	// Verify the audit trail. The updates are ordered from oldest to
+	// newest and contain the values that they replaced and the user
+	// that made them.
+	history, err = ts.PluginSettingsHistory(cmplugin.PluginID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != len(updates) {
+		t.Fatalf("got %v updates, want %v", len(history), len(updates))
+	}
+	previous := []string{initial, "100"}
+	for i, v := range history {
+		switch {
+		case v.PluginID != cmplugin.PluginID:
+			t.Fatalf("update %v: got plugin %v", i, v.PluginID)
+		case !reflect.DeepEqual(v.Settings, updates[i:i+1]):
+			t.Fatalf("update %v: got settings %v, want %v",
+				i, v.Settings, updates[i:i+1])
+		case len(v.Previous) != 1 || v.Previous[0].Key != key ||
+			v.Previous[0].Value != previous[i]:
+			t.Fatalf("update %v: got previous %v, want %v %v",
+				i, v.Previous, key, previous[i])
+		case v.Reason != "reason "+strconv.Itoa(i):
+			t.Fatalf("update %v: got reason '%v'", i, v.Reason)
+		case v.User != "user"+strconv.Itoa(i):
+			t.Fatalf("update %v: got user '%v'", i, v.User)
+		case v.Timestamp == 0:
+			t.Fatalf("update %v: timestamp not set", i)
+		}
+	}
+
+	// The updates are applied again when the plugin is registered
+	// after a restart.
+	ts.Close()
+	ts = newTestTstoreAt(t, dir)
+	defer ts.Close()
+	err = ts.PluginRegister(nil, backend.Plugin{ID: cmplugin.PluginID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := pluginSetting(t, ts, cmplugin.PluginID, key); v != "200" {
+		t.Fatalf("got setting %v after restart, want 200", v)
+	}
+	history, err = ts.PluginSettingsHistory(cmplugin.PluginID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != len(updates) {
+		t.Fatalf("got %v updates after restart, want %v",
+			len(history), len(updates))
+	}
+}
+
+func TestPluginSettingsUpdateUnset(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tstore.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	ts := newTestTstoreAt(t, dir)
+	defer ts.Close()
+
+	p := &testSettingsPlugin{
+		settings: map[string]string{"a": "1"},
+	}
+	ts.plugins["test"] = plugin{
+		id:     "test",
+		client: p,
+	}
+	want := []backend.PluginSetting{{Key: "a", Value: "1"}}
+
+	// A rejected update must not apply a setting that did not have a
+	// value prior to the update.
+	_, err = ts.PluginSettingsUpdate("test", []backend.PluginSetting{
+		{Key: "a", Value: "2"},
+		{Key: "b", Value: "2"},
+		{Key: "invalid", Value: "2"},
+	}, "", "admin")
+	var e backend.PluginSettingError
+	if !errors.As(err, &e) {
+		t.Fatalf("got error %v, want PluginSettingError", err)
+	}
+	if s := p.Settings(); !reflect.DeepEqual(s, want) {
+		t.Fatalf("got settings %v, want %v", s, want)
+	}
+	history, err := ts.PluginSettingsHistory("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(history) != 0 {
+		t.Fatalf("got %v updates, want 0", len(history))
+	}
+
+	// An update that cannot be saved to the audit trail must not
+	// apply a setting that did not have a value prior to the update.
+	kv := ts.store
+	ts.store = &failPutStore{kv}
+	_, err = ts.PluginSettingsUpdate("test", []backend.PluginSetting{
+		{Key: "a", Value: "2"},
+		{Key: "b", Value: "2"},
+	}, "", "admin")
+	ts.store = kv
+	if err == nil {
+		t.Fatalf("got nil error, want put error")
+	}
+	if s := p.Settings(); !reflect.DeepEqual(s, want) {
+		t.Fatalf("got settings %v, want %v", s, want)
+	}
+}
+
+func TestPluginSettingsReplay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tstore.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	var (
+		key = cmplugin.SettingKeyCommentLengthMax
+		ts  *Tstore
+	)
+
+	// restart restarts the tstore and registers the comments plugin
+	// using the provided config value. The setting value that is in
+	// effect after the replay is returned.
+	restart := func(config string) string {
+		t.Helper()
+
+		if ts != nil {
+			ts.Close()
+		}
+		ts = newTestTstoreAt(t, dir)
+		err := ts.PluginRegister(nil, backend.Plugin{
+			ID:       cmplugin.PluginID,
+			Settings: []backend.PluginSetting{{Key: key, Value: config}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pluginSetting(t, ts, cmplugin.PluginID, key)
+	}
+	update := func(value string) {
+		t.Helper()
+
+		_, err := ts.PluginSettingsUpdate(cmplugin.PluginID,
+			[]backend.PluginSetting{{Key: key, Value: value}}, "", "admin")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A runtime update takes precedence over the config value that
+	// was in effect when the update was made.
+	restart("100")
+	update("200")
+	if v := restart("100"); v != "200" {
+		t.Fatalf("got setting %v, want runtime value 200", v)
+	}
+
+	// A config change made after the last runtime update takes
+	// precedence over the runtime update.
+	if v := restart("300"); v != "300" {
+		t.Fatalf("got setting %v, want config value 300", v)
+	}
+
+	// A runtime update made after the config change takes precedence
+	// again.
+	update("400")
+	if v := restart("300"); v != "400" {
+		t.Fatalf("got setting %v, want runtime value 400", v)
+	}
+
+	// Reverting the config to a value that it had prior to the last
+	// runtime update is also a config change.
+	if v := restart("100"); v != "100" {
+		t.Fatalf("got setting %v, want config value 100", v)
+	}
+	ts.Close()
+}
@@ -15,6 +15,7 @@ import (
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
 	"github.com/decred/politeia/util"
 	"github.com/google/trillian"
+	"github.com/google/trillian/crypto/keys/der"
 	"google.golang.org/grpc/codes"
 )
 
@@ -772,6 +773,10 @@ func (t *Tstore) record(treeID int64, version uint32, filenames []string, omitAl
 		}
 	}
 
+	if recordMD == nil {
+		return nil, fmt.Errorf("record metadata not found")
+	}
+
 	return &backend.Record{
 		RecordMetadata: *recordMD,
 		Metadata:       metadata,
@@ -941,6 +946,21 @@ func (t *Tstore) timestamp(treeID int64, merkleLeafHash []byte, leaves []*trilli
 		LeafIndex: p.LeafIndex,
 		TreeSize:  int64(a.LogRoot.TreeSize),
 	}
+	if t.tlogSigner != nil {
+		// Include the signed log root so that the log root can be
+		// verified without trusting this server.
+		slr, err := t.tlogSigner.SignLogRoot(a.LogRoot)
+		if err != nil {
+			return nil, fmt.Errorf("SignLogRoot: %v", err)
+		}
+		pk, err := der.MarshalPublicKey(t.tlogSigner.Public())
+		if err != nil {
+			return nil, err
+		}
+		edt.LogRoot = hex.EncodeToString(slr.LogRoot)
+		edt.LogRootSignature = hex.EncodeToString(slr.LogRootSignature)
+		edt.PublicKey = hex.EncodeToString(pk)
+	}
 	extraData, err := json.Marshal(edt)
 	if err != nil {
 		return nil, err
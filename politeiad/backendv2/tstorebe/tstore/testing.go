@@ -34,5 +34,6 @@ func NewTestTstore(t *testing.T, dataDir string) *Tstore {
 	return &Tstore{
 		tlog:  newTestTClient(t),
 		store: store,
+		inv:   store,
 	}
 }
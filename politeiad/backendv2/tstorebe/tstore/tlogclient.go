@@ -531,6 +531,17 @@ const (
 	tlogKeyParamsKey = "tlogkeyparams"
 )
 
+// newTlogSigner returns a log root signer for the provided tlog signing key.
+// Log roots that are signed by the returned signer can be verified using the
+// same public key as the log roots that are signed by trillian.
+func newTlogSigner(privateKey *keyspb.PrivateKey) (*tcrypto.Signer, error) {
+	signer, err := der.UnmarshalPrivateKey(privateKey.Der)
+	if err != nil {
+		return nil, err
+	}
+	return tcrypto.NewSigner(0, signer, crypto.SHA256), nil
+}
+
 // deriveTlogKey derives a ed25519 tlog private signing key using the provided
 // passphrase and the Aragon2id key derivation function. A random 16 byte salt
 // is created the first time the key is derived. The salt and the other argon2
@@ -730,7 +741,7 @@ func (t *testTClient) TreesAll() ([]*trillian.Tree, error) {
 	t.Lock()
 	defer t.Unlock()
 
-	trees := make([]*trillian.Tree, len(t.trees))
+	trees := make([]*trillian.Tree, 0, len(t.trees))
 	for _, v := range t.trees {
 		trees = append(trees, &trillian.Tree{
 			TreeId:             v.TreeId,
@@ -814,8 +825,8 @@ func (t *testTClient) LeavesAll(treeID int64) ([]*trillian.LogLeaf, error) {
 	leavesCopy := make([]*trillian.LogLeaf, 0, len(leaves))
 	for _, v := range leaves {
 		var (
-			leafValue []byte
-			extraData []byte
+			leafValue = make([]byte, len(v.LeafValue))
+			extraData = make([]byte, len(v.ExtraData))
 		)
 		copy(leafValue, v.LeafValue)
 		copy(extraData, v.ExtraData)
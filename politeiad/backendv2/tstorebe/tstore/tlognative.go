@@ -0,0 +1,762 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tstore
+
+import (
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+	"github.com/decred/politeia/util"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/google/trillian"
+	"github.com/google/trillian/client"
+	tcrypto "github.com/google/trillian/crypto"
+	"github.com/google/trillian/crypto/keys/der"
+	"github.com/google/trillian/crypto/keyspb"
+	"github.com/google/trillian/crypto/sigpb"
+	"github.com/google/trillian/merkle/compact"
+	"github.com/google/trillian/types"
+	rstatus "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// The following are the key-value store keys that are used by the
+	// native tlog. They are not uuids so they are ignored by the
+	// tstore filesystem check.
+	nativeTreesKey     = "tlog-trees"
+	nativeTreeKeyFmt   = "tlog-tree-%v"    // treeID
+	nativeLeafKeyFmt   = "tlog-leaf-%v-%v" // treeID, leaf index
+	nativeLeafHashFmt  = "tlog-hash-%v-%x" // treeID, merkle leaf hash
+	nativeLeafBatchMax = 5000              // Max leaves per store Get
+)
+
+var (
+	_ tlogClient = (*nativeTlog)(nil)
+)
+
+// nativeTree is the tree record of the native tlog. It contains the tree
+// state, the compact merkle range that is used to compute the root hash when
+// leaves are appended, and the signed log root of the current tree height.
+type nativeTree struct {
+	TreeID    int64              `json:"treeid"`
+	State     trillian.TreeState `json:"state"`
+	Created   int64              `json:"created"` // Unix nano
+	Updated   int64              `json:"updated"` // Unix nano
+	Size      uint64             `json:"size"`
+	Range     [][]byte           `json:"range"`     // Compact range hashes
+	LogRoot   []byte             `json:"logroot"`   // Encoded LogRootV1
+	Signature []byte             `json:"signature"` // Log root signature
+}
+
+// nativeLeaf is a log leaf of the native tlog.
+type nativeLeaf struct {
+	LeafValue      []byte `json:"leafvalue"`
+	ExtraData      []byte `json:"extradata"`
+	MerkleLeafHash []byte `json:"merkleleafhash"`
+	Timestamp      int64  `json:"timestamp"` // Unix nano
+}
+
+// nativeTlog implements the tlogClient interface using a RFC 6962 merkle log
+// that is saved to a key-value store. The trees, leaves, and signed log roots
+// use the trillian formats so that the inclusion proofs and the anchored log
+// roots can be verified using the same tooling regardless of the tlog
+// implementation.
+//
+// Leaves are appended in the order in which they are provided. Duplicate
+// leaves are not appended and are returned with an AlreadyExists status code,
+// the same as trillian.
+type nativeTlog struct {
+	sync.RWMutex
+	kv        store.BlobKV
+	signer    *tcrypto.Signer
+	publicKey *keyspb.PublicKey
+	verifier  *client.LogVerifier
+
+	// hashes caches the merkle leaf hashes of the trees. The hashes
+	// of a tree are loaded on first use and are updated as leaves are
+	// appended.
+	hashes map[int64][][]byte // [treeID]merkleLeafHashes
+}
+
+// nativeTreeKey returns the key-value store key of a tree record.
+func nativeTreeKey(treeID int64) string {
+	return fmt.Sprintf(nativeTreeKeyFmt, treeID)
+}
+
+// nativeLeafKey returns the key-value store key of a log leaf.
+func nativeLeafKey(treeID int64, index uint64) string {
+	return fmt.Sprintf(nativeLeafKeyFmt, treeID, index)
+}
+
+// nativeLeafHashKey returns the key-value store key of the index entry that
+// maps a merkle leaf hash to its leaf index.
+func nativeLeafHashKey(treeID int64, merkleLeafHash []byte) string {
+	return fmt.Sprintf(nativeLeafHashFmt, treeID, merkleLeafHash)
+}
+
+// treeNotFound returns the error that is returned when a tree does not exist.
+// A NotFound status code is used so that the error is handled the same as a
+// trillian error.
+func treeNotFound(treeID int64) error {
+	return status.Errorf(codes.NotFound, "tree %v not found", treeID)
+}
+
+// treeIDs returns the IDs of all trees.
+func (t *nativeTlog) treeIDs() ([]int64, error) {
+	blobs, err := t.kv.Get([]string{nativeTreesKey})
+	if err != nil {
+		return nil, fmt.Errorf("store Get: %v", err)
+	}
+	b, ok := blobs[nativeTreesKey]
+	if !ok {
+		return []int64{}, nil
+	}
+	var treeIDs []int64
+	err = json.Unmarshal(b, &treeIDs)
+	if err != nil {
+		return nil, err
+	}
+	return treeIDs, nil
+}
+
+// treeGet returns the tree record of a tree.
+func (t *nativeTlog) treeGet(treeID int64) (*nativeTree, error) {
+	key := nativeTreeKey(treeID)
+	blobs, err := t.kv.Get([]string{key})
+	if err != nil {
+		return nil, fmt.Errorf("store Get: %v", err)
+	}
+	b, ok := blobs[key]
+	if !ok {
+		return nil, treeNotFound(treeID)
+	}
+	var nt nativeTree
+	err = json.Unmarshal(b, &nt)
+	if err != nil {
+		return nil, err
+	}
+	return &nt, nil
+}
+
+// treeSign updates the log root of a tree record using the root hash of the
+// compact range and signs it.
+func (t *nativeTlog) treeSign(nt *nativeTree, r *compact.Range) error {
+	rootHash, err := r.GetRootHash(nil)
+	if err != nil {
+		return err
+	}
+	if rootHash == nil {
+		rootHash = hasher.EmptyRoot()
+	}
+	var revision uint64
+	if len(nt.LogRoot) > 0 {
+		var lr types.LogRootV1
+		err = lr.UnmarshalBinary(nt.LogRoot)
+		if err != nil {
+			return err
+		}
+		revision = lr.Revision + 1
+	}
+	slr, err := t.signer.SignLogRoot(&types.LogRootV1{
+		TreeSize:       r.End(),
+		RootHash:       rootHash,
+		TimestampNanos: uint64(nt.Updated),
+		Revision:       revision,
+	})
+	if err != nil {
+		return err
+	}
+
+	nt.Size = r.End()
+	nt.Range = r.Hashes()
+	nt.LogRoot = slr.LogRoot
+	nt.Signature = slr.LogRootSignature
+
+	return nil
+}
+
+// signedLogRoot returns the signed log root of a tree record after verifying
+// the signature.
+func (t *nativeTlog) signedLogRoot(nt nativeTree) (*trillian.SignedLogRoot, *types.LogRootV1, error) {
+	slr := &trillian.SignedLogRoot{
+		KeyHint:          t.signer.KeyHint,
+		LogRoot:          nt.LogRoot,
+		LogRootSignature: nt.Signature,
+	}
+	lr, err := tcrypto.VerifySignedLogRoot(t.verifier.PubKey,
+		crypto.SHA256, slr)
+	if err != nil {
+		return nil, nil, fmt.Errorf("verify log root %v: %v", nt.TreeID, err)
+	}
+	if lr.TreeSize != nt.Size {
+		return nil, nil, fmt.Errorf("log root size mismatch %v: got %v, "+
+			"want %v", nt.TreeID, lr.TreeSize, nt.Size)
+	}
+	return slr, lr, nil
+}
+
+// tree converts a tree record into a trillian tree.
+func (t *nativeTlog) tree(nt nativeTree) (*trillian.Tree, error) {
+	created, err := ptypes.TimestampProto(time.Unix(0, nt.Created))
+	if err != nil {
+		return nil, err
+	}
+	updated, err := ptypes.TimestampProto(time.Unix(0, nt.Updated))
+	if err != nil {
+		return nil, err
+	}
+	return &trillian.Tree{
+		TreeId:             nt.TreeID,
+		TreeState:          nt.State,
+		TreeType:           trillian.TreeType_LOG,
+		HashStrategy:       trillian.HashStrategy_RFC6962_SHA256,
+		HashAlgorithm:      sigpb.DigitallySigned_SHA256,
+		SignatureAlgorithm: sigpb.DigitallySigned_ED25519,
+		PublicKey:          t.publicKey,
+		MaxRootDuration:    ptypes.DurationProto(0),
+		CreateTime:         created,
+		UpdateTime:         updated,
+	}, nil
+}
+
+// leaves returns the leaves of a tree in the range [start, end).
+func (t *nativeTlog) leaves(treeID int64, start, end uint64) ([]nativeLeaf, error) {
+	leaves := make([]nativeLeaf, 0, end-start)
+	for i := start; i < end; i += nativeLeafBatchMax {
+		batchEnd := i + nativeLeafBatchMax
+		if batchEnd > end {
+			batchEnd = end
+		}
+		keys := make([]string, 0, batchEnd-i)
+		for j := i; j < batchEnd; j++ {
+			keys = append(keys, nativeLeafKey(treeID, j))
+		}
+		blobs, err := t.kv.Get(keys)
+		if err != nil {
+			return nil, fmt.Errorf("store Get: %v", err)
+		}
+		for _, k := range keys {
+			b, ok := blobs[k]
+			if !ok {
+				return nil, fmt.Errorf("leaf not found: %v", k)
+			}
+			var l nativeLeaf
+			err = json.Unmarshal(b, &l)
+			if err != nil {
+				return nil, err
+			}
+			leaves = append(leaves, l)
+		}
+	}
+	return leaves, nil
+}
+
+// leafHashes returns the merkle leaf hashes of a tree. The hashes are loaded
+// from the key-value store if they have not been cached yet.
+//
+// This function must be called WITH the lock held.
+func (t *nativeTlog) leafHashes(nt nativeTree) ([][]byte, error) {
+	hashes, ok := t.hashes[nt.TreeID]
+	if ok && uint64(len(hashes)) == nt.Size {
+		return hashes, nil
+	}
+
+	leaves, err := t.leaves(nt.TreeID, 0, nt.Size)
+	if err != nil {
+		return nil, err
+	}
+	hashes = make([][]byte, 0, len(leaves))
+	for _, v := range leaves {
+		hashes = append(hashes, v.MerkleLeafHash)
+	}
+	t.hashes[nt.TreeID] = hashes
+
+	return hashes, nil
+}
+
+// inclusionProof returns the inclusion proof of the leaf at the provided index
+// for the tree made up of the provided leaf hashes. The proof is verified
+// against the provided log root.
+func (t *nativeTlog) inclusionProof(index uint64, hashes [][]byte, lr *types.LogRootV1) (*trillian.Proof, error) {
+	if index >= uint64(len(hashes)) {
+		return nil, fmt.Errorf("leaf index %v is beyond tree size %v",
+			index, len(hashes))
+	}
+	proof := &trillian.Proof{
+		LeafIndex: int64(index),
+		Hashes:    merklePath(int(index), hashes),
+	}
+	err := t.verifier.VerifyInclusionByHash(lr, hashes[index], proof)
+	if err != nil {
+		return nil, fmt.Errorf("VerifyInclusionByHash: %v", err)
+	}
+	return proof, nil
+}
+
+// TreeNew creates a new tree and returns the tree and its signed log root.
+//
+// This function satisfies the tlogClient interface.
+func (t *nativeTlog) TreeNew() (*trillian.Tree, *trillian.SignedLogRoot, error) {
+	log.Tracef("native TreeNew")
+
+	t.Lock()
+	defer t.Unlock()
+
+	treeIDs, err := t.treeIDs()
+	if err != nil {
+		return nil, nil, err
+	}
+	exists := make(map[int64]struct{}, len(treeIDs))
+	for _, v := range treeIDs {
+		exists[v] = struct{}{}
+	}
+
+	// Create a tree ID. Tree IDs are random, the same as trillian.
+	var treeID int64
+	for treeID == 0 {
+		r, err := util.RandomUint64()
+		if err != nil {
+			return nil, nil, err
+		}
+		treeID = int64(r >> 1)
+		if _, ok := exists[treeID]; ok {
+			treeID = 0
+		}
+	}
+
+	// Create the tree record
+	now := time.Now().UnixNano()
+	nt := nativeTree{
+		TreeID:  treeID,
+		State:   trillian.TreeState_ACTIVE,
+		Created: now,
+		Updated: now,
+	}
+	rf := compact.RangeFactory{Hash: hasher.HashChildren}
+	err = t.treeSign(&nt, rf.NewEmptyRange(0))
+	if err != nil {
+		return nil, nil, err
+	}
+	bt, err := json.Marshal(nt)
+	if err != nil {
+		return nil, nil, err
+	}
+	bi, err := json.Marshal(append(treeIDs, treeID))
+	if err != nil {
+		return nil, nil, err
+	}
+	kv := map[string][]byte{
+		nativeTreeKey(treeID): bt,
+		nativeTreesKey:        bi,
+	}
+	err = t.kv.Put(kv, false)
+	if err != nil {
+		return nil, nil, fmt.Errorf("store Put: %v", err)
+	}
+	t.hashes[treeID] = [][]byte{}
+
+	tree, err := t.tree(nt)
+	if err != nil {
+		return nil, nil, err
+	}
+	slr, _, err := t.signedLogRoot(nt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	log.Debugf("Created tree %v", treeID)
+
+	return tree, slr, nil
+}
+
+// TreeFreeze sets the status of a tree to frozen and returns the updated tree.
+//
+// This function satisfies the tlogClient interface.
+func (t *nativeTlog) TreeFreeze(treeID int64) (*trillian.Tree, error) {
+	log.Tracef("native TreeFreeze: %v", treeID)
+
+	t.Lock()
+	defer t.Unlock()
+
+	nt, err := t.treeGet(treeID)
+	if err != nil {
+		return nil, err
+	}
+	nt.State = trillian.TreeState_FROZEN
+	nt.Updated = time.Now().UnixNano()
+	b, err := json.Marshal(nt)
+	if err != nil {
+		return nil, err
+	}
+	err = t.kv.Put(map[string][]byte{nativeTreeKey(treeID): b}, false)
+	if err != nil {
+		return nil, fmt.Errorf("store Put: %v", err)
+	}
+
+	return t.tree(*nt)
+}
+
+// Tree returns a tree.
+//
+// This function satisfies the tlogClient interface.
+func (t *nativeTlog) Tree(treeID int64) (*trillian.Tree, error) {
+	log.Tracef("native Tree: %v", treeID)
+
+	t.RLock()
+	defer t.RUnlock()
+
+	nt, err := t.treeGet(treeID)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.tree(*nt)
+}
+
+// TreesAll returns all trees.
+//
+// This function satisfies the tlogClient interface.
+func (t *nativeTlog) TreesAll() ([]*trillian.Tree, error) {
+	log.Tracef("native TreesAll")
+
+	t.RLock()
+	defer t.RUnlock()
+
+	treeIDs, err := t.treeIDs()
+	if err != nil {
+		return nil, err
+	}
+	keys := make([]string, 0, len(treeIDs))
+	for _, v := range treeIDs {
+		keys = append(keys, nativeTreeKey(v))
+	}
+	blobs, err := t.kv.Get(keys)
+	if err != nil {
+		return nil, fmt.Errorf("store Get: %v", err)
+	}
+	trees := make([]*trillian.Tree, 0, len(treeIDs))
+	for _, k := range keys {
+		b, ok := blobs[k]
+		if !ok {
+			return nil, fmt.Errorf("tree not found: %v", k)
+		}
+		var nt nativeTree
+		err = json.Unmarshal(b, &nt)
+		if err != nil {
+			return nil, err
+		}
+		tree, err := t.tree(nt)
+		if err != nil {
+			return nil, err
+		}
+		trees = append(trees, tree)
+	}
+
+	return trees, nil
+}
+
+// LeavesAppend appends leaves onto a tree and returns the queued leaves along
+// with the inclusion proofs of the appended leaves for the updated log root.
+// The leaves, the leaf index entries, and the updated tree record are saved
+// to the key-value store atomically.
+//
+// This function satisfies the tlogClient interface.
+func (t *nativeTlog) LeavesAppend(treeID int64, leaves []*trillian.LogLeaf) ([]queuedLeafProof, *types.LogRootV1, error) {
+	log.Tracef("native LeavesAppend: %v %v", treeID, len(leaves))
+
+	t.Lock()
+	defer t.Unlock()
+
+	nt, err := t.treeGet(treeID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if nt.State == trillian.TreeState_FROZEN {
+		return nil, nil, fmt.Errorf("tree is frozen")
+	}
+	hashes, err := t.leafHashes(*nt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Find the leaves that already exist in the tree
+	hashKeys := make([]string, 0, len(leaves))
+	for _, v := range leaves {
+		v.MerkleLeafHash = merkleLeafHash(v.LeafValue)
+		hashKeys = append(hashKeys, nativeLeafHashKey(treeID, v.MerkleLeafHash))
+	}
+	existing, err := t.kv.Get(hashKeys)
+	if err != nil {
+		return nil, nil, fmt.Errorf("store Get: %v", err)
+	}
+
+	// Append the leaves to the compact range
+	rf := compact.RangeFactory{Hash: hasher.HashChildren}
+	r, err := rf.NewRange(0, nt.Size, nt.Range)
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		now      = time.Now().UnixNano()
+		kv       = make(map[string][]byte, 2*len(leaves)+1)
+		queued   = make([]queuedLeafProof, 0, len(leaves))
+		appended = make([][]byte, 0, len(leaves)) // Merkle leaf hashes
+	)
+	for i, v := range leaves {
+		qlp := queuedLeafProof{
+			QueuedLeaf: &trillian.QueuedLogLeaf{
+				Leaf: v,
+				Status: &rstatus.Status{
+					Code: int32(codes.OK),
+				},
+			},
+		}
+		_, ok := existing[hashKeys[i]]
+		if !ok {
+			_, ok = kv[hashKeys[i]]
+		}
+		if ok {
+			// Leaf is a duplicate
+			qlp.QueuedLeaf.Status = &rstatus.Status{
+				Code:    int32(codes.AlreadyExists),
+				Message: "leaf already exists",
+			}
+			queued = append(queued, qlp)
+			continue
+		}
+
+		index := r.End()
+		b, err := json.Marshal(nativeLeaf{
+			LeafValue:      v.LeafValue,
+			ExtraData:      v.ExtraData,
+			MerkleLeafHash: v.MerkleLeafHash,
+			Timestamp:      now,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		kv[nativeLeafKey(treeID, index)] = b
+		kv[hashKeys[i]] = []byte(strconv.FormatUint(index, 10))
+		err = r.Append(v.MerkleLeafHash, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		v.LeafIndex = int64(index)
+		appended = append(appended, v.MerkleLeafHash)
+		queued = append(queued, qlp)
+	}
+
+	// Sign the updated log root and save the changes
+	if len(appended) > 0 {
+		nt.Updated = now
+		err = t.treeSign(nt, r)
+		if err != nil {
+			return nil, nil, err
+		}
+		b, err := json.Marshal(nt)
+		if err != nil {
+			return nil, nil, err
+		}
+		kv[nativeTreeKey(treeID)] = b
+		err = t.kv.Put(kv, false)
+		if err != nil {
+			return nil, nil, fmt.Errorf("store Put: %v", err)
+		}
+		hashes = append(hashes[:len(hashes):len(hashes)], appended...)
+		t.hashes[treeID] = hashes
+	}
+	_, lr, err := t.signedLogRoot(*nt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Get the inclusion proofs of the appended leaves
+	for i, v := range queued {
+		if codes.Code(v.QueuedLeaf.GetStatus().GetCode()) != codes.OK {
+			continue
+		}
+		queued[i].Proof, err = t.inclusionProof(uint64(v.QueuedLeaf.Leaf.LeafIndex),
+			hashes, lr)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	log.Debugf("Appended leaves (%v/%v) to tree %v",
+		len(appended), len(leaves), treeID)
+
+	return queued, lr, nil
+}
+
+// LeavesAll returns all leaves of a tree.
+//
+// This function satisfies the tlogClient interface.
+func (t *nativeTlog) LeavesAll(treeID int64) ([]*trillian.LogLeaf, error) {
+	log.Tracef("native LeavesAll: %v", treeID)
+
+	t.RLock()
+	defer t.RUnlock()
+
+	nt, err := t.treeGet(treeID)
+	if err != nil {
+		return nil, err
+	}
+	leaves, err := t.leaves(treeID, 0, nt.Size)
+	if err != nil {
+		return nil, err
+	}
+	ll := make([]*trillian.LogLeaf, 0, len(leaves))
+	for i, v := range leaves {
+		ts, err := ptypes.TimestampProto(time.Unix(0, v.Timestamp))
+		if err != nil {
+			return nil, err
+		}
+		ll = append(ll, &trillian.LogLeaf{
+			MerkleLeafHash:     v.MerkleLeafHash,
+			LeafValue:          v.LeafValue,
+			ExtraData:          v.ExtraData,
+			LeafIndex:          int64(i),
+			LeafIdentityHash:   v.MerkleLeafHash,
+			QueueTimestamp:     ts,
+			IntegrateTimestamp: ts,
+		})
+	}
+
+	return ll, nil
+}
+
+// SignedLogRoot returns the signed log root of a tree.
+//
+// This function satisfies the tlogClient interface.
+func (t *nativeTlog) SignedLogRoot(tree *trillian.Tree) (*trillian.SignedLogRoot, *types.LogRootV1, error) {
+	log.Tracef("native SignedLogRoot: %v", tree.TreeId)
+
+	t.RLock()
+	defer t.RUnlock()
+
+	nt, err := t.treeGet(tree.TreeId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return t.signedLogRoot(*nt)
+}
+
+// InclusionProof returns a proof for the inclusion of a merkle leaf hash in a
+// log root. The log root can be any prior log root of the tree.
+//
+// This function satisfies the tlogClient interface.
+func (t *nativeTlog) InclusionProof(treeID int64, merkleLeafHash []byte, lr *types.LogRootV1) (*trillian.Proof, error) {
+	log.Tracef("native InclusionProof: %v %x", treeID, merkleLeafHash)
+
+	// The hashes cache is updated so the write lock is required
+	t.Lock()
+	defer t.Unlock()
+
+	nt, err := t.treeGet(treeID)
+	if err != nil {
+		return nil, err
+	}
+	if lr.TreeSize > nt.Size {
+		return nil, fmt.Errorf("log root size %v is beyond tree size %v",
+			lr.TreeSize, nt.Size)
+	}
+
+	// Lookup the leaf index
+	key := nativeLeafHashKey(treeID, merkleLeafHash)
+	blobs, err := t.kv.Get([]string{key})
+	if err != nil {
+		return nil, fmt.Errorf("store Get: %v", err)
+	}
+	b, ok := blobs[key]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "leaf %x not found",
+			merkleLeafHash)
+	}
+	index, err := strconv.ParseUint(string(b), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	hashes, err := t.leafHashes(*nt)
+	if err != nil {
+		return nil, err
+	}
+
+	return t.inclusionProof(index, hashes[:lr.TreeSize], lr)
+}
+
+// Close performs cleanup of the client. The key-value store is closed by
+// tstore.
+//
+// This function satisfies the tlogClient interface.
+func (t *nativeTlog) Close() {
+	log.Tracef("native Close")
+}
+
+// merkleTreeHash returns the RFC 6962 merkle tree hash of the provided merkle
+// leaf hashes.
+func merkleTreeHash(hashes [][]byte) []byte {
+	switch len(hashes) {
+	case 0:
+		return hasher.EmptyRoot()
+	case 1:
+		return hashes[0]
+	}
+	k := merkleSplit(len(hashes))
+	return hasher.HashChildren(merkleTreeHash(hashes[:k]),
+		merkleTreeHash(hashes[k:]))
+}
+
+// merklePath returns the RFC 6962 merkle audit path of the leaf at the
+// provided index. The path is ordered from the leaf to the root, which is the
+// order that is used by trillian inclusion proofs.
+func merklePath(index int, hashes [][]byte) [][]byte {
+	if len(hashes) <= 1 {
+		return [][]byte{}
+	}
+	k := merkleSplit(len(hashes))
+	if index < k {
+		return append(merklePath(index, hashes[:k]), merkleTreeHash(hashes[k:]))
+	}
+	return append(merklePath(index-k, hashes[k:]), merkleTreeHash(hashes[:k]))
+}
+
+// merkleSplit returns the largest power of two that is smaller than n. n must
+// be greater than one.
+func merkleSplit(n int) int {
+	k := 1
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
+
+// newNativeTlog returns a new nativeTlog that saves its data to the provided
+// key-value store and signs the log roots using the provided tlog key.
+func newNativeTlog(kv store.BlobKV, privateKey *keyspb.PrivateKey) (*nativeTlog, error) {
+	signer, err := newTlogSigner(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	publicKey, err := der.ToPublicProto(signer.Public())
+	if err != nil {
+		return nil, err
+	}
+	return &nativeTlog{
+		kv:        kv,
+		signer:    signer,
+		publicKey: publicKey,
+		verifier:  client.NewLogVerifier(hasher, signer.Public(), crypto.SHA256),
+		hashes:    make(map[int64][][]byte),
+	}, nil
+}
@@ -0,0 +1,285 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tstore
+
+import (
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store/localdb"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store/mysql"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store/postgres"
+	"github.com/google/trillian"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// The native tlog tests are also run against actual mysql and postgres
+// instances since the native tlog overwrites its tree records. They are
+// skipped unless the following env variables are set. All tables of the test
+// databases are dropped.
+//
+// MYSQL_TEST_HOST   : mysql ip:port (ex. localhost:3306)
+// MYSQL_TEST_USER   : mysql user (default: politeiad)
+// MYSQL_TEST_PASS   : password of the mysql user
+// MYSQL_TEST_DB     : name of the mysql test database
+// POSTGRES_TEST_HOST: postgres ip:port (ex. localhost:5432)
+// POSTGRES_TEST_USER: postgres user (default: politeiad)
+// POSTGRES_TEST_PASS: password of the postgres user
+// POSTGRES_TEST_DB  : name of the postgres test database
+
+// sqlTestEnv returns the connection settings of a SQL test database. The test
+// is skipped if the database host is not set.
+func sqlTestEnv(t *testing.T, prefix string) (host, user, pass, dbname string) {
+	t.Helper()
+
+	host = os.Getenv(prefix + "_TEST_HOST")
+	if host == "" {
+		t.Skipf("%v_TEST_HOST not set; skipping %v test",
+			prefix, strings.ToLower(prefix))
+	}
+	user = os.Getenv(prefix + "_TEST_USER")
+	if user == "" {
+		user = "politeiad"
+	}
+	pass = os.Getenv(prefix + "_TEST_PASS")
+	dbname = os.Getenv(prefix + "_TEST_DB")
+	if dbname == "" {
+		t.Fatalf("%v_TEST_DB must be set", prefix)
+	}
+	return host, user, pass, dbname
+}
+
+// sqlTestDropTables drops the tables of a SQL test database so that the test
+// starts with an empty store.
+func sqlTestDropTables(t *testing.T, driver, dsn string) {
+	t.Helper()
+
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	tables := []string{"kv", "nonce", "inventory_attributes", "inventory"}
+	for _, v := range tables {
+		_, err = db.Exec(fmt.Sprintf("DROP TABLE IF EXISTS %v;", v))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestNativeTlog(t *testing.T) {
+	appDir, err := ioutil.TempDir("", "tlognative.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(appDir)
+
+	kvstore, err := localdb.New(appDir, filepath.Join(appDir, "store"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kvstore.Close()
+
+	testNativeTlog(t, kvstore)
+}
+
+func TestNativeTlogMySQL(t *testing.T) {
+	host, user, pass, dbname := sqlTestEnv(t, "MYSQL")
+	sqlTestDropTables(t, "mysql",
+		fmt.Sprintf("%v:%v@tcp(%v)/%v", user, pass, host, dbname))
+
+	kvstore, err := mysql.New("", host, user, pass, dbname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kvstore.Close()
+
+	testNativeTlog(t, kvstore)
+}
+
+func TestNativeTlogPostgres(t *testing.T) {
+	host, user, pass, dbname := sqlTestEnv(t, "POSTGRES")
+	sqlTestDropTables(t, "postgres",
+		postgres.DSN(host, user, pass, dbname))
+
+	kvstore, err := postgres.New("", host, user, pass, dbname)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer kvstore.Close()
+
+	testNativeTlog(t, kvstore)
+}
+
+// testNativeTlog tests the native tlog using the provided kv store. The kv
+// store must be empty.
+func testNativeTlog(t *testing.T, kvstore store.BlobKV) {
+	key, err := deriveTlogKey(kvstore, "testpassphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tl, err := newNativeTlog(kvstore, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Create a tree
+	tree, _, err := tl.TreeNew()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, lr, err := tl.SignedLogRoot(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lr.TreeSize != 0 {
+		t.Fatalf("got tree size %v, want 0", lr.TreeSize)
+	}
+
+	// Append leaves in multiple batches. Every appended leaf must
+	// come with a valid inclusion proof.
+	leafValues := make([][]byte, 0, 12)
+	for i := 0; i < 12; i++ {
+		leafValues = append(leafValues, []byte{byte(i)})
+	}
+	for _, batch := range [][][]byte{leafValues[:1], leafValues[1:7],
+		leafValues[7:]} {
+		leaves := make([]*trillian.LogLeaf, 0, len(batch))
+		for _, v := range batch {
+			leaves = append(leaves, newLogLeaf(v, nil))
+		}
+		queued, lr, err := tl.LeavesAppend(tree.TreeId, leaves)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(queued) != len(batch) {
+			t.Fatalf("got %v queued leaves, want %v", len(queued), len(batch))
+		}
+		for _, v := range queued {
+			c := codes.Code(v.QueuedLeaf.GetStatus().GetCode())
+			if c != codes.OK {
+				t.Fatalf("got leaf status %v, want ok", c)
+			}
+			err = tl.verifier.VerifyInclusionByHash(lr,
+				v.QueuedLeaf.Leaf.MerkleLeafHash, v.Proof)
+			if err != nil {
+				t.Fatalf("VerifyInclusionByHash: %v", err)
+			}
+		}
+	}
+
+	// Duplicate leaves are not appended
+	leaves := []*trillian.LogLeaf{
+		newLogLeaf(leafValues[3], nil),
+		newLogLeaf([]byte("new"), nil),
+		newLogLeaf([]byte("new"), nil),
+	}
+	queued, lr, err := tl.LeavesAppend(tree.TreeId, leaves)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []codes.Code{codes.AlreadyExists, codes.OK, codes.AlreadyExists}
+	for i, v := range queued {
+		c := codes.Code(v.QueuedLeaf.GetStatus().GetCode())
+		if c != want[i] {
+			t.Errorf("leaf %v: got status %v, want %v", i, c, want[i])
+		}
+	}
+	if lr.TreeSize != 13 {
+		t.Fatalf("got tree size %v, want 13", lr.TreeSize)
+	}
+
+	// Inclusion proofs can be retrieved for prior log roots
+	leavesAll, err := tl.LeavesAll(tree.TreeId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(leavesAll) != 13 {
+		t.Fatalf("got %v leaves, want 13", len(leavesAll))
+	}
+	_, lr, err = tl.SignedLogRoot(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range leavesAll {
+		p, err := tl.InclusionProof(tree.TreeId, v.MerkleLeafHash, lr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = tl.verifier.VerifyInclusionByHash(lr, v.MerkleLeafHash, p)
+		if err != nil {
+			t.Fatalf("leaf %v: VerifyInclusionByHash: %v", v.LeafIndex, err)
+		}
+	}
+
+	// The log is reloaded from the kv store
+	tl, err = newNativeTlog(kvstore, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, lr2, err := tl.SignedLogRoot(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lr2.TreeSize != lr.TreeSize || lr2.Revision != lr.Revision {
+		t.Fatalf("got log root %v/%v, want %v/%v", lr2.TreeSize,
+			lr2.Revision, lr.TreeSize, lr.Revision)
+	}
+	p, err := tl.InclusionProof(tree.TreeId, leavesAll[5].MerkleLeafHash, lr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = tl.verifier.VerifyInclusionByHash(lr, leavesAll[5].MerkleLeafHash, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Creating a second tree updates the tree list
+	tree2, _, err := tl.TreeNew()
+	if err != nil {
+		t.Fatal(err)
+	}
+	trees, err := tl.TreesAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trees) != 2 {
+		t.Fatalf("got %v trees, want 2", len(trees))
+	}
+	for _, v := range trees {
+		if v.TreeId != tree.TreeId && v.TreeId != tree2.TreeId {
+			t.Fatalf("unexpected tree %v", v.TreeId)
+		}
+	}
+
+	// Frozen trees cannot be appended to
+	tree, err = tl.TreeFreeze(tree.TreeId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tree.TreeState != trillian.TreeState_FROZEN {
+		t.Fatalf("got tree state %v, want frozen", tree.TreeState)
+	}
+	_, _, err = tl.LeavesAppend(tree.TreeId,
+		[]*trillian.LogLeaf{newLogLeaf([]byte("frozen"), nil)})
+	if err == nil {
+		t.Fatalf("leaf appended to a frozen tree")
+	}
+
+	// A tree that does not exist returns a NotFound error
+	_, err = tl.Tree(tree.TreeId + 1)
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("got error %v, want not found", err)
+	}
+}
@@ -6,19 +6,25 @@ package tstore
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/decred/dcrd/chaincfg/v3"
 	backend "github.com/decred/politeia/politeiad/backendv2"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store/cache"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store/localdb"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store/mysql"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store/postgres"
 	"github.com/decred/politeia/util"
+	tcrypto "github.com/google/trillian/crypto"
 	"github.com/robfig/cron"
 )
 
@@ -31,10 +37,23 @@ const (
 	// store to a MySQL instance.
 	DBTypeMySQL = "mysql"
 
+	// DBTypePostgres is a config option that sets the backing
+	// key-value store to a PostgreSQL instance.
+	DBTypePostgres = "postgres"
+
+	// TlogTypeTrillian is a config option that sets the tlog backend
+	// to a trillian log server.
+	TlogTypeTrillian = "trillian"
+
+	// TlogTypeNative is a config option that sets the tlog backend to
+	// a merkle log that is saved to the tstore key-value store. It
+	// does not require a trillian log server.
+	TlogTypeNative = "native"
+
 	// LevelDB settings
 	storeDirname = "store"
 
-	// MySQL settings
+	// MySQL and PostgreSQL settings
 	dbUser = "politeiad"
 )
 
@@ -66,16 +85,84 @@ type Tstore struct {
 	dataDir         string
 	activeNetParams *chaincfg.Params
 	tlog            tlogClient
+	tlogType        string
+	tlogSigner      *tcrypto.Signer // Signs anchored log roots
 	store           store.BlobKV
-	dcrtime         *dcrtimeClient
+	anchor          anchorClient
 	cron            *cron.Cron
 	plugins         map[string]plugin // [pluginID]plugin
 
+	// freezer blocks writes to the key-value store and to the tlog
+	// backend while a backup is being taken. backupKey is used to
+	// encrypt the unvetted blobs that are included in a backup. It is
+	// derived from the tlog signing key. backingUp indicates whether
+	// a backup is in progress and is protected by the tstore mutex.
+	freezer   *writeFreezer
+	backupKey *[32]byte
+	backingUp bool
+
+	// cacheRebuildStatus contains the status of the most recent plugin
+	// cache rebuild. It will be nil if a cache rebuild has not been
+	// run. It is protected by the tstore mutex.
+	cacheRebuildStatus *backend.CacheRebuildStatus
+
+	// keyRotator is the key-value store that is used to rotate the
+	// encryption key. It bypasses the blob cache, which only caches
+	// unencrypted blobs. This field will be nil if the key-value store
+	// does not support key rotation.
+	keyRotator store.KeyRotator
+
+	// inv is the key-value store that maintains the indexed record
+	// inventory. It bypasses the blob cache. This field will be nil if
+	// the key-value store does not support an inventory index.
+	inv store.Inventory
+
+	// blobCache is the in-memory cache of vetted blobs. This field will
+	// be nil if the blob cache is disabled.
+	blobCache *cache.BlobCache
+
+	// events is the backend event stream. Events are published by the
+	// backend after the post plugin hooks have been executed and by
+	// plugins.
+	events *eventStream
+
+	// mtxPluginSettings serializes the runtime plugin settings updates
+	// so that the audit trail reflects the order that the updates
+	// were applied in.
+	mtxPluginSettings sync.Mutex
+
+	// reencrypting indicates whether a re-encryption pass, i.e. the
+	// re-encryption of all encrypted blobs using the active encryption
+	// key, is in progress. reencryptPending indicates whether another
+	// pass has been requested while a pass was in progress. These are
+	// protected by the tstore mutex.
+	reencrypting     bool
+	reencryptPending bool
+	reencrypted      uint64 // Blobs re-encrypted during the current pass
+
 	// droppingAnchor indicates whether tstore is in the process of
 	// dropping an anchor, i.e. timestamping unanchored tlog trees
-	// using dcrtime. An anchor is dropped periodically using cron.
+	// using the anchor client. An anchor is dropped periodically using
+	// cron.
 	droppingAnchor bool
 
+	// anchorVerifyPeriod is how often the anchor client is checked to
+	// see if a submitted anchor has dropped.
+	anchorVerifyPeriod time.Duration
+
+	// anchorType and anchorSchedule are the anchor settings that
+	// tstore was started with.
+	anchorType     string
+	anchorSchedule string
+
+	// anchorDrops contains the most recent anchor drops, ordered from
+	// oldest to newest. anchorLastRun and anchorLastErr describe the
+	// most recent anchor run. These are kept in memory and are
+	// protected by the tstore mutex.
+	anchorDrops   []*backend.AnchorDrop
+	anchorLastRun int64
+	anchorLastErr string
+
 	// tokens contains the short token to full token mappings. The
 	// short token is the first n characters of the hex encoded record
 	// token, where n is defined by the short token length politeiad
@@ -167,17 +254,29 @@ func (t *Tstore) fullLengthToken(token []byte) ([]byte, error) {
 	return fullToken, nil
 }
 
-// Fsck performs a filesystem check on the tstore.
-func (t *Tstore) Fsck() {
-	// Set tree status to frozen for any trees that are frozen and have
-	// been anchored one last time.
-	// Verify all file blobs have been deleted for censored records.
-}
-
 // Close performs cleanup of the tstore.
 func (t *Tstore) Close() {
 	log.Tracef("Close")
 
+	// Close the event stream subscriptions
+	t.events.close()
+
+	// Stop any plugins that run background jobs or that run in a
+	// separate process. The lock is not held while the plugins are
+	// stopped since an in progress plugin job may call into the
+	// tstore.
+	t.Lock()
+	closers := make([]interface{ Close() }, 0, len(t.plugins))
+	for _, v := range t.plugins {
+		if c, ok := v.client.(interface{ Close() }); ok {
+			closers = append(closers, c)
+		}
+	}
+	t.Unlock()
+	for _, c := range closers {
+		c.Close()
+	}
+
 	// Close connections
 	t.tlog.Close()
 	t.store.Close()
@@ -198,89 +297,192 @@ func (t *Tstore) Setup() error {
 		t.tokenAdd(v)
 	}
 
+	// Resume any re-encryption pass that did not complete prior to the
+	// last shutdown. Prior encryption keys exist until the pass that
+	// follows a key rotation has completed.
+	if t.keyRotator != nil {
+		keyIDs, activeKeyID := t.keyRotator.KeyIDs()
+		log.Infof("Encryption key IDs: %v, active: %v", keyIDs, activeKeyID)
+		if len(keyIDs) > 1 && t.reencryptStart() {
+			log.Infof("Resuming blob re-encryption")
+			go t.reencrypt()
+		}
+	}
+
 	return nil
 }
 
-// New returns a new tstore instance.
-func New(appDir, dataDir string, anp *chaincfg.Params, tlogHost, tlogPass, dbType, dbHost, dbPass, dcrtimeHost, dcrtimeCert string) (*Tstore, error) {
-	// Setup datadir for this tstore instance
-	dataDir = filepath.Join(dataDir)
-	err := os.MkdirAll(dataDir, 0700)
-	if err != nil {
-		return nil, err
-	}
-
-	// Setup key-value store
+// newBlobKV returns the key-value store for the provided database type.
+func newBlobKV(appDir, dataDir string, anp *chaincfg.Params, dbType, dbHost, dbPass string) (store.BlobKV, error) {
 	log.Infof("Database type: %v", dbType)
-	var kvstore store.BlobKV
 	switch dbType {
 	case DBTypeLevelDB:
 		fp := filepath.Join(dataDir, storeDirname)
-		err = os.MkdirAll(fp, 0700)
-		if err != nil {
-			return nil, err
-		}
-		kvstore, err = localdb.New(appDir, fp)
+		err := os.MkdirAll(fp, 0700)
 		if err != nil {
 			return nil, err
 		}
+		return localdb.New(appDir, fp)
 	case DBTypeMySQL:
 		// Example db name: testnet3_unvetted_kv
 		dbName := fmt.Sprintf("%v_kv", anp.Name)
-		kvstore, err = mysql.New(appDir, dbHost, dbUser, dbPass, dbName)
-		if err != nil {
-			return nil, err
-		}
-	default:
-		return nil, fmt.Errorf("invalid db type: %v", dbType)
+		return mysql.New(appDir, dbHost, dbUser, dbPass, dbName)
+	case DBTypePostgres:
+		// Example db name: testnet3_kv
+		dbName := fmt.Sprintf("%v_kv", anp.Name)
+		return postgres.New(appDir, dbHost, dbUser, dbPass, dbName)
 	}
+	return nil, fmt.Errorf("invalid db type: %v", dbType)
+}
 
-	// Setup trillian client
-	log.Infof("Tlog host: %v", tlogHost)
-	tlogKey, err := deriveTlogKey(kvstore, tlogPass)
+// New returns a new tstore instance.
+func New(appDir, dataDir string, anp *chaincfg.Params, tlogType, tlogHost, tlogPass, dbType, dbHost, dbPass, dcrtimeHost, dcrtimeCert, anchorType, anchorSchedule string, blobCacheSize int64) (*Tstore, error) {
+	// Setup datadir for this tstore instance
+	dataDir = filepath.Join(dataDir)
+	err := os.MkdirAll(dataDir, 0700)
 	if err != nil {
 		return nil, err
 	}
-	tlogClient, err := newTClient(tlogHost, tlogKey)
+
+	// Setup key-value store
+	kvstore, err := newBlobKV(appDir, dataDir, anp, dbType, dbHost, dbPass)
 	if err != nil {
 		return nil, err
 	}
 
-	// Verify dcrtime host
-	_, err = url.Parse(dcrtimeHost)
-	if err != nil {
-		return nil, fmt.Errorf("parse dcrtime host '%v': %v", dcrtimeHost, err)
+	// The key rotator and the inventory index must be the underlying
+	// key-value store and not the blob cache decorator.
+	keyRotator, _ := kvstore.(store.KeyRotator)
+	inv, _ := kvstore.(store.Inventory)
+
+	// The native tlog overwrites its tree records on every append so
+	// it also uses the underlying key-value store. Its writes are
+	// blocked during a backup by the tlog client decorator.
+	tlogStore := kvstore
+
+	// Writes to the key-value store and to the tlog backend are
+	// blocked while a backup is being taken so that the backup is a
+	// point-in-time snapshot.
+	freezer := &writeFreezer{}
+	kvstore = &frozenBlobKV{
+		BlobKV:  kvstore,
+		freezer: freezer,
 	}
-	log.Infof("Anchor host: %v", dcrtimeHost)
 
-	// Setup dcrtime client
-	dcrtimeClient, err := newDcrtimeClient(dcrtimeHost, dcrtimeCert)
+	// Setup the blob cache. Only tstore blobs that are not prefixed
+	// with the encrypted key prefix are cached. Encrypted blobs are
+	// unvetted and must not be held in memory in plain text. Blobs
+	// that were not created by tstore, such as the key derivation
+	// params, can be overwritten and are not cached.
+	var blobCache *cache.BlobCache
+	if blobCacheSize > 0 {
+		blobCache = cache.New(kvstore, blobCacheSize, func(key string) bool {
+			return !strings.HasPrefix(key, keyPrefixEncrypted) &&
+				isTstoreKey(key)
+		})
+		kvstore = blobCache
+	}
+
+	// Setup tlog client
+	tlogKey, err := deriveTlogKey(kvstore, tlogPass)
 	if err != nil {
 		return nil, err
 	}
+	tlogSigner, err := newTlogSigner(tlogKey)
+	if err != nil {
+		return nil, err
+	}
+	var tc tlogClient
+	log.Infof("Tlog type: %v", tlogType)
+	switch tlogType {
+	case TlogTypeTrillian:
+		log.Infof("Tlog host: %v", tlogHost)
+		tc, err = newTClient(tlogHost, tlogKey)
+		if err != nil {
+			return nil, err
+		}
+	case TlogTypeNative:
+		tc, err = newNativeTlog(tlogStore, tlogKey)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("invalid tlog type: %v", tlogType)
+	}
+	tc = &frozenTlog{
+		tlogClient: tc,
+		freezer:    freezer,
+	}
+
+	// Setup anchor client
+	var (
+		ac           anchorClient
+		verifyPeriod time.Duration
+	)
+	log.Infof("Anchor type: %v", anchorType)
+	switch anchorType {
+	case AnchorTypeDcrtime:
+		// Verify dcrtime host
+		_, err = url.Parse(dcrtimeHost)
+		if err != nil {
+			return nil, fmt.Errorf("parse dcrtime host '%v': %v",
+				dcrtimeHost, err)
+		}
+		log.Infof("Anchor host: %v", dcrtimeHost)
+
+		ac, err = newDcrtimeClient(dcrtimeHost, dcrtimeCert)
+		if err != nil {
+			return nil, err
+		}
+		verifyPeriod = 5 * time.Minute
+	case AnchorTypeLocal:
+		fp := filepath.Join(dataDir, localAnchorDirname)
+		log.Infof("Anchor log: %v", fp)
+
+		ac, err = newLocalAnchorClient(fp)
+		if err != nil {
+			return nil, err
+		}
+		// Local anchors drop immediately
+		verifyPeriod = 5 * time.Second
+	default:
+		return nil, fmt.Errorf("invalid anchor type: %v", anchorType)
+	}
 
 	// Setup tstore
 	t := Tstore{
-		dataDir:         dataDir,
-		activeNetParams: anp,
-		tlog:            tlogClient,
-		store:           kvstore,
-		dcrtime:         dcrtimeClient,
-		cron:            cron.New(),
-		plugins:         make(map[string]plugin),
-		tokens:          make(map[string][]byte),
+		dataDir:            dataDir,
+		activeNetParams:    anp,
+		tlog:               tc,
+		tlogType:           tlogType,
+		tlogSigner:         tlogSigner,
+		store:              kvstore,
+		freezer:            freezer,
+		backupKey:          backupKeyDerive(tlogKey),
+		keyRotator:         keyRotator,
+		inv:                inv,
+		blobCache:          blobCache,
+		anchor:             ac,
+		anchorVerifyPeriod: verifyPeriod,
+		anchorType:         anchorType,
+		anchorSchedule:     anchorSchedule,
+		cron:               cron.New(),
+		plugins:            make(map[string]plugin),
+		tokens:             make(map[string][]byte),
+		events:             newEventStream(),
 	}
 
 	// Launch cron
-	log.Infof("Launch cron anchor job")
+	log.Infof("Launch cron anchor job: %v", anchorSchedule)
 	err = t.cron.AddFunc(anchorSchedule, func() {
-		err := t.anchorTrees()
-		if err != nil {
+		err := t.anchorRun()
+		if err != nil && !errors.Is(err, backend.ErrAnchorDropInProgress) {
 			log.Errorf("anchorTrees: %v", err)
 		}
 	})
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("invalid anchor schedule '%v': %v",
+			anchorSchedule, err)
 	}
 	t.cron.Start()
 
@@ -0,0 +1,113 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tstore
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/util"
+)
+
+// newTestTstoreNative returns a tstore that is backed by a leveldb kv store,
+// the native tlog, and a local anchor client. The returned function closes the
+// tstore and removes its data directory.
+func newTestTstoreNative(t *testing.T) (*Tstore, func()) {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "tstore.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := newTestTstoreAt(t, dir)
+
+	return ts, func() {
+		ts.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+// newTestTstoreAt returns a tstore that uses the provided directory as both
+// its app directory and its data directory. The anchor cron job is scheduled
+// yearly so that anchors are only dropped when a test requests it.
+func newTestTstoreAt(t *testing.T, dir string) *Tstore {
+	t.Helper()
+
+	ts, err := New(dir, filepath.Join(dir, "data"), chaincfg.TestNet3Params(),
+		TlogTypeNative, "", "testpassphrase", DBTypeLevelDB, "", "", "", "",
+		AnchorTypeLocal, "@yearly", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ts
+}
+
+// newTestFile returns a backend file with the provided name and payload.
+func newTestFile(name, payload string) backend.File {
+	return backend.File{
+		Name:    name,
+		MIME:    "text/plain; charset=utf-8",
+		Digest:  hex.EncodeToString(util.Digest([]byte(payload))),
+		Payload: base64.StdEncoding.EncodeToString([]byte(payload)),
+	}
+}
+
+// newTestRecord creates a new public record in the tstore and returns its
+// token and the metadata and files that were saved. The record is first saved
+// as unvetted then made public, the same way that the backend does it.
+func newTestRecord(t *testing.T, ts *Tstore) ([]byte, backend.RecordMetadata, []backend.File) {
+	t.Helper()
+
+	token, err := ts.RecordNew()
+	if err != nil {
+		t.Fatal(err)
+	}
+	files := []backend.File{
+		newTestFile("index.md", "record "+hex.EncodeToString(token)),
+		newTestFile("notes.txt", "notes"),
+	}
+	rm := backend.RecordMetadata{
+		Token:     hex.EncodeToString(token),
+		Version:   1,
+		Iteration: 1,
+		State:     backend.StateUnvetted,
+		Status:    backend.StatusUnreviewed,
+		Timestamp: time.Now().Unix(),
+	}
+	err = ts.RecordSave(token, rm, []backend.MetadataStream{}, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The version and iteration are reset when a record is made
+	// public.
+	rm.State = backend.StateVetted
+	rm.Status = backend.StatusPublic
+	err = ts.RecordSave(token, rm, []backend.MetadataStream{}, files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token, rm, files
+}
+
+// fsckIssues returns the number of issues of the provided type in the fsck
+// report.
+func fsckIssues(r *backend.FsckReport, issue backend.FsckIssueT) int {
+	var n int
+	for _, v := range r.Issues {
+		if v.Type == issue {
+			n++
+		}
+	}
+	return n
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/decred/politeia/politeiad/api/v1/mime"
 	backend "github.com/decred/politeia/politeiad/backendv2"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/tstore"
 	"github.com/decred/politeia/util"
 	"github.com/subosito/gozaru"
@@ -380,6 +381,11 @@ func (t *tstoreBackend) RecordNew(metadata []backend.MetadataStream, files []bac
 		return nil, fmt.Errorf("RecordSave: %v", err)
 	}
 
+	// Update the inventory. This is done prior to calling the post
+	// plugin hooks so that the plugins are able to set inventory
+	// attributes.
+	t.inventoryAdd(*rm)
+
 	// Call post plugin hooks
 	post := plugins.HookNewRecordPost{
 		Metadata:       metadata,
@@ -392,15 +398,15 @@ func (t *tstoreBackend) RecordNew(metadata []backend.MetadataStream, files []bac
 	}
 	t.tstore.PluginHookPost(plugins.HookTypeNewRecordPost, string(b))
 
-	// Update the inventory cache
-	t.inventoryAdd(backend.StateUnvetted, token, backend.StatusUnreviewed)
-
 	// Get the full record to return
 	r, err := t.tstore.RecordLatest(token)
 	if err != nil {
 		return nil, fmt.Errorf("RecordLatest %x: %v", token, err)
 	}
 
+	// Publish event
+	t.recordEventPublish(backend.EventTypeRecordNew, *r)
+
 	return r, nil
 }
 
@@ -490,6 +496,9 @@ func (t *tstoreBackend) RecordEdit(token []byte, mdAppend, mdOverwrite []backend
 		}
 	}
 
+	// Update the inventory
+	t.inventoryUpdate(*recordMD)
+
 	// Call post plugin hooks
 	t.tstore.PluginHookPost(plugins.HookTypeEditRecordPost, string(b))
 
@@ -499,6 +508,9 @@ func (t *tstoreBackend) RecordEdit(token []byte, mdAppend, mdOverwrite []backend
 		return nil, fmt.Errorf("RecordLatest: %v", err)
 	}
 
+	// Publish event
+	t.recordEventPublish(backend.EventTypeRecordEdit, *r)
+
 	return r, nil
 }
 
@@ -578,6 +590,9 @@ func (t *tstoreBackend) RecordEditMetadata(token []byte, mdAppend, mdOverwrite [
 		}
 	}
 
+	// Update the inventory
+	t.inventoryUpdate(*recordMD)
+
 	// Call post plugin hooks
 	t.tstore.PluginHookPost(plugins.HookTypeEditMetadataPost, string(b))
 
@@ -587,6 +602,9 @@ func (t *tstoreBackend) RecordEditMetadata(token []byte, mdAppend, mdOverwrite [
 		return nil, fmt.Errorf("RecordLatest: %v", err)
 	}
 
+	// Publish event
+	t.recordEventPublish(backend.EventTypeRecordEditMetadata, *r)
+
 	return r, nil
 }
 
@@ -767,24 +785,23 @@ func (t *tstoreBackend) RecordSetStatus(token []byte, status backend.StatusT, md
 		token, backend.Statuses[currStatus], currStatus,
 		backend.Statuses[status], status)
 
+	// Update the inventory. This is done prior to calling the post
+	// plugin hooks so that the plugins are able to set inventory
+	// attributes.
+	t.inventoryUpdate(*recordMD)
+
 	// Call post plugin hooks
 	t.tstore.PluginHookPost(plugins.HookTypeSetRecordStatusPost, string(b))
 
-	// Update inventory cache
-	switch status {
-	case backend.StatusPublic:
-		// The state is updated to vetted when a record is made public
-		t.inventoryMoveToVetted(token, status)
-	default:
-		t.inventoryUpdate(r.RecordMetadata.State, token, status)
-	}
-
 	// Return updated record
 	r, err = t.tstore.RecordLatest(token)
 	if err != nil {
 		return nil, fmt.Errorf("RecordLatest: %v", err)
 	}
 
+	// Publish event
+	t.recordEventPublish(backend.EventTypeRecordSetStatus, *r)
+
 	return r, nil
 }
 
@@ -825,6 +842,110 @@ func (t *tstoreBackend) RecordTimestamps(token []byte, version uint32) (*backend
 	return t.tstore.RecordTimestamps(token, version)
 }
 
+// RecordDiff returns the changes between two versions of a record. A to
+// version of 0 indicates the most recent version of the record.
+//
+// This function satisfies the backendv2 Backend interface.
+func (t *tstoreBackend) RecordDiff(token []byte, from, to uint32) (*backend.RecordDiff, error) {
+	log.Tracef("RecordDiff: %x %v %v", token, from, to)
+
+	if t.isShutdown() {
+		return nil, backend.ErrShutdown
+	}
+
+	// A from version must be provided. A from version of 0 would
+	// return the most recent version of the record.
+	if from == 0 {
+		return nil, backend.ErrRecordNotFound
+	}
+
+	rFrom, err := t.tstore.RecordPartial(token, from, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	rTo, err := t.tstore.RecordPartial(token, to, nil, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return backend.Diff(*rFrom, *rTo)
+}
+
+// RecordExport returns a bundle that contains the full contents of a record,
+// including all versions, all plugin data, and the timestamps of every
+// version.
+//
+// This function satisfies the backendv2 Backend interface.
+func (t *tstoreBackend) RecordExport(token []byte) (*backend.RecordBundle, error) {
+	log.Tracef("RecordExport: %x", token)
+
+	if t.isShutdown() {
+		return nil, backend.ErrShutdown
+	}
+
+	// Hold the record lock so that the record is not updated while
+	// the bundle is being compiled.
+	m := t.recordMutex(token)
+	m.Lock()
+	defer m.Unlock()
+
+	return t.tstore.RecordExport(token)
+}
+
+// RecordImport restores a record bundle as a new record and returns the token
+// of the new record. The bundle digests are verified prior to the import. The
+// record import post plugin hook is executed once the record has been
+// imported so that the plugins can build their caches for the new record.
+//
+// This function satisfies the backendv2 Backend interface.
+func (t *tstoreBackend) RecordImport(b backend.RecordBundle) ([]byte, error) {
+	log.Tracef("RecordImport: %v", b.Token)
+
+	if t.isShutdown() {
+		return nil, backend.ErrShutdown
+	}
+
+	token, err := t.tstore.RecordImport(b)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update the inventory. The created timestamp of the inventory
+	// entry is the timestamp of the original record creation.
+	r, err := t.tstore.RecordLatest(token)
+	if err != nil {
+		return nil, fmt.Errorf("RecordLatest %x: %v", token, err)
+	}
+	rm := r.RecordMetadata
+	e, err := t.invEntryBuild(rm)
+	if err != nil {
+		return nil, err
+	}
+	err = t.tstore.InvPut([]store.InvEntry{*e})
+	if err != nil {
+		return nil, fmt.Errorf("InvPut: %v", err)
+	}
+
+	// Call post plugin hooks. This gives the plugins the opportunity
+	// to build their caches for the imported record.
+	post := plugins.HookRecordImportPost{
+		Record: *r,
+	}
+	pb, err := json.Marshal(post)
+	if err != nil {
+		return nil, err
+	}
+	t.tstore.PluginHookPost(plugins.HookTypeRecordImportPost, string(pb))
+
+	log.Infof("Record imported %v as %x %v %v", b.Token, token,
+		backend.States[rm.State], backend.Statuses[rm.Status])
+
+	// Publish event
+	t.recordEventPublish(backend.EventTypeRecordImport, *r)
+
+	return token, nil
+}
+
 // Records retreives a batch of records. Individual record errors are not
 // returned. If the record was not found then it will not be included in the
 // returned map.
@@ -862,7 +983,7 @@ func (t *tstoreBackend) Records(reqs []backend.RecordRequest) (map[string]backen
 
 // Inventory returns the tokens of records in the inventory categorized by
 // record state and record status. The tokens are ordered by the timestamp of
-// their most recent status change, sorted from newest to oldest.
+// their most recent update, sorted from newest to oldest.
 //
 // The state, status, and page arguments can be provided to request a specific
 // page of record tokens.
@@ -886,7 +1007,7 @@ func (t *tstoreBackend) Inventory(state backend.StateT, status backend.StatusT,
 }
 
 // InventoryOrdered returns a page of record tokens ordered by the timestamp of
-// their most recent status change. The returned tokens will include all record
+// their most recent update. The returned tokens will include all record
 // statuses.
 //
 // This function satisfies the backendv2 Backend interface.
@@ -901,6 +1022,20 @@ func (t *tstoreBackend) InventoryOrdered(state backend.StateT, pageSize, pageNum
 	return tokens, nil
 }
 
+// InventoryQuery returns a page of inventory entries that match the provided
+// query.
+//
+// This function satisfies the backendv2 Backend interface.
+func (t *tstoreBackend) InventoryQuery(q backend.InventoryQuery) (*backend.InventoryPage, error) {
+	log.Tracef("InventoryQuery: %+v", q)
+
+	if t.isShutdown() {
+		return nil, backend.ErrShutdown
+	}
+
+	return t.invQuery(q)
+}
+
 // PluginRegister registers a plugin.
 //
 // This function satisfies the backendv2 Backend interface.
@@ -992,6 +1127,16 @@ func (t *tstoreBackend) PluginWrite(token []byte, pluginID, pluginCmd, payload s
 	}
 	t.tstore.PluginHookPost(plugins.HookTypePluginPost, string(b))
 
+	// Publish event
+	t.tstore.EventPublish(backend.Event{
+		Type:     backend.EventTypePluginWrite,
+		Token:    hex.EncodeToString(token),
+		PluginID: pluginID,
+		Name:     pluginCmd,
+		Payload:  payload,
+		Reply:    reply,
+	})
+
 	return reply, nil
 }
 
@@ -1004,6 +1149,235 @@ func (t *tstoreBackend) PluginInventory() []backend.Plugin {
 	return t.tstore.Plugins()
 }
 
+// PluginSettingsUpdate updates the settings of a registered plugin at runtime.
+//
+// This function satisfies the backendv2 Backend interface.
+func (t *tstoreBackend) PluginSettingsUpdate(pluginID string, settings []backend.PluginSetting, reason, user string) (*backend.Plugin, error) {
+	log.Tracef("PluginSettingsUpdate: %v %v", pluginID, settings)
+
+	if t.isShutdown() {
+		return nil, backend.ErrShutdown
+	}
+
+	plugin, err := t.tstore.PluginSettingsUpdate(pluginID, settings,
+		reason, user)
+	if err != nil {
+		return nil, err
+	}
+
+	// Publish event
+	t.tstore.EventPublish(backend.Event{
+		Type:     backend.EventTypePluginSettingsUpdate,
+		PluginID: pluginID,
+	})
+
+	return plugin, nil
+}
+
+// PluginSettingsHistory returns the audit trail of the runtime plugin settings
+// updates of a plugin.
+//
+// This function satisfies the backendv2 Backend interface.
+func (t *tstoreBackend) PluginSettingsHistory(pluginID string) ([]backend.PluginSettingsChange, error) {
+	log.Tracef("PluginSettingsHistory: %v", pluginID)
+
+	if t.isShutdown() {
+		return nil, backend.ErrShutdown
+	}
+
+	return t.tstore.PluginSettingsHistory(pluginID)
+}
+
+// Fsck performs a filesystem check on the backend. This includes a check of
+// the tstore and all plugins, as well as a check of the inventory. If
+// repair is set to true then any issues that can be repaired will be.
+//
+// Repairs must only be performed when the backend is not accepting writes.
+//
+// This function satisfies the backendv2 Backend interface.
+func (t *tstoreBackend) Fsck(repair bool) (*backend.FsckReport, error) {
+	log.Tracef("Fsck: %v", repair)
+
+	if t.isShutdown() {
+		return nil, backend.ErrShutdown
+	}
+
+	// Check the tstore
+	r, err := t.tstore.Fsck(repair)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the inventory
+	issues, err := t.invFsck(repair)
+	if err != nil {
+		return nil, fmt.Errorf("invFsck: %v", err)
+	}
+	r.Issues = append(r.Issues, issues...)
+
+	return r, nil
+}
+
+// RotateEncryptionKey creates a new key for encrypting unvetted data at rest
+// and starts re-encrypting the existing data using the new key in the
+// background. Prior keys are retired once they are no longer used.
+//
+// This function satisfies the backendv2 Backend interface.
+func (t *tstoreBackend) RotateEncryptionKey(keyFile string) (*backend.EncryptionKeyStatus, error) {
+	log.Tracef("RotateEncryptionKey: %v", keyFile)
+
+	if t.isShutdown() {
+		return nil, backend.ErrShutdown
+	}
+
+	return t.tstore.RotateEncryptionKey(keyFile)
+}
+
+// EncryptionKeyStatus returns the status of the keys that are used to encrypt
+// unvetted data at rest.
+//
+// This function satisfies the backendv2 Backend interface.
+func (t *tstoreBackend) EncryptionKeyStatus() (*backend.EncryptionKeyStatus, error) {
+	log.Tracef("EncryptionKeyStatus")
+
+	if t.isShutdown() {
+		return nil, backend.ErrShutdown
+	}
+
+	return t.tstore.EncryptionKeyStatus()
+}
+
+// BlobCacheStats returns the statistics of the in-memory cache of vetted
+// blobs.
+//
+// This function satisfies the backendv2 Backend interface.
+func (t *tstoreBackend) BlobCacheStats() (*backend.BlobCacheStats, error) {
+	log.Tracef("BlobCacheStats")
+
+	if t.isShutdown() {
+		return nil, backend.ErrShutdown
+	}
+
+	return t.tstore.BlobCacheStats(), nil
+}
+
+// AnchorStatus returns the status of the anchoring of the backend data.
+//
+// This function satisfies the backendv2 Backend interface.
+func (t *tstoreBackend) AnchorStatus() (*backend.AnchorStatus, error) {
+	log.Tracef("AnchorStatus")
+
+	if t.isShutdown() {
+		return nil, backend.ErrShutdown
+	}
+
+	return t.tstore.AnchorStatus()
+}
+
+// AnchorDrop drops an anchor for all data that has not been anchored yet
+// without waiting for the anchor schedule.
+//
+// This function satisfies the backendv2 Backend interface.
+func (t *tstoreBackend) AnchorDrop() (*backend.AnchorStatus, error) {
+	log.Tracef("AnchorDrop")
+
+	if t.isShutdown() {
+		return nil, backend.ErrShutdown
+	}
+
+	return t.tstore.AnchorDrop()
+}
+
+// EventsSubscribe subscribes to the backend event stream.
+//
+// This function satisfies the backendv2 Backend interface.
+func (t *tstoreBackend) EventsSubscribe(resume string) (<-chan backend.Event, error) {
+	log.Tracef("EventsSubscribe: %v", resume)
+
+	if t.isShutdown() {
+		return nil, backend.ErrShutdown
+	}
+
+	return t.tstore.EventsSubscribe(resume)
+}
+
+// EventsUnsubscribe cancels an event stream subscription.
+//
+// This function satisfies the backendv2 Backend interface.
+func (t *tstoreBackend) EventsUnsubscribe(ch <-chan backend.Event) {
+	log.Tracef("EventsUnsubscribe")
+
+	t.tstore.EventsUnsubscribe(ch)
+}
+
+// Backup saves a point-in-time snapshot of the backend to a new directory
+// inside of the provided directory.
+//
+// This function satisfies the backendv2 Backend interface.
+func (t *tstoreBackend) Backup(dir string, hook func(backupDir string) error) (*backend.BackupSummary, error) {
+	log.Tracef("Backup: %v", dir)
+
+	if t.isShutdown() {
+		return nil, backend.ErrShutdown
+	}
+
+	return t.tstore.Backup(dir, hook)
+}
+
+// RestoreVerify verifies that the backend matches the backup that is saved in
+// the provided backup directory.
+//
+// This function satisfies the backendv2 Backend interface.
+func (t *tstoreBackend) RestoreVerify(backupDir string) error {
+	log.Tracef("RestoreVerify: %v", backupDir)
+
+	if t.isShutdown() {
+		return backend.ErrShutdown
+	}
+
+	return t.tstore.RestoreVerify(backupDir)
+}
+
+// CacheRebuild starts a rebuild of the caches of the provided plugins. The
+// caches of all registered plugins are rebuilt if no plugin IDs are provided.
+//
+// This function satisfies the backendv2 Backend interface.
+func (t *tstoreBackend) CacheRebuild(pluginIDs []string) (*backend.CacheRebuildStatus, error) {
+	log.Tracef("CacheRebuild: %v", pluginIDs)
+
+	if t.isShutdown() {
+		return nil, backend.ErrShutdown
+	}
+
+	return t.tstore.CacheRebuild(pluginIDs)
+}
+
+// CacheRebuildStatus returns the status of the most recent plugin cache
+// rebuild.
+//
+// This function satisfies the backendv2 Backend interface.
+func (t *tstoreBackend) CacheRebuildStatus() (*backend.CacheRebuildStatus, error) {
+	log.Tracef("CacheRebuildStatus")
+
+	if t.isShutdown() {
+		return nil, backend.ErrShutdown
+	}
+
+	return t.tstore.CacheRebuildStatus(), nil
+}
+
+// recordEventPublish publishes a record event to the backend event stream.
+// The record files are not included in the event.
+func (t *tstoreBackend) recordEventPublish(e backend.EventT, r backend.Record) {
+	rm := r.RecordMetadata
+	t.tstore.EventPublish(backend.Event{
+		Type:           e,
+		Token:          rm.Token,
+		RecordMetadata: &rm,
+		Metadata:       r.Metadata,
+	})
+}
+
 // Close performs cleanup of the backend.
 //
 // This function satisfies the backendv2 Backend interface.
@@ -1022,14 +1396,29 @@ func (t *tstoreBackend) Close() {
 
 // setup performs any required work to setup the tstore instance.
 func (t *tstoreBackend) setup() error {
-	return t.tstore.Setup()
+	err := t.tstore.Setup()
+	if err != nil {
+		return err
+	}
+
+	// Migrate the legacy inventory files
+	return t.invMigrate()
+}
+
+// Restore restores the backup that is saved in the provided directory to a
+// new tstore deployment. This must be done prior to the tstoreBackend being
+// created. See the tstore Restore function for details.
+func Restore(backupDir, appDir, dataDir string, anp *chaincfg.Params, tlogPass, dbType, dbHost, dbPass string) error {
+	return tstore.Restore(backupDir, appDir, dataDir, anp, tlogPass,
+		dbType, dbHost, dbPass)
 }
 
 // New returns a new tstoreBackend.
-func New(appDir, dataDir string, anp *chaincfg.Params, tlogHost, tlogPass, dbType, dbHost, dbPass, dcrtimeHost, dcrtimeCert string) (*tstoreBackend, error) {
+func New(appDir, dataDir string, anp *chaincfg.Params, tlogType, tlogHost, tlogPass, dbType, dbHost, dbPass, dcrtimeHost, dcrtimeCert, anchorType, anchorSchedule string, blobCacheSize int64) (*tstoreBackend, error) {
 	// Setup tstore instances
-	ts, err := tstore.New(appDir, dataDir, anp, tlogHost,
-		tlogPass, dbType, dbHost, dbPass, dcrtimeHost, dcrtimeCert)
+	ts, err := tstore.New(appDir, dataDir, anp, tlogType, tlogHost,
+		tlogPass, dbType, dbHost, dbPass, dcrtimeHost, dcrtimeCert,
+		anchorType, anchorSchedule, blobCacheSize)
 	if err != nil {
 		return nil, fmt.Errorf("new tstore: %v", err)
 	}
@@ -5,6 +5,8 @@
 package backendv2
 
 import (
+	"bytes"
+	"crypto"
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/hex"
@@ -16,6 +18,8 @@ import (
 	dmerkle "github.com/decred/dcrtime/merkle"
 	"github.com/decred/politeia/util"
 	"github.com/google/trillian"
+	tcrypto "github.com/google/trillian/crypto"
+	"github.com/google/trillian/crypto/keys/der"
 	tmerkle "github.com/google/trillian/merkle"
 	"github.com/google/trillian/merkle/hashers/registry"
 )
@@ -31,9 +35,58 @@ const (
 
 // ExtraDataTrillianRFC6962 contains the extra data required to verify a
 // trillian inclusion proof.
+//
+// The signed log root fields are optional. When present, they contain the
+// trillian log root that the proof resolves to and its signature, which allows
+// the log root to be verified without trusting the server that returned it.
+// The log root is a hex encoded trillian LogRootV1. The public key is the hex
+// encoded DER public key of the tlog signing key.
 type ExtraDataTrillianRFC6962 struct {
 	LeafIndex int64 `json:"leafindex"`
 	TreeSize  int64 `json:"treesize"`
+
+	LogRoot          string `json:"logroot,omitempty"`
+	LogRootSignature string `json:"logrootsignature,omitempty"`
+	PublicKey        string `json:"publickey,omitempty"`
+}
+
+// verifySignedLogRoot verifies the signed log root that is included in the
+// trillian proof extra data. The log root must be signed by the provided
+// public key and must match the merkle root and tree size of the proof.
+func verifySignedLogRoot(ed ExtraDataTrillianRFC6962, merkleRoot []byte) error {
+	logRoot, err := hex.DecodeString(ed.LogRoot)
+	if err != nil {
+		return err
+	}
+	sig, err := hex.DecodeString(ed.LogRootSignature)
+	if err != nil {
+		return err
+	}
+	pk, err := hex.DecodeString(ed.PublicKey)
+	if err != nil {
+		return err
+	}
+	pub, err := der.UnmarshalPublicKey(pk)
+	if err != nil {
+		return err
+	}
+	lr, err := tcrypto.VerifySignedLogRoot(pub, crypto.SHA256,
+		&trillian.SignedLogRoot{
+			LogRoot:          logRoot,
+			LogRootSignature: sig,
+		})
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(lr.RootHash, merkleRoot) {
+		return fmt.Errorf("log root hash %x does not match merkle root %x",
+			lr.RootHash, merkleRoot)
+	}
+	if lr.TreeSize != uint64(ed.TreeSize) {
+		return fmt.Errorf("log root tree size %v does not match proof "+
+			"tree size %v", lr.TreeSize, ed.TreeSize)
+	}
+	return nil
 }
 
 // verifyProofTrillian verifies a proof with the type ProofTypeTrillianRFC6962.
@@ -77,8 +130,21 @@ func verifyProofTrillian(p Proof) error {
 	}
 
 	verifier := tmerkle.NewLogVerifier(h)
-	return verifier.VerifyInclusionProof(ed.LeafIndex, ed.TreeSize,
+	err = verifier.VerifyInclusionProof(ed.LeafIndex, ed.TreeSize,
 		merklePath, merkleRoot, leafHash)
+	if err != nil {
+		return err
+	}
+
+	// Verify the signed log root if one was provided
+	if ed.LogRoot != "" {
+		err = verifySignedLogRoot(ed, merkleRoot)
+		if err != nil {
+			return fmt.Errorf("invalid signed log root: %v", err)
+		}
+	}
+
+	return nil
 }
 
 // ExtraDataDcrtime contains the extra data required to verify a dcrtime
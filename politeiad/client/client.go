@@ -7,6 +7,7 @@ package client
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -113,3 +114,18 @@ func New(rpcHost, rpcCert, rpcUser, rpcPass string, pid *identity.PublicIdentity
 		pid:     pid,
 	}, nil
 }
+
+// SetClientCert sets the client certificate that is presented to politeiad.
+// It is used when politeiad has client certificate authentication enabled.
+func (c *Client) SetClientCert(certFile, keyFile string) error {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return err
+	}
+	t, ok := c.http.Transport.(*http.Transport)
+	if !ok || t.TLSClientConfig == nil {
+		return fmt.Errorf("http transport does not support tls")
+	}
+	t.TLSClientConfig.Certificates = []tls.Certificate{cert}
+	return nil
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+
+	pdv2 "github.com/decred/politeia/politeiad/api/v2"
+	"github.com/decred/politeia/util"
+	"github.com/gorilla/websocket"
+)
+
+// EventStream is a politeiad v2 event stream.
+type EventStream struct {
+	conn *websocket.Conn
+}
+
+// Events opens a politeiad v2 event stream. The events that were published
+// after the provided resume token, i.e. the ID of the last event that was
+// received, are sent before any new events. An empty resume token streams new
+// events only.
+func (c *Client) Events(ctx context.Context, resume string) (*EventStream, error) {
+	// Setup request
+	challenge, err := util.Random(pdv2.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+	u, err := url.Parse(c.rpcHost + pdv2.APIRoute + pdv2.RouteEvents)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "https":
+		u.Scheme = "wss"
+	case "http":
+		u.Scheme = "ws"
+	}
+	q := url.Values{}
+	q.Set(pdv2.EventsQueryChallenge, hex.EncodeToString(challenge))
+	if resume != "" {
+		q.Set(pdv2.EventsQueryResume, resume)
+	}
+	u.RawQuery = q.Encode()
+
+	// The basic auth header is created using a throwaway request so
+	// that it is encoded the same way as the other requests.
+	req, err := http.NewRequest(http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.SetBasicAuth(c.rpcUser, c.rpcPass)
+
+	// Open the websocket connection
+	var d websocket.Dialer
+	if t, ok := c.http.Transport.(*http.Transport); ok {
+		d.TLSClientConfig = t.TLSClientConfig
+	}
+	conn, r, err := d.DialContext(ctx, u.String(), req.Header)
+	if err != nil {
+		if r == nil || r.StatusCode == http.StatusSwitchingProtocols {
+			return nil, err
+		}
+		// politeiad replied with an error
+		var e ErrorReply
+		decoder := json.NewDecoder(r.Body)
+		if err := decoder.Decode(&e); err != nil {
+			return nil, err
+		}
+		return nil, RespError{
+			HTTPCode:   r.StatusCode,
+			ErrorReply: e,
+		}
+	}
+
+	// Verify reply
+	var er pdv2.EventsReply
+	err = conn.ReadJSON(&er)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	err = util.VerifyChallenge(c.pid, challenge, er.Response)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &EventStream{
+		conn: conn,
+	}, nil
+}
+
+// Next blocks until the next event is received. An error is returned once the
+// event stream has been closed, either by the client or by politeiad. The
+// client should reopen the event stream using the ID of the last event that
+// it received.
+func (s *EventStream) Next() (*pdv2.Event, error) {
+	var e pdv2.Event
+	err := s.conn.ReadJSON(&e)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// Close closes the event stream.
+func (s *EventStream) Close() error {
+	return s.conn.Close()
+}
@@ -0,0 +1,566 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+
+	grpcv2 "github.com/decred/politeia/politeiad/api/grpc/v2"
+	"github.com/decred/politeia/politeiad/api/v1/identity"
+	pdv2 "github.com/decred/politeia/politeiad/api/v2"
+	"github.com/decred/politeia/util"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/status"
+)
+
+// GRPCClient provides a client for interacting with the politeiad v2 gRPC
+// service. Record file payloads are sent and received as raw bytes.
+//
+// Errors that are returned by politeiad are converted into a RespError using
+// the http status code that the politeiad JSON API would have returned so
+// that callers can handle the errors of both clients the same way.
+type GRPCClient struct {
+	conn   *grpc.ClientConn
+	client grpcv2.PoliteiadClient
+	pid    *identity.PublicIdentity
+}
+
+// basicAuth provides the politeiad RPC credentials on every gRPC call.
+type basicAuth struct {
+	user string
+	pass string
+}
+
+// GetRequestMetadata returns the basic authentication header.
+//
+// This function satisfies the grpc credentials PerRPCCredentials interface.
+func (a basicAuth) GetRequestMetadata(ctx context.Context, uri ...string) (map[string]string, error) {
+	auth := base64.StdEncoding.EncodeToString([]byte(a.user + ":" + a.pass))
+	return map[string]string{
+		"authorization": "Basic " + auth,
+	}, nil
+}
+
+// RequireTransportSecurity returns true since the credentials must only be
+// sent over TLS.
+//
+// This function satisfies the grpc credentials PerRPCCredentials interface.
+func (a basicAuth) RequireTransportSecurity() bool {
+	return true
+}
+
+// NewGRPC returns a new politeiad gRPC client. The host must be provided in
+// the host:port format. The connection should be closed using Close once the
+// client is no longer needed.
+func NewGRPC(host, rpcCert, rpcUser, rpcPass string, pid *identity.PublicIdentity) (*GRPCClient, error) {
+	// Setup TLS config. The politeiad certificate is added to the
+	// system cert pool.
+	tlsConfig := &tls.Config{}
+	if rpcCert != "" {
+		cert, err := ioutil.ReadFile(rpcCert)
+		if err != nil {
+			return nil, err
+		}
+		certPool, err := x509.SystemCertPool()
+		if err != nil {
+			certPool = x509.NewCertPool()
+		}
+		certPool.AppendCertsFromPEM(cert)
+		tlsConfig.RootCAs = certPool
+	}
+
+	// Setup connection
+	conn, err := grpc.Dial(host,
+		grpc.WithTransportCredentials(credentials.NewTLS(tlsConfig)),
+		grpc.WithPerRPCCredentials(basicAuth{
+			user: rpcUser,
+			pass: rpcPass,
+		}))
+	if err != nil {
+		return nil, err
+	}
+
+	return &GRPCClient{
+		conn:   conn,
+		client: grpcv2.NewPoliteiadClient(conn),
+		pid:    pid,
+	}, nil
+}
+
+// Close closes the gRPC connection.
+func (c *GRPCClient) Close() error {
+	return c.conn.Close()
+}
+
+// RecordNew sends a RecordNew command to the politeiad gRPC service. The file
+// payloads are not included in the returned record.
+func (c *GRPCClient) RecordNew(ctx context.Context, metadata []pdv2.MetadataStream, files []*grpcv2.File) (*grpcv2.Record, error) {
+	// Setup request
+	challenge, err := util.Random(pdv2.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+	rn := grpcv2.RecordNew{
+		Challenge: hex.EncodeToString(challenge),
+		Metadata:  convertMetadataStreamsToGRPC(metadata),
+		Files:     grpcFilesOmitPayloads(files),
+	}
+
+	// Send request
+	stream, err := c.client.RecordNew(ctx)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	err = stream.Send(&grpcv2.RecordNewMsg{
+		RecordNew: &rn,
+	})
+	if err == nil {
+		err = grpcFilesSend(files, func(fc *grpcv2.FileChunk) error {
+			return stream.Send(&grpcv2.RecordNewMsg{
+				Chunk: fc,
+			})
+		})
+	}
+	if err != nil && err != io.EOF {
+		// An io.EOF indicates that the server has closed the
+		// stream. The status is returned by CloseAndRecv.
+		return nil, convertGRPCError(err)
+	}
+
+	// Receive reply
+	rnr, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	err = util.VerifyChallenge(c.pid, challenge, rnr.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	return rnr.Record, nil
+}
+
+// RecordEdit sends a RecordEdit command to the politeiad gRPC service. The
+// file payloads are not included in the returned record.
+func (c *GRPCClient) RecordEdit(ctx context.Context, token string, mdAppend, mdOverwrite []pdv2.MetadataStream, filesAdd []*grpcv2.File, filesDel []string) (*grpcv2.Record, error) {
+	// Setup request
+	challenge, err := util.Random(pdv2.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+	re := grpcv2.RecordEdit{
+		Challenge:   hex.EncodeToString(challenge),
+		Token:       token,
+		MdAppend:    convertMetadataStreamsToGRPC(mdAppend),
+		MdOverwrite: convertMetadataStreamsToGRPC(mdOverwrite),
+		FilesAdd:    grpcFilesOmitPayloads(filesAdd),
+		FilesDel:    filesDel,
+	}
+
+	// Send request
+	stream, err := c.client.RecordEdit(ctx)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	err = stream.Send(&grpcv2.RecordEditMsg{
+		RecordEdit: &re,
+	})
+	if err == nil {
+		err = grpcFilesSend(filesAdd, func(fc *grpcv2.FileChunk) error {
+			return stream.Send(&grpcv2.RecordEditMsg{
+				Chunk: fc,
+			})
+		})
+	}
+	if err != nil && err != io.EOF {
+		// An io.EOF indicates that the server has closed the
+		// stream. The status is returned by CloseAndRecv.
+		return nil, convertGRPCError(err)
+	}
+
+	// Receive reply
+	rer, err := stream.CloseAndRecv()
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	err = util.VerifyChallenge(c.pid, challenge, rer.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	return rer.Record, nil
+}
+
+// Records sends a Records command to the politeiad gRPC service. The returned
+// records include the payloads of the requested files.
+func (c *GRPCClient) Records(ctx context.Context, reqs []pdv2.RecordRequest) (map[string]*grpcv2.Record, error) {
+	// Setup request
+	challenge, err := util.Random(pdv2.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+	r := grpcv2.Records{
+		Challenge: hex.EncodeToString(challenge),
+		Requests:  make([]*grpcv2.RecordRequest, 0, len(reqs)),
+	}
+	for _, v := range reqs {
+		r.Requests = append(r.Requests, &grpcv2.RecordRequest{
+			Token:        v.Token,
+			Version:      v.Version,
+			Filenames:    v.Filenames,
+			OmitAllFiles: v.OmitAllFiles,
+		})
+	}
+
+	// Send request
+	stream, err := c.client.Records(ctx, &r)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+
+	// Receive the challenge response
+	m, err := stream.Recv()
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	err = util.VerifyChallenge(c.pid, challenge, m.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	// Receive the records and their file payloads
+	var (
+		records = make(map[string]*grpcv2.Record, len(reqs))
+		current *grpcv2.Record
+	)
+	for {
+		m, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, convertGRPCError(err)
+		}
+		switch {
+		case m.Record != nil:
+			current = m.Record
+			records[m.Token] = current
+		case m.Chunk != nil:
+			if current == nil || int(m.Chunk.File) >= len(current.Files) ||
+				current.Files[m.Chunk.File] == nil {
+				return nil, fmt.Errorf("unexpected file chunk")
+			}
+			f := current.Files[m.Chunk.File]
+			f.Payload = append(f.Payload, m.Chunk.Data...)
+		default:
+			return nil, fmt.Errorf("empty records message")
+		}
+	}
+
+	return records, nil
+}
+
+// RecordTimestamps sends a RecordTimestamps command to the politeiad gRPC
+// service.
+func (c *GRPCClient) RecordTimestamps(ctx context.Context, token string, version uint32) (*pdv2.RecordTimestampsReply, error) {
+	// Setup request
+	challenge, err := util.Random(pdv2.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+	rt := grpcv2.RecordTimestamps{
+		Challenge: hex.EncodeToString(challenge),
+		Token:     token,
+		Version:   version,
+	}
+
+	// Send request
+	rtr, err := c.client.RecordTimestamps(ctx, &rt)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	err = util.VerifyChallenge(c.pid, challenge, rtr.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	return convertRecordTimestampsReplyFromGRPC(rtr), nil
+}
+
+// Inventory sends an Inventory command to the politeiad gRPC service.
+func (c *GRPCClient) Inventory(ctx context.Context, state pdv2.RecordStateT, status pdv2.RecordStatusT, page uint32) (*pdv2.InventoryReply, error) {
+	// Setup request
+	challenge, err := util.Random(pdv2.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+	i := grpcv2.Inventory{
+		Challenge: hex.EncodeToString(challenge),
+		State:     grpcv2.RecordState(state),
+		Status:    grpcv2.RecordStatus(status),
+		Page:      page,
+	}
+
+	// Send request
+	ir, err := c.client.Inventory(ctx, &i)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	err = util.VerifyChallenge(c.pid, challenge, ir.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pdv2.InventoryReply{
+		Response: ir.Response,
+		Unvetted: convertTokensFromGRPC(ir.Unvetted),
+		Vetted:   convertTokensFromGRPC(ir.Vetted),
+	}, nil
+}
+
+// PluginWrite sends a PluginWrite command to the politeiad gRPC service.
+func (c *GRPCClient) PluginWrite(ctx context.Context, cmd pdv2.PluginCmd) (string, error) {
+	// Setup request
+	challenge, err := util.Random(pdv2.ChallengeSize)
+	if err != nil {
+		return "", err
+	}
+	pw := grpcv2.PluginWrite{
+		Challenge: hex.EncodeToString(challenge),
+		Cmd:       convertPluginCmdToGRPC(cmd),
+	}
+
+	// Send request
+	pwr, err := c.client.PluginWrite(ctx, &pw)
+	if err != nil {
+		return "", convertGRPCError(err)
+	}
+	err = util.VerifyChallenge(c.pid, challenge, pwr.Response)
+	if err != nil {
+		return "", err
+	}
+
+	return pwr.Payload, nil
+}
+
+// PluginReads sends a PluginReads command to the politeiad gRPC service.
+func (c *GRPCClient) PluginReads(ctx context.Context, cmds []pdv2.PluginCmd) ([]pdv2.PluginCmdReply, error) {
+	// Setup request
+	challenge, err := util.Random(pdv2.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+	pr := grpcv2.PluginReads{
+		Challenge: hex.EncodeToString(challenge),
+		Cmds:      make([]*grpcv2.PluginCmd, 0, len(cmds)),
+	}
+	for _, v := range cmds {
+		pr.Cmds = append(pr.Cmds, convertPluginCmdToGRPC(v))
+	}
+
+	// Send request
+	prr, err := c.client.PluginReads(ctx, &pr)
+	if err != nil {
+		return nil, convertGRPCError(err)
+	}
+	err = util.VerifyChallenge(c.pid, challenge, prr.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	replies := make([]pdv2.PluginCmdReply, 0, len(prr.Replies))
+	for _, v := range prr.Replies {
+		reply := pdv2.PluginCmdReply{
+			Token:   v.GetToken(),
+			ID:      v.GetId(),
+			Command: v.GetCommand(),
+			Payload: v.GetPayload(),
+		}
+		if ue := v.GetUserError(); ue != nil {
+			reply.UserError = &pdv2.UserErrorReply{
+				ErrorCode:    pdv2.ErrorCodeT(ue.ErrorCode),
+				ErrorContext: ue.ErrorContext,
+			}
+		}
+		if pe := v.GetPluginError(); pe != nil {
+			reply.PluginError = &pdv2.PluginErrorReply{
+				PluginID:     pe.PluginId,
+				ErrorCode:    pe.ErrorCode,
+				ErrorContext: pe.ErrorContext,
+			}
+		}
+		replies = append(replies, reply)
+	}
+
+	return replies, nil
+}
+
+// grpcFilesOmitPayloads returns a copy of the provided files without the
+// file payloads. The payloads are sent separately as file chunks.
+func grpcFilesOmitPayloads(files []*grpcv2.File) []*grpcv2.File {
+	f := make([]*grpcv2.File, 0, len(files))
+	for _, v := range files {
+		f = append(f, &grpcv2.File{
+			Name:   v.GetName(),
+			Mime:   v.GetMime(),
+			Digest: v.GetDigest(),
+		})
+	}
+	return f
+}
+
+// grpcFilesSend splits the payloads of the provided files into chunks and
+// sends them using the provided send function.
+func grpcFilesSend(files []*grpcv2.File, send func(*grpcv2.FileChunk) error) error {
+	for i, v := range files {
+		payload := v.GetPayload()
+		for len(payload) > 0 {
+			n := len(payload)
+			if n > grpcv2.ChunkSize {
+				n = grpcv2.ChunkSize
+			}
+			err := send(&grpcv2.FileChunk{
+				File: uint32(i),
+				Data: payload[:n],
+			})
+			if err != nil {
+				return err
+			}
+			payload = payload[n:]
+		}
+	}
+	return nil
+}
+
+func convertMetadataStreamsToGRPC(streams []pdv2.MetadataStream) []*grpcv2.MetadataStream {
+	ms := make([]*grpcv2.MetadataStream, 0, len(streams))
+	for _, v := range streams {
+		ms = append(ms, &grpcv2.MetadataStream{
+			PluginId: v.PluginID,
+			StreamId: v.StreamID,
+			Payload:  v.Payload,
+		})
+	}
+	return ms
+}
+
+func convertPluginCmdToGRPC(cmd pdv2.PluginCmd) *grpcv2.PluginCmd {
+	return &grpcv2.PluginCmd{
+		Token:   cmd.Token,
+		Id:      cmd.ID,
+		Command: cmd.Command,
+		Payload: cmd.Payload,
+	}
+}
+
+func convertTokensFromGRPC(tokens map[string]*grpcv2.Tokens) map[string][]string {
+	t := make(map[string][]string, len(tokens))
+	for k, v := range tokens {
+		t[k] = v.GetTokens()
+	}
+	return t
+}
+
+func convertTimestampFromGRPC(t *grpcv2.Timestamp) pdv2.Timestamp {
+	proofs := make([]pdv2.Proof, 0, len(t.GetProofs()))
+	for _, v := range t.GetProofs() {
+		proofs = append(proofs, pdv2.Proof{
+			Type:       v.GetType(),
+			Digest:     v.GetDigest(),
+			MerkleRoot: v.GetMerkleRoot(),
+			MerklePath: v.GetMerklePath(),
+			ExtraData:  v.GetExtraData(),
+		})
+	}
+	return pdv2.Timestamp{
+		Data:       t.GetData(),
+		Digest:     t.GetDigest(),
+		TxID:       t.GetTxId(),
+		MerkleRoot: t.GetMerkleRoot(),
+		Proofs:     proofs,
+	}
+}
+
+func convertRecordTimestampsReplyFromGRPC(r *grpcv2.RecordTimestampsReply) *pdv2.RecordTimestampsReply {
+	metadata := make(map[string]map[uint32]pdv2.Timestamp, len(r.Metadata))
+	for pluginID, st := range r.Metadata {
+		streams := make(map[uint32]pdv2.Timestamp, len(st.GetStreams()))
+		for streamID, v := range st.GetStreams() {
+			streams[streamID] = convertTimestampFromGRPC(v)
+		}
+		metadata[pluginID] = streams
+	}
+	files := make(map[string]pdv2.Timestamp, len(r.Files))
+	for k, v := range r.Files {
+		files[k] = convertTimestampFromGRPC(v)
+	}
+	return &pdv2.RecordTimestampsReply{
+		Response:       r.Response,
+		RecordMetadata: convertTimestampFromGRPC(r.RecordMetadata),
+		Metadata:       metadata,
+		Files:          files,
+	}
+}
+
+// convertGRPCError converts a gRPC status error that was returned by
+// politeiad into a RespError. Errors that were not returned by politeiad are
+// returned unmodified.
+func convertGRPCError(err error) error {
+	s, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	switch s.Code() {
+	case codes.InvalidArgument:
+		// User error or plugin error. The status message contains the
+		// JSON encoded error reply.
+		var e ErrorReply
+		if err := json.Unmarshal([]byte(s.Message()), &e); err != nil {
+			return err
+		}
+		return RespError{
+			HTTPCode:   http.StatusBadRequest,
+			ErrorReply: e,
+		}
+	case codes.Unauthenticated:
+		return RespError{
+			HTTPCode: http.StatusUnauthorized,
+		}
+	case codes.PermissionDenied:
+		return RespError{
+			HTTPCode: http.StatusForbidden,
+			ErrorReply: ErrorReply{
+				ErrorCode:    uint32(pdv2.ErrorCodeAccessDenied),
+				ErrorContext: s.Message(),
+			},
+		}
+	case codes.Internal:
+		// Internal server error. The status message contains the error
+		// code that can be used to find the error in the server logs.
+		errCode, err := strconv.ParseUint(s.Message(), 10, 32)
+		if err != nil {
+			return s.Err()
+		}
+		return RespError{
+			HTTPCode: http.StatusInternalServerError,
+			ErrorReply: ErrorReply{
+				ErrorCode: uint32(errCode),
+			},
+		}
+	}
+	return err
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"net/http"
 
 	pdv1 "github.com/decred/politeia/politeiad/api/v1"
@@ -52,6 +53,58 @@ func (c *Client) Identity(ctx context.Context) (*identity.PublicIdentity, error)
 	return pid, nil
 }
 
+// IdentityHistory sends a Identity request to the politeiad v1 API and
+// returns the politeiad identity key history. The history is verified prior
+// to being returned. An empty history is returned if the politeiad identity
+// has never been rotated.
+func (c *Client) IdentityHistory(ctx context.Context) ([]pdv1.IdentityKey, error) {
+	// Setup request
+	challenge, err := util.Random(pdv1.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+	i := v1.Identity{
+		Challenge: hex.EncodeToString(challenge),
+	}
+
+	// Send request
+	resBody, err := c.makeReq(ctx, http.MethodPost, "",
+		pdv1.IdentityRoute, i)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var ir v1.IdentityReply
+	err = json.Unmarshal(resBody, &ir)
+	if err != nil {
+		return nil, err
+	}
+	pid, err := util.IdentityFromString(ir.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	err = util.VerifyChallenge(pid, challenge, ir.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify history
+	if len(ir.History) == 0 {
+		return []pdv1.IdentityKey{}, nil
+	}
+	err = pdv1.VerifyIdentityHistory(ir.History)
+	if err != nil {
+		return nil, err
+	}
+	if ir.History[len(ir.History)-1].PublicKey != ir.PublicKey {
+		return nil, fmt.Errorf("public key is not the last key of the " +
+			"identity history")
+	}
+
+	return ir.History, nil
+}
+
 // NewRecord sends a NewRecord request to the politeiad v1 API.
 func (c *Client) NewRecord(ctx context.Context, metadata []pdv1.MetadataStream, files []pdv1.File) (*pdv1.CensorshipRecord, error) {
 	// Setup request
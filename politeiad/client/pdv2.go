@@ -195,6 +195,41 @@ func (c *Client) RecordTimestamps(ctx context.Context, token string, version uin
 	return &reply, nil
 }
 
+// RecordDiff sends a RecordDiff command to the politeiad v2 API.
+func (c *Client) RecordDiff(ctx context.Context, token string, from, to uint32) (*pdv2.RecordDiffReply, error) {
+	// Setup request
+	challenge, err := util.Random(pdv2.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+	rd := pdv2.RecordDiff{
+		Challenge: hex.EncodeToString(challenge),
+		Token:     token,
+		From:      from,
+		To:        to,
+	}
+
+	// Send request
+	resBody, err := c.makeReq(ctx, http.MethodPost,
+		pdv2.APIRoute, pdv2.RouteRecordDiff, rd)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var reply pdv2.RecordDiffReply
+	err = json.Unmarshal(resBody, &reply)
+	if err != nil {
+		return nil, err
+	}
+	err = util.VerifyChallenge(c.pid, challenge, reply.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &reply, nil
+}
+
 // Records sends a Records command to the politeiad v2 API.
 func (c *Client) Records(ctx context.Context, reqs []pdv2.RecordRequest) (map[string]pdv2.Record, error) {
 	// Setup request
@@ -297,6 +332,37 @@ func (c *Client) InventoryOrdered(ctx context.Context, state pdv2.RecordStateT,
 	return ir.Tokens, nil
 }
 
+// InventoryQuery sends a InventoryQuery command to the politeiad v2 API. The
+// challenge field of the provided query is populated by this method.
+func (c *Client) InventoryQuery(ctx context.Context, q pdv2.InventoryQuery) (*pdv2.InventoryQueryReply, error) {
+	// Setup request
+	challenge, err := util.Random(pdv2.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+	q.Challenge = hex.EncodeToString(challenge)
+
+	// Send request
+	resBody, err := c.makeReq(ctx, http.MethodPost,
+		pdv2.APIRoute, pdv2.RouteInventoryQuery, q)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var iqr pdv2.InventoryQueryReply
+	err = json.Unmarshal(resBody, &iqr)
+	if err != nil {
+		return nil, err
+	}
+	err = util.VerifyChallenge(c.pid, challenge, iqr.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &iqr, nil
+}
+
 // PluginWrite sends a PluginWrite command to the politeiad v2 API.
 func (c *Client) PluginWrite(ctx context.Context, cmd pdv2.PluginCmd) (string, error) {
 	// Setup request
@@ -395,6 +461,436 @@ func (c *Client) PluginInventory(ctx context.Context) ([]pdv2.Plugin, error) {
 	return pir.Plugins, nil
 }
 
+// Fsck sends a Fsck command to the politeiad v2 API.
+func (c *Client) Fsck(ctx context.Context) (*pdv2.FsckReply, error) {
+	// Setup request
+	challenge, err := util.Random(pdv2.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+	f := pdv2.Fsck{
+		Challenge: hex.EncodeToString(challenge),
+	}
+
+	// Send request
+	resBody, err := c.makeReq(ctx, http.MethodPost,
+		pdv2.APIRoute, pdv2.RouteFsck, f)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var fr pdv2.FsckReply
+	err = json.Unmarshal(resBody, &fr)
+	if err != nil {
+		return nil, err
+	}
+	err = util.VerifyChallenge(c.pid, challenge, fr.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fr, nil
+}
+
+// EncryptionKeyRotate sends a EncryptionKeyRotate command to the politeiad v2
+// API. The key file is the path of the new key file on the politeiad host.
+// politeiad uses a default key file if it is empty.
+func (c *Client) EncryptionKeyRotate(ctx context.Context, keyFile string) (*pdv2.EncryptionKeys, error) {
+	// Setup request
+	challenge, err := util.Random(pdv2.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+	e := pdv2.EncryptionKeyRotate{
+		Challenge: hex.EncodeToString(challenge),
+		KeyFile:   keyFile,
+	}
+
+	// Send request
+	resBody, err := c.makeReq(ctx, http.MethodPost,
+		pdv2.APIRoute, pdv2.RouteEncryptionKeyRotate, e)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var er pdv2.EncryptionKeyRotateReply
+	err = json.Unmarshal(resBody, &er)
+	if err != nil {
+		return nil, err
+	}
+	err = util.VerifyChallenge(c.pid, challenge, er.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &er.Keys, nil
+}
+
+// EncryptionKeyStatus sends a EncryptionKeyStatus command to the politeiad v2
+// API.
+func (c *Client) EncryptionKeyStatus(ctx context.Context) (*pdv2.EncryptionKeys, error) {
+	// Setup request
+	challenge, err := util.Random(pdv2.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+	e := pdv2.EncryptionKeyStatus{
+		Challenge: hex.EncodeToString(challenge),
+	}
+
+	// Send request
+	resBody, err := c.makeReq(ctx, http.MethodPost,
+		pdv2.APIRoute, pdv2.RouteEncryptionKeyStatus, e)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var er pdv2.EncryptionKeyStatusReply
+	err = json.Unmarshal(resBody, &er)
+	if err != nil {
+		return nil, err
+	}
+	err = util.VerifyChallenge(c.pid, challenge, er.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &er.Keys, nil
+}
+
+// BlobCacheStats sends a BlobCacheStats command to the politeiad v2 API.
+func (c *Client) BlobCacheStats(ctx context.Context) (*pdv2.BlobCache, error) {
+	// Setup request
+	challenge, err := util.Random(pdv2.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+	bc := pdv2.BlobCacheStats{
+		Challenge: hex.EncodeToString(challenge),
+	}
+
+	// Send request
+	resBody, err := c.makeReq(ctx, http.MethodPost,
+		pdv2.APIRoute, pdv2.RouteBlobCacheStats, bc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var bcr pdv2.BlobCacheStatsReply
+	err = json.Unmarshal(resBody, &bcr)
+	if err != nil {
+		return nil, err
+	}
+	err = util.VerifyChallenge(c.pid, challenge, bcr.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bcr.Cache, nil
+}
+
+// AnchorStatus sends a AnchorStatus command to the politeiad v2 API.
+func (c *Client) AnchorStatus(ctx context.Context) (*pdv2.Anchors, error) {
+	// Setup request
+	challenge, err := util.Random(pdv2.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+	as := pdv2.AnchorStatus{
+		Challenge: hex.EncodeToString(challenge),
+	}
+
+	// Send request
+	resBody, err := c.makeReq(ctx, http.MethodPost,
+		pdv2.APIRoute, pdv2.RouteAnchorStatus, as)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var asr pdv2.AnchorStatusReply
+	err = json.Unmarshal(resBody, &asr)
+	if err != nil {
+		return nil, err
+	}
+	err = util.VerifyChallenge(c.pid, challenge, asr.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &asr.Anchors, nil
+}
+
+// AnchorDrop sends a AnchorDrop command to the politeiad v2 API.
+func (c *Client) AnchorDrop(ctx context.Context) (*pdv2.Anchors, error) {
+	// Setup request
+	challenge, err := util.Random(pdv2.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+	ad := pdv2.AnchorDrop{
+		Challenge: hex.EncodeToString(challenge),
+	}
+
+	// Send request
+	resBody, err := c.makeReq(ctx, http.MethodPost,
+		pdv2.APIRoute, pdv2.RouteAnchorDrop, ad)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var adr pdv2.AnchorDropReply
+	err = json.Unmarshal(resBody, &adr)
+	if err != nil {
+		return nil, err
+	}
+	err = util.VerifyChallenge(c.pid, challenge, adr.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &adr.Anchors, nil
+}
+
+// RecordExport sends a RecordExport command to the politeiad v2 API.
+func (c *Client) RecordExport(ctx context.Context, token string) (*pdv2.RecordBundle, error) {
+	// Setup request
+	challenge, err := util.Random(pdv2.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+	re := pdv2.RecordExport{
+		Challenge: hex.EncodeToString(challenge),
+		Token:     token,
+	}
+
+	// Send request
+	resBody, err := c.makeReq(ctx, http.MethodPost,
+		pdv2.APIRoute, pdv2.RouteRecordExport, re)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var rer pdv2.RecordExportReply
+	err = json.Unmarshal(resBody, &rer)
+	if err != nil {
+		return nil, err
+	}
+	err = util.VerifyChallenge(c.pid, challenge, rer.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rer.Bundle, nil
+}
+
+// RecordImport sends a RecordImport command to the politeiad v2 API. The
+// token of the imported record is returned.
+func (c *Client) RecordImport(ctx context.Context, b pdv2.RecordBundle) (string, error) {
+	// Setup request
+	challenge, err := util.Random(pdv2.ChallengeSize)
+	if err != nil {
+		return "", err
+	}
+	ri := pdv2.RecordImport{
+		Challenge: hex.EncodeToString(challenge),
+		Bundle:    b,
+	}
+
+	// Send request
+	resBody, err := c.makeReq(ctx, http.MethodPost,
+		pdv2.APIRoute, pdv2.RouteRecordImport, ri)
+	if err != nil {
+		return "", err
+	}
+
+	// Decode reply
+	var rir pdv2.RecordImportReply
+	err = json.Unmarshal(resBody, &rir)
+	if err != nil {
+		return "", err
+	}
+	err = util.VerifyChallenge(c.pid, challenge, rir.Response)
+	if err != nil {
+		return "", err
+	}
+
+	return rir.Token, nil
+}
+
+// PluginSettings sends a PluginSettings command to the politeiad v2 API.
+func (c *Client) PluginSettings(ctx context.Context, pluginID string) (*pdv2.PluginSettingsReply, error) {
+	// Setup request
+	challenge, err := util.Random(pdv2.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+	ps := pdv2.PluginSettings{
+		Challenge: hex.EncodeToString(challenge),
+		PluginID:  pluginID,
+	}
+
+	// Send request
+	resBody, err := c.makeReq(ctx, http.MethodPost,
+		pdv2.APIRoute, pdv2.RoutePluginSettings, ps)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var psr pdv2.PluginSettingsReply
+	err = json.Unmarshal(resBody, &psr)
+	if err != nil {
+		return nil, err
+	}
+	err = util.VerifyChallenge(c.pid, challenge, psr.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &psr, nil
+}
+
+// PluginSettingsUpdate sends a PluginSettingsUpdate command to the politeiad
+// v2 API. The updated plugin settings are returned.
+func (c *Client) PluginSettingsUpdate(ctx context.Context, pluginID string, settings []pdv2.PluginSetting, reason string) (*pdv2.Plugin, error) {
+	// Setup request
+	challenge, err := util.Random(pdv2.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+	psu := pdv2.PluginSettingsUpdate{
+		Challenge: hex.EncodeToString(challenge),
+		PluginID:  pluginID,
+		Settings:  settings,
+		Reason:    reason,
+	}
+
+	// Send request
+	resBody, err := c.makeReq(ctx, http.MethodPost,
+		pdv2.APIRoute, pdv2.RoutePluginSettingsUpdate, psu)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var psur pdv2.PluginSettingsUpdateReply
+	err = json.Unmarshal(resBody, &psur)
+	if err != nil {
+		return nil, err
+	}
+	err = util.VerifyChallenge(c.pid, challenge, psur.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &psur.Plugin, nil
+}
+
+// Backup sends a Backup command to the politeiad v2 API.
+func (c *Client) Backup(ctx context.Context) (*pdv2.BackupReply, error) {
+	// Setup request
+	challenge, err := util.Random(pdv2.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+	b := pdv2.Backup{
+		Challenge: hex.EncodeToString(challenge),
+	}
+
+	// Send request
+	resBody, err := c.makeReq(ctx, http.MethodPost,
+		pdv2.APIRoute, pdv2.RouteBackup, b)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var br pdv2.BackupReply
+	err = json.Unmarshal(resBody, &br)
+	if err != nil {
+		return nil, err
+	}
+	err = util.VerifyChallenge(c.pid, challenge, br.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &br, nil
+}
+
+// CacheRebuild sends a CacheRebuild command to the politeiad v2 API.
+func (c *Client) CacheRebuild(ctx context.Context, pluginIDs []string) (*pdv2.CacheRebuildState, error) {
+	// Setup request
+	challenge, err := util.Random(pdv2.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+	cr := pdv2.CacheRebuild{
+		Challenge: hex.EncodeToString(challenge),
+		PluginIDs: pluginIDs,
+	}
+
+	// Send request
+	resBody, err := c.makeReq(ctx, http.MethodPost,
+		pdv2.APIRoute, pdv2.RouteCacheRebuild, cr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var crr pdv2.CacheRebuildReply
+	err = json.Unmarshal(resBody, &crr)
+	if err != nil {
+		return nil, err
+	}
+	err = util.VerifyChallenge(c.pid, challenge, crr.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &crr.Rebuild, nil
+}
+
+// CacheRebuildStatus sends a CacheRebuildStatus command to the politeiad v2
+// API.
+func (c *Client) CacheRebuildStatus(ctx context.Context) (*pdv2.CacheRebuildState, error) {
+	// Setup request
+	challenge, err := util.Random(pdv2.ChallengeSize)
+	if err != nil {
+		return nil, err
+	}
+	cs := pdv2.CacheRebuildStatus{
+		Challenge: hex.EncodeToString(challenge),
+	}
+
+	// Send request
+	resBody, err := c.makeReq(ctx, http.MethodPost,
+		pdv2.APIRoute, pdv2.RouteCacheRebuildStatus, cs)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var csr pdv2.CacheRebuildStatusReply
+	err = json.Unmarshal(resBody, &csr)
+	if err != nil {
+		return nil, err
+	}
+	err = util.VerifyChallenge(c.pid, challenge, csr.Response)
+	if err != nil {
+		return nil, err
+	}
+
+	return &csr.Rebuild, nil
+}
+
 // RecordVerify verifies the censorship record of a v2 Record.
 func RecordVerify(r pdv2.Record, serverPubKey string) error {
 	// Verify censorship record merkle root
@@ -0,0 +1,53 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	pdv2 "github.com/decred/politeia/politeiad/api/v2"
+	"github.com/decred/politeia/politeiad/plugins/search"
+)
+
+// Search sends the search plugin Search command to the politeiad v2 API.
+func (c *Client) Search(ctx context.Context, s search.Search) (*search.SearchReply, error) {
+	// Setup request
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	cmds := []pdv2.PluginCmd{
+		{
+			ID:      search.PluginID,
+			Command: search.CmdSearch,
+			Payload: string(b),
+		},
+	}
+
+	// Send request
+	replies, err := c.PluginReads(ctx, cmds)
+	if err != nil {
+		return nil, err
+	}
+	if len(replies) == 0 {
+		return nil, fmt.Errorf("no replies found")
+	}
+	pcr := replies[0]
+	err = extractPluginCmdError(pcr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var sr search.SearchReply
+	err = json.Unmarshal([]byte(pcr.Payload), &sr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sr, nil
+}
@@ -106,6 +106,68 @@ func (c *Client) TicketVoteCastBallot(ctx context.Context, token string, cb tick
 	return &cbr, nil
 }
 
+// TicketVoteReveal sends the ticketvote plugin Reveal command to the
+// politeiad v2 API.
+func (c *Client) TicketVoteReveal(ctx context.Context, token string, r ticketvote.Reveal) (*ticketvote.RevealReply, error) {
+	// Setup request
+	b, err := json.Marshal(r)
+	if err != nil {
+		return nil, err
+	}
+	cmd := pdv2.PluginCmd{
+		Token:   token,
+		ID:      ticketvote.PluginID,
+		Command: ticketvote.CmdReveal,
+		Payload: string(b),
+	}
+
+	// Send request
+	reply, err := c.PluginWrite(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var rr ticketvote.RevealReply
+	err = json.Unmarshal([]byte(reply), &rr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rr, nil
+}
+
+// TicketVoteCancel sends the ticketvote plugin Cancel command to the
+// politeiad v2 API.
+func (c *Client) TicketVoteCancel(ctx context.Context, cl ticketvote.Cancel) (*ticketvote.CancelReply, error) {
+	// Setup request
+	b, err := json.Marshal(cl)
+	if err != nil {
+		return nil, err
+	}
+	cmd := pdv2.PluginCmd{
+		Token:   cl.Token,
+		ID:      ticketvote.PluginID,
+		Command: ticketvote.CmdCancel,
+		Payload: string(b),
+	}
+
+	// Send request
+	reply, err := c.PluginWrite(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode reply
+	var cr ticketvote.CancelReply
+	err = json.Unmarshal([]byte(reply), &cr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cr, nil
+}
+
 // TicketVoteDetails sends the ticketvote plugin Details command to the
 // politeiad v2 API.
 func (c *Client) TicketVoteDetails(ctx context.Context, token string) (*ticketvote.DetailsReply, error) {
@@ -0,0 +1,366 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/decred/politeia/decredplugin"
+	pd "github.com/decred/politeia/politeiad/api/v1"
+	"github.com/decred/politeia/politeiad/backend"
+	"github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+	"github.com/decred/politeia/politeiad/plugins/comments"
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+	"github.com/decred/politeia/politeiad/plugins/usermd"
+	"github.com/decred/politeia/util"
+)
+
+const (
+	// legacyPluginID and legacyStreamID identify the metadata stream
+	// that is saved to every version of a migrated record. It contains
+	// the legacy record metadata and the raw legacy metadata streams,
+	// which includes the legacy token that the original signatures
+	// were created against.
+	legacyPluginID        = "gitbe"
+	legacyStreamID uint32 = 1
+
+	// The following data descriptors must match the data descriptors
+	// that are used by the tstorebe comments and ticketvote plugins.
+	dataDescriptorCommentAdd      = comments.PluginID + "-add-v1"
+	dataDescriptorCommentDel      = comments.PluginID + "-del-v1"
+	dataDescriptorCommentVote     = comments.PluginID + "-vote-v1"
+	dataDescriptorAuthDetails     = ticketvote.PluginID + "-auth-v1"
+	dataDescriptorVoteDetails     = ticketvote.PluginID + "-vote-v1"
+	dataDescriptorCastVoteDetails = ticketvote.PluginID + "-castvote-v1"
+)
+
+// legacyMetadata is the payload of the legacy metadata stream.
+type legacyMetadata struct {
+	Token          string                 `json:"token"`
+	RecordMetadata backend.RecordMetadata `json:"recordmetadata"`
+	Metadata       map[uint64]string      `json:"metadata"` // [streamID]payload
+}
+
+// convertStatus converts a legacy record status to a tstore record status.
+func convertStatus(s backend.MDStatusT) (backendv2.StatusT, error) {
+	switch s {
+	case backend.MDStatusUnvetted, backend.MDStatusIterationUnvetted:
+		return backendv2.StatusUnreviewed, nil
+	case backend.MDStatusVetted:
+		return backendv2.StatusPublic, nil
+	case backend.MDStatusCensored:
+		return backendv2.StatusCensored, nil
+	case backend.MDStatusArchived:
+		return backendv2.StatusArchived, nil
+	}
+	return backendv2.StatusInvalid, fmt.Errorf("invalid status %v", s)
+}
+
+// convertStatusFromPD converts a legacy politeiad record status, which is
+// used in the legacy status change metadata stream, to a tstore record
+// status.
+func convertStatusFromPD(s pd.RecordStatusT) backendv2.StatusT {
+	switch s {
+	case pd.RecordStatusNotReviewed, pd.RecordStatusUnreviewedChanges:
+		return backendv2.StatusUnreviewed
+	case pd.RecordStatusPublic:
+		return backendv2.StatusPublic
+	case pd.RecordStatusCensored:
+		return backendv2.StatusCensored
+	case pd.RecordStatusArchived:
+		return backendv2.StatusArchived
+	}
+	return backendv2.StatusInvalid
+}
+
+// convertFiles converts legacy files to tstore files.
+func convertFiles(files []backend.File) []backendv2.File {
+	f := make([]backendv2.File, 0, len(files))
+	for _, v := range files {
+		f = append(f, backendv2.File{
+			Name:    v.Name,
+			MIME:    v.MIME,
+			Digest:  v.Digest,
+			Payload: v.Payload,
+		})
+	}
+	return f
+}
+
+// convertRecordMetadata returns the tstore record metadata for a legacy
+// record version. The legacy timestamp is preserved.
+func convertRecordMetadata(token string, rv recordVersion, state backendv2.StateT, status backendv2.StatusT, version, iteration uint32) (*backendv2.RecordMetadata, error) {
+	digests := make([]string, 0, len(rv.Files))
+	for _, v := range rv.Files {
+		digests = append(digests, v.Digest)
+	}
+	m, err := util.MerkleRoot(digests)
+	if err != nil {
+		return nil, err
+	}
+	return &backendv2.RecordMetadata{
+		Token:     token,
+		Version:   version,
+		Iteration: iteration,
+		State:     state,
+		Status:    status,
+		Timestamp: rv.RecordMetadata.Timestamp,
+		Merkle:    hex.EncodeToString(m[:]),
+	}, nil
+}
+
+// convertUserMetadata converts a legacy proposal general metadata stream to a
+// usermd user metadata stream.
+func convertUserMetadata(pg proposalGeneral, userID string) (*backendv2.MetadataStream, error) {
+	b, err := json.Marshal(usermd.UserMetadata{
+		UserID:    userID,
+		PublicKey: pg.PublicKey,
+		Signature: pg.Signature,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &backendv2.MetadataStream{
+		PluginID: usermd.PluginID,
+		StreamID: usermd.StreamIDUserMetadata,
+		Payload:  string(b),
+	}, nil
+}
+
+// convertStatusChanges converts legacy status changes to a usermd status
+// changes metadata stream. The original signatures and timestamps are
+// preserved.
+func convertStatusChanges(token string, version uint32, sc []statusChange) (*backendv2.MetadataStream, error) {
+	var payload []byte
+	for _, v := range sc {
+		b, err := json.Marshal(usermd.StatusChangeMetadata{
+			Token:     token,
+			Version:   version,
+			Status:    uint32(convertStatusFromPD(v.NewStatus)),
+			Reason:    v.StatusChangeMessage,
+			PublicKey: v.AdminPubKey,
+			Signature: v.Signature,
+			Timestamp: v.Timestamp,
+		})
+		if err != nil {
+			return nil, err
+		}
+		payload = append(payload, b...)
+	}
+	return &backendv2.MetadataStream{
+		PluginID: usermd.PluginID,
+		StreamID: usermd.StreamIDStatusChanges,
+		Payload:  string(payload),
+	}, nil
+}
+
+// convertLegacyMetadata returns the legacy metadata stream for a legacy record
+// version.
+func convertLegacyMetadata(legacyToken string, rv recordVersion) (*backendv2.MetadataStream, error) {
+	md := make(map[uint64]string, len(rv.Metadata))
+	for _, v := range rv.Metadata {
+		md[v.ID] = v.Payload
+	}
+	b, err := json.Marshal(legacyMetadata{
+		Token:          legacyToken,
+		RecordMetadata: rv.RecordMetadata,
+		Metadata:       md,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &backendv2.MetadataStream{
+		PluginID: legacyPluginID,
+		StreamID: legacyStreamID,
+		Payload:  string(b),
+	}, nil
+}
+
+// convertCommentAdd converts a legacy comment to a comments plugin
+// CommentAdd. The original signature, receipt, and timestamp are preserved.
+// Legacy comments could not be edited so the comment version is always 1.
+func convertCommentAdd(token string, state comments.RecordStateT, c decredplugin.Comment, userID string) (*comments.CommentAdd, error) {
+	commentID, err := parseUint32(c.CommentID)
+	if err != nil {
+		return nil, fmt.Errorf("comment id %v: %v", c.CommentID, err)
+	}
+	parentID, err := parseUint32(c.ParentID)
+	if err != nil {
+		return nil, fmt.Errorf("parent id %v: %v", c.ParentID, err)
+	}
+	return &comments.CommentAdd{
+		UserID:    userID,
+		State:     state,
+		Token:     token,
+		ParentID:  parentID,
+		Comment:   c.Comment,
+		PublicKey: c.PublicKey,
+		Signature: c.Signature,
+		CommentID: commentID,
+		Version:   1,
+		Timestamp: c.Timestamp,
+		Receipt:   c.Receipt,
+	}, nil
+}
+
+// convertCommentDel converts a legacy censor comment journal entry to a
+// comments plugin CommentDel. The parent ID and user ID are taken from the
+// comment that was censored.
+func convertCommentDel(token string, state comments.RecordStateT, cc decredplugin.CensorComment, ca comments.CommentAdd) comments.CommentDel {
+	return comments.CommentDel{
+		Token:     token,
+		State:     state,
+		CommentID: ca.CommentID,
+		Reason:    cc.Reason,
+		PublicKey: cc.PublicKey,
+		Signature: cc.Signature,
+		ParentID:  ca.ParentID,
+		UserID:    ca.UserID,
+		Timestamp: cc.Timestamp,
+		Receipt:   cc.Receipt,
+	}
+}
+
+// convertCommentVote converts a legacy like comment journal entry to a
+// comments plugin CommentVote.
+func convertCommentVote(token string, state comments.RecordStateT, lc likeComment, userID string) (*comments.CommentVote, error) {
+	commentID, err := parseUint32(lc.CommentID)
+	if err != nil {
+		return nil, fmt.Errorf("comment id %v: %v", lc.CommentID, err)
+	}
+	var vote comments.VoteT
+	switch lc.Action {
+	case "1":
+		vote = comments.VoteUpvote
+	case "-1":
+		vote = comments.VoteDownvote
+	default:
+		return nil, fmt.Errorf("invalid like action %v", lc.Action)
+	}
+	return &comments.CommentVote{
+		UserID:    userID,
+		State:     state,
+		Token:     token,
+		CommentID: commentID,
+		Vote:      vote,
+		PublicKey: lc.PublicKey,
+		Signature: lc.Signature,
+		Timestamp: lc.Timestamp,
+		Receipt:   lc.Receipt,
+	}, nil
+}
+
+// convertAuthDetails converts a legacy authorize vote metadata stream to a
+// ticketvote AuthDetails.
+func convertAuthDetails(token string, version uint32, av authorizeVote) ticketvote.AuthDetails {
+	return ticketvote.AuthDetails{
+		Token:     token,
+		Version:   version,
+		Action:    av.Action,
+		PublicKey: av.PublicKey,
+		Signature: av.Signature,
+		Timestamp: av.Timestamp,
+		Receipt:   av.Receipt,
+	}
+}
+
+// convertVoteDetails converts the legacy start vote and start vote reply
+// metadata streams to a ticketvote VoteDetails. Version 1 start votes did not
+// include the record version or the vote type. The provided record version is
+// used for these and the vote type is set to standard.
+func convertVoteDetails(token string, version uint32, sv startVote, svr startVoteReply) (*ticketvote.VoteDetails, error) {
+	var (
+		vt = ticketvote.VoteTypeStandard
+		rv = version
+	)
+	if sv.Version >= 2 {
+		switch sv.Vote.Type {
+		case voteTypeStandard:
+			vt = ticketvote.VoteTypeStandard
+		case voteTypeRunoff:
+			vt = ticketvote.VoteTypeRunoff
+		default:
+			return nil, fmt.Errorf("invalid vote type %v", sv.Vote.Type)
+		}
+		rv = sv.Vote.ProposalVersion
+	}
+	options := make([]ticketvote.VoteOption, 0, len(sv.Vote.Options))
+	for _, v := range sv.Vote.Options {
+		options = append(options, ticketvote.VoteOption{
+			ID:          v.ID,
+			Description: v.Description,
+			Bit:         v.Bits,
+		})
+	}
+	startHeight, err := parseUint32(svr.StartBlockHeight)
+	if err != nil {
+		return nil, fmt.Errorf("start block height %v: %v",
+			svr.StartBlockHeight, err)
+	}
+	endHeight, err := parseUint32(svr.EndHeight)
+	if err != nil {
+		return nil, fmt.Errorf("end height %v: %v", svr.EndHeight, err)
+	}
+	return &ticketvote.VoteDetails{
+		Params: ticketvote.VoteParams{
+			Token:            token,
+			Version:          rv,
+			Type:             vt,
+			Mask:             sv.Vote.Mask,
+			Duration:         sv.Vote.Duration,
+			QuorumPercentage: sv.Vote.QuorumPercentage,
+			PassPercentage:   sv.Vote.PassPercentage,
+			Options:          options,
+		},
+		PublicKey:        sv.PublicKey,
+		Signature:        sv.Signature,
+		StartBlockHeight: startHeight,
+		StartBlockHash:   svr.StartBlockHash,
+		EndBlockHeight:   endHeight,
+		EligibleTickets:  svr.EligibleTickets,
+	}, nil
+}
+
+// convertCastVoteDetails converts a legacy ballot journal entry to a
+// ticketvote CastVoteDetails. The legacy ballot journal did not record the
+// ticket commitment address or a timestamp, so these fields are left empty.
+func convertCastVoteDetails(token string, cvj castVoteJournal) ticketvote.CastVoteDetails {
+	return ticketvote.CastVoteDetails{
+		Token:     token,
+		Ticket:    cvj.CastVote.Ticket,
+		VoteBit:   cvj.CastVote.VoteBit,
+		Signature: cvj.CastVote.Signature,
+		Receipt:   cvj.Receipt,
+	}
+}
+
+// convertBlobEntry returns a JSON encoded blob entry for the provided plugin
+// data structure.
+func convertBlobEntry(dataDescriptor string, v interface{}) (*store.BlobEntry, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	hint, err := json.Marshal(
+		store.DataDescriptor{
+			Type:       store.DataTypeStructure,
+			Descriptor: dataDescriptor,
+		})
+	if err != nil {
+		return nil, err
+	}
+	be := store.NewBlobEntry(hint, data)
+	return &be, nil
+}
+
+// versionString returns the legacy string representation of a record
+// version.
+func versionString(version uint32) string {
+	return strconv.FormatUint(uint64(version), 10)
+}
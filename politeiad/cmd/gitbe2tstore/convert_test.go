@@ -0,0 +1,357 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+	"github.com/decred/politeia/politeiad/plugins/comments"
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
+	"github.com/decred/politeia/politeiad/plugins/usermd"
+	"github.com/decred/politeia/util"
+)
+
+// The following fixtures use the legacy gitbe data formats. The keys,
+// signatures, and receipts are placeholders since the conversions do not
+// verify them.
+const (
+	fixtureToken = "27f87171d98b7923a1bd2bee6affed929fa2d2a6e178b5c80a9971a92a5c7f50"
+
+	// fixtureStatusChanges is the legacy status changes metadata
+	// stream. It contains a version 2 status change followed by a
+	// version 1 status change with an older timestamp. Version 1 status
+	// changes did not include a signature.
+	fixtureStatusChanges = `{"version":2,"newstatus":6,"statuschangemessage":"abandoned","signature":"e2ab51","adminpubkey":"5a3bf0","timestamp":1597186054}
+{"version":1,"adminpubkey":"5a3bf0","newstatus":4,"timestamp":1596064451}
+`
+
+	// fixtureCommentsJournal is the legacy comments journal. Comment 2
+	// is a reply to comment 1 and is censored.
+	fixtureCommentsJournal = `{"version":"1","action":"add"}{"token":"27f87171d98b7923a1bd2bee6affed929fa2d2a6e178b5c80a9971a92a5c7f50","parentid":"0","comment":"first","signature":"c0ffee01","publickey":"aa11","commentid":"1","receipt":"beef01","timestamp":1596100000,"totalvotes":0,"resultvotes":0,"censored":false}
+{"version":"1","action":"add"}{"token":"27f87171d98b7923a1bd2bee6affed929fa2d2a6e178b5c80a9971a92a5c7f50","parentid":"1","comment":"reply","signature":"c0ffee02","publickey":"bb22","commentid":"2","receipt":"beef02","timestamp":1596100100,"totalvotes":0,"resultvotes":0,"censored":false}
+
+{"version":"1","action":"addlike"}{"token":"27f87171d98b7923a1bd2bee6affed929fa2d2a6e178b5c80a9971a92a5c7f50","commentid":"1","action":"1","signature":"c0ffee03","publickey":"bb22","receipt":"beef03","timestamp":1596100200}
+{"version":"1","action":"addlike"}{"token":"27f87171d98b7923a1bd2bee6affed929fa2d2a6e178b5c80a9971a92a5c7f50","commentid":"1","action":"-1","signature":"c0ffee04","publickey":"cc33","receipt":"beef04","timestamp":1596100300}
+{"version":"1","action":"del"}{"token":"27f87171d98b7923a1bd2bee6affed929fa2d2a6e178b5c80a9971a92a5c7f50","commentid":"2","reason":"spam","signature":"c0ffee05","publickey":"5a3bf0","receipt":"beef05","timestamp":1596100400}
+`
+
+	// fixtureStartVote and fixtureStartVoteReply are the version 2
+	// legacy start vote metadata streams.
+	fixtureStartVote = `{"version":2,"publickey":"5a3bf0","vote":{"token":"27f87171d98b7923a1bd2bee6affed929fa2d2a6e178b5c80a9971a92a5c7f50","proposalversion":3,"type":1,"mask":3,"duration":2016,"quorumpercentage":20,"passpercentage":60,"options":[{"id":"no","description":"Don't approve proposal","bits":1},{"id":"yes","description":"Approve proposal","bits":2}]},"signature":"d00d01"}`
+
+	fixtureStartVoteReply = `{"version":2,"startblockheight":"501234","startblockhash":"0000000001a2b3c4","endheight":"503250","eligibletickets":["t1","t2","t3"]}`
+
+	// fixtureStartVoteV1 is a version 1 legacy start vote. Version 1
+	// did not include the proposal version or the vote type.
+	fixtureStartVoteV1 = `{"version":1,"publickey":"5a3bf0","vote":{"token":"27f87171d98b7923a1bd2bee6affed929fa2d2a6e178b5c80a9971a92a5c7f50","mask":3,"duration":2016,"quorumpercentage":20,"passpercentage":60,"options":[{"id":"no","description":"Don't approve proposal","bits":1},{"id":"yes","description":"Approve proposal","bits":2}]},"signature":"d00d02"}`
+
+	// fixtureBallotJournal is the legacy ballot journal.
+	fixtureBallotJournal = `{"version":"1","action":"add"}{"castvote":{"token":"27f87171d98b7923a1bd2bee6affed929fa2d2a6e178b5c80a9971a92a5c7f50","ticket":"t1","votebit":"2","signature":"5160"},"receipt":"7ec1"}
+{"version":"1","action":"add"}{"castvote":{"token":"27f87171d98b7923a1bd2bee6affed929fa2d2a6e178b5c80a9971a92a5c7f50","ticket":"t2","votebit":"1","signature":"5161"},"receipt":"7ec2"}
+`
+)
+
+func TestConvertStatusChanges(t *testing.T) {
+	sc, err := decodeStatusChanges(fixtureStatusChanges)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ms, err := convertStatusChanges(fixtureToken, 3, sc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ms.PluginID != usermd.PluginID ||
+		ms.StreamID != usermd.StreamIDStatusChanges {
+		t.Fatalf("got stream %v %v, want %v %v", ms.PluginID, ms.StreamID,
+			usermd.PluginID, usermd.StreamIDStatusChanges)
+	}
+
+	// Decode the converted status changes. They must be sorted from
+	// oldest to newest and must preserve the legacy signatures and
+	// timestamps.
+	got := make([]usermd.StatusChangeMetadata, 0, 2)
+	d := json.NewDecoder(strings.NewReader(ms.Payload))
+	for {
+		var scm usermd.StatusChangeMetadata
+		err := d.Decode(&scm)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, scm)
+	}
+	want := []usermd.StatusChangeMetadata{
+		{
+			Token:     fixtureToken,
+			Version:   3,
+			Status:    uint32(backendv2.StatusPublic),
+			PublicKey: "5a3bf0",
+			Timestamp: 1596064451,
+		},
+		{
+			Token:     fixtureToken,
+			Version:   3,
+			Status:    uint32(backendv2.StatusArchived),
+			Reason:    "abandoned",
+			PublicKey: "5a3bf0",
+			Signature: "e2ab51",
+			Timestamp: 1597186054,
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got status changes %+v, want %+v", got, want)
+	}
+}
+
+func TestConvertComments(t *testing.T) {
+	cj, err := decodeCommentsJournal([]byte(fixtureCommentsJournal))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cj.Adds) != 2 || len(cj.Dels) != 1 || len(cj.Likes) != 2 {
+		t.Fatalf("got %v adds, %v dels, %v likes, want 2, 1, 2",
+			len(cj.Adds), len(cj.Dels), len(cj.Likes))
+	}
+	state := comments.RecordStateVetted
+
+	// Comment adds
+	adds := make(map[uint32]comments.CommentAdd, len(cj.Adds))
+	for _, v := range cj.Adds {
+		ca, err := convertCommentAdd(fixtureToken, state, v, "user-"+v.PublicKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		adds[ca.CommentID] = *ca
+	}
+	wantAdd := comments.CommentAdd{
+		UserID:    "user-bb22",
+		State:     state,
+		Token:     fixtureToken,
+		ParentID:  1,
+		Comment:   "reply",
+		PublicKey: "bb22",
+		Signature: "c0ffee02",
+		CommentID: 2,
+		Version:   1,
+		Timestamp: 1596100100,
+		Receipt:   "beef02",
+	}
+	if !reflect.DeepEqual(adds[2], wantAdd) {
+		t.Fatalf("got comment add %+v, want %+v", adds[2], wantAdd)
+	}
+	if adds[1].ParentID != 0 {
+		t.Fatalf("got parent id %v, want 0", adds[1].ParentID)
+	}
+
+	// Comment del. The parent ID and user ID are taken from the
+	// comment that was censored.
+	cd := convertCommentDel(fixtureToken, state, cj.Dels[0], adds[2])
+	wantDel := comments.CommentDel{
+		Token:     fixtureToken,
+		State:     state,
+		CommentID: 2,
+		Reason:    "spam",
+		PublicKey: "5a3bf0",
+		Signature: "c0ffee05",
+		ParentID:  1,
+		UserID:    "user-bb22",
+		Timestamp: 1596100400,
+		Receipt:   "beef05",
+	}
+	if !reflect.DeepEqual(cd, wantDel) {
+		t.Fatalf("got comment del %+v, want %+v", cd, wantDel)
+	}
+
+	// Comment votes
+	wantVotes := []comments.VoteT{comments.VoteUpvote, comments.VoteDownvote}
+	for i, v := range cj.Likes {
+		cv, err := convertCommentVote(fixtureToken, state, v, "user-"+v.PublicKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cv.CommentID != 1 || cv.Vote != wantVotes[i] ||
+			cv.Signature != v.Signature || cv.Receipt != v.Receipt ||
+			cv.Timestamp != v.Timestamp || cv.UserID != "user-"+v.PublicKey {
+			t.Fatalf("like %v: got comment vote %+v", i, cv)
+		}
+	}
+
+	// Invalid like actions must be rejected
+	lc := cj.Likes[0]
+	lc.Action = "0"
+	_, err = convertCommentVote(fixtureToken, state, lc, "")
+	if err == nil {
+		t.Fatalf("invalid like action was accepted")
+	}
+
+	// The converted blobs must use the comments plugin data
+	// descriptors.
+	be, err := convertBlobEntry(dataDescriptorCommentAdd, adds[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ca comments.CommentAdd
+	err = json.Unmarshal(blobEntryData(t, *be), &ca)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ca, adds[1]) {
+		t.Fatalf("got blob comment add %+v, want %+v", ca, adds[1])
+	}
+}
+
+func TestConvertVoteDetails(t *testing.T) {
+	var svr startVoteReply
+	err := json.Unmarshal([]byte(fixtureStartVoteReply), &svr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	options := []ticketvote.VoteOption{
+		{ID: "no", Description: "Don't approve proposal", Bit: 1},
+		{ID: "yes", Description: "Approve proposal", Bit: 2},
+	}
+
+	var tests = []struct {
+		name      string
+		startVote string
+		version   uint32 // Record version provided to the conversion
+		want      ticketvote.VoteParams
+		signature string
+	}{
+		{
+			"version 2",
+			fixtureStartVote,
+			1,
+			ticketvote.VoteParams{
+				Token:            fixtureToken,
+				Version:          3,
+				Type:             ticketvote.VoteTypeStandard,
+				Mask:             3,
+				Duration:         2016,
+				QuorumPercentage: 20,
+				PassPercentage:   60,
+				Options:          options,
+			},
+			"d00d01",
+		},
+		{
+			"version 1 uses the record version",
+			fixtureStartVoteV1,
+			2,
+			ticketvote.VoteParams{
+				Token:            fixtureToken,
+				Version:          2,
+				Type:             ticketvote.VoteTypeStandard,
+				Mask:             3,
+				Duration:         2016,
+				QuorumPercentage: 20,
+				PassPercentage:   60,
+				Options:          options,
+			},
+			"d00d02",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var sv startVote
+			err := json.Unmarshal([]byte(test.startVote), &sv)
+			if err != nil {
+				t.Fatal(err)
+			}
+			vd, err := convertVoteDetails(fixtureToken, test.version, sv, svr)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(vd.Params, test.want) {
+				t.Fatalf("got params %+v, want %+v", vd.Params, test.want)
+			}
+			if vd.Signature != test.signature || vd.PublicKey != "5a3bf0" ||
+				vd.StartBlockHeight != 501234 || vd.EndBlockHeight != 503250 ||
+				vd.StartBlockHash != "0000000001a2b3c4" ||
+				!reflect.DeepEqual(vd.EligibleTickets, svr.EligibleTickets) {
+				t.Fatalf("unexpected vote details %+v", vd)
+			}
+		})
+	}
+
+	// Invalid vote types and block heights must be rejected
+	var sv startVote
+	err = json.Unmarshal([]byte(fixtureStartVote), &sv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sv.Vote.Type = 9
+	_, err = convertVoteDetails(fixtureToken, 1, sv, svr)
+	if err == nil {
+		t.Fatalf("invalid vote type was accepted")
+	}
+	sv.Vote.Type = voteTypeRunoff
+	svrInvalid := svr
+	svrInvalid.EndHeight = "notanumber"
+	_, err = convertVoteDetails(fixtureToken, 1, sv, svrInvalid)
+	if err == nil {
+		t.Fatalf("invalid end height was accepted")
+	}
+}
+
+func TestConvertCastVotes(t *testing.T) {
+	votes, err := decodeBallotJournal([]byte(fixtureBallotJournal))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ticketvote.CastVoteDetails{
+		{
+			Token:     fixtureToken,
+			Ticket:    "t1",
+			VoteBit:   "2",
+			Signature: "5160",
+			Receipt:   "7ec1",
+		},
+		{
+			Token:     fixtureToken,
+			Ticket:    "t2",
+			VoteBit:   "1",
+			Signature: "5161",
+			Receipt:   "7ec2",
+		},
+	}
+	got := make([]ticketvote.CastVoteDetails, 0, len(votes))
+	for _, v := range votes {
+		got = append(got, convertCastVoteDetails(fixtureToken, v))
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got cast votes %+v, want %+v", got, want)
+	}
+
+	// A ballot journal may only contain add actions
+	_, err = decodeBallotJournal([]byte(`{"version":"1","action":"del"}{}`))
+	if err == nil {
+		t.Fatalf("invalid ballot journal action was accepted")
+	}
+}
+
+// blobEntryData decodes the data of a blob entry and verifies that it matches
+// the blob entry digest.
+func blobEntryData(t *testing.T, be store.BlobEntry) []byte {
+	t.Helper()
+
+	b, err := base64.StdEncoding.DecodeString(be.Data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hex.EncodeToString(util.Digest(b)) != be.Digest {
+		t.Fatalf("blob entry digest mismatch")
+	}
+	return b
+}
@@ -0,0 +1,302 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	v1 "github.com/decred/dcrtime/api/v1"
+	"github.com/decred/politeia/politeiad/backend/gitbe"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/tstore"
+	"github.com/decred/politeia/politeiad/sharedconfig"
+	"github.com/decred/politeia/util"
+)
+
+const (
+	defaultDataDirname     = sharedconfig.DefaultDataDirname
+	defaultUnvettedDirname = gitbe.DefaultUnvettedPath
+	defaultVettedDirname   = gitbe.DefaultVettedPath
+	defaultJournalsDirname = gitbe.DefaultJournalsPath
+
+	defaultDBType     = tstore.DBTypeLevelDB
+	defaultTlogHost   = "localhost:8090"
+	defaultReportFile = "gitbe2tstore-report.json"
+
+	// Environment variables
+	envDBPass   = "DBPASS"
+	envTlogPass = "TLOGPASS"
+)
+
+var (
+	defaultHomeDir = sharedconfig.DefaultHomeDir
+
+	// CLI flags
+	homeDir     = flag.String("homedir", defaultHomeDir, "politeiad home dir path")
+	testnet     = flag.Bool("testnet", false, "migrate testnet data")
+	gitbeDir    = flag.String("gitbedir", "", "gitbe data dir path; defaults to the politeiad data dir")
+	unvetted    = flag.Bool("unvetted", false, "migrate unvetted records")
+	dbType      = flag.String("dbtype", defaultDBType, "tstore database type")
+	dbHost      = flag.String("dbhost", "", "tstore database host")
+	tlogType    = flag.String("tlogtype", tstore.TlogTypeTrillian, "tstore tlog type (trillian or native)")
+	tlogHost    = flag.String("tloghost", defaultTlogHost, "trillian host")
+	anchorType  = flag.String("anchor", tstore.AnchorTypeDcrtime, "tstore anchor type (dcrtime or local)")
+	dcrtimeHost = flag.String("dcrtimehost", "", "dcrtime host")
+	dcrtimeCert = flag.String("dcrtimecert", "", "dcrtime certificate file path")
+	userMap     = flag.String("usermap", "", "JSON file that maps public keys to user IDs")
+	reportFile  = flag.String("report", defaultReportFile, "verification report file path")
+)
+
+// loadUserMap loads the JSON encoded public key to user ID map.
+func loadUserMap(fp string) (map[string]string, error) {
+	userIDs := make(map[string]string)
+	if fp == "" {
+		return userIDs, nil
+	}
+	b, err := ioutil.ReadFile(util.CleanAndExpandPath(fp))
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(b, &userIDs)
+	if err != nil {
+		return nil, fmt.Errorf("decode user map: %v", err)
+	}
+	return userIDs, nil
+}
+
+func _main() error {
+	flag.Parse()
+
+	// Setup the network params and directories
+	anp := chaincfg.MainNetParams()
+	timeHost := v1.DefaultMainnetTimeHost
+	timePort := v1.DefaultMainnetTimePort
+	if *testnet {
+		anp = chaincfg.TestNet3Params()
+		timeHost = v1.DefaultTestnetTimeHost
+		timePort = v1.DefaultTestnetTimePort
+	}
+	home := util.CleanAndExpandPath(*homeDir)
+	dataDir := filepath.Join(home, defaultDataDirname, anp.Name)
+	gitbePath := dataDir
+	if *gitbeDir != "" {
+		gitbePath = util.CleanAndExpandPath(*gitbeDir)
+	}
+	if *dcrtimeHost != "" {
+		timeHost = *dcrtimeHost
+	}
+	timeHost = "https://" + util.NormalizeAddress(timeHost, timePort)
+
+	// Verify the database settings. The passwords are provided in env
+	// variables the same way they are provided to politeiad.
+	var dbPass string
+	switch *dbType {
+	case tstore.DBTypeLevelDB:
+		// Allowed; continue
+	case tstore.DBTypeMySQL, tstore.DBTypePostgres:
+		dbPass = os.Getenv(envDBPass)
+		if dbPass == "" {
+			return fmt.Errorf("the database password must be provided "+
+				"in the env variable %v", envDBPass)
+		}
+		if *dbHost == "" {
+			return fmt.Errorf("must provide a database host")
+		}
+	default:
+		return fmt.Errorf("invalid db type: %v", *dbType)
+	}
+	tlogPass := os.Getenv(envTlogPass)
+	if tlogPass == "" {
+		return fmt.Errorf("the tlog password must be provided in the env "+
+			"variable %v", envTlogPass)
+	}
+
+	// Setup the legacy repos
+	vetted, err := newGitRepo(filepath.Join(gitbePath, defaultVettedDirname))
+	if err != nil {
+		return err
+	}
+	var unvettedRepo *gitRepo
+	if *unvetted {
+		unvettedRepo, err = newGitRepo(filepath.Join(gitbePath,
+			defaultUnvettedDirname))
+		if err != nil {
+			return err
+		}
+	}
+	journalsDir := filepath.Join(gitbePath, defaultJournalsDirname)
+
+	userIDs, err := loadUserMap(*userMap)
+	if err != nil {
+		return err
+	}
+
+	// Load the report of any prior run. Records that have already
+	// been migrated are skipped. Records that failed are retried and
+	// their prior results are dropped from the report.
+	rp := util.CleanAndExpandPath(*reportFile)
+	rpt, err := loadReport(rp)
+	if err != nil {
+		return err
+	}
+	migrated := make(map[string]struct{}, len(rpt.Records))
+	records := make([]recordReport, 0, len(rpt.Records))
+	for _, v := range rpt.Records {
+		if v.Error != "" {
+			continue
+		}
+		migrated[v.LegacyToken] = struct{}{}
+		records = append(records, v)
+	}
+	rpt.Records = records
+
+	// Setup tstore
+	ts, err := tstore.New(home, dataDir, anp, *tlogType, *tlogHost,
+		tlogPass, *dbType, *dbHost, dbPass, timeHost, *dcrtimeCert,
+		*anchorType, tstore.AnchorScheduleDefault, 0)
+	if err != nil {
+		return fmt.Errorf("new tstore: %v", err)
+	}
+	defer ts.Close()
+	err = ts.Setup()
+	if err != nil {
+		return fmt.Errorf("tstore setup: %v", err)
+	}
+
+	m := migrator{
+		tstore:   ts,
+		userIDs:  userIDs,
+		unmapped: make(map[string]struct{}),
+	}
+	for _, v := range rpt.UnmappedKeys {
+		m.unmapped[v] = struct{}{}
+	}
+
+	// Compile the list of records to migrate. Vetted records are all
+	// on the master branch of the vetted repo. Unvetted records each
+	// have their own branch in the unvetted repo. An unvetted branch
+	// that has been made public also exists in the vetted repo.
+	type legacyRecord struct {
+		repo   *gitRepo
+		branch string
+		token  string
+		vetted bool
+	}
+	vettedTokens, err := vetted.tokens(vettedBranch)
+	if err != nil {
+		return fmt.Errorf("vetted tokens: %v", err)
+	}
+	isVetted := make(map[string]struct{}, len(vettedTokens))
+	todo := make([]legacyRecord, 0, len(vettedTokens))
+	for _, v := range vettedTokens {
+		isVetted[v] = struct{}{}
+		todo = append(todo, legacyRecord{vetted, vettedBranch, v, true})
+	}
+	if unvettedRepo != nil {
+		branches, err := unvettedRepo.branches()
+		if err != nil {
+			return fmt.Errorf("unvetted branches: %v", err)
+		}
+		sort.Strings(branches)
+		for _, v := range branches {
+			if _, err := util.TokenDecode(util.TokenTypeGit, v); err != nil {
+				// Not a record branch
+				continue
+			}
+			if _, ok := isVetted[v]; ok {
+				continue
+			}
+			todo = append(todo, legacyRecord{unvettedRepo, v, v, false})
+		}
+	}
+
+	fmt.Printf("Legacy records: %v\n", len(todo))
+	fmt.Printf("Already migrated: %v\n", len(migrated))
+
+	// Migrate the records
+	for i, v := range todo {
+		if _, ok := migrated[v.token]; ok {
+			continue
+		}
+
+		fmt.Printf("%v/%v %v\n", i+1, len(todo), v.token)
+
+		rr := recordReport{
+			LegacyToken: v.token,
+		}
+		r, err := v.repo.record(journalsDir, v.branch, v.token, v.vetted)
+		if err != nil {
+			rr.Error = err.Error()
+		} else {
+			verifySignatures(r, &rr)
+			err = m.migrateRecord(r, &rr)
+			if err == nil {
+				err = m.verifyRecord(r, &rr)
+			}
+			if err != nil {
+				rr.Error = err.Error()
+			}
+		}
+		if rr.Error != "" {
+			fmt.Printf("  error: %v\n", rr.Error)
+		}
+		for _, v := range rr.Mismatches {
+			fmt.Printf("  mismatch: %v\n", v)
+		}
+
+		// Save the report after every record so that the migration
+		// can be resumed if it is interrupted.
+		rpt.Records = append(rpt.Records, rr)
+		err = rpt.save(rp)
+		if err != nil {
+			return fmt.Errorf("save report: %v", err)
+		}
+	}
+
+	// Save the final report
+	rpt.UnmappedKeys = make([]string, 0, len(m.unmapped))
+	for k := range m.unmapped {
+		rpt.UnmappedKeys = append(rpt.UnmappedKeys, k)
+	}
+	err = rpt.save(rp)
+	if err != nil {
+		return fmt.Errorf("save report: %v", err)
+	}
+
+	var ok, mismatches, errs, sigErrs int
+	for _, v := range rpt.Records {
+		switch {
+		case v.ok():
+			ok++
+		case v.Error != "":
+			errs++
+		default:
+			mismatches++
+		}
+		sigErrs += len(v.SignatureErrors)
+	}
+	fmt.Printf("Records ok        : %v\n", ok)
+	fmt.Printf("Records mismatched: %v\n", mismatches)
+	fmt.Printf("Records failed    : %v\n", errs)
+	fmt.Printf("Signature errors  : %v\n", sigErrs)
+	fmt.Printf("Unmapped keys     : %v\n", len(rpt.UnmappedKeys))
+	fmt.Printf("Report            : %v\n", rp)
+
+	return nil
+}
+
+func main() {
+	err := _main()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(1)
+	}
+}
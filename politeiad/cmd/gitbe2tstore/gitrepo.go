@@ -0,0 +1,336 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/decred/politeia/politeiad/api/v1/mime"
+	"github.com/decred/politeia/politeiad/backend"
+	"github.com/decred/politeia/util"
+)
+
+const (
+	// The following are the gitbe filenames and directory names that
+	// are used by the legacy record layout.
+	//
+	// [repo]/[token]/[version]/recordmetadata.json
+	// [repo]/[token]/[version]/[mdstreamID].metadata.txt
+	// [repo]/[token]/[version]/payload/[filename]
+	// [repo]/[token]/[version]/plugins/decred/comments.journal
+	// [repo]/[token]/[version]/plugins/decred/ballot.journal
+	// [journals]/[token]/comments.journal
+	// [journals]/[token]/ballot.journal
+	fnRecordMetadata = "recordmetadata.json"
+	fnMDStreamSuffix = ".metadata.txt"
+	fnComments       = "comments.journal"
+	fnBallot         = "ballot.journal"
+	dirPayload       = "payload"
+	dirPluginData    = "plugins/decred"
+
+	// vettedBranch is the branch of the vetted repo that contains all
+	// vetted records. Unvetted records are each saved to their own
+	// branch in the unvetted repo. The branch name is the record token.
+	vettedBranch = "master"
+)
+
+// gitRepo provides read only access to a gitbe git repository. The content is
+// read directly from the git object database so that records that only exist
+// on a branch can be read without modifying the working tree.
+type gitRepo struct {
+	path string
+}
+
+// newGitRepo returns a new gitRepo.
+func newGitRepo(path string) (*gitRepo, error) {
+	_, err := os.Stat(filepath.Join(path, ".git"))
+	if err != nil {
+		return nil, fmt.Errorf("not a git repo %v: %v", path, err)
+	}
+	return &gitRepo{
+		path: path,
+	}, nil
+}
+
+// git executes a git command against the repo and returns the stdout.
+func (g *gitRepo) git(args ...string) ([]byte, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("git", append([]string{"-C", g.path}, args...)...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	if err != nil {
+		return nil, fmt.Errorf("git %v: %v: %s", strings.Join(args, " "),
+			err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// branches returns all branches of the repo.
+func (g *gitRepo) branches() ([]string, error) {
+	b, err := g.git("for-each-ref", "--format=%(refname:short)", "refs/heads")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(string(b)), nil
+}
+
+// exists returns whether the provided path exists on the provided branch.
+func (g *gitRepo) exists(branch, p string) bool {
+	_, err := g.git("cat-file", "-e", branch+":"+p)
+	return err == nil
+}
+
+// ls returns the names of the entries of the directory at the provided path
+// on the provided branch.
+func (g *gitRepo) ls(branch, dir string) ([]string, error) {
+	// The entries are NUL terminated so that filenames that contain
+	// whitespace or special characters are returned verbatim.
+	b, err := g.git("ls-tree", "-z", "--name-only", branch+":"+dir)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]string, 0, 16)
+	for _, v := range strings.Split(string(b), "\x00") {
+		if v != "" {
+			entries = append(entries, v)
+		}
+	}
+	return entries, nil
+}
+
+// read returns the contents of the file at the provided path on the provided
+// branch.
+func (g *gitRepo) read(branch, p string) ([]byte, error) {
+	return g.git("cat-file", "blob", branch+":"+p)
+}
+
+// versions returns the record versions that exist for the provided token
+// sorted from oldest to newest.
+func (g *gitRepo) versions(branch, token string) ([]string, error) {
+	dirs, err := g.ls(branch, token)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]int, 0, len(dirs))
+	for _, v := range dirs {
+		u, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid version dir %v/%v", token, v)
+		}
+		versions = append(versions, u)
+	}
+	sort.Ints(versions)
+	s := make([]string, 0, len(versions))
+	for _, v := range versions {
+		s = append(s, strconv.Itoa(v))
+	}
+	return s, nil
+}
+
+// recordVersion returns the content of a single version of a record.
+func (g *gitRepo) recordVersion(branch, token, version string) (*recordVersion, error) {
+	dir := path.Join(token, version)
+	v, err := parseUint32(version)
+	if err != nil {
+		return nil, fmt.Errorf("invalid version %v: %v", version, err)
+	}
+
+	// Record metadata
+	b, err := g.read(branch, path.Join(dir, fnRecordMetadata))
+	if err != nil {
+		return nil, err
+	}
+	var rm backend.RecordMetadata
+	err = json.Unmarshal(b, &rm)
+	if err != nil {
+		return nil, fmt.Errorf("decode record metadata: %v", err)
+	}
+
+	// Metadata streams
+	entries, err := g.ls(branch, dir)
+	if err != nil {
+		return nil, err
+	}
+	metadata := make([]backend.MetadataStream, 0, len(entries))
+	for _, v := range entries {
+		if !strings.HasSuffix(v, fnMDStreamSuffix) {
+			continue
+		}
+		id, err := strconv.ParseUint(strings.TrimSuffix(v, fnMDStreamSuffix),
+			10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid metadata stream %v: %v", v, err)
+		}
+		b, err := g.read(branch, path.Join(dir, v))
+		if err != nil {
+			return nil, err
+		}
+		metadata = append(metadata, backend.MetadataStream{
+			ID:      id,
+			Payload: string(b),
+		})
+	}
+	sort.Slice(metadata, func(i, j int) bool {
+		return metadata[i].ID < metadata[j].ID
+	})
+
+	// Files
+	payloadDir := path.Join(dir, dirPayload)
+	fns, err := g.ls(branch, payloadDir)
+	if err != nil {
+		return nil, err
+	}
+	files := make([]backend.File, 0, len(fns))
+	for _, fn := range fns {
+		b, err := g.read(branch, path.Join(payloadDir, fn))
+		if err != nil {
+			return nil, err
+		}
+		digest := sha256.Sum256(b)
+		files = append(files, backend.File{
+			Name:    fn,
+			MIME:    mime.DetectMimeType(b),
+			Digest:  hex.EncodeToString(digest[:]),
+			Payload: base64.StdEncoding.EncodeToString(b),
+		})
+	}
+
+	return &recordVersion{
+		Version:        v,
+		RecordMetadata: rm,
+		Metadata:       metadata,
+		Files:          files,
+	}, nil
+}
+
+// journal returns the contents of a record journal. The journals dir contains
+// the most recent copy of a journal. A journal is only copied into the repo
+// when it gets flushed, so the journals dir copy is used when it exists. A nil
+// slice is returned if the journal does not exist.
+func (g *gitRepo) journal(journalsDir, branch, token, version, fn string) ([]byte, error) {
+	if journalsDir != "" {
+		fp := filepath.Join(journalsDir, token, fn)
+		if util.FileExists(fp) {
+			return ioutil.ReadFile(fp)
+		}
+	}
+	p := path.Join(token, version, dirPluginData, fn)
+	if !g.exists(branch, p) {
+		return nil, nil
+	}
+	return g.read(branch, p)
+}
+
+// record returns the full legacy record for the provided token.
+func (g *gitRepo) record(journalsDir, branch, token string, vetted bool) (*record, error) {
+	versions, err := g.versions(branch, token)
+	if err != nil {
+		return nil, err
+	}
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("no record versions found")
+	}
+	r := record{
+		Token:    token,
+		Vetted:   vetted,
+		Versions: make([]recordVersion, 0, len(versions)),
+	}
+	for _, v := range versions {
+		rv, err := g.recordVersion(branch, token, v)
+		if err != nil {
+			return nil, fmt.Errorf("version %v: %v", v, err)
+		}
+		r.Versions = append(r.Versions, *rv)
+	}
+
+	// Parse the metadata streams of the latest version. The legacy
+	// metadata streams of the latest version always contain the full
+	// history. The proposal general metadata stream is version
+	// specific and is parsed separately for each version.
+	metadata := r.latest().Metadata
+	if s := mdStream(metadata, mdStreamStatusChanges); s != "" {
+		r.StatusChanges, err = decodeStatusChanges(s)
+		if err != nil {
+			return nil, fmt.Errorf("status changes: %v", err)
+		}
+	}
+	if s := mdStream(metadata, mdStreamAuthorizeVote); s != "" {
+		var av authorizeVote
+		err = json.Unmarshal([]byte(s), &av)
+		if err != nil {
+			return nil, fmt.Errorf("authorize vote: %v", err)
+		}
+		r.AuthorizeVote = &av
+	}
+	if s := mdStream(metadata, mdStreamStartVote); s != "" {
+		var sv startVote
+		err = json.Unmarshal([]byte(s), &sv)
+		if err != nil {
+			return nil, fmt.Errorf("start vote: %v", err)
+		}
+		r.StartVote = &sv
+	}
+	if s := mdStream(metadata, mdStreamStartVoteReply); s != "" {
+		var svr startVoteReply
+		err = json.Unmarshal([]byte(s), &svr)
+		if err != nil {
+			return nil, fmt.Errorf("start vote reply: %v", err)
+		}
+		r.StartVoteReply = &svr
+	}
+
+	// Parse the journals
+	latest := versions[len(versions)-1]
+	b, err := g.journal(journalsDir, branch, token, latest, fnComments)
+	if err != nil {
+		return nil, err
+	}
+	cj, err := decodeCommentsJournal(b)
+	if err != nil {
+		return nil, fmt.Errorf("comments journal: %v", err)
+	}
+	r.Comments = *cj
+	b, err = g.journal(journalsDir, branch, token, latest, fnBallot)
+	if err != nil {
+		return nil, err
+	}
+	r.CastVotes, err = decodeBallotJournal(b)
+	if err != nil {
+		return nil, fmt.Errorf("ballot journal: %v", err)
+	}
+
+	return &r, nil
+}
+
+// tokens returns the tokens of all records that exist on the provided branch.
+func (g *gitRepo) tokens(branch string) ([]string, error) {
+	entries, err := g.ls(branch, "")
+	if err != nil {
+		return nil, err
+	}
+	tokens := make([]string, 0, len(entries))
+	for _, v := range entries {
+		if _, err := util.TokenDecode(util.TokenTypeGit, v); err != nil {
+			// Not a record dir
+			continue
+		}
+		tokens = append(tokens, v)
+	}
+	return tokens, nil
+}
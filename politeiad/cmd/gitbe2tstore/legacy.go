@@ -0,0 +1,360 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/decred/politeia/decredplugin"
+	"github.com/decred/politeia/mdstream"
+	pd "github.com/decred/politeia/politeiad/api/v1"
+	"github.com/decred/politeia/politeiad/backend"
+)
+
+// The types in this file are the legacy data types that were used by the
+// gitbe backend and the decred plugin. Some of them no longer exist anywhere
+// else in the politeia codebase, so they are defined here in order to be able
+// to decode the legacy data.
+
+const (
+	// Legacy metadata stream IDs. All of these metadata streams are
+	// JSON encoded.
+	mdStreamProposalGeneral = 0
+	mdStreamStatusChanges   = 2
+	mdStreamAuthorizeVote   = 13
+	mdStreamStartVote       = 14
+	mdStreamStartVoteReply  = 15
+
+	// Legacy journal actions
+	journalActionAdd     = "add"
+	journalActionDel     = "del"
+	journalActionAddLike = "addlike"
+
+	// Legacy vote types
+	voteTypeStandard = 1
+	voteTypeRunoff   = 2
+)
+
+// journalAction prefixes every entry of a legacy journal and determines the
+// structure of the entry that follows it.
+type journalAction struct {
+	Version string `json:"version"`
+	Action  string `json:"action"`
+}
+
+// proposalGeneral is the legacy metadata stream that contains the proposal
+// author's identity and signature. Version 1 includes the proposal name.
+// Version 2 moved the proposal name into a proposal metadata file.
+//
+// Signature is the author signature of the record merkle root.
+type proposalGeneral struct {
+	Version   uint64 `json:"version"`
+	Timestamp int64  `json:"timestamp"`
+	Name      string `json:"name,omitempty"`
+	PublicKey string `json:"publickey"`
+	Signature string `json:"signature"`
+}
+
+// likeComment is the legacy decred plugin journal entry for a comment upvote
+// or downvote. Action is either "1" or "-1".
+//
+// Signature is the client signature of the Token+CommentID+Action.
+type likeComment struct {
+	Token     string `json:"token"`
+	CommentID string `json:"commentid"`
+	Action    string `json:"action"`
+	Signature string `json:"signature"`
+	PublicKey string `json:"publickey"`
+	Receipt   string `json:"receipt,omitempty"`
+	Timestamp int64  `json:"timestamp,omitempty"`
+}
+
+// authorizeVote is the legacy metadata stream that contains the most recent
+// vote authorization action. Action is either "authorize" or "revoke".
+//
+// Signature is the client signature of the Token+Version+Action.
+type authorizeVote struct {
+	Version   uint   `json:"version"`
+	Action    string `json:"action"`
+	Token     string `json:"token"`
+	Signature string `json:"signature"`
+	PublicKey string `json:"publickey"`
+	Receipt   string `json:"receipt"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// voteOption is a legacy vote option.
+type voteOption struct {
+	ID          string `json:"id"`
+	Description string `json:"description"`
+	Bits        uint64 `json:"bits"`
+}
+
+// vote contains the parameters of a legacy ticket vote. ProposalVersion and
+// Type were added in version 2 of the start vote metadata stream.
+type vote struct {
+	Token            string       `json:"token"`
+	ProposalVersion  uint32       `json:"proposalversion,omitempty"`
+	Type             uint32       `json:"type,omitempty"`
+	Mask             uint64       `json:"mask"`
+	Duration         uint32       `json:"duration"`
+	QuorumPercentage uint32       `json:"quorumpercentage"`
+	PassPercentage   uint32       `json:"passpercentage"`
+	Options          []voteOption `json:"options"`
+}
+
+// startVote is the legacy metadata stream that contains the admin signed
+// vote parameters.
+type startVote struct {
+	Version   uint   `json:"version"`
+	PublicKey string `json:"publickey"`
+	Vote      vote   `json:"vote"`
+	Signature string `json:"signature"`
+}
+
+// startVoteReply is the legacy metadata stream that contains the server
+// generated vote details. The block heights are encoded as strings.
+type startVoteReply struct {
+	Version          uint     `json:"version"`
+	StartBlockHeight string   `json:"startblockheight"`
+	StartBlockHash   string   `json:"startblockhash"`
+	EndHeight        string   `json:"endheight"`
+	EligibleTickets  []string `json:"eligibletickets"`
+}
+
+// castVote is a legacy ticket vote.
+//
+// Signature is the signature of the Token+Ticket+VoteBit using the ticket's
+// largest commitment address.
+type castVote struct {
+	Token     string `json:"token"`
+	Ticket    string `json:"ticket"`
+	VoteBit   string `json:"votebit"`
+	Signature string `json:"signature"`
+}
+
+// castVoteJournal is the legacy ballot journal entry for a cast vote.
+type castVoteJournal struct {
+	CastVote castVote `json:"castvote"`
+	Receipt  string   `json:"receipt"`
+}
+
+// statusChange is a legacy record status change. It combines the version 1
+// and version 2 status change metadata stream structures. Version 1 status
+// changes do not include a signature.
+//
+// Signature is the admin signature of the Token+NewStatus+StatusChangeMessage.
+type statusChange struct {
+	Version             uint
+	NewStatus           pd.RecordStatusT
+	StatusChangeMessage string
+	Signature           string
+	AdminPubKey         string
+	Timestamp           int64
+}
+
+// recordVersion contains the content of a single version of a legacy record.
+type recordVersion struct {
+	Version        uint32 // Version directory name
+	RecordMetadata backend.RecordMetadata
+	Metadata       []backend.MetadataStream
+	Files          []backend.File
+}
+
+// commentsJournal contains the replayed entries of a legacy comments journal
+// in the order that they were appended to the journal.
+type commentsJournal struct {
+	Adds  []decredplugin.Comment
+	Dels  []decredplugin.CensorComment
+	Likes []likeComment
+}
+
+// record contains the full content of a legacy gitbe record.
+type record struct {
+	Token    string
+	Vetted   bool
+	Versions []recordVersion // Ordered from oldest to newest
+
+	// The following fields are parsed from the metadata streams of the
+	// latest record version and from the record journals.
+	StatusChanges  []statusChange
+	AuthorizeVote  *authorizeVote
+	StartVote      *startVote
+	StartVoteReply *startVoteReply
+	Comments       commentsJournal
+	CastVotes      []castVoteJournal
+}
+
+// latest returns the latest version of the record.
+func (r *record) latest() recordVersion {
+	return r.Versions[len(r.Versions)-1]
+}
+
+// mdStream returns the payload of the metadata stream with the provided ID.
+// An empty string is returned if the metadata stream does not exist.
+func mdStream(metadata []backend.MetadataStream, id uint64) string {
+	for _, v := range metadata {
+		if v.ID == id {
+			return v.Payload
+		}
+	}
+	return ""
+}
+
+// decodeProposalGeneral decodes the legacy proposal general metadata stream.
+func decodeProposalGeneral(payload string) (*proposalGeneral, error) {
+	var pg proposalGeneral
+	err := json.Unmarshal([]byte(payload), &pg)
+	if err != nil {
+		return nil, err
+	}
+	switch pg.Version {
+	case 1, 2:
+	default:
+		return nil, fmt.Errorf("invalid proposal general version: %v",
+			pg.Version)
+	}
+	return &pg, nil
+}
+
+// decodeStatusChanges decodes the legacy status changes metadata stream. The
+// status changes are returned sorted from oldest to newest.
+func decodeStatusChanges(payload string) ([]statusChange, error) {
+	v1, v2, err := mdstream.DecodeRecordStatusChanges([]byte(payload))
+	if err != nil {
+		return nil, err
+	}
+	sc := make([]statusChange, 0, len(v1)+len(v2))
+	for _, v := range v1 {
+		sc = append(sc, statusChange{
+			Version:             v.Version,
+			NewStatus:           v.NewStatus,
+			StatusChangeMessage: v.StatusChangeMessage,
+			AdminPubKey:         v.AdminPubKey,
+			Timestamp:           v.Timestamp,
+		})
+	}
+	for _, v := range v2 {
+		sc = append(sc, statusChange{
+			Version:             v.Version,
+			NewStatus:           v.NewStatus,
+			StatusChangeMessage: v.StatusChangeMessage,
+			Signature:           v.Signature,
+			AdminPubKey:         v.AdminPubKey,
+			Timestamp:           v.Timestamp,
+		})
+	}
+	sort.SliceStable(sc, func(i, j int) bool {
+		return sc[i].Timestamp < sc[j].Timestamp
+	})
+	return sc, nil
+}
+
+// decodeJournal decodes a legacy journal. A legacy journal contains one entry
+// per line. Each entry is a JSON encoded journalAction followed by the JSON
+// encoded structure that the action applies to. The provided function is
+// invoked for every entry and is responsible for decoding the structure.
+func decodeJournal(journal []byte, f func(action string, d *json.Decoder) error) error {
+	s := bufio.NewScanner(bytes.NewReader(journal))
+	s.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for i := 1; s.Scan(); i++ {
+		line := strings.TrimSpace(s.Text())
+		if line == "" {
+			continue
+		}
+		d := json.NewDecoder(strings.NewReader(line))
+		var ja journalAction
+		err := d.Decode(&ja)
+		if err != nil {
+			return fmt.Errorf("line %v: journal action: %v", i, err)
+		}
+		err = f(ja.Action, d)
+		if err != nil {
+			return fmt.Errorf("line %v: %v", i, err)
+		}
+	}
+	return s.Err()
+}
+
+// decodeCommentsJournal decodes a legacy comments journal.
+func decodeCommentsJournal(journal []byte) (*commentsJournal, error) {
+	cj := commentsJournal{
+		Adds:  make([]decredplugin.Comment, 0, 64),
+		Dels:  make([]decredplugin.CensorComment, 0, 16),
+		Likes: make([]likeComment, 0, 256),
+	}
+	err := decodeJournal(journal, func(action string, d *json.Decoder) error {
+		switch action {
+		case journalActionAdd:
+			var c decredplugin.Comment
+			err := d.Decode(&c)
+			if err != nil {
+				return fmt.Errorf("journal add: %v", err)
+			}
+			cj.Adds = append(cj.Adds, c)
+		case journalActionDel:
+			var cc decredplugin.CensorComment
+			err := d.Decode(&cc)
+			if err != nil {
+				return fmt.Errorf("journal del: %v", err)
+			}
+			cj.Dels = append(cj.Dels, cc)
+		case journalActionAddLike:
+			var lc likeComment
+			err := d.Decode(&lc)
+			if err != nil {
+				return fmt.Errorf("journal addlike: %v", err)
+			}
+			cj.Likes = append(cj.Likes, lc)
+		default:
+			return fmt.Errorf("invalid action: %v", action)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &cj, nil
+}
+
+// decodeBallotJournal decodes a legacy ballot journal.
+func decodeBallotJournal(journal []byte) ([]castVoteJournal, error) {
+	votes := make([]castVoteJournal, 0, 1024)
+	err := decodeJournal(journal, func(action string, d *json.Decoder) error {
+		if action != journalActionAdd {
+			return fmt.Errorf("invalid action: %v", action)
+		}
+		var cvj castVoteJournal
+		err := d.Decode(&cvj)
+		if err != nil {
+			return fmt.Errorf("journal add: %v", err)
+		}
+		votes = append(votes, cvj)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return votes, nil
+}
+
+// parseUint32 parses a legacy numeric string, such as a comment ID or a block
+// height. An empty string is parsed as zero.
+func parseUint32(s string) (uint32, error) {
+	if s == "" {
+		return 0, nil
+	}
+	u, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return 0, err
+	}
+	return uint32(u), nil
+}
@@ -0,0 +1,347 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/tstore"
+	"github.com/decred/politeia/politeiad/plugins/comments"
+)
+
+const (
+	// blobsSaveBatchSize is the maximum number of plugin data blobs that
+	// are saved to tstore in a single call.
+	blobsSaveBatchSize = 500
+)
+
+// migrator migrates legacy gitbe records into a tstore instance.
+type migrator struct {
+	tstore *tstore.Tstore
+
+	// userIDs contains the user ID for every public key that is found
+	// in the legacy data. Public keys that are not in the user ID map
+	// use the public key as the user ID and are added to unmapped.
+	userIDs  map[string]string // [publicKey]userID
+	unmapped map[string]struct{}
+}
+
+// userID returns the user ID for the provided public key.
+func (m *migrator) userID(publicKey string) string {
+	userID, ok := m.userIDs[publicKey]
+	if !ok {
+		m.unmapped[publicKey] = struct{}{}
+		return publicKey
+	}
+	return userID
+}
+
+// metadata returns the metadata streams for a legacy record version. Only
+// the status changes that occurred prior to the version timestamp are
+// included. Archived and censored status changes are only included when
+// final is set since these statuses are only applied when the record is
+// frozen.
+func (m *migrator) metadata(token string, r *record, rv recordVersion, final bool) ([]backendv2.MetadataStream, error) {
+	md := make([]backendv2.MetadataStream, 0, 3)
+
+	// User metadata
+	if s := mdStream(rv.Metadata, mdStreamProposalGeneral); s != "" {
+		pg, err := decodeProposalGeneral(s)
+		if err != nil {
+			return nil, fmt.Errorf("proposal general: %v", err)
+		}
+		um, err := convertUserMetadata(*pg, m.userID(pg.PublicKey))
+		if err != nil {
+			return nil, err
+		}
+		md = append(md, *um)
+	}
+
+	// Status changes
+	sc := make([]statusChange, 0, len(r.StatusChanges))
+	for _, v := range r.StatusChanges {
+		if final {
+			sc = append(sc, v)
+			continue
+		}
+		if v.Timestamp > rv.RecordMetadata.Timestamp {
+			continue
+		}
+		switch convertStatusFromPD(v.NewStatus) {
+		case backendv2.StatusArchived, backendv2.StatusCensored:
+			continue
+		}
+		sc = append(sc, v)
+	}
+	if len(sc) > 0 {
+		s, err := convertStatusChanges(token, rv.Version, sc)
+		if err != nil {
+			return nil, err
+		}
+		md = append(md, *s)
+	}
+
+	// Legacy metadata
+	lm, err := convertLegacyMetadata(r.Token, rv)
+	if err != nil {
+		return nil, err
+	}
+	md = append(md, *lm)
+
+	return md, nil
+}
+
+// timestampedBlob is a plugin data blob along with the timestamp of the data
+// that it contains. It's used to save plugin data blobs in chronological
+// order.
+type timestampedBlob struct {
+	timestamp int64
+	entry     store.BlobEntry
+}
+
+// blobsSave saves the provided plugin data blobs to tstore in batches.
+func (m *migrator) blobsSave(token []byte, blobs []store.BlobEntry) error {
+	for i := 0; i < len(blobs); i += blobsSaveBatchSize {
+		end := i + blobsSaveBatchSize
+		if end > len(blobs) {
+			end = len(blobs)
+		}
+		err := m.tstore.BlobsSave(token, blobs[i:end])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// commentsSave saves the comments plugin data of a legacy record to tstore.
+// The comment adds, dels, and votes are saved in chronological order. The
+// comment add blobs of censored comments are deleted once all blobs have
+// been saved, the same way the comments plugin handles a comment del.
+func (m *migrator) commentsSave(token []byte, r *record, rr *recordReport) error {
+	var (
+		t     = hex.EncodeToString(token)
+		state = comments.RecordStateVetted
+		cj    = r.Comments
+		blobs = make([]timestampedBlob, 0,
+			len(cj.Adds)+len(cj.Dels)+len(cj.Likes))
+
+		adds       = make(map[string]comments.CommentAdd, len(cj.Adds))
+		addDigests = make(map[string][]byte, len(cj.Adds))
+	)
+	if !r.Vetted {
+		state = comments.RecordStateUnvetted
+	}
+	for _, v := range cj.Adds {
+		ca, err := convertCommentAdd(t, state, v, m.userID(v.PublicKey))
+		if err != nil {
+			return err
+		}
+		be, err := convertBlobEntry(dataDescriptorCommentAdd, *ca)
+		if err != nil {
+			return err
+		}
+		d, err := hex.DecodeString(be.Digest)
+		if err != nil {
+			return err
+		}
+		adds[v.CommentID] = *ca
+		addDigests[v.CommentID] = d
+		blobs = append(blobs, timestampedBlob{ca.Timestamp, *be})
+	}
+	dels := make([][]byte, 0, len(cj.Dels))
+	for _, v := range cj.Dels {
+		ca, ok := adds[v.CommentID]
+		if !ok {
+			return fmt.Errorf("censored comment %v not found", v.CommentID)
+		}
+		cd := convertCommentDel(t, state, v, ca)
+		be, err := convertBlobEntry(dataDescriptorCommentDel, cd)
+		if err != nil {
+			return err
+		}
+		dels = append(dels, addDigests[v.CommentID])
+		blobs = append(blobs, timestampedBlob{cd.Timestamp, *be})
+	}
+	for _, v := range cj.Likes {
+		cv, err := convertCommentVote(t, state, v, m.userID(v.PublicKey))
+		if err != nil {
+			return err
+		}
+		be, err := convertBlobEntry(dataDescriptorCommentVote, *cv)
+		if err != nil {
+			return err
+		}
+		blobs = append(blobs, timestampedBlob{cv.Timestamp, *be})
+	}
+
+	// Save the blobs in chronological order. The journal order is
+	// used for entries that have the same timestamp.
+	sort.SliceStable(blobs, func(i, j int) bool {
+		return blobs[i].timestamp < blobs[j].timestamp
+	})
+	entries := make([]store.BlobEntry, 0, len(blobs))
+	for _, v := range blobs {
+		entries = append(entries, v.entry)
+	}
+	err := m.blobsSave(token, entries)
+	if err != nil {
+		return fmt.Errorf("save comments: %v", err)
+	}
+	if len(dels) > 0 {
+		err = m.tstore.BlobsDel(token, dels)
+		if err != nil {
+			return fmt.Errorf("del censored comments: %v", err)
+		}
+	}
+
+	rr.Comments = len(cj.Adds)
+	rr.CommentDels = len(cj.Dels)
+	rr.CommentVotes = len(cj.Likes)
+
+	return nil
+}
+
+// ticketVoteSave saves the ticketvote plugin data of a legacy record to
+// tstore.
+func (m *migrator) ticketVoteSave(token []byte, r *record, rr *recordReport) error {
+	var (
+		t       = hex.EncodeToString(token)
+		version = r.latest().Version
+		entries = make([]store.BlobEntry, 0, len(r.CastVotes)+2)
+	)
+	if r.AuthorizeVote != nil {
+		ad := convertAuthDetails(t, version, *r.AuthorizeVote)
+		be, err := convertBlobEntry(dataDescriptorAuthDetails, ad)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, *be)
+		rr.Authorizations = 1
+	}
+	if r.StartVote != nil {
+		if r.StartVoteReply == nil {
+			return fmt.Errorf("start vote reply not found")
+		}
+		vd, err := convertVoteDetails(t, version, *r.StartVote,
+			*r.StartVoteReply)
+		if err != nil {
+			return err
+		}
+		be, err := convertBlobEntry(dataDescriptorVoteDetails, *vd)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, *be)
+		rr.VoteDetails = true
+	}
+	for _, v := range r.CastVotes {
+		cv := convertCastVoteDetails(t, v)
+		be, err := convertBlobEntry(dataDescriptorCastVoteDetails, cv)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, *be)
+	}
+	err := m.blobsSave(token, entries)
+	if err != nil {
+		return fmt.Errorf("save votes: %v", err)
+	}
+
+	rr.CastVotes = len(r.CastVotes)
+
+	return nil
+}
+
+// migrateRecord recreates a legacy record in tstore. Every legacy record
+// version is saved as a tstore record version, followed by the plugin data.
+// Archived and censored records are frozen once all of their content has been
+// saved. The file blobs of censored records are deleted.
+func (m *migrator) migrateRecord(r *record, rr *recordReport) error {
+	var (
+		state      = backendv2.StateUnvetted
+		saveStatus = backendv2.StatusUnreviewed
+	)
+	if r.Vetted {
+		state = backendv2.StateVetted
+		saveStatus = backendv2.StatusPublic
+	}
+	latest := r.latest()
+	status, err := convertStatus(latest.RecordMetadata.Status)
+	if err != nil {
+		return err
+	}
+
+	token, err := m.tstore.RecordNew()
+	if err != nil {
+		return fmt.Errorf("RecordNew: %v", err)
+	}
+	t := hex.EncodeToString(token)
+	rr.Token = t
+	rr.State = backendv2.States[state]
+	rr.Status = backendv2.Statuses[status]
+
+	// Save the record versions
+	var iteration uint32
+	for _, v := range r.Versions {
+		iteration++
+		rm, err := convertRecordMetadata(t, v, state, saveStatus,
+			v.Version, iteration)
+		if err != nil {
+			return err
+		}
+		md, err := m.metadata(t, r, v, false)
+		if err != nil {
+			return fmt.Errorf("version %v: %v", v.Version, err)
+		}
+		err = m.tstore.RecordSave(token, *rm, md, convertFiles(v.Files))
+		if err != nil {
+			return fmt.Errorf("RecordSave %v: %v", v.Version, err)
+		}
+	}
+	rr.Versions = len(r.Versions)
+	rr.StatusChanges = len(r.StatusChanges)
+
+	// Save the plugin data
+	err = m.commentsSave(token, r, rr)
+	if err != nil {
+		return err
+	}
+	err = m.ticketVoteSave(token, r, rr)
+	if err != nil {
+		return err
+	}
+
+	// Apply the final record status
+	switch status {
+	case backendv2.StatusArchived, backendv2.StatusCensored:
+		iteration++
+		rm, err := convertRecordMetadata(t, latest, state, status,
+			latest.Version, iteration)
+		if err != nil {
+			return err
+		}
+		md, err := m.metadata(t, r, latest, true)
+		if err != nil {
+			return err
+		}
+		err = m.tstore.RecordFreeze(token, *rm, md, convertFiles(latest.Files))
+		if err != nil {
+			return fmt.Errorf("RecordFreeze: %v", err)
+		}
+		if status == backendv2.StatusCensored {
+			err = m.tstore.RecordDel(token)
+			if err != nil {
+				return fmt.Errorf("RecordDel: %v", err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,246 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/util"
+)
+
+// recordReport contains the migration results for a single legacy record.
+type recordReport struct {
+	LegacyToken string `json:"legacytoken"`
+	Token       string `json:"token,omitempty"` // Tstore token
+	State       string `json:"state,omitempty"`
+	Status      string `json:"status,omitempty"`
+
+	// Migrated content counts
+	Versions       int  `json:"versions"`
+	StatusChanges  int  `json:"statuschanges"`
+	Comments       int  `json:"comments"`
+	CommentDels    int  `json:"commentdels"`
+	CommentVotes   int  `json:"commentvotes"`
+	Authorizations int  `json:"authorizations"`
+	VoteDetails    bool `json:"votedetails"`
+	CastVotes      int  `json:"castvotes"`
+
+	// Verification results. SignaturesVerified is the number of legacy
+	// signatures that were successfully verified. SignatureErrors
+	// contains the legacy signatures that failed verification. These
+	// are reported, but do not prevent a record from being migrated.
+	// Mismatches contains the differences that were found between the
+	// legacy record and the migrated tstore record.
+	SignaturesVerified int      `json:"signaturesverified"`
+	SignatureErrors    []string `json:"signatureerrors,omitempty"`
+	Mismatches         []string `json:"mismatches,omitempty"`
+
+	// Error is set if the record could not be migrated.
+	Error string `json:"error,omitempty"`
+}
+
+// ok returns whether the record was migrated and verified without any
+// errors.
+func (r *recordReport) ok() bool {
+	return r.Error == "" && len(r.Mismatches) == 0
+}
+
+// report is the verification report of a migration.
+type report struct {
+	Records []recordReport `json:"records"`
+
+	// UnmappedKeys contains the public keys that were not found in the
+	// user ID map. The public key was used as the user ID for these.
+	UnmappedKeys []string `json:"unmappedkeys,omitempty"`
+}
+
+// loadReport loads the report from a prior migration run. An empty report is
+// returned if the file does not exist.
+func loadReport(fp string) (*report, error) {
+	b, err := ioutil.ReadFile(fp)
+	if os.IsNotExist(err) {
+		return &report{
+			Records: make([]recordReport, 0, 1024),
+		}, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var r report
+	err = json.Unmarshal(b, &r)
+	if err != nil {
+		return nil, fmt.Errorf("decode report %v: %v", fp, err)
+	}
+	return &r, nil
+}
+
+// save writes the report to the provided file path.
+func (r *report) save(fp string) error {
+	sort.Strings(r.UnmappedKeys)
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(fp, b, 0600)
+}
+
+// signatureVerify verifies a legacy signature and records the result.
+func (rr *recordReport) signatureVerify(desc, signature, publicKey, msg string) {
+	err := util.VerifySignature(signature, publicKey, msg)
+	if err != nil {
+		rr.SignatureErrors = append(rr.SignatureErrors,
+			fmt.Sprintf("%v: %v", desc, err))
+		return
+	}
+	rr.SignaturesVerified++
+}
+
+// verifySignatures verifies the original client signatures of a legacy
+// record using the legacy token and the legacy message formats. Version 1
+// status changes were not signed. Cast vote signatures are made using the
+// ticket commitment address, which the legacy ballot journal does not record,
+// so they are not verified.
+func verifySignatures(r *record, rr *recordReport) {
+	for _, v := range r.Versions {
+		s := mdStream(v.Metadata, mdStreamProposalGeneral)
+		if s == "" {
+			continue
+		}
+		pg, err := decodeProposalGeneral(s)
+		if err != nil {
+			// Decoding errors are caught during the migration
+			continue
+		}
+		rr.signatureVerify(fmt.Sprintf("version %v user metadata", v.Version),
+			pg.Signature, pg.PublicKey, v.RecordMetadata.Merkle)
+	}
+	for _, v := range r.StatusChanges {
+		if v.Version < 2 {
+			continue
+		}
+		msg := r.Token + strconv.Itoa(int(v.NewStatus)) + v.StatusChangeMessage
+		rr.signatureVerify(fmt.Sprintf("status change %v", v.Timestamp),
+			v.Signature, v.AdminPubKey, msg)
+	}
+	for _, v := range r.Comments.Adds {
+		rr.signatureVerify(fmt.Sprintf("comment %v", v.CommentID),
+			v.Signature, v.PublicKey, r.Token+v.ParentID+v.Comment)
+	}
+	for _, v := range r.Comments.Dels {
+		rr.signatureVerify(fmt.Sprintf("comment del %v", v.CommentID),
+			v.Signature, v.PublicKey, r.Token+v.CommentID+v.Reason)
+	}
+	for _, v := range r.Comments.Likes {
+		rr.signatureVerify(fmt.Sprintf("comment vote %v %v", v.CommentID,
+			v.Timestamp), v.Signature, v.PublicKey,
+			r.Token+v.CommentID+v.Action)
+	}
+	if av := r.AuthorizeVote; av != nil {
+		msg := r.Token + versionString(r.latest().Version) + av.Action
+		rr.signatureVerify("vote authorization", av.Signature,
+			av.PublicKey, msg)
+	}
+}
+
+// mismatch records a difference between the legacy record and the migrated
+// tstore record.
+func (rr *recordReport) mismatch(format string, args ...interface{}) {
+	rr.Mismatches = append(rr.Mismatches, fmt.Sprintf(format, args...))
+}
+
+// verifyRecord reads back a migrated record from tstore and verifies that it
+// is coherent with the legacy record.
+func (m *migrator) verifyRecord(r *record, rr *recordReport) error {
+	token, err := hex.DecodeString(rr.Token)
+	if err != nil {
+		return err
+	}
+
+	// Verify the latest record metadata
+	latest := r.latest()
+	status, err := convertStatus(latest.RecordMetadata.Status)
+	if err != nil {
+		return err
+	}
+	tr, err := m.tstore.RecordPartial(token, 0, nil, true)
+	if err != nil {
+		return fmt.Errorf("RecordPartial: %v", err)
+	}
+	rm := tr.RecordMetadata
+	if rm.Version != latest.Version {
+		rr.mismatch("version: got %v, want %v", rm.Version, latest.Version)
+	}
+	if rm.Status != status {
+		rr.mismatch("status: got %v, want %v",
+			backendv2.Statuses[rm.Status], backendv2.Statuses[status])
+	}
+
+	// Verify the content of every record version. The files of
+	// censored records have been deleted.
+	for _, v := range r.Versions {
+		if status == backendv2.StatusCensored {
+			break
+		}
+		tr, err := m.tstore.Record(token, v.Version)
+		if err != nil {
+			return fmt.Errorf("Record %v: %v", v.Version, err)
+		}
+		if tr.RecordMetadata.Merkle != v.RecordMetadata.Merkle {
+			rr.mismatch("version %v merkle: got %v, want %v", v.Version,
+				tr.RecordMetadata.Merkle, v.RecordMetadata.Merkle)
+		}
+		files := make(map[string]string, len(tr.Files)) // [name]digest
+		for _, f := range tr.Files {
+			files[f.Name] = f.Digest
+		}
+		if len(files) != len(v.Files) {
+			rr.mismatch("version %v files: got %v, want %v", v.Version,
+				len(files), len(v.Files))
+		}
+		for _, f := range v.Files {
+			if files[f.Name] != f.Digest {
+				rr.mismatch("version %v file %v: got digest %v, want %v",
+					v.Version, f.Name, files[f.Name], f.Digest)
+			}
+		}
+	}
+
+	// Verify the plugin data blob counts. The digests are retrieved
+	// from the tlog leaves, so the digests of deleted blobs are still
+	// included.
+	voteDetails := 0
+	if rr.VoteDetails {
+		voteDetails = 1
+	}
+	want := []struct {
+		desc  string
+		count int
+	}{
+		{dataDescriptorCommentAdd, rr.Comments},
+		{dataDescriptorCommentDel, rr.CommentDels},
+		{dataDescriptorCommentVote, rr.CommentVotes},
+		{dataDescriptorAuthDetails, rr.Authorizations},
+		{dataDescriptorVoteDetails, voteDetails},
+		{dataDescriptorCastVoteDetails, rr.CastVotes},
+	}
+	for _, v := range want {
+		digests, err := m.tstore.DigestsByDataDesc(token, []string{v.desc})
+		if err != nil {
+			return fmt.Errorf("DigestsByDataDesc %v: %v", v.desc, err)
+		}
+		if len(digests) != v.count {
+			rr.mismatch("%v blobs: got %v, want %v", v.desc,
+				len(digests), v.count)
+		}
+	}
+
+	return nil
+}
@@ -9,13 +9,16 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io/ioutil"
 	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/decred/dcrd/dcrutil/v3"
@@ -54,6 +57,8 @@ var (
 	rpcpass     = flag.String("rpcpass", "", "RPC password for privileged calls")
 	rpchost     = flag.String("rpchost", "", "RPC host")
 	rpccert     = flag.String("rpccert", "", "RPC certificate")
+	clientcert  = flag.String("clientcert", "", "Client certificate")
+	clientkey   = flag.String("clientkey", "", "Client certificate key")
 	interactive = flag.String("interactive", "", "Set to "+
 		allowInteractive+" to to turn off interactive mode during "+
 		"identity fetch")
@@ -75,8 +80,33 @@ Available commands:
                    Args: <token> <status>
   record           Get a record 
                    Args: <token>
+  diff             Get the changes between two record versions
+                   Args: <token> <from> [to]
   inventory        Get the record inventory 
                    Args (optional): <state> <status> <page>
+  invquery         Query the record inventory
+                   Args (optional): [state:<state>] [status:<status>]
+                         [sort:<created|updated>] [asc] [cursor:<cursor>]
+                         [limit:<limit>] [attr:<key>=<value>]...
+  fsck             Perform a backend filesystem check (admin)
+  keyrotate        Rotate the data encryption key (admin)
+                   Args (optional): <keyfile>
+  keystatus        Get the data encryption key status (admin)
+  blobcachestats   Get the blob cache hit and miss stats (admin)
+  anchorstatus     Get the anchor status and recent anchor drops (admin)
+  anchordrop       Drop an anchor without waiting for the schedule (admin)
+  export           Export a record bundle to a file (admin)
+                   Args: <token> <filepath>
+  import           Import a record bundle from a file (admin)
+                   Args: <filepath>
+  pluginsettings   Get the plugin settings and update history (admin)
+                   Args: <pluginid>
+  pluginsettingsupdate Update plugin settings at runtime (admin)
+                   Args: <pluginid> <key>=<value>... [reason:<reason>]
+  backup           Save a backup of the backend (admin)
+  cacherebuild     Rebuild plugin caches and wait for it to finish (admin)
+                   Args (optional): <pluginid>...
+  cacherebuildstatus Get the status of the most recent cache rebuild (admin)
 
 Metadata actions: appendmetadata, overwritemetadata
 File actions: add, del
@@ -398,10 +428,26 @@ func getFile(filename string) (*v2.File, *[sha256.Size]byte, error) {
 	return file, &digest32, nil
 }
 
+// newClient returns a new politeiad client. The client certificate is set
+// when one has been provided.
+func newClient(pid *identity.PublicIdentity) (*pdclient.Client, error) {
+	c, err := pdclient.New(*rpchost, *rpccert, *rpcuser, *rpcpass, pid)
+	if err != nil {
+		return nil, err
+	}
+	if *clientcert != "" {
+		err = c.SetClientCert(*clientcert, *clientkey)
+		if err != nil {
+			return nil, fmt.Errorf("client cert: %v", err)
+		}
+	}
+	return c, nil
+}
+
 // getIdentity retrieves the politeiad server identity, i.e. public key.
 func getIdentity() error {
 	// Fetch remote identity
-	c, err := pdclient.New(*rpchost, *rpccert, *rpcuser, *rpcpass, nil)
+	c, err := newClient(nil)
 	if err != nil {
 		return err
 	}
@@ -409,12 +455,26 @@ func getIdentity() error {
 	if err != nil {
 		return err
 	}
+	history, err := c.IdentityHistory(context.Background())
+	if err != nil {
+		return err
+	}
 
 	rf := filepath.Join(defaultHomeDir, defaultIdentityFilename)
 
 	// Pretty print identity.
 	fmt.Printf("Key        : %x\n", id.Key)
 	fmt.Printf("Fingerprint: %v\n", id.Fingerprint())
+	if len(history) > 0 {
+		fmt.Printf("Key history:\n")
+		for _, v := range history {
+			activation := "initial key"
+			if v.Activation > 0 {
+				activation = time.Unix(v.Activation, 0).UTC().String()
+			}
+			fmt.Printf("  %v %v\n", v.PublicKey, activation)
+		}
+	}
 
 	// Ask user if we like this identity
 	if *interactive != allowInteractive {
@@ -467,7 +527,7 @@ func recordNew() error {
 	}
 
 	// Setup client
-	c, err := pdclient.New(*rpchost, *rpccert, *rpcuser, *rpcpass, pid)
+	c, err := newClient(pid)
 	if err != nil {
 		return err
 	}
@@ -581,7 +641,7 @@ func recordEdit() error {
 	}
 
 	// Setup client
-	c, err := pdclient.New(*rpchost, *rpccert, *rpcuser, *rpcpass, pid)
+	c, err := newClient(pid)
 	if err != nil {
 		return err
 	}
@@ -627,7 +687,7 @@ func recordEditMetadata() error {
 	}
 
 	// Setup client
-	c, err := pdclient.New(*rpchost, *rpccert, *rpcuser, *rpcpass, pid)
+	c, err := newClient(pid)
 	if err != nil {
 		return err
 	}
@@ -678,7 +738,7 @@ func recordSetStatus() error {
 	}
 
 	// Setup client
-	c, err := pdclient.New(*rpchost, *rpccert, *rpcuser, *rpcpass, pid)
+	c, err := newClient(pid)
 	if err != nil {
 		return err
 	}
@@ -723,7 +783,7 @@ func record() error {
 	}
 
 	// Setup client
-	c, err := pdclient.New(*rpchost, *rpccert, *rpcuser, *rpcpass, pid)
+	c, err := newClient(pid)
 	if err != nil {
 		return err
 	}
@@ -752,6 +812,92 @@ func record() error {
 	return pdclient.RecordVerify(r, pid.String())
 }
 
+// printDiffHunks prints diff hunks using the unified diff format.
+func printDiffHunks(hunks []v2.DiffHunk) {
+	for _, h := range hunks {
+		fmt.Printf("@@ -%v,%v +%v,%v @@\n",
+			h.FromLine, h.FromLines, h.ToLine, h.ToLines)
+		for _, l := range h.Lines {
+			var prefix string
+			switch l.Type {
+			case v2.DiffAdded:
+				prefix = "+"
+			case v2.DiffRemoved:
+				prefix = "-"
+			default:
+				prefix = " "
+			}
+			fmt.Printf("%v%v\n", prefix, l.Text)
+		}
+	}
+}
+
+// recordDiff retrieves and prints the changes between two versions of a
+// record. The most recent version is used if the to version is not provided.
+func recordDiff() error {
+	flags := flag.Args()[1:] // Chop off action.
+
+	// Unpack args
+	if len(flags) != 2 && len(flags) != 3 {
+		return fmt.Errorf("invalid number of arguments (%v); you must "+
+			"provide a token, a from version, and an optional to version",
+			len(flags))
+	}
+	var (
+		token    = flags[0]
+		from, to uint64
+		err      error
+	)
+	from, err = strconv.ParseUint(flags[1], 10, 32)
+	if err != nil {
+		return fmt.Errorf("unable to parse from version '%v': %v",
+			flags[1], err)
+	}
+	if len(flags) == 3 {
+		to, err = strconv.ParseUint(flags[2], 10, 32)
+		if err != nil {
+			return fmt.Errorf("unable to parse to version '%v': %v",
+				flags[2], err)
+		}
+	}
+
+	// Load server identity
+	pid, err := identity.LoadPublicIdentity(*identityFilename)
+	if err != nil {
+		return err
+	}
+
+	// Setup client
+	c, err := newClient(pid)
+	if err != nil {
+		return err
+	}
+
+	// Get diff
+	rd, err := c.RecordDiff(context.Background(), token,
+		uint32(from), uint32(to))
+	if err != nil {
+		return err
+	}
+
+	// Print diff
+	fmt.Printf("Version %v %v %v -> version %v %v %v\n",
+		rd.From.Version, v2.RecordStates[rd.From.State],
+		v2.RecordStatuses[rd.From.Status], rd.To.Version,
+		v2.RecordStates[rd.To.State], v2.RecordStatuses[rd.To.Status])
+	for _, v := range rd.Files {
+		fmt.Printf("File %v %v\n", v.Name, v2.Diffs[v.Type])
+		printDiffHunks(v.Hunks)
+	}
+	for _, v := range rd.Metadata {
+		fmt.Printf("Metadata %v %v %v\n", v.PluginID, v.StreamID,
+			v2.Diffs[v.Type])
+		printDiffHunks(v.Hunks)
+	}
+
+	return nil
+}
+
 // recordInventory retrieves the censorship record tokens of the records in
 // the inventory, categorized by their record state and record status.
 func recordInventory() error {
@@ -789,7 +935,7 @@ func recordInventory() error {
 	}
 
 	// Setup client
-	c, err := pdclient.New(*rpchost, *rpccert, *rpcuser, *rpcpass, pid)
+	c, err := newClient(pid)
 	if err != nil {
 		return err
 	}
@@ -814,6 +960,674 @@ func recordInventory() error {
 	return nil
 }
 
+// inventoryQuery retrieves a page of inventory entries that match the filters
+// provided in the arguments. The cursor that is printed with the entries can
+// be used to request the next page.
+func inventoryQuery() error {
+	flags := flag.Args()[1:] // Chop off action.
+
+	// Parse args
+	q := v2.InventoryQuery{
+		Attributes: make(map[string]string),
+	}
+	for _, v := range flags {
+		switch {
+		case v == "asc":
+			q.Ascending = true
+		case strings.HasPrefix(v, "state:"):
+			s := strings.TrimPrefix(v, "state:")
+			q.State = convertState(s)
+			if q.State == v2.RecordStateInvalid {
+				return fmt.Errorf("invalid state '%v'", s)
+			}
+		case strings.HasPrefix(v, "status:"):
+			s := strings.TrimPrefix(v, "status:")
+			q.Status = convertStatus(s)
+			if q.Status == v2.RecordStatusInvalid {
+				return fmt.Errorf("invalid status '%v'", s)
+			}
+		case strings.HasPrefix(v, "sort:"):
+			switch strings.TrimPrefix(v, "sort:") {
+			case "updated":
+				q.Sort = v2.InventorySortUpdated
+			case "created":
+				q.Sort = v2.InventorySortCreated
+			default:
+				return fmt.Errorf("invalid sort '%v'", v)
+			}
+		case strings.HasPrefix(v, "cursor:"):
+			q.Cursor = strings.TrimPrefix(v, "cursor:")
+		case strings.HasPrefix(v, "limit:"):
+			u, err := strconv.ParseUint(strings.TrimPrefix(v, "limit:"), 10, 32)
+			if err != nil {
+				return fmt.Errorf("unable to parse limit '%v': %v", v, err)
+			}
+			q.Limit = uint32(u)
+		case strings.HasPrefix(v, "attr:"):
+			kv := strings.SplitN(strings.TrimPrefix(v, "attr:"), "=", 2)
+			if len(kv) != 2 {
+				return fmt.Errorf("invalid attribute '%v'", v)
+			}
+			q.Attributes[kv[0]] = kv[1]
+		default:
+			return fmt.Errorf("invalid argument '%v'", v)
+		}
+	}
+
+	// Load server identity
+	pid, err := identity.LoadPublicIdentity(*identityFilename)
+	if err != nil {
+		return err
+	}
+
+	// Setup client
+	c, err := newClient(pid)
+	if err != nil {
+		return err
+	}
+
+	// Query inventory
+	iqr, err := c.InventoryQuery(context.Background(), q)
+	if err != nil {
+		return err
+	}
+
+	if *verbose {
+		fmt.Printf("%v\n", util.FormatJSON(iqr.Entries))
+		if iqr.Cursor != "" {
+			fmt.Printf("Cursor: %v\n", iqr.Cursor)
+		}
+	}
+
+	return nil
+}
+
+// fsck performs a filesystem check on the backend and prints the report. The
+// fsck route does not perform any repairs. Repairs can only be performed on
+// politeiad startup using the --fsckrepair flag.
+func fsck() error {
+	// Load server identity
+	pid, err := identity.LoadPublicIdentity(*identityFilename)
+	if err != nil {
+		return err
+	}
+
+	// Setup client
+	c, err := newClient(pid)
+	if err != nil {
+		return err
+	}
+
+	// Perform fsck
+	fr, err := c.Fsck(context.Background())
+	if err != nil {
+		return err
+	}
+
+	// Print report
+	fmt.Printf("Records: %v\n", fr.Records)
+	fmt.Printf("Blobs  : %v\n", fr.Blobs)
+	fmt.Printf("Issues : %v\n", len(fr.Issues))
+	for _, v := range fr.Issues {
+		fmt.Printf("  %v", v.Type)
+		if v.Token != "" {
+			fmt.Printf(" %v", v.Token)
+		}
+		fmt.Printf(": %v\n", v.Description)
+	}
+
+	return nil
+}
+
+// printEncryptionKeys prints the encryption key status.
+func printEncryptionKeys(k v2.EncryptionKeys) {
+	fmt.Printf("Active key  : %v\n", k.ActiveKeyID)
+	fmt.Printf("Keys        : %v\n", k.KeyIDs)
+	fmt.Printf("Reencrypting: %v\n", k.Reencrypting)
+	fmt.Printf("Reencrypted : %v\n", k.Reencrypted)
+}
+
+// keyRotate rotates the key that is used to encrypt unvetted data at rest.
+// The existing data is re-encrypted by politeiad in the background. Prior
+// keys are retired once the re-encryption is complete. The optional key file
+// is the path of the new key file on the politeiad host.
+func keyRotate() error {
+	flags := flag.Args()[1:] // Chop off action.
+
+	// Unpack args
+	if len(flags) > 1 {
+		return fmt.Errorf("invalid number of arguments (%v); you can "+
+			"provide an optional key file", len(flags))
+	}
+	var keyFile string
+	if len(flags) == 1 {
+		keyFile = flags[0]
+	}
+
+	// Load server identity
+	pid, err := identity.LoadPublicIdentity(*identityFilename)
+	if err != nil {
+		return err
+	}
+
+	// Setup client
+	c, err := newClient(pid)
+	if err != nil {
+		return err
+	}
+
+	// Rotate key
+	k, err := c.EncryptionKeyRotate(context.Background(), keyFile)
+	if err != nil {
+		return err
+	}
+
+	printEncryptionKeys(*k)
+
+	return nil
+}
+
+// keyStatus prints the status of the keys that are used to encrypt unvetted
+// data at rest.
+func keyStatus() error {
+	// Load server identity
+	pid, err := identity.LoadPublicIdentity(*identityFilename)
+	if err != nil {
+		return err
+	}
+
+	// Setup client
+	c, err := newClient(pid)
+	if err != nil {
+		return err
+	}
+
+	// Get key status
+	k, err := c.EncryptionKeyStatus(context.Background())
+	if err != nil {
+		return err
+	}
+
+	printEncryptionKeys(*k)
+
+	return nil
+}
+
+// anchorTime returns a human readable anchor status timestamp. A zero
+// timestamp is returned as a dash.
+func anchorTime(t int64) string {
+	if t == 0 {
+		return "-"
+	}
+	return time.Unix(t, 0).UTC().String()
+}
+
+// printAnchors prints the anchor status.
+func printAnchors(a v2.Anchors) {
+	fmt.Printf("Anchor type: %v\n", a.Type)
+	fmt.Printf("Schedule   : %v\n", a.Schedule)
+	fmt.Printf("Next drop  : %v\n", anchorTime(a.NextDrop))
+	fmt.Printf("Dropping   : %v\n", a.Dropping)
+	fmt.Printf("Last run   : %v\n", anchorTime(a.LastRun))
+	if a.LastError != "" {
+		fmt.Printf("Last error : %v\n", a.LastError)
+	}
+
+	fmt.Printf("Pending trees: %v\n", len(a.Pending))
+	for _, v := range a.Pending {
+		fmt.Printf("  %v  %v unanchored leaves, last anchor at size %v: %v\n",
+			v.Token, v.Leaves, v.AnchoredSize, anchorTime(v.Anchored))
+	}
+
+	fmt.Printf("Anchor drops: %v\n", len(a.Drops))
+	for _, v := range a.Drops {
+		fmt.Printf("  Status     : %v\n", v.Status)
+		fmt.Printf("  Trees      : %v/%v anchored\n", v.Anchored, v.Trees)
+		fmt.Printf("  Started    : %v\n", anchorTime(v.Started))
+		fmt.Printf("  Attempts   : %v/%v, last check %v\n",
+			v.Attempts, v.MaxAttempts, anchorTime(v.LastCheck))
+		fmt.Printf("  Completed  : %v\n", anchorTime(v.Completed))
+		for _, tx := range v.TxIDs {
+			fmt.Printf("  Tx         : %v\n", tx)
+		}
+		for _, mr := range v.MerkleRoots {
+			fmt.Printf("  Merkle root: %v\n", mr)
+		}
+		if v.Error != "" {
+			fmt.Printf("  Error      : %v\n", v.Error)
+		}
+		fmt.Printf("\n")
+	}
+}
+
+// printBlobCache prints the blob cache stats.
+func printBlobCache(c v2.BlobCache) {
+	if !c.Enabled {
+		fmt.Printf("Blob cache disabled\n")
+		return
+	}
+	var hitRate float64
+	if c.Hits+c.Misses > 0 {
+		hitRate = float64(c.Hits) / float64(c.Hits+c.Misses) * 100
+	}
+	fmt.Printf("Hits     : %v\n", c.Hits)
+	fmt.Printf("Misses   : %v\n", c.Misses)
+	fmt.Printf("Hit rate : %.1f%%\n", hitRate)
+	fmt.Printf("Evictions: %v\n", c.Evictions)
+	fmt.Printf("Entries  : %v\n", c.Entries)
+	fmt.Printf("Size     : %v/%v bytes\n", c.Size, c.MaxSize)
+}
+
+// blobCacheStats prints the hit and miss stats of the politeiad blob cache.
+func blobCacheStats() error {
+	// Load server identity
+	pid, err := identity.LoadPublicIdentity(*identityFilename)
+	if err != nil {
+		return err
+	}
+
+	// Setup client
+	c, err := newClient(pid)
+	if err != nil {
+		return err
+	}
+
+	// Get blob cache stats
+	bc, err := c.BlobCacheStats(context.Background())
+	if err != nil {
+		return err
+	}
+
+	printBlobCache(*bc)
+
+	return nil
+}
+
+// anchorStatus prints the status of the anchoring of the politeiad data,
+// including the trees that have unanchored leaves and the most recent anchor
+// drops.
+func anchorStatus() error {
+	// Load server identity
+	pid, err := identity.LoadPublicIdentity(*identityFilename)
+	if err != nil {
+		return err
+	}
+
+	// Setup client
+	c, err := newClient(pid)
+	if err != nil {
+		return err
+	}
+
+	// Get anchor status
+	a, err := c.AnchorStatus(context.Background())
+	if err != nil {
+		return err
+	}
+
+	printAnchors(*a)
+
+	return nil
+}
+
+// anchorDrop drops an anchor for all politeiad data that has not been
+// anchored yet without waiting for the anchor schedule. The anchor is dropped
+// by politeiad in the background.
+func anchorDrop() error {
+	// Load server identity
+	pid, err := identity.LoadPublicIdentity(*identityFilename)
+	if err != nil {
+		return err
+	}
+
+	// Setup client
+	c, err := newClient(pid)
+	if err != nil {
+		return err
+	}
+
+	// Drop anchor
+	a, err := c.AnchorDrop(context.Background())
+	if err != nil {
+		return err
+	}
+
+	printAnchors(*a)
+
+	return nil
+}
+
+// recordExport exports the full contents of a record, including all plugin
+// data and timestamps, and saves the record bundle to the provided file.
+func recordExport() error {
+	flags := flag.Args()[1:] // Chop off action.
+
+	if len(flags) != 2 {
+		return fmt.Errorf("must provide a token and a file path")
+	}
+	token := flags[0]
+	_, err := util.TokenDecodeAnyLength(util.TokenTypeTstore, token)
+	if err != nil {
+		return err
+	}
+	fp := util.CleanAndExpandPath(flags[1])
+
+	// Load server identity
+	pid, err := identity.LoadPublicIdentity(*identityFilename)
+	if err != nil {
+		return err
+	}
+
+	// Setup client
+	c, err := newClient(pid)
+	if err != nil {
+		return err
+	}
+
+	// Export record
+	b, err := c.RecordExport(context.Background(), token)
+	if err != nil {
+		return err
+	}
+
+	// Verify the censorship record of every record version
+	for _, v := range b.Records {
+		err = pdclient.RecordVerify(v, b.ServerPublicKey)
+		if err != nil {
+			return fmt.Errorf("version %v: %v", v.Version, err)
+		}
+	}
+
+	// Save bundle
+	j, err := json.Marshal(b)
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(fp, j, 0600)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Token    : %v\n", b.Token)
+	fmt.Printf("Versions : %v\n", len(b.Records))
+	fmt.Printf("Blobs    : %v\n", len(b.Blobs))
+	fmt.Printf("Bundle   : %v\n", fp)
+
+	return nil
+}
+
+// recordImport imports a record bundle from the provided file. The imported
+// record is assigned a new token.
+func recordImport() error {
+	flags := flag.Args()[1:] // Chop off action.
+
+	if len(flags) != 1 {
+		return fmt.Errorf("must provide a file path")
+	}
+	j, err := ioutil.ReadFile(util.CleanAndExpandPath(flags[0]))
+	if err != nil {
+		return err
+	}
+	var b v2.RecordBundle
+	err = json.Unmarshal(j, &b)
+	if err != nil {
+		return fmt.Errorf("decode bundle: %v", err)
+	}
+
+	// Load server identity
+	pid, err := identity.LoadPublicIdentity(*identityFilename)
+	if err != nil {
+		return err
+	}
+
+	// Setup client
+	c, err := newClient(pid)
+	if err != nil {
+		return err
+	}
+
+	// Import record
+	token, err := c.RecordImport(context.Background(), b)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Bundle token: %v\n", b.Token)
+	fmt.Printf("New token   : %v\n", token)
+
+	return nil
+}
+
+// printPlugin prints the settings of a plugin.
+func printPlugin(p v2.Plugin) {
+	fmt.Printf("Plugin: %v\n", p.ID)
+	for _, v := range p.Settings {
+		fmt.Printf("  %v: %v\n", v.Key, v.Value)
+	}
+}
+
+// pluginSettings prints the settings of a plugin and the history of the
+// runtime updates that were made to the plugin settings.
+func pluginSettings() error {
+	flags := flag.Args()[1:] // Chop off action.
+
+	if len(flags) != 1 {
+		return fmt.Errorf("must provide a plugin ID")
+	}
+
+	// Load server identity
+	pid, err := identity.LoadPublicIdentity(*identityFilename)
+	if err != nil {
+		return err
+	}
+
+	// Setup client
+	c, err := newClient(pid)
+	if err != nil {
+		return err
+	}
+
+	// Get plugin settings
+	psr, err := c.PluginSettings(context.Background(), flags[0])
+	if err != nil {
+		return err
+	}
+
+	printPlugin(psr.Plugin)
+	fmt.Printf("Updates: %v\n", len(psr.History))
+	for _, v := range psr.History {
+		fmt.Printf("  %v  %v  %v\n", anchorTime(v.Timestamp), v.User,
+			v.Reason)
+		previous := make(map[string]string, len(v.Previous))
+		for _, p := range v.Previous {
+			previous[p.Key] = p.Value
+		}
+		for _, s := range v.Settings {
+			fmt.Printf("    %v: %v -> %v\n", s.Key, previous[s.Key], s.Value)
+		}
+	}
+
+	return nil
+}
+
+// pluginSettingsUpdate updates the settings of a plugin at runtime. Only the
+// provided settings are updated.
+func pluginSettingsUpdate() error {
+	flags := flag.Args()[1:] // Chop off action.
+
+	if len(flags) < 2 {
+		return fmt.Errorf("must provide a plugin ID and at least one setting")
+	}
+	var (
+		pluginID = flags[0]
+		settings = make([]v2.PluginSetting, 0, len(flags)-1)
+		reason   string
+	)
+	for _, v := range flags[1:] {
+		if strings.HasPrefix(v, "reason:") {
+			reason = strings.TrimPrefix(v, "reason:")
+			continue
+		}
+		kv := strings.SplitN(v, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return fmt.Errorf("invalid setting '%v'; format should be "+
+				"'key=value'", v)
+		}
+		settings = append(settings, v2.PluginSetting{
+			Key:   kv[0],
+			Value: kv[1],
+		})
+	}
+	if len(settings) == 0 {
+		return fmt.Errorf("must provide at least one setting")
+	}
+
+	// Load server identity
+	pid, err := identity.LoadPublicIdentity(*identityFilename)
+	if err != nil {
+		return err
+	}
+
+	// Setup client
+	c, err := newClient(pid)
+	if err != nil {
+		return err
+	}
+
+	// Update plugin settings
+	p, err := c.PluginSettingsUpdate(context.Background(),
+		pluginID, settings, reason)
+	if err != nil {
+		return err
+	}
+
+	printPlugin(*p)
+
+	return nil
+}
+
+// backup saves a point-in-time backup of the politeiad backend. The backup is
+// saved to the politeiad backup directory on the politeiad server.
+func backup() error {
+	// Load server identity
+	pid, err := identity.LoadPublicIdentity(*identityFilename)
+	if err != nil {
+		return err
+	}
+
+	// Setup client
+	c, err := newClient(pid)
+	if err != nil {
+		return err
+	}
+
+	// Make backup
+	br, err := c.Backup(context.Background())
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Backup   : %v\n", br.Name)
+	fmt.Printf("Timestamp: %v\n", time.Unix(br.Timestamp, 0).UTC())
+	fmt.Printf("Trees    : %v\n", br.Trees)
+	fmt.Printf("Blobs    : %v\n", br.Blobs)
+	fmt.Printf("Files    : %v\n", br.Files)
+
+	return nil
+}
+
+func printCacheRebuild(s v2.CacheRebuildState) {
+	fmt.Printf("In progress: %v\n", s.InProgress)
+	fmt.Printf("Started    : %v\n", anchorTime(s.Started))
+	fmt.Printf("Finished   : %v\n", anchorTime(s.Finished))
+	for _, v := range s.Plugins {
+		status := "pending"
+		switch {
+		case v.Error != "":
+			status = "failed: " + v.Error
+		case v.Done:
+			status = "done"
+		case v.Records > 0:
+			status = "rebuilding"
+		}
+		fmt.Printf("  %-12v %v/%v records, %v\n",
+			v.PluginID, v.Rebuilt, v.Records, status)
+	}
+}
+
+// cacheRebuild rebuilds the caches of the provided plugins. The caches of all
+// plugins are rebuilt if no plugin IDs are provided. politeiad rebuilds the
+// caches in the background. The rebuild progress is polled and printed until
+// the rebuild has finished.
+func cacheRebuild() error {
+	pluginIDs := flag.Args()[1:] // Chop off action.
+
+	// Load server identity
+	pid, err := identity.LoadPublicIdentity(*identityFilename)
+	if err != nil {
+		return err
+	}
+
+	// Setup client
+	c, err := newClient(pid)
+	if err != nil {
+		return err
+	}
+
+	// Start the rebuild
+	s, err := c.CacheRebuild(context.Background(), pluginIDs)
+	if err != nil {
+		return err
+	}
+
+	// Wait for the rebuild to finish
+	for s.InProgress {
+		for _, v := range s.Plugins {
+			if v.Done || v.Records == 0 {
+				continue
+			}
+			fmt.Printf("Rebuilding %v: %v/%v records\n",
+				v.PluginID, v.Rebuilt, v.Records)
+		}
+		time.Sleep(2 * time.Second)
+
+		s, err = c.CacheRebuildStatus(context.Background())
+		if err != nil {
+			return err
+		}
+	}
+
+	printCacheRebuild(*s)
+
+	return nil
+}
+
+// cacheRebuildStatus prints the status of the most recent plugin cache
+// rebuild.
+func cacheRebuildStatus() error {
+	// Load server identity
+	pid, err := identity.LoadPublicIdentity(*identityFilename)
+	if err != nil {
+		return err
+	}
+
+	// Setup client
+	c, err := newClient(pid)
+	if err != nil {
+		return err
+	}
+
+	// Get the rebuild status
+	s, err := c.CacheRebuildStatus(context.Background())
+	if err != nil {
+		return err
+	}
+
+	printCacheRebuild(*s)
+
+	return nil
+}
+
 func _main() error {
 	flag.Usage = usage
 	flag.Parse()
@@ -865,8 +1679,38 @@ func _main() error {
 				return recordSetStatus()
 			case "record":
 				return record()
+			case "diff":
+				return recordDiff()
 			case "inventory":
 				return recordInventory()
+			case "invquery":
+				return inventoryQuery()
+			case "fsck":
+				return fsck()
+			case "keyrotate":
+				return keyRotate()
+			case "keystatus":
+				return keyStatus()
+			case "blobcachestats":
+				return blobCacheStats()
+			case "anchorstatus":
+				return anchorStatus()
+			case "anchordrop":
+				return anchorDrop()
+			case "export":
+				return recordExport()
+			case "import":
+				return recordImport()
+			case "pluginsettings":
+				return pluginSettings()
+			case "pluginsettingsupdate":
+				return pluginSettingsUpdate()
+			case "backup":
+				return backup()
+			case "cacherebuild":
+				return cacheRebuild()
+			case "cacherebuildstatus":
+				return cacheRebuildStatus()
 			default:
 				return fmt.Errorf("invalid action: %v", a)
 			}
@@ -31,9 +31,12 @@ const (
 	defaultDataDirname      = sharedconfig.DefaultDataDirname
 	defaultLogLevel         = "info"
 	defaultLogDirname       = "logs"
+	defaultBackupDirname    = "backups"
 	defaultLogFilename      = "politeiad.log"
 	defaultIdentityFilename = "identity.json"
 
+	defaultIdentityHistoryFilename = "identityhistory.json"
+
 	defaultMainnetPort = "49374"
 	defaultTestnetPort = "59374"
 
@@ -46,9 +49,12 @@ const (
 	defaultBackend = backendTstore
 
 	// Tstore default settings
-	defaultDBType   = tstore.DBTypeLevelDB
-	defaultDBHost   = "localhost:3306" // MySQL default host
-	defaultTlogHost = "localhost:8090"
+	defaultDBType         = tstore.DBTypeLevelDB
+	defaultDBHost         = "localhost:3306" // MySQL default host
+	defaultPostgresDBHost = "localhost:5432" // PostgreSQL default host
+	defaultTlogType       = tstore.TlogTypeTrillian
+	defaultTlogHost       = "localhost:8090"
+	defaultAnchor         = tstore.AnchorTypeDcrtime
 
 	// Environment variables
 	envDBPass   = "DBPASS"
@@ -62,6 +68,7 @@ var (
 	defaultHTTPSKeyFile  = filepath.Join(defaultHomeDir, "https.key")
 	defaultHTTPSCertFile = filepath.Join(defaultHomeDir, "https.cert")
 	defaultLogDir        = filepath.Join(defaultHomeDir, defaultLogDirname)
+	defaultBackupDir     = filepath.Join(defaultHomeDir, defaultBackupDirname)
 	defaultIdentityFile  = filepath.Join(defaultHomeDir, defaultIdentityFilename)
 )
 
@@ -85,6 +92,7 @@ type config struct {
 	MemProfile  string   `long:"memprofile" description:"Write mem profile to the specified file"`
 	DebugLevel  string   `short:"d" long:"debuglevel" description:"Logging level for all subsystems {trace, debug, info, warn, error, critical} -- You may also specify <subsystem>=<level>,<subsystem2>=<level>,... to set the log level for individual subsystems -- Use show to list available subsystems"`
 	Listeners   []string `long:"listen" description:"Add an interface/port to listen for connections (default all interfaces port: 49152, testnet: 59152)"`
+	GRPCListen  []string `long:"grpclisten" description:"Add an interface:port to listen for gRPC connections (tstore backend only; disabled by default)"`
 	Version     string
 	HTTPSCert   string `long:"httpscert" description:"File containing the https certificate file"`
 	HTTPSKey    string `long:"httpskey" description:"File containing the https certificate key"`
@@ -95,16 +103,38 @@ type config struct {
 	Identity    string `long:"identity" description:"File containing the politeiad identity file"`
 	Backend     string `long:"backend" description:"Backend type"`
 
+	// RPC authentication options
+	RPCCredentials       []string `long:"rpccredential" description:"Add a named RPC credential with limited access -- Format: user:pass:scope[,scope]... -- Scopes: read, write, status, admin, plugin:<pluginid>, plugin:*"`
+	RPCClientCA          string   `long:"rpcclientca" description:"File containing the CA certificate that client certificates are verified against; enables client certificate authentication"`
+	RPCClientCerts       []string `long:"rpcclientcert" description:"Add a client certificate credential with limited access -- Format: commonname:scope[,scope]..."`
+	RPCRequireClientCert bool     `long:"rpcrequireclientcert" description:"Reject connections that do not provide a client certificate that was signed by the client CA"`
+
+	// Identity options
+	IdentityHistory string `long:"identityhistory" description:"File containing the politeiad identity key history"`
+	IdentityRotate  bool   `long:"identityrotate" description:"Replace the politeiad identity with a new identity on startup; the current identity signs a handover to the new identity"`
+
 	// Git backend options
 	GitTrace    bool   `long:"gittrace" description:"Enable git tracing in logs"`
 	DcrdataHost string `long:"dcrdatahost" description:"Dcrdata ip:port"`
 
 	// Tstore backend options
-	DBType   string `long:"dbtype" description:"Database type"`
-	DBHost   string `long:"dbhost" description:"Database ip:port"`
-	DBPass   string // Provided in env variable "DBPASS"
-	TlogHost string `long:"tloghost" description:"Trillian log ip:port"`
-	TlogPass string // Provided in env variable "TLOGPASS"
+	DBType        string `long:"dbtype" description:"Database type"`
+	DBHost        string `long:"dbhost" description:"Database ip:port"`
+	DBPass        string // Provided in env variable "DBPASS"
+	TlogType      string `long:"tlogtype" description:"Tlog type (trillian or native)"`
+	TlogHost      string `long:"tloghost" description:"Trillian log ip:port"`
+	TlogPass      string // Provided in env variable "TLOGPASS"
+	Fsck          bool   `long:"fsck" description:"Perform a filesystem check of the backend on startup"`
+	FsckRepair    bool   `long:"fsckrepair" description:"Perform a filesystem check of the backend on startup and repair any issues that are found"`
+	BlobCacheSize int64  `long:"blobcachesize" description:"Size in MiB of the in-memory cache for vetted blobs; 0 disables the cache"`
+	BackupDir     string `long:"backupdir" description:"Directory that backups are saved to"`
+	BackupHook    string `long:"backuphook" description:"Command that is run while writes are frozen during a backup; the backup directory is provided as the only argument"`
+	Restore       string `long:"restore" description:"Restore the backend from the provided backup directory on startup"`
+	RebuildCaches bool   `long:"rebuildcaches" description:"Rebuild all plugin caches from the backend data on startup"`
+
+	// Anchor options
+	Anchor         string `long:"anchor" description:"Timestamp anchoring provider (dcrtime or local)"`
+	AnchorSchedule string `long:"anchorschedule" description:"Cron schedule of the anchor drops (Seconds Minutes Hours Days Months DayOfWeek)"`
 
 	// Plugin options
 	Plugins        []string `long:"plugin" description:"Plugins"`
@@ -254,13 +284,18 @@ func loadConfig() (*config, []string, error) {
 		DebugLevel: defaultLogLevel,
 		DataDir:    defaultDataDir,
 		LogDir:     defaultLogDir,
+		BackupDir:  defaultBackupDir,
 		HTTPSKey:   defaultHTTPSKeyFile,
 		HTTPSCert:  defaultHTTPSCertFile,
 		Version:    version.String(),
 		Backend:    defaultBackend,
 		DBType:     defaultDBType,
 		DBHost:     defaultDBHost,
+		TlogType:   defaultTlogType,
 		TlogHost:   defaultTlogHost,
+
+		Anchor:         defaultAnchor,
+		AnchorSchedule: tstore.AnchorScheduleDefault,
 	}
 
 	// Service options which are only added on Windows.
@@ -334,6 +369,11 @@ func loadConfig() (*config, []string, error) {
 		} else {
 			cfg.LogDir = preCfg.LogDir
 		}
+		if preCfg.BackupDir == defaultBackupDir {
+			cfg.BackupDir = filepath.Join(cfg.HomeDir, defaultBackupDirname)
+		} else {
+			cfg.BackupDir = preCfg.BackupDir
+		}
 	}
 
 	// Load additional config from file.
@@ -424,6 +464,14 @@ func loadConfig() (*config, []string, error) {
 	cfg.LogDir = util.CleanAndExpandPath(cfg.LogDir)
 	cfg.LogDir = filepath.Join(cfg.LogDir, netName(activeNetParams))
 
+	// Append the network type to the backup directory so it is
+	// "namespaced" per network in the same fashion as the data directory.
+	cfg.BackupDir = util.CleanAndExpandPath(cfg.BackupDir)
+	cfg.BackupDir = filepath.Join(cfg.BackupDir, netName(activeNetParams))
+	if cfg.Restore != "" {
+		cfg.Restore = util.CleanAndExpandPath(cfg.Restore)
+	}
+
 	cfg.HTTPSKey = util.CleanAndExpandPath(cfg.HTTPSKey)
 	cfg.HTTPSCert = util.CleanAndExpandPath(cfg.HTTPSCert)
 
@@ -518,6 +566,11 @@ func loadConfig() (*config, []string, error) {
 		cfg.Identity = defaultIdentityFile
 	}
 	cfg.Identity = util.CleanAndExpandPath(cfg.Identity)
+	if cfg.IdentityHistory == "" {
+		cfg.IdentityHistory = filepath.Join(filepath.Dir(cfg.Identity),
+			defaultIdentityHistoryFilename)
+	}
+	cfg.IdentityHistory = util.CleanAndExpandPath(cfg.IdentityHistory)
 
 	// Set random username and password when not specified
 	if cfg.RPCUser == "" {
@@ -537,6 +590,20 @@ func loadConfig() (*config, []string, error) {
 		log.Warnf("RPC password not set, using random value")
 	}
 
+	// Verify client certificate authentication settings
+	if cfg.RPCClientCA != "" {
+		cfg.RPCClientCA = util.CleanAndExpandPath(cfg.RPCClientCA)
+		if !util.FileExists(cfg.RPCClientCA) {
+			return nil, nil, fmt.Errorf("rpcclientca not found: %v",
+				cfg.RPCClientCA)
+		}
+	}
+	if cfg.RPCClientCA == "" &&
+		(len(cfg.RPCClientCerts) > 0 || cfg.RPCRequireClientCert) {
+		return nil, nil, fmt.Errorf("rpcclientcert and rpcrequireclientcert " +
+			"require rpcclientca")
+	}
+
 	// Verify backend type
 	switch cfg.Backend {
 	case backendGit, backendTstore:
@@ -545,11 +612,23 @@ func loadConfig() (*config, []string, error) {
 		return nil, nil, fmt.Errorf("invalid backend type '%v'", cfg.Backend)
 	}
 
+	// Verify gRPC listeners. The gRPC service is only available when
+	// using the tstore backend and is disabled by default.
+	if len(cfg.GRPCListen) > 0 && cfg.Backend != backendTstore {
+		return nil, nil, fmt.Errorf("grpclisten requires the %v backend",
+			backendTstore)
+	}
+	for _, v := range cfg.GRPCListen {
+		if _, _, err := net.SplitHostPort(v); err != nil {
+			return nil, nil, fmt.Errorf("invalid grpclisten '%v': %v", v, err)
+		}
+	}
+
 	// Verify tstore backend database choice
 	switch cfg.DBType {
 	case tstore.DBTypeLevelDB:
 		// Allowed; continue
-	case tstore.DBTypeMySQL:
+	case tstore.DBTypeMySQL, tstore.DBTypePostgres:
 		// The database password is provided in an env variable
 		cfg.DBPass = os.Getenv(envDBPass)
 		if cfg.DBPass == "" {
@@ -557,13 +636,50 @@ func loadConfig() (*config, []string, error) {
 				"the database password for the politeiad user in the env " +
 				"variable DBPASS")
 		}
+		// Use the PostgreSQL default host if a host was not provided
+		if cfg.DBType == tstore.DBTypePostgres && cfg.DBHost == defaultDBHost {
+			cfg.DBHost = defaultPostgresDBHost
+		}
 	}
 
-	// Verify tlog options
-	_, err = url.Parse(cfg.TlogHost)
-	if err != nil {
-		return nil, nil, fmt.Errorf("invalid tlog host '%v': %v",
-			cfg.TlogHost, err)
+	// Verify anchor provider. The anchor schedule is verified when
+	// the anchor job is added to the tstore cron.
+	switch cfg.Anchor {
+	case tstore.AnchorTypeDcrtime, tstore.AnchorTypeLocal:
+		// Allowed; continue
+	default:
+		return nil, nil, fmt.Errorf("invalid anchor type '%v'", cfg.Anchor)
+	}
+
+	// Verify blob cache size
+	if cfg.BlobCacheSize < 0 {
+		return nil, nil, fmt.Errorf("invalid blob cache size %v; "+
+			"must be >= 0", cfg.BlobCacheSize)
+	}
+
+	// A restore implies a fsck repair. The inventory and any derived
+	// data are rebuilt from the restored records.
+	if cfg.Restore != "" {
+		cfg.FsckRepair = true
+	}
+
+	// A fsck repair implies a fsck
+	if cfg.FsckRepair {
+		cfg.Fsck = true
+	}
+
+	// Verify tlog options. The tlog host is only used by trillian.
+	switch cfg.TlogType {
+	case tstore.TlogTypeTrillian:
+		_, err = url.Parse(cfg.TlogHost)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid tlog host '%v': %v",
+				cfg.TlogHost, err)
+		}
+	case tstore.TlogTypeNative:
+		// Allowed; continue
+	default:
+		return nil, nil, fmt.Errorf("invalid tlog type '%v'", cfg.TlogType)
 	}
 	cfg.TlogPass = os.Getenv(envTlogPass)
 	if cfg.TlogPass == "" {
@@ -0,0 +1,158 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+	"net/http"
+	"time"
+
+	v2 "github.com/decred/politeia/politeiad/api/v2"
+	"github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/util"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// eventsPingInterval is the interval at which pings are sent to an
+	// event stream client so that idle connections are kept alive and
+	// dead connections are detected.
+	eventsPingInterval = 30 * time.Second
+
+	// eventsWriteTimeout is the maximum amount of time that a write to
+	// an event stream client is allowed to take.
+	eventsWriteTimeout = 10 * time.Second
+)
+
+var (
+	// eventsUpgrader upgrades an events request to a websocket
+	// connection.
+	eventsUpgrader = websocket.Upgrader{}
+)
+
+func (p *politeia) handleEvents(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleEvents")
+
+	// Decode request
+	q := r.URL.Query()
+	challenge, err := hex.DecodeString(q.Get(v2.EventsQueryChallenge))
+	if err != nil || len(challenge) != v2.ChallengeSize {
+		respondWithErrorV2(w, r, "handleEvents: decode challenge",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeChallengeInvalid,
+			})
+		return
+	}
+
+	// Subscribe to the backend event stream. This is done prior to
+	// upgrading the connection so that an invalid resume token is
+	// returned as a regular error reply.
+	ch, err := p.backendv2.EventsSubscribe(q.Get(v2.EventsQueryResume))
+	if err != nil {
+		respondWithErrorV2(w, r,
+			"handleEvents: EventsSubscribe: %v", err)
+		return
+	}
+	defer p.backendv2.EventsUnsubscribe(ch)
+
+	// Upgrade the connection. The upgrader replies with an http error
+	// if the upgrade fails.
+	conn, err := eventsUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		log.Errorf("handleEvents: Upgrade: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	// Send reply
+	response := p.identity.SignMessage(challenge)
+	conn.SetWriteDeadline(time.Now().Add(eventsWriteTimeout))
+	err = conn.WriteJSON(v2.EventsReply{
+		Response: hex.EncodeToString(response[:]),
+	})
+	if err != nil {
+		log.Debugf("handleEvents: WriteJSON: %v", err)
+		return
+	}
+
+	log.Infof("%v Event stream opened", util.RemoteAddr(r))
+
+	eventsStream(conn, ch)
+
+	log.Infof("%v Event stream closed", util.RemoteAddr(r))
+}
+
+// eventsStream sends the backend events to the websocket client until the
+// client disconnects or the subscription is closed.
+func eventsStream(conn *websocket.Conn, ch <-chan backendv2.Event) {
+	// The client does not send any messages. The connection is read
+	// so that the control messages are processed and so that a client
+	// disconnect is detected.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ping := time.NewTicker(eventsPingInterval)
+	defer ping.Stop()
+
+	for {
+		select {
+		case e, ok := <-ch:
+			if !ok {
+				// The subscription was closed because the client fell
+				// behind or because politeiad is shutting down. The
+				// client should reconnect using the last event ID.
+				msg := websocket.FormatCloseMessage(websocket.CloseTryAgainLater,
+					"subscription closed")
+				conn.WriteControl(websocket.CloseMessage, msg,
+					time.Now().Add(eventsWriteTimeout))
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(eventsWriteTimeout))
+			err := conn.WriteJSON(convertEventToV2(e))
+			if err != nil {
+				log.Debugf("eventsStream: WriteJSON: %v", err)
+				return
+			}
+
+		case <-ping.C:
+			err := conn.WriteControl(websocket.PingMessage, nil,
+				time.Now().Add(eventsWriteTimeout))
+			if err != nil {
+				log.Debugf("eventsStream: ping: %v", err)
+				return
+			}
+
+		case <-done:
+			return
+		}
+	}
+}
+
+func convertEventToV2(e backendv2.Event) v2.Event {
+	ev := v2.Event{
+		ID:        e.ID,
+		Type:      v2.EventTypeT(e.Type),
+		Timestamp: e.Timestamp,
+		Token:     e.Token,
+		PluginID:  e.PluginID,
+		Name:      e.Name,
+		Payload:   e.Payload,
+		Reply:     e.Reply,
+	}
+	if e.RecordMetadata != nil {
+		ev.State = v2.RecordStateT(e.RecordMetadata.State)
+		ev.Status = v2.RecordStatusT(e.RecordMetadata.Status)
+		ev.Version = e.RecordMetadata.Version
+		ev.Metadata = convertMetadataStreamsToV2(e.Metadata)
+	}
+	return ev
+}
@@ -0,0 +1,853 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	grpcv2 "github.com/decred/politeia/politeiad/api/grpc/v2"
+	v2 "github.com/decred/politeia/politeiad/api/v2"
+	"github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/util"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+)
+
+// grpcServer implements the politeiad v2 gRPC service. It mirrors the v2 JSON
+// API routes that are used to submit and retrieve records.
+type grpcServer struct {
+	p *politeia
+}
+
+// grpcPermissions contains the permission that is required to call each of
+// the gRPC service methods.
+var grpcPermissions = map[string]permission{
+	grpcv2.FullMethod(grpcv2.MethodRecordNew):        permissionRecordWrite,
+	grpcv2.FullMethod(grpcv2.MethodRecordEdit):       permissionRecordWrite,
+	grpcv2.FullMethod(grpcv2.MethodRecords):          permissionRead,
+	grpcv2.FullMethod(grpcv2.MethodRecordTimestamps): permissionRead,
+	grpcv2.FullMethod(grpcv2.MethodInventory):        permissionRead,
+	grpcv2.FullMethod(grpcv2.MethodPluginWrite):      permissionPluginWrite,
+	grpcv2.FullMethod(grpcv2.MethodPluginReads):      permissionRead,
+
+	// The server reflection service allows clients such as grpcurl to
+	// discover the politeiad service definition.
+	"/grpc.reflection.v1alpha.ServerReflection/ServerReflectionInfo": permissionRead,
+}
+
+// setupGRPC returns a gRPC server that serves the politeiad v2 gRPC service.
+// The server uses the politeiad https certificate and the provided TLS config.
+// All calls require an RPC credential that has been granted the permission
+// for the called method.
+func (p *politeia) setupGRPC(tlsConfig *tls.Config) (*grpc.Server, error) {
+	cert, err := tls.LoadX509KeyPair(p.cfg.HTTPSCert, p.cfg.HTTPSKey)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.Certificates = []tls.Certificate{cert}
+	return p.newGRPCServer(grpc.Creds(credentials.NewTLS(tlsConfig))), nil
+}
+
+// newGRPCServer returns a gRPC server that has the politeiad v2 gRPC service
+// and the server reflection service registered and that verifies the RPC
+// credentials of all calls. The provided options are added to the server
+// options.
+func (p *politeia) newGRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts, grpc.UnaryInterceptor(p.grpcUnaryAuth),
+		grpc.StreamInterceptor(p.grpcStreamAuth))
+	s := grpc.NewServer(opts...)
+	grpcv2.RegisterPoliteiadServer(s, &grpcServer{p: p})
+	reflection.Register(s)
+	return s
+}
+
+// grpcRemoteAddr returns the remote address of the gRPC caller.
+func grpcRemoteAddr(ctx context.Context) string {
+	pr, ok := peer.FromContext(ctx)
+	if !ok {
+		return "unknown"
+	}
+	return pr.Addr.String()
+}
+
+// grpcAuth verifies the RPC credential of a gRPC call. The caller is
+// authenticated using either a verified client certificate or basic
+// authentication credentials that are provided in the call metadata. The
+// returned context contains the credential.
+func (p *politeia) grpcAuth(ctx context.Context, method string) (context.Context, error) {
+	var (
+		user, pass string
+		ok         bool
+		tlsState   *tls.ConnectionState
+	)
+	md, _ := metadata.FromIncomingContext(ctx)
+	if a := md.Get("authorization"); len(a) == 1 {
+		user, pass, ok = parseBasicAuth(a[0])
+	}
+	if pr, found := peer.FromContext(ctx); found {
+		if ti, isTLS := pr.AuthInfo.(credentials.TLSInfo); isTLS {
+			tlsState = &ti.State
+		}
+	}
+	c, ok := p.creds.authenticate(tlsState, user, pass, ok)
+	if !ok {
+		log.Infof("%v Unauthorized gRPC access for: %v",
+			grpcRemoteAddr(ctx), user)
+		return nil, status.Error(codes.Unauthenticated,
+			"invalid rpc credentials")
+	}
+	perm, found := grpcPermissions[method]
+	if !found {
+		perm = permissionAdmin
+	}
+	if !c.allowed(perm) {
+		log.Infof("%v Forbidden gRPC access for: %v %v",
+			grpcRemoteAddr(ctx), c.name, method)
+		return nil, status.Error(codes.PermissionDenied,
+			"rpc credential scope insufficient")
+	}
+	log.Infof("%v gRPC %v %v", grpcRemoteAddr(ctx), c.name, method)
+	return context.WithValue(ctx, credentialKey{}, c), nil
+}
+
+// grpcUnaryAuth is a gRPC interceptor that verifies the RPC credentials of
+// unary calls.
+func (p *politeia) grpcUnaryAuth(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := p.grpcAuth(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// grpcAuthStream wraps a grpc.ServerStream in order to provide handlers with
+// a context that contains the RPC credential of the caller.
+type grpcAuthStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context of the stream.
+//
+// This function satisfies the grpc.ServerStream interface.
+func (s *grpcAuthStream) Context() context.Context {
+	return s.ctx
+}
+
+// grpcStreamAuth is a gRPC interceptor that verifies the RPC credentials of
+// streaming calls.
+func (p *politeia) grpcStreamAuth(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := p.grpcAuth(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &grpcAuthStream{
+		ServerStream: ss,
+		ctx:          ctx,
+	})
+}
+
+// parseBasicAuth parses the value of a basic authentication header.
+func parseBasicAuth(auth string) (string, string, bool) {
+	const prefix = "Basic "
+	if !strings.HasPrefix(auth, prefix) {
+		return "", "", false
+	}
+	b, err := base64.StdEncoding.DecodeString(auth[len(prefix):])
+	if err != nil {
+		return "", "", false
+	}
+	s := strings.SplitN(string(b), ":", 2)
+	if len(s) != 2 {
+		return "", "", false
+	}
+	return s[0], s[1], true
+}
+
+// RecordNew creates a new record. The file payloads are received as chunks
+// following the RecordNew request.
+//
+// This function satisfies the grpcv2 PoliteiadServer interface.
+func (s *grpcServer) RecordNew(stream grpcv2.Politeiad_RecordNewServer) error {
+	log.Tracef("grpc RecordNew")
+
+	ctx := stream.Context()
+
+	// Receive request
+	m, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	if m.RecordNew == nil {
+		return grpcErrorV2(ctx, "RecordNew: recv",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeRequestPayloadInvalid,
+			})
+	}
+	rn := m.RecordNew
+	challenge, err := hex.DecodeString(rn.Challenge)
+	if err != nil || len(challenge) != v2.ChallengeSize {
+		return grpcErrorV2(ctx, "RecordNew: decode challenge",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeChallengeInvalid,
+			})
+	}
+	err = grpcChunksRecv(rn.Files, func() (*grpcv2.FileChunk, error) {
+		m, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		return m.Chunk, nil
+	})
+	if err != nil {
+		return grpcErrorV2(ctx, "RecordNew: recv chunks: %v", err)
+	}
+
+	// Create new record
+	var (
+		metadata = convertMetadataStreamsFromGRPC(rn.Metadata)
+		files    = convertFilesFromGRPC(rn.Files)
+	)
+	rc, err := s.p.backendv2.RecordNew(metadata, files)
+	if err != nil {
+		return grpcErrorV2(ctx, "RecordNew: RecordNew: %v", err)
+	}
+
+	// Send reply
+	response := s.p.identity.SignMessage(challenge)
+	rnr := grpcv2.RecordNewReply{
+		Response: hex.EncodeToString(response[:]),
+		Record:   convertRecordToGRPC(s.p.convertRecordToV2(*rc)),
+	}
+
+	log.Infof("%v Record created %v",
+		grpcRemoteAddr(ctx), rc.RecordMetadata.Token)
+
+	return stream.SendAndClose(&rnr)
+}
+
+// RecordEdit edits an existing record. The file payloads of the added files
+// are received as chunks following the RecordEdit request.
+//
+// This function satisfies the grpcv2 PoliteiadServer interface.
+func (s *grpcServer) RecordEdit(stream grpcv2.Politeiad_RecordEditServer) error {
+	log.Tracef("grpc RecordEdit")
+
+	ctx := stream.Context()
+
+	// Receive request
+	m, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	if m.RecordEdit == nil {
+		return grpcErrorV2(ctx, "RecordEdit: recv",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeRequestPayloadInvalid,
+			})
+	}
+	re := m.RecordEdit
+	challenge, err := hex.DecodeString(re.Challenge)
+	if err != nil || len(challenge) != v2.ChallengeSize {
+		return grpcErrorV2(ctx, "RecordEdit: decode challenge",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeChallengeInvalid,
+			})
+	}
+	token, err := decodeToken(re.Token)
+	if err != nil {
+		return grpcErrorV2(ctx, "RecordEdit: decode token",
+			v2.UserErrorReply{
+				ErrorCode:    v2.ErrorCodeTokenInvalid,
+				ErrorContext: util.TokenRegexp(),
+			})
+	}
+	err = grpcChunksRecv(re.FilesAdd, func() (*grpcv2.FileChunk, error) {
+		m, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		return m.Chunk, nil
+	})
+	if err != nil {
+		return grpcErrorV2(ctx, "RecordEdit: recv chunks: %v", err)
+	}
+
+	// Edit record
+	var (
+		mdAppend    = convertMetadataStreamsFromGRPC(re.MdAppend)
+		mdOverwrite = convertMetadataStreamsFromGRPC(re.MdOverwrite)
+		filesAdd    = convertFilesFromGRPC(re.FilesAdd)
+	)
+	rc, err := s.p.backendv2.RecordEdit(token, mdAppend,
+		mdOverwrite, filesAdd, re.FilesDel)
+	if err != nil {
+		return grpcErrorV2(ctx, "RecordEdit: RecordEdit: %v", err)
+	}
+
+	// Send reply
+	response := s.p.identity.SignMessage(challenge)
+	rer := grpcv2.RecordEditReply{
+		Response: hex.EncodeToString(response[:]),
+		Record:   convertRecordToGRPC(s.p.convertRecordToV2(*rc)),
+	}
+
+	log.Infof("%v Record edited %v",
+		grpcRemoteAddr(ctx), rc.RecordMetadata.Token)
+
+	return stream.SendAndClose(&rer)
+}
+
+// Records returns a batch of records. The challenge response is sent first.
+// Each record is then sent followed by the chunks of its file payloads.
+//
+// This function satisfies the grpcv2 PoliteiadServer interface.
+func (s *grpcServer) Records(r *grpcv2.Records, stream grpcv2.Politeiad_RecordsServer) error {
+	log.Tracef("grpc Records")
+
+	ctx := stream.Context()
+
+	// Verify request
+	challenge, err := hex.DecodeString(r.Challenge)
+	if err != nil || len(challenge) != v2.ChallengeSize {
+		return grpcErrorV2(ctx, "Records: decode challenge",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeChallengeInvalid,
+			})
+	}
+	if len(r.Requests) > int(v2.RecordsPageSize) {
+		return grpcErrorV2(ctx, "Records: page size",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodePageSizeExceeded,
+			})
+	}
+
+	// Get record batch
+	reqs := convertRecordRequestsFromGRPC(r.Requests)
+	brecords, err := s.p.backendv2.Records(reqs)
+	if err != nil {
+		return grpcErrorV2(ctx, "Records: Records: %v", err)
+	}
+
+	// Send the challenge response
+	response := s.p.identity.SignMessage(challenge)
+	err = stream.Send(&grpcv2.RecordsMsg{
+		Response: hex.EncodeToString(response[:]),
+	})
+	if err != nil {
+		return err
+	}
+
+	// Send the records
+	for token, br := range brecords {
+		rc := convertRecordToGRPC(s.p.convertRecordToV2(br))
+		err = stream.Send(&grpcv2.RecordsMsg{
+			Token:  token,
+			Record: rc,
+		})
+		if err != nil {
+			return err
+		}
+		for i, f := range br.Files {
+			payload, err := base64.StdEncoding.DecodeString(f.Payload)
+			if err != nil {
+				return grpcErrorV2(ctx, "Records: decode payload: %v", err)
+			}
+			err = grpcChunksSend(uint32(i), payload,
+				func(c *grpcv2.FileChunk) error {
+					return stream.Send(&grpcv2.RecordsMsg{
+						Chunk: c,
+					})
+				})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// RecordTimestamps returns the timestamps for a record.
+//
+// This function satisfies the grpcv2 PoliteiadServer interface.
+func (s *grpcServer) RecordTimestamps(ctx context.Context, r *grpcv2.RecordTimestamps) (*grpcv2.RecordTimestampsReply, error) {
+	log.Tracef("grpc RecordTimestamps")
+
+	// Verify request
+	challenge, err := hex.DecodeString(r.Challenge)
+	if err != nil || len(challenge) != v2.ChallengeSize {
+		return nil, grpcErrorV2(ctx, "RecordTimestamps: decode challenge",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeChallengeInvalid,
+			})
+	}
+	token, err := decodeTokenAnyLength(r.Token)
+	if err != nil {
+		return nil, grpcErrorV2(ctx, "RecordTimestamps: decode token",
+			v2.UserErrorReply{
+				ErrorCode:    v2.ErrorCodeTokenInvalid,
+				ErrorContext: util.TokenRegexp(),
+			})
+	}
+
+	// Get record timestamps
+	rt, err := s.p.backendv2.RecordTimestamps(token, r.Version)
+	if err != nil {
+		return nil, grpcErrorV2(ctx,
+			"RecordTimestamps: RecordTimestamps: %v", err)
+	}
+
+	// Prepare reply
+	response := s.p.identity.SignMessage(challenge)
+	files := make(map[string]*grpcv2.Timestamp, len(rt.Files))
+	for k, v := range convertFileTimestampsToV2(rt.Files) {
+		files[k] = convertTimestampToGRPC(v)
+	}
+	metadata := make(map[string]*grpcv2.StreamTimestamps, len(rt.Metadata))
+	for pluginID, streams := range convertMetadataTimestampsToV2(rt.Metadata) {
+		st := make(map[uint32]*grpcv2.Timestamp, len(streams))
+		for streamID, v := range streams {
+			st[streamID] = convertTimestampToGRPC(v)
+		}
+		metadata[pluginID] = &grpcv2.StreamTimestamps{
+			Streams: st,
+		}
+	}
+	return &grpcv2.RecordTimestampsReply{
+		Response: hex.EncodeToString(response[:]),
+		RecordMetadata: convertTimestampToGRPC(
+			convertTimestampToV2(rt.RecordMetadata)),
+		Metadata: metadata,
+		Files:    files,
+	}, nil
+}
+
+// Inventory returns the tokens of the records in the inventory, categorized
+// by record state and record status.
+//
+// This function satisfies the grpcv2 PoliteiadServer interface.
+func (s *grpcServer) Inventory(ctx context.Context, r *grpcv2.Inventory) (*grpcv2.InventoryReply, error) {
+	log.Tracef("grpc Inventory")
+
+	// Verify request
+	challenge, err := hex.DecodeString(r.Challenge)
+	if err != nil || len(challenge) != v2.ChallengeSize {
+		return nil, grpcErrorV2(ctx, "Inventory: decode challenge",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeChallengeInvalid,
+			})
+	}
+
+	// Verify inventory arguments. These arguments are optional. Only
+	// return an error if the arguments have been provided. The gRPC
+	// record state and status values match the v2 API values.
+	var (
+		state  backendv2.StateT
+		status backendv2.StatusT
+	)
+	if r.State != grpcv2.RecordState_RECORD_STATE_INVALID {
+		state = convertRecordStateToBackend(v2.RecordStateT(r.State))
+		if state == backendv2.StateInvalid {
+			return nil, grpcErrorV2(ctx, "",
+				v2.UserErrorReply{
+					ErrorCode: v2.ErrorCodeRecordStateInvalid,
+				})
+		}
+	}
+	if r.Status != grpcv2.RecordStatus_RECORD_STATUS_INVALID {
+		status = convertRecordStatusToBackend(v2.RecordStatusT(r.Status))
+		if status == backendv2.StatusInvalid {
+			return nil, grpcErrorV2(ctx, "",
+				v2.UserErrorReply{
+					ErrorCode: v2.ErrorCodeRecordStatusInvalid,
+				})
+		}
+	}
+
+	// Get inventory
+	inv, err := s.p.backendv2.Inventory(state, status,
+		v2.InventoryPageSize, r.Page)
+	if err != nil {
+		return nil, grpcErrorV2(ctx, "Inventory: Inventory: %v", err)
+	}
+
+	// Prepare reply
+	unvetted := make(map[string]*grpcv2.Tokens, len(inv.Unvetted))
+	for k, v := range inv.Unvetted {
+		unvetted[backendv2.Statuses[k]] = &grpcv2.Tokens{
+			Tokens: v,
+		}
+	}
+	vetted := make(map[string]*grpcv2.Tokens, len(inv.Vetted))
+	for k, v := range inv.Vetted {
+		vetted[backendv2.Statuses[k]] = &grpcv2.Tokens{
+			Tokens: v,
+		}
+	}
+	response := s.p.identity.SignMessage(challenge)
+	return &grpcv2.InventoryReply{
+		Response: hex.EncodeToString(response[:]),
+		Unvetted: unvetted,
+		Vetted:   vetted,
+	}, nil
+}
+
+// PluginWrite executes a plugin write command.
+//
+// This function satisfies the grpcv2 PoliteiadServer interface.
+func (s *grpcServer) PluginWrite(ctx context.Context, r *grpcv2.PluginWrite) (*grpcv2.PluginWriteReply, error) {
+	log.Tracef("grpc PluginWrite")
+
+	// Verify request
+	challenge, err := hex.DecodeString(r.Challenge)
+	if err != nil || len(challenge) != v2.ChallengeSize {
+		return nil, grpcErrorV2(ctx, "PluginWrite: decode challenge",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeChallengeInvalid,
+			})
+	}
+	cmd := r.GetCmd()
+	token, err := decodeToken(cmd.GetToken())
+	if err != nil {
+		return nil, grpcErrorV2(ctx, "PluginWrite: decode token",
+			v2.UserErrorReply{
+				ErrorCode:    v2.ErrorCodeTokenInvalid,
+				ErrorContext: util.TokenRegexp(),
+			})
+	}
+
+	// Verify that the credential has write access to the plugin
+	c := credentialFromContext(ctx)
+	if !c.pluginWriteAllowed(cmd.GetId()) {
+		log.Infof("%v Forbidden gRPC plugin write for: %v %v",
+			grpcRemoteAddr(ctx), c.name, cmd.GetId())
+		return nil, status.Error(codes.PermissionDenied,
+			"rpc credential scope insufficient")
+	}
+
+	// Execute plugin cmd
+	payload, err := s.p.backendv2.PluginWrite(token, cmd.GetId(),
+		cmd.GetCommand(), cmd.GetPayload())
+	if err != nil {
+		return nil, grpcErrorV2(ctx, "PluginWrite: PluginWrite: %v", err)
+	}
+
+	log.Infof("%v Plugin '%v' write cmd '%v' executed",
+		grpcRemoteAddr(ctx), cmd.GetId(), cmd.GetCommand())
+
+	// Prepare reply
+	response := s.p.identity.SignMessage(challenge)
+	return &grpcv2.PluginWriteReply{
+		Response: hex.EncodeToString(response[:]),
+		Payload:  payload,
+	}, nil
+}
+
+// PluginReads executes a batch of plugin read commands. User errors and
+// plugin errors are returned in the reply of the command that caused them.
+//
+// This function satisfies the grpcv2 PoliteiadServer interface.
+func (s *grpcServer) PluginReads(ctx context.Context, r *grpcv2.PluginReads) (*grpcv2.PluginReadsReply, error) {
+	log.Tracef("grpc PluginReads")
+
+	// Verify request
+	challenge, err := hex.DecodeString(r.Challenge)
+	if err != nil || len(challenge) != v2.ChallengeSize {
+		return nil, grpcErrorV2(ctx, "PluginReads: decode challenge",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeChallengeInvalid,
+			})
+	}
+
+	replies := make([]*grpcv2.PluginCmdReply, len(r.Cmds))
+	for k, v := range r.Cmds {
+		// Decode token. The token is optional on plugin reads.
+		var token []byte
+		if v.GetToken() != "" {
+			token, err = decodeTokenAnyLength(v.GetToken())
+			if err != nil {
+				replies[k] = &grpcv2.PluginCmdReply{
+					UserError: &grpcv2.UserErrorReply{
+						ErrorCode:    uint32(v2.ErrorCodeTokenInvalid),
+						ErrorContext: util.TokenRegexp(),
+					},
+				}
+				continue
+			}
+		}
+
+		// Execute plugin cmd
+		payload, err := s.p.backendv2.PluginRead(token, v.GetId(),
+			v.GetCommand(), v.GetPayload())
+		if err != nil {
+			var (
+				errCode = convertErrorToV2(err)
+				pe      backendv2.PluginError
+			)
+			switch {
+			case errCode != v2.ErrorCodeInvalid:
+				replies[k] = &grpcv2.PluginCmdReply{
+					UserError: &grpcv2.UserErrorReply{
+						ErrorCode: uint32(errCode),
+					},
+				}
+				continue
+
+			case errors.As(err, &pe):
+				replies[k] = &grpcv2.PluginCmdReply{
+					PluginError: &grpcv2.PluginErrorReply{
+						PluginId:     pe.PluginID,
+						ErrorCode:    pe.ErrorCode,
+						ErrorContext: pe.ErrorContext,
+					},
+				}
+				continue
+
+			default:
+				return nil, grpcErrorV2(ctx, "PluginReads: %v",
+					fmt.Errorf("PluginRead %v %v %v: %v",
+						v.GetId(), v.GetCommand(), v.GetPayload(), err))
+			}
+		}
+
+		replies[k] = &grpcv2.PluginCmdReply{
+			Token:   v.GetToken(),
+			Id:      v.GetId(),
+			Command: v.GetCommand(),
+			Payload: payload,
+		}
+	}
+
+	// Prepare reply
+	response := s.p.identity.SignMessage(challenge)
+	return &grpcv2.PluginReadsReply{
+		Response: hex.EncodeToString(response[:]),
+		Replies:  replies,
+	}, nil
+}
+
+// grpcChunksRecv receives file chunks using the provided recv function until
+// the client closes the stream and adds them to the payloads of the provided
+// files. The combined size of the received payloads is limited to the gRPC
+// PayloadMax so that a client can't exhaust the server memory.
+func grpcChunksRecv(files []*grpcv2.File, recv func() (*grpcv2.FileChunk, error)) error {
+	var total int
+	for {
+		c, err := recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if c == nil || int(c.File) >= len(files) || files[c.File] == nil ||
+			len(c.Data) == 0 || len(c.Data) > grpcv2.ChunkSize {
+			return v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeRequestPayloadInvalid,
+			}
+		}
+		total += len(c.Data)
+		if total > grpcv2.PayloadMax {
+			return v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeRequestPayloadInvalid,
+				ErrorContext: fmt.Sprintf("file payloads exceed %v bytes",
+					grpcv2.PayloadMax),
+			}
+		}
+		files[c.File].Payload = append(files[c.File].Payload, c.Data...)
+	}
+}
+
+// grpcChunksSend splits the provided file payload into chunks and sends them
+// using the provided send function.
+func grpcChunksSend(file uint32, payload []byte, send func(*grpcv2.FileChunk) error) error {
+	for len(payload) > 0 {
+		n := len(payload)
+		if n > grpcv2.ChunkSize {
+			n = grpcv2.ChunkSize
+		}
+		err := send(&grpcv2.FileChunk{
+			File: file,
+			Data: payload[:n],
+		})
+		if err != nil {
+			return err
+		}
+		payload = payload[n:]
+	}
+	return nil
+}
+
+// grpcErrorV2 converts the provided error into a gRPC status error. User
+// errors and plugin errors are returned using the InvalidArgument code with
+// the JSON encoded v2 error reply as the status message. All other errors are
+// logged as internal errors and are returned using the Internal code with the
+// error timestamp as the status message.
+func grpcErrorV2(ctx context.Context, format string, err error) error {
+	var (
+		errCode = convertErrorToV2(err)
+		ue      v2.UserErrorReply
+		ce      backendv2.ContentError
+		ste     backendv2.StatusTransitionError
+		pe      backendv2.PluginError
+		pse     backendv2.PluginSettingError
+
+		reply interface{}
+	)
+	switch {
+	case errCode != v2.ErrorCodeInvalid:
+		reply = v2.UserErrorReply{
+			ErrorCode: errCode,
+		}
+	case errors.As(err, &ue):
+		reply = ue
+	case errors.As(err, &ce):
+		reply = v2.UserErrorReply{
+			ErrorCode:    convertContentErrorToV2(ce.ErrorCode),
+			ErrorContext: ce.ErrorContext,
+		}
+	case errors.As(err, &ste):
+		reply = v2.UserErrorReply{
+			ErrorCode:    v2.ErrorCodeStatusChangeInvalid,
+			ErrorContext: ste.Error(),
+		}
+	case errors.As(err, &pe):
+		reply = v2.PluginErrorReply{
+			PluginID:     pe.PluginID,
+			ErrorCode:    pe.ErrorCode,
+			ErrorContext: pe.ErrorContext,
+		}
+	case errors.As(err, &pse):
+		reply = v2.UserErrorReply{
+			ErrorCode: v2.ErrorCodePluginSettingInvalid,
+			ErrorContext: fmt.Sprintf("%v '%v': %v",
+				pse.Key, pse.Value, pse.Reason),
+		}
+	}
+	if reply != nil {
+		b, err := json.Marshal(reply)
+		if err != nil {
+			return status.Error(codes.Internal, err.Error())
+		}
+		log.Infof("%v gRPC user error: %s", grpcRemoteAddr(ctx), b)
+		return status.Error(codes.InvalidArgument, string(b))
+	}
+
+	// Internal server error
+	t := time.Now().Unix()
+	e := fmt.Sprintf(format, err)
+	log.Errorf("%v gRPC internal error %v: %v", grpcRemoteAddr(ctx), t, e)
+	log.Errorf("Stacktrace (NOT A REAL CRASH): %s", debug.Stack())
+
+	return status.Errorf(codes.Internal, "%v", t)
+}
+
+func convertMetadataStreamsFromGRPC(streams []*grpcv2.MetadataStream) []backendv2.MetadataStream {
+	ms := make([]backendv2.MetadataStream, 0, len(streams))
+	for _, v := range streams {
+		ms = append(ms, backendv2.MetadataStream{
+			PluginID: v.GetPluginId(),
+			StreamID: v.GetStreamId(),
+			Payload:  v.GetPayload(),
+		})
+	}
+	return ms
+}
+
+func convertFilesFromGRPC(files []*grpcv2.File) []backendv2.File {
+	bf := make([]backendv2.File, 0, len(files))
+	for _, v := range files {
+		bf = append(bf, backendv2.File{
+			Name:    v.GetName(),
+			MIME:    v.GetMime(),
+			Digest:  v.GetDigest(),
+			Payload: base64.StdEncoding.EncodeToString(v.GetPayload()),
+		})
+	}
+	return bf
+}
+
+func convertRecordRequestsFromGRPC(reqs []*grpcv2.RecordRequest) []backendv2.RecordRequest {
+	r := make([]v2.RecordRequest, 0, len(reqs))
+	for _, v := range reqs {
+		r = append(r, v2.RecordRequest{
+			Token:        v.GetToken(),
+			Version:      v.GetVersion(),
+			Filenames:    v.GetFilenames(),
+			OmitAllFiles: v.GetOmitAllFiles(),
+		})
+	}
+	return convertRecordRequestsToBackend(r)
+}
+
+func convertTimestampToGRPC(t v2.Timestamp) *grpcv2.Timestamp {
+	proofs := make([]*grpcv2.Proof, 0, len(t.Proofs))
+	for _, v := range t.Proofs {
+		proofs = append(proofs, &grpcv2.Proof{
+			Type:       v.Type,
+			Digest:     v.Digest,
+			MerkleRoot: v.MerkleRoot,
+			MerklePath: v.MerklePath,
+			ExtraData:  v.ExtraData,
+		})
+	}
+	return &grpcv2.Timestamp{
+		Data:       t.Data,
+		Digest:     t.Digest,
+		TxId:       t.TxID,
+		MerkleRoot: t.MerkleRoot,
+		Proofs:     proofs,
+	}
+}
+
+// convertRecordToGRPC converts a v2 record into a gRPC record. The file
+// payloads are not included.
+func convertRecordToGRPC(r v2.Record) *grpcv2.Record {
+	metadata := make([]*grpcv2.MetadataStream, 0, len(r.Metadata))
+	for _, v := range r.Metadata {
+		metadata = append(metadata, &grpcv2.MetadataStream{
+			PluginId: v.PluginID,
+			StreamId: v.StreamID,
+			Payload:  v.Payload,
+		})
+	}
+	files := make([]*grpcv2.File, 0, len(r.Files))
+	for _, v := range r.Files {
+		files = append(files, &grpcv2.File{
+			Name:   v.Name,
+			Mime:   v.MIME,
+			Digest: v.Digest,
+		})
+	}
+	return &grpcv2.Record{
+		State:     grpcv2.RecordState(r.State),
+		Status:    grpcv2.RecordStatus(r.Status),
+		Version:   r.Version,
+		Timestamp: r.Timestamp,
+		Metadata:  metadata,
+		Files:     files,
+		CensorshipRecord: &grpcv2.CensorshipRecord{
+			Token:     r.CensorshipRecord.Token,
+			Merkle:    r.CensorshipRecord.Merkle,
+			Signature: r.CensorshipRecord.Signature,
+		},
+	}
+}
@@ -0,0 +1,663 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	grpcv2 "github.com/decred/politeia/politeiad/api/grpc/v2"
+	"github.com/decred/politeia/politeiad/api/v1/identity"
+	v2 "github.com/decred/politeia/politeiad/api/v2"
+	backendv2 "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/util"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// testGRPCToken is the token of the records that are created by the
+// testGRPCBackend.
+const testGRPCToken = "0123456789abcdef"
+
+// testGRPCBackend is a backendv2 Backend that stores records in memory. Only
+// the methods that are used by the gRPC tests are implemented. Calling any
+// other method panics.
+type testGRPCBackend struct {
+	backendv2.Backend
+
+	sync.Mutex
+	records map[string]backendv2.Record
+}
+
+// RecordNew creates a new record using the testGRPCToken.
+//
+// This function satisfies the backendv2 Backend interface.
+func (b *testGRPCBackend) RecordNew(metadata []backendv2.MetadataStream, files []backendv2.File) (*backendv2.Record, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	r := backendv2.Record{
+		RecordMetadata: backendv2.RecordMetadata{
+			Token:     testGRPCToken,
+			Version:   1,
+			Iteration: 1,
+			State:     backendv2.StateUnvetted,
+			Status:    backendv2.StatusUnreviewed,
+		},
+		Metadata: metadata,
+		Files:    files,
+	}
+	b.records[testGRPCToken] = r
+	return &r, nil
+}
+
+// RecordEdit adds and deletes the files of an existing record.
+//
+// This function satisfies the backendv2 Backend interface.
+func (b *testGRPCBackend) RecordEdit(token []byte, mdAppend, mdOverwrite []backendv2.MetadataStream, filesAdd []backendv2.File, filesDel []string) (*backendv2.Record, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	r, ok := b.records[hex.EncodeToString(token)]
+	if !ok {
+		return nil, backendv2.ErrRecordNotFound
+	}
+	del := make(map[string]struct{}, len(filesDel))
+	for _, v := range filesDel {
+		del[v] = struct{}{}
+	}
+	files := make([]backendv2.File, 0, len(r.Files)+len(filesAdd))
+	for _, v := range r.Files {
+		if _, ok := del[v.Name]; !ok {
+			files = append(files, v)
+		}
+	}
+	r.Files = append(files, filesAdd...)
+	r.RecordMetadata.Iteration++
+	b.records[r.RecordMetadata.Token] = r
+	return &r, nil
+}
+
+// Records returns a batch of records. Records that do not exist are not
+// included in the reply.
+//
+// This function satisfies the backendv2 Backend interface.
+func (b *testGRPCBackend) Records(reqs []backendv2.RecordRequest) (map[string]backendv2.Record, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	records := make(map[string]backendv2.Record, len(reqs))
+	for _, v := range reqs {
+		token := hex.EncodeToString(v.Token)
+		if r, ok := b.records[token]; ok {
+			records[token] = r
+		}
+	}
+	return records, nil
+}
+
+// Inventory returns the tokens of all records as unreviewed records.
+//
+// This function satisfies the backendv2 Backend interface.
+func (b *testGRPCBackend) Inventory(state backendv2.StateT, status backendv2.StatusT, pageSize, page uint32) (*backendv2.Inventory, error) {
+	b.Lock()
+	defer b.Unlock()
+
+	tokens := make([]string, 0, len(b.records))
+	for k := range b.records {
+		tokens = append(tokens, k)
+	}
+	return &backendv2.Inventory{
+		Unvetted: map[backendv2.StatusT][]string{
+			backendv2.StatusUnreviewed: tokens,
+		},
+	}, nil
+}
+
+// PluginRead returns the payload of the plugin command.
+//
+// This function satisfies the backendv2 Backend interface.
+func (b *testGRPCBackend) PluginRead(token []byte, pluginID, cmd, payload string) (string, error) {
+	return payload, nil
+}
+
+// PluginWrite returns the payload of the plugin command.
+//
+// This function satisfies the backendv2 Backend interface.
+func (b *testGRPCBackend) PluginWrite(token []byte, pluginID, cmd, payload string) (string, error) {
+	return payload, nil
+}
+
+// newTestGRPC returns a gRPC client that is connected to a politeiad gRPC
+// server over an in-memory connection, the politeia context of the server,
+// and a closure that stops the server when invoked. The server uses a
+// testGRPCBackend and the test RPC credentials.
+func newTestGRPC(t *testing.T) (grpcv2.PoliteiadClient, *politeia, func()) {
+	t.Helper()
+
+	logCleanup := newTestLogRotator(t)
+
+	id, err := identity.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := &politeia{
+		backendv2: &testGRPCBackend{
+			records: make(map[string]backendv2.Record),
+		},
+		identity: id,
+		creds:    newTestRPCCredentials(t),
+	}
+
+	l := bufconn.Listen(4 * grpcv2.ChunkSize)
+	s := p.newGRPCServer()
+	go s.Serve(l)
+
+	cc, err := grpc.Dial("bufconn",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return l.Dial()
+		}),
+		grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return grpcv2.NewPoliteiadClient(cc), p, func() {
+		cc.Close()
+		s.Stop()
+		logCleanup()
+	}
+}
+
+// testGRPCContext returns a context that provides the basic authentication
+// credentials of a gRPC call. No credentials are provided if the user is
+// empty.
+func testGRPCContext(user, pass string) context.Context {
+	ctx := context.Background()
+	if user == "" {
+		return ctx
+	}
+	auth := base64.StdEncoding.EncodeToString([]byte(user + ":" + pass))
+	return metadata.AppendToOutgoingContext(ctx,
+		"authorization", "Basic "+auth)
+}
+
+// testGRPCUserError returns the error code of the user error that is
+// contained in a gRPC status error.
+func testGRPCUserError(t *testing.T, err error) v2.ErrorCodeT {
+	t.Helper()
+
+	s, _ := status.FromError(err)
+	if s.Code() != codes.InvalidArgument {
+		t.Fatalf("got status %v, want %v", s.Code(), codes.InvalidArgument)
+	}
+	var ue v2.UserErrorReply
+	err = json.Unmarshal([]byte(s.Message()), &ue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ue.ErrorCode
+}
+
+// testGRPCPayload returns a file payload of the provided size.
+func testGRPCPayload(size int) []byte {
+	b := make([]byte, size)
+	for i := range b {
+		b[i] = byte(i % 251)
+	}
+	return b
+}
+
+func TestGRPCChunksRecv(t *testing.T) {
+	// chunk returns a file chunk that contains size bytes of data
+	chunk := func(file uint32, size int) *grpcv2.FileChunk {
+		return &grpcv2.FileChunk{
+			File: file,
+			Data: bytes.Repeat([]byte{byte(file) + 1}, size),
+		}
+	}
+
+	// The max payload is sent using full chunks
+	full := make([]*grpcv2.FileChunk, 0, grpcv2.PayloadMax/grpcv2.ChunkSize)
+	for i := 0; i < grpcv2.PayloadMax/grpcv2.ChunkSize; i++ {
+		full = append(full, chunk(0, grpcv2.ChunkSize))
+	}
+
+	var tests = []struct {
+		name      string
+		files     int
+		chunks    []*grpcv2.FileChunk
+		wantErr   bool
+		wantSizes []int // Payload size of each file
+	}{
+		{"no chunks", 2, nil, false, []int{0, 0}},
+		{"file split across chunks", 2,
+			[]*grpcv2.FileChunk{chunk(0, grpcv2.ChunkSize), chunk(1, 3),
+				chunk(0, grpcv2.ChunkSize), chunk(0, 1)},
+			false, []int{2*grpcv2.ChunkSize + 1, 3}},
+		{"max payload", 1, full, false, []int{grpcv2.PayloadMax}},
+		{"max payload exceeded", 1, append(full, chunk(0, 1)), true, nil},
+		{"file index out of range", 2, []*grpcv2.FileChunk{chunk(2, 1)},
+			true, nil},
+		{"no files", 0, []*grpcv2.FileChunk{chunk(0, 1)}, true, nil},
+		{"missing chunk", 1, []*grpcv2.FileChunk{nil}, true, nil},
+		{"empty chunk", 1, []*grpcv2.FileChunk{chunk(0, 0)}, true, nil},
+		{"chunk too large", 1,
+			[]*grpcv2.FileChunk{chunk(0, grpcv2.ChunkSize+1)}, true, nil},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			files := make([]*grpcv2.File, v.files)
+			for i := range files {
+				files[i] = &grpcv2.File{}
+			}
+			chunks := v.chunks
+			err := grpcChunksRecv(files, func() (*grpcv2.FileChunk, error) {
+				if len(chunks) == 0 {
+					return nil, io.EOF
+				}
+				c := chunks[0]
+				chunks = chunks[1:]
+				return c, nil
+			})
+			if v.wantErr {
+				var ue v2.UserErrorReply
+				if !errors.As(err, &ue) ||
+					ue.ErrorCode != v2.ErrorCodeRequestPayloadInvalid {
+					t.Fatalf("got error %v, want %v", err,
+						v2.ErrorCodes[v2.ErrorCodeRequestPayloadInvalid])
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// The chunks of a file must be reassembled in order
+			for i, f := range files {
+				want := bytes.Repeat([]byte{byte(i) + 1}, v.wantSizes[i])
+				if !bytes.Equal(f.Payload, want) {
+					t.Fatalf("file %v: got %v payload bytes, want %v",
+						i, len(f.Payload), len(want))
+				}
+			}
+		})
+	}
+}
+
+func TestGRPCRecords(t *testing.T) {
+	c, p, cleanup := newTestGRPC(t)
+	defer cleanup()
+
+	var (
+		ctx = testGRPCContext("admin", "adminpass")
+
+		// The payload of the large files are split across several
+		// chunks.
+		files = []*grpcv2.File{
+			{Name: "large.md", Payload: testGRPCPayload(2*grpcv2.ChunkSize + 1)},
+			{Name: "small.json", Payload: []byte("{}")},
+		}
+		fileAdd = &grpcv2.File{
+			Name:    "edit.md",
+			Payload: testGRPCPayload(grpcv2.ChunkSize + 1),
+		}
+	)
+
+	// verifyChallenge verifies the challenge response of a call
+	verifyChallenge := func(challenge []byte, response string) {
+		t.Helper()
+		err := util.VerifyChallenge(&p.identity.Public, challenge, response)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// verifyFiles verifies the file payloads of the backend record
+	verifyFiles := func(want []*grpcv2.File) {
+		t.Helper()
+		b := p.backendv2.(*testGRPCBackend)
+		got := b.records[testGRPCToken].Files
+		if len(got) != len(want) {
+			t.Fatalf("got %v files, want %v", len(got), len(want))
+		}
+		for i, v := range want {
+			payload := base64.StdEncoding.EncodeToString(v.Payload)
+			if got[i].Name != v.Name || got[i].Payload != payload {
+				t.Fatalf("file %v: got %v with %v payload bytes, want %v "+
+					"with %v payload bytes", i, got[i].Name,
+					base64.StdEncoding.DecodedLen(len(got[i].Payload)),
+					v.Name, len(v.Payload))
+			}
+		}
+	}
+	// omitPayloads returns the provided files without their payloads
+	omitPayloads := func(files []*grpcv2.File) []*grpcv2.File {
+		f := make([]*grpcv2.File, 0, len(files))
+		for _, v := range files {
+			f = append(f, &grpcv2.File{Name: v.Name})
+		}
+		return f
+	}
+	challenge := make([]byte, v2.ChallengeSize)
+
+	// Create a new record. The file payloads are sent as chunks.
+	rn, err := c.RecordNew(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = rn.Send(&grpcv2.RecordNewMsg{
+		RecordNew: &grpcv2.RecordNew{
+			Challenge: hex.EncodeToString(challenge),
+			Files:     omitPayloads(files),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range files {
+		err = grpcChunksSend(uint32(i), v.Payload,
+			func(c *grpcv2.FileChunk) error {
+				return rn.Send(&grpcv2.RecordNewMsg{Chunk: c})
+			})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	rnr, err := rn.CloseAndRecv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifyChallenge(challenge, rnr.Response)
+	if rnr.Record.CensorshipRecord.Token != testGRPCToken {
+		t.Fatalf("got token %v, want %v",
+			rnr.Record.CensorshipRecord.Token, testGRPCToken)
+	}
+	verifyFiles(files)
+
+	// Edit the record. The added file is sent as chunks.
+	re, err := c.RecordEdit(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = re.Send(&grpcv2.RecordEditMsg{
+		RecordEdit: &grpcv2.RecordEdit{
+			Challenge: hex.EncodeToString(challenge),
+			Token:     testGRPCToken,
+			FilesAdd:  omitPayloads([]*grpcv2.File{fileAdd}),
+			FilesDel:  []string{"small.json"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = grpcChunksSend(0, fileAdd.Payload,
+		func(c *grpcv2.FileChunk) error {
+			return re.Send(&grpcv2.RecordEditMsg{Chunk: c})
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rer, err := re.CloseAndRecv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifyChallenge(challenge, rer.Response)
+	files = []*grpcv2.File{files[0], fileAdd}
+	verifyFiles(files)
+
+	// Get the record. The file payloads are received as chunks that
+	// must be reassembled.
+	rs, err := c.Records(ctx, &grpcv2.Records{
+		Challenge: hex.EncodeToString(challenge),
+		Requests: []*grpcv2.RecordRequest{
+			{Token: testGRPCToken},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := rs.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifyChallenge(challenge, m.Response)
+	m, err = rs.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Record == nil || m.Token != testGRPCToken {
+		t.Fatalf("got token %v, want record %v", m.Token, testGRPCToken)
+	}
+	r := m.Record
+	var chunks int
+	for {
+		m, err := rs.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if m.Chunk == nil || int(m.Chunk.File) >= len(r.Files) {
+			t.Fatalf("got message %+v, want file chunk", m)
+		}
+		f := r.Files[m.Chunk.File]
+		f.Payload = append(f.Payload, m.Chunk.Data...)
+		chunks++
+	}
+	if chunks != 5 {
+		t.Fatalf("got %v chunks, want 5", chunks)
+	}
+	for i, v := range files {
+		if r.Files[i].Name != v.Name ||
+			!bytes.Equal(r.Files[i].Payload, v.Payload) {
+			t.Fatalf("file %v: got %v with %v payload bytes, want %v with "+
+				"%v payload bytes", i, r.Files[i].Name,
+				len(r.Files[i].Payload), v.Name, len(v.Payload))
+		}
+	}
+}
+
+func TestGRPCRecordNewChunks(t *testing.T) {
+	c, p, cleanup := newTestGRPC(t)
+	defer cleanup()
+
+	ctx := testGRPCContext("admin", "adminpass")
+	challenge := hex.EncodeToString(make([]byte, v2.ChallengeSize))
+
+	// Chunks that are invalid must be rejected with a user error
+	// before the record is created.
+	var tests = []struct {
+		name  string
+		chunk *grpcv2.FileChunk
+	}{
+		{"file index out of range",
+			&grpcv2.FileChunk{File: 1, Data: []byte("a")}},
+		{"chunk too large", &grpcv2.FileChunk{
+			Data: testGRPCPayload(grpcv2.ChunkSize + 1)}},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			stream, err := c.RecordNew(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, m := range []*grpcv2.RecordNewMsg{
+				{RecordNew: &grpcv2.RecordNew{
+					Challenge: challenge,
+					Files:     []*grpcv2.File{{Name: "index.md"}},
+				}},
+				{Chunk: v.chunk},
+			} {
+				// An io.EOF indicates that the server has closed
+				// the stream. The error is returned by CloseAndRecv.
+				err = stream.Send(m)
+				if err != nil && err != io.EOF {
+					t.Fatal(err)
+				}
+			}
+			_, err = stream.CloseAndRecv()
+			got := testGRPCUserError(t, err)
+			if got != v2.ErrorCodeRequestPayloadInvalid {
+				t.Fatalf("got error code %v, want %v", v2.ErrorCodes[got],
+					v2.ErrorCodes[v2.ErrorCodeRequestPayloadInvalid])
+			}
+			b := p.backendv2.(*testGRPCBackend)
+			if len(b.records) != 0 {
+				t.Fatalf("record was created using an invalid chunk")
+			}
+		})
+	}
+}
+
+func TestGRPCAuth(t *testing.T) {
+	c, _, cleanup := newTestGRPC(t)
+	defer cleanup()
+
+	challenge := hex.EncodeToString(make([]byte, v2.ChallengeSize))
+
+	// The calls that are made by the tests. The record new call is a
+	// streaming call. All other calls are unary calls.
+	recordNew := func(ctx context.Context) error {
+		stream, err := c.RecordNew(ctx)
+		if err != nil {
+			return err
+		}
+		err = stream.Send(&grpcv2.RecordNewMsg{
+			RecordNew: &grpcv2.RecordNew{
+				Challenge: challenge,
+			},
+		})
+		if err != nil && err != io.EOF {
+			return err
+		}
+		_, err = stream.CloseAndRecv()
+		return err
+	}
+	inventory := func(ctx context.Context) error {
+		_, err := c.Inventory(ctx, &grpcv2.Inventory{
+			Challenge: challenge,
+		})
+		return err
+	}
+	pluginReads := func(ctx context.Context) error {
+		_, err := c.PluginReads(ctx, &grpcv2.PluginReads{
+			Challenge: challenge,
+			Cmds: []*grpcv2.PluginCmd{
+				{Id: "comments", Command: "count"},
+			},
+		})
+		return err
+	}
+	pluginWrite := func(pluginID string) func(context.Context) error {
+		return func(ctx context.Context) error {
+			_, err := c.PluginWrite(ctx, &grpcv2.PluginWrite{
+				Challenge: challenge,
+				Cmd: &grpcv2.PluginCmd{
+					Token:   testGRPCToken,
+					Id:      pluginID,
+					Command: "new",
+					Payload: "{}",
+				},
+			})
+			return err
+		}
+	}
+
+	var tests = []struct {
+		name     string
+		user     string
+		pass     string
+		call     func(context.Context) error
+		wantCode codes.Code
+	}{
+		{"no credentials", "", "", inventory, codes.Unauthenticated},
+		{"invalid password", "reader", "adminpass", inventory,
+			codes.Unauthenticated},
+		{"unknown user", "writer", "writerpass", inventory,
+			codes.Unauthenticated},
+		{"read scope unary", "reader", "readerpass", inventory, codes.OK},
+		{"read scope plugin reads", "reader", "readerpass", pluginReads,
+			codes.OK},
+		{"read scope stream", "reader", "readerpass", recordNew,
+			codes.PermissionDenied},
+		{"admin stream", "admin", "adminpass", recordNew, codes.OK},
+		{"read scope plugin write", "reader", "readerpass",
+			pluginWrite("comments"), codes.PermissionDenied},
+		{"plugin scope", "commenter", "commenterpass",
+			pluginWrite("comments"), codes.OK},
+		{"plugin scope other plugin", "commenter", "commenterpass",
+			pluginWrite("ticketvote"), codes.PermissionDenied},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			err := v.call(testGRPCContext(v.user, v.pass))
+			if got := status.Code(err); got != v.wantCode {
+				t.Fatalf("got %v (%v), want %v", got, err, v.wantCode)
+			}
+		})
+	}
+}
+
+func TestGRPCReflection(t *testing.T) {
+	_, p, cleanup := newTestGRPC(t)
+	defer cleanup()
+
+	// Serve a second server using the same politeia context so that a
+	// reflection client can be connected to it.
+	l := bufconn.Listen(1024 * 1024)
+	s := p.newGRPCServer()
+	go s.Serve(l)
+	defer s.Stop()
+	cc, err := grpc.Dial("bufconn",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) {
+			return l.Dial()
+		}),
+		grpc.WithInsecure())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cc.Close()
+
+	// List the services using the reflection service. A read only
+	// credential is sufficient.
+	ctx := testGRPCContext("reader", "readerpass")
+	stream, err := rpb.NewServerReflectionClient(cc).
+		ServerReflectionInfo(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = stream.Send(&rpb.ServerReflectionRequest{
+		MessageRequest: &rpb.ServerReflectionRequest_ListServices{},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	reply, err := stream.Recv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var found bool
+	for _, v := range reply.GetListServicesResponse().GetService() {
+		if v.Name == grpcv2.ServiceName {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("service %v not found using reflection", grpcv2.ServiceName)
+	}
+}
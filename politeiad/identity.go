@@ -0,0 +1,204 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	v1 "github.com/decred/politeia/politeiad/api/v1"
+	"github.com/decred/politeia/politeiad/api/v1/identity"
+	"github.com/decred/politeia/util"
+)
+
+// loadIdentityHistory loads the identity key history from the provided file.
+// A nil history is returned if the file does not exist, i.e. the identity
+// has never been rotated. The history is verified and the last key of the
+// history must be the key of the provided identity.
+func loadIdentityHistory(fp string, id *identity.FullIdentity) ([]v1.IdentityKey, error) {
+	if !util.FileExists(fp) {
+		return nil, nil
+	}
+	b, err := ioutil.ReadFile(fp)
+	if err != nil {
+		return nil, err
+	}
+	var history []v1.IdentityKey
+	err = json.Unmarshal(b, &history)
+	if err != nil {
+		return nil, err
+	}
+	err = v1.VerifyIdentityHistory(history)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 ||
+		history[len(history)-1].PublicKey != id.Public.String() {
+		return nil, fmt.Errorf("the identity does not match the last key " +
+			"of the identity history")
+	}
+	return history, nil
+}
+
+// identityTmpSuffix is the suffix of the temporary files that the identity
+// and the identity history are written to during a rotation.
+const identityTmpSuffix = ".tmp"
+
+// writeFileSync writes the data to the provided file and flushes the file to
+// disk before returning.
+func writeFileSync(fp string, b []byte) error {
+	f, err := os.OpenFile(fp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(b)
+	if err == nil {
+		err = f.Sync()
+	}
+	if err2 := f.Close(); err == nil {
+		err = err2
+	}
+	return err
+}
+
+// recoverIdentityRotation recovers from an identity rotation that was
+// interrupted. It must be run after the identity has been loaded and before
+// the identity history is loaded.
+//
+// The new identity is renamed into place before the new history, so a
+// pending history whose last key is the key of the loaded identity means that
+// the rotation took effect but the history was not renamed. The rotation is
+// completed by renaming the pending history. Any other pending files belong
+// to a rotation that never took effect and are removed.
+func recoverIdentityRotation(cfg *config, id *identity.FullIdentity) error {
+	pending := cfg.IdentityHistory + identityTmpSuffix
+	if util.FileExists(pending) {
+		var history []v1.IdentityKey
+		b, err := ioutil.ReadFile(pending)
+		if err == nil {
+			err = json.Unmarshal(b, &history)
+		}
+		if err == nil {
+			err = v1.VerifyIdentityHistory(history)
+		}
+		if err == nil && len(history) > 0 &&
+			history[len(history)-1].PublicKey == id.Public.String() {
+			log.Infof("Completing interrupted identity rotation")
+			err = os.Rename(pending, cfg.IdentityHistory)
+			if err != nil {
+				return err
+			}
+		} else {
+			log.Infof("Discarding interrupted identity rotation")
+			err = os.Remove(pending)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if util.FileExists(cfg.Identity + identityTmpSuffix) {
+		return os.Remove(cfg.Identity + identityTmpSuffix)
+	}
+	return nil
+}
+
+// rotateIdentity replaces the politeiad signing identity with a newly
+// generated identity. The current identity signs a handover of the new key
+// and its activation time, which is appended to the identity key history.
+// The current identity is archived next to the identity file and the new
+// identity replaces it. The new identity and key history are returned.
+//
+// The activation time is the time of the rotation. This function must be
+// run on startup, prior to the identity being used to sign anything, so that
+// every signature is made by the key that is active at the time it is made.
+func rotateIdentity(cfg *config, id *identity.FullIdentity, history []v1.IdentityKey) (*identity.FullIdentity, []v1.IdentityKey, error) {
+	newID, err := identity.New()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// The first key of a history is the key that was used prior to
+	// the first rotation. It is valid from the beginning of time.
+	if len(history) == 0 {
+		history = []v1.IdentityKey{
+			{
+				PublicKey:  id.Public.String(),
+				Activation: 0,
+			},
+		}
+	}
+
+	// Sign the handover
+	activation := time.Now().Unix()
+	last := history[len(history)-1]
+	if activation <= last.Activation {
+		return nil, nil, fmt.Errorf("activation time %v is not after the "+
+			"activation time of the current key %v", activation,
+			last.Activation)
+	}
+	pubKey := newID.Public.String()
+	sig := id.SignMessage(v1.IdentityHandoverMsg(pubKey, activation))
+	history = append(history, v1.IdentityKey{
+		PublicKey:  pubKey,
+		Activation: activation,
+		Signature:  hex.EncodeToString(sig[:]),
+	})
+
+	// Archive the current identity. It is no longer used to sign
+	// anything but is kept in case the rotation needs to be audited.
+	archive := filepath.Join(filepath.Dir(cfg.Identity),
+		fmt.Sprintf("identity-%v.json", last.PublicKey[:16]))
+	err = id.Save(archive)
+	if err != nil {
+		return nil, nil, fmt.Errorf("archive identity: %v", err)
+	}
+
+	// Save the new identity and history. Both are written to temporary
+	// files and flushed to disk before either one is renamed into
+	// place. Renaming the identity commits the rotation. If politeiad
+	// stops before the history has been renamed as well, the rotation
+	// is completed by recoverIdentityRotation on the next start.
+	idb, err := newID.Marshal()
+	if err != nil {
+		return nil, nil, err
+	}
+	hb, err := json.MarshalIndent(history, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+	var (
+		idTmp      = cfg.Identity + identityTmpSuffix
+		historyTmp = cfg.IdentityHistory + identityTmpSuffix
+	)
+	err = writeFileSync(historyTmp, hb)
+	if err != nil {
+		return nil, nil, fmt.Errorf("save identity history: %v", err)
+	}
+	err = writeFileSync(idTmp, idb)
+	if err != nil {
+		return nil, nil, fmt.Errorf("save identity: %v", err)
+	}
+	err = os.Rename(idTmp, cfg.Identity)
+	if err != nil {
+		return nil, nil, fmt.Errorf("save identity: %v", err)
+	}
+	err = os.Rename(historyTmp, cfg.IdentityHistory)
+	if err != nil {
+		return nil, nil, fmt.Errorf("save identity history: %v", err)
+	}
+
+	log.Infof("Identity rotated")
+	log.Infof("Previous key: %v", last.PublicKey)
+	log.Infof("New key     : %v", pubKey)
+	log.Infof("Activation  : %v", time.Unix(activation, 0).UTC())
+	log.Infof("Previous identity archived to %v", archive)
+
+	return newID, history, nil
+}
@@ -0,0 +1,199 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/decred/politeia/politeiad/api/v1/identity"
+	"github.com/decred/politeia/util"
+)
+
+// newTestIdentityConfig returns a config that uses a new identity in a
+// temporary directory and a closure that removes the directory when invoked.
+func newTestIdentityConfig(t *testing.T) (*config, func()) {
+	t.Helper()
+
+	dir, err := ioutil.TempDir("", "politeiad.identity")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &config{
+		Identity:        filepath.Join(dir, "identity.json"),
+		IdentityHistory: filepath.Join(dir, "identityhistory.json"),
+	}
+	id, err := identity.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = id.Save(cfg.Identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg, func() {
+		os.RemoveAll(dir)
+	}
+}
+
+// loadTestIdentity loads the identity and the identity history the same way
+// that politeiad does on startup.
+func loadTestIdentity(t *testing.T, cfg *config) *identity.FullIdentity {
+	t.Helper()
+
+	id, err := identity.LoadFullIdentity(cfg.Identity)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = recoverIdentityRotation(cfg, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = loadIdentityHistory(cfg.IdentityHistory, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
+
+// copyTestFile copies the src file to dst.
+func copyTestFile(t *testing.T, src, dst string) {
+	t.Helper()
+
+	b, err := ioutil.ReadFile(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(dst, b, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecoverIdentityRotation(t *testing.T) {
+	// The rotation files are written in the following order. A test
+	// stops the rotation at one of these steps.
+	const (
+		stepHistoryTmp  = iota // History written to temp file
+		stepIdentityTmp        // Identity written to temp file
+		stepIdentity           // Identity renamed into place
+		stepHistory            // History renamed into place
+	)
+	var tests = []struct {
+		name        string
+		rotations   int  // Completed rotations prior to the test
+		step        int  // Last step of the interrupted rotation
+		corrupt     bool // History temp file was partially written
+		wantRotated bool
+		wantHistLen int
+	}{
+		{"first history tmp", 0, stepHistoryTmp, false, false, 0},
+		{"first partial history tmp", 0, stepHistoryTmp, true, false, 0},
+		{"first identity tmp", 0, stepIdentityTmp, false, false, 0},
+		{"first identity", 0, stepIdentity, false, true, 2},
+		{"first complete", 0, stepHistory, false, true, 2},
+		{"second identity tmp", 1, stepIdentityTmp, false, false, 2},
+		{"second identity", 1, stepIdentity, false, true, 3},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			cfg, cleanup := newTestIdentityConfig(t)
+			defer cleanup()
+
+			// Complete the prior rotations. The activation time of a
+			// key must be after the activation time of the previous
+			// key, which has a resolution of one second.
+			for i := 0; i < v.rotations; i++ {
+				id := loadTestIdentity(t, cfg)
+				history, err := loadIdentityHistory(cfg.IdentityHistory, id)
+				if err != nil {
+					t.Fatal(err)
+				}
+				_, _, err = rotateIdentity(cfg, id, history)
+				if err != nil {
+					t.Fatal(err)
+				}
+				time.Sleep(time.Second)
+			}
+			prevID := loadTestIdentity(t, cfg)
+			prevHistory, err := loadIdentityHistory(cfg.IdentityHistory, prevID)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// Save the files of the current state, complete a rotation,
+			// and then rebuild the state of the interrupted rotation
+			// from the files of both states.
+			var (
+				dir        = filepath.Dir(cfg.Identity)
+				prevIDFile = filepath.Join(dir, "prev-identity.json")
+				prevHFile  = filepath.Join(dir, "prev-history.json")
+				idTmp      = cfg.Identity + identityTmpSuffix
+				historyTmp = cfg.IdentityHistory + identityTmpSuffix
+			)
+			copyTestFile(t, cfg.Identity, prevIDFile)
+			if prevHistory != nil {
+				copyTestFile(t, cfg.IdentityHistory, prevHFile)
+			}
+			newID, newHistory, err := rotateIdentity(cfg, prevID, prevHistory)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if util.FileExists(idTmp) || util.FileExists(historyTmp) {
+				t.Fatalf("rotation temp files were not removed")
+			}
+			if v.step < stepHistory {
+				copyTestFile(t, cfg.IdentityHistory, historyTmp)
+				if v.corrupt {
+					err = ioutil.WriteFile(historyTmp, []byte("[{"), 0600)
+					if err != nil {
+						t.Fatal(err)
+					}
+				}
+				os.Remove(cfg.IdentityHistory)
+				if prevHistory != nil {
+					copyTestFile(t, prevHFile, cfg.IdentityHistory)
+				}
+			}
+			if v.step < stepIdentity {
+				if v.step >= stepIdentityTmp {
+					copyTestFile(t, cfg.Identity, idTmp)
+				}
+				copyTestFile(t, prevIDFile, cfg.Identity)
+			}
+
+			// politeiad must be able to start and use the identity
+			// that matches the history.
+			id := loadTestIdentity(t, cfg)
+			history, err := loadIdentityHistory(cfg.IdentityHistory, id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantID := prevID
+			if v.wantRotated {
+				wantID = newID
+			}
+			if id.Public.String() != wantID.Public.String() {
+				t.Fatalf("got identity %v, want %v",
+					id.Public.String(), wantID.Public.String())
+			}
+			if len(history) != v.wantHistLen {
+				t.Fatalf("got %v history keys, want %v",
+					len(history), v.wantHistLen)
+			}
+			if v.wantRotated &&
+				history[len(history)-1] != newHistory[len(newHistory)-1] {
+				t.Fatalf("got last history key %+v, want %+v",
+					history[len(history)-1], newHistory[len(newHistory)-1])
+			}
+			if util.FileExists(idTmp) || util.FileExists(historyTmp) {
+				t.Fatalf("rotation temp files were not removed")
+			}
+		})
+	}
+}
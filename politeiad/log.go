@@ -13,10 +13,14 @@ import (
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins/comments"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins/dcrdata"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins/external"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins/search"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins/ticketvote"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/plugins/usermd"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store/cache"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store/localdb"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store/mysql"
+	"github.com/decred/politeia/politeiad/backendv2/tstorebe/store/postgres"
 	"github.com/decred/politeia/politeiad/backendv2/tstorebe/tstore"
 	"github.com/decred/politeia/wsdcrdata"
 	"github.com/decred/slog"
@@ -69,10 +73,14 @@ func init() {
 	tstore.UseLogger(tstoreLog)
 	localdb.UseLogger(kvstoreLog)
 	mysql.UseLogger(kvstoreLog)
+	postgres.UseLogger(kvstoreLog)
+	cache.UseLogger(kvstoreLog)
 
 	// Plugin loggers
 	comments.UseLogger(pluginLog)
 	dcrdata.UseLogger(pluginLog)
+	external.UseLogger(pluginLog)
+	search.UseLogger(pluginLog)
 	ticketvote.UseLogger(pluginLog)
 	usermd.UseLogger(pluginLog)
 
@@ -0,0 +1,108 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package search provides a politeiad plugin that indexes the text content of
+// records and provides an API for searching records by keyword.
+package search
+
+const (
+	// PluginID is the unique identifier for this plugin.
+	PluginID = "search"
+
+	// Plugin commands
+	CmdSearch = "search" // Search records by keyword
+)
+
+// Plugin setting keys can be used to specify custom plugin settings. Default
+// plugin setting values can be overridden by providing a plugin setting key
+// and value to the plugin on startup.
+const (
+	// SettingKeyTextFiles is the plugin setting key for the
+	// SettingTextFiles plugin setting.
+	SettingKeyTextFiles = "textfiles"
+
+	// SettingKeyNameField is the plugin setting key for the
+	// SettingNameField plugin setting.
+	SettingKeyNameField = "namefield"
+
+	// SettingKeyPageSize is the plugin setting key for the
+	// SettingPageSize plugin setting.
+	SettingKeyPageSize = "pagesize"
+)
+
+// Plugin setting default values. These can be overridden by providing a plugin
+// setting key and value to the plugin on startup.
+var (
+	// SettingTextFiles contains the names of the record files whose
+	// content is indexed.
+	SettingTextFiles = []string{"index.md"}
+
+	// SettingNameField is the JSON field of a record file that contains
+	// the record name. It is formatted as <filename>:<jsonfield>. The
+	// record name is indexed with a higher weight than the text files
+	// and is returned in the search results.
+	SettingNameField = "proposalmetadata.json:name"
+
+	// SettingPageSize is the number of results that are returned per
+	// page.
+	SettingPageSize uint32 = 20
+)
+
+// ErrorCodeT represents a plugin error that was caused by the user.
+type ErrorCodeT uint32
+
+const (
+	// ErrorCodeInvalid is an invalid error code.
+	ErrorCodeInvalid ErrorCodeT = 0
+
+	// ErrorCodeQueryInvalid is returned when a search query does not
+	// contain any searchable terms.
+	ErrorCodeQueryInvalid ErrorCodeT = 1
+
+	// ErrorCodeLast unit test only.
+	ErrorCodeLast ErrorCodeT = 2
+)
+
+var (
+	// ErrorCodes contains the human readable errors.
+	ErrorCodes = map[ErrorCodeT]string{
+		ErrorCodeInvalid:      "error code invalid",
+		ErrorCodeQueryInvalid: "query invalid",
+	}
+)
+
+// Search searches the indexed record content for the provided query. The
+// query is split into terms. Records that contain any of the terms are
+// returned, ranked by relevance. Terms that are found in the record name are
+// ranked higher than terms that are only found in the record text.
+//
+// Only vetted records that have not been censored are searched by default.
+// Unvetted can be set to search the unvetted records instead. It is the
+// responsibility of the caller to ensure that unvetted results are only
+// returned to users that are allowed to see them.
+//
+// Page is the page number of the results. Pages start at 1. A page number of
+// 0 returns the first page.
+type Search struct {
+	Query    string `json:"query"`
+	Unvetted bool   `json:"unvetted,omitempty"`
+	Page     uint32 `json:"page,omitempty"`
+}
+
+// SearchResult is a record that matched a search query. Score is the
+// relevance of the record to the query. A higher score is more relevant.
+type SearchResult struct {
+	Token     string  `json:"token"`
+	Name      string  `json:"name,omitempty"`
+	Score     float64 `json:"score"`
+	Timestamp int64   `json:"timestamp"` // Last updated
+}
+
+// SearchReply is the reply to the Search command. Results contains a page of
+// results sorted by score from most relevant to least relevant. Total is the
+// total number of records that matched the query.
+type SearchReply struct {
+	Results []SearchResult `json:"results"`
+	Total   uint32         `json:"total"`
+}
@@ -6,6 +6,11 @@
 // tickets to participate.
 package ticketvote
 
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
 const (
 	// PluginID is the unique identifier for this plugin.
 	PluginID = "ticketvote"
@@ -14,6 +19,8 @@ const (
 	CmdAuthorize   = "authorize"   // Authorize a vote
 	CmdStart       = "start"       // Start a vote
 	CmdCastBallot  = "castballot"  // Cast a ballot of votes
+	CmdReveal      = "reveal"      // Reveal a ballot of secret votes
+	CmdCancel      = "cancel"      // Cancel a vote
 	CmdDetails     = "details"     // Get vote details
 	CmdResults     = "results"     // Get vote results
 	CmdSummary     = "summary"     // Get vote summary
@@ -22,6 +29,14 @@ const (
 	CmdTimestamps  = "timestamps"  // Get vote timestamps
 )
 
+const (
+	// EventVoteFinished is the name of the plugin event that is
+	// published to the backend event stream when a vote has finished,
+	// i.e. the best block has reached the vote end height. The event
+	// payload is a VoteFinished.
+	EventVoteFinished = "votefinished"
+)
+
 // Plugin setting keys can be used to specify custom plugin settings. Default
 // plugin setting values can be overridden by providing a plugin setting key
 // and value to the plugin on startup.
@@ -178,8 +193,12 @@ const (
 	// command is executed on a record that is not public.
 	ErrorCodeRecordStatusInvalid ErrorCodeT = 20
 
+	// ErrorCodeCancelReasonInvalid is returned when a vote is attempted
+	// to be cancelled without a reason.
+	ErrorCodeCancelReasonInvalid ErrorCodeT = 21
+
 	// ErrorCodeLast unit test only
-	ErrorCodeLast ErrorCodeT = 21
+	ErrorCodeLast ErrorCodeT = 22
 )
 
 var (
@@ -206,6 +225,8 @@ var (
 		ErrorCodeLinkToInvalid:        "linkto invalid",
 		ErrorCodeLinkByNotExpired:     "linkby not exipred",
 		ErrorCodeRecordStatusInvalid:  "record status invalid",
+
+		ErrorCodeCancelReasonInvalid: "cancel reason invalid",
 	}
 )
 
@@ -232,6 +253,19 @@ type VoteMetadata struct {
 	// It is set when a record is being submitted as a vote options in
 	// the runoff vote.
 	LinkTo string `json:"linkto,omitempty"`
+
+	// Budget is the total budget of a runoff vote. It can only be set
+	// on a runoff vote parent record, i.e. a record that has set the
+	// LinkBy field. A runoff vote with a budget can have multiple
+	// winners. See RunoffBudget for more details. The budget is in the
+	// smallest unit of the funding currency, e.g. USD cents.
+	Budget uint64 `json:"budget,omitempty"`
+
+	// Amount is the amount that a runoff vote submission is requesting
+	// from the runoff vote budget. It must be set on the submissions
+	// of a runoff vote with a budget and it cannot be set on any other
+	// records. The amount uses the same unit as the budget.
+	Amount uint64 `json:"amount,omitempty"`
 }
 
 // AuthDetails is the structure that is saved to disk when a vote is authorized
@@ -250,6 +284,20 @@ type AuthDetails struct {
 	Receipt   string `json:"receipt"`   // Server signature of client signature
 }
 
+// CancelDetails is the structure that is saved to disk when a vote is
+// cancelled. It contains all the fields from a Cancel and a CancelReply.
+type CancelDetails struct {
+	// Data generated by client
+	Token     string `json:"token"`     // Record token
+	Reason    string `json:"reason"`    // Reason for cancelling the vote
+	PublicKey string `json:"publickey"` // Public key used for signature
+	Signature string `json:"signature"` // Signature of token+reason
+
+	// Metadata generated by server
+	Timestamp int64  `json:"timestamp"` // Received UNIX timestamp
+	Receipt   string `json:"receipt"`   // Server signature of client signature
+}
+
 // VoteT represents the different types of ticket votes that are available.
 type VoteT uint32
 
@@ -276,6 +324,21 @@ const (
 	// net yes votes. Runoff vote participants are not required to have
 	// the voting period authorized prior to the vote starting.
 	VoteTypeRunoff VoteT = 2
+
+	// VoteTypeMultipleChoice is used to indicate a vote that has
+	// between 2 and VoteOptionsMax custom vote options. The winner is
+	// the vote option with the most votes, provided that the quorum
+	// requirement has been met. The quorum is calculated using the
+	// votes cast for all vote options. If a pass percentage is
+	// specified, the winning vote option must also receive at least
+	// the pass percentage of the total votes. A pass percentage of 0
+	// means that the vote option with the most votes wins, i.e. a
+	// plurality vote. A tie for the most votes results in no winner.
+	// Multiple choice votes do not have an approved or rejected
+	// outcome. They finish with the VoteStatusFinished status and the
+	// winning vote option is reported in the vote summary. Multiple
+	// choice votes must be authorized before the vote can be started.
+	VoteTypeMultipleChoice VoteT = 3
 )
 
 const (
@@ -288,6 +351,10 @@ const (
 	// should be not be approved. Votes that are an approve/reject vote
 	// are required to use this vote option ID.
 	VoteOptionIDReject = "no"
+
+	// VoteOptionsMax is the maximum number of vote options that a
+	// multiple choice vote can have.
+	VoteOptionsMax = 16
 )
 
 // VoteOption describes a single vote option.
@@ -318,6 +385,20 @@ type VoteParams struct {
 	// Parent is the token of the parent record. This field will only
 	// be populated for runoff votes.
 	Parent string `json:"parent,omitempty"`
+
+	// RevealDuration is the duration in blocks of the reveal window of
+	// a secret ballot vote. Setting a reveal duration makes the vote a
+	// secret ballot vote. Secret ballot votes use a commit-reveal
+	// scheme. During the voting period each ticket casts a commitment
+	// to its vote bit instead of the vote bit itself, so the results
+	// of the vote are not known while the vote is ongoing. Once the
+	// voting period has ended the reveal window begins and the vote
+	// bits are revealed. A revealed vote bit is only counted if it
+	// matches the commitment of the ticket. Commitments that are not
+	// revealed count toward the quorum requirement, but not toward
+	// the results of any vote option. Standard and multiple choice
+	// votes can be secret ballot votes.
+	RevealDuration uint32 `json:"revealduration,omitempty"`
 }
 
 // VoteDetails is the structure that is saved to disk when a vote is started.
@@ -340,19 +421,32 @@ type VoteDetails struct {
 	StartBlockHash   string   `json:"startblockhash"`
 	EndBlockHeight   uint32   `json:"endblockheight"`
 	EligibleTickets  []string `json:"eligibletickets"` // Ticket hashes
+
+	// RevealEndBlockHeight is the block height that the reveal window
+	// of a secret ballot vote ends at. The reveal window begins at the
+	// end block height.
+	RevealEndBlockHeight uint32 `json:"revealendblockheight,omitempty"`
 }
 
 // CastVoteDetails contains the details of a cast vote.
 //
-// Signature is the client signature of the Token+Ticket+VoteBit. The client
-// uses the ticket's largest commitment address to create the signature. The
-// receipt is the server signature of the client signature.
+// Signature is the client signature of the Token+Ticket+VoteBit+Commitment.
+// The client uses the ticket's largest commitment address to create the
+// signature. The receipt is the server signature of the client signature.
+//
+// The vote bit of a secret ballot vote is empty until the vote has been
+// revealed. The salt is populated once the vote has been revealed. The vote
+// bit was not part of the cast vote, so the signature of a secret ballot vote
+// is the client signature of the Token+Ticket+Commitment. See CastVote for
+// more details.
 type CastVoteDetails struct {
 	// Data generated by client
-	Token     string `json:"token"`     // Record token
-	Ticket    string `json:"ticket"`    // Ticket hash
-	VoteBit   string `json:"votebit"`   // Vote bit, hex encoded
-	Signature string `json:"signature"` // Client signature
+	Token      string `json:"token"`                // Record token
+	Ticket     string `json:"ticket"`               // Ticket hash
+	VoteBit    string `json:"votebit"`              // Vote bit, hex encoded
+	Commitment string `json:"commitment,omitempty"` // Secret ballot commitment
+	Salt       string `json:"salt,omitempty"`       // Secret ballot salt
+	Signature  string `json:"signature"`            // Client signature
 
 	// Metdata generated by server
 	Address   string `json:"address"`   // Largest commitment address
@@ -389,6 +483,26 @@ type AuthorizeReply struct {
 	Receipt   string `json:"receipt"`   // Server signature of client signature
 }
 
+// Cancel cancels an ongoing ticket vote. A cancelled vote is ended
+// immediately and no further ballots are accepted. The votes that have
+// already been cast are kept, but the vote has no outcome. Runoff votes cannot
+// be cancelled since cancelling a single runoff vote would change the outcome
+// of the other records in the runoff.
+//
+// Signature contains the client signature of the Token+Reason.
+type Cancel struct {
+	Token     string `json:"token"`     // Record token
+	Reason    string `json:"reason"`    // Reason for cancelling the vote
+	PublicKey string `json:"publickey"` // Public key used for signature
+	Signature string `json:"signature"` // Client signature
+}
+
+// CancelReply is the reply to the Cancel command.
+type CancelReply struct {
+	Timestamp int64  `json:"timestamp"` // Received UNIX timestamp
+	Receipt   string `json:"receipt"`   // Server signature of client signature
+}
+
 // StartDetails is the structure that is provided when starting a ticket vote.
 //
 // Signature is the signature of a SHA256 digest of the JSON encoded VoteParams
@@ -460,8 +574,18 @@ const (
 	// using a ticket that has already voted.
 	VoteErrorTicketAlreadyVoted VoteErrorT = 9
 
+	// VoteErrorCommitmentInvalid is returned when a secret ballot
+	// vote is cast without a valid commitment, when a commitment is
+	// provided for a vote that is not a secret ballot vote, or when a
+	// revealed vote does not match its commitment.
+	VoteErrorCommitmentInvalid VoteErrorT = 10
+
+	// VoteErrorCommitmentNotFound is returned when a vote is revealed
+	// for a ticket that has not cast a commitment.
+	VoteErrorCommitmentNotFound VoteErrorT = 11
+
 	// VoteErrorLast unit test only.
-	VoteErrorLast VoteErrorT = 10
+	VoteErrorLast VoteErrorT = 12
 )
 
 var (
@@ -478,16 +602,46 @@ var (
 		VoteErrorSignatureInvalid:    "signature invalid",
 		VoteErrorTicketNotEligible:   "ticket not eligible",
 		VoteErrorTicketAlreadyVoted:  "ticket already voted",
+		VoteErrorCommitmentInvalid:   "commitment invalid",
+		VoteErrorCommitmentNotFound:  "commitment not found",
 	}
 )
 
 // CastVote is a signed ticket vote. This structure gets saved to disk when
 // a vote is cast.
+//
+// The vote bit of a secret ballot vote is not included in the cast vote. The
+// vote bit must be left empty and the Commitment must be set instead. The
+// Commitment is the hex encoded SHA256 digest of Token+Ticket+VoteBit+Salt,
+// where VoteBit is the hex encoded vote bit and Salt is a hex encoded random
+// value of SaltSize bytes. The vote bit and salt are revealed once the voting
+// period has ended using the Reveal command. The client must keep the salt
+// secret until then.
+//
+// Signature is the client signature of the Token+Ticket+VoteBit+Commitment.
+// Either the VoteBit or the Commitment will be empty.
 type CastVote struct {
-	Token     string `json:"token"`     // Record token
-	Ticket    string `json:"ticket"`    // Ticket ID
-	VoteBit   string `json:"votebit"`   // Selected vote bit, hex encoded
-	Signature string `json:"signature"` // Signature of Token+Ticket+VoteBit
+	Token      string `json:"token"`                // Record token
+	Ticket     string `json:"ticket"`               // Ticket ID
+	VoteBit    string `json:"votebit"`              // Selected vote bit, hex encoded
+	Commitment string `json:"commitment,omitempty"` // Secret ballot commitment
+	Signature  string `json:"signature"`            // Client signature
+}
+
+const (
+	// SaltSize is the size in bytes of the salt that is used to create
+	// a secret ballot commitment.
+	SaltSize = 32
+)
+
+// VoteCommitment returns the secret ballot commitment of a ticket to the
+// provided vote bit and salt. The commitment is the hex encoded SHA256 digest
+// of the Token+Ticket+VoteBit+Salt. Including the token and ticket binds the
+// commitment to a single ticket vote so that it can't be reused by another
+// ticket or on another record.
+func VoteCommitment(token, ticket, voteBit, salt string) string {
+	d := sha256.Sum256([]byte(token + ticket + voteBit + salt))
+	return hex.EncodeToString(d[:])
 }
 
 // CastVoteReply contains the receipt for the cast vote.
@@ -512,13 +666,49 @@ type CastBallotReply struct {
 	Receipts []CastVoteReply `json:"receipts"`
 }
 
+// RevealVote reveals the vote bit of a secret ballot vote. The vote bit and
+// salt must match the commitment that was cast by the ticket. Votes can only
+// be revealed during the reveal window, i.e. after the vote end block height
+// and prior to the reveal end block height.
+type RevealVote struct {
+	Token   string `json:"token"`   // Record token
+	Ticket  string `json:"ticket"`  // Ticket ID
+	VoteBit string `json:"votebit"` // Selected vote bit, hex encoded
+	Salt    string `json:"salt"`    // Commitment salt, hex encoded
+}
+
+// RevealVoteReply contains the receipt for the revealed vote. The receipt is
+// the server signature of the Ticket+VoteBit+Salt.
+type RevealVoteReply struct {
+	Ticket  string `json:"ticket"`  // Ticket ID
+	Receipt string `json:"receipt"` // Server signature
+
+	// The follwing fields will only be present if an error occurred
+	// while attempting to reveal the vote.
+	ErrorCode    VoteErrorT `json:"errorcode,omitempty"`
+	ErrorContext string     `json:"errorcontext,omitempty"`
+}
+
+// Reveal reveals a ballot of secret ballot votes. A ballot can only contain
+// votes for a single record. A ticket can only be revealed once. Any
+// additional reveals of the same ticket in a ballot are rejected.
+type Reveal struct {
+	Votes []RevealVote `json:"votes"`
+}
+
+// RevealReply is the reply to the Reveal command.
+type RevealReply struct {
+	Receipts []RevealVoteReply `json:"receipts"`
+}
+
 // Details returns the vote details for a record.
 type Details struct{}
 
 // DetailsReply is the reply to the Details command.
 type DetailsReply struct {
-	Auths []AuthDetails `json:"auths"`
-	Vote  *VoteDetails  `json:"vote,omitempty"`
+	Auths  []AuthDetails  `json:"auths"`
+	Vote   *VoteDetails   `json:"vote,omitempty"`
+	Cancel *CancelDetails `json:"cancel,omitempty"`
 }
 
 // Results requests the results of a vote.
@@ -568,8 +758,12 @@ const (
 	// be voted on. This happens when a record is censored or archived.
 	VoteStatusIneligible VoteStatusT = 7
 
+	// VoteStatusCancelled indicates that a started vote was cancelled
+	// by an admin before it finished. A cancelled vote has no outcome.
+	VoteStatusCancelled VoteStatusT = 8
+
 	// VoteStatusLast unit test only.
-	VoteStatusLast VoteStatusT = 8
+	VoteStatusLast VoteStatusT = 9
 )
 
 var (
@@ -583,6 +777,7 @@ var (
 		VoteStatusApproved:     "approved",
 		VoteStatusRejected:     "rejected",
 		VoteStatusIneligible:   "ineligible",
+		VoteStatusCancelled:    "cancelled",
 	}
 )
 
@@ -595,6 +790,63 @@ type VoteOptionResult struct {
 	Votes       uint64 `json:"votes"`       // Votes cast for this option
 }
 
+// BudgetOutcomeT represents the outcome of a submission in a runoff vote that
+// has a budget.
+type BudgetOutcomeT uint32
+
+const (
+	// BudgetOutcomeInvalid is an invalid budget outcome.
+	BudgetOutcomeInvalid BudgetOutcomeT = 0
+
+	// BudgetOutcomeFunded indicates that the submission met the quorum
+	// and pass requirements and its amount was funded by the budget.
+	BudgetOutcomeFunded BudgetOutcomeT = 1
+
+	// BudgetOutcomeNotPassed indicates that the submission did not meet
+	// the quorum and pass requirements.
+	BudgetOutcomeNotPassed BudgetOutcomeT = 2
+
+	// BudgetOutcomeOverBudget indicates that the submission met the
+	// quorum and pass requirements, but the remaining budget was not
+	// sufficient to fund its amount once the submissions with a higher
+	// net approval had been funded.
+	BudgetOutcomeOverBudget BudgetOutcomeT = 3
+
+	// BudgetOutcomeLast unit test only.
+	BudgetOutcomeLast BudgetOutcomeT = 4
+)
+
+var (
+	// BudgetOutcomes contains the human readable budget outcomes.
+	BudgetOutcomes = map[BudgetOutcomeT]string{
+		BudgetOutcomeInvalid:    "invalid",
+		BudgetOutcomeFunded:     "funded",
+		BudgetOutcomeNotPassed:  "not passed",
+		BudgetOutcomeOverBudget: "over budget",
+	}
+)
+
+// RunoffBudget contains the budget outcome of a submission in a runoff vote
+// that has a budget.
+//
+// The submissions that meet the quorum and pass requirements are funded in
+// order of net approval, i.e. approve votes minus reject votes, from highest
+// to lowest. A submission whose amount exceeds the remaining budget is not
+// funded, but the submissions with a lower net approval are still considered
+// so that a smaller submission can be funded with the remaining budget.
+// Submissions with the same net approval are ordered by token. All funded
+// submissions are considered approved. All other submissions are considered
+// rejected.
+//
+// Remaining is the budget that remains after all funded submissions have
+// been deducted. It is the same for all submissions of the runoff vote.
+type RunoffBudget struct {
+	Total     uint64         `json:"total"`     // Runoff vote budget
+	Amount    uint64         `json:"amount"`    // Submission amount
+	Remaining uint64         `json:"remaining"` // Remaining budget
+	Outcome   BudgetOutcomeT `json:"outcome"`
+}
+
 // Summary requests the vote summary for a record.
 type Summary struct{}
 
@@ -611,11 +863,37 @@ type SummaryReply struct {
 	PassPercentage   uint32             `json:"passpercentage,omitempty"`
 	Results          []VoteOptionResult `json:"results,omitempty"`
 
+	// RevealEndBlockHeight and Unrevealed are only populated for secret
+	// ballot votes. Unrevealed is the number of commitments that have
+	// not been revealed. The results of a secret ballot vote only
+	// include the revealed votes. The unrevealed commitments count
+	// toward the quorum requirement.
+	RevealEndBlockHeight uint32 `json:"revealendblockheight,omitempty"`
+	Unrevealed           uint64 `json:"unrevealed,omitempty"`
+
+	// Budget contains the budget outcome of a runoff vote submission
+	// when the runoff vote has a budget. It is only populated once the
+	// runoff vote has finished.
+	Budget *RunoffBudget `json:"budget,omitempty"`
+
+	// WinningOption is the ID of the winning vote option of a finished
+	// multiple choice vote. It will be empty if the vote did not have
+	// a winner, i.e. the quorum or pass requirements were not met or
+	// there was a tie for the most votes.
+	WinningOption string `json:"winningoption,omitempty"`
+
 	// BestBlock is the best block value that was used to prepare this
 	// summary.
 	BestBlock uint32 `json:"bestblock"`
 }
 
+// VoteFinished is the payload of the EventVoteFinished plugin event. The
+// summary contains the final vote status and results.
+type VoteFinished struct {
+	Token   string       `json:"token"`
+	Summary SummaryReply `json:"summary"`
+}
+
 // Submissions requests the submissions of a runoff vote. The only records that
 // will have a submissions list are the parent records in a runoff vote. The
 // list will contain all public runoff vote submissions, i.e. records that
@@ -709,9 +987,9 @@ const (
 
 // Timestamps requests the timestamps for a ticket vote.
 //
-// If no votes page number is provided then the vote authorization and vote
-// details timestamps will be returned. If a votes page number is provided then
-// the specified page of votes will be returned.
+// If no votes page number is provided then the vote authorization, vote
+// details, and vote cancellation timestamps will be returned. If a votes page
+// number is provided then the specified page of votes will be returned.
 type Timestamps struct {
 	VotesPage uint32 `json:"votespage,omitempty"`
 }
@@ -720,5 +998,6 @@ type Timestamps struct {
 type TimestampsReply struct {
 	Auths   []Timestamp `json:"auths"`
 	Details *Timestamp  `json:"details,omitempty"`
+	Cancel  *Timestamp  `json:"cancel,omitempty"`
 	Votes   []Timestamp `json:"votes"`
 }
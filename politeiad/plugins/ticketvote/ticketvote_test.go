@@ -23,4 +23,26 @@ func TestMaps(t *testing.T) {
 	if err != nil {
 		t.Fatalf("VoteStatuses: %v", err)
 	}
+	err = unittest.TestGenericConstMap(BudgetOutcomes,
+		uint64(BudgetOutcomeLast))
+	if err != nil {
+		t.Fatalf("BudgetOutcomes: %v", err)
+	}
+}
+
+func TestVoteCommitment(t *testing.T) {
+	// The commitment is the SHA256 digest of the Token+Ticket+VoteBit+Salt
+	// and must not change since the server and the clients compute it
+	// independently.
+	got := VoteCommitment("aaaa", "bbbb", "1", "cccc")
+	want := "1641722185b9f6534cf26f111b3538c089702c8d5237ada53b9fb38d7451e96d"
+	if got != want {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+
+	// The token and ticket are bound to the commitment
+	if VoteCommitment("aaab", "bbbb", "1", "cccc") == want ||
+		VoteCommitment("aaaa", "bbbc", "1", "cccc") == want {
+		t.Fatalf("commitment is not bound to the token and ticket")
+	}
 }
@@ -5,10 +5,12 @@
 package main
 
 import (
+	"context"
 	"crypto/elliptic"
 	"crypto/x509"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/http/httputil"
 	"os"
@@ -16,6 +18,7 @@ import (
 	"runtime/debug"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/decred/dcrd/chaincfg/v3"
 	v1 "github.com/decred/politeia/politeiad/api/v1"
@@ -28,13 +31,18 @@ import (
 	"github.com/decred/politeia/util"
 	"github.com/decred/politeia/util/version"
 	"github.com/gorilla/mux"
+	"google.golang.org/grpc"
 )
 
 type permission uint
 
 const (
-	permissionPublic permission = iota
-	permissionAuth
+	permissionPublic       permission = iota
+	permissionAdmin                   // Admin routes
+	permissionRead                    // Record and plugin reads
+	permissionRecordWrite             // Record submissions and edits
+	permissionStatusChange            // Record status changes
+	permissionPluginWrite             // Plugin writes; verified per plugin
 )
 
 // politeia application context.
@@ -44,6 +52,12 @@ type politeia struct {
 	cfg       *config
 	router    *mux.Router
 	identity  *identity.FullIdentity
+	creds     *rpcCredentials
+
+	// identityHistory contains the keys that have been used as the
+	// politeiad identity, ordered from oldest to newest. It is nil if
+	// the identity has never been rotated.
+	identityHistory []v1.IdentityKey
 }
 
 func remoteAddr(r *http.Request) string {
@@ -88,17 +102,11 @@ func (p *politeia) respondWithServerError(w http.ResponseWriter, errorCode int64
 	})
 }
 
-func (p *politeia) check(user, pass string) bool {
-	if user != p.cfg.RPCUser || pass != p.cfg.RPCPass {
-		return false
-	}
-	return true
-}
-
-func (p *politeia) auth(fn http.HandlerFunc) http.HandlerFunc {
+func (p *politeia) auth(fn http.HandlerFunc, perm permission) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		user, pass, ok := r.BasicAuth()
-		if !ok || !p.check(user, pass) {
+		c, ok := p.creds.authenticate(r.TLS, user, pass, ok)
+		if !ok {
 			log.Infof("%v Unauthorized access for: %v",
 				remoteAddr(r), user)
 			w.Header().Set("WWW-Authenticate",
@@ -107,9 +115,14 @@ func (p *politeia) auth(fn http.HandlerFunc) http.HandlerFunc {
 			p.respondWithUserError(w, v1.ErrorStatusInvalidRPCCredentials, nil)
 			return
 		}
+		if !c.allowed(perm) {
+			respondWithForbidden(w, r, c, "")
+			return
+		}
 		log.Infof("%v Authorized access for: %v",
-			remoteAddr(r), user)
-		fn(w, r)
+			remoteAddr(r), c.name)
+		fn(w, r.WithContext(context.WithValue(r.Context(),
+			credentialKey{}, c)))
 	}
 }
 
@@ -140,8 +153,8 @@ func closeBody(f http.HandlerFunc) http.HandlerFunc {
 }
 
 func (p *politeia) addRoute(method string, route string, handler http.HandlerFunc, perm permission) {
-	if perm == permissionAuth {
-		handler = p.auth(handler)
+	if perm != permissionPublic {
+		handler = p.auth(handler, perm)
 	}
 	handler = closeBody(logging(handler))
 
@@ -183,27 +196,39 @@ func (p *politeia) setupBackendGit(anp *chaincfg.Params) error {
 
 	// Routes that require auth
 	p.addRoute(http.MethodPost, v1.InventoryRoute, p.inventory,
-		permissionAuth)
+		permissionAdmin)
 	p.addRoute(http.MethodPost, v1.SetUnvettedStatusRoute,
-		p.setUnvettedStatus, permissionAuth)
+		p.setUnvettedStatus, permissionAdmin)
 	p.addRoute(http.MethodPost, v1.SetVettedStatusRoute,
-		p.setVettedStatus, permissionAuth)
+		p.setVettedStatus, permissionAdmin)
 	p.addRoute(http.MethodPost, v1.UpdateVettedMetadataRoute,
-		p.updateVettedMetadata, permissionAuth)
+		p.updateVettedMetadata, permissionAdmin)
 
 	// Set plugin routes. Requires auth.
 	p.addRoute(http.MethodPost, v1.PluginCommandRoute, p.pluginCommand,
-		permissionAuth)
+		permissionAdmin)
 	p.addRoute(http.MethodPost, v1.PluginInventoryRoute, p.pluginInventory,
-		permissionAuth)
+		permissionAdmin)
 
 	return nil
 }
 
 func (p *politeia) setupBackendTstore(anp *chaincfg.Params) error {
+	// Restore the backend from a backup. This must be done prior to
+	// the backend being initialized since the restore requires that
+	// the backend database be empty.
+	if p.cfg.Restore != "" {
+		err := tstorebe.Restore(p.cfg.Restore, p.cfg.HomeDir, p.cfg.DataDir,
+			anp, p.cfg.TlogPass, p.cfg.DBType, p.cfg.DBHost, p.cfg.DBPass)
+		if err != nil {
+			return fmt.Errorf("restore: %v", err)
+		}
+	}
+
 	b, err := tstorebe.New(p.cfg.HomeDir, p.cfg.DataDir, anp,
-		p.cfg.TlogHost, p.cfg.TlogPass, p.cfg.DBType, p.cfg.DBHost,
-		p.cfg.DBPass, p.cfg.DcrtimeHost, p.cfg.DcrtimeCert)
+		p.cfg.TlogType, p.cfg.TlogHost, p.cfg.TlogPass, p.cfg.DBType,
+		p.cfg.DBHost, p.cfg.DBPass, p.cfg.DcrtimeHost, p.cfg.DcrtimeCert,
+		p.cfg.Anchor, p.cfg.AnchorSchedule, p.cfg.BlobCacheSize*1024*1024)
 	if err != nil {
 		return fmt.Errorf("new tstorebe: %v", err)
 	}
@@ -221,30 +246,61 @@ func (p *politeia) setupBackendTstore(anp *chaincfg.Params) error {
 
 	// Setup v2 routes
 	p.addRouteV2(http.MethodPost, v2.RouteRecordNew,
-		p.handleRecordNew, permissionPublic)
+		p.handleRecordNew, permissionRecordWrite)
 	p.addRouteV2(http.MethodPost, v2.RouteRecordEdit,
-		p.handleRecordEdit, permissionPublic)
+		p.handleRecordEdit, permissionRecordWrite)
 	p.addRouteV2(http.MethodPost, v2.RouteRecordEditMetadata,
-		p.handleRecordEditMetadata, permissionPublic)
+		p.handleRecordEditMetadata, permissionRecordWrite)
 	p.addRouteV2(http.MethodPost, v2.RouteRecordSetStatus,
-		p.handleRecordSetStatus, permissionPublic)
+		p.handleRecordSetStatus, permissionStatusChange)
 	p.addRouteV2(http.MethodPost, v2.RouteRecords,
-		p.handleRecords, permissionPublic)
+		p.handleRecords, permissionRead)
 	p.addRouteV2(http.MethodPost, v2.RouteRecordTimestamps,
-		p.handleRecordTimestamps, permissionPublic)
+		p.handleRecordTimestamps, permissionRead)
+	p.addRouteV2(http.MethodPost, v2.RouteRecordDiff,
+		p.handleRecordDiff, permissionRead)
 	p.addRouteV2(http.MethodPost, v2.RouteInventory,
-		p.handleInventory, permissionPublic)
+		p.handleInventory, permissionRead)
 	p.addRouteV2(http.MethodPost, v2.RouteInventoryOrdered,
-		p.handleInventoryOrdered, permissionPublic)
+		p.handleInventoryOrdered, permissionRead)
+	p.addRouteV2(http.MethodPost, v2.RouteInventoryQuery,
+		p.handleInventoryQuery, permissionRead)
 	p.addRouteV2(http.MethodPost, v2.RoutePluginWrite,
-		p.handlePluginWrite, permissionPublic)
+		p.handlePluginWrite, permissionPluginWrite)
 	p.addRouteV2(http.MethodPost, v2.RoutePluginReads,
-		p.handlePluginReads, permissionPublic)
+		p.handlePluginReads, permissionRead)
 	p.addRouteV2(http.MethodPost, v2.RoutePluginInventory,
-		p.handlePluginInventory, permissionPublic)
-
-	p.addRouteV2(http.MethodPost, v2.RoutePluginInventory,
-		p.handlePluginInventory, permissionPublic)
+		p.handlePluginInventory, permissionRead)
+	p.addRouteV2(http.MethodGet, v2.RouteEvents,
+		p.handleEvents, permissionRead)
+
+	// Setup v2 admin routes
+	p.addRouteV2(http.MethodPost, v2.RouteFsck,
+		p.handleFsck, permissionAdmin)
+	p.addRouteV2(http.MethodPost, v2.RouteEncryptionKeyRotate,
+		p.handleEncryptionKeyRotate, permissionAdmin)
+	p.addRouteV2(http.MethodPost, v2.RouteEncryptionKeyStatus,
+		p.handleEncryptionKeyStatus, permissionAdmin)
+	p.addRouteV2(http.MethodPost, v2.RouteBlobCacheStats,
+		p.handleBlobCacheStats, permissionAdmin)
+	p.addRouteV2(http.MethodPost, v2.RouteAnchorStatus,
+		p.handleAnchorStatus, permissionAdmin)
+	p.addRouteV2(http.MethodPost, v2.RouteAnchorDrop,
+		p.handleAnchorDrop, permissionAdmin)
+	p.addRouteV2(http.MethodPost, v2.RouteRecordExport,
+		p.handleRecordExport, permissionAdmin)
+	p.addRouteV2(http.MethodPost, v2.RouteRecordImport,
+		p.handleRecordImport, permissionAdmin)
+	p.addRouteV2(http.MethodPost, v2.RoutePluginSettings,
+		p.handlePluginSettings, permissionAdmin)
+	p.addRouteV2(http.MethodPost, v2.RoutePluginSettingsUpdate,
+		p.handlePluginSettingsUpdate, permissionAdmin)
+	p.addRouteV2(http.MethodPost, v2.RouteBackup,
+		p.handleBackup, permissionAdmin)
+	p.addRouteV2(http.MethodPost, v2.RouteCacheRebuild,
+		p.handleCacheRebuild, permissionAdmin)
+	p.addRouteV2(http.MethodPost, v2.RouteCacheRebuildStatus,
+		p.handleCacheRebuildStatus, permissionAdmin)
 
 	// Setup plugins
 	if len(p.cfg.Plugins) > 0 {
@@ -276,22 +332,43 @@ func (p *politeia) setupBackendTstore(anp *chaincfg.Params) error {
 
 		// Register plugins
 		for _, v := range p.cfg.Plugins {
+			// Plugin will be in format: pluginID[,path]. The path is
+			// only provided for plugins that run in a separate process.
+			var (
+				s        = strings.SplitN(v, ",", 2)
+				pluginID = s[0]
+				path     string
+			)
+			if len(s) == 2 {
+				path = s[1]
+			}
+
 			// Setup plugin
-			ps, ok := settings[v]
+			ps, ok := settings[pluginID]
 			if !ok {
 				ps = make([]backendv2.PluginSetting, 0)
 			}
 			plugin := backendv2.Plugin{
-				ID:       v,
+				ID:       pluginID,
 				Settings: ps,
 				Identity: p.identity,
+				Path:     path,
 			}
 
 			// Register plugin
 			log.Infof("Register plugin: %v", v)
 			err = p.backendv2.PluginRegister(plugin)
 			if err != nil {
-				return fmt.Errorf("PluginRegister %v: %v", v, err)
+				return fmt.Errorf("PluginRegister %v: %v", pluginID, err)
+			}
+		}
+
+		// Rebuild the plugin caches. This must be done prior to the
+		// plugins being setup since plugin setup reads the caches.
+		if p.cfg.RebuildCaches {
+			err = p.rebuildCaches()
+			if err != nil {
+				return fmt.Errorf("rebuild caches: %v", err)
 			}
 		}
 
@@ -305,9 +382,81 @@ func (p *politeia) setupBackendTstore(anp *chaincfg.Params) error {
 		}
 	}
 
+	// Verify that the restored tlog trees match the backup. The
+	// trillian trees are restored separately from the backend data,
+	// so they are only known to be consistent once they have been
+	// checked against the backup manifest.
+	if p.cfg.Restore != "" {
+		err := p.backendv2.RestoreVerify(p.cfg.Restore)
+		if err != nil {
+			return fmt.Errorf("restore verify: %v", err)
+		}
+	}
+
+	// Perform a filesystem check. This must be done prior to the
+	// listeners being started since repairs are not allowed while the
+	// backend is accepting writes.
+	if p.cfg.Fsck {
+		r, err := p.backendv2.Fsck(p.cfg.FsckRepair)
+		if err != nil {
+			return fmt.Errorf("fsck: %v", err)
+		}
+		var unrepaired int
+		for _, v := range r.Issues {
+			if v.Repaired {
+				continue
+			}
+			unrepaired++
+			log.Warnf("Fsck %v %v: %v", backendv2.FsckIssues[v.Type],
+				v.Token, v.Description)
+		}
+		log.Infof("Fsck complete: %v records, %v blobs, %v issues, "+
+			"%v repaired", r.Records, r.Blobs, len(r.Issues),
+			len(r.Issues)-unrepaired)
+
+		// A restored backend must be free of issues before it is
+		// allowed to accept writes.
+		if p.cfg.Restore != "" && unrepaired > 0 {
+			return fmt.Errorf("restore: fsck found %v unrepaired issues",
+				unrepaired)
+		}
+	}
+
 	return nil
 }
 
+// rebuildCaches rebuilds the caches of all registered plugins and waits for
+// the rebuild to finish. The rebuild progress is logged periodically.
+func (p *politeia) rebuildCaches() error {
+	log.Infof("Rebuilding plugin caches")
+
+	_, err := p.backendv2.CacheRebuild(nil)
+	if err != nil {
+		return err
+	}
+	for {
+		time.Sleep(5 * time.Second)
+
+		s, err := p.backendv2.CacheRebuildStatus()
+		if err != nil {
+			return err
+		}
+		for _, v := range s.Plugins {
+			log.Infof("Cache rebuild %v: %v/%v records", v.PluginID,
+				v.Rebuilt, v.Records)
+		}
+		if s.InProgress {
+			continue
+		}
+		for _, v := range s.Plugins {
+			if v.Error != "" {
+				return fmt.Errorf("%v: %v", v.PluginID, v.Error)
+			}
+		}
+		return nil
+	}
+}
+
 func _main() error {
 	// Load configuration and parse command line.  This function also
 	// initializes logging and configures it accordingly.
@@ -372,8 +521,33 @@ func _main() error {
 	if err != nil {
 		return err
 	}
+	err = recoverIdentityRotation(cfg, p.identity)
+	if err != nil {
+		return fmt.Errorf("recover identity rotation: %v", err)
+	}
+	p.identityHistory, err = loadIdentityHistory(cfg.IdentityHistory,
+		p.identity)
+	if err != nil {
+		return fmt.Errorf("load identity history: %v", err)
+	}
+
+	// Rotate the identity. This must be done before the identity is
+	// used to sign anything.
+	if cfg.IdentityRotate {
+		p.identity, p.identityHistory, err = rotateIdentity(cfg,
+			p.identity, p.identityHistory)
+		if err != nil {
+			return fmt.Errorf("rotate identity: %v", err)
+		}
+	}
 	log.Infof("Public key: %x", p.identity.Public.Key)
 
+	// Setup RPC credentials
+	p.creds, err = newRPCCredentials(cfg)
+	if err != nil {
+		return fmt.Errorf("rpc credentials: %v", err)
+	}
+
 	// Load certs, if there.  If they aren't there assume OS is used to
 	// resolve cert validity.
 	if len(cfg.DcrtimeCert) != 0 {
@@ -410,17 +584,47 @@ func _main() error {
 		return fmt.Errorf("invalid backend selected: %v", cfg.Backend)
 	}
 
+	// Setup the TLS config. Client certificates are verified when
+	// client certificate authentication has been enabled.
+	tlsConfig, err := p.tlsConfig()
+	if err != nil {
+		return err
+	}
+
 	// Bind to a port and pass our router in
 	listenC := make(chan error)
 	for _, listener := range cfg.Listeners {
 		listen := listener
 		go func() {
 			log.Infof("Listen: %v", listen)
-			listenC <- http.ListenAndServeTLS(listen,
-				cfg.HTTPSCert, cfg.HTTPSKey, p.router)
+			srv := &http.Server{
+				Addr:      listen,
+				Handler:   p.router,
+				TLSConfig: tlsConfig.Clone(),
+			}
+			listenC <- srv.ListenAndServeTLS(cfg.HTTPSCert, cfg.HTTPSKey)
 		}()
 	}
 
+	// Bind the gRPC listeners
+	var grpcServer *grpc.Server
+	if len(cfg.GRPCListen) > 0 {
+		grpcServer, err = p.setupGRPC(tlsConfig.Clone())
+		if err != nil {
+			return fmt.Errorf("setup grpc: %v", err)
+		}
+		for _, listener := range cfg.GRPCListen {
+			l, err := net.Listen("tcp", listener)
+			if err != nil {
+				return fmt.Errorf("grpc listen %v: %v", listener, err)
+			}
+			log.Infof("gRPC listen: %v", listener)
+			go func() {
+				listenC <- grpcServer.Serve(l)
+			}()
+		}
+	}
+
 	// Tell user we are ready to go.
 	log.Infof("Start of day")
 
@@ -439,6 +643,9 @@ func _main() error {
 		}
 	}
 done:
+	if grpcServer != nil {
+		grpcServer.Stop()
+	}
 	switch p.cfg.Backend {
 	case backendGit:
 		p.backend.Close()
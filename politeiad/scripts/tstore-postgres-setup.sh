@@ -0,0 +1,40 @@
+#!/usr/bin/env sh
+
+# Accepts environment variables:
+# - POSTGRES_HOST: The hostname of the PostgreSQL server (default: localhost).
+# - POSTGRES_PORT: The port the PostgreSQL server is listening on (default:
+#   5432).
+# - POSTGRES_ROOT_USER: A user with sufficient rights to create new users and
+#   create/drop the politeiad database (default: postgres).
+# - POSTGRES_ROOT_PASSWORD: The password for the user defined by
+#   POSTGRES_ROOT_USER (required, default: none).
+# - POSTGRES_POLITEIAD_PASSWORD: The password for the politeiad user that will
+#   be created during this script (required, default: none).
+
+# Set unset environment variables to defaults
+[ -z ${POSTGRES_HOST+x} ] && POSTGRES_HOST="localhost"
+[ -z ${POSTGRES_PORT+x} ] && POSTGRES_PORT="5432"
+[ -z ${POSTGRES_ROOT_USER+x} ] && POSTGRES_ROOT_USER="postgres"
+[ -z ${POSTGRES_ROOT_PASSWORD+x} ] && POSTGRES_ROOT_PASSWORD=""
+[ -z ${POSTGRES_POLITEIAD_PASSWORD+x} ] && POSTGRES_POLITEIAD_PASSWORD=""
+
+export PGPASSWORD="${POSTGRES_ROOT_PASSWORD}"
+flags="-U ${POSTGRES_ROOT_USER} -h ${POSTGRES_HOST} -p ${POSTGRES_PORT} \
+  --echo-all"
+
+# Database users
+politeiad="politeiad"
+
+# Database names
+testnet_kv="testnet3_kv"
+mainnet_kv="mainnet_kv"
+
+# Setup database user. PostgreSQL does not support CREATE USER IF NOT EXISTS
+# so the error is ignored if the user already exists.
+psql ${flags} -c \
+  "CREATE USER ${politeiad} WITH PASSWORD '${POSTGRES_POLITEIAD_PASSWORD}';"
+
+# Setup kv databases. PostgreSQL does not support CREATE DATABASE IF NOT
+# EXISTS so the error is ignored if the database already exists.
+psql ${flags} -c "CREATE DATABASE ${testnet_kv} OWNER ${politeiad};"
+psql ${flags} -c "CREATE DATABASE ${mainnet_kv} OWNER ${politeiad};"
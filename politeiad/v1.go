@@ -33,6 +33,7 @@ func (p *politeia) getIdentity(w http.ResponseWriter, r *http.Request) {
 	reply := v1.IdentityReply{
 		PublicKey: hex.EncodeToString(p.identity.Public.Key[:]),
 		Response:  hex.EncodeToString(response[:]),
+		History:   p.identityHistory,
 	}
 
 	util.RespondWithJSON(w, http.StatusOK, reply)
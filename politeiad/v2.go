@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"os/exec"
 	"runtime/debug"
 	"time"
 
@@ -338,6 +339,58 @@ func (p *politeia) handleRecordTimestamps(w http.ResponseWriter, r *http.Request
 	util.RespondWithJSON(w, http.StatusOK, rtr)
 }
 
+func (p *politeia) handleRecordDiff(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleRecordDiff")
+
+	// Decode request
+	var rd v2.RecordDiff
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&rd); err != nil {
+		respondWithErrorV2(w, r, "handleRecordDiff: unmarshal",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeRequestPayloadInvalid,
+			})
+		return
+	}
+	challenge, err := hex.DecodeString(rd.Challenge)
+	if err != nil || len(challenge) != v2.ChallengeSize {
+		respondWithErrorV2(w, r, "handleRecordDiff: decode challenge",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeChallengeInvalid,
+			})
+		return
+	}
+	token, err := decodeTokenAnyLength(rd.Token)
+	if err != nil {
+		respondWithErrorV2(w, r, "handleRecordDiff: decode token",
+			v2.UserErrorReply{
+				ErrorCode:    v2.ErrorCodeTokenInvalid,
+				ErrorContext: util.TokenRegexp(),
+			})
+		return
+	}
+
+	// Get record diff
+	d, err := p.backendv2.RecordDiff(token, rd.From, rd.To)
+	if err != nil {
+		respondWithErrorV2(w, r,
+			"handleRecordDiff: RecordDiff: %v", err)
+		return
+	}
+
+	// Prepare reply
+	response := p.identity.SignMessage(challenge)
+	rdr := v2.RecordDiffReply{
+		Response: hex.EncodeToString(response[:]),
+		From:     convertDiffVersionToV2(d.From),
+		To:       convertDiffVersionToV2(d.To),
+		Files:    convertFileDiffsToV2(d.Files),
+		Metadata: convertMetadataStreamDiffsToV2(d.Metadata),
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, rdr)
+}
+
 func (p *politeia) handleInventory(w http.ResponseWriter, r *http.Request) {
 	log.Tracef("handleInventory")
 
@@ -471,6 +524,58 @@ func (p *politeia) handleInventoryOrdered(w http.ResponseWriter, r *http.Request
 	util.RespondWithJSON(w, http.StatusOK, ir)
 }
 
+func (p *politeia) handleInventoryQuery(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleInventoryQuery")
+
+	// Decode request
+	var iq v2.InventoryQuery
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&iq); err != nil {
+		respondWithErrorV2(w, r, "handleInventoryQuery: unmarshal",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeRequestPayloadInvalid,
+			})
+		return
+	}
+	challenge, err := hex.DecodeString(iq.Challenge)
+	if err != nil || len(challenge) != v2.ChallengeSize {
+		respondWithErrorV2(w, r, "handleInventoryQuery: decode challenge",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeChallengeInvalid,
+			})
+		return
+	}
+
+	// Verify query
+	q, err := convertInventoryQueryToBackend(iq)
+	if err != nil {
+		respondWithErrorV2(w, r, "", err)
+		return
+	}
+
+	// Get inventory page
+	page, err := p.backendv2.InventoryQuery(*q)
+	if err != nil {
+		respondWithErrorV2(w, r,
+			"handleInventoryQuery: InventoryQuery: %v", err)
+		return
+	}
+
+	// Prepare reply
+	entries := make([]v2.InventoryEntry, 0, len(page.Entries))
+	for _, v := range page.Entries {
+		entries = append(entries, convertInventoryEntryToV2(v))
+	}
+	response := p.identity.SignMessage(challenge)
+	iqr := v2.InventoryQueryReply{
+		Response: hex.EncodeToString(response[:]),
+		Entries:  entries,
+		Cursor:   page.Cursor,
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, iqr)
+}
+
 func (p *politeia) handlePluginWrite(w http.ResponseWriter, r *http.Request) {
 	log.Tracef("handlePluginWrite")
 
@@ -502,6 +607,14 @@ func (p *politeia) handlePluginWrite(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Verify that the credential has write access to the plugin
+	c := credentialFromContext(r.Context())
+	if !c.pluginWriteAllowed(pw.Cmd.ID) {
+		respondWithForbidden(w, r, c,
+			fmt.Sprintf("plugin write scope required for %v", pw.Cmd.ID))
+		return
+	}
+
 	// Execute plugin cmd
 	payload, err := p.backendv2.PluginWrite(token, pw.Cmd.ID,
 		pw.Cmd.Command, pw.Cmd.Payload)
@@ -666,160 +779,807 @@ func (p *politeia) handlePluginInventory(w http.ResponseWriter, r *http.Request)
 
 }
 
-// decodeToken decodes a v2 token and errors if the token is not the full
-// length token.
-func decodeToken(token string) ([]byte, error) {
-	return util.TokenDecode(util.TokenTypeTstore, token)
-}
-
-// decodeTokenAnyLength decodes a v2 token. It accepts both the full length
-// token and the short token.
-func decodeTokenAnyLength(token string) ([]byte, error) {
-	return util.TokenDecodeAnyLength(util.TokenTypeTstore, token)
-}
+func (p *politeia) handleFsck(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleFsck")
 
-func (p *politeia) convertRecordToV2(r backendv2.Record) v2.Record {
-	var (
-		metadata = convertMetadataStreamsToV2(r.Metadata)
-		files    = convertFilesToV2(r.Files)
-		rm       = r.RecordMetadata
-		sig      = p.identity.SignMessage([]byte(rm.Merkle + rm.Token))
-	)
-	return v2.Record{
-		State:     v2.RecordStateT(rm.State),
-		Status:    v2.RecordStatusT(rm.Status),
-		Version:   rm.Version,
-		Timestamp: rm.Timestamp,
-		Metadata:  metadata,
-		Files:     files,
-		CensorshipRecord: v2.CensorshipRecord{
-			Token:     rm.Token,
-			Merkle:    rm.Merkle,
-			Signature: hex.EncodeToString(sig[:]),
-		},
+	// Decode request
+	var f v2.Fsck
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&f); err != nil {
+		respondWithErrorV2(w, r, "handleFsck: unmarshal",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeRequestPayloadInvalid,
+			})
+		return
 	}
-}
-
-func convertMetadataStreamsToBackend(metadata []v2.MetadataStream) []backendv2.MetadataStream {
-	ms := make([]backendv2.MetadataStream, 0, len(metadata))
-	for _, v := range metadata {
-		ms = append(ms, backendv2.MetadataStream{
-			PluginID: v.PluginID,
-			StreamID: v.StreamID,
-			Payload:  v.Payload,
-		})
+	challenge, err := hex.DecodeString(f.Challenge)
+	if err != nil || len(challenge) != v2.ChallengeSize {
+		respondWithErrorV2(w, r, "handleFsck: decode challenge",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeChallengeInvalid,
+			})
+		return
 	}
-	return ms
-}
 
-func convertMetadataStreamsToV2(metadata []backendv2.MetadataStream) []v2.MetadataStream {
-	ms := make([]v2.MetadataStream, 0, len(metadata))
-	for _, v := range metadata {
-		ms = append(ms, v2.MetadataStream{
-			PluginID: v.PluginID,
-			StreamID: v.StreamID,
-			Payload:  v.Payload,
-		})
+	// Perform fsck. Repairs are not allowed while the backend is
+	// accepting writes.
+	report, err := p.backendv2.Fsck(false)
+	if err != nil {
+		respondWithErrorV2(w, r,
+			"handleFsck: Fsck: %v", err)
+		return
 	}
-	return ms
-}
 
-func convertFilesToBackend(files []v2.File) []backendv2.File {
-	fs := make([]backendv2.File, 0, len(files))
-	for _, v := range files {
-		fs = append(fs, backendv2.File{
-			Name:    v.Name,
-			MIME:    v.MIME,
-			Digest:  v.Digest,
-			Payload: v.Payload,
-		})
+	// Prepare reply
+	response := p.identity.SignMessage(challenge)
+	fr := v2.FsckReply{
+		Response: hex.EncodeToString(response[:]),
+		Records:  report.Records,
+		Blobs:    report.Blobs,
+		Issues:   convertFsckIssuesToV2(report.Issues),
 	}
-	return fs
+
+	util.RespondWithJSON(w, http.StatusOK, fr)
 }
 
-func convertFilesToV2(files []backendv2.File) []v2.File {
-	fs := make([]v2.File, 0, len(files))
-	for _, v := range files {
-		fs = append(fs, v2.File{
-			Name:    v.Name,
-			MIME:    v.MIME,
-			Digest:  v.Digest,
-			Payload: v.Payload,
-		})
+func (p *politeia) handleEncryptionKeyRotate(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleEncryptionKeyRotate")
+
+	// Decode request
+	var e v2.EncryptionKeyRotate
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&e); err != nil {
+		respondWithErrorV2(w, r, "handleEncryptionKeyRotate: unmarshal",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeRequestPayloadInvalid,
+			})
+		return
+	}
+	challenge, err := hex.DecodeString(e.Challenge)
+	if err != nil || len(challenge) != v2.ChallengeSize {
+		respondWithErrorV2(w, r, "handleEncryptionKeyRotate: decode challenge",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeChallengeInvalid,
+			})
+		return
 	}
-	return fs
-}
 
-func convertRecordRequestsToBackend(reqs []v2.RecordRequest) []backendv2.RecordRequest {
-	r := make([]backendv2.RecordRequest, 0, len(reqs))
-	for _, v := range reqs {
-		token, err := decodeTokenAnyLength(v.Token)
-		if err != nil {
-			// Records with errors will not be included in the reply
-			log.Debugf("convertRecordRequestsToBackend: decode token: %v", err)
-			continue
-		}
-		r = append(r, backendv2.RecordRequest{
-			Token:        token,
-			Version:      v.Version,
-			Filenames:    v.Filenames,
-			OmitAllFiles: v.OmitAllFiles,
-		})
+	// Rotate the encryption key. The existing data is re-encrypted in
+	// the background.
+	ks, err := p.backendv2.RotateEncryptionKey(e.KeyFile)
+	if err != nil {
+		respondWithErrorV2(w, r,
+			"handleEncryptionKeyRotate: RotateEncryptionKey: %v", err)
+		return
 	}
-	return r
-}
 
-func convertProofToV2(p backendv2.Proof) v2.Proof {
-	return v2.Proof{
-		Type:       p.Type,
-		Digest:     p.Digest,
-		MerkleRoot: p.MerkleRoot,
-		MerklePath: p.MerklePath,
-		ExtraData:  p.ExtraData,
+	// Prepare reply
+	response := p.identity.SignMessage(challenge)
+	er := v2.EncryptionKeyRotateReply{
+		Response: hex.EncodeToString(response[:]),
+		Keys:     convertEncryptionKeyStatusToV2(*ks),
 	}
+
+	util.RespondWithJSON(w, http.StatusOK, er)
 }
 
-func convertTimestampToV2(t backendv2.Timestamp) v2.Timestamp {
-	proofs := make([]v2.Proof, 0, len(t.Proofs))
-	for _, v := range t.Proofs {
-		proofs = append(proofs, convertProofToV2(v))
+func (p *politeia) handleEncryptionKeyStatus(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleEncryptionKeyStatus")
+
+	// Decode request
+	var e v2.EncryptionKeyStatus
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&e); err != nil {
+		respondWithErrorV2(w, r, "handleEncryptionKeyStatus: unmarshal",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeRequestPayloadInvalid,
+			})
+		return
 	}
-	return v2.Timestamp{
-		Data:       t.Data,
-		Digest:     t.Digest,
-		TxID:       t.TxID,
-		MerkleRoot: t.MerkleRoot,
-		Proofs:     proofs,
+	challenge, err := hex.DecodeString(e.Challenge)
+	if err != nil || len(challenge) != v2.ChallengeSize {
+		respondWithErrorV2(w, r, "handleEncryptionKeyStatus: decode challenge",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeChallengeInvalid,
+			})
+		return
 	}
-}
 
-func convertMetadataTimestampsToV2(metadata map[string]map[uint32]backendv2.Timestamp) map[string]map[uint32]v2.Timestamp {
-	md := make(map[string]map[uint32]v2.Timestamp, 16)
-	for pluginID, v := range metadata {
-		timestamps, ok := md[pluginID]
-		if !ok {
-			timestamps = make(map[uint32]v2.Timestamp, 16)
-		}
-		for streamID, ts := range v {
-			timestamps[streamID] = convertTimestampToV2(ts)
-		}
-		md[pluginID] = timestamps
+	// Get the encryption key status
+	ks, err := p.backendv2.EncryptionKeyStatus()
+	if err != nil {
+		respondWithErrorV2(w, r,
+			"handleEncryptionKeyStatus: EncryptionKeyStatus: %v", err)
+		return
 	}
-	return md
-}
 
-func convertFileTimestampsToV2(files map[string]backendv2.Timestamp) map[string]v2.Timestamp {
-	fs := make(map[string]v2.Timestamp, len(files))
-	for k, v := range files {
-		fs[k] = convertTimestampToV2(v)
+	// Prepare reply
+	response := p.identity.SignMessage(challenge)
+	er := v2.EncryptionKeyStatusReply{
+		Response: hex.EncodeToString(response[:]),
+		Keys:     convertEncryptionKeyStatusToV2(*ks),
 	}
-	return fs
+
+	util.RespondWithJSON(w, http.StatusOK, er)
 }
 
-func convertRecordStateToBackend(s v2.RecordStateT) backendv2.StateT {
-	switch s {
-	case v2.RecordStateUnvetted:
-		return backendv2.StateUnvetted
+func (p *politeia) handleBlobCacheStats(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleBlobCacheStats")
+
+	// Decode request
+	var bc v2.BlobCacheStats
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&bc); err != nil {
+		respondWithErrorV2(w, r, "handleBlobCacheStats: unmarshal",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeRequestPayloadInvalid,
+			})
+		return
+	}
+	challenge, err := hex.DecodeString(bc.Challenge)
+	if err != nil || len(challenge) != v2.ChallengeSize {
+		respondWithErrorV2(w, r, "handleBlobCacheStats: decode challenge",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeChallengeInvalid,
+			})
+		return
+	}
+
+	// Get the cache stats
+	s, err := p.backendv2.BlobCacheStats()
+	if err != nil {
+		respondWithErrorV2(w, r,
+			"handleBlobCacheStats: BlobCacheStats: %v", err)
+		return
+	}
+
+	// Prepare reply
+	response := p.identity.SignMessage(challenge)
+	bcr := v2.BlobCacheStatsReply{
+		Response: hex.EncodeToString(response[:]),
+		Cache:    convertBlobCacheStatsToV2(*s),
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, bcr)
+}
+
+func (p *politeia) handleAnchorStatus(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleAnchorStatus")
+
+	// Decode request
+	var as v2.AnchorStatus
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&as); err != nil {
+		respondWithErrorV2(w, r, "handleAnchorStatus: unmarshal",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeRequestPayloadInvalid,
+			})
+		return
+	}
+	challenge, err := hex.DecodeString(as.Challenge)
+	if err != nil || len(challenge) != v2.ChallengeSize {
+		respondWithErrorV2(w, r, "handleAnchorStatus: decode challenge",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeChallengeInvalid,
+			})
+		return
+	}
+
+	// Get the anchor status
+	s, err := p.backendv2.AnchorStatus()
+	if err != nil {
+		respondWithErrorV2(w, r,
+			"handleAnchorStatus: AnchorStatus: %v", err)
+		return
+	}
+
+	// Prepare reply
+	response := p.identity.SignMessage(challenge)
+	asr := v2.AnchorStatusReply{
+		Response: hex.EncodeToString(response[:]),
+		Anchors:  convertAnchorStatusToV2(*s),
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, asr)
+}
+
+func (p *politeia) handleAnchorDrop(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleAnchorDrop")
+
+	// Decode request
+	var ad v2.AnchorDrop
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&ad); err != nil {
+		respondWithErrorV2(w, r, "handleAnchorDrop: unmarshal",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeRequestPayloadInvalid,
+			})
+		return
+	}
+	challenge, err := hex.DecodeString(ad.Challenge)
+	if err != nil || len(challenge) != v2.ChallengeSize {
+		respondWithErrorV2(w, r, "handleAnchorDrop: decode challenge",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeChallengeInvalid,
+			})
+		return
+	}
+
+	// Drop an anchor. The anchor is dropped in the background.
+	s, err := p.backendv2.AnchorDrop()
+	if err != nil {
+		respondWithErrorV2(w, r,
+			"handleAnchorDrop: AnchorDrop: %v", err)
+		return
+	}
+
+	// Prepare reply
+	response := p.identity.SignMessage(challenge)
+	adr := v2.AnchorDropReply{
+		Response: hex.EncodeToString(response[:]),
+		Anchors:  convertAnchorStatusToV2(*s),
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, adr)
+}
+
+func (p *politeia) handleRecordExport(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleRecordExport")
+
+	// Decode request
+	var re v2.RecordExport
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&re); err != nil {
+		respondWithErrorV2(w, r, "handleRecordExport: unmarshal",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeRequestPayloadInvalid,
+			})
+		return
+	}
+	challenge, err := hex.DecodeString(re.Challenge)
+	if err != nil || len(challenge) != v2.ChallengeSize {
+		respondWithErrorV2(w, r, "handleRecordExport: decode challenge",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeChallengeInvalid,
+			})
+		return
+	}
+	token, err := decodeTokenAnyLength(re.Token)
+	if err != nil {
+		respondWithErrorV2(w, r, "handleRecordExport: decode token",
+			v2.UserErrorReply{
+				ErrorCode:    v2.ErrorCodeTokenInvalid,
+				ErrorContext: util.TokenRegexp(),
+			})
+		return
+	}
+
+	// Export the record
+	b, err := p.backendv2.RecordExport(token)
+	if err != nil {
+		respondWithErrorV2(w, r,
+			"handleRecordExport: RecordExport: %v", err)
+		return
+	}
+
+	// Prepare reply
+	response := p.identity.SignMessage(challenge)
+	rer := v2.RecordExportReply{
+		Response: hex.EncodeToString(response[:]),
+		Bundle:   p.convertRecordBundleToV2(*b),
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, rer)
+}
+
+func (p *politeia) handleRecordImport(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleRecordImport")
+
+	// Decode request
+	var ri v2.RecordImport
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&ri); err != nil {
+		respondWithErrorV2(w, r, "handleRecordImport: unmarshal",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeRequestPayloadInvalid,
+			})
+		return
+	}
+	challenge, err := hex.DecodeString(ri.Challenge)
+	if err != nil || len(challenge) != v2.ChallengeSize {
+		respondWithErrorV2(w, r, "handleRecordImport: decode challenge",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeChallengeInvalid,
+			})
+		return
+	}
+
+	// Import the record
+	token, err := p.backendv2.RecordImport(
+		convertRecordBundleToBackend(ri.Bundle))
+	if err != nil {
+		respondWithErrorV2(w, r,
+			"handleRecordImport: RecordImport: %v", err)
+		return
+	}
+
+	// Prepare reply
+	response := p.identity.SignMessage(challenge)
+	rir := v2.RecordImportReply{
+		Response: hex.EncodeToString(response[:]),
+		Token:    hex.EncodeToString(token),
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, rir)
+}
+
+func (p *politeia) handlePluginSettings(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handlePluginSettings")
+
+	// Decode request
+	var ps v2.PluginSettings
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&ps); err != nil {
+		respondWithErrorV2(w, r, "handlePluginSettings: unmarshal",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeRequestPayloadInvalid,
+			})
+		return
+	}
+	challenge, err := hex.DecodeString(ps.Challenge)
+	if err != nil || len(challenge) != v2.ChallengeSize {
+		respondWithErrorV2(w, r, "handlePluginSettings: decode challenge",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeChallengeInvalid,
+			})
+		return
+	}
+
+	// Get the plugin settings audit trail. This errors if the plugin
+	// ID is invalid.
+	history, err := p.backendv2.PluginSettingsHistory(ps.PluginID)
+	if err != nil {
+		respondWithErrorV2(w, r,
+			"handlePluginSettings: PluginSettingsHistory: %v", err)
+		return
+	}
+
+	// Get the current plugin settings
+	var plugin *v2.Plugin
+	for _, v := range convertPluginsToV2(p.backendv2.PluginInventory()) {
+		if v.ID == ps.PluginID {
+			plugin = &v
+			break
+		}
+	}
+	if plugin == nil {
+		respondWithErrorV2(w, r, "handlePluginSettings: plugin not found",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodePluginIDInvalid,
+			})
+		return
+	}
+
+	// Prepare reply
+	response := p.identity.SignMessage(challenge)
+	psr := v2.PluginSettingsReply{
+		Response: hex.EncodeToString(response[:]),
+		Plugin:   *plugin,
+		History:  convertPluginSettingsChangesToV2(history),
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, psr)
+}
+
+func (p *politeia) handlePluginSettingsUpdate(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handlePluginSettingsUpdate")
+
+	// Decode request
+	var psu v2.PluginSettingsUpdate
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&psu); err != nil {
+		respondWithErrorV2(w, r, "handlePluginSettingsUpdate: unmarshal",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeRequestPayloadInvalid,
+			})
+		return
+	}
+	challenge, err := hex.DecodeString(psu.Challenge)
+	if err != nil || len(challenge) != v2.ChallengeSize {
+		respondWithErrorV2(w, r, "handlePluginSettingsUpdate: decode challenge",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeChallengeInvalid,
+			})
+		return
+	}
+	if len(psu.Settings) == 0 {
+		respondWithErrorV2(w, r, "handlePluginSettingsUpdate: no settings",
+			v2.UserErrorReply{
+				ErrorCode:    v2.ErrorCodeRequestPayloadInvalid,
+				ErrorContext: "no settings provided",
+			})
+		return
+	}
+
+	// Update the plugin settings. The name of the RPC credential
+	// that made the update is recorded in the audit trail.
+	c := credentialFromContext(r.Context())
+	plugin, err := p.backendv2.PluginSettingsUpdate(psu.PluginID,
+		convertPluginSettingsToBackend(psu.Settings), psu.Reason, c.name)
+	if err != nil {
+		respondWithErrorV2(w, r,
+			"handlePluginSettingsUpdate: PluginSettingsUpdate: %v", err)
+		return
+	}
+
+	// Prepare reply
+	response := p.identity.SignMessage(challenge)
+	psur := v2.PluginSettingsUpdateReply{
+		Response: hex.EncodeToString(response[:]),
+		Plugin:   convertPluginsToV2([]backendv2.Plugin{*plugin})[0],
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, psur)
+}
+
+func (p *politeia) handleBackup(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleBackup")
+
+	// Decode request
+	var b v2.Backup
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&b); err != nil {
+		respondWithErrorV2(w, r, "handleBackup: unmarshal",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeRequestPayloadInvalid,
+			})
+		return
+	}
+	challenge, err := hex.DecodeString(b.Challenge)
+	if err != nil || len(challenge) != v2.ChallengeSize {
+		respondWithErrorV2(w, r, "handleBackup: decode challenge",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeChallengeInvalid,
+			})
+		return
+	}
+
+	// Make the backup. Writes are blocked until it has finished.
+	s, err := p.backendv2.Backup(p.cfg.BackupDir, p.backupHook)
+	if err != nil {
+		respondWithErrorV2(w, r,
+			"handleBackup: Backup: %v", err)
+		return
+	}
+
+	log.Infof("Backup %v saved to %v: %v trees, %v blobs, %v files",
+		s.Name, s.Path, s.Trees, s.Blobs, s.Files)
+
+	// Prepare reply
+	response := p.identity.SignMessage(challenge)
+	br := v2.BackupReply{
+		Response:  hex.EncodeToString(response[:]),
+		Name:      s.Name,
+		Timestamp: s.Timestamp,
+		Trees:     s.Trees,
+		Blobs:     s.Blobs,
+		Files:     s.Files,
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, br)
+}
+
+// backupHook runs the configured backup hook command using the provided
+// backup directory as its only argument. The backend runs the hook while
+// writes are blocked so that data that is not managed by the backend, such as
+// the trillian database, can be saved at the same point in time as the rest
+// of the backup.
+func (p *politeia) backupHook(backupDir string) error {
+	if p.cfg.BackupHook == "" {
+		return nil
+	}
+	log.Infof("Running backup hook: %v %v", p.cfg.BackupHook, backupDir)
+	out, err := exec.Command(p.cfg.BackupHook, backupDir).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("backup hook %v: %v: %s",
+			p.cfg.BackupHook, err, out)
+	}
+	return nil
+}
+
+func (p *politeia) handleCacheRebuild(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleCacheRebuild")
+
+	// Decode request
+	var cr v2.CacheRebuild
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&cr); err != nil {
+		respondWithErrorV2(w, r, "handleCacheRebuild: unmarshal",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeRequestPayloadInvalid,
+			})
+		return
+	}
+	challenge, err := hex.DecodeString(cr.Challenge)
+	if err != nil || len(challenge) != v2.ChallengeSize {
+		respondWithErrorV2(w, r, "handleCacheRebuild: decode challenge",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeChallengeInvalid,
+			})
+		return
+	}
+
+	// Start the cache rebuild. The caches are rebuilt in the
+	// background.
+	s, err := p.backendv2.CacheRebuild(cr.PluginIDs)
+	if err != nil {
+		respondWithErrorV2(w, r,
+			"handleCacheRebuild: CacheRebuild: %v", err)
+		return
+	}
+
+	// Prepare reply
+	response := p.identity.SignMessage(challenge)
+	crr := v2.CacheRebuildReply{
+		Response: hex.EncodeToString(response[:]),
+		Rebuild:  convertCacheRebuildStatusToV2(*s),
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, crr)
+}
+
+func (p *politeia) handleCacheRebuildStatus(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("handleCacheRebuildStatus")
+
+	// Decode request
+	var cs v2.CacheRebuildStatus
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&cs); err != nil {
+		respondWithErrorV2(w, r, "handleCacheRebuildStatus: unmarshal",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeRequestPayloadInvalid,
+			})
+		return
+	}
+	challenge, err := hex.DecodeString(cs.Challenge)
+	if err != nil || len(challenge) != v2.ChallengeSize {
+		respondWithErrorV2(w, r, "handleCacheRebuildStatus: decode challenge",
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeChallengeInvalid,
+			})
+		return
+	}
+
+	// Get the cache rebuild status
+	s, err := p.backendv2.CacheRebuildStatus()
+	if err != nil {
+		respondWithErrorV2(w, r,
+			"handleCacheRebuildStatus: CacheRebuildStatus: %v", err)
+		return
+	}
+
+	// Prepare reply
+	response := p.identity.SignMessage(challenge)
+	csr := v2.CacheRebuildStatusReply{
+		Response: hex.EncodeToString(response[:]),
+		Rebuild:  convertCacheRebuildStatusToV2(*s),
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, csr)
+}
+
+// decodeToken decodes a v2 token and errors if the token is not the full
+// length token.
+func decodeToken(token string) ([]byte, error) {
+	return util.TokenDecode(util.TokenTypeTstore, token)
+}
+
+// decodeTokenAnyLength decodes a v2 token. It accepts both the full length
+// token and the short token.
+func decodeTokenAnyLength(token string) ([]byte, error) {
+	return util.TokenDecodeAnyLength(util.TokenTypeTstore, token)
+}
+
+func (p *politeia) convertRecordToV2(r backendv2.Record) v2.Record {
+	var (
+		metadata = convertMetadataStreamsToV2(r.Metadata)
+		files    = convertFilesToV2(r.Files)
+		rm       = r.RecordMetadata
+		sig      = p.identity.SignMessage([]byte(rm.Merkle + rm.Token))
+	)
+	return v2.Record{
+		State:     v2.RecordStateT(rm.State),
+		Status:    v2.RecordStatusT(rm.Status),
+		Version:   rm.Version,
+		Timestamp: rm.Timestamp,
+		Metadata:  metadata,
+		Files:     files,
+		CensorshipRecord: v2.CensorshipRecord{
+			Token:     rm.Token,
+			Merkle:    rm.Merkle,
+			Signature: hex.EncodeToString(sig[:]),
+		},
+	}
+}
+
+func convertMetadataStreamsToBackend(metadata []v2.MetadataStream) []backendv2.MetadataStream {
+	ms := make([]backendv2.MetadataStream, 0, len(metadata))
+	for _, v := range metadata {
+		ms = append(ms, backendv2.MetadataStream{
+			PluginID: v.PluginID,
+			StreamID: v.StreamID,
+			Payload:  v.Payload,
+		})
+	}
+	return ms
+}
+
+func convertMetadataStreamsToV2(metadata []backendv2.MetadataStream) []v2.MetadataStream {
+	ms := make([]v2.MetadataStream, 0, len(metadata))
+	for _, v := range metadata {
+		ms = append(ms, v2.MetadataStream{
+			PluginID: v.PluginID,
+			StreamID: v.StreamID,
+			Payload:  v.Payload,
+		})
+	}
+	return ms
+}
+
+func convertFilesToBackend(files []v2.File) []backendv2.File {
+	fs := make([]backendv2.File, 0, len(files))
+	for _, v := range files {
+		fs = append(fs, backendv2.File{
+			Name:    v.Name,
+			MIME:    v.MIME,
+			Digest:  v.Digest,
+			Payload: v.Payload,
+		})
+	}
+	return fs
+}
+
+func convertFilesToV2(files []backendv2.File) []v2.File {
+	fs := make([]v2.File, 0, len(files))
+	for _, v := range files {
+		fs = append(fs, v2.File{
+			Name:    v.Name,
+			MIME:    v.MIME,
+			Digest:  v.Digest,
+			Payload: v.Payload,
+		})
+	}
+	return fs
+}
+
+func convertRecordRequestsToBackend(reqs []v2.RecordRequest) []backendv2.RecordRequest {
+	r := make([]backendv2.RecordRequest, 0, len(reqs))
+	for _, v := range reqs {
+		token, err := decodeTokenAnyLength(v.Token)
+		if err != nil {
+			// Records with errors will not be included in the reply
+			log.Debugf("convertRecordRequestsToBackend: decode token: %v", err)
+			continue
+		}
+		r = append(r, backendv2.RecordRequest{
+			Token:        token,
+			Version:      v.Version,
+			Filenames:    v.Filenames,
+			OmitAllFiles: v.OmitAllFiles,
+		})
+	}
+	return r
+}
+
+func convertProofToV2(p backendv2.Proof) v2.Proof {
+	return v2.Proof{
+		Type:       p.Type,
+		Digest:     p.Digest,
+		MerkleRoot: p.MerkleRoot,
+		MerklePath: p.MerklePath,
+		ExtraData:  p.ExtraData,
+	}
+}
+
+func convertTimestampToV2(t backendv2.Timestamp) v2.Timestamp {
+	proofs := make([]v2.Proof, 0, len(t.Proofs))
+	for _, v := range t.Proofs {
+		proofs = append(proofs, convertProofToV2(v))
+	}
+	return v2.Timestamp{
+		Data:       t.Data,
+		Digest:     t.Digest,
+		TxID:       t.TxID,
+		MerkleRoot: t.MerkleRoot,
+		Proofs:     proofs,
+	}
+}
+
+func convertMetadataTimestampsToV2(metadata map[string]map[uint32]backendv2.Timestamp) map[string]map[uint32]v2.Timestamp {
+	md := make(map[string]map[uint32]v2.Timestamp, 16)
+	for pluginID, v := range metadata {
+		timestamps, ok := md[pluginID]
+		if !ok {
+			timestamps = make(map[uint32]v2.Timestamp, 16)
+		}
+		for streamID, ts := range v {
+			timestamps[streamID] = convertTimestampToV2(ts)
+		}
+		md[pluginID] = timestamps
+	}
+	return md
+}
+
+func convertFileTimestampsToV2(files map[string]backendv2.Timestamp) map[string]v2.Timestamp {
+	fs := make(map[string]v2.Timestamp, len(files))
+	for k, v := range files {
+		fs[k] = convertTimestampToV2(v)
+	}
+	return fs
+}
+
+func convertDiffVersionToV2(rm backendv2.RecordMetadata) v2.DiffVersion {
+	return v2.DiffVersion{
+		State:     v2.RecordStateT(rm.State),
+		Status:    v2.RecordStatusT(rm.Status),
+		Version:   rm.Version,
+		Timestamp: rm.Timestamp,
+	}
+}
+
+func convertDiffHunksToV2(hunks []backendv2.DiffHunk) []v2.DiffHunk {
+	h := make([]v2.DiffHunk, 0, len(hunks))
+	for _, v := range hunks {
+		lines := make([]v2.DiffLine, 0, len(v.Lines))
+		for _, l := range v.Lines {
+			lines = append(lines, v2.DiffLine{
+				Type: v2.DiffT(l.Type),
+				Text: l.Text,
+			})
+		}
+		h = append(h, v2.DiffHunk{
+			FromLine:  v.FromLine,
+			FromLines: v.FromLines,
+			ToLine:    v.ToLine,
+			ToLines:   v.ToLines,
+			Lines:     lines,
+		})
+	}
+	return h
+}
+
+func convertFileDiffsToV2(files []backendv2.FileDiff) []v2.FileDiff {
+	fd := make([]v2.FileDiff, 0, len(files))
+	for _, v := range files {
+		fd = append(fd, v2.FileDiff{
+			Name:       v.Name,
+			Type:       v2.DiffT(v.Type),
+			MIME:       v.MIME,
+			FromDigest: v.FromDigest,
+			ToDigest:   v.ToDigest,
+			Hunks:      convertDiffHunksToV2(v.Hunks),
+		})
+	}
+	return fd
+}
+
+func convertMetadataStreamDiffsToV2(metadata []backendv2.MetadataStreamDiff) []v2.MetadataStreamDiff {
+	md := make([]v2.MetadataStreamDiff, 0, len(metadata))
+	for _, v := range metadata {
+		md = append(md, v2.MetadataStreamDiff{
+			PluginID: v.PluginID,
+			StreamID: v.StreamID,
+			Type:     v2.DiffT(v.Type),
+			Hunks:    convertDiffHunksToV2(v.Hunks),
+		})
+	}
+	return md
+}
+
+func convertRecordStateToBackend(s v2.RecordStateT) backendv2.StateT {
+	switch s {
+	case v2.RecordStateUnvetted:
+		return backendv2.StateUnvetted
 	case v2.RecordStateVetted:
 		return backendv2.StateVetted
 	}
@@ -840,6 +1600,72 @@ func convertRecordStatusToBackend(s v2.RecordStatusT) backendv2.StatusT {
 	return backendv2.StatusInvalid
 }
 
+func convertInventoryQueryToBackend(q v2.InventoryQuery) (*backendv2.InventoryQuery, error) {
+	var (
+		state  backendv2.StateT
+		status backendv2.StatusT
+		sort   backendv2.InventorySortT
+		limit  = q.Limit
+	)
+	if q.State != v2.RecordStateInvalid {
+		state = convertRecordStateToBackend(q.State)
+		if state == backendv2.StateInvalid {
+			return nil, v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeRecordStateInvalid,
+			}
+		}
+	}
+	if q.Status != v2.RecordStatusInvalid {
+		status = convertRecordStatusToBackend(q.Status)
+		if status == backendv2.StatusInvalid {
+			return nil, v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodeRecordStatusInvalid,
+			}
+		}
+	}
+	switch q.Sort {
+	case v2.InventorySortUpdated:
+		sort = backendv2.InventorySortUpdated
+	case v2.InventorySortCreated:
+		sort = backendv2.InventorySortCreated
+	default:
+		return nil, v2.UserErrorReply{
+			ErrorCode:    v2.ErrorCodeInventoryQueryInvalid,
+			ErrorContext: fmt.Sprintf("invalid sort %v", q.Sort),
+		}
+	}
+	switch {
+	case limit == 0:
+		limit = v2.InventoryQueryPageSize
+	case limit > v2.InventoryQueryPageSize:
+		return nil, v2.UserErrorReply{
+			ErrorCode: v2.ErrorCodePageSizeExceeded,
+			ErrorContext: fmt.Sprintf("max page size is %v",
+				v2.InventoryQueryPageSize),
+		}
+	}
+	return &backendv2.InventoryQuery{
+		State:      state,
+		Status:     status,
+		Attributes: q.Attributes,
+		Sort:       sort,
+		Ascending:  q.Ascending,
+		Cursor:     q.Cursor,
+		Limit:      limit,
+	}, nil
+}
+
+func convertInventoryEntryToV2(e backendv2.InventoryEntry) v2.InventoryEntry {
+	return v2.InventoryEntry{
+		Token:      e.Token,
+		State:      v2.RecordStateT(e.State),
+		Status:     v2.RecordStatusT(e.Status),
+		Created:    e.Created,
+		Updated:    e.Updated,
+		Attributes: e.Attributes,
+	}
+}
+
 func convertPluginSettingToV2(p backendv2.PluginSetting) v2.PluginSetting {
 	return v2.PluginSetting{
 		Key:   p.Key,
@@ -862,6 +1688,259 @@ func convertPluginsToV2(bplugins []backendv2.Plugin) []v2.Plugin {
 	return plugins
 }
 
+func convertPluginSettingsToBackend(settings []v2.PluginSetting) []backendv2.PluginSetting {
+	s := make([]backendv2.PluginSetting, 0, len(settings))
+	for _, v := range settings {
+		s = append(s, backendv2.PluginSetting{
+			Key:   v.Key,
+			Value: v.Value,
+		})
+	}
+	return s
+}
+
+func convertPluginSettingsChangesToV2(changes []backendv2.PluginSettingsChange) []v2.PluginSettingsChange {
+	c := make([]v2.PluginSettingsChange, 0, len(changes))
+	for _, v := range changes {
+		settings := make([]v2.PluginSetting, 0, len(v.Settings))
+		for _, v := range v.Settings {
+			settings = append(settings, convertPluginSettingToV2(v))
+		}
+		previous := make([]v2.PluginSetting, 0, len(v.Previous))
+		for _, v := range v.Previous {
+			previous = append(previous, convertPluginSettingToV2(v))
+		}
+		c = append(c, v2.PluginSettingsChange{
+			Settings:  settings,
+			Previous:  previous,
+			Reason:    v.Reason,
+			User:      v.User,
+			Timestamp: v.Timestamp,
+		})
+	}
+	return c
+}
+
+func convertFsckIssuesToV2(issues []backendv2.FsckIssue) []v2.FsckIssue {
+	fi := make([]v2.FsckIssue, 0, len(issues))
+	for _, v := range issues {
+		fi = append(fi, v2.FsckIssue{
+			Type:        backendv2.FsckIssues[v.Type],
+			Token:       v.Token,
+			Description: v.Description,
+			Repaired:    v.Repaired,
+		})
+	}
+	return fi
+}
+
+func convertEncryptionKeyStatusToV2(s backendv2.EncryptionKeyStatus) v2.EncryptionKeys {
+	return v2.EncryptionKeys{
+		ActiveKeyID:  s.ActiveKeyID,
+		KeyIDs:       s.KeyIDs,
+		Reencrypting: s.Reencrypting,
+		Reencrypted:  s.Reencrypted,
+	}
+}
+
+func convertCacheRebuildStatusToV2(s backendv2.CacheRebuildStatus) v2.CacheRebuildState {
+	plugins := make([]v2.PluginCacheRebuild, 0, len(s.Plugins))
+	for _, v := range s.Plugins {
+		plugins = append(plugins, v2.PluginCacheRebuild{
+			PluginID: v.PluginID,
+			Records:  v.Records,
+			Rebuilt:  v.Rebuilt,
+			Done:     v.Done,
+			Error:    v.Error,
+		})
+	}
+	return v2.CacheRebuildState{
+		InProgress: s.InProgress,
+		Started:    s.Started,
+		Finished:   s.Finished,
+		Plugins:    plugins,
+	}
+}
+
+func convertBlobCacheStatsToV2(s backendv2.BlobCacheStats) v2.BlobCache {
+	return v2.BlobCache{
+		Enabled:   s.Enabled,
+		Hits:      s.Hits,
+		Misses:    s.Misses,
+		Evictions: s.Evictions,
+		Entries:   s.Entries,
+		Size:      s.Size,
+		MaxSize:   s.MaxSize,
+	}
+}
+
+func convertAnchorStatusToV2(s backendv2.AnchorStatus) v2.Anchors {
+	pending := make([]v2.AnchorTree, 0, len(s.Pending))
+	for _, v := range s.Pending {
+		pending = append(pending, v2.AnchorTree{
+			Token:        v.Token,
+			Leaves:       v.Leaves,
+			AnchoredSize: v.AnchoredSize,
+			Anchored:     v.Anchored,
+		})
+	}
+	drops := make([]v2.Anchor, 0, len(s.Drops))
+	for _, v := range s.Drops {
+		drops = append(drops, v2.Anchor{
+			Status:      backendv2.AnchorDrops[v.Status],
+			Trees:       v.Trees,
+			Anchored:    v.Anchored,
+			MerkleRoots: v.MerkleRoots,
+			TxIDs:       v.TxIDs,
+			Attempts:    v.Attempts,
+			MaxAttempts: v.MaxAttempts,
+			Started:     v.Started,
+			LastCheck:   v.LastCheck,
+			Completed:   v.Completed,
+			Error:       v.Error,
+		})
+	}
+	return v2.Anchors{
+		Type:      s.Type,
+		Schedule:  s.Schedule,
+		NextDrop:  s.NextDrop,
+		Dropping:  s.Dropping,
+		LastRun:   s.LastRun,
+		LastError: s.LastError,
+		Pending:   pending,
+		Drops:     drops,
+	}
+}
+
+func convertRecordToBackend(r v2.Record) backendv2.Record {
+	return backendv2.Record{
+		RecordMetadata: backendv2.RecordMetadata{
+			Token:     r.CensorshipRecord.Token,
+			Version:   r.Version,
+			State:     convertRecordStateToBackend(r.State),
+			Status:    convertRecordStatusToBackend(r.Status),
+			Timestamp: r.Timestamp,
+			Merkle:    r.CensorshipRecord.Merkle,
+		},
+		Metadata: convertMetadataStreamsToBackend(r.Metadata),
+		Files:    convertFilesToBackend(r.Files),
+	}
+}
+
+func convertProofToBackend(p v2.Proof) backendv2.Proof {
+	return backendv2.Proof{
+		Type:       p.Type,
+		Digest:     p.Digest,
+		MerkleRoot: p.MerkleRoot,
+		MerklePath: p.MerklePath,
+		ExtraData:  p.ExtraData,
+	}
+}
+
+func convertTimestampToBackend(t v2.Timestamp) backendv2.Timestamp {
+	proofs := make([]backendv2.Proof, 0, len(t.Proofs))
+	for _, v := range t.Proofs {
+		proofs = append(proofs, convertProofToBackend(v))
+	}
+	return backendv2.Timestamp{
+		Data:       t.Data,
+		Digest:     t.Digest,
+		TxID:       t.TxID,
+		MerkleRoot: t.MerkleRoot,
+		Proofs:     proofs,
+	}
+}
+
+func convertRecordTimestampsToBackend(t v2.BundleTimestamps) backendv2.RecordTimestamps {
+	md := make(map[string]map[uint32]backendv2.Timestamp, len(t.Metadata))
+	for pluginID, v := range t.Metadata {
+		timestamps := make(map[uint32]backendv2.Timestamp, len(v))
+		for streamID, ts := range v {
+			timestamps[streamID] = convertTimestampToBackend(ts)
+		}
+		md[pluginID] = timestamps
+	}
+	fs := make(map[string]backendv2.Timestamp, len(t.Files))
+	for k, v := range t.Files {
+		fs[k] = convertTimestampToBackend(v)
+	}
+	return backendv2.RecordTimestamps{
+		RecordMetadata: convertTimestampToBackend(t.RecordMetadata),
+		Metadata:       md,
+		Files:          fs,
+	}
+}
+
+func (p *politeia) convertRecordBundleToV2(b backendv2.RecordBundle) v2.RecordBundle {
+	records := make([]v2.Record, 0, len(b.Records))
+	for _, v := range b.Records {
+		records = append(records, p.convertRecordToV2(v))
+	}
+	timestamps := make(map[uint32]v2.BundleTimestamps, len(b.Timestamps))
+	for version, v := range b.Timestamps {
+		timestamps[version] = v2.BundleTimestamps{
+			RecordMetadata: convertTimestampToV2(v.RecordMetadata),
+			Metadata:       convertMetadataTimestampsToV2(v.Metadata),
+			Files:          convertFileTimestampsToV2(v.Files),
+		}
+	}
+	blobs := make([]v2.BundleBlob, 0, len(b.Blobs))
+	for _, v := range b.Blobs {
+		blobs = append(blobs, v2.BundleBlob{
+			LeafIndex:      v.LeafIndex,
+			Digest:         v.Digest,
+			DataDescriptor: v.DataDescriptor,
+			State:          v2.RecordStateT(v.State),
+			PlainText:      v.PlainText,
+			Deleted:        v.Deleted,
+			DataHint:       v.DataHint,
+			Data:           v.Data,
+		})
+	}
+	return v2.RecordBundle{
+		Version:         b.Version,
+		ServerPublicKey: p.identity.Public.String(),
+		Token:           b.Token,
+		Timestamp:       b.Timestamp,
+		Records:         records,
+		Timestamps:      timestamps,
+		Blobs:           blobs,
+	}
+}
+
+func convertRecordBundleToBackend(b v2.RecordBundle) backendv2.RecordBundle {
+	records := make([]backendv2.Record, 0, len(b.Records))
+	for _, v := range b.Records {
+		records = append(records, convertRecordToBackend(v))
+	}
+	timestamps := make(map[uint32]backendv2.RecordTimestamps,
+		len(b.Timestamps))
+	for version, v := range b.Timestamps {
+		timestamps[version] = convertRecordTimestampsToBackend(v)
+	}
+	blobs := make([]backendv2.BundleBlob, 0, len(b.Blobs))
+	for _, v := range b.Blobs {
+		blobs = append(blobs, backendv2.BundleBlob{
+			LeafIndex:      v.LeafIndex,
+			Digest:         v.Digest,
+			DataDescriptor: v.DataDescriptor,
+			State:          convertRecordStateToBackend(v.State),
+			PlainText:      v.PlainText,
+			Deleted:        v.Deleted,
+			DataHint:       v.DataHint,
+			Data:           v.Data,
+		})
+	}
+	return backendv2.RecordBundle{
+		Version:    b.Version,
+		Token:      b.Token,
+		Timestamp:  b.Timestamp,
+		Records:    records,
+		Timestamps: timestamps,
+		Blobs:      blobs,
+	}
+}
+
 func respondWithErrorV2(w http.ResponseWriter, r *http.Request, format string, err error) {
 	var (
 		errCode = convertErrorToV2(err)
@@ -869,6 +1948,7 @@ func respondWithErrorV2(w http.ResponseWriter, r *http.Request, format string, e
 		ce      backendv2.ContentError
 		ste     backendv2.StatusTransitionError
 		pe      backendv2.PluginError
+		pse     backendv2.PluginSettingError
 	)
 	switch {
 	case errCode != v2.ErrorCodeInvalid:
@@ -933,6 +2013,17 @@ func respondWithErrorV2(w http.ResponseWriter, r *http.Request, format string, e
 				ErrorContext: pe.ErrorContext,
 			})
 		return
+
+	case errors.As(err, &pse):
+		// Plugin setting error
+		log.Infof("%v User error: %v", util.RemoteAddr(r), pse.Error())
+		util.RespondWithJSON(w, http.StatusBadRequest,
+			v2.UserErrorReply{
+				ErrorCode: v2.ErrorCodePluginSettingInvalid,
+				ErrorContext: fmt.Sprintf("%v '%v': %v",
+					pse.Key, pse.Value, pse.Reason),
+			})
+		return
 	}
 
 	// Internal server error. Log it and return a 500.
@@ -962,6 +2053,18 @@ func convertErrorToV2(e error) v2.ErrorCodeT {
 		return v2.ErrorCodePluginIDInvalid
 	case backendv2.ErrPluginCmdInvalid:
 		return v2.ErrorCodePluginCmdInvalid
+	case backendv2.ErrInventoryQueryInvalid:
+		return v2.ErrorCodeInventoryQueryInvalid
+	case backendv2.ErrAnchorDropInProgress:
+		return v2.ErrorCodeAnchorDropInProgress
+	case backendv2.ErrEventResumeInvalid:
+		return v2.ErrorCodeEventResumeInvalid
+	case backendv2.ErrEventResumeExpired:
+		return v2.ErrorCodeEventResumeExpired
+	case backendv2.ErrBackupInProgress:
+		return v2.ErrorCodeBackupInProgress
+	case backendv2.ErrCacheRebuildInProgress:
+		return v2.ErrorCodeCacheRebuildInProgress
 	}
 	return v2.ErrorCodeInvalid
 }
@@ -986,6 +2089,8 @@ func convertContentErrorToV2(e backendv2.ContentErrorCodeT) v2.ErrorCodeT {
 		return v2.ErrorCodeFileMIMETypeInvalid
 	case backendv2.ContentErrorFileMIMETypeUnsupported:
 		return v2.ErrorCodeFileMIMETypeUnsupported
+	case backendv2.ContentErrorBundleInvalid:
+		return v2.ErrorCodeBundleInvalid
 	}
 	return v2.ErrorCodeInvalid
 }
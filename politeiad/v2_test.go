@@ -0,0 +1,116 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/decred/politeia/politeiad/api/v1/identity"
+	v2 "github.com/decred/politeia/politeiad/api/v2"
+	backendv2 "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/client"
+	"github.com/gorilla/mux"
+)
+
+// testBlobCacheBackend is a backendv2 Backend that returns fixed blob cache
+// stats. Calling any other method panics.
+type testBlobCacheBackend struct {
+	backendv2.Backend
+	stats backendv2.BlobCacheStats
+}
+
+// BlobCacheStats returns the blob cache stats of the backend.
+//
+// This function satisfies the backendv2 Backend interface.
+func (b *testBlobCacheBackend) BlobCacheStats() (*backendv2.BlobCacheStats, error) {
+	s := b.stats
+	return &s, nil
+}
+
+func TestBlobCacheStats(t *testing.T) {
+	cleanup := newTestLogRotator(t)
+	defer cleanup()
+
+	id, err := identity.New()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats := backendv2.BlobCacheStats{
+		Enabled:   true,
+		Hits:      10,
+		Misses:    4,
+		Evictions: 1,
+		Entries:   3,
+		Size:      512,
+		MaxSize:   1024,
+	}
+	p := &politeia{
+		router:    mux.NewRouter(),
+		backendv2: &testBlobCacheBackend{stats: stats},
+		identity:  id,
+		creds:     newTestRPCCredentials(t),
+	}
+	p.addRouteV2(http.MethodPost, v2.RouteBlobCacheStats,
+		p.handleBlobCacheStats, permissionAdmin)
+
+	// Setup a TLS server and save its certificate so that the client
+	// can verify it.
+	s := httptest.NewTLSServer(p.router)
+	defer s.Close()
+	dir, err := ioutil.TempDir("", "politeiad.test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	certFile := filepath.Join(dir, "rpc.cert")
+	cert := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: s.Certificate().Raw,
+	})
+	err = ioutil.WriteFile(certFile, cert, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The stats require admin privileges
+	pid := &id.Public
+	c, err := client.New(s.URL, certFile, "reader", "readerpass", pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = c.BlobCacheStats(context.Background())
+	if err == nil {
+		t.Fatalf("got nil error, want forbidden error")
+	}
+
+	// Read the stats using an admin credential
+	c, err = client.New(s.URL, certFile, "admin", "adminpass", pid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bc, err := c.BlobCacheStats(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := v2.BlobCache{
+		Enabled:   true,
+		Hits:      10,
+		Misses:    4,
+		Evictions: 1,
+		Entries:   3,
+		Size:      512,
+		MaxSize:   1024,
+	}
+	if *bc != want {
+		t.Fatalf("got %+v, want %+v", *bc, want)
+	}
+}
@@ -62,4 +62,16 @@ type VoteMetadata struct {
 	// It is set when a proposal is being submitted as a vote options
 	// in the runoff vote.
 	LinkTo string `json:"linkto,omitempty"`
+
+	// Budget is the total budget of a runoff vote in USD cents. It can
+	// only be set on a runoff vote parent proposal. A runoff vote with
+	// a budget can have multiple winners. The submissions that pass the
+	// vote are funded in order of net approval until the budget has
+	// been exhausted.
+	Budget uint64 `json:"budget,omitempty"`
+
+	// Amount is the amount in USD cents that a runoff vote submission
+	// is requesting from the runoff vote budget. It must be set on the
+	// submissions of a runoff vote with a budget.
+	Amount uint64 `json:"amount,omitempty"`
 }
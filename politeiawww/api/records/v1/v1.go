@@ -16,9 +16,11 @@ const (
 	RouteSetStatus        = "/setstatus"
 	RouteDetails          = "/details"
 	RouteTimestamps       = "/timestamps"
+	RouteDiff             = "/diff"
 	RouteRecords          = "/records"
 	RouteInventory        = "/inventory"
 	RouteInventoryOrdered = "/inventoryordered"
+	RouteSearch           = "/search"
 
 	// Metadata routes
 	RouteUserRecords = "/userrecords"
@@ -364,6 +366,145 @@ type TimestampsReply struct {
 	Files map[string]Timestamp `json:"files"`
 }
 
+// TimestampsBundle is an export of the timestamps of a record version that
+// can be verified offline. It contains the timestamps along with the decred
+// chain data of every anchor transaction that the timestamps reference, so
+// that verifying the bundle does not require access to politeia or to a block
+// explorer.
+//
+// The trillian proofs of the timestamps contain the signed log roots that the
+// proofs resolve to.
+type TimestampsBundle struct {
+	Timestamps TimestampsReply `json:"timestamps"`
+	Anchors    []AnchorChain   `json:"anchors"`
+}
+
+// AnchorChain contains the decred chain data for an anchor transaction.
+//
+// Block is the serialized block that includes the anchor transaction. It is
+// used to verify that the transaction commits to the anchored merkle root and
+// that the transaction was included in the block. Headers contains the
+// serialized headers of the blocks that were mined on top of the anchor block,
+// ordered by height. The headers are used to verify the proof of work that
+// has been done on top of the anchor block. All fields are hex encoded.
+type AnchorChain struct {
+	TxID    string   `json:"txid"`
+	Height  uint32   `json:"height"`
+	Block   string   `json:"block"`
+	Headers []string `json:"headers"`
+}
+
+// DiffT represents the type of change that is described by a record diff.
+type DiffT uint32
+
+const (
+	// DiffInvalid is an invalid diff type.
+	DiffInvalid DiffT = 0
+
+	// DiffUnchanged indicates that a line was not changed. It is only
+	// used for the lines of a diff hunk.
+	DiffUnchanged DiffT = 1
+
+	// DiffAdded indicates that a file, metadata stream, or line was
+	// added.
+	DiffAdded DiffT = 2
+
+	// DiffRemoved indicates that a file, metadata stream, or line was
+	// removed.
+	DiffRemoved DiffT = 3
+
+	// DiffModified indicates that a file or metadata stream was
+	// modified.
+	DiffModified DiffT = 4
+
+	// DiffLast unit test only.
+	DiffLast DiffT = 5
+)
+
+var (
+	// Diffs contains the human readable diff types.
+	Diffs = map[DiffT]string{
+		DiffInvalid:   "invalid",
+		DiffUnchanged: "unchanged",
+		DiffAdded:     "added",
+		DiffRemoved:   "removed",
+		DiffModified:  "modified",
+	}
+)
+
+// DiffLine is a single line of a diff hunk.
+type DiffLine struct {
+	Type DiffT  `json:"type"`
+	Text string `json:"text"`
+}
+
+// DiffHunk is a contiguous section of changed lines along with up to three
+// unchanged lines before and after them. FromLine and ToLine are the 1-based
+// line numbers of the first line of the hunk in the old and new text.
+// FromLines and ToLines are the number of lines of the hunk that are part of
+// the old and new text.
+type DiffHunk struct {
+	FromLine  uint32     `json:"fromline"`
+	FromLines uint32     `json:"fromlines"`
+	ToLine    uint32     `json:"toline"`
+	ToLines   uint32     `json:"tolines"`
+	Lines     []DiffLine `json:"lines"`
+}
+
+// FileDiff describes the changes made to a record file. Hunks contains a line
+// level diff of the file content and is only populated for text/plain files.
+// The digest of a file is empty if the file does not exist in that version.
+type FileDiff struct {
+	Name       string     `json:"name"`
+	Type       DiffT      `json:"type"`
+	MIME       string     `json:"mime"`
+	FromDigest string     `json:"fromdigest,omitempty"`
+	ToDigest   string     `json:"todigest,omitempty"`
+	Hunks      []DiffHunk `json:"hunks,omitempty"`
+}
+
+// MetadataStreamDiff describes the changes made to a record metadata stream.
+// Each JSON value in the metadata stream payload is treated as a line of the
+// diff.
+type MetadataStreamDiff struct {
+	PluginID string     `json:"pluginid"`
+	StreamID uint32     `json:"streamid"`
+	Type     DiffT      `json:"type"`
+	Hunks    []DiffHunk `json:"hunks"`
+}
+
+// DiffVersion contains the state and status of a record version that is part
+// of a record diff.
+type DiffVersion struct {
+	State     RecordStateT  `json:"state"`
+	Status    RecordStatusT `json:"status"`
+	Version   uint32        `json:"version"`
+	Timestamp int64         `json:"timestamp"` // Last update
+}
+
+// Diff requests the changes between two versions of a record. The from
+// version is required. If the to version is omitted, the most recent version
+// of the record is used.
+type Diff struct {
+	Token string `json:"token"`
+	From  uint32 `json:"from"`
+	To    uint32 `json:"to,omitempty"`
+}
+
+// DiffReply is the reply to the Diff command. Only the files and metadata
+// streams that changed are included. StatusChanges contains the record status
+// changes that occurred between the two versions.
+//
+// Unvetted record files are only returned to admins and the record author.
+// Files will be empty for all other users.
+type DiffReply struct {
+	From          DiffVersion          `json:"from"`
+	To            DiffVersion          `json:"to"`
+	Files         []FileDiff           `json:"files"`
+	Metadata      []MetadataStreamDiff `json:"metadata"`
+	StatusChanges []StatusChange       `json:"statuschanges"`
+}
+
 const (
 	// RecordsPageSize is the maximum number of records that can be
 	// requested in a Records request.
@@ -440,6 +581,36 @@ type InventoryOrderedReply struct {
 	Tokens []string `json:"tokens"`
 }
 
+// Search searches the text content of records for the provided query and
+// returns a page of the records that matched, ranked by relevance. Records
+// that contain a query term in their name are ranked higher than records that
+// only contain the term in their text. Censored records are never returned.
+// Unvetted records will only be returned to admins.
+//
+// Pages start at 1. A page number of 0 returns the first page. The page size
+// is set by the politeiad search plugin.
+type Search struct {
+	Query string       `json:"query"`
+	State RecordStateT `json:"state"`
+	Page  uint32       `json:"page,omitempty"`
+}
+
+// SearchResult is a record that matched a search query. A higher score means
+// that the record is more relevant to the query.
+type SearchResult struct {
+	Token     string  `json:"token"`
+	Name      string  `json:"name,omitempty"`
+	Score     float64 `json:"score"`
+	Timestamp int64   `json:"timestamp"` // Last updated
+}
+
+// SearchReply is the reply to the Search command. Total is the total number
+// of records that matched the query.
+type SearchReply struct {
+	Results []SearchResult `json:"results"`
+	Total   uint32         `json:"total"`
+}
+
 // UserRecords requests the tokens of all records submitted by a user. Unvetted
 // record tokens are only returned to admins and the record author.
 type UserRecords struct {
@@ -23,4 +23,8 @@ func TestMaps(t *testing.T) {
 	if err != nil {
 		t.Fatalf("RecordStatuses: %v", err)
 	}
+	err = unittest.TestGenericConstMap(Diffs, uint64(DiffLast))
+	if err != nil {
+		t.Fatalf("Diffs: %v", err)
+	}
 }
@@ -14,6 +14,8 @@ const (
 	RouteAuthorize   = "/authorize"
 	RouteStart       = "/start"
 	RouteCastBallot  = "/castballot"
+	RouteReveal      = "/reveal"
+	RouteCancel      = "/cancel"
 	RouteDetails     = "/details"
 	RouteResults     = "/results"
 	RouteSummaries   = "/summaries"
@@ -162,16 +164,32 @@ const (
 	// net yes votes.
 	VoteTypeRunoff VoteT = 2
 
+	// VoteTypeMultipleChoice is used to indicate a vote that has
+	// between 2 and VoteOptionsMax custom vote options. The winner is
+	// the vote option with the most votes, provided that the quorum
+	// requirement has been met. The quorum is calculated using the
+	// votes cast for all vote options. If a pass percentage is
+	// specified, the winning vote option must also receive at least
+	// the pass percentage of the total votes. A pass percentage of 0
+	// results in a plurality vote. A tie for the most votes results in
+	// no winner. Multiple choice votes finish with the
+	// VoteStatusFinished status and the winning vote option is
+	// reported in the vote summary. Multiple choice votes require an
+	// authorization from the record author before the voting period
+	// can be started by an admin.
+	VoteTypeMultipleChoice VoteT = 3
+
 	// VoteTypeLast unit test only.
-	VoteTypeLast VoteT = 3
+	VoteTypeLast VoteT = 4
 )
 
 var (
 	// VoteType contains the human readable vote types.
 	VoteTypes = map[VoteT]string{
-		VoteTypeInvalid:  "invalid vote type",
-		VoteTypeStandard: "standard",
-		VoteTypeRunoff:   "runoff",
+		VoteTypeInvalid:        "invalid vote type",
+		VoteTypeStandard:       "standard",
+		VoteTypeRunoff:         "runoff",
+		VoteTypeMultipleChoice: "multiplechoice",
 	}
 )
 
@@ -185,6 +203,10 @@ const (
 	// record should be rejected. Standard votes and runoff vote
 	// submissions are required to use this vote option ID.
 	VoteOptionIDReject = "no"
+
+	// VoteOptionsMax is the maximum number of vote options that a
+	// multiple choice vote can have.
+	VoteOptionsMax = 16
 )
 
 // VoteStatusT represents a vote status.
@@ -229,8 +251,12 @@ const (
 	// be voted on. This happens when a record is censored or archived.
 	VoteStatusIneligible VoteStatusT = 7
 
+	// VoteStatusCancelled indicates that a started vote was cancelled
+	// by an admin before it finished. A cancelled vote has no outcome.
+	VoteStatusCancelled VoteStatusT = 8
+
 	// VoteStatusLast unit test only.
-	VoteStatusLast VoteStatusT = 8
+	VoteStatusLast VoteStatusT = 9
 )
 
 var (
@@ -244,6 +270,7 @@ var (
 		VoteStatusApproved:     "approved",
 		VoteStatusRejected:     "rejected",
 		VoteStatusIneligible:   "ineligible",
+		VoteStatusCancelled:    "cancelled",
 	}
 )
 
@@ -261,6 +288,16 @@ type VoteMetadata struct {
 	// It is set when a record is being submitted as a vote options in
 	// the runoff vote.
 	LinkTo string `json:"linkto,omitempty"`
+
+	// Budget is the total budget of a runoff vote. It can only be set
+	// on a runoff vote parent record. A runoff vote with a budget can
+	// have multiple winners. See RunoffBudget for more details.
+	Budget uint64 `json:"budget,omitempty"`
+
+	// Amount is the amount that a runoff vote submission is requesting
+	// from the runoff vote budget. It must be set on the submissions
+	// of a runoff vote with a budget.
+	Amount uint64 `json:"amount,omitempty"`
 }
 
 // VoteOption describes a single vote option.
@@ -292,6 +329,16 @@ type VoteParams struct {
 	// Parent is the token of the parent record. This field will only
 	// be populated for runoff votes.
 	Parent string `json:"parent,omitempty"`
+
+	// RevealDuration is the duration in blocks of the reveal window of
+	// a secret ballot vote. Setting a reveal duration makes the vote a
+	// secret ballot vote. During the voting period each ticket casts a
+	// commitment to its vote bit instead of the vote bit itself. The
+	// vote bits are revealed during the reveal window, which begins
+	// once the voting period has ended. Commitments that are not
+	// revealed count toward the quorum requirement only. Runoff votes
+	// cannot be secret ballot votes.
+	RevealDuration uint32 `json:"revealduration,omitempty"`
 }
 
 // StartDetails is the structure that is provided when starting a record
@@ -376,16 +423,43 @@ const (
 	// VoteErrorTicketAlreadyVoted is returned when attempting to cast
 	// a vote using a dcr ticket that has already voted.
 	VoteErrorTicketAlreadyVoted VoteErrorT = 9
+
+	// VoteErrorCommitmentInvalid is returned when a secret ballot vote
+	// is cast without a valid commitment, when a commitment is cast on
+	// a vote that is not a secret ballot vote, or when a revealed vote
+	// does not match its commitment.
+	VoteErrorCommitmentInvalid VoteErrorT = 10
+
+	// VoteErrorCommitmentNotFound is returned when attempting to reveal
+	// a vote using a dcr ticket that has not cast a commitment.
+	VoteErrorCommitmentNotFound VoteErrorT = 11
 )
 
 // CastVote is a signed ticket vote.
+//
+// The vote bit of a secret ballot vote is not included in the cast vote. The
+// VoteBit must be left empty and the Commitment must be set instead. The
+// Commitment is the hex encoded SHA256 digest of Token+Ticket+VoteBit+Salt,
+// where VoteBit is the hex encoded vote bit and Salt is a hex encoded random
+// value of SaltSize bytes. The client must keep the salt secret until the
+// vote is revealed using the Reveal command.
+//
+// Signature is the client signature of the Token+Ticket+VoteBit+Commitment.
+// Either the VoteBit or the Commitment will be empty.
 type CastVote struct {
-	Token     string `json:"token"`     // Record token
-	Ticket    string `json:"ticket"`    // Ticket ID
-	VoteBit   string `json:"votebit"`   // Selected vote bit, hex encoded
-	Signature string `json:"signature"` // Signature of Token+Ticket+VoteBit
+	Token      string `json:"token"`                // Record token
+	Ticket     string `json:"ticket"`               // Ticket ID
+	VoteBit    string `json:"votebit"`              // Selected vote bit, hex encoded
+	Commitment string `json:"commitment,omitempty"` // Secret ballot commitment
+	Signature  string `json:"signature"`            // Client signature
 }
 
+const (
+	// SaltSize is the size in bytes of the salt that is used to create
+	// a secret ballot commitment.
+	SaltSize = 32
+)
+
 // CastVoteReply contains the receipt for the cast vote.
 type CastVoteReply struct {
 	Ticket  string `json:"ticket"`  // Ticket ID
@@ -408,6 +482,63 @@ type CastBallotReply struct {
 	Receipts []CastVoteReply `json:"receipts"`
 }
 
+// RevealVote reveals the vote bit of a secret ballot vote. The vote bit and
+// salt must match the commitment that was cast by the ticket. Votes can only
+// be revealed during the reveal window, i.e. after the vote end block height
+// and prior to the reveal end block height.
+type RevealVote struct {
+	Token   string `json:"token"`   // Record token
+	Ticket  string `json:"ticket"`  // Ticket ID
+	VoteBit string `json:"votebit"` // Selected vote bit, hex encoded
+	Salt    string `json:"salt"`    // Commitment salt, hex encoded
+}
+
+// RevealVoteReply contains the receipt for the revealed vote. The receipt is
+// the server signature of the Ticket+VoteBit+Salt.
+type RevealVoteReply struct {
+	Ticket  string `json:"ticket"`  // Ticket ID
+	Receipt string `json:"receipt"` // Server signature
+
+	// The follwing fields will only be present if an error occurred
+	// while attempting to reveal the vote.
+	ErrorCode    VoteErrorT `json:"errorcode,omitempty"`
+	ErrorContext string     `json:"errorcontext,omitempty"`
+}
+
+// Reveal reveals a ballot of secret ballot votes. A ballot can only contain
+// the votes for a single record. A ticket can only be revealed once. Any
+// additional reveals of the same ticket in a ballot are rejected.
+type Reveal struct {
+	Votes []RevealVote `json:"votes"`
+}
+
+// RevealReply is the reply to the Reveal command.
+type RevealReply struct {
+	Receipts []RevealVoteReply `json:"receipts"`
+}
+
+// Cancel cancels an ongoing record vote. This call requires admin
+// privileges. A cancelled vote is ended immediately and no further ballots
+// are accepted. The votes that have already been cast are kept, but the vote
+// has no outcome. Runoff votes cannot be cancelled.
+//
+// Signature contains the client signature of the Token+Reason.
+type Cancel struct {
+	Token     string `json:"token"`
+	Reason    string `json:"reason"`
+	PublicKey string `json:"publickey"`
+	Signature string `json:"signature"`
+}
+
+// CancelReply is the reply to the Cancel command.
+//
+// Receipt is the server signature of the client signature. This is proof that
+// the server received and processed the Cancel command.
+type CancelReply struct {
+	Timestamp int64  `json:"timestamp"`
+	Receipt   string `json:"receipt"`
+}
+
 // AuthDetails contains the details of a vote authorization.
 //
 // Signature is the client signature of the Token+Version+Action.
@@ -421,6 +552,18 @@ type AuthDetails struct {
 	Receipt   string `json:"receipt"`   // Server sig of client sig
 }
 
+// CancelDetails contains the details of a vote cancellation.
+//
+// Signature is the client signature of the Token+Reason.
+type CancelDetails struct {
+	Token     string `json:"token"`     // Record token
+	Reason    string `json:"reason"`    // Reason for cancelling the vote
+	PublicKey string `json:"publickey"` // Public key used for signature
+	Signature string `json:"signature"` // Client signature
+	Timestamp int64  `json:"timestamp"` // Server timestamp
+	Receipt   string `json:"receipt"`   // Server sig of client sig
+}
+
 // VoteDetails contains the details of a record vote. A vote details with the
 // eligible tickets snapshot will be ~0.35MB.
 //
@@ -437,6 +580,10 @@ type VoteDetails struct {
 	StartBlockHash   string     `json:"startblockhash"`
 	EndBlockHeight   uint32     `json:"endblockheight"`
 	EligibleTickets  []string   `json:"eligibletickets"` // Ticket hashes
+
+	// RevealEndBlockHeight is the block height that the reveal window
+	// of a secret ballot vote ends at.
+	RevealEndBlockHeight uint32 `json:"revealendblockheight,omitempty"`
 }
 
 // Details requests the vote details for a record vote.
@@ -446,23 +593,31 @@ type Details struct {
 
 // DetailsReply is the reply to the Details command.
 type DetailsReply struct {
-	Auths []AuthDetails `json:"auths"`
-	Vote  *VoteDetails  `json:"vote"`
+	Auths  []AuthDetails  `json:"auths"`
+	Vote   *VoteDetails   `json:"vote"`
+	Cancel *CancelDetails `json:"cancel,omitempty"`
 }
 
 // CastVoteDetails contains the details of a cast vote.
 //
-// Signature is the client signature of the Token+Ticket+VoteBit. The client
-// uses the ticket's largest commitment address to create the signature. The
-// receipt is the server signature of the client signature.
+// Signature is the client signature of the Token+Ticket+VoteBit+Commitment.
+// The client uses the ticket's largest commitment address to create the
+// signature. The receipt is the server signature of the client signature.
+//
+// The vote bit of a secret ballot vote is empty until the vote has been
+// revealed. The salt is populated once the vote has been revealed. The vote
+// bit was not part of the cast vote, so the signature of a secret ballot vote
+// is the client signature of the Token+Ticket+Commitment.
 type CastVoteDetails struct {
-	Token     string `json:"token"`     // Record token
-	Ticket    string `json:"ticket"`    // Ticket hash
-	VoteBit   string `json:"votebit"`   // Selected vote bit, hex encoded
-	Address   string `json:"address"`   // Address used in client signature
-	Signature string `json:"signature"` // Client signature
-	Receipt   string `json:"receipt"`   // Server sig of client sig
-	Timestamp int64  `json:"timestamp"` // Unix timestamp
+	Token      string `json:"token"`                // Record token
+	Ticket     string `json:"ticket"`               // Ticket hash
+	VoteBit    string `json:"votebit"`              // Selected vote bit, hex encoded
+	Commitment string `json:"commitment,omitempty"` // Secret ballot commitment
+	Salt       string `json:"salt,omitempty"`       // Secret ballot salt
+	Address    string `json:"address"`              // Address used in client signature
+	Signature  string `json:"signature"`            // Client signature
+	Receipt    string `json:"receipt"`              // Server sig of client sig
+	Timestamp  int64  `json:"timestamp"`            // Unix timestamp
 }
 
 // Results returns the cast votes for a record.
@@ -484,6 +639,54 @@ type VoteResult struct {
 	Votes       uint64 `json:"votes"`       // Votes cast for this option
 }
 
+// BudgetOutcomeT represents the outcome of a submission in a runoff vote that
+// has a budget.
+type BudgetOutcomeT uint32
+
+const (
+	// BudgetOutcomeInvalid is an invalid budget outcome.
+	BudgetOutcomeInvalid BudgetOutcomeT = 0
+
+	// BudgetOutcomeFunded indicates that the submission met the quorum
+	// and pass requirements and its amount was funded by the budget.
+	BudgetOutcomeFunded BudgetOutcomeT = 1
+
+	// BudgetOutcomeNotPassed indicates that the submission did not meet
+	// the quorum and pass requirements.
+	BudgetOutcomeNotPassed BudgetOutcomeT = 2
+
+	// BudgetOutcomeOverBudget indicates that the submission met the
+	// quorum and pass requirements, but the remaining budget was not
+	// sufficient to fund its amount.
+	BudgetOutcomeOverBudget BudgetOutcomeT = 3
+
+	// BudgetOutcomeLast unit test only.
+	BudgetOutcomeLast BudgetOutcomeT = 4
+)
+
+var (
+	// BudgetOutcomes contains the human readable budget outcomes.
+	BudgetOutcomes = map[BudgetOutcomeT]string{
+		BudgetOutcomeInvalid:    "invalid",
+		BudgetOutcomeFunded:     "funded",
+		BudgetOutcomeNotPassed:  "not passed",
+		BudgetOutcomeOverBudget: "over budget",
+	}
+)
+
+// RunoffBudget contains the budget outcome of a submission in a runoff vote
+// that has a budget. The submissions that meet the quorum and pass
+// requirements are funded in order of net approval until the budget has been
+// exhausted. A submission whose amount exceeds the remaining budget is
+// skipped and the submissions with a lower net approval are still
+// considered. All funded submissions are approved.
+type RunoffBudget struct {
+	Total     uint64         `json:"total"`     // Runoff vote budget
+	Amount    uint64         `json:"amount"`    // Submission amount
+	Remaining uint64         `json:"remaining"` // Remaining budget
+	Outcome   BudgetOutcomeT `json:"outcome"`
+}
+
 // Summary summarizes the vote params and results of a record vote.
 type Summary struct {
 	Type             VoteT       `json:"type"`
@@ -507,6 +710,23 @@ type Summary struct {
 
 	Results []VoteResult `json:"results"`
 
+	// RevealEndBlockHeight and Unrevealed are only populated for secret
+	// ballot votes. Unrevealed is the number of commitments that have
+	// not been revealed. The results of a secret ballot vote only
+	// include the revealed votes.
+	RevealEndBlockHeight uint32 `json:"revealendblockheight,omitempty"`
+	Unrevealed           uint64 `json:"unrevealed,omitempty"`
+
+	// Budget contains the budget outcome of a runoff vote submission
+	// when the runoff vote has a budget. It is only populated once the
+	// runoff vote has finished.
+	Budget *RunoffBudget `json:"budget,omitempty"`
+
+	// WinningOption is the ID of the winning vote option of a finished
+	// multiple choice vote. It will be empty if the vote did not have
+	// a winner.
+	WinningOption string `json:"winningoption,omitempty"`
+
 	// BestBlock is the best block value that was used to prepare the
 	// summary.
 	BestBlock uint32 `json:"bestblock"`
@@ -623,9 +843,10 @@ const (
 
 // Timestamps requests the timestamps for ticket vote data.
 //
-// If no votes page number is provided then the vote authorization and vote
-// details timestamps will be returned. If a votes page number is provided then
-// the specified page of cast vote timestamps will be returned.
+// If no votes page number is provided then the vote authorization, vote
+// details, and vote cancellation timestamps will be returned. If a votes page
+// number is provided then the specified page of cast vote timestamps will be
+// returned.
 type Timestamps struct {
 	Token     string `json:"token"`
 	VotesPage uint32 `json:"votespage,omitempty"`
@@ -641,6 +862,10 @@ type TimestampsReply struct {
 	// payload will contain a VoteDetails structure.
 	Details *Timestamp `json:"details,omitempty"`
 
+	// Cancel contains the timestamp for the vote cancellation. The data
+	// payload will contain a CancelDetails structure.
+	Cancel *Timestamp `json:"cancel,omitempty"`
+
 	// Votes contains the timestamps for the cast votes. The data
 	// payloads will contain CastVoteDetails strucutures.
 	Votes []Timestamp `json:"votes,omitempty"`
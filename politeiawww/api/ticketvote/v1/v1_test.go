@@ -23,4 +23,9 @@ func TestMaps(t *testing.T) {
 	if err != nil {
 		t.Fatalf("VoteStatuses: %v", err)
 	}
+	err = unittest.TestGenericConstMap(BudgetOutcomes,
+		uint64(BudgetOutcomeLast))
+	if err != nil {
+		t.Fatalf("BudgetOutcomes: %v", err)
+	}
 }
@@ -276,9 +276,14 @@ const (
 	// Note: in a runoff vote it is possible for a proposal to meet the quorum
 	// and pass requirements but still be rejected if it does not have the most
 	// net yes votes.
-	VoteTypeInvalid  VoteT = 0
-	VoteTypeStandard VoteT = 1
-	VoteTypeRunoff   VoteT = 2
+	//
+	// VoteTypeMultipleChoice specifies a vote with custom vote options. The
+	// winner is the vote option with the most votes. See the ticketvote API for
+	// the details.
+	VoteTypeInvalid        VoteT = 0
+	VoteTypeStandard       VoteT = 1
+	VoteTypeRunoff         VoteT = 2
+	VoteTypeMultipleChoice VoteT = 3
 
 	// User manage actions
 	UserManageInvalid                         UserManageActionT = 0 // Invalid action type
@@ -513,6 +518,7 @@ type VoteSummary struct {
 	QuorumPercentage uint32             `json:"quorumpercentage,omitempty"` // Percent of eligible votes required for quorum
 	PassPercentage   uint32             `json:"passpercentage,omitempty"`   // Percent of total votes required to pass
 	Results          []VoteOptionResult `json:"results,omitempty"`          // Vote results
+	WinningOption    string             `json:"winningoption,omitempty"`    // Winning option of a multiple choice vote
 }
 
 // ProposalRecord is an entire proposal and it's content.
@@ -587,14 +593,31 @@ type Version struct{}
 // VersionReply returns information that indicates the lowest version that
 // this backend supports and additionally the route to the API and the public
 // signing key of the server.
+//
+// PubKeyHistory contains all of the public keys that have been used by the
+// server, ordered from oldest to newest. Signatures must be verified using the
+// key that was active at the time the signature was made. PubKeyHistory is
+// empty if the server key has never been rotated.
 type VersionReply struct {
-	Version           uint   `json:"version"`           // Lowest supported WWW API version
-	Route             string `json:"route"`             // Prefix to API calls
-	BuildVersion      string `json:"buildversion"`      // Build version from hosted pi module
-	PubKey            string `json:"pubkey"`            // Server public key
-	TestNet           bool   `json:"testnet"`           // Network indicator
-	Mode              string `json:"mode"`              // current politeiawww mode running (piwww or cmswww)
-	ActiveUserSession bool   `json:"activeusersession"` // indicates if there is an active user session
+	Version           uint           `json:"version"`                 // Lowest supported WWW API version
+	Route             string         `json:"route"`                   // Prefix to API calls
+	BuildVersion      string         `json:"buildversion"`            // Build version from hosted pi module
+	PubKey            string         `json:"pubkey"`                  // Server public key
+	PubKeyHistory     []ServerPubKey `json:"pubkeyhistory,omitempty"` // Server public key history
+	TestNet           bool           `json:"testnet"`                 // Network indicator
+	Mode              string         `json:"mode"`                    // current politeiawww mode running (piwww or cmswww)
+	ActiveUserSession bool           `json:"activeusersession"`       // indicates if there is an active user session
+}
+
+// ServerPubKey contains a server public key and the unix timestamp at which
+// it became the active signing key. Every key, except for the first key of a
+// history, contains a handover signature that was made by the previous key.
+// The handover signature is the signature of the previous key on the public
+// key concatenated with the activation timestamp, i.e. pubkey+activation.
+type ServerPubKey struct {
+	PubKey     string `json:"pubkey"`              // Server public key
+	Activation int64  `json:"activation"`          // Unix timestamp
+	Signature  string `json:"signature,omitempty"` // Handover signature
 }
 
 // NewUser is used to request that a new user be created within the db.
@@ -1027,6 +1050,11 @@ type Vote struct {
 	QuorumPercentage uint32       `json:"quorumpercentage"` // Percent of eligible votes required for quorum
 	PassPercentage   uint32       `json:"passpercentage"`   // Percent of total votes required to pass
 	Options          []VoteOption `json:"options"`          // Vote options
+
+	// RevealDuration is the duration in blocks of the reveal window of
+	// a secret ballot vote. It is only set for secret ballot votes. See
+	// the ticketvote API for the details.
+	RevealDuration uint32 `json:"revealduration,omitempty"`
 }
 
 // ActiveVote obtains all proposals that have active votes.
@@ -1089,14 +1117,24 @@ type StartVoteReply struct {
 	StartBlockHash   string   `json:"startblockhash"`   // Block hash
 	EndHeight        string   `json:"endheight"`        // Height of vote end
 	EligibleTickets  []string `json:"eligibletickets"`  // Valid voting tickets
+
+	// RevealEndHeight is the height that the reveal window of a secret
+	// ballot vote ends at. The reveal window begins at the EndHeight.
+	RevealEndHeight string `json:"revealendheight,omitempty"`
 }
 
 // CastVote is a signed vote.
+//
+// A secret ballot vote leaves the VoteBit empty and sets the Commitment
+// instead. The signature is then the signature of Token+Ticket+Commitment.
+// See the ticketvote API for the details.
 type CastVote struct {
 	Token     string `json:"token"`     // Proposal ID
 	Ticket    string `json:"ticket"`    // Ticket ID
 	VoteBit   string `json:"votebit"`   // Vote bit that was selected, this is encode in hex
 	Signature string `json:"signature"` // Signature of Token+Ticket+VoteBit
+
+	Commitment string `json:"commitment,omitempty"` // Secret ballot commitment
 }
 
 // CastVoteReply is the answer to the CastVote command. The Error and
@@ -11,6 +11,7 @@ import (
 
 	cmplugin "github.com/decred/politeia/politeiad/plugins/comments"
 	piplugin "github.com/decred/politeia/politeiad/plugins/pi"
+	srplugin "github.com/decred/politeia/politeiad/plugins/search"
 	tkplugin "github.com/decred/politeia/politeiad/plugins/ticketvote"
 	umplugin "github.com/decred/politeia/politeiad/plugins/usermd"
 	cmv1 "github.com/decred/politeia/politeiawww/api/comments/v1"
@@ -94,6 +95,8 @@ func pluginUserErr(e ErrorReply) string {
 		errMsg = cmplugin.ErrorCodes[cmplugin.ErrorCodeT(e.ErrorCode)]
 	case piplugin.PluginID:
 		errMsg = piplugin.ErrorCodes[piplugin.ErrorCodeT(e.ErrorCode)]
+	case srplugin.PluginID:
+		errMsg = srplugin.ErrorCodes[srplugin.ErrorCodeT(e.ErrorCode)]
 	case tkplugin.PluginID:
 		errMsg = tkplugin.ErrorCodes[tkplugin.ErrorCodeT(e.ErrorCode)]
 	case umplugin.PluginID:
@@ -0,0 +1,71 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"fmt"
+
+	pdv1 "github.com/decred/politeia/politeiad/api/v1"
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
+)
+
+// serverPubKeyHistory converts the provided server public key history to a
+// politeiad identity key history and verifies it. The last key of the
+// history must be the provided server public key. A nil history is returned
+// if the provided history is empty, i.e. the server key has never been
+// rotated.
+func serverPubKeyHistory(serverPubKey string, history []www.ServerPubKey) ([]pdv1.IdentityKey, error) {
+	if len(history) == 0 {
+		return nil, nil
+	}
+	h := make([]pdv1.IdentityKey, 0, len(history))
+	for _, v := range history {
+		h = append(h, pdv1.IdentityKey{
+			PublicKey:  v.PubKey,
+			Activation: v.Activation,
+			Signature:  v.Signature,
+		})
+	}
+	err := pdv1.VerifyIdentityHistory(h)
+	if err != nil {
+		return nil, err
+	}
+	if h[len(h)-1].PublicKey != serverPubKey {
+		return nil, fmt.Errorf("server public key is not the last key " +
+			"of the server public key history")
+	}
+	return h, nil
+}
+
+// ServerPubKeyAt returns the server public key that was active at the
+// provided unix timestamp. Receipts must be verified using the key that was
+// active at the timestamp of the receipt. The server public key is returned
+// if the history is empty.
+func ServerPubKeyAt(serverPubKey string, history []www.ServerPubKey, timestamp int64) (string, error) {
+	h, err := serverPubKeyHistory(serverPubKey, history)
+	if err != nil {
+		return "", err
+	}
+	if h == nil {
+		return serverPubKey, nil
+	}
+	return pdv1.IdentityKeyAt(h, timestamp)
+}
+
+// ServerPubKeysSince returns the server public keys that have been active at
+// any point since the provided unix timestamp. Censorship records are signed
+// by the server when they are returned, so a censorship record may have been
+// signed by any key that has been active since the record was created. The
+// server public key is returned if the history is empty.
+func ServerPubKeysSince(serverPubKey string, history []www.ServerPubKey, timestamp int64) ([]string, error) {
+	h, err := serverPubKeyHistory(serverPubKey, history)
+	if err != nil {
+		return nil, err
+	}
+	if h == nil {
+		return []string{serverPubKey}, nil
+	}
+	return pdv1.IdentityKeysSince(h, timestamp)
+}
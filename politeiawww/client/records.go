@@ -109,6 +109,23 @@ func (c *Client) RecordTimestamps(t rcv1.Timestamps) (*rcv1.TimestampsReply, err
 	return &tr, nil
 }
 
+// RecordDiff sends a records v1 Diff request to politeiawww.
+func (c *Client) RecordDiff(d rcv1.Diff) (*rcv1.DiffReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		rcv1.APIRoute, rcv1.RouteDiff, d)
+	if err != nil {
+		return nil, err
+	}
+
+	var dr rcv1.DiffReply
+	err = json.Unmarshal(resBody, &dr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dr, nil
+}
+
 // Records sends a records v1 Records request to politeiawww.
 func (c *Client) Records(r rcv1.Records) (map[string]rcv1.Record, error) {
 	resBody, err := c.makeReq(http.MethodPost,
@@ -161,6 +178,23 @@ func (c *Client) RecordInventoryOrdered(i rcv1.InventoryOrdered) (*rcv1.Inventor
 	return &ir, nil
 }
 
+// RecordSearch sends a records v1 Search request to politeiawww.
+func (c *Client) RecordSearch(s rcv1.Search) (*rcv1.SearchReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		rcv1.APIRoute, rcv1.RouteSearch, s)
+	if err != nil {
+		return nil, err
+	}
+
+	var sr rcv1.SearchReply
+	err = json.Unmarshal(resBody, &sr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &sr, nil
+}
+
 // UserRecords sends a records v1 UserRecords request to politeiawww.
 func (c *Client) UserRecords(ur rcv1.UserRecords) (*rcv1.UserRecordsReply, error) {
 	resBody, err := c.makeReq(http.MethodPost,
@@ -87,6 +87,40 @@ func (c *Client) TicketVoteCastBallot(cb tkv1.CastBallot) (*tkv1.CastBallotReply
 	return &cbr, nil
 }
 
+// TicketVoteReveal sends a ticketvote v1 Reveal request to politeiawww.
+func (c *Client) TicketVoteReveal(r tkv1.Reveal) (*tkv1.RevealReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		tkv1.APIRoute, tkv1.RouteReveal, r)
+	if err != nil {
+		return nil, err
+	}
+
+	var rr tkv1.RevealReply
+	err = json.Unmarshal(resBody, &rr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &rr, nil
+}
+
+// TicketVoteCancel sends a ticketvote v1 Cancel request to politeiawww.
+func (c *Client) TicketVoteCancel(cl tkv1.Cancel) (*tkv1.CancelReply, error) {
+	resBody, err := c.makeReq(http.MethodPost,
+		tkv1.APIRoute, tkv1.RouteCancel, cl)
+	if err != nil {
+		return nil, err
+	}
+
+	var cr tkv1.CancelReply
+	err = json.Unmarshal(resBody, &cr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cr, nil
+}
+
 // TicketVoteDetails sends a ticketvote v1 Details request to politeiawww.
 func (c *Client) TicketVoteDetails(d tkv1.Details) (*tkv1.DetailsReply, error) {
 	resBody, err := c.makeReq(http.MethodPost,
@@ -216,6 +250,14 @@ func TicketVoteTimestampsVerify(tr tkv1.TimestampsReply) error {
 		}
 	}
 
+	// Verify vote cancellation timestamp
+	if tr.Cancel != nil {
+		err := TicketVoteTimestampVerify(*tr.Cancel)
+		if err != nil {
+			return fmt.Errorf("verify vote cancel timestamp: %v", err)
+		}
+	}
+
 	// Verify vote timestamps
 	for k, v := range tr.Votes {
 		err := TicketVoteTimestampVerify(v)
@@ -254,6 +296,25 @@ func AuthDetailsVerify(a tkv1.AuthDetails, serverPublicKey string) error {
 	return nil
 }
 
+// CancelDetailsVerify verifies the signature and receipt of the provided
+// ticketvote v1 CancelDetails.
+func CancelDetailsVerify(cd tkv1.CancelDetails, serverPublicKey string) error {
+	// Verify signature
+	msg := cd.Token + cd.Reason
+	err := util.VerifySignature(cd.Signature, cd.PublicKey, msg)
+	if err != nil {
+		return fmt.Errorf("verify signature: %v", err)
+	}
+
+	// Verify receipt
+	err = util.VerifySignature(cd.Receipt, serverPublicKey, cd.Signature)
+	if err != nil {
+		return fmt.Errorf("verify receipt: %v", err)
+	}
+
+	return nil
+}
+
 // VoteDetailsVerify verifies the signature and receipt of the provided
 // ticketvote v1 VoteDetails.
 func VoteDetailsVerify(vd tkv1.VoteDetails, serverPublicKey string) error {
@@ -298,9 +359,23 @@ func CastVoteDetailsVerify(cvd tkv1.CastVoteDetails, serverPublicKey string) err
 		return fmt.Errorf("unknown p2pkh address %v", cvd.Address)
 	}
 
+	// The vote bit of a secret ballot vote is not part of the
+	// signature. It is populated once the vote has been revealed and
+	// must match the commitment.
+	voteBit := cvd.VoteBit
+	if cvd.Commitment != "" {
+		voteBit = ""
+		if cvd.Salt != "" {
+			d := util.Digest([]byte(cvd.VoteBit + cvd.Salt))
+			if hex.EncodeToString(d) != cvd.Commitment {
+				return fmt.Errorf("revealed vote does not match commitment")
+			}
+		}
+	}
+
 	// Verify signature. The signature must be converted from hex to
 	// base64. This is what the verify message function expects.
-	msg := cvd.Token + cvd.Ticket + cvd.VoteBit
+	msg := cvd.Token + cvd.Ticket + voteBit + cvd.Commitment
 	b, err := hex.DecodeString(cvd.Signature)
 	if err != nil {
 		return fmt.Errorf("signature invalid hex")
@@ -0,0 +1,460 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/decred/dcrd/blockchain/standalone"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/wire"
+	rcv1 "github.com/decred/politeia/politeiawww/api/records/v1"
+	"lukechampine.com/blake3"
+)
+
+const (
+	// BundleConfirmations is the default number of block headers that
+	// are included in a timestamps bundle for every anchor block.
+	BundleConfirmations = 6
+
+	// dcrdataTimeout is the timeout of the dcrdata requests that are
+	// made when creating a timestamps bundle.
+	dcrdataTimeout = 30 * time.Second
+
+	// Dcrdata routes
+	dcrdataRouteTx         = "/api/tx/{txid}"
+	dcrdataRouteBlockRaw   = "/api/block/{height}/raw"
+	dcrdataRouteHeaderRaw  = "/api/block/{height}/header/raw"
+	dcrdataRouteBestHeight = "/api/block/best/height"
+)
+
+var (
+	// blake3PowHeights contains the heights at which the BLAKE3 proof of
+	// work agenda (DCP0011) activated on the networks where it is known.
+	// The proof of work of the anchor chains of other networks cannot be
+	// verified.
+	blake3PowHeights = map[wire.CurrencyNet]uint32{
+		wire.MainNet: 794368,
+	}
+
+	// minChainWork contains the minimum cumulative work that an anchor
+	// block and the blocks that were mined on top of it must contain.
+	// It is well below the work of the mainnet blocks, but prevents a
+	// chain of headers that were mined at the proof of work limit from
+	// being accepted. Networks that allow minimum difficulty blocks do
+	// not have a minimum.
+	minChainWork = map[wire.CurrencyNet]*big.Int{
+		wire.MainNet: new(big.Int).Lsh(big.NewInt(1), 43),
+	}
+)
+
+// AnchorChainInfo contains the details of a verified anchor chain.
+type AnchorChainInfo struct {
+	TxID          string
+	MerkleRoot    string
+	BlockHash     string
+	Height        uint32
+	Timestamp     int64  // Block timestamp
+	Confirmations uint32 // Number of verified blocks on top of the anchor
+	TipHash       string // Hash of the last verified block
+}
+
+// dcrdataTx contains the fields of a dcrdata tx reply that are used to locate
+// the block of an anchor transaction.
+type dcrdataTx struct {
+	Block *struct {
+		BlockHash   string `json:"blockhash"`
+		BlockHeight int64  `json:"blockheight"`
+	} `json:"block"`
+}
+
+// dcrdataRaw contains the fields of a dcrdata raw block or raw block header
+// reply.
+type dcrdataRaw struct {
+	Hash string `json:"hash"`
+	Hex  string `json:"hex"`
+}
+
+// dcrdataGet makes a dcrdata GET request and returns the response body.
+func dcrdataGet(host, route string) ([]byte, error) {
+	c := &http.Client{
+		Timeout: dcrdataTimeout,
+	}
+	url := strings.TrimSuffix(host, "/") + route
+	r, err := c.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	if r.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("dcrdata error: %v %v %s",
+			r.StatusCode, url, body)
+	}
+
+	return body, nil
+}
+
+// dcrdataRawGet makes a dcrdata GET request for a raw block or a raw block
+// header and returns the reply.
+func dcrdataRawGet(host, route string, height uint32) (*dcrdataRaw, error) {
+	route = strings.Replace(route, "{height}",
+		strconv.FormatUint(uint64(height), 10), 1)
+	b, err := dcrdataGet(host, route)
+	if err != nil {
+		return nil, err
+	}
+	var dr dcrdataRaw
+	err = json.Unmarshal(b, &dr)
+	if err != nil {
+		return nil, err
+	}
+	return &dr, nil
+}
+
+// anchorChainNew retrieves the chain data of an anchor transaction from
+// dcrdata. The headers of up to the provided number of confirmation blocks
+// are included.
+func anchorChainNew(dcrdataHost, txID string, confirmations uint32) (*rcv1.AnchorChain, error) {
+	// Get the block of the anchor tx
+	route := strings.Replace(dcrdataRouteTx, "{txid}", txID, 1)
+	b, err := dcrdataGet(dcrdataHost, route)
+	if err != nil {
+		return nil, err
+	}
+	var tx dcrdataTx
+	err = json.Unmarshal(b, &tx)
+	if err != nil {
+		return nil, err
+	}
+	if tx.Block == nil || tx.Block.BlockHash == "" {
+		return nil, fmt.Errorf("tx %v has not been mined", txID)
+	}
+	height := uint32(tx.Block.BlockHeight)
+
+	// Get the raw block
+	block, err := dcrdataRawGet(dcrdataHost, dcrdataRouteBlockRaw, height)
+	if err != nil {
+		return nil, err
+	}
+	if block.Hash != tx.Block.BlockHash {
+		return nil, fmt.Errorf("block %v hash mismatch: got %v, want %v",
+			height, block.Hash, tx.Block.BlockHash)
+	}
+
+	// Get the headers of the confirmation blocks
+	b, err = dcrdataGet(dcrdataHost, dcrdataRouteBestHeight)
+	if err != nil {
+		return nil, err
+	}
+	best, err := strconv.ParseUint(strings.TrimSpace(string(b)), 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid best height: %v", err)
+	}
+	headers := make([]string, 0, confirmations)
+	for h := height + 1; h <= height+confirmations && h <= uint32(best); h++ {
+		header, err := dcrdataRawGet(dcrdataHost, dcrdataRouteHeaderRaw, h)
+		if err != nil {
+			return nil, err
+		}
+		headers = append(headers, header.Hex)
+	}
+
+	return &rcv1.AnchorChain{
+		TxID:    txID,
+		Height:  height,
+		Block:   block.Hex,
+		Headers: headers,
+	}, nil
+}
+
+// timestampsAll returns all of the timestamps in a records v1 timestamps
+// reply.
+func timestampsAll(tr rcv1.TimestampsReply) []rcv1.Timestamp {
+	ts := []rcv1.Timestamp{tr.RecordMetadata}
+	for _, streams := range tr.Metadata {
+		for _, v := range streams {
+			ts = append(ts, v)
+		}
+	}
+	for _, v := range tr.Files {
+		ts = append(ts, v)
+	}
+	return ts
+}
+
+// anchorTxs returns the merkle roots of the anchor transactions that are
+// referenced by the provided timestamps.
+func anchorTxs(tr rcv1.TimestampsReply) (map[string]string, error) {
+	txs := make(map[string]string) // [txID]merkleRoot
+	for _, v := range timestampsAll(tr) {
+		if v.TxID == "" {
+			return nil, fmt.Errorf("data not anchored yet")
+		}
+		mr, ok := txs[v.TxID]
+		if ok && mr != v.MerkleRoot {
+			return nil, fmt.Errorf("tx %v has multiple merkle roots", v.TxID)
+		}
+		txs[v.TxID] = v.MerkleRoot
+	}
+	return txs, nil
+}
+
+// TimestampsBundleNew returns a timestamps bundle for the provided record
+// timestamps. The chain data of the anchor transactions is retrieved from the
+// provided dcrdata host. The headers of up to the provided number of blocks
+// that were mined on top of each anchor block are included in the bundle.
+func TimestampsBundleNew(dcrdataHost string, tr rcv1.TimestampsReply, confirmations uint32) (*rcv1.TimestampsBundle, error) {
+	txs, err := anchorTxs(tr)
+	if err != nil {
+		return nil, err
+	}
+	anchors := make([]rcv1.AnchorChain, 0, len(txs))
+	for txID := range txs {
+		ac, err := anchorChainNew(dcrdataHost, txID, confirmations)
+		if err != nil {
+			return nil, fmt.Errorf("anchor %v: %v", txID, err)
+		}
+		anchors = append(anchors, *ac)
+	}
+	sort.Slice(anchors, func(i, j int) bool {
+		return anchors[i].Height < anchors[j].Height
+	})
+
+	return &rcv1.TimestampsBundle{
+		Timestamps: tr,
+		Anchors:    anchors,
+	}, nil
+}
+
+// TimestampsBundleVerify verifies a timestamps bundle. This proves the
+// inclusion of the record data in the signed log roots and in the merkle roots
+// that were timestamped onto the dcr blockchain, that the merkle roots were
+// included in the anchor transactions, that the anchor transactions were
+// included in the anchor blocks, and that the provided blocks were mined on
+// top of the anchor blocks. The proof of work of the blocks is verified using
+// the provided network params. No network access is required.
+//
+// The verified anchor chains are returned. It is the responsibility of the
+// caller to verify that the returned block hashes are part of the main chain.
+func TimestampsBundleVerify(tb rcv1.TimestampsBundle, params *chaincfg.Params) ([]AnchorChainInfo, error) {
+	// Verify the timestamps
+	err := RecordTimestampsVerify(tb.Timestamps)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify the chain data of every anchor tx
+	txs, err := anchorTxs(tb.Timestamps)
+	if err != nil {
+		return nil, err
+	}
+	chains := make(map[string]rcv1.AnchorChain, len(tb.Anchors))
+	for _, v := range tb.Anchors {
+		chains[v.TxID] = v
+	}
+	info := make([]AnchorChainInfo, 0, len(txs))
+	for txID, merkleRoot := range txs {
+		ac, ok := chains[txID]
+		if !ok {
+			return nil, fmt.Errorf("chain data not found for anchor tx %v", txID)
+		}
+		aci, err := AnchorChainVerify(ac, merkleRoot, params)
+		if err != nil {
+			return nil, fmt.Errorf("could not verify anchor tx %v: %v", txID, err)
+		}
+		info = append(info, *aci)
+	}
+	sort.Slice(info, func(i, j int) bool {
+		return info[i].Height < info[j].Height
+	})
+
+	return info, nil
+}
+
+// AnchorChainVerify verifies that the anchor transaction commits to the
+// provided merkle root, that the transaction was included in the anchor
+// block, and that the block headers build a valid chain on top of the anchor
+// block. The proof of work of every block must be valid for the provided
+// network and the blocks must contain the minimum cumulative work of the
+// network.
+func AnchorChainVerify(ac rcv1.AnchorChain, merkleRoot string, params *chaincfg.Params) (*AnchorChainInfo, error) {
+	// Decode the anchor block
+	b, err := hex.DecodeString(ac.Block)
+	if err != nil {
+		return nil, err
+	}
+	var block wire.MsgBlock
+	err = block.Deserialize(bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("invalid block: %v", err)
+	}
+	if block.Header.Height != ac.Height {
+		return nil, fmt.Errorf("invalid block height: got %v, want %v",
+			block.Header.Height, ac.Height)
+	}
+
+	// Verify the anchor tx is included in the block and commits to
+	// the merkle root.
+	mr, err := hex.DecodeString(merkleRoot)
+	if err != nil {
+		return nil, err
+	}
+	if len(mr) != chainhash.HashSize {
+		return nil, fmt.Errorf("invalid merkle root %v", merkleRoot)
+	}
+	var tx *wire.MsgTx
+	for _, v := range block.Transactions {
+		if v.TxHash().String() == ac.TxID {
+			tx = v
+			break
+		}
+	}
+	if tx == nil {
+		return nil, fmt.Errorf("tx not found in block")
+	}
+	script := append([]byte{0x6a, 0x20}, mr...) // OP_RETURN OP_DATA_32
+	var found bool
+	for _, v := range tx.TxOut {
+		if bytes.Equal(v.PkScript, script) {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("merkle root %v not found in the tx outputs",
+			merkleRoot)
+	}
+
+	// Verify the block transactions match the header merkle root
+	err = verifyMerkleRoots(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify the block headers
+	err = verifyProofOfWork(block.Header, params)
+	if err != nil {
+		return nil, fmt.Errorf("block %v: %v", ac.Height, err)
+	}
+	var (
+		prev = block.Header
+		work = standalone.CalcWork(prev.Bits)
+	)
+	for i, v := range ac.Headers {
+		b, err := hex.DecodeString(v)
+		if err != nil {
+			return nil, err
+		}
+		var h wire.BlockHeader
+		err = h.FromBytes(b)
+		if err != nil {
+			return nil, fmt.Errorf("invalid header %v: %v", i, err)
+		}
+		if h.PrevBlock != prev.BlockHash() || h.Height != prev.Height+1 {
+			return nil, fmt.Errorf("header %v does not connect to block %v",
+				h.Height, prev.Height)
+		}
+		err = verifyProofOfWork(h, params)
+		if err != nil {
+			return nil, fmt.Errorf("block %v: %v", h.Height, err)
+		}
+		work.Add(work, standalone.CalcWork(h.Bits))
+		prev = h
+	}
+
+	// Verify the cumulative work of the blocks
+	if min, ok := minChainWork[params.Net]; ok && work.Cmp(min) < 0 {
+		return nil, fmt.Errorf("insufficient chain work: got %v, want %v",
+			work, min)
+	}
+
+	return &AnchorChainInfo{
+		TxID:          ac.TxID,
+		MerkleRoot:    merkleRoot,
+		BlockHash:     block.BlockHash().String(),
+		Height:        block.Header.Height,
+		Timestamp:     block.Header.Timestamp.Unix(),
+		Confirmations: uint32(len(ac.Headers)),
+		TipHash:       prev.BlockHash().String(),
+	}, nil
+}
+
+// verifyMerkleRoots verifies that the transactions of a block match the
+// merkle root of the block header. Blocks that were mined prior to the header
+// commitments agenda commit to the regular and stake transaction trees
+// separately. Blocks that were mined after it commit to the combined merkle
+// root of both trees.
+func verifyMerkleRoots(block wire.MsgBlock) error {
+	var (
+		combined = standalone.CalcCombinedTxTreeMerkleRoot(
+			block.Transactions, block.STransactions)
+		regularRoot = standalone.CalcTxTreeMerkleRoot(block.Transactions)
+		stakeRoot   = standalone.CalcTxTreeMerkleRoot(block.STransactions)
+	)
+	switch {
+	case block.Header.MerkleRoot == combined:
+		// Header commitments merkle root
+	case block.Header.MerkleRoot == regularRoot &&
+		block.Header.StakeRoot == stakeRoot:
+		// Legacy merkle roots
+	default:
+		return fmt.Errorf("block transactions do not match the merkle root")
+	}
+	return nil
+}
+
+// verifyProofOfWork verifies that the target difficulty of a block header is
+// within the proof of work limit of the network and that the proof of work
+// hash of the header is below the target difficulty.
+func verifyProofOfWork(h wire.BlockHeader, params *chaincfg.Params) error {
+	err := standalone.CheckProofOfWorkRange(h.Bits, params.PowLimit)
+	if err != nil {
+		return err
+	}
+	hash, err := powHash(h, params.Net)
+	if err != nil {
+		return err
+	}
+	return standalone.CheckProofOfWork(hash, h.Bits, params.PowLimit)
+}
+
+// powHash returns the proof of work hash of a block header. The BLAKE3 hash
+// of the header is the proof of work hash once the BLAKE3 proof of work agenda
+// has activated. The BLAKE-256 block hash is the proof of work hash prior to
+// that. An error is returned for networks whose activation height is not
+// known since the proof of work of their headers cannot be verified.
+func powHash(h wire.BlockHeader, net wire.CurrencyNet) (*chainhash.Hash, error) {
+	height, ok := blake3PowHeights[net]
+	if !ok {
+		return nil, fmt.Errorf("blake3 pow activation height unknown for "+
+			"network %v", net)
+	}
+	if h.Height < height {
+		hash := h.BlockHash()
+		return &hash, nil
+	}
+	b, err := h.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	hash := chainhash.Hash(blake3.Sum256(b))
+	return &hash, nil
+}
@@ -0,0 +1,406 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package client
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/decred/dcrd/blockchain/standalone"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/dcrd/chaincfg/v3"
+	"github.com/decred/dcrd/wire"
+	rcv1 "github.com/decred/politeia/politeiawww/api/records/v1"
+	"lukechampine.com/blake3"
+)
+
+func TestProofOfWorkMainNet(t *testing.T) {
+	params := chaincfg.MainNetParams()
+
+	// The following are mainnet block hashes and the target difficulty
+	// bits of the block.
+	var tests = []struct {
+		name    string
+		hash    string
+		bits    uint32
+		wantErr bool
+	}{
+		{
+			"block 1",
+			"000000000000437482b6d47f82f374cde539440ddb108b0a76886f0d87d126b9",
+			0x1b01ffff,
+			false,
+		},
+		{
+			"block 2",
+			"000000000000c41019872ff7db8fd2e9bfa05f42d3f8fee8e895e8c1e5b8dcba",
+			0x1b01ffff,
+			false,
+		},
+		{
+			"block 2 hash above a lower target",
+			"000000000000c41019872ff7db8fd2e9bfa05f42d3f8fee8e895e8c1e5b8dcba",
+			0x1a01ffff,
+			true,
+		},
+		{
+			"genesis block",
+			params.GenesisHash.String(),
+			params.GenesisBlock.Header.Bits,
+			true,
+		},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			hash, err := chainhash.NewHashFromStr(v.hash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = standalone.CheckProofOfWork(hash, v.bits, params.PowLimit)
+			switch {
+			case v.wantErr && err == nil:
+				t.Fatalf("got nil error, want error")
+			case !v.wantErr && err != nil:
+				t.Fatalf("got error %v, want nil", err)
+			}
+		})
+	}
+
+	// The genesis block header is a real mainnet header that does not
+	// satisfy its own target difficulty.
+	err := verifyProofOfWork(params.GenesisBlock.Header, params)
+	if err == nil {
+		t.Fatalf("genesis block header passed proof of work verification")
+	}
+
+	// A target difficulty above the mainnet proof of work limit is
+	// rejected regardless of the block hash.
+	h := params.GenesisBlock.Header
+	h.Bits = chaincfg.RegNetParams().PowLimitBits
+	err = verifyProofOfWork(h, params)
+	if err == nil {
+		t.Fatalf("target difficulty above the pow limit was accepted")
+	}
+}
+
+func TestPowHash(t *testing.T) {
+	h := chaincfg.MainNetParams().GenesisBlock.Header
+	height := blake3PowHeights[wire.MainNet]
+
+	// powHashAt returns the expected proof of work hash of the header at
+	// the provided height.
+	powHashAt := func(height uint32, useBlake3 bool) chainhash.Hash {
+		h := h
+		h.Height = height
+		if !useBlake3 {
+			return h.BlockHash()
+		}
+		b, err := h.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return blake3Sum(b)
+	}
+
+	var tests = []struct {
+		name    string
+		net     wire.CurrencyNet
+		height  uint32
+		want    chainhash.Hash
+		wantErr bool
+	}{
+		{
+			"mainnet prior to activation",
+			wire.MainNet,
+			height - 1,
+			powHashAt(height-1, false),
+			false,
+		},
+		{
+			"mainnet activation",
+			wire.MainNet,
+			height,
+			powHashAt(height, true),
+			false,
+		},
+		{
+			"mainnet after activation",
+			wire.MainNet,
+			height + 1,
+			powHashAt(height+1, true),
+			false,
+		},
+		{
+			"unknown activation height",
+			wire.TestNet3,
+			height,
+			chainhash.Hash{},
+			true,
+		},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			h := h
+			h.Height = v.height
+			hash, err := powHash(h, v.net)
+			switch {
+			case v.wantErr && err == nil:
+				t.Fatalf("got nil error, want error")
+			case !v.wantErr && err != nil:
+				t.Fatalf("got error %v, want nil", err)
+			case v.wantErr:
+				return
+			}
+			if *hash != v.want {
+				t.Fatalf("got %v, want %v", hash, v.want)
+			}
+		})
+	}
+
+	// Verify the BLAKE3 implementation against the BLAKE3 test vector of
+	// the empty input.
+	want := "af1349b9f5f9a1a6a0404dea36dcc9499bcb25c9adc112b7cc9a93cae41f3262"
+	got := blake3.Sum256(nil)
+	if hex.EncodeToString(got[:]) != want {
+		t.Fatalf("got BLAKE3 hash %x, want %v", got, want)
+	}
+}
+
+// blake3Sum returns the BLAKE3 hash of the provided data.
+func blake3Sum(b []byte) chainhash.Hash {
+	return chainhash.Hash(blake3.Sum256(b))
+}
+
+// mineHeader increments the nonce of the header until the proof of work
+// hashes of the header satisfy the provided function.
+func mineHeader(t *testing.T, h *wire.BlockHeader, accept func(blake256, blake3 bool) bool) {
+	t.Helper()
+
+	target := standalone.CompactToBig(h.Bits)
+	below := func(hash chainhash.Hash) bool {
+		return standalone.HashToBig(&hash).Cmp(target) <= 0
+	}
+	for i := 0; i < 1000; i++ {
+		b, err := h.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if accept(below(h.BlockHash()), below(blake3Sum(b))) {
+			return
+		}
+		h.Nonce++
+	}
+	t.Fatalf("could not mine header")
+}
+
+// newTestAnchorChain returns an anchor chain for the provided merkle root that
+// is mined using the regnet pow limit. The confirmation headers only satisfy
+// the target difficulty using their BLAKE-256 hash.
+func newTestAnchorChain(t *testing.T, mr []byte, confirmations int) rcv1.AnchorChain {
+	t.Helper()
+
+	var (
+		params   = chaincfg.RegNetParams()
+		blake256 = func(blake256, blake3 bool) bool {
+			return blake256 && !blake3
+		}
+	)
+
+	// Setup the anchor block
+	coinbase := wire.NewMsgTx()
+	coinbase.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{},
+		wire.MaxPrevOutIndex, wire.TxTreeRegular), 0, nil))
+	coinbase.AddTxOut(wire.NewTxOut(0, []byte{0x51}))
+	tx := wire.NewMsgTx()
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{1}, 0,
+		wire.TxTreeRegular), 0, nil))
+	tx.AddTxOut(wire.NewTxOut(0, append([]byte{0x6a, 0x20}, mr...)))
+	block := wire.MsgBlock{
+		Header: wire.BlockHeader{
+			Version:   1,
+			PrevBlock: params.GenesisHash,
+			Bits:      params.PowLimitBits,
+			Height:    100,
+			Timestamp: time.Unix(time.Now().Unix(), 0),
+		},
+		Transactions: []*wire.MsgTx{coinbase, tx},
+	}
+	block.Header.MerkleRoot = standalone.CalcCombinedTxTreeMerkleRoot(
+		block.Transactions, nil)
+	mineHeader(t, &block.Header, blake256)
+	var buf bytes.Buffer
+	err := block.Serialize(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Setup the confirmation headers
+	var (
+		headers = make([]string, 0, confirmations)
+		prev    = block.Header
+	)
+	for i := 0; i < confirmations; i++ {
+		h := prev
+		h.PrevBlock = prev.BlockHash()
+		h.Height++
+		h.Nonce = 0
+		mineHeader(t, &h, blake256)
+		b, err := h.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		headers = append(headers, hex.EncodeToString(b))
+		prev = h
+	}
+
+	return rcv1.AnchorChain{
+		TxID:    tx.TxHash().String(),
+		Height:  block.Header.Height,
+		Block:   hex.EncodeToString(buf.Bytes()),
+		Headers: headers,
+	}
+}
+
+func TestAnchorChainVerify(t *testing.T) {
+	var (
+		params = chaincfg.RegNetParams()
+		mr     = blake3Sum([]byte("merkle root"))
+		ac     = newTestAnchorChain(t, mr[:], 6)
+	)
+
+	// The BLAKE3 proof of work activation height of regnet is not
+	// known. Set it above the anchor chain so that the BLAKE-256 proof
+	// of work of the anchor chain is verified.
+	blake3PowHeights[params.Net] = ac.Height + 1000
+	defer delete(blake3PowHeights, params.Net)
+
+	// Verify a valid anchor chain
+	aci, err := AnchorChainVerify(ac, hex.EncodeToString(mr[:]), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if aci.Confirmations != 6 || aci.Height != ac.Height ||
+		aci.TxID != ac.TxID {
+		t.Fatalf("got anchor chain info %+v", aci)
+	}
+
+	// headerSet replaces the header at the provided index
+	headerSet := func(ac rcv1.AnchorChain, i int, update func(*wire.BlockHeader)) rcv1.AnchorChain {
+		b, err := hex.DecodeString(ac.Headers[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		var h wire.BlockHeader
+		err = h.FromBytes(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		update(&h)
+		b, err = h.Bytes()
+		if err != nil {
+			t.Fatal(err)
+		}
+		headers := make([]string, len(ac.Headers))
+		copy(headers, ac.Headers)
+		headers[i] = hex.EncodeToString(b)
+		ac.Headers = headers
+		return ac
+	}
+
+	var tests = []struct {
+		name       string
+		ac         rcv1.AnchorChain
+		merkleRoot string
+		params     *chaincfg.Params
+		setup      func() func()
+	}{
+		{
+			"merkle root not committed to",
+			ac,
+			hex.EncodeToString(make([]byte, chainhash.HashSize)),
+			params,
+			nil,
+		},
+		{
+			"header does not connect",
+			headerSet(ac, 2, func(h *wire.BlockHeader) {
+				h.PrevBlock = chainhash.Hash{}
+				mineHeader(t, h, func(b256, b3 bool) bool { return b256 })
+			}),
+			hex.EncodeToString(mr[:]),
+			params,
+			nil,
+		},
+		{
+			"header with invalid proof of work",
+			headerSet(ac, 5, func(h *wire.BlockHeader) {
+				mineHeader(t, h, func(b256, b3 bool) bool {
+					return !b256 && !b3
+				})
+			}),
+			hex.EncodeToString(mr[:]),
+			params,
+			nil,
+		},
+		{
+			"target difficulty above the mainnet pow limit",
+			ac,
+			hex.EncodeToString(mr[:]),
+			chaincfg.MainNetParams(),
+			nil,
+		},
+		{
+			"BLAKE3 proof of work after activation",
+			ac,
+			hex.EncodeToString(mr[:]),
+			params,
+			func() func() {
+				height := blake3PowHeights[params.Net]
+				blake3PowHeights[params.Net] = ac.Height + 3
+				return func() {
+					blake3PowHeights[params.Net] = height
+				}
+			},
+		},
+		{
+			"insufficient chain work",
+			ac,
+			hex.EncodeToString(mr[:]),
+			params,
+			func() func() {
+				work := standalone.CalcWork(params.PowLimitBits)
+				minChainWork[params.Net] = work.Mul(work, big.NewInt(8))
+				return func() {
+					delete(minChainWork, params.Net)
+				}
+			},
+		},
+	}
+	for _, v := range tests {
+		t.Run(v.name, func(t *testing.T) {
+			if v.setup != nil {
+				cleanup := v.setup()
+				defer cleanup()
+			}
+			_, err := AnchorChainVerify(v.ac, v.merkleRoot, v.params)
+			if err == nil {
+				t.Fatalf("got nil error, want error")
+			}
+		})
+	}
+
+	// The chain work of the anchor block and the 6 confirmations is
+	// sufficient for a minimum of 7 blocks of work.
+	work := standalone.CalcWork(params.PowLimitBits)
+	minChainWork[params.Net] = work.Mul(work, big.NewInt(7))
+	defer delete(minChainWork, params.Net)
+	_, err = AnchorChainVerify(ac, hex.EncodeToString(mr[:]), params)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
@@ -73,6 +73,14 @@ func (c *cmdCastBallot) Execute(args []string) error {
 	}
 	voteDetails := dr.Vote
 
+	// The salts of a secret ballot vote must be kept until the votes
+	// are revealed. politeiavoter journals the salts and reveals the
+	// votes once the voting period has ended.
+	if voteDetails.Params.RevealDuration != 0 {
+		return fmt.Errorf("secret ballot votes must be cast using " +
+			"politeiavoter")
+	}
+
 	// Verify provided vote ID
 	var voteBit string
 	for _, option := range voteDetails.Params.Options {
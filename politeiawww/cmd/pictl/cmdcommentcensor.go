@@ -89,7 +89,7 @@ func (c *cmdCommentCensor) Execute(args []string) error {
 	if err != nil {
 		return err
 	}
-	err = pclient.CommentVerify(dr.Comment, vr.PubKey)
+	err = commentVerify(dr.Comment, vr)
 	if err != nil {
 		return err
 	}
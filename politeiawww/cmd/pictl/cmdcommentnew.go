@@ -89,7 +89,7 @@ func (c *cmdCommentNew) Execute(args []string) error {
 	if err != nil {
 		return err
 	}
-	err = pclient.CommentVerify(nr.Comment, vr.PubKey)
+	err = commentVerify(nr.Comment, vr)
 	if err != nil {
 		return err
 	}
@@ -87,7 +87,12 @@ func (c *cmdCommentVote) Execute(args []string) error {
 	if err != nil {
 		return err
 	}
-	serverID, err := util.IdentityFromString(vr.PubKey)
+	serverPubKey, err := pclient.ServerPubKeyAt(vr.PubKey,
+		vr.PubKeyHistory, cvr.Timestamp)
+	if err != nil {
+		return err
+	}
+	serverID, err := util.IdentityFromString(serverPubKey)
 	if err != nil {
 		return err
 	}
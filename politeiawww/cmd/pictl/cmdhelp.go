@@ -81,12 +81,16 @@ func (c *cmdHelp) Execute(args []string) error {
 		fmt.Printf("%s\n", proposalDetailsHelpMsg)
 	case "proposaltimestamps":
 		fmt.Printf("%s\n", proposalTimestampsHelpMsg)
+	case "proposaldiff":
+		fmt.Printf("%s\n", proposalDiffHelpMsg)
 	case "proposals":
 		fmt.Printf("%s\n", proposalsHelpMsg)
 	case "proposalinv":
 		fmt.Printf("%s\n", proposalInvHelpMsg)
 	case "proposalinvordered":
 		fmt.Printf("%s\n", proposalInvOrderedHelpMsg)
+	case "proposalsearch":
+		fmt.Printf("%s\n", proposalSearchHelpMsg)
 	case "userproposals":
 		fmt.Printf("%s\n", userProposalsHelpMsg)
 
@@ -115,6 +119,8 @@ func (c *cmdHelp) Execute(args []string) error {
 		fmt.Printf("%s\n", voteAuthorizeHelpMsg)
 	case "votestart":
 		fmt.Printf("%s\n", voteStartHelpMsg)
+	case "votecancel":
+		fmt.Printf("%s\n", voteCancelHelpMsg)
 	case "castballot":
 		fmt.Printf("%s\n", castBallotHelpMsg)
 	case "votedetails":
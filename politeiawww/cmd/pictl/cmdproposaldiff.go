@@ -0,0 +1,73 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	rcv1 "github.com/decred/politeia/politeiawww/api/records/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
+)
+
+// cmdProposalDiff retrieves the changes between two versions of a proposal.
+type cmdProposalDiff struct {
+	Args struct {
+		Token string `positional-arg-name:"token" required:"true"`
+		From  uint32 `positional-arg-name:"from" required:"true"`
+		To    uint32 `positional-arg-name:"to" optional:"true"`
+	} `positional-args:"true"`
+}
+
+// Execute executes the cmdProposalDiff command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdProposalDiff) Execute(args []string) error {
+	// Setup client
+	opts := pclient.Opts{
+		HTTPSCert:  cfg.HTTPSCert,
+		Cookies:    cfg.Cookies,
+		HeaderCSRF: cfg.CSRF,
+		Verbose:    cfg.Verbose,
+		RawJSON:    cfg.RawJSON,
+	}
+	pc, err := pclient.New(cfg.Host, opts)
+	if err != nil {
+		return err
+	}
+
+	// Get diff
+	d := rcv1.Diff{
+		Token: c.Args.Token,
+		From:  c.Args.From,
+		To:    c.Args.To,
+	}
+	dr, err := pc.RecordDiff(d)
+	if err != nil {
+		return err
+	}
+
+	// Print diff
+	printJSON(dr)
+
+	return nil
+}
+
+// proposalDiffHelpMsg is printed to stdout by the help command.
+const proposalDiffHelpMsg = `proposaldiff "token" from [to]
+
+Fetch the changes between two versions of a proposal. The reply contains the
+files that were added, removed, or modified, a line level diff of the text
+files, the metadata changes, and the status changes that occurred between the
+two versions.
+
+If the to version is not provided, the most recent version of the proposal is
+used.
+
+Unvetted proposal file diffs are only returned to admins and the proposal
+author.
+
+Arguments:
+1. token  (string, required) Record token
+2. from   (uint32, required) Record version to diff from
+3. to     (uint32, optional) Record version to diff to
+`
@@ -36,6 +36,8 @@ type cmdProposalEdit struct {
 	Name   string `long:"name" optional:"true"`
 	LinkTo string `long:"linkto" optional:"true"`
 	LinkBy string `long:"linkby" optional:"true"`
+	Budget uint64 `long:"budget" optional:"true"`
+	Amount uint64 `long:"amount" optional:"true"`
 
 	// RFP is a flag that is intended to make submitting an RFP easier
 	// by calculating and inserting a linkby timestamp automatically
@@ -190,6 +192,8 @@ func proposalEdit(c *cmdProposalEdit) (*rcv1.Record, error) {
 		}
 		linkBy = vm.LinkBy
 		c.LinkTo = vm.LinkTo
+		c.Budget = vm.Budget
+		c.Amount = vm.Amount
 	case c.RFP:
 		// Set linkby to a month from now
 		linkBy = time.Now().Add(time.Hour * 24 * 30).Unix()
@@ -205,6 +209,8 @@ func proposalEdit(c *cmdProposalEdit) (*rcv1.Record, error) {
 		vm := piv1.VoteMetadata{
 			LinkTo: c.LinkTo,
 			LinkBy: linkBy,
+			Budget: c.Budget,
+			Amount: c.Amount,
 		}
 		vmb, err := json.Marshal(vm)
 		if err != nil {
@@ -239,7 +245,7 @@ func proposalEdit(c *cmdProposalEdit) (*rcv1.Record, error) {
 	if err != nil {
 		return nil, err
 	}
-	err = pclient.RecordVerify(er.Record, vr.PubKey)
+	err = recordVerify(er.Record, vr)
 	if err != nil {
 		return nil, fmt.Errorf("unable to verify record: %v", err)
 	}
@@ -289,6 +295,15 @@ Flags:
                          month from the current time. This is intended to be
                          used in place of --linkby.
 
+ --budget       (uint64) Total budget of the RFP in USD cents. The RFP
+                         submissions that pass the runoff vote are funded in
+                         order of net approval until the budget has been
+                         exhausted. Can only be used on an RFP.
+
+ --amount       (uint64) Amount in USD cents that an RFP submission is
+                         requesting from the RFP budget. Required when the RFP
+                         has set a budget.
+
  --random       (bool)   Generate random proposal data, not including
                          attachments. The indexFile argument is not allowed
                          when using this flag.
@@ -30,6 +30,8 @@ type cmdProposalNew struct {
 	Name   string `long:"name" optional:"true"`
 	LinkTo string `long:"linkto" optional:"true"`
 	LinkBy string `long:"linkby" optional:"true"`
+	Budget uint64 `long:"budget" optional:"true"`
+	Amount uint64 `long:"amount" optional:"true"`
 
 	// RFP is a flag that is intended to make submitting an RFP easier
 	// by calculating and inserting a linkby timestamp automatically
@@ -169,6 +171,8 @@ func proposalNew(c *cmdProposalNew) (*rcv1.Record, error) {
 		vm := piv1.VoteMetadata{
 			LinkTo: c.LinkTo,
 			LinkBy: linkBy,
+			Budget: c.Budget,
+			Amount: c.Amount,
 		}
 		vmb, err := json.Marshal(vm)
 		if err != nil {
@@ -209,7 +213,7 @@ func proposalNew(c *cmdProposalNew) (*rcv1.Record, error) {
 	if err != nil {
 		return nil, err
 	}
-	err = pclient.RecordVerify(nr.Record, vr.PubKey)
+	err = recordVerify(nr.Record, vr)
 	if err != nil {
 		return nil, fmt.Errorf("unable to verify record: %v", err)
 	}
@@ -254,6 +258,15 @@ Flags:
                          month from the current time. This is intended to be
                          used in place of --linkby.
 
+ --budget       (uint64) Total budget of the RFP in USD cents. The RFP
+                         submissions that pass the runoff vote are funded in
+                         order of net approval until the budget has been
+                         exhausted. Can only be used on an RFP.
+
+ --amount       (uint64) Amount in USD cents that an RFP submission is
+                         requesting from the RFP budget. Required when the RFP
+                         has set a budget.
+
  --random       (bool)   Generate random proposal data, not including
                          attachments. The indexFile argument is not allowed
                          when using this flag.
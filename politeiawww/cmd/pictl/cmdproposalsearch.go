@@ -0,0 +1,86 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	rcv1 "github.com/decred/politeia/politeiawww/api/records/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
+)
+
+// cmdProposalSearch searches the proposal text and names for the provided
+// query and returns a page of the matching proposals, ranked by relevance.
+type cmdProposalSearch struct {
+	Args struct {
+		Query string `positional-arg-name:"query" required:"true"`
+		Page  uint32 `positional-arg-name:"page"`
+	} `positional-args:"true"`
+
+	// Unvetted is used to search the unvetted proposals. If this flag
+	// is not used the command searches the vetted proposals.
+	Unvetted bool `long:"unvetted" optional:"true"`
+}
+
+// Execute executes the cmdProposalSearch command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdProposalSearch) Execute(args []string) error {
+	// Setup client
+	opts := pclient.Opts{
+		HTTPSCert:  cfg.HTTPSCert,
+		Cookies:    cfg.Cookies,
+		HeaderCSRF: cfg.CSRF,
+		Verbose:    cfg.Verbose,
+		RawJSON:    cfg.RawJSON,
+	}
+	pc, err := pclient.New(cfg.Host, opts)
+	if err != nil {
+		return err
+	}
+
+	// Setup state
+	state := rcv1.RecordStateVetted
+	if c.Unvetted {
+		state = rcv1.RecordStateUnvetted
+	}
+
+	// Search proposals
+	s := rcv1.Search{
+		Query: c.Args.Query,
+		State: state,
+		Page:  c.Args.Page,
+	}
+	sr, err := pc.RecordSearch(s)
+	if err != nil {
+		return err
+	}
+
+	// Print results
+	printJSON(sr)
+
+	return nil
+}
+
+// proposalSearchHelpMsg is printed to stdout by the help command.
+const proposalSearchHelpMsg = `proposalsearch "query" [page]
+
+Search the proposal names and proposal text for the provided query. A page of
+matching proposals is returned, ranked by relevance. Proposals that contain a
+query term in their name are ranked above proposals that only contain the term
+in their text. Censored proposals are never returned.
+
+Unvetted proposals can only be searched by admins.
+
+If no page number is provided this command defaults to requesting page 1.
+
+Arguments:
+1. query  (string, required) Search query.
+2. page   (uint32, optional) Page number.
+
+Flags:
+ --unvetted (bool, optional) Search unvetted proposals.
+
+Example:
+$ pictl proposalsearch "treasury report" 2
+`
@@ -105,7 +105,7 @@ func proposalSetStatus(c *cmdProposalSetStatus) (*rcv1.Record, error) {
 	if err != nil {
 		return nil, err
 	}
-	err = pclient.RecordVerify(ssr.Record, vr.PubKey)
+	err = recordVerify(ssr.Record, vr)
 	if err != nil {
 		return nil, fmt.Errorf("unable to verify record: %v", err)
 	}
@@ -5,6 +5,13 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/decred/dcrd/chaincfg/v3"
+	backend "github.com/decred/politeia/politeiad/backendv2"
+	"github.com/decred/politeia/politeiad/plugins/dcrdata"
 	rcv1 "github.com/decred/politeia/politeiawww/api/records/v1"
 	pclient "github.com/decred/politeia/politeiawww/client"
 )
@@ -15,6 +22,14 @@ type cmdProposalTimestamps struct {
 		Token   string `positional-arg-name:"token" required:"true"`
 		Version uint32 `positional-arg-name:"version" optional:"true"`
 	} `positional-args:"true"`
+
+	// Bundle saves the timestamps to a timestamps bundle file that
+	// can be verified offline using politeiaverify.
+	Bundle bool `long:"bundle" optional:"true"`
+
+	// Dcrdata is the dcrdata host that the chain data of the bundle
+	// is retrieved from.
+	Dcrdata string `long:"dcrdata" optional:"true"`
 }
 
 // Execute executes the cmdProposalTimestamps command.
@@ -50,12 +65,70 @@ func (c *cmdProposalTimestamps) Execute(args []string) error {
 		return err
 	}
 
+	// Save the timestamps bundle
+	if c.Bundle {
+		return timestampsBundleSave(*tr, c.Dcrdata)
+	}
+
 	// Print timestamps
 	printJSON(tr)
 
 	return nil
 }
 
+// timestampsBundleSave creates a timestamps bundle for the provided record
+// timestamps and saves it to the current directory. The bundle file is named
+// the same way as the politeiagui timestamp downloads so that it can be passed
+// directly to politeiaverify.
+func timestampsBundleSave(tr rcv1.TimestampsReply, dcrdataHost string) error {
+	// The chain data is retrieved for and verified against the network
+	// of the politeiawww instance.
+	vr, err := client.Version()
+	if err != nil {
+		return err
+	}
+	var (
+		params      = chaincfg.MainNetParams()
+		defaultHost = dcrdata.SettingHostHTTPMainNet
+	)
+	if vr.TestNet {
+		params = chaincfg.TestNet3Params()
+		defaultHost = dcrdata.SettingHostHTTPTestNet
+	}
+	if dcrdataHost == "" {
+		dcrdataHost = defaultHost
+	}
+	tb, err := pclient.TimestampsBundleNew(dcrdataHost, tr,
+		pclient.BundleConfirmations)
+	if err != nil {
+		return err
+	}
+	_, err = pclient.TimestampsBundleVerify(*tb, params)
+	if err != nil {
+		return err
+	}
+
+	// Save the bundle
+	var rm backend.RecordMetadata
+	err = json.Unmarshal([]byte(tr.RecordMetadata.Data), &rm)
+	if err != nil {
+		return fmt.Errorf("could not unmarshal record metadata: %v", err)
+	}
+	fp := fmt.Sprintf("%v-v%v-timestamps-bundle.json", rm.Token, rm.Version)
+	b, err := json.MarshalIndent(tb, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(fp, b, 0644)
+	if err != nil {
+		return err
+	}
+
+	printf("Timestamps bundle saved to %v\n", fp)
+
+	return nil
+}
+
 // proposalTimestampsHelpMsg is printed to stdout by the help command.
 const proposalTimestampsHelpMsg = `proposaltimestamps [flags] "token" "version"
 
@@ -66,7 +139,18 @@ decred blockchain.
 This command defaults to requesting vetted proposals unless the --unvetted flag
 is used.
 
+The --bundle flag saves the timestamps to a timestamps bundle file in the
+current directory instead of printing them. The bundle contains the chain data
+of the anchor transactions, which is retrieved from dcrdata, and can be
+verified offline using politeiaverify.
+
 Arguments:
 1. token    (string, required) Record token
 2. version  (uint32, optional) Record version
+
+Flags:
+  --bundle   (bool, optional)    Save a timestamps bundle.
+  --dcrdata  (string, optional)  Dcrdata host of the bundle chain data.
+                                 Defaults to the dcrdata host of the
+                                 politeiawww network.
 `
@@ -99,7 +99,12 @@ func (c *cmdVoteAuthorize) Execute(args []string) error {
 	if err != nil {
 		return err
 	}
-	serverID, err := util.IdentityFromString(vr.PubKey)
+	serverPubKey, err := pclient.ServerPubKeyAt(vr.PubKey,
+		vr.PubKeyHistory, ar.Timestamp)
+	if err != nil {
+		return err
+	}
+	serverID, err := util.IdentityFromString(serverPubKey)
 	if err != nil {
 		return err
 	}
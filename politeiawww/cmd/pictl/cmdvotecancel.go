@@ -0,0 +1,108 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	tkv1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
+	"github.com/decred/politeia/politeiawww/cmd/shared"
+	"github.com/decred/politeia/util"
+)
+
+// cmdVoteCancel cancels an ongoing ticket vote.
+type cmdVoteCancel struct {
+	Args struct {
+		Token  string `positional-arg-name:"token" required:"true"`
+		Reason string `positional-arg-name:"reason" required:"true"`
+	} `positional-args:"true"`
+}
+
+// Execute executes the cmdVoteCancel command.
+//
+// This function satisfies the go-flags Commander interface.
+func (c *cmdVoteCancel) Execute(args []string) error {
+	// Verify user identity. An identity is required to sign the vote
+	// cancellation.
+	if cfg.Identity == nil {
+		return shared.ErrUserIdentityNotFound
+	}
+
+	// Setup client
+	opts := pclient.Opts{
+		HTTPSCert:  cfg.HTTPSCert,
+		Cookies:    cfg.Cookies,
+		HeaderCSRF: cfg.CSRF,
+		Verbose:    cfg.Verbose,
+		RawJSON:    cfg.RawJSON,
+	}
+	pc, err := pclient.New(cfg.Host, opts)
+	if err != nil {
+		return err
+	}
+
+	// Setup request
+	msg := c.Args.Token + c.Args.Reason
+	sig := cfg.Identity.SignMessage([]byte(msg))
+	cl := tkv1.Cancel{
+		Token:     c.Args.Token,
+		Reason:    c.Args.Reason,
+		PublicKey: cfg.Identity.Public.String(),
+		Signature: hex.EncodeToString(sig[:]),
+	}
+
+	// Send request
+	cr, err := pc.TicketVoteCancel(cl)
+	if err != nil {
+		return err
+	}
+
+	// Verify receipt
+	vr, err := client.Version()
+	if err != nil {
+		return err
+	}
+	serverPubKey, err := pclient.ServerPubKeyAt(vr.PubKey,
+		vr.PubKeyHistory, cr.Timestamp)
+	if err != nil {
+		return err
+	}
+	serverID, err := util.IdentityFromString(serverPubKey)
+	if err != nil {
+		return err
+	}
+	s, err := util.ConvertSignature(cr.Receipt)
+	if err != nil {
+		return err
+	}
+	if !serverID.VerifyMessage([]byte(cl.Signature), s) {
+		return fmt.Errorf("could not verify receipt")
+	}
+
+	// Print receipt
+	printf("Token    : %v\n", cl.Token)
+	printf("Reason   : %v\n", cl.Reason)
+	printf("Timestamp: %v\n", timestampFromUnix(cr.Timestamp))
+	printf("Receipt  : %v\n", cr.Receipt)
+
+	return nil
+}
+
+// voteCancelHelpMsg is printed to stdout by the help command.
+const voteCancelHelpMsg = `votecancel "token" "reason"
+
+Cancel an ongoing ticket vote. Requires admin privileges.
+
+A cancelled vote is ended immediately and no further ballots are accepted. The
+votes that have already been cast are kept, but the vote has no outcome and
+the proposal is locked. Runoff votes cannot be cancelled.
+
+The reason is recorded with the cancellation and is publicly visible.
+
+Arguments:
+1. token   (string, required)  Record token.
+2. reason  (string, required)  Reason for cancelling the vote.`
@@ -53,6 +53,11 @@ func (c *cmdVoteDetails) Execute(args []string) error {
 		fmt.Printf("Vote details\n")
 		printVoteDetails(*dr.Vote)
 	}
+	if dr.Cancel != nil {
+		printf("\n")
+		fmt.Printf("Vote cancellation\n")
+		printCancelDetails(*dr.Cancel)
+	}
 
 	return nil
 }
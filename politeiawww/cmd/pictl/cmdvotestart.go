@@ -8,6 +8,7 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"strings"
 
 	"github.com/decred/politeia/politeiad/plugins/ticketvote"
 	rcv1 "github.com/decred/politeia/politeiawww/api/records/v1"
@@ -33,6 +34,16 @@ type cmdVoteStart struct {
 	// Runoff is used to indicate the vote is a runoff vote and the
 	// provided token is the parent token of the runoff vote.
 	Runoff bool `long:"runoff" optional:"true"`
+
+	// Options is used to start a multiple choice vote. Each option
+	// must be in the format id:description. The vote option bits are
+	// assigned in the order that the options are provided.
+	Options []string `long:"option" optional:"true"`
+
+	// RevealDuration is used to start a secret ballot vote. It is the
+	// duration in blocks of the reveal window that begins once the
+	// voting period has ended.
+	RevealDuration uint32 `long:"revealduration" optional:"true"`
 }
 
 // Execute executes the cmdVoteStart command.
@@ -78,13 +89,28 @@ func (c *cmdVoteStart) Execute(args []string) error {
 	}
 
 	var sr *tkv1.StartReply
-	if c.Runoff {
+	switch {
+	case c.Runoff && len(c.Options) > 0:
+		return fmt.Errorf("--runoff and --option cannot be used together")
+	case c.Runoff && c.RevealDuration != 0:
+		return fmt.Errorf("runoff votes cannot be secret ballot votes")
+	case c.Runoff:
 		sr, err = voteStartRunoff(token, duration, quorum, pass, pc)
 		if err != nil {
 			return err
 		}
-	} else {
-		sr, err = voteStartStandard(token, duration, quorum, pass, pc)
+	case len(c.Options) > 0:
+		// Multiple choice votes default to a plurality vote. A pass
+		// percentage is only used if one was provided.
+		pass = c.Args.PassPercentage
+		sr, err = voteStartMultipleChoice(token, duration, quorum, pass,
+			c.RevealDuration, c.Options, pc)
+		if err != nil {
+			return err
+		}
+	default:
+		sr, err = voteStartStandard(token, duration, quorum, pass,
+			c.RevealDuration, pc)
 		if err != nil {
 			return err
 		}
@@ -95,11 +121,14 @@ func (c *cmdVoteStart) Execute(args []string) error {
 	printf("StartBlockHash  : %v\n", sr.StartBlockHash)
 	printf("StartBlockHeight: %v\n", sr.StartBlockHeight)
 	printf("EndBlockHeight  : %v\n", sr.EndBlockHeight)
+	if c.RevealDuration != 0 {
+		printf("RevealEndHeight : %v\n", sr.EndBlockHeight+c.RevealDuration)
+	}
 
 	return nil
 }
 
-func voteStartStandard(token string, duration, quorum, pass uint32, pc *pclient.Client) (*tkv1.StartReply, error) {
+func voteStartStandard(token string, duration, quorum, pass, reveal uint32, pc *pclient.Client) (*tkv1.StartReply, error) {
 	// Get record version
 	d := rcv1.Details{
 		Token: token,
@@ -130,6 +159,74 @@ func voteStartStandard(token string, duration, quorum, pass uint32, pc *pclient.
 				Bit:         0x02,
 			},
 		},
+		RevealDuration: reveal,
+	}
+	vpb, err := json.Marshal(vp)
+	if err != nil {
+		return nil, err
+	}
+	msg := hex.EncodeToString(util.Digest(vpb))
+	b := cfg.Identity.SignMessage([]byte(msg))
+	signature := hex.EncodeToString(b[:])
+	s := tkv1.Start{
+		Starts: []tkv1.StartDetails{
+			{
+				Params:    vp,
+				PublicKey: cfg.Identity.Public.String(),
+				Signature: signature,
+			},
+		},
+	}
+
+	// Send request
+	return pc.TicketVoteStart(s)
+}
+
+func voteStartMultipleChoice(token string, duration, quorum, pass, reveal uint32, options []string, pc *pclient.Client) (*tkv1.StartReply, error) {
+	// Parse vote options
+	if len(options) < 2 || len(options) > tkv1.VoteOptionsMax {
+		return nil, fmt.Errorf("multiple choice votes require 2 to %v "+
+			"options", tkv1.VoteOptionsMax)
+	}
+	var (
+		mask uint64
+		vo   = make([]tkv1.VoteOption, 0, len(options))
+	)
+	for i, v := range options {
+		s := strings.SplitN(v, ":", 2)
+		if len(s) != 2 || s[0] == "" {
+			return nil, fmt.Errorf("invalid option '%v'; format should "+
+				"be 'id:description'", v)
+		}
+		bit := uint64(1) << uint(i)
+		mask |= bit
+		vo = append(vo, tkv1.VoteOption{
+			ID:          s[0],
+			Description: s[1],
+			Bit:         bit,
+		})
+	}
+
+	// Get record version
+	d := rcv1.Details{
+		Token: token,
+	}
+	r, err := pc.RecordDetails(d)
+	if err != nil {
+		return nil, err
+	}
+
+	// Setup request
+	vp := tkv1.VoteParams{
+		Token:            token,
+		Version:          r.Version,
+		Type:             tkv1.VoteTypeMultipleChoice,
+		Mask:             mask,
+		Duration:         duration,
+		QuorumPercentage: quorum,
+		PassPercentage:   pass,
+		Options:          vo,
+		RevealDuration:   reveal,
 	}
 	vpb, err := json.Marshal(vp)
 	if err != nil {
@@ -230,6 +327,17 @@ Start the voting period for a proposal. Requires admin privileges.
 If the vote is a runoff vote then the --runoff flag must be used. The provided
 token should be the parent token of the runoff vote.
 
+A multiple choice vote is started by providing between 2 and 16 --option
+flags. The vote option with the most votes wins, provided that the quorum has
+been met. A multiple choice vote does not use a pass percentage unless one is
+provided, in which case the winning option must also receive at least the pass
+percentage of the cast votes.
+
+A secret ballot vote is started by providing the --revealduration flag. The
+votes of a secret ballot vote are cast as commitments and are revealed once
+the voting period has ended, during a reveal window of the provided number of
+blocks. Runoff votes cannot be secret ballot votes.
+
 Arguments:
 1. token             (string, required)  Proposal censorship token
 2. duration          (uint32, optional)  Duration of vote in blocks
@@ -239,5 +347,14 @@ Arguments:
 4. passpercentage    (uint32, optional)  Percent of cast votes required for
                                          vote to be approved (default: 60)
 Flags:
- --runoff  (bool, optional)  Start a runoff vote.
+ --runoff          (bool, optional)    Start a runoff vote.
+ --option          (string, optional)  Vote option of a multiple choice vote.
+                                       The format is id:description. Can be
+                                       provided multiple times.
+ --revealduration  (uint32, optional)  Duration of the reveal window of a
+                                       secret ballot vote in blocks.
+
+Example:
+votestart <token> --option=a:"Design A" --option=b:"Design B" \
+  --option=c:"Design C"
 `
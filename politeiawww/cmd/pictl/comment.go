@@ -9,6 +9,8 @@ import (
 	"strings"
 
 	cmv1 "github.com/decred/politeia/politeiawww/api/comments/v1"
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
+	pclient "github.com/decred/politeia/politeiawww/client"
 )
 
 func printComment(c cmv1.Comment) {
@@ -68,3 +70,14 @@ func printCommentVotes(votes []cmv1.CommentVote) {
 			timestampFromUnix(v.Timestamp), v.CommentID, v.Vote)
 	}
 }
+
+// commentVerify verifies a comment using the server public key that was
+// active at the comment timestamp.
+func commentVerify(c cmv1.Comment, vr *www.VersionReply) error {
+	serverPubKey, err := pclient.ServerPubKeyAt(vr.PubKey, vr.PubKeyHistory,
+		c.Timestamp)
+	if err != nil {
+		return err
+	}
+	return pclient.CommentVerify(c, serverPubKey)
+}
@@ -70,9 +70,11 @@ type pictl struct {
 	ProposalSetStatus  cmdProposalSetStatus  `command:"proposalsetstatus"`
 	ProposalDetails    cmdProposalDetails    `command:"proposaldetails"`
 	ProposalTimestamps cmdProposalTimestamps `command:"proposaltimestamps"`
+	ProposalDiff       cmdProposalDiff       `command:"proposaldiff"`
 	Proposals          cmdProposals          `command:"proposals"`
 	ProposalInv        cmdProposalInv        `command:"proposalinv"`
 	ProposalInvOrdered cmdProposalInvOrdered `command:"proposalinvordered"`
+	ProposalSearch     cmdProposalSearch     `command:"proposalsearch"`
 	UserProposals      cmdUserProposals      `command:"userproposals"`
 
 	// Comments commands
@@ -89,6 +91,7 @@ type pictl struct {
 	VotePolicy      cmdVotePolicy      `command:"votepolicy"`
 	VoteAuthorize   cmdVoteAuthorize   `command:"voteauthorize"`
 	VoteStart       cmdVoteStart       `command:"votestart"`
+	VoteCancel      cmdVoteCancel      `command:"votecancel"`
 	CastBallot      cmdCastBallot      `command:"castballot"`
 	VoteDetails     cmdVoteDetails     `command:"votedetails"`
 	VoteResults     cmdVoteResults     `command:"voteresults"`
@@ -158,9 +161,11 @@ Proposal commands
   proposalstatusset       (admin)  Set the status of a proposal
   proposaldetails         (public) Get a full proposal record
   proposaltimestamps      (public) Get timestamps for a proposal
+  proposaldiff            (public) Get the changes between proposal versions
   proposals               (public) Get proposals without their files
   proposalinv             (public) Get inventory by proposal status
   proposalinvordered      (public) Get inventory ordered chronologically
+  proposalsearch          (public) Search proposals by keyword
   userproposals           (public) Get proposals submitted by a user
 
 Comment commands
@@ -177,6 +182,7 @@ Vote commands
   votepolicy              (public) Get the ticketvote api policy
   voteauthorize           (user)   Authorize a proposal vote
   votestart               (admin)  Start a proposal vote
+  votecancel              (admin)  Cancel an ongoing proposal vote
   castballot              (public) Cast a ballot of votes
   votedetails             (public) Get details for a vote
   voteresults             (public) Get full vote results
@@ -22,6 +22,7 @@ import (
 	piplugin "github.com/decred/politeia/politeiad/plugins/pi"
 	piv1 "github.com/decred/politeia/politeiawww/api/pi/v1"
 	rcv1 "github.com/decred/politeia/politeiawww/api/records/v1"
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
 	pclient "github.com/decred/politeia/politeiawww/client"
 	"github.com/decred/politeia/util"
 )
@@ -57,6 +58,12 @@ func printProposalFiles(files []rcv1.File) error {
 		if vm.LinkBy != 0 {
 			printf("  LinkBy: %v\n", timestampFromUnix(vm.LinkBy))
 		}
+		if vm.Budget != 0 {
+			printf("  Budget: %v cents\n", vm.Budget)
+		}
+		if vm.Amount != 0 {
+			printf("  Amount: %v cents\n", vm.Amount)
+		}
 	}
 
 	return nil
@@ -218,3 +225,22 @@ func signedMerkleRoot(files []rcv1.File, fid *identity.FullIdentity) (string, er
 	sig := fid.SignMessage([]byte(mr))
 	return hex.EncodeToString(sig[:]), nil
 }
+
+// recordVerify verifies a record using the server public keys of the provided
+// version reply. The censorship record is signed by the server when the
+// record is returned, so any server key that has been active since the record
+// was last updated is accepted.
+func recordVerify(r rcv1.Record, vr *www.VersionReply) error {
+	keys, err := pclient.ServerPubKeysSince(vr.PubKey, vr.PubKeyHistory,
+		r.Timestamp)
+	if err != nil {
+		return err
+	}
+	for _, v := range keys {
+		err = pclient.RecordVerify(r, v)
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
@@ -17,6 +17,13 @@ func printAuthDetails(a tkv1.AuthDetails) {
 	printf("Receipt  : %v\n", a.Receipt)
 }
 
+func printCancelDetails(c tkv1.CancelDetails) {
+	printf("Token    : %v\n", c.Token)
+	printf("Reason   : %v\n", c.Reason)
+	printf("Timestamp: %v\n", timestampFromUnix(c.Timestamp))
+	printf("Receipt  : %v\n", c.Receipt)
+}
+
 func printVoteDetails(v tkv1.VoteDetails) {
 	printf("Token             : %v\n", v.Params.Token)
 	printf("Type              : %v\n", tkv1.VoteTypes[v.Params.Type])
@@ -86,10 +93,31 @@ func printVoteSummary(token string, s tkv1.Summary) {
 	printf("Start Block Hash  : %v\n", s.StartBlockHash)
 	printf("Start Block Height: %v\n", s.StartBlockHeight)
 	printf("End Block Height  : %v\n", s.EndBlockHeight)
+	if s.RevealEndBlockHeight != 0 {
+		printf("Reveal End Height : %v\n", s.RevealEndBlockHeight)
+	}
 	printf("Eligible Tickets  : %v tickets\n", s.EligibleTickets)
 	printf("Best Block        : %v\n", s.BestBlock)
 	printf("Results\n")
 	for _, v := range s.Results {
 		printf(" %v %-3v %v votes\n", v.VoteBit, v.ID, v.Votes)
 	}
+	if s.RevealEndBlockHeight != 0 {
+		printf(" unrevealed %v votes\n", s.Unrevealed)
+	}
+	if s.Type == tkv1.VoteTypeMultipleChoice &&
+		s.Status == tkv1.VoteStatusFinished {
+		winner := s.WinningOption
+		if winner == "" {
+			winner = "none"
+		}
+		printf("Winning Option    : %v\n", winner)
+	}
+	if s.Budget != nil {
+		printf("Budget\n")
+		printf(" Total    : %v\n", s.Budget.Total)
+		printf(" Amount   : %v\n", s.Budget.Amount)
+		printf(" Remaining: %v\n", s.Budget.Remaining)
+		printf(" Outcome  : %v\n", tkv1.BudgetOutcomes[s.Budget.Outcome])
+	}
 }
@@ -12,14 +12,19 @@ import (
 	"strings"
 
 	cmv1 "github.com/decred/politeia/politeiawww/api/comments/v1"
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
 	"github.com/decred/politeia/politeiawww/client"
 )
 
 // commentsBundle represents the comments bundle that is available for download
 // in politeiagui.
+//
+// ServerPublicKeyHistory contains the server public key history. It is only
+// present if the server key has been rotated.
 type commentsBundle struct {
-	Comments        []cmv1.Comment `json:"comments"`
-	ServerPublicKey string         `json:"serverpublickey"`
+	Comments               []cmv1.Comment     `json:"comments"`
+	ServerPublicKey        string             `json:"serverpublickey"`
+	ServerPublicKeyHistory []www.ServerPubKey `json:"serverpublickeyhistory,omitempty"`
 }
 
 // verifyCommentsBundle takes the filepath of a comments bundle and verifies
@@ -49,7 +54,14 @@ func verifyCommentsBundle(fp string) error {
 		dels     int
 	)
 	for _, v := range cb.Comments {
-		err := client.CommentVerify(v, cb.ServerPublicKey)
+		// The receipt is signed by the server key that was active
+		// at the comment timestamp.
+		serverPubKey, err := client.ServerPubKeyAt(cb.ServerPublicKey,
+			cb.ServerPublicKeyHistory, v.Timestamp)
+		if err != nil {
+			return err
+		}
+		err = client.CommentVerify(v, serverPubKey)
 		if err != nil {
 			return err
 		}
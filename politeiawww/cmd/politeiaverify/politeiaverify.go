@@ -22,6 +22,8 @@ var (
 	publicKey = flag.String("k", "", "server public key")
 	token     = flag.String("t", "", "record censorship token")
 	signature = flag.String("s", "", "record censorship signature")
+	testnet   = flag.Bool("testnet", false, "verify timestamps bundles "+
+		"against testnet")
 )
 
 // loadFiles loads and returns a politeiawww records v1 File for each provided
@@ -112,6 +114,7 @@ var (
 	expJSONFile          = `.json$`
 	expRecord            = `^[0-9a-f]{16}-v[\d]{1,2}.json$`
 	expRecordTimestamps  = `^[0-9a-f]{16}-v[\d]{1,2}-timestamps.json$`
+	expTimestampsBundle  = `^[0-9a-f]{16}-v[\d]{1,2}-timestamps-bundle.json$`
 	expComments          = `^[0-9a-f]{16}-comments.json$`
 	expCommentTimestamps = `^[0-9a-f]{16}-comments-timestamps.json$`
 	expVotes             = `^[0-9a-f]{16}-votes.json$`
@@ -120,6 +123,7 @@ var (
 	regexpJSONFile          = regexp.MustCompile(expJSONFile)
 	regexpRecord            = regexp.MustCompile(expRecord)
 	regexpRecordTimestamps  = regexp.MustCompile(expRecordTimestamps)
+	regexpTimestampsBundle  = regexp.MustCompile(expTimestampsBundle)
 	regexpComments          = regexp.MustCompile(expComments)
 	regexpCommentTimestamps = regexp.MustCompile(expCommentTimestamps)
 	regexpVotes             = regexp.MustCompile(expVotes)
@@ -133,6 +137,7 @@ var (
 // Files that this function accepts:
 // Record bundle     : [token]-[version].json
 // Record timestamps : [token]-[version]-timestamps.json
+// Timestamps bundle : [token]-[version]-timestamps-bundle.json
 // Comments bundle   : [token]-comments.json
 // Comment timestamps: [token]-comments-timestamps.json
 // Votes bundle      : [token]-votes.json
@@ -147,6 +152,8 @@ func verifyFile(fp string) error {
 		return verifyRecordBundle(fp)
 	case regexpRecordTimestamps.FindString(filename) != "":
 		return verifyRecordTimestamps(fp)
+	case regexpTimestampsBundle.FindString(filename) != "":
+		return verifyTimestampsBundle(fp)
 	case regexpComments.FindString(filename) != "":
 		return verifyCommentsBundle(fp)
 	case regexpCommentTimestamps.FindString(filename) != "":
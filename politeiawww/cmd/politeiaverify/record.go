@@ -8,17 +8,25 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"time"
 
+	"github.com/decred/dcrd/chaincfg/v3"
 	"github.com/decred/politeia/politeiad/backend"
+	backendv2 "github.com/decred/politeia/politeiad/backendv2"
 	rcv1 "github.com/decred/politeia/politeiawww/api/records/v1"
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
 	"github.com/decred/politeia/politeiawww/client"
 )
 
 // recordBundle represents the record bundle that is available for download
 // in politeiagui.
+//
+// ServerPublicKeyHistory contains the server public key history. It is only
+// present if the server key has been rotated.
 type recordBundle struct {
-	Record          rcv1.Record `json:"record"`
-	ServerPublicKey string      `json:"serverpublickey"`
+	Record                 rcv1.Record        `json:"record"`
+	ServerPublicKey        string             `json:"serverpublickey"`
+	ServerPublicKeyHistory []www.ServerPubKey `json:"serverpublickeyhistory,omitempty"`
 }
 
 // verifyRecordBundle takes the file path of a record bundle file and verifies
@@ -43,7 +51,21 @@ func verifyRecordBundle(fp string) error {
 	fmt.Printf("  Merkle root: %v\n", rb.Record.CensorshipRecord.Merkle)
 	fmt.Printf("  Signature  : %v\n", rb.Record.CensorshipRecord.Signature)
 
-	err = client.RecordVerify(rb.Record, rb.ServerPublicKey)
+	// The censorship record is signed by the server when the record
+	// is retrieved. Any server key that has been active since the
+	// record was last updated may have signed it.
+	keys, err := client.ServerPubKeysSince(rb.ServerPublicKey,
+		rb.ServerPublicKeyHistory, rb.Record.Timestamp)
+	if err != nil {
+		return fmt.Errorf("could not verify server public key history: %v",
+			err)
+	}
+	for _, v := range keys {
+		err = client.RecordVerify(rb.Record, v)
+		if err == nil {
+			break
+		}
+	}
 	if err != nil {
 		return fmt.Errorf("could not verify record: %v", err)
 	}
@@ -149,3 +171,71 @@ func verifyRecordTimestamps(fp string) error {
 
 	return nil
 }
+
+// verifyTimestampsBundle takes the filepath of a record timestamps bundle and
+// verifies the validity of all timestamps and of the decred chain data of the
+// anchor transactions. This does not require network access.
+func verifyTimestampsBundle(fp string) error {
+	// Decode timestamps bundle
+	b, err := ioutil.ReadFile(fp)
+	if err != nil {
+		return err
+	}
+	var tb rcv1.TimestampsBundle
+	err = json.Unmarshal(b, &tb)
+	if err != nil {
+		return fmt.Errorf("could not unmarshal timestamps bundle: %v", err)
+	}
+	tr := tb.Timestamps
+	if tr.RecordMetadata.TxID == "" {
+		return fmt.Errorf("data not anchored yet")
+	}
+
+	// Pull the token out of the record metadata
+	var rm backend.RecordMetadata
+	err = json.Unmarshal([]byte(tr.RecordMetadata.Data), &rm)
+	if err != nil {
+		return fmt.Errorf("could not unmarshal record metadata: %v", err)
+	}
+
+	// Verify the timestamps and the chain data
+	params := chaincfg.MainNetParams()
+	if *testnet {
+		params = chaincfg.TestNet3Params()
+	}
+	anchors, err := client.TimestampsBundleVerify(tb, params)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Token          : %v\n", rm.Token)
+	for _, v := range tr.RecordMetadata.Proofs {
+		if v.Type != backendv2.ProofTypeTrillianRFC6962 {
+			continue
+		}
+		var ed backendv2.ExtraDataTrillianRFC6962
+		err = json.Unmarshal([]byte(v.ExtraData), &ed)
+		if err != nil {
+			return err
+		}
+		if ed.PublicKey != "" {
+			fmt.Printf("Tlog public key: %v\n", ed.PublicKey)
+		}
+	}
+	for _, v := range anchors {
+		fmt.Printf("Anchor\n")
+		fmt.Printf("  Merkle root  : %v\n", v.MerkleRoot)
+		fmt.Printf("  DCR tx       : %v\n", v.TxID)
+		fmt.Printf("  Block        : %v %v\n", v.Height, v.BlockHash)
+		fmt.Printf("  Block time   : %v\n",
+			time.Unix(v.Timestamp, 0).UTC().Format(time.RFC3339))
+		fmt.Printf("  Confirmations: %v\n", v.Confirmations)
+		fmt.Printf("  Tip block    : %v %v\n", v.Height+v.Confirmations,
+			v.TipHash)
+	}
+
+	fmt.Printf("Timestamps bundle verified!\n")
+	fmt.Printf("Verify that the tip blocks are part of the main chain.\n")
+
+	return nil
+}
@@ -12,16 +12,21 @@ import (
 	backend "github.com/decred/politeia/politeiad/backendv2"
 	tkplugin "github.com/decred/politeia/politeiad/plugins/ticketvote"
 	tkv1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
+	www "github.com/decred/politeia/politeiawww/api/www/v1"
 	"github.com/decred/politeia/politeiawww/client"
 )
 
 // votesBundle represents the bundle that is downloaded from politeiagui for
 // DCR ticket votes.
+//
+// ServerPublicKeyHistory contains the server public key history. It is only
+// present if the server key has been rotated.
 type votesBundle struct {
-	Auths           []tkv1.AuthDetails     `json:"auths,omitempty"`
-	Details         *tkv1.VoteDetails      `json:"details,omitempty"`
-	Votes           []tkv1.CastVoteDetails `json:"votes,omitempty"`
-	ServerPublicKey string                 `json:"serverpublickey"`
+	Auths                  []tkv1.AuthDetails     `json:"auths,omitempty"`
+	Details                *tkv1.VoteDetails      `json:"details,omitempty"`
+	Votes                  []tkv1.CastVoteDetails `json:"votes,omitempty"`
+	ServerPublicKey        string                 `json:"serverpublickey"`
+	ServerPublicKeyHistory []www.ServerPubKey     `json:"serverpublickeyhistory,omitempty"`
 }
 
 // verifyVotesBundle takes the filepath of a votes bundle and verifies the
@@ -54,7 +59,12 @@ func verifyVotesBundle(fp string) error {
 		fmt.Printf("  Public key: %v\n", v.PublicKey)
 		fmt.Printf("  Signature : %v\n", v.Signature)
 		fmt.Printf("  Receipt   : %v\n", v.Receipt)
-		err = client.AuthDetailsVerify(v, vb.ServerPublicKey)
+		serverPubKey, err := client.ServerPubKeyAt(vb.ServerPublicKey,
+			vb.ServerPublicKeyHistory, v.Timestamp)
+		if err != nil {
+			return err
+		}
+		err = client.AuthDetailsVerify(v, serverPubKey)
 		if err != nil {
 			return err
 		}
@@ -72,7 +82,21 @@ func verifyVotesBundle(fp string) error {
 	fmt.Printf("  Signature : %v\n", vb.Details.Signature)
 	fmt.Printf("  Receipt   : %v\n", vb.Details.Receipt)
 
-	err = client.VoteDetailsVerify(*vb.Details, vb.ServerPublicKey)
+	// The vote details do not contain a timestamp. The vote is started
+	// after the final authorization, so the receipt was signed by a key
+	// that has been active since the final authorization.
+	lastAuth := vb.Auths[len(vb.Auths)-1].Timestamp
+	keys, err := client.ServerPubKeysSince(vb.ServerPublicKey,
+		vb.ServerPublicKeyHistory, lastAuth)
+	if err != nil {
+		return err
+	}
+	for _, v := range keys {
+		err = client.VoteDetailsVerify(*vb.Details, v)
+		if err == nil {
+			break
+		}
+	}
 	if err != nil {
 		return err
 	}
@@ -97,7 +121,12 @@ func verifyVotesBundle(fp string) error {
 	fmt.Printf("Cast votes: %v/%v\n", len(vb.Votes), len(eligible))
 
 	for _, v := range vb.Votes {
-		err := client.CastVoteDetailsVerify(v, vb.ServerPublicKey)
+		serverPubKey, err := client.ServerPubKeyAt(vb.ServerPublicKey,
+			vb.ServerPublicKeyHistory, v.Timestamp)
+		if err != nil {
+			return err
+		}
+		err = client.CastVoteDetailsVerify(v, serverPubKey)
 		if err != nil {
 			return fmt.Errorf("could not verify vote %v: %v",
 				v.Ticket, err)
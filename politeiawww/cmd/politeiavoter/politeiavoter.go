@@ -41,6 +41,7 @@ import (
 	"github.com/decred/dcrd/wire"
 	"github.com/decred/politeia/decredplugin"
 	"github.com/decred/politeia/politeiad/api/v1/identity"
+	tkv1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
 	v1 "github.com/decred/politeia/politeiawww/api/www/v1"
 	"github.com/decred/politeia/util"
 	"github.com/gorilla/schema"
@@ -54,6 +55,8 @@ const (
 	failedJournal  = "failed.json"
 	successJournal = "success.json"
 	workJournal    = "work.json"
+	saltJournal    = "salt.json"
+	revealJournal  = "reveal.json"
 )
 
 func generateSeed() (int64, error) {
@@ -75,6 +78,8 @@ func usage() {
 	fmt.Fprintf(os.Stderr, "  vote      - Vote on a proposal\n")
 	fmt.Fprintf(os.Stderr, "  tally     - Tally votes on a proposal\n")
 	fmt.Fprintf(os.Stderr, "  verify    - Verify votes on a proposal\n")
+	fmt.Fprintf(os.Stderr, "  reveal    - Reveal secret ballot votes on a "+
+		"proposal\n")
 	//fmt.Fprintf(os.Stderr, "  startvote          - Instruct vote to start "+
 	//	"(admin only)\n")
 	fmt.Fprintf(os.Stderr, "\n")
@@ -180,6 +185,10 @@ type ctx struct {
 
 	run time.Time // when this run started
 
+	// secret is set when the vote of this run is a secret ballot vote.
+	// The votes are revealed once the voting period has ended.
+	secret bool
+
 	cfg *config // application config
 
 	// https
@@ -314,6 +323,13 @@ func convertTicketHashes(h []string) ([][]byte, error) {
 }
 
 func (c *ctx) makeRequest(method, route string, b interface{}) ([]byte, error) {
+	return c.makeAPIRequest(method, v1.PoliteiaWWWAPIRoute, route, b)
+}
+
+// makeAPIRequest sends a request to the provided politeiawww API. The user
+// error replies of the ticketvote API are formatted differently than those of
+// the legacy www API.
+func (c *ctx) makeAPIRequest(method, apiRoute, route string, b interface{}) ([]byte, error) {
 	var requestBody []byte
 	var queryParams string
 	if b != nil {
@@ -336,8 +352,7 @@ func (c *ctx) makeRequest(method, route string, b interface{}) ([]byte, error) {
 		}
 	}
 
-	fullRoute := c.cfg.PoliteiaWWW + v1.PoliteiaWWWAPIRoute + route +
-		queryParams
+	fullRoute := c.cfg.PoliteiaWWW + apiRoute + route + queryParams
 	log.Debugf("Request: %v %v", method, fullRoute)
 	if len(requestBody) != 0 {
 		log.Tracef("%v  ", string(requestBody))
@@ -364,12 +379,21 @@ func (c *ctx) makeRequest(method, route string, b interface{}) ([]byte, error) {
 	log.Tracef("Response: %v %v", r.StatusCode, string(responseBody))
 
 	if r.StatusCode != http.StatusOK {
-		var ue v1.UserError
-		err = json.Unmarshal(responseBody, &ue)
-		if err == nil && ue.ErrorCode != 0 {
-			return nil, fmt.Errorf("%v, %v %v", r.StatusCode,
-				v1.ErrorStatus[ue.ErrorCode],
-				strings.Join(ue.ErrorContext, ", "))
+		if apiRoute == tkv1.APIRoute {
+			var ue tkv1.UserErrorReply
+			err = json.Unmarshal(responseBody, &ue)
+			if err == nil && ue.ErrorCode != 0 {
+				return nil, fmt.Errorf("%v, %v %v", r.StatusCode,
+					tkv1.ErrorCodes[ue.ErrorCode], ue.ErrorContext)
+			}
+		} else {
+			var ue v1.UserError
+			err = json.Unmarshal(responseBody, &ue)
+			if err == nil && ue.ErrorCode != 0 {
+				return nil, fmt.Errorf("%v, %v %v", r.StatusCode,
+					v1.ErrorStatus[ue.ErrorCode],
+					strings.Join(ue.ErrorContext, ", "))
+			}
 		}
 
 		return nil, ErrRetry{
@@ -677,6 +701,10 @@ func (c *ctx) inventory() error {
 		fmt.Printf("  Proposal        : %v\n", v.Proposal.Name)
 		fmt.Printf("  Start block     : %v\n", v.StartVoteReply.StartBlockHeight)
 		fmt.Printf("  End block       : %v\n", v.StartVoteReply.EndHeight)
+		if v.StartVote.Vote.RevealDuration != 0 {
+			fmt.Printf("  Reveal end block: %v (secret ballot)\n",
+				v.StartVoteReply.RevealEndHeight)
+		}
 		fmt.Printf("  Mask            : %v\n", v.StartVote.Vote.Mask)
 		fmt.Printf("  Eligible tickets: %v\n", len(ctres.TicketAddresses))
 		fmt.Printf("  Eligible votes  : %v\n", len(eligible))
@@ -902,7 +930,13 @@ func verifyV1Vote(params *chaincfg.Params, address string, vote *v1.CastVote) bo
 		return false
 	}
 
-	msg := vote.Token + vote.Ticket + vote.VoteBit
+	// The vote bit of a secret ballot vote is not part of the signature.
+	// It is populated once the vote has been revealed.
+	voteBit := vote.VoteBit
+	if vote.Commitment != "" {
+		voteBit = ""
+	}
+	msg := vote.Token + vote.Ticket + voteBit + vote.Commitment
 
 	validated, err := verifyMessage(params, address, msg,
 		base64.StdEncoding.EncodeToString(sig))
@@ -1003,6 +1037,23 @@ func (c *ctx) _vote(token, voteId string) error {
 	}
 	ctres.TicketAddresses = eligible
 
+	// The vote bit of a secret ballot vote is replaced by a commitment
+	// to the vote bit. The salts are journaled prior to casting any of
+	// the commitments. The votes cannot be revealed without them.
+	var (
+		castBit     = voteBit
+		commitments []string
+	)
+	secret := vrr.StartVote.Vote.RevealDuration != 0
+	if secret {
+		c.secret = true
+		castBit = ""
+		commitments, err = c.secretVotes(token, voteBit, ctres)
+		if err != nil {
+			return err
+		}
+	}
+
 	passphrase, err := c.walletPassphrase()
 	if err != nil {
 		return err
@@ -1014,12 +1065,15 @@ func (c *ctx) _vote(token, voteId string) error {
 		Messages: make([]*pb.SignMessagesRequest_Message, 0,
 			len(ctres.TicketAddresses)),
 	}
-	for _, v := range ctres.TicketAddresses {
+	for k, v := range ctres.TicketAddresses {
 		h, err := chainhash.NewHash(v.Ticket)
 		if err != nil {
 			return err
 		}
-		msg := token + h.String() + voteBit
+		msg := token + h.String() + castBit
+		if secret {
+			msg += commitments[k]
+		}
 		sm.Messages = append(sm.Messages, &pb.SignMessagesRequest_Message{
 			Address: v.Address,
 			Message: msg,
@@ -1055,7 +1109,7 @@ func (c *ctx) _vote(token, voteId string) error {
 		}
 
 		// Generate work
-		err := c.calculateTrickle(token, voteBit, ctres, smr)
+		err := c.calculateTrickle(token, castBit, commitments, ctres, smr)
 		if err != nil {
 			return err
 		}
@@ -1076,12 +1130,16 @@ func (c *ctx) _vote(token, voteId string) error {
 			return err
 		}
 		signature := hex.EncodeToString(smr.Replies[k].Signature)
-		cv.Votes = append(cv.Votes, v1.CastVote{
+		vote := v1.CastVote{
 			Token:     token,
 			Ticket:    h.String(),
-			VoteBit:   voteBit,
+			VoteBit:   castBit,
 			Signature: signature,
-		})
+		}
+		if secret {
+			vote.Commitment = commitments[k]
+		}
+		cv.Votes = append(cv.Votes, vote)
 
 		// Prep results since we don't CastVoteReply doesn't return
 		// ticket address.
@@ -1157,6 +1215,20 @@ func (c *ctx) vote(args []string) error {
 			v.Ticket, v.Receipt.Error)
 	}
 
+	// Reveal the votes once the voting period has ended. The reveal
+	// command can be used to reveal the votes if this run is aborted.
+	if !c.secret {
+		return nil
+	}
+	fmt.Printf("Secret ballot vote; votes will be revealed once the " +
+		"voting period has ended\n")
+	receipts, err := c._reveal(args[0])
+	if err != nil {
+		return fmt.Errorf("reveal failed, retry using the reveal "+
+			"command: %v", err)
+	}
+	printRevealReceipts(receipts)
+
 	return nil
 }
 
@@ -1206,7 +1278,13 @@ func (c *ctx) tally(args []string) error {
 	// tally votes
 	count := make(map[uint64]uint)
 	var total uint
+	var unrevealed uint
 	for _, v := range t.CastVotes {
+		if v.VoteBit == "" && v.Commitment != "" {
+			// Secret ballot vote that has not been revealed
+			unrevealed++
+			continue
+		}
 		bits, err := strconv.ParseUint(v.VoteBit, 10, 64)
 		if err != nil {
 			return err
@@ -1215,9 +1293,31 @@ func (c *ctx) tally(args []string) error {
 		total++
 	}
 
-	if total == 0 {
+	if total == 0 && unrevealed == 0 {
 		return fmt.Errorf("no votes recorded")
 	}
+	if t.StartVote.Vote.RevealDuration != 0 {
+		fmt.Printf("Vote type: secret ballot\n")
+		fmt.Printf("Unrevealed votes: %v\n", unrevealed)
+	}
+
+	// Get the vote summary. Multiple choice votes report the winning
+	// option once the vote has finished.
+	vsr, err := c._summary(args[0])
+	if err != nil {
+		return err
+	}
+	vs := vsr.Summaries[args[0]]
+	if vs.Type == v1.VoteTypeMultipleChoice {
+		fmt.Printf("Vote type: multiple choice\n")
+		if vs.Status == v1.PropVoteStatusFinished {
+			winner := vs.WinningOption
+			if winner == "" {
+				winner = "none"
+			}
+			fmt.Printf("Winning option: %v\n", winner)
+		}
+	}
 
 	// Dump
 	for _, vo := range t.StartVote.Vote.Options {
@@ -1531,6 +1631,10 @@ func (c *ctx) verifyVote(vote string) error {
 					err)
 			}
 
+		case strings.HasPrefix(name, saltJournal),
+			strings.HasPrefix(name, revealJournal):
+			// Secret ballot journals, skip
+
 		case name == ".voteresults":
 			// Cache file, skip
 
@@ -1764,6 +1868,8 @@ func _main() error {
 		err = c.vote(args[1:])
 	case "verify":
 		err = c.verify(args[1:])
+	case "reveal":
+		err = c.reveal(args[1:])
 	default:
 		err = fmt.Errorf("invalid action: %v", action)
 	}
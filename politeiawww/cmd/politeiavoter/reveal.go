@@ -0,0 +1,336 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	pb "decred.org/dcrwallet/rpc/walletrpc"
+	"github.com/decred/dcrd/chaincfg/chainhash"
+	"github.com/decred/politeia/politeiad/api/v1/identity"
+	tkplugin "github.com/decred/politeia/politeiad/plugins/ticketvote"
+	tkv1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
+	v1 "github.com/decred/politeia/politeiawww/api/www/v1"
+	"github.com/decred/politeia/util"
+)
+
+// secretVote contains the data that is required to reveal a secret ballot
+// vote. The secret votes are journaled prior to the commitments being cast
+// since the votes cannot be revealed without the salts.
+type secretVote struct {
+	Ticket     string `json:"ticket"`     // Ticket hash
+	VoteBit    string `json:"votebit"`    // Vote bit, hex encoded
+	Salt       string `json:"salt"`       // Salt, hex encoded
+	Commitment string `json:"commitment"` // Commitment to the vote
+}
+
+// secretVotes generates a salt for each of the provided tickets and returns
+// the commitments to the vote bit. The commitments use the same index as the
+// tickets. The secret votes are journaled before the commitments are returned.
+func (c *ctx) secretVotes(token, voteBit string, ctres *pb.CommittedTicketsResponse) ([]string, error) {
+	votes := make([]secretVote, 0, len(ctres.TicketAddresses))
+	commitments := make([]string, 0, len(ctres.TicketAddresses))
+	for _, v := range ctres.TicketAddresses {
+		h, err := chainhash.NewHash(v.Ticket)
+		if err != nil {
+			return nil, err
+		}
+		b, err := util.Random(tkv1.SaltSize)
+		if err != nil {
+			return nil, err
+		}
+		var (
+			ticket     = h.String()
+			salt       = hex.EncodeToString(b)
+			commitment = tkplugin.VoteCommitment(token, ticket, voteBit, salt)
+		)
+		votes = append(votes, secretVote{
+			Ticket:     ticket,
+			VoteBit:    voteBit,
+			Salt:       salt,
+			Commitment: commitment,
+		})
+		commitments = append(commitments, commitment)
+	}
+
+	err := c.jsonLog(saltJournal, token, votes)
+	if err != nil {
+		return nil, fmt.Errorf("journal salts: %v", err)
+	}
+
+	return commitments, nil
+}
+
+type saltTuple struct {
+	Time  JSONTime
+	Votes []secretVote
+}
+
+func decodeSalts(filename string, salts map[string][]secretVote) error {
+	f, err := os.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	d := json.NewDecoder(f)
+
+	var st *saltTuple
+	state := 0
+	for {
+		switch state {
+		case 0:
+			st = &saltTuple{}
+			err = d.Decode(&st.Time)
+			if err != nil {
+				// Only expect EOF in state 0
+				if err == io.EOF {
+					goto exit
+				}
+				return fmt.Errorf("decode time (%v): %v",
+					d.InputOffset(), err)
+			}
+			state = 1
+
+		case 1:
+			err = d.Decode(&st.Votes)
+			if err != nil {
+				return fmt.Errorf("decode salts (%v): %v",
+					d.InputOffset(), err)
+			}
+
+			// Add to map. A ticket is journaled once per run.
+			for _, v := range st.Votes {
+				salts[v.Ticket] = append(salts[v.Ticket], v)
+			}
+
+			// Reset statemachine
+			st = &saltTuple{}
+			state = 0
+		}
+	}
+
+exit:
+	return nil
+}
+
+// loadSecretVotes returns the journaled secret votes of the provided record.
+// A ticket may have been journaled in multiple runs. Only one of its
+// commitments will have been recorded by the server.
+func (c *ctx) loadSecretVotes(token string) (map[string][]secretVote, error) {
+	dir := filepath.Join(c.cfg.voteDir, token)
+	fa, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	salts := make(map[string][]secretVote, 128) // [ticket]secretVotes
+	for k := range fa {
+		name := fa[k].Name()
+		if !strings.HasPrefix(name, saltJournal) {
+			continue
+		}
+		filename := filepath.Join(dir, name)
+		err = decodeSalts(filename, salts)
+		if err != nil {
+			return nil, fmt.Errorf("decodeSalts %v: %v", filename, err)
+		}
+	}
+	return salts, nil
+}
+
+// revealWait waits until the reveal window of the provided vote has opened.
+// The reveal window opens at the vote end height. The best block of the
+// server is used since the server decides whether the window has opened. The
+// best block is returned. An error is returned if the vote is cancelled while
+// waiting, since a cancelled vote does not have a reveal window.
+func (c *ctx) revealWait(token string, endHeight uint64) (uint64, error) {
+	for {
+		vsr, err := c._summary(token)
+		var e ErrRetry
+		switch {
+		case errors.As(err, &e):
+			// Try again at the next block
+			log.Debugf("revealWait: %v", e)
+		case err != nil:
+			return 0, err
+		case vsr.BestBlock >= endHeight:
+			return vsr.BestBlock, nil
+		case vsr.Summaries[token].Status == v1.PropVoteStatusFinished:
+			// The vote can only finish prior to the end height
+			// if it has been cancelled.
+			return 0, fmt.Errorf("vote has been cancelled")
+		default:
+			fmt.Printf("Awaiting reveal window: block %v/%v\n",
+				vsr.BestBlock, endHeight)
+		}
+
+		select {
+		case <-c.wctx.Done():
+			return 0, c.wctx.Err()
+		case <-time.After(activeNetParams.TargetTimePerBlock):
+		}
+	}
+}
+
+func (c *ctx) sendReveal(r *tkv1.Reveal) (*tkv1.RevealReply, error) {
+	responseBody, err := c.makeAPIRequest(http.MethodPost, tkv1.APIRoute,
+		tkv1.RouteReveal, r)
+	if err != nil {
+		return nil, err
+	}
+
+	var rr tkv1.RevealReply
+	err = json.Unmarshal(responseBody, &rr)
+	if err != nil {
+		return nil, fmt.Errorf("Could not unmarshal RevealReply: %v", err)
+	}
+	if len(rr.Receipts) != len(r.Votes) {
+		return nil, fmt.Errorf("unexpected receipt count got %v wanted %v",
+			len(rr.Receipts), len(r.Votes))
+	}
+
+	return &rr, nil
+}
+
+// _reveal reveals the secret ballot votes that were cast by this politeiavoter
+// instance on the provided record. It waits for the reveal window to open if
+// it has not opened yet. Only the commitments that were recorded by the server
+// and that have not been revealed yet are revealed.
+func (c *ctx) _reveal(token string) ([]tkv1.RevealVoteReply, error) {
+	vrr, err := c._tally(token)
+	if err != nil {
+		return nil, err
+	}
+	if vrr.StartVote.Vote.RevealDuration == 0 {
+		return nil, fmt.Errorf("not a secret ballot vote: %v", token)
+	}
+	endHeight, err := strconv.ParseUint(vrr.StartVoteReply.EndHeight, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	revealEndHeight, err := strconv.ParseUint(vrr.StartVoteReply.RevealEndHeight,
+		10, 32)
+	if err != nil {
+		return nil, err
+	}
+	salts, err := c.loadSecretVotes(token)
+	if err != nil {
+		return nil, err
+	}
+	if len(salts) == 0 {
+		return nil, fmt.Errorf("no secret votes found")
+	}
+
+	bestBlock, err := c.revealWait(token, endHeight)
+	if err != nil {
+		return nil, err
+	}
+	if bestBlock >= revealEndHeight {
+		return nil, fmt.Errorf("reveal window has ended: current %v >= "+
+			"end %v", bestBlock, revealEndHeight)
+	}
+
+	// Get the commitments that were recorded by the server. The cast
+	// votes must be retrieved again since commitments may have been
+	// cast while waiting for the reveal window.
+	vrr, err = c._tally(token)
+	if err != nil {
+		return nil, err
+	}
+	r := tkv1.Reveal{
+		Votes: make([]tkv1.RevealVote, 0, len(salts)),
+	}
+	for _, v := range vrr.CastVotes {
+		if v.Commitment == "" || v.VoteBit != "" {
+			// Not a commitment or already revealed
+			continue
+		}
+		for _, sv := range salts[v.Ticket] {
+			if sv.Commitment != v.Commitment {
+				continue
+			}
+			r.Votes = append(r.Votes, tkv1.RevealVote{
+				Token:   token,
+				Ticket:  sv.Ticket,
+				VoteBit: sv.VoteBit,
+				Salt:    sv.Salt,
+			})
+			break
+		}
+	}
+	if len(r.Votes) == 0 {
+		return nil, fmt.Errorf("no unrevealed votes found")
+	}
+
+	fmt.Printf("Revealing votes: %v\n", len(r.Votes))
+	rr, err := c.sendReveal(&r)
+	if err != nil {
+		return nil, err
+	}
+	err = c.jsonLog(revealJournal, token, rr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify receipts
+	for k, v := range rr.Receipts {
+		if v.ErrorCode != tkv1.VoteErrorInvalid {
+			continue
+		}
+		rv := r.Votes[k]
+		sig, err := identity.SignatureFromString(v.Receipt)
+		if err != nil {
+			rr.Receipts[k].ErrorContext = err.Error()
+			continue
+		}
+		msg := rv.Ticket + rv.VoteBit + rv.Salt
+		if !c.id.VerifyMessage([]byte(msg), *sig) {
+			rr.Receipts[k].ErrorContext = "could not verify receipt " +
+				v.Receipt
+		}
+	}
+
+	return rr.Receipts, nil
+}
+
+// printRevealReceipts prints a summary of the provided reveal receipts.
+func printRevealReceipts(receipts []tkv1.RevealVoteReply) {
+	failed := make([]tkv1.RevealVoteReply, 0, len(receipts))
+	for _, v := range receipts {
+		if v.ErrorCode != tkv1.VoteErrorInvalid || v.ErrorContext != "" {
+			failed = append(failed, v)
+		}
+	}
+	fmt.Printf("Reveals succeeded: %v\n", len(receipts)-len(failed))
+	fmt.Printf("Reveals failed   : %v\n", len(failed))
+	for _, v := range failed {
+		fmt.Printf("Failed reveal    : %v %v %v\n", v.Ticket,
+			v.ErrorCode, v.ErrorContext)
+	}
+}
+
+func (c *ctx) reveal(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("reveal: not enough arguments %v", args)
+	}
+
+	receipts, err := c._reveal(args[0])
+	if err != nil {
+		return err
+	}
+	printRevealReceipts(receipts)
+
+	return nil
+}
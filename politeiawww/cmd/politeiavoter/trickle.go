@@ -14,7 +14,11 @@ import (
 	"github.com/decred/politeia/politeiawww/cmd/politeiavoter/uniformprng"
 )
 
-func (c *ctx) calculateTrickle(token, voteBit string, ctres *pb.CommittedTicketsResponse, smr *pb.SignMessagesResponse) error {
+// calculateTrickle calculates the vote intervals of the provided tickets and
+// queues the votes. The commitments are only provided for a secret ballot
+// vote, in which case the vote bit is empty. The commitments use the same
+// index as the tickets.
+func (c *ctx) calculateTrickle(token, voteBit string, commitments []string, ctres *pb.CommittedTicketsResponse, smr *pb.SignMessagesResponse) error {
 	votes := len(ctres.TicketAddresses)
 	duration := c.cfg.voteDuration
 	voteDuration := duration - time.Hour
@@ -55,6 +59,9 @@ func (c *ctx) calculateTrickle(token, voteBit string, ctres *pb.CommittedTickets
 			},
 			At: ts[k] - previous, // Delta to previous timestamp
 		}
+		if commitments != nil {
+			buckets[k].Vote.Commitment = commitments[k]
+		}
 		t += ts[k] - previous
 		previous = ts[k]
 	}
@@ -61,7 +61,7 @@ func TestTrickleNotEnoughTime(t *testing.T) {
 	defer cleanup()
 
 	ctres, smr := fakeTickets(x)
-	err := c.calculateTrickle("", "", ctres, smr)
+	err := c.calculateTrickle("", "", nil, ctres, smr)
 	if err == nil {
 		t.Fatal("expected error")
 	}
@@ -73,7 +73,7 @@ func TestTrickle2(t *testing.T) {
 	defer cleanup()
 
 	ctres, smr := fakeTickets(x)
-	err := c.calculateTrickle("", "", ctres, smr)
+	err := c.calculateTrickle("", "", nil, ctres, smr)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"sync"
 
 	pdv2 "github.com/decred/politeia/politeiad/api/v2"
 	pdclient "github.com/decred/politeia/politeiad/client"
@@ -28,14 +29,22 @@ type Comments struct {
 	userdb    user.Database
 	sessions  *sessions.Sessions
 	events    *events.Manager
-	policy    *v1.PolicyReply
+
+	// mtx protects the policy, which is updated when the politeiad
+	// plugin settings are updated at runtime.
+	mtx    sync.RWMutex
+	policy *v1.PolicyReply
 }
 
 // HandlePolicy is the request handler for the comments v1 Policy route.
 func (c *Comments) HandlePolicy(w http.ResponseWriter, r *http.Request) {
 	log.Tracef("HandlePolicy")
 
-	util.RespondWithJSON(w, http.StatusOK, c.policy)
+	c.mtx.RLock()
+	policy := c.policy
+	c.mtx.RUnlock()
+
+	util.RespondWithJSON(w, http.StatusOK, policy)
 }
 
 // HandleNew is the request handler for the comments v1 New route.
@@ -249,6 +258,41 @@ func (c *Comments) HandleTimestamps(w http.ResponseWriter, r *http.Request) {
 
 // New returns a new Comments context.
 func New(cfg *config.Config, pdc *pdclient.Client, udb user.Database, s *sessions.Sessions, e *events.Manager, plugins []pdv2.Plugin) (*Comments, error) {
+	policy, err := policyNew(plugins)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Comments{
+		cfg:       cfg,
+		politeiad: pdc,
+		userdb:    udb,
+		sessions:  s,
+		events:    e,
+		policy:    policy,
+	}, nil
+}
+
+// PolicyUpdate updates the comments policy using the provided politeiad
+// plugin settings. The plugin settings can be updated in politeiad at
+// runtime.
+func (c *Comments) PolicyUpdate(plugins []pdv2.Plugin) error {
+	policy, err := policyNew(plugins)
+	if err != nil {
+		return err
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.policy = policy
+
+	return nil
+}
+
+// policyNew returns the comments policy for the provided politeiad plugin
+// settings.
+func policyNew(plugins []pdv2.Plugin) (*v1.PolicyReply, error) {
 	// Parse plugin settings
 	var (
 		lengthMax      uint32
@@ -290,15 +334,8 @@ func New(cfg *config.Config, pdc *pdclient.Client, udb user.Database, s *session
 			comments.SettingKeyVoteChangesMax)
 	}
 
-	return &Comments{
-		cfg:       cfg,
-		politeiad: pdc,
-		userdb:    udb,
-		sessions:  s,
-		events:    e,
-		policy: &v1.PolicyReply{
-			LengthMax:      lengthMax,
-			VoteChangesMax: voteChangesMax,
-		},
+	return &v1.PolicyReply{
+		LengthMax:      lengthMax,
+		VoteChangesMax: voteChangesMax,
 	}, nil
 }
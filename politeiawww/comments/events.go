@@ -5,7 +5,13 @@
 package comments
 
 import (
+	"context"
+	"encoding/json"
+
+	pdv2 "github.com/decred/politeia/politeiad/api/v2"
+	"github.com/decred/politeia/politeiad/plugins/comments"
 	v1 "github.com/decred/politeia/politeiawww/api/comments/v1"
+	"github.com/google/uuid"
 )
 
 const (
@@ -18,3 +24,45 @@ type EventNew struct {
 	State   v1.RecordStateT
 	Comment v1.Comment
 }
+
+// HandlePoliteiadEvent emits the comments events that correspond to a
+// politeiad event. The comments events are emitted once politeiad has
+// published the comments plugin write, regardless of which politeiawww
+// instance made the write.
+func (c *Comments) HandlePoliteiadEvent(ctx context.Context, e pdv2.Event) {
+	if e.Type != pdv2.EventTypePluginWrite ||
+		e.PluginID != comments.PluginID {
+		return
+	}
+
+	switch e.Name {
+	case comments.CmdNew:
+		var nr comments.NewReply
+		err := json.Unmarshal([]byte(e.Reply), &nr)
+		if err != nil {
+			log.Errorf("HandlePoliteiadEvent %v: unmarshal: %v", e.Token, err)
+			return
+		}
+
+		// Fill in user data
+		cm := convertComment(nr.Comment)
+		uid, err := uuid.Parse(cm.UserID)
+		if err != nil {
+			log.Errorf("HandlePoliteiadEvent %v: user id: %v", e.Token, err)
+			return
+		}
+		u, err := c.userdb.UserGetById(uid)
+		if err != nil {
+			log.Errorf("HandlePoliteiadEvent %v: UserGetById: %v",
+				e.Token, err)
+			return
+		}
+		commentPopulateUserData(&cm, *u)
+
+		c.events.Emit(EventTypeNew,
+			EventNew{
+				State:   cm.State,
+				Comment: cm,
+			})
+	}
+}
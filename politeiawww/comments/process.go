@@ -81,13 +81,6 @@ func (c *Comments) processNew(ctx context.Context, n v1.New, u user.User) (*v1.N
 	cm := convertComment(*pdc)
 	commentPopulateUserData(&cm, u)
 
-	// Emit event
-	c.events.Emit(EventTypeNew,
-		EventNew{
-			State:   n.State,
-			Comment: cm,
-		})
-
 	return &v1.NewReply{
 		Comment: cm,
 	}, nil
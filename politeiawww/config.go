@@ -491,6 +491,8 @@ func loadConfig() (*config.Config, []string, error) {
 	cfg.HTTPSKey = util.CleanAndExpandPath(cfg.HTTPSKey)
 	cfg.HTTPSCert = util.CleanAndExpandPath(cfg.HTTPSCert)
 	cfg.RPCCert = util.CleanAndExpandPath(cfg.RPCCert)
+	cfg.RPCClientCert = util.CleanAndExpandPath(cfg.RPCClientCert)
+	cfg.RPCClientKey = util.CleanAndExpandPath(cfg.RPCClientKey)
 
 	if cfg.CodeStatStart > 0 &&
 		(time.Unix(cfg.CodeStatStart, 0).Before(codeStatCheck) ||
@@ -585,6 +587,10 @@ func loadConfig() (*config.Config, []string, error) {
 		return nil, nil, fmt.Errorf("politeiad rpc pass must be provided " +
 			"with --rpcpass")
 	}
+	if (cfg.RPCClientCert == "") != (cfg.RPCClientKey == "") {
+		return nil, nil, fmt.Errorf("politeiad rpc client cert and key " +
+			"must be provided together")
+	}
 
 	// Verify mail settings
 	switch {
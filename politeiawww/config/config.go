@@ -64,6 +64,8 @@ type Config struct {
 	RPCIdentityFile string   `long:"rpcidentityfile" description:"Path to file containing the politeiad identity"`
 	RPCUser         string   `long:"rpcuser" description:"RPC user name for privileged politeaid commands"`
 	RPCPass         string   `long:"rpcpass" description:"RPC password for privileged politeiad commands"`
+	RPCClientCert   string   `long:"rpcclientcert" description:"File containing the client certificate that is presented to politeiad"`
+	RPCClientKey    string   `long:"rpcclientkey" description:"File containing the client certificate key"`
 	FetchIdentity   bool     `long:"fetchidentity" description:"Whether or not politeiawww fetches the identity from politeiad."`
 	Interactive     string   `long:"interactive" description:"Set to i-know-this-is-a-bad-idea to turn off interactive mode during --fetchidentity."`
 	AdminLogFile    string   `long:"adminlogfile" description:"admin log filename (Default: admin.log)"`
@@ -0,0 +1,105 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	pdv2 "github.com/decred/politeia/politeiad/api/v2"
+	pdclient "github.com/decred/politeia/politeiad/client"
+)
+
+const (
+	// politeiadEventsRetryInterval is the amount of time that is waited
+	// before the politeiad event stream is reopened after it has been
+	// closed or after it could not be opened.
+	politeiadEventsRetryInterval = 10 * time.Second
+)
+
+// politeiadEventHandler handles a politeiad event.
+type politeiadEventHandler func(ctx context.Context, e pdv2.Event)
+
+// politeiadEvents consumes the politeiad event stream and passes each event to
+// the provided handlers. The event stream is reopened using the ID of the last
+// event that was received whenever it is closed so that no events are missed.
+// This function should be run in a go routine. It does not return.
+//
+// Every politeiawww instance consumes the politeiad event stream. The local
+// handlers update the state of this instance, so every event is passed to
+// them. The emit handlers emit the politeiawww events that correspond to the
+// politeiad events. The politeiawww events, e.g. the notification emails,
+// must only be emitted once, so an event is only passed to the emit handlers
+// if this instance is the first to claim the event ID in the user database.
+func (p *politeiawww) politeiadEvents(local, emit []politeiadEventHandler) {
+	var (
+		ctx    = context.Background()
+		resume string
+	)
+	for {
+		s, err := p.politeiad.Events(ctx, resume)
+		if err != nil {
+			var pde pdclient.RespError
+			if errors.As(err, &pde) {
+				switch pdv2.ErrorCodeT(pde.ErrorReply.ErrorCode) {
+				case pdv2.ErrorCodeEventResumeExpired:
+					// The events that were published since the last
+					// event are no longer available. This happens when
+					// politeiad is restarted. Start over with new
+					// events.
+					log.Warnf("politeiad event stream resume token %v "+
+						"expired; events may have been missed", resume)
+					resume = ""
+					continue
+				case pdv2.ErrorCodeEventResumeInvalid:
+					log.Errorf("politeiad event stream resume token %v "+
+						"invalid; starting over with new events", resume)
+					resume = ""
+					continue
+				}
+			}
+			log.Errorf("politeiadEvents: Events: %v", err)
+			time.Sleep(politeiadEventsRetryInterval)
+			continue
+		}
+
+		log.Infof("politeiad event stream opened")
+
+		for {
+			e, err := s.Next()
+			if err != nil {
+				log.Infof("politeiad event stream closed: %v", err)
+				break
+			}
+
+			log.Debugf("politeiad event %v: %v %v", e.ID,
+				pdv2.EventTypes[e.Type], e.Token)
+
+			resume = e.ID
+			for _, h := range local {
+				h(ctx, *e)
+			}
+
+			claimed, err := p.db.EventClaim(e.ID)
+			if err != nil {
+				log.Errorf("politeiadEvents: EventClaim %v: %v", e.ID, err)
+				continue
+			}
+			if !claimed {
+				log.Debugf("politeiad event %v claimed by a different "+
+					"politeiawww instance", e.ID)
+				continue
+			}
+
+			for _, h := range emit {
+				h(ctx, *e)
+			}
+		}
+		s.Close()
+
+		time.Sleep(politeiadEventsRetryInterval)
+	}
+}
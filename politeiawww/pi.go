@@ -5,9 +5,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"net/http"
+	"time"
 
+	pdv2 "github.com/decred/politeia/politeiad/api/v2"
 	cmplugin "github.com/decred/politeia/politeiad/plugins/comments"
 	piplugin "github.com/decred/politeia/politeiad/plugins/pi"
 	tkplugin "github.com/decred/politeia/politeiad/plugins/ticketvote"
@@ -24,6 +27,15 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	// policyRefreshInterval is the interval at which the politeiad
+	// plugin settings are fetched when no plugin settings update event
+	// has been received. The API policies are refreshed when the event
+	// is received. Polling is a fallback for the events that are
+	// missed, e.g. while politeiad is being restarted.
+	policyRefreshInterval = 10 * time.Minute
+)
+
 // setupPiRoutes sets up the API routes for piwww mode.
 func (p *politeiawww) setupPiRoutes(r *records.Records, c *comments.Comments, t *ticketvote.TicketVote, pic *pi.Pi) {
 	// Return a 404 when a route is not found
@@ -88,6 +100,9 @@ func (p *politeiawww) setupPiRoutes(r *records.Records, c *comments.Comments, t
 	p.addRoute(http.MethodPost, rcv1.APIRoute,
 		rcv1.RouteTimestamps, r.HandleTimestamps,
 		permissionPublic)
+	p.addRoute(http.MethodPost, rcv1.APIRoute,
+		rcv1.RouteDiff, r.HandleDiff,
+		permissionPublic)
 	p.addRoute(http.MethodPost, rcv1.APIRoute,
 		rcv1.RouteRecords, r.HandleRecords,
 		permissionPublic)
@@ -97,6 +112,9 @@ func (p *politeiawww) setupPiRoutes(r *records.Records, c *comments.Comments, t
 	p.addRoute(http.MethodPost, rcv1.APIRoute,
 		rcv1.RouteInventoryOrdered, r.HandleInventoryOrdered,
 		permissionPublic)
+	p.addRoute(http.MethodPost, rcv1.APIRoute,
+		rcv1.RouteSearch, r.HandleSearch,
+		permissionPublic)
 	p.addRoute(http.MethodPost, rcv1.APIRoute,
 		rcv1.RouteUserRecords, r.HandleUserRecords,
 		permissionPublic)
@@ -140,6 +158,12 @@ func (p *politeiawww) setupPiRoutes(r *records.Records, c *comments.Comments, t
 	p.addRoute(http.MethodPost, tkv1.APIRoute,
 		tkv1.RouteCastBallot, t.HandleCastBallot,
 		permissionPublic)
+	p.addRoute(http.MethodPost, tkv1.APIRoute,
+		tkv1.RouteReveal, t.HandleReveal,
+		permissionPublic)
+	p.addRoute(http.MethodPost, tkv1.APIRoute,
+		tkv1.RouteCancel, t.HandleCancel,
+		permissionAdmin)
 	p.addRoute(http.MethodPost, tkv1.APIRoute,
 		tkv1.RouteDetails, t.HandleDetails,
 		permissionPublic)
@@ -204,7 +228,7 @@ func (p *politeiawww) setupPi() error {
 	if err != nil {
 		return fmt.Errorf("new comments api: %v", err)
 	}
-	voteCtx, err := ticketvote.New(p.cfg, p.politeiad,
+	voteCtx, err := ticketvote.New(p.cfg, p.politeiad, p.db,
 		p.sessions, p.events, plugins)
 	if err != nil {
 		return fmt.Errorf("new ticketvote api: %v", err)
@@ -219,6 +243,23 @@ func (p *politeiawww) setupPi() error {
 	p.setUserWWWRoutes()
 	p.setupPiRoutes(recordsCtx, commentsCtx, voteCtx, piCtx)
 
+	// Keep the API policies up to date with the politeiad plugin
+	// settings.
+	refresh := make(chan struct{}, 1)
+	go p.policyRefresh(refresh, commentsCtx, voteCtx, piCtx)
+
+	// Refresh the API policies and emit the records, comments, and
+	// ticketvote events from the politeiad event stream.
+	go p.politeiadEvents(
+		[]politeiadEventHandler{
+			policyRefreshHandler(refresh),
+		},
+		[]politeiadEventHandler{
+			recordsCtx.HandlePoliteiadEvent,
+			commentsCtx.HandlePoliteiadEvent,
+			voteCtx.HandlePoliteiadEvent,
+		})
+
 	// Verify paywall settings
 	switch {
 	case p.cfg.PaywallAmount != 0 && p.cfg.PaywallXpub != "":
@@ -245,3 +286,52 @@ func (p *politeiawww) setupPi() error {
 
 	return nil
 }
+
+// policyRefreshHandler returns a politeiad event handler that requests a
+// refresh of the API policies when the settings of a politeiad plugin have
+// been updated.
+func policyRefreshHandler(refresh chan<- struct{}) politeiadEventHandler {
+	return func(ctx context.Context, e pdv2.Event) {
+		if e.Type != pdv2.EventTypePluginSettingsUpdate {
+			return
+		}
+		select {
+		case refresh <- struct{}{}:
+		default:
+			// A refresh has already been requested
+		}
+	}
+}
+
+// policyRefresh fetches the politeiad plugin inventory and updates the API
+// policies that are derived from the plugin settings. The plugin settings can
+// be updated in politeiad at runtime. The policies are refreshed whenever a
+// refresh is requested on the provided channel and every
+// policyRefreshInterval. This function should be run in a go routine. It does
+// not return.
+func (p *politeiawww) policyRefresh(refresh <-chan struct{}, c *comments.Comments, t *ticketvote.TicketVote, pic *pi.Pi) {
+	for {
+		select {
+		case <-refresh:
+		case <-time.After(policyRefreshInterval):
+		}
+
+		plugins, err := p.politeiad.PluginInventory(context.Background())
+		if err != nil {
+			log.Errorf("policyRefresh: PluginInventory: %v", err)
+			continue
+		}
+		err = c.PolicyUpdate(plugins)
+		if err != nil {
+			log.Errorf("policyRefresh: comments: %v", err)
+		}
+		err = t.PolicyUpdate(plugins)
+		if err != nil {
+			log.Errorf("policyRefresh: ticketvote: %v", err)
+		}
+		err = pic.PolicyUpdate(plugins)
+		if err != nil {
+			log.Errorf("policyRefresh: pi: %v", err)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"sync"
 
 	pdv2 "github.com/decred/politeia/politeiad/api/v2"
 	pdclient "github.com/decred/politeia/politeiad/client"
@@ -30,18 +31,68 @@ type Pi struct {
 	sessions  *sessions.Sessions
 	events    *events.Manager
 	mail      *mail.Client
-	policy    *v1.PolicyReply
+
+	// mtx protects the policy, which is updated when the politeiad
+	// plugin settings are updated at runtime.
+	mtx    sync.RWMutex
+	policy *v1.PolicyReply
 }
 
 // HandlePolicy is the request handler for the pi v1 Policy route.
 func (p *Pi) HandlePolicy(w http.ResponseWriter, r *http.Request) {
 	log.Tracef("HandlePolicy")
 
-	util.RespondWithJSON(w, http.StatusOK, p.policy)
+	p.mtx.RLock()
+	policy := p.policy
+	p.mtx.RUnlock()
+
+	util.RespondWithJSON(w, http.StatusOK, policy)
 }
 
 // New returns a new Pi context.
 func New(cfg *config.Config, pdc *pdclient.Client, udb user.Database, s *sessions.Sessions, e *events.Manager, m *mail.Client, plugins []pdv2.Plugin) (*Pi, error) {
+	policy, err := policyNew(plugins)
+	if err != nil {
+		return nil, err
+	}
+
+	// Setup pi context
+	p := Pi{
+		cfg:       cfg,
+		politeiad: pdc,
+		userdb:    udb,
+		sessions:  s,
+		events:    e,
+		mail:      m,
+		policy:    policy,
+	}
+
+	// Setup event listeners
+	p.setupEventListeners()
+
+	return &p, nil
+}
+
+// PolicyUpdate updates the pi policy using the provided politeiad
+// plugin settings. The plugin settings can be updated in politeiad at
+// runtime.
+func (p *Pi) PolicyUpdate(plugins []pdv2.Plugin) error {
+	policy, err := policyNew(plugins)
+	if err != nil {
+		return err
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	p.policy = policy
+
+	return nil
+}
+
+// policyNew returns the pi policy for the provided politeiad plugin
+// settings.
+func policyNew(plugins []pdv2.Plugin) (*v1.PolicyReply, error) {
 	// Parse plugin settings
 	var (
 		textFileSizeMax    uint32
@@ -121,26 +172,12 @@ func New(cfg *config.Config, pdc *pdclient.Client, udb user.Database, s *session
 			pi.SettingKeyProposalNameLengthMax)
 	}
 
-	// Setup pi context
-	p := Pi{
-		cfg:       cfg,
-		politeiad: pdc,
-		userdb:    udb,
-		sessions:  s,
-		events:    e,
-		mail:      m,
-		policy: &v1.PolicyReply{
-			TextFileSizeMax:    textFileSizeMax,
-			ImageFileCountMax:  imageFileCountMax,
-			ImageFileSizeMax:   imageFileSizeMax,
-			NameLengthMin:      nameLengthMin,
-			NameLengthMax:      nameLengthMax,
-			NameSupportedChars: nameSupportedChars,
-		},
-	}
-
-	// Setup event listeners
-	p.setupEventListeners()
-
-	return &p, nil
+	return &v1.PolicyReply{
+		TextFileSizeMax:    textFileSizeMax,
+		ImageFileCountMax:  imageFileCountMax,
+		ImageFileSizeMax:   imageFileSizeMax,
+		NameLengthMin:      nameLengthMin,
+		NameLengthMax:      nameLengthMax,
+		NameSupportedChars: nameSupportedChars,
+	}, nil
 }
@@ -0,0 +1,39 @@
+// Copyright (c) 2021 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"testing"
+
+	pdv2 "github.com/decred/politeia/politeiad/api/v2"
+)
+
+func TestPolicyRefreshHandler(t *testing.T) {
+	var (
+		ctx     = context.Background()
+		refresh = make(chan struct{}, 1)
+		h       = policyRefreshHandler(refresh)
+	)
+
+	// Events other than plugin settings updates do not request a
+	// refresh.
+	h(ctx, pdv2.Event{Type: pdv2.EventTypePluginWrite})
+	if len(refresh) != 0 {
+		t.Fatalf("got %v refresh requests, want 0", len(refresh))
+	}
+
+	// A plugin settings update requests a refresh. Additional updates
+	// do not block while a refresh is pending.
+	for i := 0; i < 2; i++ {
+		h(ctx, pdv2.Event{
+			Type:     pdv2.EventTypePluginSettingsUpdate,
+			PluginID: "comments",
+		})
+	}
+	if len(refresh) != 1 {
+		t.Fatalf("got %v refresh requests, want 1", len(refresh))
+	}
+}
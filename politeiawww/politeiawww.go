@@ -77,6 +77,10 @@ type politeiawww struct {
 	sessions  *sessions.Sessions
 	events    *events.Manager
 
+	// pdIdentityHistory contains the politeiad identity key history.
+	// It is empty if the politeiad identity has never been rotated.
+	pdIdentityHistory []www.ServerPubKey
+
 	// Client websocket connections
 	ws    map[string]map[string]*wsContext // [uuid][]*context
 	wsMtx sync.RWMutex
@@ -127,12 +131,13 @@ func (p *politeiawww) handleVersion(w http.ResponseWriter, r *http.Request) {
 	log.Tracef("handleVersion")
 
 	versionReply := www.VersionReply{
-		Version:      www.PoliteiaWWWAPIVersion,
-		Route:        www.PoliteiaWWWAPIRoute,
-		BuildVersion: version.BuildMainVersion(),
-		PubKey:       hex.EncodeToString(p.cfg.Identity.Key[:]),
-		TestNet:      p.cfg.TestNet,
-		Mode:         p.cfg.Mode,
+		Version:       www.PoliteiaWWWAPIVersion,
+		Route:         www.PoliteiaWWWAPIRoute,
+		BuildVersion:  version.BuildMainVersion(),
+		PubKey:        hex.EncodeToString(p.cfg.Identity.Key[:]),
+		PubKeyHistory: p.pdIdentityHistory,
+		TestNet:       p.cfg.TestNet,
+		Mode:          p.cfg.Mode,
 	}
 
 	_, err := p.sessions.GetSessionUser(w, r)
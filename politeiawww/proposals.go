@@ -354,6 +354,7 @@ func (p *politeiawww) processBatchVoteSummary(ctx context.Context, bvs www.Batch
 			QuorumPercentage: v.QuorumPercentage,
 			PassPercentage:   v.PassPercentage,
 			Results:          results,
+			WinningOption:    v.WinningOption,
 		}
 	}
 
@@ -431,6 +432,8 @@ func convertVoteDetails(vd tkplugin.VoteDetails) (www.StartVote, www.StartVoteRe
 			QuorumPercentage: vd.Params.QuorumPercentage,
 			PassPercentage:   vd.Params.PassPercentage,
 			Options:          options,
+
+			RevealDuration: vd.Params.RevealDuration,
 		},
 		PublicKey: vd.PublicKey,
 		Signature: vd.Signature,
@@ -441,6 +444,9 @@ func convertVoteDetails(vd tkplugin.VoteDetails) (www.StartVote, www.StartVoteRe
 		EndHeight:        strconv.FormatUint(uint64(vd.EndBlockHeight), 10),
 		EligibleTickets:  vd.EligibleTickets,
 	}
+	if vd.RevealEndBlockHeight != 0 {
+		svr.RevealEndHeight = strconv.FormatUint(uint64(vd.RevealEndBlockHeight), 10)
+	}
 
 	return sv, svr
 }
@@ -540,10 +546,11 @@ func (p *politeiawww) processCastVotes(ctx context.Context, ballot *www.Ballot)
 	for _, v := range ballot.Votes {
 		token = v.Token
 		votes = append(votes, tkplugin.CastVote{
-			Token:     v.Token,
-			Ticket:    v.Ticket,
-			VoteBit:   v.VoteBit,
-			Signature: v.Signature,
+			Token:      v.Token,
+			Ticket:     v.Ticket,
+			VoteBit:    v.VoteBit,
+			Commitment: v.Commitment,
+			Signature:  v.Signature,
 		})
 	}
 	cb := tkplugin.CastBallot{
@@ -595,10 +602,11 @@ func (p *politeiawww) processVoteResults(ctx context.Context, token string) (*ww
 	votes := make([]www.CastVote, 0, len(rr.Votes))
 	for _, v := range rr.Votes {
 		votes = append(votes, www.CastVote{
-			Token:     v.Token,
-			Ticket:    v.Ticket,
-			VoteBit:   v.VoteBit,
-			Signature: v.Signature,
+			Token:      v.Token,
+			Ticket:     v.Ticket,
+			VoteBit:    v.VoteBit,
+			Signature:  v.Signature,
+			Commitment: v.Commitment,
 		})
 	}
 
@@ -1033,6 +1041,10 @@ func convertVoteStatusToWWW(status tkplugin.VoteStatusT) www.PropVoteStatusT {
 		return www.PropVoteStatusFinished
 	case tkplugin.VoteStatusRejected:
 		return www.PropVoteStatusFinished
+	case tkplugin.VoteStatusCancelled:
+		// The legacy API does not have a cancelled vote status. A
+		// cancelled vote is over, it just doesn't have an outcome.
+		return www.PropVoteStatusFinished
 	default:
 		return www.PropVoteStatusInvalid
 	}
@@ -1046,6 +1058,8 @@ func convertVoteTypeToWWW(t tkplugin.VoteT) www.VoteT {
 		return www.VoteTypeStandard
 	case tkplugin.VoteTypeRunoff:
 		return www.VoteTypeRunoff
+	case tkplugin.VoteTypeMultipleChoice:
+		return www.VoteTypeMultipleChoice
 	default:
 		return www.VoteTypeInvalid
 	}
@@ -1071,6 +1085,10 @@ func convertVoteErrorCodeToWWW(e tkplugin.VoteErrorT) decredplugin.ErrorStatusT
 		return decredplugin.ErrorStatusIneligibleTicket
 	case tkplugin.VoteErrorTicketAlreadyVoted:
 		return decredplugin.ErrorStatusDuplicateVote
+	case tkplugin.VoteErrorCommitmentInvalid:
+		// There is not decredplugin error code for this
+	case tkplugin.VoteErrorCommitmentNotFound:
+		// There is not decredplugin error code for this
 	default:
 	}
 	return decredplugin.ErrorStatusInternalError
@@ -5,8 +5,12 @@
 package records
 
 import (
+	"context"
+
+	pdv2 "github.com/decred/politeia/politeiad/api/v2"
 	v1 "github.com/decred/politeia/politeiawww/api/records/v1"
 	"github.com/decred/politeia/politeiawww/user"
+	"github.com/google/uuid"
 )
 
 const (
@@ -36,3 +40,74 @@ type EventEdit struct {
 type EventSetStatus struct {
 	Record v1.Record
 }
+
+// HandlePoliteiadEvent emits the records events that correspond to a
+// politeiad event. The records events are emitted once politeiad has
+// published the record update, regardless of which politeiawww instance made
+// the update.
+func (r *Records) HandlePoliteiadEvent(ctx context.Context, e pdv2.Event) {
+	switch e.Type {
+	case pdv2.EventTypeRecordNew, pdv2.EventTypeRecordEdit,
+		pdv2.EventTypeRecordSetStatus:
+		// These events are handled; continue
+	default:
+		return
+	}
+
+	// The event does not include the record files. Get the record
+	// version that the event corresponds to.
+	reqs := []pdv2.RecordRequest{
+		{
+			Token:   e.Token,
+			Version: e.Version,
+		},
+	}
+	pdr, err := r.politeiad.Records(ctx, reqs)
+	if err != nil {
+		log.Errorf("HandlePoliteiadEvent %v %v: Records: %v",
+			pdv2.EventTypes[e.Type], e.Token, err)
+		return
+	}
+	p, ok := pdr[e.Token]
+	if !ok {
+		log.Errorf("HandlePoliteiadEvent %v %v: record not found",
+			pdv2.EventTypes[e.Type], e.Token)
+		return
+	}
+	rc := convertRecordToV1(p)
+
+	// Get the record author and populate the record user data
+	uid, err := uuid.Parse(userIDFromMetadataStreams(rc.Metadata))
+	if err != nil {
+		log.Errorf("HandlePoliteiadEvent %v: user id: %v",
+			e.Token, err)
+		return
+	}
+	u, err := r.userdb.UserGetById(uid)
+	if err != nil {
+		log.Errorf("HandlePoliteiadEvent %v: UserGetById: %v",
+			e.Token, err)
+		return
+	}
+	recordPopulateUserData(&rc, *u)
+
+	switch e.Type {
+	case pdv2.EventTypeRecordNew:
+		r.events.Emit(EventTypeNew,
+			EventNew{
+				User:   *u,
+				Record: rc,
+			})
+	case pdv2.EventTypeRecordEdit:
+		r.events.Emit(EventTypeEdit,
+			EventEdit{
+				User:   *u,
+				Record: rc,
+			})
+	case pdv2.EventTypeRecordSetStatus:
+		r.events.Emit(EventTypeSetStatus,
+			EventSetStatus{
+				Record: rc,
+			})
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	pdv2 "github.com/decred/politeia/politeiad/api/v2"
+	"github.com/decred/politeia/politeiad/plugins/search"
 	"github.com/decred/politeia/politeiad/plugins/usermd"
 	v1 "github.com/decred/politeia/politeiawww/api/records/v1"
 	"github.com/decred/politeia/politeiawww/client"
@@ -85,13 +86,6 @@ func (r *Records) processNew(ctx context.Context, n v1.New, u user.User) (*v1.Ne
 		}
 	}
 
-	// Emit event
-	r.events.Emit(EventTypeNew,
-		EventNew{
-			User:   u,
-			Record: *rc,
-		})
-
 	return &v1.NewReply{
 		Record: *rc,
 	}, nil
@@ -176,13 +170,6 @@ func (r *Records) processEdit(ctx context.Context, e v1.Edit, u user.User) (*v1.
 		log.Debugf("%02v: %v", k, f.Name)
 	}
 
-	// Emit event
-	r.events.Emit(EventTypeEdit,
-		EventEdit{
-			User:   u,
-			Record: rc,
-		})
-
 	return &v1.EditReply{
 		Record: rc,
 	}, nil
@@ -232,12 +219,6 @@ func (r *Records) processSetStatus(ctx context.Context, ss v1.SetStatus, u user.
 	rc := convertRecordToV1(*pdr)
 	recordPopulateUserData(&rc, u)
 
-	// Emit event
-	r.events.Emit(EventTypeSetStatus,
-		EventSetStatus{
-			Record: rc,
-		})
-
 	return &v1.SetStatusReply{
 		Record: rc,
 	}, nil
@@ -275,6 +256,86 @@ func (r *Records) processDetails(ctx context.Context, d v1.Details, u *user.User
 	}, nil
 }
 
+func (r *Records) processDiff(ctx context.Context, d v1.Diff, u *user.User) (*v1.DiffReply, error) {
+	log.Tracef("processDiff: %v %v %v", d.Token, d.From, d.To)
+
+	// A from version is required. politeiad interprets a version of
+	// 0 as the most recent version of the record.
+	if d.From == 0 {
+		return nil, v1.UserErrorReply{
+			ErrorCode:    v1.ErrorCodeInputInvalid,
+			ErrorContext: "from version not provided",
+		}
+	}
+
+	// Get record diff
+	rd, err := r.politeiad.RecordDiff(ctx, d.Token, d.From, d.To)
+	if err != nil {
+		return nil, err
+	}
+	statusChanges, err := statusChangesFromDiff(rd.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only admins and the record author are allowed to retrieve
+	// unvetted record files. Remove the file diffs if the user is not
+	// an admin or the author. This is a public route so a user may not
+	// exist.
+	files := convertFileDiffsToV1(rd.Files)
+	if rd.To.State != pdv2.RecordStateVetted {
+		var (
+			isAdmin  = u != nil && u.Admin
+			isAuthor bool
+		)
+		if u != nil && !isAdmin {
+			authorID, err := r.politeiad.Author(ctx, d.Token)
+			if err != nil {
+				return nil, err
+			}
+			isAuthor = u.ID.String() == authorID
+		}
+		if !isAuthor && !isAdmin {
+			files = []v1.FileDiff{}
+		}
+	}
+
+	return &v1.DiffReply{
+		From:          convertDiffVersionToV1(rd.From),
+		To:            convertDiffVersionToV1(rd.To),
+		Files:         files,
+		Metadata:      convertMetadataStreamDiffsToV1(rd.Metadata),
+		StatusChanges: statusChanges,
+	}, nil
+}
+
+// statusChangesFromDiff returns the status changes that were added to the
+// status changes metadata stream in a record diff. Each line of a metadata
+// stream diff contains a single JSON encoded status change.
+func statusChangesFromDiff(md []pdv2.MetadataStreamDiff) ([]v1.StatusChange, error) {
+	sc := make([]v1.StatusChange, 0, 4)
+	for _, v := range md {
+		if v.PluginID != usermd.PluginID ||
+			v.StreamID != usermd.StreamIDStatusChanges {
+			continue
+		}
+		for _, h := range v.Hunks {
+			for _, l := range h.Lines {
+				if l.Type != pdv2.DiffAdded {
+					continue
+				}
+				var s v1.StatusChange
+				err := json.Unmarshal([]byte(l.Text), &s)
+				if err != nil {
+					return nil, fmt.Errorf("decode status change: %v", err)
+				}
+				sc = append(sc, s)
+			}
+		}
+	}
+	return sc, nil
+}
+
 func (r *Records) processTimestamps(ctx context.Context, t v1.Timestamps, isAdmin bool) (*v1.TimestampsReply, error) {
 	log.Tracef("processTimestamps: %v %v", t.Token, t.Version)
 
@@ -449,6 +510,42 @@ func (r *Records) processInventoryOrdered(ctx context.Context, i v1.InventoryOrd
 	}, nil
 }
 
+func (r *Records) processSearch(ctx context.Context, s v1.Search, u *user.User) (*v1.SearchReply, error) {
+	log.Tracef("processSearch: %v %v %v", s.Query, s.State, s.Page)
+
+	// Verify state
+	state := convertStateToPD(s.State)
+	if state == pdv2.RecordStateInvalid {
+		return nil, v1.UserErrorReply{
+			ErrorCode: v1.ErrorCodeRecordStateInvalid,
+		}
+	}
+
+	// Only admins are allowed to search unvetted records. This is a
+	// public route so a user may or may not exist.
+	isAdmin := u != nil && u.Admin
+	if state == pdv2.RecordStateUnvetted && !isAdmin {
+		return &v1.SearchReply{
+			Results: []v1.SearchResult{},
+		}, nil
+	}
+
+	// Search records
+	sr, err := r.politeiad.Search(ctx, search.Search{
+		Query:    s.Query,
+		Unvetted: state == pdv2.RecordStateUnvetted,
+		Page:     s.Page,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.SearchReply{
+		Results: convertSearchResultsToV1(sr.Results),
+		Total:   sr.Total,
+	}, nil
+}
+
 func (r *Records) processUserRecords(ctx context.Context, ur v1.UserRecords, u *user.User) (*v1.UserRecordsReply, error) {
 	log.Tracef("processUserRecords: %v", ur.UserID)
 
@@ -651,6 +748,77 @@ func convertTimestampToV1(t pdv2.Timestamp) v1.Timestamp {
 	}
 }
 
+func convertDiffVersionToV1(v pdv2.DiffVersion) v1.DiffVersion {
+	return v1.DiffVersion{
+		State:     convertStateToV1(v.State),
+		Status:    convertStatusToV1(v.Status),
+		Version:   v.Version,
+		Timestamp: v.Timestamp,
+	}
+}
+
+func convertDiffHunksToV1(hunks []pdv2.DiffHunk) []v1.DiffHunk {
+	h := make([]v1.DiffHunk, 0, len(hunks))
+	for _, v := range hunks {
+		lines := make([]v1.DiffLine, 0, len(v.Lines))
+		for _, l := range v.Lines {
+			lines = append(lines, v1.DiffLine{
+				Type: v1.DiffT(l.Type),
+				Text: l.Text,
+			})
+		}
+		h = append(h, v1.DiffHunk{
+			FromLine:  v.FromLine,
+			FromLines: v.FromLines,
+			ToLine:    v.ToLine,
+			ToLines:   v.ToLines,
+			Lines:     lines,
+		})
+	}
+	return h
+}
+
+func convertFileDiffsToV1(files []pdv2.FileDiff) []v1.FileDiff {
+	fd := make([]v1.FileDiff, 0, len(files))
+	for _, v := range files {
+		fd = append(fd, v1.FileDiff{
+			Name:       v.Name,
+			Type:       v1.DiffT(v.Type),
+			MIME:       v.MIME,
+			FromDigest: v.FromDigest,
+			ToDigest:   v.ToDigest,
+			Hunks:      convertDiffHunksToV1(v.Hunks),
+		})
+	}
+	return fd
+}
+
+func convertMetadataStreamDiffsToV1(metadata []pdv2.MetadataStreamDiff) []v1.MetadataStreamDiff {
+	md := make([]v1.MetadataStreamDiff, 0, len(metadata))
+	for _, v := range metadata {
+		md = append(md, v1.MetadataStreamDiff{
+			PluginID: v.PluginID,
+			StreamID: v.StreamID,
+			Type:     v1.DiffT(v.Type),
+			Hunks:    convertDiffHunksToV1(v.Hunks),
+		})
+	}
+	return md
+}
+
+func convertSearchResultsToV1(sr []search.SearchResult) []v1.SearchResult {
+	results := make([]v1.SearchResult, 0, len(sr))
+	for _, v := range sr {
+		results = append(results, v1.SearchResult{
+			Token:     v.Token,
+			Name:      v.Name,
+			Score:     v.Score,
+			Timestamp: v.Timestamp,
+		})
+	}
+	return results
+}
+
 func convertFilesToPD(f []v1.File) []pdv2.File {
 	files := make([]pdv2.File, 0, len(f))
 	for _, v := range f {
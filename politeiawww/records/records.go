@@ -152,6 +152,39 @@ func (c *Records) HandleDetails(w http.ResponseWriter, r *http.Request) {
 	util.RespondWithJSON(w, http.StatusOK, dr)
 }
 
+// HandleDiff is the request handler for the records v1 Diff route.
+func (c *Records) HandleDiff(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleDiff")
+
+	var d v1.Diff
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&d); err != nil {
+		respondWithError(w, r, "HandleDiff: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	// Lookup session user. This is a public route so a session may not
+	// exist. Ignore any session not found errors.
+	u, err := c.sessions.GetSessionUser(w, r)
+	if err != nil && err != sessions.ErrSessionNotFound {
+		respondWithError(w, r,
+			"HandleDiff: GetSessionUser: %v", err)
+		return
+	}
+
+	dr, err := c.processDiff(r.Context(), d, u)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleDiff: processDiff: %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, dr)
+}
+
 // HandleTimestamps is the request handler for the records v1 Timestamps route.
 func (c *Records) HandleTimestamps(w http.ResponseWriter, r *http.Request) {
 	log.Tracef("HandleTimestamps")
@@ -286,6 +319,39 @@ func (c *Records) HandleInventoryOrdered(w http.ResponseWriter, r *http.Request)
 	util.RespondWithJSON(w, http.StatusOK, ir)
 }
 
+// HandleSearch is the request handler for the records v1 Search route.
+func (c *Records) HandleSearch(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleSearch")
+
+	var s v1.Search
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&s); err != nil {
+		respondWithError(w, r, "HandleSearch: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	// Lookup session user. This is a public route so a session may not
+	// exist. Ignore any session not found errors.
+	u, err := c.sessions.GetSessionUser(w, r)
+	if err != nil && err != sessions.ErrSessionNotFound {
+		respondWithError(w, r,
+			"HandleSearch: GetSessionUser: %v", err)
+		return
+	}
+
+	sr, err := c.processSearch(r.Context(), s, u)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleSearch: processSearch: %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, sr)
+}
+
 // HandleUserRecords is the request handler for the records v1 UserRecords
 // route.
 func (c *Records) HandleUserRecords(w http.ResponseWriter, r *http.Request) {
@@ -5,6 +5,11 @@
 package ticketvote
 
 import (
+	"context"
+	"encoding/json"
+
+	pdv2 "github.com/decred/politeia/politeiad/api/v2"
+	"github.com/decred/politeia/politeiad/plugins/ticketvote"
 	v1 "github.com/decred/politeia/politeiawww/api/ticketvote/v1"
 	"github.com/decred/politeia/politeiawww/user"
 )
@@ -15,6 +20,9 @@ const (
 
 	// EventTypeStart is emitted when a vote is started.
 	EventTypeStart = "ticketvote-start"
+
+	// EventTypeFinished is emitted when a vote has finished.
+	EventTypeFinished = "ticketvote-finished"
 )
 
 // EventAuthorize is the event data for EventTypeAuthorize.
@@ -28,3 +36,83 @@ type EventStart struct {
 	Starts []v1.StartDetails
 	User   user.User
 }
+
+// EventFinished is the event data for EventTypeFinished.
+type EventFinished struct {
+	Token   string
+	Summary v1.Summary
+}
+
+// HandlePoliteiadEvent emits the ticketvote events that correspond to a
+// politeiad event. The ticketvote events are emitted once politeiad has
+// published the ticketvote plugin write or the ticketvote plugin event,
+// regardless of which politeiawww instance made the write.
+func (t *TicketVote) HandlePoliteiadEvent(ctx context.Context, e pdv2.Event) {
+	if e.PluginID != ticketvote.PluginID {
+		return
+	}
+
+	switch {
+	case e.Type == pdv2.EventTypePluginWrite &&
+		e.Name == ticketvote.CmdAuthorize:
+		var a ticketvote.Authorize
+		err := json.Unmarshal([]byte(e.Payload), &a)
+		if err != nil {
+			log.Errorf("HandlePoliteiadEvent %v: unmarshal: %v", e.Token, err)
+			return
+		}
+		u, err := t.userdb.UserGetByPubKey(a.PublicKey)
+		if err != nil {
+			log.Errorf("HandlePoliteiadEvent %v: UserGetByPubKey: %v",
+				e.Token, err)
+			return
+		}
+		t.events.Emit(EventTypeAuthorize,
+			EventAuthorize{
+				Auth: convertAuthorizeToV1(a),
+				User: *u,
+			})
+
+	case e.Type == pdv2.EventTypePluginWrite &&
+		e.Name == ticketvote.CmdStart:
+		var s ticketvote.Start
+		err := json.Unmarshal([]byte(e.Payload), &s)
+		if err != nil {
+			log.Errorf("HandlePoliteiadEvent %v: unmarshal: %v", e.Token, err)
+			return
+		}
+		if len(s.Starts) == 0 {
+			return
+		}
+		// All start details are signed by the same admin
+		u, err := t.userdb.UserGetByPubKey(s.Starts[0].PublicKey)
+		if err != nil {
+			log.Errorf("HandlePoliteiadEvent %v: UserGetByPubKey: %v",
+				e.Token, err)
+			return
+		}
+		starts := make([]v1.StartDetails, 0, len(s.Starts))
+		for _, v := range s.Starts {
+			starts = append(starts, convertStartDetailsToV1(v))
+		}
+		t.events.Emit(EventTypeStart,
+			EventStart{
+				Starts: starts,
+				User:   *u,
+			})
+
+	case e.Type == pdv2.EventTypePlugin &&
+		e.Name == ticketvote.EventVoteFinished:
+		var vf ticketvote.VoteFinished
+		err := json.Unmarshal([]byte(e.Payload), &vf)
+		if err != nil {
+			log.Errorf("HandlePoliteiadEvent %v: unmarshal: %v", e.Token, err)
+			return
+		}
+		t.events.Emit(EventTypeFinished,
+			EventFinished{
+				Token:   vf.Token,
+				Summary: convertSummaryToV1(vf.Summary),
+			})
+	}
+}
@@ -49,13 +49,6 @@ func (t *TicketVote) processAuthorize(ctx context.Context, a v1.Authorize, u use
 		return nil, err
 	}
 
-	// Emit event
-	t.events.Emit(EventTypeAuthorize,
-		EventAuthorize{
-			Auth: a,
-			User: u,
-		})
-
 	return &v1.AuthorizeReply{
 		Timestamp: tar.Timestamp,
 		Receipt:   tar.Receipt,
@@ -91,9 +84,9 @@ func (t *TicketVote) processStart(ctx context.Context, s v1.Start, u user.User)
 			// This is a runoff vote. Execute the plugin command on the
 			// parent record.
 			token = v.Params.Parent
-		case v1.VoteTypeStandard:
-			// This is a standard vote. Execute the plugin command on the
-			// record specified in the vote params.
+		case v1.VoteTypeStandard, v1.VoteTypeMultipleChoice:
+			// This is a single record vote. Execute the plugin command
+			// on the record specified in the vote params.
 			token = v.Params.Token
 		}
 	}
@@ -105,13 +98,6 @@ func (t *TicketVote) processStart(ctx context.Context, s v1.Start, u user.User)
 		return nil, err
 	}
 
-	// Emit notification for each start
-	t.events.Emit(EventTypeStart,
-		EventStart{
-			Starts: s.Starts,
-			User:   u,
-		})
-
 	return &v1.StartReply{
 		Receipt:          tsr.Receipt,
 		StartBlockHeight: tsr.StartBlockHeight,
@@ -145,6 +131,59 @@ func (t *TicketVote) processCastBallot(ctx context.Context, cb v1.CastBallot) (*
 	}, nil
 }
 
+func (t *TicketVote) processReveal(ctx context.Context, r v1.Reveal) (*v1.RevealReply, error) {
+	log.Tracef("processReveal")
+
+	// Get token from one of the votes
+	var token string
+	for _, v := range r.Votes {
+		token = v.Token
+		break
+	}
+
+	// Send plugin command
+	tr := ticketvote.Reveal{
+		Votes: convertRevealVotesToPlugin(r.Votes),
+	}
+	trr, err := t.politeiad.TicketVoteReveal(ctx, token, tr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.RevealReply{
+		Receipts: convertRevealVoteRepliesToV1(trr.Receipts),
+	}, nil
+}
+
+func (t *TicketVote) processCancel(ctx context.Context, c v1.Cancel, u user.User) (*v1.CancelReply, error) {
+	log.Tracef("processCancel: %v", c.Token)
+
+	// Verify user signed with their active identity
+	if u.PublicKey() != c.PublicKey {
+		return nil, v1.UserErrorReply{
+			ErrorCode:    v1.ErrorCodePublicKeyInvalid,
+			ErrorContext: "not active identity",
+		}
+	}
+
+	// Send plugin command
+	tc := ticketvote.Cancel{
+		Token:     c.Token,
+		Reason:    c.Reason,
+		PublicKey: c.PublicKey,
+		Signature: c.Signature,
+	}
+	tcr, err := t.politeiad.TicketVoteCancel(ctx, tc)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v1.CancelReply{
+		Timestamp: tcr.Timestamp,
+		Receipt:   tcr.Receipt,
+	}, nil
+}
+
 func (t *TicketVote) processDetails(ctx context.Context, d v1.Details) (*v1.DetailsReply, error) {
 	log.Tracef("processsDetails: %v", d.Token)
 
@@ -159,9 +198,16 @@ func (t *TicketVote) processDetails(ctx context.Context, d v1.Details) (*v1.Deta
 		vote = &vd
 	}
 
+	var cancel *v1.CancelDetails
+	if tdr.Cancel != nil {
+		cd := convertCancelDetailsToV1(*tdr.Cancel)
+		cancel = &cd
+	}
+
 	return &v1.DetailsReply{
-		Auths: convertAuthDetailsToV1(tdr.Auths),
-		Vote:  vote,
+		Auths:  convertAuthDetailsToV1(tdr.Auths),
+		Vote:   vote,
+		Cancel: cancel,
 	}, nil
 }
 
@@ -251,11 +297,16 @@ func (t *TicketVote) processTimestamps(ctx context.Context, ts v1.Timestamps) (*
 		votes = make([]v1.Timestamp, 0, len(tsr.Votes))
 
 		details *v1.Timestamp
+		cancel  *v1.Timestamp
 	)
 	if tsr.Details != nil {
 		dt := convertTimestampToV1(*tsr.Details)
 		details = &dt
 	}
+	if tsr.Cancel != nil {
+		ct := convertTimestampToV1(*tsr.Cancel)
+		cancel = &ct
+	}
 	for _, v := range tsr.Auths {
 		auths = append(auths, convertTimestampToV1(v))
 	}
@@ -266,6 +317,7 @@ func (t *TicketVote) processTimestamps(ctx context.Context, ts v1.Timestamps) (*
 	return &v1.TimestampsReply{
 		Auths:   auths,
 		Details: details,
+		Cancel:  cancel,
 		Votes:   votes,
 	}, nil
 }
@@ -284,6 +336,8 @@ func convertVoteStatusToPlugin(s v1.VoteStatusT) ticketvote.VoteStatusT {
 		return ticketvote.VoteStatusApproved
 	case v1.VoteStatusRejected:
 		return ticketvote.VoteStatusRejected
+	case v1.VoteStatusCancelled:
+		return ticketvote.VoteStatusCancelled
 	default:
 		return ticketvote.VoteStatusInvalid
 	}
@@ -295,6 +349,8 @@ func convertVoteTypeToPlugin(t v1.VoteT) ticketvote.VoteT {
 		return ticketvote.VoteTypeStandard
 	case v1.VoteTypeRunoff:
 		return ticketvote.VoteTypeRunoff
+	case v1.VoteTypeMultipleChoice:
+		return ticketvote.VoteTypeMultipleChoice
 	}
 	return ticketvote.VoteTypeInvalid
 }
@@ -309,6 +365,7 @@ func convertVoteParamsToPlugin(v v1.VoteParams) ticketvote.VoteParams {
 		QuorumPercentage: v.QuorumPercentage,
 		PassPercentage:   v.PassPercentage,
 		Parent:           v.Parent,
+		RevealDuration:   v.RevealDuration,
 	}
 	// Convert vote options
 	vo := make([]ticketvote.VoteOption, 0, len(v.Options))
@@ -346,21 +403,37 @@ func convertCastVotesToPlugin(votes []v1.CastVote) []ticketvote.CastVote {
 	cv := make([]ticketvote.CastVote, 0, len(votes))
 	for _, v := range votes {
 		cv = append(cv, ticketvote.CastVote{
-			Token:     v.Token,
-			Ticket:    v.Ticket,
-			VoteBit:   v.VoteBit,
-			Signature: v.Signature,
+			Token:      v.Token,
+			Ticket:     v.Ticket,
+			VoteBit:    v.VoteBit,
+			Commitment: v.Commitment,
+			Signature:  v.Signature,
 		})
 	}
 	return cv
 }
 
+func convertRevealVotesToPlugin(votes []v1.RevealVote) []ticketvote.RevealVote {
+	rv := make([]ticketvote.RevealVote, 0, len(votes))
+	for _, v := range votes {
+		rv = append(rv, ticketvote.RevealVote{
+			Token:   v.Token,
+			Ticket:  v.Ticket,
+			VoteBit: v.VoteBit,
+			Salt:    v.Salt,
+		})
+	}
+	return rv
+}
+
 func convertVoteTypeToV1(t ticketvote.VoteT) v1.VoteT {
 	switch t {
 	case ticketvote.VoteTypeStandard:
 		return v1.VoteTypeStandard
 	case ticketvote.VoteTypeRunoff:
 		return v1.VoteTypeRunoff
+	case ticketvote.VoteTypeMultipleChoice:
+		return v1.VoteTypeMultipleChoice
 	}
 	return v1.VoteTypeInvalid
 
@@ -375,6 +448,7 @@ func convertVoteParamsToV1(v ticketvote.VoteParams) v1.VoteParams {
 		Duration:         v.Duration,
 		QuorumPercentage: v.QuorumPercentage,
 		PassPercentage:   v.PassPercentage,
+		RevealDuration:   v.RevealDuration,
 	}
 	vo := make([]v1.VoteOption, 0, len(v.Options))
 	for _, o := range v.Options {
@@ -389,6 +463,24 @@ func convertVoteParamsToV1(v ticketvote.VoteParams) v1.VoteParams {
 	return vp
 }
 
+func convertAuthorizeToV1(a ticketvote.Authorize) v1.Authorize {
+	return v1.Authorize{
+		Token:     a.Token,
+		Version:   a.Version,
+		Action:    v1.AuthActionT(a.Action),
+		PublicKey: a.PublicKey,
+		Signature: a.Signature,
+	}
+}
+
+func convertStartDetailsToV1(sd ticketvote.StartDetails) v1.StartDetails {
+	return v1.StartDetails{
+		Params:    convertVoteParamsToV1(sd.Params),
+		PublicKey: sd.PublicKey,
+		Signature: sd.Signature,
+	}
+}
+
 func convertVoteErrorToV1(e ticketvote.VoteErrorT) v1.VoteErrorT {
 	switch e {
 	case ticketvote.VoteErrorInvalid:
@@ -405,6 +497,10 @@ func convertVoteErrorToV1(e ticketvote.VoteErrorT) v1.VoteErrorT {
 		return v1.VoteErrorTicketAlreadyVoted
 	case ticketvote.VoteErrorTicketNotEligible:
 		return v1.VoteErrorTicketNotEligible
+	case ticketvote.VoteErrorCommitmentInvalid:
+		return v1.VoteErrorCommitmentInvalid
+	case ticketvote.VoteErrorCommitmentNotFound:
+		return v1.VoteErrorCommitmentNotFound
 	default:
 		return v1.VoteErrorInternalError
 	}
@@ -423,6 +519,19 @@ func convertCastVoteRepliesToV1(replies []ticketvote.CastVoteReply) []v1.CastVot
 	return r
 }
 
+func convertRevealVoteRepliesToV1(replies []ticketvote.RevealVoteReply) []v1.RevealVoteReply {
+	r := make([]v1.RevealVoteReply, 0, len(replies))
+	for _, v := range replies {
+		r = append(r, v1.RevealVoteReply{
+			Ticket:       v.Ticket,
+			Receipt:      v.Receipt,
+			ErrorCode:    convertVoteErrorToV1(v.ErrorCode),
+			ErrorContext: v.ErrorContext,
+		})
+	}
+	return r
+}
+
 func convertVoteDetailsToV1(vd ticketvote.VoteDetails) v1.VoteDetails {
 	return v1.VoteDetails{
 		Params:           convertVoteParamsToV1(vd.Params),
@@ -433,6 +542,8 @@ func convertVoteDetailsToV1(vd ticketvote.VoteDetails) v1.VoteDetails {
 		StartBlockHash:   vd.StartBlockHash,
 		EndBlockHeight:   vd.EndBlockHeight,
 		EligibleTickets:  vd.EligibleTickets,
+
+		RevealEndBlockHeight: vd.RevealEndBlockHeight,
 	}
 }
 
@@ -452,17 +563,30 @@ func convertAuthDetailsToV1(auths []ticketvote.AuthDetails) []v1.AuthDetails {
 	return a
 }
 
+func convertCancelDetailsToV1(cd ticketvote.CancelDetails) v1.CancelDetails {
+	return v1.CancelDetails{
+		Token:     cd.Token,
+		Reason:    cd.Reason,
+		PublicKey: cd.PublicKey,
+		Signature: cd.Signature,
+		Timestamp: cd.Timestamp,
+		Receipt:   cd.Receipt,
+	}
+}
+
 func convertCastVoteDetailsToV1(votes []ticketvote.CastVoteDetails) []v1.CastVoteDetails {
 	vs := make([]v1.CastVoteDetails, 0, len(votes))
 	for _, v := range votes {
 		vs = append(vs, v1.CastVoteDetails{
-			Token:     v.Token,
-			Ticket:    v.Ticket,
-			VoteBit:   v.VoteBit,
-			Address:   v.Address,
-			Signature: v.Signature,
-			Receipt:   v.Receipt,
-			Timestamp: v.Timestamp,
+			Token:      v.Token,
+			Ticket:     v.Ticket,
+			VoteBit:    v.VoteBit,
+			Commitment: v.Commitment,
+			Salt:       v.Salt,
+			Address:    v.Address,
+			Signature:  v.Signature,
+			Receipt:    v.Receipt,
+			Timestamp:  v.Timestamp,
 		})
 	}
 	return vs
@@ -484,11 +608,26 @@ func convertVoteStatusToV1(s ticketvote.VoteStatusT) v1.VoteStatusT {
 		return v1.VoteStatusApproved
 	case ticketvote.VoteStatusRejected:
 		return v1.VoteStatusRejected
+	case ticketvote.VoteStatusCancelled:
+		return v1.VoteStatusCancelled
 	default:
 		return v1.VoteStatusInvalid
 	}
 }
 
+func convertBudgetOutcomeToV1(o ticketvote.BudgetOutcomeT) v1.BudgetOutcomeT {
+	switch o {
+	case ticketvote.BudgetOutcomeFunded:
+		return v1.BudgetOutcomeFunded
+	case ticketvote.BudgetOutcomeNotPassed:
+		return v1.BudgetOutcomeNotPassed
+	case ticketvote.BudgetOutcomeOverBudget:
+		return v1.BudgetOutcomeOverBudget
+	default:
+		return v1.BudgetOutcomeInvalid
+	}
+}
+
 func convertSummaryToV1(s ticketvote.SummaryReply) v1.Summary {
 	results := make([]v1.VoteResult, 0, len(s.Results))
 	for _, v := range s.Results {
@@ -499,6 +638,15 @@ func convertSummaryToV1(s ticketvote.SummaryReply) v1.Summary {
 			Votes:       v.Votes,
 		})
 	}
+	var rb *v1.RunoffBudget
+	if s.Budget != nil {
+		rb = &v1.RunoffBudget{
+			Total:     s.Budget.Total,
+			Amount:    s.Budget.Amount,
+			Remaining: s.Budget.Remaining,
+			Outcome:   convertBudgetOutcomeToV1(s.Budget.Outcome),
+		}
+	}
 	return v1.Summary{
 		Type:             convertVoteTypeToV1(s.Type),
 		Status:           convertVoteStatusToV1(s.Status),
@@ -510,7 +658,12 @@ func convertSummaryToV1(s ticketvote.SummaryReply) v1.Summary {
 		QuorumPercentage: s.QuorumPercentage,
 		PassPercentage:   s.PassPercentage,
 		Results:          results,
+		Budget:           rb,
+		WinningOption:    s.WinningOption,
 		BestBlock:        s.BestBlock,
+
+		RevealEndBlockHeight: s.RevealEndBlockHeight,
+		Unrevealed:           s.Unrevealed,
 	}
 }
 
@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"sync"
 
 	pdv2 "github.com/decred/politeia/politeiad/api/v2"
 	pdclient "github.com/decred/politeia/politeiad/client"
@@ -17,6 +18,7 @@ import (
 	"github.com/decred/politeia/politeiawww/config"
 	"github.com/decred/politeia/politeiawww/events"
 	"github.com/decred/politeia/politeiawww/sessions"
+	"github.com/decred/politeia/politeiawww/user"
 	"github.com/decred/politeia/util"
 )
 
@@ -24,16 +26,25 @@ import (
 type TicketVote struct {
 	cfg       *config.Config
 	politeiad *pdclient.Client
+	userdb    user.Database
 	sessions  *sessions.Sessions
 	events    *events.Manager
-	policy    *v1.PolicyReply
+
+	// mtx protects the policy, which is updated when the politeiad
+	// plugin settings are updated at runtime.
+	mtx    sync.RWMutex
+	policy *v1.PolicyReply
 }
 
 // HandlePolicy is the request handler for the ticketvote v1 Policy route.
 func (t *TicketVote) HandlePolicy(w http.ResponseWriter, r *http.Request) {
 	log.Tracef("HandlePolicy")
 
-	util.RespondWithJSON(w, http.StatusOK, t.policy)
+	t.mtx.RLock()
+	policy := t.policy
+	t.mtx.RUnlock()
+
+	util.RespondWithJSON(w, http.StatusOK, policy)
 }
 
 // HandleAuthorize is the request handler for the ticketvote v1 Authorize
@@ -124,6 +135,61 @@ func (t *TicketVote) HandleCastBallot(w http.ResponseWriter, r *http.Request) {
 	util.RespondWithJSON(w, http.StatusOK, cbr)
 }
 
+// HandleReveal is the request handler for the ticketvote v1 Reveal route.
+func (t *TicketVote) HandleReveal(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleReveal")
+
+	var rv v1.Reveal
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&rv); err != nil {
+		respondWithError(w, r, "HandleReveal: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	rr, err := t.processReveal(r.Context(), rv)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleReveal: processReveal: %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, rr)
+}
+
+// HandleCancel is the request handler for the ticketvote v1 Cancel route.
+func (t *TicketVote) HandleCancel(w http.ResponseWriter, r *http.Request) {
+	log.Tracef("HandleCancel")
+
+	var c v1.Cancel
+	decoder := json.NewDecoder(r.Body)
+	if err := decoder.Decode(&c); err != nil {
+		respondWithError(w, r, "HandleCancel: unmarshal",
+			v1.UserErrorReply{
+				ErrorCode: v1.ErrorCodeInputInvalid,
+			})
+		return
+	}
+
+	u, err := t.sessions.GetSessionUser(w, r)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleCancel: GetSessionUser: %v", err)
+		return
+	}
+
+	cr, err := t.processCancel(r.Context(), c, *u)
+	if err != nil {
+		respondWithError(w, r,
+			"HandleCancel: processCancel: %v", err)
+		return
+	}
+
+	util.RespondWithJSON(w, http.StatusOK, cr)
+}
+
 // HandleDetails is the request handler for the ticketvote v1 Details route.
 func (t *TicketVote) HandleDetails(w http.ResponseWriter, r *http.Request) {
 	log.Tracef("HandleDetails")
@@ -273,7 +339,42 @@ func (t *TicketVote) HandleTimestamps(w http.ResponseWriter, r *http.Request) {
 }
 
 // New returns a new TicketVote context.
-func New(cfg *config.Config, pdc *pdclient.Client, s *sessions.Sessions, e *events.Manager, plugins []pdv2.Plugin) (*TicketVote, error) {
+func New(cfg *config.Config, pdc *pdclient.Client, udb user.Database, s *sessions.Sessions, e *events.Manager, plugins []pdv2.Plugin) (*TicketVote, error) {
+	policy, err := policyNew(plugins)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TicketVote{
+		cfg:       cfg,
+		politeiad: pdc,
+		userdb:    udb,
+		sessions:  s,
+		events:    e,
+		policy:    policy,
+	}, nil
+}
+
+// PolicyUpdate updates the ticketvote policy using the provided politeiad
+// plugin settings. The plugin settings can be updated in politeiad at
+// runtime.
+func (t *TicketVote) PolicyUpdate(plugins []pdv2.Plugin) error {
+	policy, err := policyNew(plugins)
+	if err != nil {
+		return err
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	t.policy = policy
+
+	return nil
+}
+
+// policyNew returns the ticketvote policy for the provided politeiad plugin
+// settings.
+func policyNew(plugins []pdv2.Plugin) (*v1.PolicyReply, error) {
 	// Parse plugin settings
 	var (
 		linkByPeriodMin int64
@@ -334,16 +435,10 @@ func New(cfg *config.Config, pdc *pdclient.Client, s *sessions.Sessions, e *even
 			ticketvote.SettingKeyVoteDurationMax)
 	}
 
-	return &TicketVote{
-		cfg:       cfg,
-		politeiad: pdc,
-		sessions:  s,
-		events:    e,
-		policy: &v1.PolicyReply{
-			LinkByPeriodMin: linkByPeriodMin,
-			LinkByPeriodMax: linkByPeriodMax,
-			VoteDurationMin: voteDurationMin,
-			VoteDurationMax: voteDurationMax,
-		},
+	return &v1.PolicyReply{
+		LinkByPeriodMin: linkByPeriodMin,
+		LinkByPeriodMax: linkByPeriodMax,
+		VoteDurationMin: voteDurationMin,
+		VoteDurationMax: voteDurationMax,
 	}, nil
 }
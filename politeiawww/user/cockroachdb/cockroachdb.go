@@ -13,6 +13,7 @@ import (
 	"io/ioutil"
 	"net/url"
 	"sync"
+	"time"
 
 	"github.com/decred/politeia/politeiawww/user"
 	"github.com/decred/politeia/util"
@@ -30,6 +31,7 @@ const (
 	tableUsers      = "users"
 	tableIdentities = "identities"
 	tableSessions   = "sessions"
+	tableEvents     = "events"
 
 	// Database user (read/write access)
 	userPoliteiawww = "politeiawww"
@@ -37,6 +39,11 @@ const (
 	// Key-value store keys
 	keyVersion             = "version"
 	keyPaywallAddressIndex = "paywalladdressindex"
+
+	// eventClaimExpiry is the number of seconds that a politeiad event
+	// claim is kept for. politeiad only buffers the most recent events,
+	// so an event can no longer be received once its claim expires.
+	eventClaimExpiry = 7 * 24 * 60 * 60 // 1 week
 )
 
 // cockroachdb implements the user database interface.
@@ -553,6 +560,37 @@ func (c *cockroachdb) SessionsDeleteByUserID(uid uuid.UUID, exemptSessionIDs []s
 		Error
 }
 
+// EventClaim claims a politeiad event for the caller. The event ID is the
+// primary key of the events table, so only the first politeiawww instance
+// that inserts the event claims it. The claims that have expired are deleted
+// prior to the insert.
+//
+// EventClaim satisfies the Database interface.
+func (c *cockroachdb) EventClaim(eventID string) (bool, error) {
+	log.Tracef("EventClaim: %v", eventID)
+
+	if c.isShutdown() {
+		return false, user.ErrShutdown
+	}
+
+	now := time.Now().Unix()
+	err := c.userDB.
+		Where("claimed_at < ?", now-eventClaimExpiry).
+		Delete(Event{}).
+		Error
+	if err != nil {
+		return false, fmt.Errorf("delete expired claims: %w", err)
+	}
+
+	tx := c.userDB.Exec("INSERT INTO events (id, claimed_at) "+
+		"VALUES (?, ?) ON CONFLICT DO NOTHING", eventID, now)
+	if tx.Error != nil {
+		return false, fmt.Errorf("insert event: %w", tx.Error)
+	}
+
+	return tx.RowsAffected == 1, nil
+}
+
 // rotateKeys rotates the existing database encryption key with the given new
 // key.
 //
@@ -782,6 +820,12 @@ func (c *cockroachdb) createTables(tx *gorm.DB) error {
 			return err
 		}
 	}
+	if !tx.HasTable(tableEvents) {
+		err := tx.CreateTable(&Event{}).Error
+		if err != nil {
+			return err
+		}
+	}
 
 	// Insert version record
 	kv := KeyValue{
@@ -902,3 +902,79 @@ func TestSessionsDeleteByUserID(t *testing.T) {
 		t.Errorf("unfulfilled expectations: %s", err)
 	}
 }
+
+func TestEventClaim(t *testing.T) {
+	cdb, mock, close := setupTestDB(t)
+	defer close()
+
+	// Arguments
+	eventID := "16b8e4c4a2c0f000-1"
+
+	// Queries
+	sqlDelete := `DELETE FROM "events" WHERE (claimed_at < $1)`
+	sqlInsert := `INSERT INTO events (id, claimed_at) ` +
+		`VALUES ($1, $2) ON CONFLICT DO NOTHING`
+
+	// Success Expectations
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(sqlDelete)).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectExec(regexp.QuoteMeta(sqlInsert)).
+		WithArgs(eventID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Execute method
+	claimed, err := cdb.EventClaim(eventID)
+	if err != nil {
+		t.Errorf("EventClaim unwanted error: %s", err)
+	}
+	if !claimed {
+		t.Errorf("got claimed %v, want true", claimed)
+	}
+
+	// Already claimed expectations
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(sqlDelete)).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+	mock.ExpectExec(regexp.QuoteMeta(sqlInsert)).
+		WithArgs(eventID, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Execute method
+	claimed, err = cdb.EventClaim(eventID)
+	if err != nil {
+		t.Errorf("EventClaim unwanted error: %s", err)
+	}
+	if claimed {
+		t.Errorf("got claimed %v, want false", claimed)
+	}
+
+	// Negative Expectations
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta(sqlDelete)).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnError(errDelete)
+	mock.ExpectRollback()
+
+	// Execute method
+	_, err = cdb.EventClaim(eventID)
+	if err == nil {
+		t.Errorf("expecting error but got none")
+	}
+
+	// Make sure we got the expected error
+	if !errors.Is(err, errDelete) {
+		t.Errorf("expecting error %s but got %s", errDelete, err)
+	}
+
+	// Make sure expectations were met for both success and failure
+	// conditions
+	err = mock.ExpectationsWereMet()
+	if err != nil {
+		t.Errorf("unfulfilled expectations: %s", err)
+	}
+}
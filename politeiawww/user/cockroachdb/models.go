@@ -67,6 +67,19 @@ func (Session) TableName() string {
 	return tableSessions
 }
 
+// Event represents a politeiad event that has been claimed by a politeiawww
+// instance. Every politeiawww instance consumes the politeiad event stream.
+// Only the instance that claims an event emits the politeiawww events for it.
+type Event struct {
+	ID        string `gorm:"primary_key"` // politeiad event ID
+	ClaimedAt int64  `gorm:"not null"`    // UNIX timestamp of the claim
+}
+
+// TableName returns the table name of the Event table.
+func (Event) TableName() string {
+	return tableEvents
+}
+
 // CMSUser represents a CMS user. A CMS user includes the politeiawww User
 // object as well as CMS specific user fields. A CMS user must correspond to
 // a politeiawww User.
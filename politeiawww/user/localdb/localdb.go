@@ -503,6 +503,23 @@ func (l *localdb) SessionDeleteByID(sid string) error {
 	return nil
 }
 
+// EventClaim claims a politeiad event. The leveldb database can only be opened
+// by a single politeiawww instance so every event is claimed by the caller.
+//
+// EventClaim satisfies the Database interface.
+func (l *localdb) EventClaim(eventID string) (bool, error) {
+	log.Tracef("EventClaim: %v", eventID)
+
+	l.RLock()
+	defer l.RUnlock()
+
+	if l.shutdown {
+		return false, user.ErrShutdown
+	}
+
+	return true, nil
+}
+
 // SessionsDeleteByUserID deletes all sessions for the given user ID, except
 // the session IDs in exemptSessionIDs.
 //
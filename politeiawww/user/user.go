@@ -491,6 +491,10 @@ type Database interface {
 	// Delete all sessions for a user except for the given session IDs
 	SessionsDeleteByUserID(id uuid.UUID, exemptSessionIDs []string) error
 
+	// Claim a politeiad event. Returns false if the event has already
+	// been claimed by a different politeiawww instance.
+	EventClaim(eventID string) (bool, error)
+
 	// Register a plugin
 	RegisterPlugin(Plugin) error
 
@@ -317,6 +317,85 @@ func (p *politeiawww) getPluginInventory() ([]pdv2.Plugin, error) {
 	return plugins, nil
 }
 
+// updatePoliteiadIdentity fetches the politeiad identity key history and
+// updates the politeiad identity if it has been rotated. The identity that
+// was loaded from disk is the trust anchor. The politeiad identity is only
+// updated if the loaded identity is part of the verified key history. If a
+// politeiad connection cannot be made, the call will be retried every 5
+// seconds for up to 1000 tries.
+func (p *politeiawww) updatePoliteiadIdentity() error {
+	var (
+		done          bool
+		maxRetries    = 1000
+		sleepInterval = 5 * time.Second
+		history       []pd.IdentityKey
+		ctx           = context.Background()
+	)
+	for retries := 0; !done; retries++ {
+		if retries == maxRetries {
+			return fmt.Errorf("max retries exceeded")
+		}
+
+		h, err := p.politeiad.IdentityHistory(ctx)
+		if err != nil {
+			log.Infof("cannot get politeiad identity history: %v: retry in %v",
+				err, sleepInterval)
+			time.Sleep(sleepInterval)
+			continue
+		}
+		history = h
+
+		done = true
+	}
+	if len(history) == 0 {
+		// The politeiad identity has never been rotated
+		return nil
+	}
+
+	// Verify that the loaded identity is part of the key history
+	var (
+		loaded  = p.cfg.Identity.String()
+		current = history[len(history)-1].PublicKey
+		found   bool
+	)
+	for _, v := range history {
+		if v.PublicKey == loaded {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return fmt.Errorf("politeiad identity %v is not part of the politeiad "+
+			"identity history", loaded)
+	}
+
+	// Update the identity. The identity is shared with the politeiad
+	// client so it must be updated in place.
+	if current != loaded {
+		id, err := util.IdentityFromString(current)
+		if err != nil {
+			return err
+		}
+		*p.cfg.Identity = *id
+
+		log.Infof("Politeiad identity has been rotated")
+		log.Infof("Loaded identity : %v", loaded)
+		log.Infof("Current identity: %v", current)
+	}
+
+	// Save the key history so that it can be returned to clients
+	p.pdIdentityHistory = make([]www.ServerPubKey, 0, len(history))
+	for _, v := range history {
+		p.pdIdentityHistory = append(p.pdIdentityHistory, www.ServerPubKey{
+			PubKey:     v.PublicKey,
+			Activation: v.Activation,
+			Signature:  v.Signature,
+		})
+	}
+
+	return nil
+}
+
 func (p *politeiawww) setupCMS() error {
 	// Setup routes
 	p.setCMSWWWRoutes()
@@ -586,6 +665,13 @@ func _main() error {
 	if err != nil {
 		return err
 	}
+	if loadedCfg.RPCClientCert != "" {
+		err = pdc.SetClientCert(loadedCfg.RPCClientCert,
+			loadedCfg.RPCClientKey)
+		if err != nil {
+			return fmt.Errorf("politeiad client cert: %v", err)
+		}
+	}
 
 	// Setup user database
 	log.Infof("User database: %v", loadedCfg.UserDB)
@@ -660,6 +746,15 @@ func _main() error {
 	if err != nil {
 		return err
 	}
+	if loadedCfg.RPCClientCert != "" {
+		cert, err := tls.LoadX509KeyPair(loadedCfg.RPCClientCert,
+			loadedCfg.RPCClientKey)
+		if err != nil {
+			return fmt.Errorf("politeiad client cert: %v", err)
+		}
+		t := httpClient.Transport.(*http.Transport)
+		t.TLSClientConfig.Certificates = []tls.Certificate{cert}
+	}
 
 	// Setup application context
 	p := &politeiawww{
@@ -683,6 +778,12 @@ func _main() error {
 		return err
 	}
 
+	// Update the politeiad identity if it has been rotated
+	err = p.updatePoliteiadIdentity()
+	if err != nil {
+		return fmt.Errorf("updatePoliteiadIdentity: %v", err)
+	}
+
 	// Perform application specific setup
 	switch p.cfg.Mode {
 	case config.PoliteiaWWWMode: